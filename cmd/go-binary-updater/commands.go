@@ -0,0 +1,436 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/release"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/schema"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// runCheck reports the tools with a newer release
+func runCheck(config *Config, args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	asJSON := flags.Bool("json", false, "Print the results as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	names, err := config.selectTools(flags.Args())
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		defer progressToStderr(stderr)()
+	}
+	var report release.StatusReport
+	failures := toolFailures{}
+	for _, name := range names {
+		check, err := checkTool(config.Tools[name])
+		if *asJSON {
+			status := release.NewToolStatus(name, config.Tools[name].Files)
+			status.Check, status.Error = check, release.NewStatusError(err)
+			report.Tools = append(report.Tools, status)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", name, err)
+			failures[name] = err
+			continue
+		}
+		if *asJSON {
+			continue
+		}
+		if check.UpdateAvailable {
+			fmt.Fprintln(stdout, check.Banner(name, "go-binary-updater install "+name))
+		} else {
+			fmt.Fprintf(stdout, "%s %s is up to date\n", name, check.CurrentVersion)
+		}
+	}
+	if *asJSON {
+		if err := release.WriteJSON(stdout, report); err != nil {
+			return err
+		}
+	}
+	return failures.err(len(names))
+}
+
+// checkTool resolves the latest release of the tool and compares it with the installed version
+func checkTool(tool *ToolConfig) (*release.UpdateCheck, error) {
+	p, err := tool.newProvider(false)
+	if err != nil {
+		return nil, err
+	}
+	return p.CheckForUpdate()
+}
+
+// runInstall installs the pinned or latest version of the tools
+func runInstall(config *Config, args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("install", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	force := flags.Bool("force", false, "Install again even if the version is already installed")
+	timeout := flags.Duration("timeout", 0, "Give up on a tool whose installation takes longer, e.g. 10m (default: no limit)")
+	asJSON := flags.Bool("json", false, "Print the installations as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	names, err := config.selectTools(flags.Args())
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		defer progressToStderr(stderr)()
+	}
+	var report release.StatusReport
+	failures := toolFailures{}
+	for _, name := range names {
+		result, err := installTool(config.Tools[name], *force, *timeout)
+		if *asJSON {
+			status := release.NewToolStatus(name, config.Tools[name].Files)
+			status.Result = result
+			status.Error = release.NewStatusError(err)
+			report.Tools = append(report.Tools, status)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "%s: %v\n", name, err)
+			failures[name] = err
+			continue
+		}
+		if !*asJSON {
+			fmt.Fprintf(stdout, "%s %s is installed\n", name, result.Version)
+		}
+	}
+	if *asJSON {
+		if err := release.WriteJSON(stdout, report); err != nil {
+			return err
+		}
+	}
+	return failures.err(len(names))
+}
+
+// installTool installs the tool's pinned version, or the latest release if none is pinned, and
+// describes the installation. Metadata requests, the download and the installation together must
+// finish within timeout, if it is positive.
+func installTool(tool *ToolConfig, force bool, timeout time.Duration) (*release.InstallResult, error) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	p, err := tool.newProvider(force)
+	if err != nil {
+		return nil, err
+	}
+	if tool.Version != "" {
+		return p.InstallReleaseWithResultContext(ctx, tool.Version)
+	}
+	if _, err := p.DownloadLatestReleaseWithResultContext(ctx); err != nil {
+		return nil, err
+	}
+	return p.InstallLatestReleaseWithResultContext(ctx)
+}
+
+// runList prints the installed versions of the tools, newest first, marking the active one and
+// showing the URL each version was downloaded from
+func runList(config *Config, args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("list", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	asJSON := flags.Bool("json", false, "Print the active installations and installed versions as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	names, err := config.selectTools(flags.Args())
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		return listJSON(config, names, stdout)
+	}
+	w := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TOOL\tVERSION\tSTATUS\tSOURCE")
+	for _, name := range names {
+		files := config.Tools[name].Files
+		versions, err := installedVersions(files)
+		if err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		if len(versions) == 0 {
+			fmt.Fprintf(w, "%s\t-\tnot installed\n", name)
+			continue
+		}
+		current, _ := fileUtils.CurrentInstalledVersion(files)
+		receipt, _ := fileUtils.GetInstallReceipt(files)
+		for _, version := range versions {
+			status := ""
+			if version == current {
+				status = "active"
+			}
+			source := ""
+			if receipt != nil {
+				source = receipt.Provenance[version].DownloadURL
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", name, version, status, source)
+		}
+	}
+	return w.Flush()
+}
+
+// listJSON prints the status of the tools with their installed versions, newest first
+func listJSON(config *Config, names []string, stdout io.Writer) error {
+	var report release.StatusReport
+	for _, name := range names {
+		files := config.Tools[name].Files
+		versions, err := installedVersions(files)
+		if err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		status := release.NewToolStatus(name, files)
+		status.Versions = versions
+		report.Tools = append(report.Tools, status)
+	}
+	return release.WriteJSON(stdout, report)
+}
+
+// runRollback activates the given installed version, or the newest one older than the active one
+func runRollback(config *Config, args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 || len(args) > 2 {
+		return fmt.Errorf("usage: go-binary-updater rollback <tool> [version]")
+	}
+	names, err := config.selectTools(args[:1])
+	if err != nil {
+		return err
+	}
+	files := config.Tools[names[0]].Files
+	version := ""
+	if len(args) == 2 {
+		version = args[1]
+	} else {
+		current, err := fileUtils.CurrentInstalledVersion(files)
+		if err != nil {
+			return fmt.Errorf("%s has no active version to roll back from: %v", names[0], err)
+		}
+		versions, err := installedVersions(files)
+		if err != nil {
+			return err
+		}
+		for _, installed := range versions {
+			if compareVersions(installed, current) < 0 {
+				version = installed
+				break
+			}
+		}
+		if version == "" {
+			return fmt.Errorf("no version of %s older than %s is installed", names[0], current)
+		}
+	}
+	if err := fileUtils.ActivateVersion(files, version); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "%s rolled back to %s\n", names[0], version)
+	return nil
+}
+
+// runPrune removes the inactive versions of the tools beyond the newest ones to keep
+func runPrune(config *Config, args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("prune", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	keep := flags.Int("keep", 0, "Inactive versions to keep (default: the tool's keep setting)")
+	dryRun := flags.Bool("dry-run", false, "Print the versions that would be removed without removing them")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *keep < 0 {
+		return fmt.Errorf("-keep must not be negative")
+	}
+	names, err := config.selectTools(flags.Args())
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		tool := config.Tools[name]
+		versions, err := installedVersions(tool.Files)
+		if err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		current, _ := fileUtils.CurrentInstalledVersion(tool.Files)
+		limit := tool.Keep
+		if flagSet(flags, "keep") {
+			limit = *keep
+		}
+		kept := 0
+		for _, version := range versions {
+			if version == current {
+				continue
+			}
+			if kept < limit {
+				kept++
+				continue
+			}
+			if *dryRun {
+				fmt.Fprintf(stdout, "Would remove %s %s\n", name, version)
+				continue
+			}
+			if err := fileUtils.RemoveVersion(tool.Files, version); err != nil {
+				return fmt.Errorf("%s: %v", name, err)
+			}
+			fmt.Fprintf(stdout, "Removed %s %s\n", name, version)
+		}
+	}
+	return nil
+}
+
+// runExplainMatch prints how the asset of the latest or given release of a tool is selected
+func runExplainMatch(config *Config, args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 || len(args) > 2 {
+		return fmt.Errorf("usage: go-binary-updater explain-match <tool> [version]")
+	}
+	names, err := config.selectTools(args[:1])
+	if err != nil {
+		return err
+	}
+	tool := config.Tools[names[0]]
+	assetConfig, err := tool.assetMatchingConfig()
+	if err != nil {
+		return err
+	}
+	p, err := tool.newProvider(false)
+	if err != nil {
+		return err
+	}
+	if len(args) == 2 {
+		err = p.GetRelease(args[1])
+	} else {
+		err = p.GetLatestRelease()
+	}
+	// A release without a matching asset is still explained, which is what this command is for
+	var assetNames []string
+	if lister, ok := p.(interface{ Assets() []release.AssetInfo }); ok {
+		for _, asset := range lister.Assets() {
+			assetNames = append(assetNames, asset.Name)
+		}
+	}
+	if len(assetNames) == 0 && err != nil {
+		return err
+	}
+	fmt.Fprint(stdout, release.ExplainMatch(assetConfig, assetNames))
+	return nil
+}
+
+// runValidate reports that the configuration, which run validated while loading it, is valid
+func runValidate(config *Config, args []string, stdout, stderr io.Writer) error {
+	if len(args) > 0 {
+		return fmt.Errorf("usage: go-binary-updater validate")
+	}
+	fmt.Fprintf(stdout, "Configuration is valid (%d tools)\n", len(config.Tools))
+	return nil
+}
+
+// runSchema prints the JSON Schema of the configuration file
+func runSchema(config *Config, args []string, stdout, stderr io.Writer) error {
+	if len(args) > 0 {
+		return fmt.Errorf("usage: go-binary-updater schema")
+	}
+	data, err := schema.Generate("go-binary-updater configuration", Config{}).MarshalIndent()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "%s\n", data)
+	return nil
+}
+
+// progressToStderr sends the progress the library prints to os.Stdout to stderr instead, so stdout
+// holds nothing but the JSON report. The returned function restores os.Stdout.
+func progressToStderr(stderr io.Writer) func() {
+	original := os.Stdout
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return func() {}
+	}
+	os.Stdout = writer
+	copied := make(chan struct{})
+	go func() {
+		io.Copy(stderr, reader)
+		reader.Close()
+		close(copied)
+	}()
+	return func() {
+		os.Stdout = original
+		writer.Close()
+		<-copied
+	}
+}
+
+// installedVersions returns the installed versions of a tool, newest first
+func installedVersions(files fileUtils.FileConfig) ([]string, error) {
+	versions, err := fileUtils.ListInstalledVersions(files)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return compareVersions(versions[i], versions[j]) > 0
+	})
+	return versions, nil
+}
+
+// compareVersions compares two versions semantically, falling back to comparing the strings
+// when either is not a semantic version
+func compareVersions(a, b string) int {
+	versionA, errA := release.ParseSemVersion(a)
+	versionB, errB := release.ParseSemVersion(b)
+	if errA == nil && errB == nil {
+		return versionA.Compare(versionB)
+	}
+	return strings.Compare(a, b)
+}
+
+// flagSet reports whether the named flag was given on the command line
+func flagSet(flags *flag.FlagSet, name string) bool {
+	set := false
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// toolFailures holds the errors of the tools that failed by name
+type toolFailures map[string]error
+
+// err returns a toolsError if any of the tools failed
+func (f toolFailures) err(total int) error {
+	if len(f) == 0 {
+		return nil
+	}
+	return &toolsError{failures: f, total: total}
+}
+
+// toolsError reports that some of the tools failed
+type toolsError struct {
+	failures toolFailures
+	total    int
+}
+
+func (e *toolsError) Error() string {
+	return fmt.Sprintf("%d of %d tools failed", len(e.failures), e.total)
+}
+
+// ErrorCode returns the code shared by all failures, or ErrorCodeUnknown if they differ
+func (e *toolsError) ErrorCode() fileUtils.ErrorCode {
+	var code fileUtils.ErrorCode
+	for _, err := range e.failures {
+		if toolCode := fileUtils.ErrorCodeOf(err); code == "" {
+			code = toolCode
+		} else if toolCode != code {
+			return fileUtils.ErrorCodeUnknown
+		}
+	}
+	return code
+}
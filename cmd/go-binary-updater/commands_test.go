@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/release"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/testutil"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// installVersions writes a config managing "tool" in a temporary directory and installs the
+// versions from local files, leaving the last one active
+func installVersions(t *testing.T, versions ...string) (string, *Config) {
+	t.Helper()
+	tempDir := t.TempDir()
+	path := writeConfig(t, fmt.Sprintf(`{"tools": {"tool": {"repository": "owner/tool", "files": {"base_binary_directory": %q}}}}`,
+		filepath.Join(tempDir, "bin")))
+	config, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+	for _, version := range versions {
+		source := filepath.Join(tempDir, "tool-"+version)
+		os.WriteFile(source, []byte("version "+version), 0755)
+		if err := fileUtils.InstallFromFile(config.Tools["tool"].Files, source, version, nil); err != nil {
+			t.Fatalf("InstallFromFile(%s) error = %v", version, err)
+		}
+	}
+	return path, config
+}
+
+// runCommand runs the CLI and fails the test if the exit code is not the expected one
+func runCommand(t *testing.T, expected int, args ...string) string {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	if code := run(args, &stdout, &stderr); code != expected {
+		t.Fatalf("run(%v) = %d, want %d, stderr: %s", args, code, expected, stderr.String())
+	}
+	return stdout.String()
+}
+
+func TestRun_ListRollbackPrune(t *testing.T) {
+	path, config := installVersions(t, "1.9.0", "1.10.0", "2.0.0")
+	files := config.Tools["tool"].Files
+
+	output := runCommand(t, 0, "-config", path, "list")
+	lines := strings.Split(output, "\n")
+	if len(lines) < 4 || strings.Join(strings.Fields(lines[1]), " ") != "tool 2.0.0 active" || !strings.Contains(lines[2], "1.10.0") {
+		t.Errorf("Expected versions newest first with 2.0.0 active, got:\n%s", output)
+	}
+
+	runCommand(t, 0, "-config", path, "rollback", "tool")
+	if current, _ := fileUtils.CurrentInstalledVersion(files); current != "1.10.0" {
+		t.Errorf("Expected rollback to activate 1.10.0, got %q", current)
+	}
+	runCommand(t, 0, "-config", path, "rollback", "tool", "2.0.0")
+	runCommand(t, 5, "-config", path, "rollback", "tool", "3.0.0")
+
+	output = runCommand(t, 0, "-config", path, "prune", "-dry-run")
+	if output != "Would remove tool 1.9.0\n" {
+		t.Errorf("Unexpected dry run output: %q", output)
+	}
+	if versions, _ := fileUtils.ListInstalledVersions(files); len(versions) != 3 {
+		t.Errorf("Expected a dry run to keep every version, got %v", versions)
+	}
+	runCommand(t, 0, "-config", path, "prune", "-keep", "0")
+	if versions, _ := fileUtils.ListInstalledVersions(files); len(versions) != 1 || versions[0] != "2.0.0" {
+		t.Errorf("Expected only the active version to remain, got %v", versions)
+	}
+	runCommand(t, 1, "-config", path, "rollback", "tool")
+}
+
+func TestRun_Usage(t *testing.T) {
+	path, _ := installVersions(t)
+	runCommand(t, 2, "-config", path)
+	runCommand(t, 2, "-config", path, "upgrade")
+	runCommand(t, 0, "-config", path, "-h")
+	runCommand(t, 0, "-config", path, "install", "-h")
+	runCommand(t, 1, "-config", path, "list", "missing")
+	runCommand(t, 1, "-config", filepath.Join(t.TempDir(), "missing.json"), "list")
+	if output := runCommand(t, 0, "-config", path, "list"); !strings.Contains(output, "not installed") {
+		t.Errorf("Expected the tool to be listed as not installed, got:\n%s", output)
+	}
+	if output := runCommand(t, 0, "-config", path, "validate"); output != "Configuration is valid (1 tools)\n" {
+		t.Errorf("Unexpected validate output: %q", output)
+	}
+}
+
+func TestRun_ListJSON(t *testing.T) {
+	path, config := installVersions(t, "1.9.0", "1.10.0")
+	output := runCommand(t, 0, "-config", path, "list", "-json")
+	var report release.StatusReport
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		t.Fatalf("Expected JSON, got %q: %v", output, err)
+	}
+	if len(report.Tools) != 1 {
+		t.Fatalf("Expected one tool, got %+v", report.Tools)
+	}
+	tool := report.Tools[0]
+	if tool.Name != "tool" || !tool.Installed || strings.Join(tool.Versions, ",") != "1.10.0,1.9.0" {
+		t.Errorf("Unexpected status: %+v", tool)
+	}
+	if info := tool.Installation; info == nil || info.Version != "1.10.0" || info.SymlinkStatus != "created" ||
+		info.LocalSymlinkPath != fileUtils.GetLocalSymlinkPath(config.Tools["tool"].Files) {
+		t.Errorf("Unexpected installation: %+v", tool.Installation)
+	}
+}
+
+func TestRun_InstallJSON(t *testing.T) {
+	server := testutil.NewServer()
+	defer server.Close()
+	asset := fmt.Sprintf("tool_%s_%s", runtime.GOOS, runtime.GOARCH)
+	server.AddRelease("owner/tool", testutil.Release{Tag: "v1.0.0", Assets: []testutil.Asset{{Name: asset, Content: []byte("tool v1.0.0")}}})
+	t.Setenv("GITHUB_API_URL", server.GitHubAPIURL())
+
+	tempDir := t.TempDir()
+	path := writeConfig(t, fmt.Sprintf(`{"tools": {"tool": {"repository": "owner/tool", "files": {
+		"base_binary_directory": %q, "staging_directory": %q, "binary_name": "tool", "is_direct_binary": true}}}}`,
+		filepath.Join(tempDir, "bin"), filepath.Join(tempDir, "staging")))
+	// The library prints its progress to os.Stdout, which must not end up in the report
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe() error = %v", err)
+	}
+	read := make(chan []byte)
+	go func() {
+		output, _ := io.ReadAll(reader)
+		read <- output
+	}()
+	originalStdout := os.Stdout
+	os.Stdout = writer
+	var stderr bytes.Buffer
+	code := run([]string{"-config", path, "install", "-json"}, writer, &stderr)
+	os.Stdout = originalStdout
+	writer.Close()
+	output := <-read
+	if code != 0 {
+		t.Fatalf("install -json = %d, want 0, stderr: %s", code, stderr.String())
+	}
+	var report release.StatusReport
+	if err := json.Unmarshal(output, &report); err != nil {
+		t.Fatalf("Expected only JSON on stdout, got %q: %v", output, err)
+	}
+	if len(report.Tools) != 1 || report.Tools[0].Error != nil {
+		t.Fatalf("Expected one installed tool, got %+v", report.Tools)
+	}
+	result := report.Tools[0].Result
+	if result == nil || result.Version != "v1.0.0" || result.Installation == nil || result.Download == nil || result.Download.AssetName != asset {
+		t.Errorf("Expected the install result of v1.0.0, got %+v", result)
+	}
+}
+
+func TestRun_ErrorCodes(t *testing.T) {
+	path, _ := installVersions(t, "1.0.0")
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"-config", path, "-json-errors", "rollback", "tool", "3.0.0"}, &stdout, &stderr); code != 5 {
+		t.Fatalf("Expected exit code 5 for a missing version, got %d", code)
+	}
+	var report errorReport
+	if err := json.Unmarshal(stderr.Bytes(), &report); err != nil {
+		t.Fatalf("Expected a JSON error report, got %q: %v", stderr.String(), err)
+	}
+	if report.Code != fileUtils.ErrorCodeNoMatch || report.ExitCode != 5 || !strings.Contains(report.Error, "3.0.0") {
+		t.Errorf("Unexpected error report: %+v", report)
+	}
+
+	invalid := writeConfig(t, `{"tools": {"jq": {"repository": "jqlang/jq", "files": {"is_direct_binary": true, "is_appimage": true}}}}`)
+	runCommand(t, 2, "-config", invalid, "list")
+
+	failures := toolFailures{
+		"a": fmt.Errorf("download: %w", &fileUtils.ChecksumError{Name: "a"}),
+		"b": &fileUtils.ChecksumError{Name: "b"},
+	}
+	if code := fileUtils.ErrorCodeOf(failures.err(3)); code != fileUtils.ErrorCodeChecksum {
+		t.Errorf("Expected the tools' common code, got %s", code)
+	}
+	failures["c"] = &fileUtils.InsufficientSpaceError{Path: "/tmp"}
+	if code := fileUtils.ErrorCodeOf(failures.err(3)); code != fileUtils.ErrorCodeUnknown {
+		t.Errorf("Expected differing codes to be unknown, got %s", code)
+	}
+}
+
+func TestRun_Schema(t *testing.T) {
+	// The schema needs no configuration file
+	output := runCommand(t, 0, "-config", filepath.Join(t.TempDir(), "missing.json"), "schema")
+	var document struct {
+		Properties struct {
+			Tools struct {
+				AdditionalProperties struct {
+					Properties map[string]any `json:"properties"`
+				} `json:"additionalProperties"`
+			} `json:"tools"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal([]byte(output), &document); err != nil {
+		t.Fatalf("Expected a JSON document, got %v:\n%s", err, output)
+	}
+	tool := document.Properties.Tools.AdditionalProperties.Properties
+	for _, name := range []string{"provider", "repository", "asset_matching", "files"} {
+		if tool[name] == nil {
+			t.Errorf("Expected the tool schema to define %s, got %v", name, tool)
+		}
+	}
+}
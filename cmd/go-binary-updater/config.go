@@ -0,0 +1,201 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/release"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// defaultKeep is the number of inactive versions prune keeps when neither the flag nor the tool sets it
+const defaultKeep = 1
+
+// Config is the declarative configuration file listing the tools the CLI manages
+type Config struct {
+	Tools map[string]*ToolConfig `json:"tools"` // Tool settings keyed by tool name
+}
+
+// ToolConfig describes where a tool is released and how it is installed
+type ToolConfig struct {
+	Provider      string                       `json:"provider"`                 // "github" (default) or "gitlab"
+	Repository    string                       `json:"repository"`               // GitHub "owner/repo", or GitLab project ID or path
+	Version       string                       `json:"version,omitempty"`        // Version to install instead of the latest release
+	Preset        string                       `json:"preset,omitempty"`         // Asset matching preset, see release.DefaultPresetRegistry
+	Selection     release.ReleaseSelection     `json:"selection"`                // Which release is the latest, e.g. tag_prefix "toolA/" for monorepos
+	AssetMatching *release.AssetMatchingConfig `json:"asset_matching,omitempty"` // Asset matching settings, used instead of Preset
+	Credentials   *release.CredentialConfig    `json:"credentials,omitempty"`    // Token source for private repositories
+	Keep          int                          `json:"keep,omitempty"`           // Inactive versions kept by prune (default: defaultKeep)
+	Files         fileUtils.FileConfig         `json:"files"`                    // Installation settings, starting from fileUtils.DefaultFileConfig
+}
+
+// UnmarshalJSON decodes a tool on top of fileUtils.DefaultFileConfig, so omitted installation
+// settings keep the library's defaults
+func (t *ToolConfig) UnmarshalJSON(data []byte) error {
+	type plain ToolConfig
+	decoded := plain{Files: fileUtils.DefaultFileConfig()}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*t = ToolConfig(decoded)
+	return nil
+}
+
+// loadConfig reads the configuration file and fills in the defaults of every tool
+func loadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %v", err)
+	}
+	config := &Config{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %v", path, err)
+	}
+	if len(config.Tools) == 0 {
+		return nil, fmt.Errorf("config %s defines no tools", path)
+	}
+	for name, tool := range config.Tools {
+		if tool == nil {
+			return nil, fmt.Errorf("tool %s has no settings", name)
+		}
+		if err := tool.applyDefaults(name); err != nil {
+			return nil, fmt.Errorf("tool %s: %v", name, err)
+		}
+		if err := tool.validate(); err != nil {
+			return nil, fmt.Errorf("tool %s: %w", name, err)
+		}
+	}
+	return config, nil
+}
+
+// applyDefaults validates the tool and fills in the settings derived from its name
+func (t *ToolConfig) applyDefaults(name string) error {
+	switch t.Provider {
+	case "":
+		t.Provider = "github"
+	case "github", "gitlab":
+	default:
+		return fmt.Errorf("unsupported provider %q (use github or gitlab)", t.Provider)
+	}
+	if t.Repository == "" {
+		return fmt.Errorf("repository is required")
+	}
+	if t.Files.BinaryName == "" {
+		t.Files.BinaryName = name
+	}
+	if t.Files.SourceBinaryName == "" && t.Files.SourceBinaryPattern == "" {
+		t.Files.SourceBinaryName = t.Files.BinaryName
+	}
+	if t.Files.ProjectName == "" {
+		t.Files.ProjectName = name
+	}
+	if t.Files.BaseBinaryDirectory == "" {
+		dir, err := fileUtils.DefaultBaseBinaryDirectory("")
+		if err != nil {
+			return err
+		}
+		t.Files.BaseBinaryDirectory = dir
+	}
+	// Tools share the binary directory, so each keeps its versions below versions/{ProjectName}
+	if t.Files.VersionedDirectoryName == "" {
+		t.Files.UseVersionsSubdirectory = true
+	}
+	if t.Keep <= 0 {
+		t.Keep = defaultKeep
+	}
+	return nil
+}
+
+// validate checks the tool's settings without any network call
+func (t *ToolConfig) validate() error {
+	if err := t.Files.Validate(); err != nil {
+		return fmt.Errorf("files: %w", err)
+	}
+	assetConfig, err := t.assetMatchingConfig()
+	if err != nil {
+		return err
+	}
+	if err := assetConfig.Validate(); err != nil {
+		return fmt.Errorf("asset_matching: %w", err)
+	}
+	if err := t.Selection.Validate(); err != nil {
+		return fmt.Errorf("selection: %w", err)
+	}
+	if t.Credentials != nil {
+		if _, err := t.Credentials.Provider(); err != nil {
+			return fmt.Errorf("credentials: %v", err)
+		}
+	}
+	return nil
+}
+
+// assetMatchingConfig returns the tool's asset matching settings: AssetMatching, the Preset, or
+// the defaults
+func (t *ToolConfig) assetMatchingConfig() (release.AssetMatchingConfig, error) {
+	if t.AssetMatching != nil {
+		return *t.AssetMatching, nil
+	}
+	if t.Preset != "" {
+		return release.GetPresetConfig(t.Preset)
+	}
+	config := release.DefaultAssetMatchingConfig()
+	config.ProjectName = t.Files.ProjectName
+	return config, nil
+}
+
+// provider is a release source the CLI can check, install and explain
+type provider interface {
+	release.Release
+	release.VersionedRelease
+	release.UpdateChecker
+	release.VersionReporter
+	release.ContextRelease
+	release.ResultContextRelease
+}
+
+// newProvider creates the release provider of the tool. Install it again even if the version is
+// already installed when force is set.
+func (t *ToolConfig) newProvider(force bool) (provider, error) {
+	assetConfig, err := t.assetMatchingConfig()
+	if err != nil {
+		return nil, err
+	}
+	if t.Provider == "gitlab" {
+		gitlab := release.NewGitlabReleaseWithAssetConfig(t.Repository, t.Files, assetConfig)
+		gitlab.GitLabConfig.Credentials = t.Credentials
+		gitlab.Selection = t.Selection
+		gitlab.Force = force
+		if _, err := strconv.Atoi(t.Repository); err != nil {
+			projectId, err := release.ResolveGitLabProjectID(t.Repository, gitlab.GitLabConfig)
+			if err != nil {
+				return nil, err
+			}
+			gitlab.ProjectId = projectId
+		}
+		return gitlab, nil
+	}
+	github := release.NewGithubReleaseWithAssetConfig(t.Repository, t.Files, assetConfig)
+	github.Credentials = t.Credentials
+	github.Selection = t.Selection
+	github.Force = force
+	return github, nil
+}
+
+// selectTools returns the named tools in the given order, or every tool sorted by name
+func (c *Config) selectTools(names []string) ([]string, error) {
+	if len(names) == 0 {
+		for name := range c.Tools {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names, nil
+	}
+	for _, name := range names {
+		if _, ok := c.Tools[name]; !ok {
+			return nil, fmt.Errorf("unknown tool %q", name)
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeConfig writes a configuration file to a temporary directory and returns its path
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "go-binary-updater.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestLoadConfig_Defaults(t *testing.T) {
+	path := writeConfig(t, `{"tools": {
+		"jq": {"repository": "jqlang/jq", "files": {"base_binary_directory": "/opt/tools"}},
+		"glab": {"provider": "gitlab", "repository": "34675721", "keep": 3, "files": {"binary_name": "glab-cli"}}
+	}}`)
+	config, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+
+	jq := config.Tools["jq"]
+	if jq.Provider != "github" || jq.Keep != defaultKeep {
+		t.Errorf("Expected the github provider and the default keep, got %q, %d", jq.Provider, jq.Keep)
+	}
+	if jq.Files.BinaryName != "jq" || jq.Files.SourceBinaryName != "jq" || jq.Files.ProjectName != "jq" {
+		t.Errorf("Expected the names to default to the tool name, got %+v", jq.Files)
+	}
+	if jq.Files.BaseBinaryDirectory != "/opt/tools" || !jq.Files.UseVersionsSubdirectory {
+		t.Errorf("Expected versions below /opt/tools/versions/jq, got %+v", jq.Files)
+	}
+	if !jq.Files.CreateLocalSymlink {
+		t.Error("Expected omitted installation settings to keep the library defaults")
+	}
+
+	glab := config.Tools["glab"]
+	if glab.Keep != 3 || glab.Files.BinaryName != "glab-cli" || glab.Files.SourceBinaryName != "glab-cli" {
+		t.Errorf("Expected the configured settings to be kept, got keep %d, %+v", glab.Keep, glab.Files)
+	}
+
+	names, err := config.selectTools(nil)
+	if err != nil || strings.Join(names, ",") != "glab,jq" {
+		t.Errorf("selectTools() = %v, %v, want every tool sorted by name", names, err)
+	}
+	if _, err := config.selectTools([]string{"missing"}); err == nil {
+		t.Error("Expected an unknown tool to be refused")
+	}
+}
+
+func TestLoadConfig_Invalid(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{"Malformed", `{"tools": `},
+		{"NoTools", `{"tools": {}}`},
+		{"NoRepository", `{"tools": {"jq": {}}}`},
+		{"UnknownProvider", `{"tools": {"jq": {"provider": "bitbucket", "repository": "jqlang/jq"}}}`},
+		{"InvalidPattern", `{"tools": {"jq": {"repository": "jqlang/jq", "asset_matching": {"strategy": 2, "custom_patterns": ["jq-("]}}}}`},
+		{"ConflictingFlags", `{"tools": {"jq": {"repository": "jqlang/jq", "files": {"is_direct_binary": true, "is_appimage": true}}}}`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := loadConfig(writeConfig(t, tc.content)); err == nil {
+				t.Error("Expected loadConfig() to fail")
+			}
+		})
+	}
+}
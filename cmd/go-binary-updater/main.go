@@ -0,0 +1,148 @@
+// Command go-binary-updater installs and updates the tools listed in a declarative configuration
+// file using the go-binary-updater library.
+//
+// Usage:
+//
+//	go-binary-updater [-config file] <command> [arguments]
+//
+// The commands are check, install, list, rollback, prune, explain-match, validate and schema; run
+// a command with -h for its arguments. Every command except schema validates the configuration
+// before doing anything else. The configuration file defaults to $GO_BINARY_UPDATER_CONFIG or
+// go-binary-updater.json in the working directory.
+//
+// Failures exit with the status of their fileUtils.ErrorCode: 1 if unclassified, 2 for usage
+// errors and invalid configurations, 3 for network, 4 for authentication, 5 for missing releases
+// or assets, 6 for checksum, 7 for disk space and 8 for permission errors. With -json-errors the
+// last line written to stderr is a JSON object describing the failure.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"io"
+	"os"
+)
+
+// configEnv names the environment variable holding the default configuration file
+const configEnv = "GO_BINARY_UPDATER_CONFIG"
+
+// command is a subcommand of the CLI
+type command struct {
+	name    string
+	usage   string // Arguments shown in the usage
+	summary string
+	run     func(config *Config, args []string, stdout, stderr io.Writer) error
+
+	standalone bool // Runs without loading the configuration file (config is nil)
+}
+
+var commands = []command{
+	{"check", "[-json] [tool...]", "Report tools with a newer release without installing anything", runCheck, false},
+	{"install", "[-force] [-timeout d] [-json] [tool...]", "Install the configured or latest version of tools", runInstall, false},
+	{"list", "[-json] [tool...]", "List the installed versions of tools", runList, false},
+	{"rollback", "<tool> [version]", "Activate the previous or given installed version", runRollback, false},
+	{"prune", "[-keep n] [-dry-run] [tool...]", "Remove old inactive versions", runPrune, false},
+	{"explain-match", "<tool> [version]", "Explain which release asset is selected and why", runExplainMatch, false},
+	{"validate", "", "Check the configuration file without any network call", runValidate, false},
+	{"schema", "", "Print the JSON Schema of the configuration file", runSchema, true},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command line and returns the process exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("go-binary-updater", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	defaultConfig := os.Getenv(configEnv)
+	if defaultConfig == "" {
+		defaultConfig = "go-binary-updater.json"
+	}
+	configPath := flags.String("config", defaultConfig, "Configuration file listing the managed tools")
+	jsonErrors := flags.Bool("json-errors", false, "Report a failure as a JSON object with its error code on the last line of stderr")
+	flags.Usage = func() { printUsage(flags, stderr) }
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return 2
+	}
+	if flags.NArg() == 0 {
+		printUsage(flags, stderr)
+		return 2
+	}
+
+	name := flags.Arg(0)
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+		var config *Config
+		if !cmd.standalone {
+			var err error
+			if config, err = loadConfig(*configPath); err != nil {
+				return reportError(stderr, err, *jsonErrors)
+			}
+		}
+		if err := cmd.run(config, flags.Args()[1:], stdout, stderr); err != nil {
+			if err == flag.ErrHelp {
+				return 0
+			}
+			return reportError(stderr, err, *jsonErrors)
+		}
+		return 0
+	}
+	fmt.Fprintf(stderr, "Error: unknown command %q\n", name)
+	printUsage(flags, stderr)
+	return 2
+}
+
+// printUsage describes the global flags and the commands
+func printUsage(flags *flag.FlagSet, w io.Writer) {
+	fmt.Fprintln(w, "Usage: go-binary-updater [-config file] <command> [arguments]")
+	fmt.Fprintln(w, "\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-14s %s\n", cmd.name, cmd.summary)
+		fmt.Fprintf(w, "  %-14s   go-binary-updater %s %s\n", "", cmd.name, cmd.usage)
+	}
+	fmt.Fprintln(w, "\nFlags:")
+	flags.PrintDefaults()
+}
+
+// errorReport is the JSON object printed for a failure with -json-errors
+type errorReport struct {
+	Error    string                 `json:"error"`
+	Code     fileUtils.ErrorCode    `json:"code"`
+	ExitCode int                    `json:"exit_code"`
+	Tools    map[string]errorReport `json:"tools,omitempty"` // Failures of the individual tools
+}
+
+// newErrorReport describes err, including the failures of the individual tools
+func newErrorReport(err error) errorReport {
+	code := fileUtils.ErrorCodeOf(err)
+	report := errorReport{Error: err.Error(), Code: code, ExitCode: code.ExitCode()}
+	var tools *toolsError
+	if errors.As(err, &tools) {
+		report.Tools = make(map[string]errorReport, len(tools.failures))
+		for name, toolErr := range tools.failures {
+			report.Tools[name] = newErrorReport(toolErr)
+		}
+	}
+	return report
+}
+
+// reportError prints err as text or as a JSON object and returns the exit code of its error code
+func reportError(w io.Writer, err error, asJSON bool) int {
+	report := newErrorReport(err)
+	if asJSON {
+		encoded, _ := json.Marshal(report) // Strings, numbers and maps of them always encode
+		fmt.Fprintf(w, "%s\n", encoded)
+	} else {
+		fmt.Fprintf(w, "Error: %v\n", err)
+	}
+	return report.ExitCode
+}
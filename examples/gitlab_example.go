@@ -132,6 +132,8 @@ func exampleFindProjectID() {
 	fmt.Println("4. You can also use the GitLab API:")
 	fmt.Println("   curl 'https://gitlab.com/api/v4/projects/owner%2Frepo'")
 	fmt.Println("   (replace 'owner/repo' with your project path)")
+	fmt.Println("5. Or let the library resolve it:")
+	fmt.Println("   release.NewGitlabReleaseFromPath(\"group/subgroup/project\", config)")
 }
 
 // Example function demonstrating error handling patterns
@@ -2,4 +2,12 @@ module gitlab.com/locke-codes/go-binary-updater
 
 go 1.23
 
-require golang.org/x/text v0.21.0
+require (
+	github.com/klauspost/compress v1.18.0
+	github.com/ulikunitz/xz v0.5.15
+	golang.org/x/crypto v0.31.0
+	golang.org/x/text v0.21.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require golang.org/x/sys v0.28.0 // indirect
@@ -4,36 +4,72 @@ import (
 	"archive/tar"
 	"archive/zip"
 	"compress/gzip"
+	"encoding/binary"
 	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/filesystem"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+	"sync"
 )
 
+// fsys returns the file system archives are read from and extracted to, see filesystem.SetDefault
+func fsys() filesystem.FS {
+	return filesystem.Default()
+}
+
 // Archiver interface defines a method for extracting archives.
 type Archiver interface {
 	Extract(source, target string) error
 }
 
+// StreamArchiver is implemented by archivers that can extract directly from a stream,
+// such as an HTTP response body, without the archive being written to disk first.
+type StreamArchiver interface {
+	ExtractReader(r io.Reader, target string) error
+}
+
+// MetadataOptions controls which file metadata stored in an archive is restored on extraction and
+// whether extracted files are synced to disk. Without options files are created with default
+// permissions, owned by the current user and left to the operating system to flush.
+type MetadataOptions struct {
+	PreservePermissions bool // Restore permission bits from tar modes or zip external attributes
+	PreserveOwnership   bool // Restore uid/gid from the archive; only applied when running as root
+	SyncFiles           bool // Flush every extracted file to disk (fsync) before it is closed
+}
+
 // TarGzArchiver handles extraction of .tar.gz archives.
-type TarGzArchiver struct{}
+type TarGzArchiver struct {
+	MetadataOptions
+	ExtractFilter
+}
 
 // Extract extracts a .tar.gz archive to the target directory.
 func (t *TarGzArchiver) Extract(source, target string) error {
-	file, err := os.Open(source)
+	file, err := fsys().Open(source)
 	if err != nil {
 		return fmt.Errorf("failed to open file %s: %v", source, err)
 	}
 	defer file.Close()
 
-	gzReader, err := gzip.NewReader(file)
+	return t.ExtractReader(file, target)
+}
+
+// ExtractReader extracts a .tar.gz stream to the target directory.
+func (t *TarGzArchiver) ExtractReader(r io.Reader, target string) error {
+	gzReader, err := gzip.NewReader(r)
 	if err != nil {
 		return fmt.Errorf("failed to create gzip reader: %v", err)
 	}
 	defer gzReader.Close()
 
+	target = LongPath(target)
 	tarReader := tar.NewReader(gzReader)
+	budget := t.newExtractBudget()
+	var dirs []tar.Header
 
 	for {
 		header, err := tarReader.Next()
@@ -46,75 +82,309 @@ func (t *TarGzArchiver) Extract(source, target string) error {
 		}
 
 		// Determine the path where the file will be extracted
-		targetPath := filepath.Join(target, header.Name)
+		targetPath := entryPath(target, header.Name, tarNameEncoding)
 
 		switch header.Typeflag {
 		case tar.TypeDir:
+			if t.excludes(header.Name) {
+				continue
+			}
 			// Create directory
-			if err := os.MkdirAll(targetPath, os.FileMode(header.Mode)); err != nil {
+			if err := fsys().MkdirAll(targetPath, os.FileMode(header.Mode)); err != nil {
 				return fmt.Errorf("failed to create directory %s: %v", targetPath, err)
 			}
+			dirs = append(dirs, *header)
 		case tar.TypeReg:
-			// Create regular file
-			if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
-				return fmt.Errorf("failed to create parent directory for file %s: %v", targetPath, err)
+			if !t.includes(header.Name) {
+				continue
 			}
-			outFile, err := os.Create(targetPath)
-			if err != nil {
-				return fmt.Errorf("failed to create file %s: %v", targetPath, err)
+			// Create regular file
+			if err := t.writeFile(targetPath, budget.reader(header.Name, tarReader)); err != nil {
+				return err
 			}
-			defer outFile.Close()
-
-			if _, err := io.Copy(outFile, tarReader); err != nil {
-				return fmt.Errorf("failed to write to file %s: %v", targetPath, err)
+			if err := t.restoreMetadata(targetPath, header.FileInfo().Mode(), header.Uid, header.Gid, true); err != nil {
+				return err
 			}
 		default:
-			return fmt.Errorf("unsupported tar entry type: %c in file %s", header.Typeflag, source)
+			return fmt.Errorf("unsupported tar entry type: %c", header.Typeflag)
+		}
+	}
+
+	// Directory modes are applied last so read-only directories do not block their contents
+	for i := len(dirs) - 1; i >= 0; i-- {
+		dirPath := entryPath(target, dirs[i].Name, tarNameEncoding)
+		if err := t.restoreMetadata(dirPath, dirs[i].FileInfo().Mode(), dirs[i].Uid, dirs[i].Gid, true); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
-// ZipArchiver handles extraction of .zip archives.
-type ZipArchiver struct{}
+// ZipArchiver handles extraction of .zip archives. Zip entries can be read independently, so
+// large archives are extracted by Concurrency workers in parallel.
+type ZipArchiver struct {
+	MetadataOptions
+	ExtractFilter
+	Concurrency int // Files extracted in parallel (default: DefaultZipConcurrency, 1 extracts sequentially)
+}
+
+// DefaultZipConcurrency is the number of files a ZipArchiver extracts in parallel when Concurrency is unset
+var DefaultZipConcurrency = min(runtime.GOMAXPROCS(0), 8)
 
 // Extract extracts a .zip archive to the target directory.
 func (z *ZipArchiver) Extract(source, target string) error {
-	r, err := zip.OpenReader(source)
+	file, err := fsys().Open(source)
+	if err != nil {
+		return fmt.Errorf("failed to open zip file %s: %v", source, err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
 	if err != nil {
 		return fmt.Errorf("failed to open zip file %s: %v", source, err)
 	}
-	defer r.Close()
+	r, err := zip.NewReader(file, info.Size())
+	if err != nil {
+		return fmt.Errorf("failed to open zip file %s: %v", source, err)
+	}
+
+	return z.extract(r, target)
+}
+
+// ExtractReader extracts a .zip stream to the target directory. Zip archives keep their
+// index at the end, so the stream is spooled to a temporary file inside the target directory.
+func (z *ZipArchiver) ExtractReader(r io.Reader, target string) error {
+	if err := fsys().MkdirAll(target, 0755); err != nil {
+		return fmt.Errorf("failed to create target directory %s: %v", target, err)
+	}
+
+	spool, err := fsys().CreateTemp(target, ".spool-*.zip")
+	if err != nil {
+		return fmt.Errorf("failed to create spool file: %v", err)
+	}
+	defer fsys().Remove(spool.Name())
+	defer spool.Close()
+
+	size, err := io.Copy(spool, r)
+	if err != nil {
+		return fmt.Errorf("failed to spool zip stream: %v", err)
+	}
+
+	zipReader, err := zip.NewReader(spool, size)
+	if err != nil {
+		return fmt.Errorf("failed to read zip stream: %v", err)
+	}
+	return z.extract(zipReader, target)
+}
+
+// concurrency returns the number of files extracted in parallel
+func (z *ZipArchiver) concurrency() int {
+	if z.Concurrency > 0 {
+		return z.Concurrency
+	}
+	return max(DefaultZipConcurrency, 1)
+}
+
+// zipEntry is a file of a zip archive and the path it is extracted to
+type zipEntry struct {
+	file *zip.File
+	path string
+}
 
+// extract writes all entries of a zip archive to the target directory. Directories are created
+// first, so the workers writing files never race to create the same parent directory.
+func (z *ZipArchiver) extract(r *zip.Reader, target string) error {
+	target = LongPath(target)
+	var dirs []*zip.File
+	var files []zipEntry
+	index := make(map[string]int)
 	for _, file := range r.File {
-		targetPath := filepath.Join(target, file.Name)
+		targetPath := entryPath(target, file.Name, zipNameEncoding)
 
 		if file.FileInfo().IsDir() {
+			if z.excludes(file.Name) {
+				continue
+			}
 			// Create directory
-			if err := os.MkdirAll(targetPath, file.Mode()); err != nil {
+			if err := fsys().MkdirAll(targetPath, file.Mode()); err != nil {
 				return fmt.Errorf("failed to create directory %s: %v", targetPath, err)
 			}
+			dirs = append(dirs, file)
 			continue
 		}
 
-		// Create file
-		if err := os.MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
+		if !z.includes(file.Name) {
+			continue
+		}
+		if err := fsys().MkdirAll(filepath.Dir(targetPath), 0755); err != nil {
 			return fmt.Errorf("failed to create parent directory for file %s: %v", targetPath, err)
 		}
-		outFile, err := os.Create(targetPath)
-		if err != nil {
-			return fmt.Errorf("failed to create file %s: %v", targetPath, err)
+		// The last entry for a path wins, as when the entries are extracted in order
+		if i, ok := index[targetPath]; ok {
+			files[i].file = file
+			continue
 		}
-		defer outFile.Close()
+		index[targetPath] = len(files)
+		files = append(files, zipEntry{file: file, path: targetPath})
+	}
 
-		rc, err := file.Open()
-		if err != nil {
-			return fmt.Errorf("failed to open file inside zip %s: %v", file.Name, err)
+	if err := z.extractFiles(files, z.newExtractBudget()); err != nil {
+		return err
+	}
+
+	// Directory modes are applied last so read-only directories do not block their contents
+	for i := len(dirs) - 1; i >= 0; i-- {
+		uid, gid, hasOwner := zipUnixOwner(dirs[i].Extra)
+		if err := z.restoreMetadata(entryPath(target, dirs[i].Name, zipNameEncoding), dirs[i].Mode(), uid, gid, hasOwner); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// extractFiles writes the files with a bounded number of workers and stops at the first failure
+func (z *ZipArchiver) extractFiles(files []zipEntry, budget *extractBudget) error {
+	workers := min(z.concurrency(), len(files))
+	if workers <= 1 {
+		for _, entry := range files {
+			if err := z.extractFile(entry, budget); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	entries := make(chan zipEntry)
+	errs := make(chan error, workers)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for entry := range entries {
+				if err := z.extractFile(entry, budget); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	var err error
+	for i := 0; i < len(files) && err == nil; i++ {
+		select {
+		case entries <- files[i]:
+		case err = <-errs:
+		}
+	}
+	close(entries)
+	<-done
+
+	if err == nil {
+		select {
+		case err = <-errs:
+		default:
+		}
+	}
+	return err
+}
+
+// extractFile writes a single zip entry to its path within the budget and restores its metadata
+func (z *ZipArchiver) extractFile(entry zipEntry, budget *extractBudget) error {
+	rc, err := entry.file.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open file inside zip %s: %v", entry.file.Name, err)
+	}
+	defer rc.Close()
+
+	if err := z.writeFile(entry.path, budget.reader(entry.file.Name, rc)); err != nil {
+		return err
+	}
+	uid, gid, hasOwner := zipUnixOwner(entry.file.Extra)
+	return z.restoreMetadata(entry.path, entry.file.Mode(), uid, gid, hasOwner)
+}
+
+// zipUnixOwner reads the uid and gid from Info-ZIP's "new Unix" extra field (0x7875), if present
+func zipUnixOwner(extra []byte) (uid, gid int, ok bool) {
+	for len(extra) >= 4 {
+		tag := binary.LittleEndian.Uint16(extra[0:2])
+		size := int(binary.LittleEndian.Uint16(extra[2:4]))
+		if len(extra) < 4+size {
+			return 0, 0, false
+		}
+		data := extra[4 : 4+size]
+		extra = extra[4+size:]
+
+		if tag != 0x7875 || len(data) < 2 || data[0] != 1 {
+			continue
+		}
+		ids := make([]int, 0, 2)
+		data = data[1:]
+		for len(ids) < 2 && len(data) > 0 {
+			idSize := int(data[0])
+			if idSize == 0 || idSize > 8 || len(data) < 1+idSize {
+				return 0, 0, false
+			}
+			var value uint64
+			for i := idSize; i >= 1; i-- {
+				value = value<<8 | uint64(data[i])
+			}
+			ids = append(ids, int(value))
+			data = data[1+idSize:]
+		}
+		if len(ids) == 2 {
+			return ids[0], ids[1], true
+		}
+	}
+	return 0, 0, false
+}
+
+// writeFile creates an extracted file, including its parent directories, and closes it before
+// returning so extracting large archives does not keep a descriptor open per entry
+func (o MetadataOptions) writeFile(path string, r io.Reader) error {
+	if err := fsys().MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create parent directory for file %s: %v", path, err)
+	}
+	outFile, err := fsys().Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s: %v", path, err)
+	}
+	if _, err := io.Copy(outFile, r); err != nil {
+		outFile.Close()
+		return fmt.Errorf("failed to write to file %s: %w", path, err)
+	}
+	if o.SyncFiles {
+		// Only files of the operating system can be synced
+		if syncer, ok := outFile.(interface{ Sync() error }); ok {
+			if err := syncer.Sync(); err != nil {
+				outFile.Close()
+				return fmt.Errorf("failed to sync file %s: %v", path, err)
+			}
 		}
-		defer rc.Close()
+	}
+	if err := outFile.Close(); err != nil {
+		return fmt.Errorf("failed to write to file %s: %v", path, err)
+	}
+	return nil
+}
 
-		if _, err := io.Copy(outFile, rc); err != nil {
-			return fmt.Errorf("failed to write to file %s: %v", targetPath, err)
+// restoreMetadata applies the archived mode and ownership to an extracted path according to the options.
+// Only permission bits are restored; setuid, setgid and sticky bits from downloaded archives are dropped.
+func (o MetadataOptions) restoreMetadata(path string, mode os.FileMode, uid, gid int, hasOwner bool) error {
+	if o.PreservePermissions {
+		if err := fsys().Chmod(path, mode.Perm()); err != nil {
+			return fmt.Errorf("failed to set permissions on %s: %v", path, err)
+		}
+	}
+	if o.PreserveOwnership && hasOwner && os.Geteuid() == 0 {
+		if err := fsys().Lchown(path, uid, gid); err != nil {
+			return fmt.Errorf("failed to set ownership on %s: %v", path, err)
 		}
 	}
 	return nil
@@ -131,12 +401,59 @@ func NewArchiveHandler() *ArchiveHandler {
 		archivers: map[string]Archiver{
 			".tar.gz": &TarGzArchiver{},
 			".zip":    &ZipArchiver{},
+			".deb":    &DebArchiver{},
+			".rpm":    &RpmArchiver{},
 		},
 	}
 }
 
+// IsSupported reports whether the source file has an extension handled by one of the archivers.
+func (h *ArchiveHandler) IsSupported(source string) bool {
+	for ext := range h.archivers {
+		if strings.HasSuffix(source, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// configureArchiver returns a copy of the archiver that restores metadata and, for zip archives,
+// extracts files in parallel as configured
+func configureArchiver(archiver Archiver, config *ExtractionConfig) Archiver {
+	options, filter := config.metadataOptions(), config.extractFilter()
+	switch a := archiver.(type) {
+	case *TarGzArchiver:
+		configured := *a
+		configured.MetadataOptions = options
+		configured.ExtractFilter = filter
+		return &configured
+	case *ZipArchiver:
+		configured := *a
+		configured.MetadataOptions = options
+		configured.ExtractFilter = filter
+		if config != nil && config.Concurrency > 0 {
+			configured.Concurrency = config.Concurrency
+		}
+		return &configured
+	case *DebArchiver:
+		configured := *a
+		configured.MetadataOptions = options
+		configured.ExtractFilter = filter
+		return &configured
+	case *RpmArchiver:
+		configured := *a
+		configured.MetadataOptions = options
+		configured.ExtractFilter = filter
+		return &configured
+	}
+	return archiver
+}
+
 // ExtractArchive extracts an archive by delegating to the appropriate Archiver.
 func (h *ArchiveHandler) ExtractArchive(source, target string) error {
+	if archiver, ok := h.sniffPackageArchiver(source); ok {
+		return archiver.Extract(source, target)
+	}
 	// Determine the appropriate Archiver based on the file extension.
 	for ext, archiver := range h.archivers {
 		if strings.HasSuffix(source, ext) {
@@ -146,6 +463,25 @@ func (h *ArchiveHandler) ExtractArchive(source, target string) error {
 	return fmt.Errorf("unsupported file type: %s", source)
 }
 
+// ExtractStream extracts an archive from a stream, choosing the Archiver by the archive's file name.
+func (h *ArchiveHandler) ExtractStream(name string, r io.Reader, target string) error {
+	return h.ExtractStreamWithConfig(name, r, target, nil)
+}
+
+// ExtractStreamWithConfig extracts an archive from a stream, restoring file metadata as configured.
+func (h *ArchiveHandler) ExtractStreamWithConfig(name string, r io.Reader, target string, config *ExtractionConfig) error {
+	for ext, archiver := range h.archivers {
+		if strings.HasSuffix(name, ext) {
+			streamArchiver, ok := configureArchiver(archiver, config).(StreamArchiver)
+			if !ok {
+				return fmt.Errorf("streaming extraction not supported for file type: %s", name)
+			}
+			return streamArchiver.ExtractReader(r, target)
+		}
+	}
+	return fmt.Errorf("unsupported file type: %s", name)
+}
+
 // ExtractArchiveWithConfig extracts an archive with enhanced configuration options
 func (h *ArchiveHandler) ExtractArchiveWithConfig(source, target string, config *ExtractionConfig) error {
 	if config == nil {
@@ -154,7 +490,17 @@ func (h *ArchiveHandler) ExtractArchiveWithConfig(source, target string, config
 
 	// For now, use the standard extraction and handle post-processing
 	// TODO: Implement strip-components functionality in the future
-	err := h.ExtractArchive(source, target)
+	err := fmt.Errorf("unsupported file type: %s", source)
+	if archiver, ok := h.sniffPackageArchiver(source); ok {
+		err = configureArchiver(archiver, config).Extract(source, target)
+		return err
+	}
+	for ext, archiver := range h.archivers {
+		if strings.HasSuffix(source, ext) {
+			err = configureArchiver(archiver, config).Extract(source, target)
+			break
+		}
+	}
 	if err != nil {
 		return err
 	}
@@ -168,8 +514,132 @@ func (h *ArchiveHandler) ExtractArchiveWithConfig(source, target string, config
 	return nil
 }
 
-// ExtractionConfig configures how binaries are extracted from archives
+// ExtractionConfig configures how binaries are extracted from archives. fileUtils and release
+// refer to this type, so a setting added here is available to every installation path.
 type ExtractionConfig struct {
-	StripComponents int    `json:"strip_components"` // Number of directory components to strip (like tar --strip-components)
-	BinaryPath      string `json:"binary_path"`      // Specific path to binary within archive (e.g., "linux-amd64/helm")
+	StripComponents     int               `json:"strip_components"`         // Number of directory components to strip (like tar --strip-components)
+	BinaryPath          string            `json:"binary_path"`              // Path to the binary within the archive with {os}, {arch}, {project} and {version} placeholders and globs (e.g., "{os}-{arch}/helm", "*/bin/tool")
+	ArchMapping         map[string]string `json:"arch_mapping,omitempty"`   // Names substituted for {arch} in BinaryPath keyed by GOARCH (default: GOARCH itself)
+	PreservePermissions bool              `json:"preserve_permissions"`     // Restore file modes stored in the archive (e.g. zip executable bits)
+	PreserveOwnership   bool              `json:"preserve_ownership"`       // Restore file owners stored in the archive when running as root
+	Concurrency         int               `json:"concurrency"`              // Zip entries extracted in parallel (default: DefaultZipConcurrency)
+	SyncFiles           bool              `json:"sync_files"`               // Flush every extracted file to disk (fsync) for durability
+	Include             []string          `json:"include,omitempty"`        // Globs of entries to extract, e.g. "bin/*"; every entry if empty (see ExtractFilter)
+	Exclude             []string          `json:"exclude,omitempty"`        // Globs of entries to skip, e.g. "docs"
+	MaxFileSize         int64             `json:"max_file_size,omitempty"`  // Largest extracted file in bytes, 0 for no limit (ErrFileTooLarge)
+	MaxTotalSize        int64             `json:"max_total_size,omitempty"` // Largest total of extracted bytes, 0 for no limit (ErrArchiveTooLarge)
+}
+
+// extractFilter returns the entry filter and size limits of a possibly nil configuration
+func (c *ExtractionConfig) extractFilter() ExtractFilter {
+	if c == nil {
+		return ExtractFilter{}
+	}
+	return ExtractFilter{Include: c.Include, Exclude: c.Exclude, MaxFileSize: c.MaxFileSize, MaxTotalSize: c.MaxTotalSize}
+}
+
+// metadataOptions returns the metadata restoration options of a possibly nil configuration
+func (c *ExtractionConfig) metadataOptions() MetadataOptions {
+	if c == nil {
+		return MetadataOptions{}
+	}
+	return MetadataOptions{
+		PreservePermissions: c.PreservePermissions,
+		PreserveOwnership:   c.PreserveOwnership,
+		SyncFiles:           c.SyncFiles,
+	}
+}
+
+// CreateTarGz packages the contents of sourceDir into a .tar.gz archive at destination.
+// Entries are stored relative to sourceDir. extraFiles are appended at the archive root,
+// which allows callers to embed generated content such as manifests. A partial archive is
+// removed when writing fails.
+func CreateTarGz(sourceDir, destination string, extraFiles map[string][]byte) (err error) {
+	out, err := fsys().Create(destination)
+	if err != nil {
+		return fmt.Errorf("failed to create archive %s: %v", destination, err)
+	}
+	defer func() {
+		if closeErr := out.Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close archive %s: %v", destination, closeErr)
+		}
+		if err != nil {
+			fsys().Remove(destination)
+		}
+	}()
+
+	gzWriter := gzip.NewWriter(out)
+	tarWriter := tar.NewWriter(gzWriter)
+
+	err = filesystem.WalkDir(fsys(), sourceDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(sourceDir, path)
+		if err != nil {
+			return err
+		}
+		if relPath == "." {
+			return nil
+		}
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			// Skip symlinks and special files, only real content is exported
+			return nil
+		}
+
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return fmt.Errorf("failed to create header for %s: %v", path, err)
+		}
+		header.Name = filepath.ToSlash(relPath)
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write header for %s: %v", path, err)
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		file, err := fsys().Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open file %s: %v", path, err)
+		}
+		defer file.Close()
+		if _, err := io.Copy(tarWriter, file); err != nil {
+			return fmt.Errorf("failed to write file %s to archive: %v", path, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for name, content := range extraFiles {
+		header := &tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write header for %s: %v", name, err)
+		}
+		if _, err := tarWriter.Write(content); err != nil {
+			return fmt.Errorf("failed to write %s to archive: %v", name, err)
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return fmt.Errorf("failed to finalize tar archive: %v", err)
+	}
+	if err := gzWriter.Close(); err != nil {
+		return fmt.Errorf("failed to finalize gzip stream: %v", err)
+	}
+	return nil
 }
@@ -0,0 +1,259 @@
+package archiver
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/filesystem"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// countingFS is a MemFS that tracks how many files are open at the same time
+type countingFS struct {
+	*filesystem.MemFS
+	mu      sync.Mutex
+	open    int
+	maxOpen int
+}
+
+func (c *countingFS) track(file filesystem.File, err error) (filesystem.File, error) {
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.open++
+	c.maxOpen = max(c.maxOpen, c.open)
+	return &countingFile{File: file, fs: c}, nil
+}
+
+func (c *countingFS) Open(name string) (filesystem.File, error) {
+	return c.track(c.MemFS.Open(name))
+}
+
+func (c *countingFS) Create(name string) (filesystem.File, error) {
+	return c.track(c.MemFS.Create(name))
+}
+
+func (c *countingFS) OpenFile(name string, flag int, perm os.FileMode) (filesystem.File, error) {
+	return c.track(c.MemFS.OpenFile(name, flag, perm))
+}
+
+func (c *countingFS) CreateTemp(dir, pattern string) (filesystem.File, error) {
+	return c.track(c.MemFS.CreateTemp(dir, pattern))
+}
+
+// countingFile reports its first Close to the countingFS
+type countingFile struct {
+	filesystem.File
+	fs     *countingFS
+	closed bool
+}
+
+func (f *countingFile) Close() error {
+	f.fs.mu.Lock()
+	if !f.closed {
+		f.closed = true
+		f.fs.open--
+	}
+	f.fs.mu.Unlock()
+	return f.File.Close()
+}
+
+// writeTestZip writes a zip archive with the given entries, in order, to path
+func writeTestZip(t testing.TB, path string, entries [][2]string) {
+	t.Helper()
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	for _, entry := range entries {
+		header := &zip.FileHeader{Name: entry[0], Method: zip.Deflate}
+		header.SetMode(0755)
+		out, err := writer.CreateHeader(header)
+		if err != nil {
+			t.Fatalf("Failed to add %s: %v", entry[0], err)
+		}
+		out.Write([]byte(entry[1]))
+	}
+	writer.Close()
+	if err := os.WriteFile(path, buffer.Bytes(), 0644); err != nil {
+		t.Fatalf("Failed to write archive: %v", err)
+	}
+}
+
+func TestZipArchiver_Concurrency(t *testing.T) {
+	tempDir := t.TempDir()
+	archivePath := filepath.Join(tempDir, "bundle.zip")
+	var entries [][2]string
+	for i := 0; i < 50; i++ {
+		entries = append(entries, [2]string{fmt.Sprintf("bundle/dir%d/file%d", i%5, i), fmt.Sprintf("content %d", i)})
+	}
+	// Duplicate entries keep the content of the last one
+	entries = append(entries, [2]string{"bundle/dir0/file0", "replaced"})
+	writeTestZip(t, archivePath, entries)
+
+	for _, concurrency := range []int{1, 8} {
+		t.Run(fmt.Sprintf("Concurrency%d", concurrency), func(t *testing.T) {
+			target := filepath.Join(tempDir, fmt.Sprint(concurrency))
+			archiver := &ZipArchiver{MetadataOptions: MetadataOptions{PreservePermissions: true}, Concurrency: concurrency}
+			if err := archiver.Extract(archivePath, target); err != nil {
+				t.Fatalf("Extract() error = %v", err)
+			}
+			for i := 1; i < 50; i++ {
+				path := filepath.Join(target, "bundle", fmt.Sprintf("dir%d", i%5), fmt.Sprintf("file%d", i))
+				if data, err := os.ReadFile(path); err != nil || string(data) != fmt.Sprintf("content %d", i) {
+					t.Errorf("ReadFile(%s) = %q, %v", path, data, err)
+				}
+			}
+			if data, _ := os.ReadFile(filepath.Join(target, "bundle", "dir0", "file0")); string(data) != "replaced" {
+				t.Errorf("Expected the last duplicate entry to win, got %q", data)
+			}
+			if info, err := os.Stat(filepath.Join(target, "bundle", "dir1", "file1")); err != nil || info.Mode().Perm() != 0755 {
+				t.Errorf("Expected the archived mode to be restored, got %v, %v", info, err)
+			}
+		})
+	}
+}
+
+func TestZipArchiver_ConcurrentFailure(t *testing.T) {
+	tempDir := t.TempDir()
+	archivePath := filepath.Join(tempDir, "bundle.zip")
+	var entries [][2]string
+	for i := 0; i < 20; i++ {
+		entries = append(entries, [2]string{fmt.Sprintf("file%d", i), "content"})
+	}
+	writeTestZip(t, archivePath, entries)
+
+	// A directory in place of a file makes its extraction fail
+	target := filepath.Join(tempDir, "target")
+	os.MkdirAll(filepath.Join(target, "file7"), 0755)
+	if err := (&ZipArchiver{Concurrency: 4}).Extract(archivePath, target); err == nil {
+		t.Error("Expected the failure of one worker to be returned")
+	}
+}
+
+func BenchmarkZipArchiver_Extract(b *testing.B) {
+	tempDir := b.TempDir()
+	archivePath := filepath.Join(tempDir, "bundle.zip")
+	content := string(bytes.Repeat([]byte("kubernetes server binary "), 40<<10))
+	var entries [][2]string
+	for i := 0; i < 32; i++ {
+		entries = append(entries, [2]string{fmt.Sprintf("kubernetes/server/bin/tool%d", i), content})
+	}
+	writeTestZip(b, archivePath, entries)
+
+	for _, concurrency := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("Concurrency%d", concurrency), func(b *testing.B) {
+			archiver := &ZipArchiver{Concurrency: concurrency}
+			for i := 0; i < b.N; i++ {
+				target := filepath.Join(tempDir, "extract")
+				if err := archiver.Extract(archivePath, target); err != nil {
+					b.Fatalf("Extract() error = %v", err)
+				}
+				b.StopTimer()
+				os.RemoveAll(target)
+				b.StartTimer()
+			}
+		})
+	}
+}
+
+func TestExtract_ClosesFilesPerEntry(t *testing.T) {
+	counting := &countingFS{MemFS: filesystem.NewMemFS()}
+	filesystem.SetDefault(counting)
+	defer filesystem.SetDefault(nil)
+
+	var tarGz bytes.Buffer
+	gzWriter := gzip.NewWriter(&tarGz)
+	tarWriter := tar.NewWriter(gzWriter)
+	var zipEntries [][2]string
+	for i := 0; i < 200; i++ {
+		name := fmt.Sprintf("bundle/file%d", i)
+		tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: 4, Typeflag: tar.TypeReg})
+		tarWriter.Write([]byte("data"))
+		zipEntries = append(zipEntries, [2]string{name, "data"})
+	}
+	tarWriter.Close()
+	gzWriter.Close()
+	var zipArchive bytes.Buffer
+	zipWriter := zip.NewWriter(&zipArchive)
+	for _, entry := range zipEntries {
+		out, _ := zipWriter.Create(entry[0])
+		out.Write([]byte(entry[1]))
+	}
+	zipWriter.Close()
+	root := filepath.Join(string(filepath.Separator), "work")
+	tarGzPath, zipPath := filepath.Join(root, "bundle.tar.gz"), filepath.Join(root, "bundle.zip")
+	counting.MkdirAll(root, 0755)
+	counting.WriteFile(tarGzPath, tarGz.Bytes(), 0644)
+	counting.WriteFile(zipPath, zipArchive.Bytes(), 0644)
+
+	testCases := []struct {
+		name     string
+		archiver Archiver
+		source   string
+		maxOpen  int // The archive plus the files written in parallel
+	}{
+		{"TarGz", &TarGzArchiver{}, tarGzPath, 2},
+		{"ZipSequential", &ZipArchiver{Concurrency: 1}, zipPath, 2},
+		{"ZipParallel", &ZipArchiver{Concurrency: 4}, zipPath, 5},
+		{"Synced", &TarGzArchiver{MetadataOptions: MetadataOptions{SyncFiles: true}}, tarGzPath, 2},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			counting.maxOpen = 0
+			target := filepath.Join(root, tc.name)
+			if err := tc.archiver.Extract(tc.source, target); err != nil {
+				t.Fatalf("Extract() error = %v", err)
+			}
+			if counting.maxOpen > tc.maxOpen {
+				t.Errorf("Expected at most %d open files, got %d", tc.maxOpen, counting.maxOpen)
+			}
+			if counting.open != 0 {
+				t.Errorf("Expected every file to be closed, %d still open", counting.open)
+			}
+			if data, err := counting.ReadFile(filepath.Join(target, "bundle", "file199")); err != nil || string(data) != "data" {
+				t.Errorf("ReadFile() = %q, %v", data, err)
+			}
+		})
+	}
+}
+
+func TestMetadataOptions_SyncFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "file")
+	options := MetadataOptions{SyncFiles: true}
+	if err := options.writeFile(path, bytes.NewReader([]byte("durable"))); err != nil {
+		t.Fatalf("writeFile() error = %v", err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "durable" {
+		t.Errorf("ReadFile() = %q, %v", data, err)
+	}
+}
+
+func TestExtractCpio_OversizedName(t *testing.T) {
+	var cpio bytes.Buffer
+	fmt.Fprintf(&cpio, "070701%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X",
+		0, 0100755, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0xFFFFFFFF, 0)
+	cpio.WriteString("tool\x00")
+
+	err := extractCpio(&cpio, t.TempDir(), MetadataOptions{}, ExtractFilter{})
+	if err == nil || !strings.Contains(err.Error(), "exceeds") {
+		t.Errorf("Expected an oversized entry name to be rejected, got %v", err)
+	}
+}
+
+func TestCreateTarGz_RemovesPartialArchive(t *testing.T) {
+	tempDir := t.TempDir()
+	destination := filepath.Join(tempDir, "export.tar.gz")
+	if err := CreateTarGz(filepath.Join(tempDir, "missing"), destination, nil); err == nil {
+		t.Fatal("Expected an error for a missing source directory")
+	}
+	if _, err := os.Stat(destination); !os.IsNotExist(err) {
+		t.Errorf("Expected the partial archive to be removed, stat error: %v", err)
+	}
+}
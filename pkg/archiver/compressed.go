@@ -0,0 +1,112 @@
+package archiver
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// compressedExtensions are the single-file compression formats, as opposed to compressed tarballs
+var compressedExtensions = []string{".gz", ".xz", ".zst"}
+
+// Magic numbers identifying the supported compression formats
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	xzMagic   = []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+	bzipMagic = []byte("BZh")
+)
+
+// IsCompressedFile reports whether name is a single compressed file (.gz, .xz or .zst)
+// rather than a compressed tar archive such as .tar.gz
+func IsCompressedFile(name string) bool {
+	lower := strings.ToLower(name)
+	for _, ext := range compressedExtensions {
+		if strings.HasSuffix(lower, ext) && !strings.HasSuffix(lower, ".tar"+ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewDecompressor returns a reader that decompresses r (gzip, xz, zstd or bzip2). The format
+// is detected from the stream's magic number, falling back to the extension of name.
+func NewDecompressor(name string, r io.Reader) (io.ReadCloser, error) {
+	buffered := bufio.NewReader(r)
+	header, _ := buffered.Peek(len(xzMagic))
+
+	format := strings.ToLower(filepath.Ext(name))
+	switch {
+	case bytes.HasPrefix(header, gzipMagic):
+		format = ".gz"
+	case bytes.HasPrefix(header, xzMagic):
+		format = ".xz"
+	case bytes.HasPrefix(header, zstdMagic):
+		format = ".zst"
+	case bytes.HasPrefix(header, bzipMagic):
+		format = ".bz2"
+	}
+
+	switch format {
+	case ".gz":
+		gzReader, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gzip reader: %v", err)
+		}
+		return gzReader, nil
+	case ".xz":
+		xzReader, err := xz.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create xz reader: %v", err)
+		}
+		return io.NopCloser(xzReader), nil
+	case ".zst":
+		zstdReader, err := zstd.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd reader: %v", err)
+		}
+		return zstdReader.IOReadCloser(), nil
+	case ".bz2":
+		return io.NopCloser(bzip2.NewReader(buffered)), nil
+	}
+	return nil, fmt.Errorf("unsupported compression format: %s", name)
+}
+
+// DecompressFile decompresses a single compressed file (.gz, .xz or .zst) to destination
+func DecompressFile(source, destination string) error {
+	file, err := fsys().Open(source)
+	if err != nil {
+		return fmt.Errorf("failed to open file %s: %v", source, err)
+	}
+	defer file.Close()
+
+	return DecompressReader(source, file, destination)
+}
+
+// DecompressReader decompresses a single compressed stream to destination, using name to
+// identify the format when the stream has no recognizable magic number
+func DecompressReader(name string, r io.Reader, destination string) error {
+	decompressor, err := NewDecompressor(name, r)
+	if err != nil {
+		return err
+	}
+	defer decompressor.Close()
+
+	outFile, err := fsys().Create(destination)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s: %v", destination, err)
+	}
+	defer outFile.Close()
+
+	if _, err := io.Copy(outFile, decompressor); err != nil {
+		return fmt.Errorf("failed to decompress %s: %v", name, err)
+	}
+	return outFile.Close()
+}
@@ -0,0 +1,109 @@
+package archiver
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"sync/atomic"
+)
+
+// Errors returned when extraction exceeds a size limit of the ExtractFilter. Sizes are counted
+// while entries are written, so archives that understate sizes in their headers are stopped too.
+var (
+	ErrFileTooLarge    = errors.New("archive entry exceeds the maximum file size")
+	ErrArchiveTooLarge = errors.New("archive exceeds the maximum extracted size")
+)
+
+// ExtractFilter selects the archive entries that are extracted and limits how much data they
+// may produce, protecting against decompression bombs. Globs use path.Match syntax and also match
+// the entries below a matching directory. Globs containing a slash match the slash-separated path
+// from the archive root ("bin/*"), others match a name at any depth ("docs", "*.md"). Without
+// settings every entry is extracted.
+type ExtractFilter struct {
+	Include      []string // Globs of entries to extract, e.g. "bin/*"; every entry if empty
+	Exclude      []string // Globs of entries to skip, applied after Include
+	MaxFileSize  int64    // Largest extracted file in bytes, 0 for no limit
+	MaxTotalSize int64    // Largest total of extracted bytes, 0 for no limit
+}
+
+// includes reports whether a file entry passes the Include and Exclude globs
+func (f ExtractFilter) includes(name string) bool {
+	name = entryName(name)
+	if len(f.Include) > 0 && !matchesAny(f.Include, name) {
+		return false
+	}
+	return !matchesAny(f.Exclude, name)
+}
+
+// excludes reports whether a directory entry matches an Exclude glob
+func (f ExtractFilter) excludes(name string) bool {
+	return matchesAny(f.Exclude, entryName(name))
+}
+
+// entryName normalizes an archive entry name to a relative slash-separated path
+func entryName(name string) string {
+	name = path.Clean("/" + strings.ReplaceAll(name, `\`, "/"))
+	return strings.TrimPrefix(name, "/")
+}
+
+// matchesAny reports whether a glob matches the entry or one of its parent directories
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		pattern = strings.Trim(pattern, "/")
+		baseOnly := !strings.Contains(pattern, "/")
+		for candidate := name; candidate != "." && candidate != ""; candidate = path.Dir(candidate) {
+			subject := candidate
+			if baseOnly {
+				subject = path.Base(candidate)
+			}
+			if ok, _ := path.Match(pattern, subject); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// extractBudget tracks the bytes written by one extraction against the filter's size limits.
+// It is safe for the concurrent workers of a ZipArchiver.
+type extractBudget struct {
+	filter  ExtractFilter
+	written atomic.Int64
+}
+
+// newExtractBudget returns the budget of a single extraction
+func (f ExtractFilter) newExtractBudget() *extractBudget {
+	return &extractBudget{filter: f}
+}
+
+// reader returns r limited to the remaining budget; reading past a limit fails with
+// ErrFileTooLarge or ErrArchiveTooLarge
+func (b *extractBudget) reader(name string, r io.Reader) io.Reader {
+	if b.filter.MaxFileSize <= 0 && b.filter.MaxTotalSize <= 0 {
+		return r
+	}
+	return &budgetReader{reader: r, name: name, budget: b}
+}
+
+// budgetReader counts the bytes of an entry as they are read
+type budgetReader struct {
+	reader io.Reader
+	name   string
+	read   int64
+	budget *extractBudget
+}
+
+func (r *budgetReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.read += int64(n)
+	if limit := r.budget.filter.MaxFileSize; limit > 0 && r.read > limit {
+		return n, fmt.Errorf("%w: %s is larger than %d bytes", ErrFileTooLarge, r.name, limit)
+	}
+	total := r.budget.written.Add(int64(n))
+	if limit := r.budget.filter.MaxTotalSize; limit > 0 && total > limit {
+		return n, fmt.Errorf("%w: more than %d bytes extracted at %s", ErrArchiveTooLarge, limit, r.name)
+	}
+	return n, err
+}
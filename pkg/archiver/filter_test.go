@@ -0,0 +1,124 @@
+package archiver
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTestTarGz writes a .tar.gz with the given entries, in order, to path
+func writeTestTarGz(t *testing.T, path string, entries [][2]string) {
+	t.Helper()
+	var buffer bytes.Buffer
+	gzWriter := gzip.NewWriter(&buffer)
+	tarWriter := tar.NewWriter(gzWriter)
+	for _, entry := range entries {
+		tarWriter.WriteHeader(&tar.Header{Name: entry[0], Mode: 0644, Size: int64(len(entry[1])), Typeflag: tar.TypeReg})
+		tarWriter.Write([]byte(entry[1]))
+	}
+	tarWriter.Close()
+	gzWriter.Close()
+	if err := os.WriteFile(path, buffer.Bytes(), 0644); err != nil {
+		t.Fatalf("Failed to write archive: %v", err)
+	}
+}
+
+func TestExtractFilter_Includes(t *testing.T) {
+	filter := ExtractFilter{Include: []string{"bin/*", "*.md"}, Exclude: []string{"docs", "bin/*-debug"}}
+	testCases := []struct {
+		name     string
+		expected bool
+	}{
+		{"bin/tool", true},
+		{"./bin/tool", true},
+		{"bin/tool-debug", false},
+		{"README.md", true},
+		{"docs/guide.md", false},
+		{"lib/libtool.so", false},
+		{"bin/nested/tool", true},
+		{"lib/bin/tool", false},
+	}
+	for _, tc := range testCases {
+		if got := filter.includes(tc.name); got != tc.expected {
+			t.Errorf("includes(%q) = %v, want %v", tc.name, got, tc.expected)
+		}
+	}
+	if !(ExtractFilter{}).includes("anything/at/all") {
+		t.Error("Expected an empty filter to include every entry")
+	}
+}
+
+func TestExtractArchiveWithConfig_Filters(t *testing.T) {
+	tempDir := t.TempDir()
+	entries := [][2]string{
+		{"bundle/bin/tool", "binary"},
+		{"bundle/docs/guide.md", "guide"},
+		{"bundle/LICENSE", "license"},
+	}
+	tarPath := filepath.Join(tempDir, "bundle.tar.gz")
+	writeTestTarGz(t, tarPath, entries)
+	zipPath := filepath.Join(tempDir, "bundle.zip")
+	writeTestZip(t, zipPath, entries)
+
+	for _, source := range []string{tarPath, zipPath} {
+		t.Run(filepath.Ext(source), func(t *testing.T) {
+			target := filepath.Join(tempDir, "filtered"+filepath.Ext(source))
+			config := &ExtractionConfig{Exclude: []string{"docs", "LICENSE"}}
+			if err := NewArchiveHandler().ExtractArchiveWithConfig(source, target, config); err != nil {
+				t.Fatalf("ExtractArchiveWithConfig() error = %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(target, "bundle", "bin", "tool")); err != nil {
+				t.Errorf("Expected the binary to be extracted: %v", err)
+			}
+			for _, skipped := range []string{"docs", "LICENSE"} {
+				if _, err := os.Stat(filepath.Join(target, "bundle", skipped)); !os.IsNotExist(err) {
+					t.Errorf("Expected %s to be skipped, Stat() error = %v", skipped, err)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractArchiveWithConfig_SizeLimits(t *testing.T) {
+	tempDir := t.TempDir()
+	entries := [][2]string{
+		{"a", strings.Repeat("a", 600)},
+		{"b", strings.Repeat("b", 600)},
+	}
+	tarPath := filepath.Join(tempDir, "bomb.tar.gz")
+	writeTestTarGz(t, tarPath, entries)
+	zipPath := filepath.Join(tempDir, "bomb.zip")
+	writeTestZip(t, zipPath, entries)
+
+	testCases := []struct {
+		name     string
+		config   ExtractionConfig
+		expected error
+	}{
+		{"FileLimit", ExtractionConfig{MaxFileSize: 500}, ErrFileTooLarge},
+		{"TotalLimit", ExtractionConfig{MaxTotalSize: 1000}, ErrArchiveTooLarge},
+		{"WithinLimits", ExtractionConfig{MaxFileSize: 600, MaxTotalSize: 1200}, nil},
+		{"SkippedEntriesAreNotCounted", ExtractionConfig{MaxTotalSize: 1000, Exclude: []string{"b"}}, nil},
+	}
+	for _, source := range []string{tarPath, zipPath} {
+		for _, tc := range testCases {
+			t.Run(filepath.Ext(source)+"/"+tc.name, func(t *testing.T) {
+				target := filepath.Join(tempDir, filepath.Ext(source)+tc.name)
+				config := tc.config
+				config.Concurrency = 2
+				err := NewArchiveHandler().ExtractArchiveWithConfig(source, target, &config)
+				if tc.expected == nil && err != nil {
+					t.Errorf("ExtractArchiveWithConfig() error = %v", err)
+				}
+				if tc.expected != nil && !errors.Is(err, tc.expected) {
+					t.Errorf("ExtractArchiveWithConfig() error = %v, want %v", err, tc.expected)
+				}
+			})
+		}
+	}
+}
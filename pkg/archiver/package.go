@@ -0,0 +1,280 @@
+package archiver
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Magic numbers of the supported package formats
+var (
+	arMagic      = []byte("!<arch>\n")
+	rpmLeadMagic = []byte{0xed, 0xab, 0xee, 0xdb}
+	rpmHdrMagic  = []byte{0x8e, 0xad, 0xe8, 0x01}
+)
+
+// DebArchiver extracts the data payload of Debian packages (.deb). The control files and
+// maintainer scripts are ignored; only the files the package would install are written.
+type DebArchiver struct {
+	MetadataOptions
+	ExtractFilter
+}
+
+// Extract extracts the payload of a .deb package to the target directory.
+func (d *DebArchiver) Extract(source, target string) error {
+	file, err := fsys().Open(source)
+	if err != nil {
+		return fmt.Errorf("failed to open file %s: %v", source, err)
+	}
+	defer file.Close()
+
+	return d.ExtractReader(file, target)
+}
+
+// ExtractReader extracts the payload of a .deb stream. A .deb is an ar archive whose
+// data.tar member (optionally gzip, xz, zstd or bzip2 compressed) holds the installed files.
+func (d *DebArchiver) ExtractReader(r io.Reader, target string) error {
+	reader := bufio.NewReader(r)
+	magic := make([]byte, len(arMagic))
+	if _, err := io.ReadFull(reader, magic); err != nil || !bytes.Equal(magic, arMagic) {
+		return fmt.Errorf("not a debian package: missing ar header")
+	}
+
+	header := make([]byte, 60)
+	for {
+		if _, err := io.ReadFull(reader, header); err != nil {
+			if err == io.EOF {
+				return fmt.Errorf("debian package has no data.tar member")
+			}
+			return fmt.Errorf("failed to read ar header: %v", err)
+		}
+		name := strings.TrimSuffix(strings.TrimSpace(string(header[0:16])), "/")
+		size, err := strconv.ParseInt(strings.TrimSpace(string(header[48:58])), 10, 64)
+		if err != nil || size < 0 {
+			return fmt.Errorf("invalid size in ar header for %s", name)
+		}
+
+		member := io.LimitReader(reader, size)
+		if strings.HasPrefix(name, "data.tar") {
+			payload := io.Reader(member)
+			if name != "data.tar" {
+				decompressor, err := NewDecompressor(name, member)
+				if err != nil {
+					return fmt.Errorf("failed to read %s: %v", name, err)
+				}
+				defer decompressor.Close()
+				payload = decompressor
+			}
+			return extractPackageTar(tar.NewReader(payload), target, d.MetadataOptions, d.ExtractFilter)
+		}
+
+		// Members are padded to an even size
+		if _, err := io.CopyN(io.Discard, reader, size+size%2); err != nil {
+			return fmt.Errorf("failed to skip ar member %s: %v", name, err)
+		}
+	}
+}
+
+// extractPackageTar writes the regular files and directories of a package payload to the target
+// directory. Links and special files are skipped since packages commonly ship documentation
+// symlinks that are irrelevant to the binary.
+func extractPackageTar(tarReader *tar.Reader, target string, options MetadataOptions, filter ExtractFilter) error {
+	target = LongPath(target)
+	budget := filter.newExtractBudget()
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar entry: %v", err)
+		}
+
+		targetPath := entryPath(target, header.Name, tarNameEncoding)
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if filter.excludes(header.Name) {
+				continue
+			}
+			if err := fsys().MkdirAll(targetPath, 0755); err != nil {
+				return fmt.Errorf("failed to create directory %s: %v", targetPath, err)
+			}
+		case tar.TypeReg:
+			if !filter.includes(header.Name) {
+				continue
+			}
+			if err := options.writeFile(targetPath, budget.reader(header.Name, tarReader)); err != nil {
+				return err
+			}
+			if err := options.restoreMetadata(targetPath, header.FileInfo().Mode(), header.Uid, header.Gid, true); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// RpmArchiver extracts the payload of RPM packages (.rpm).
+type RpmArchiver struct {
+	MetadataOptions
+	ExtractFilter
+}
+
+// Extract extracts the payload of a .rpm package to the target directory.
+func (p *RpmArchiver) Extract(source, target string) error {
+	file, err := fsys().Open(source)
+	if err != nil {
+		return fmt.Errorf("failed to open file %s: %v", source, err)
+	}
+	defer file.Close()
+
+	return p.ExtractReader(file, target)
+}
+
+// ExtractReader extracts the payload of a .rpm stream. The lead, signature and header sections
+// are skipped; the payload is a compressed cpio archive in the "newc" format.
+func (p *RpmArchiver) ExtractReader(r io.Reader, target string) error {
+	reader := bufio.NewReader(r)
+	lead := make([]byte, 96)
+	if _, err := io.ReadFull(reader, lead); err != nil || !bytes.Equal(lead[0:4], rpmLeadMagic) {
+		return fmt.Errorf("not an rpm package: missing lead")
+	}
+
+	// The signature header is padded to a multiple of 8 bytes, the main header is not
+	signatureSize, err := skipRpmHeader(reader)
+	if err != nil {
+		return fmt.Errorf("failed to read rpm signature: %v", err)
+	}
+	if padding := (8 - signatureSize%8) % 8; padding > 0 {
+		if _, err := io.CopyN(io.Discard, reader, padding); err != nil {
+			return fmt.Errorf("failed to read rpm signature: %v", err)
+		}
+	}
+	if _, err := skipRpmHeader(reader); err != nil {
+		return fmt.Errorf("failed to read rpm header: %v", err)
+	}
+
+	payload, err := NewDecompressor("payload", reader)
+	if err != nil {
+		return fmt.Errorf("failed to read rpm payload: %v", err)
+	}
+	defer payload.Close()
+	return extractCpio(payload, target, p.MetadataOptions, p.ExtractFilter)
+}
+
+// skipRpmHeader discards an rpm header structure and returns its size in bytes
+func skipRpmHeader(r io.Reader) (int64, error) {
+	intro := make([]byte, 16)
+	if _, err := io.ReadFull(r, intro); err != nil {
+		return 0, err
+	}
+	if !bytes.Equal(intro[0:4], rpmHdrMagic) {
+		return 0, fmt.Errorf("invalid header magic")
+	}
+	indexCount := int64(binary.BigEndian.Uint32(intro[8:12]))
+	dataSize := int64(binary.BigEndian.Uint32(intro[12:16]))
+	size := indexCount*16 + dataSize
+	if _, err := io.CopyN(io.Discard, r, size); err != nil {
+		return 0, err
+	}
+	return 16 + size, nil
+}
+
+// maxCpioNameSize bounds the entry name length read from cpio headers, so a corrupt or crafted
+// package cannot force a huge allocation
+const maxCpioNameSize = 64 << 10
+
+// extractCpio writes the regular files and directories of a "newc" cpio archive to the target directory
+func extractCpio(r io.Reader, target string, options MetadataOptions, filter ExtractFilter) error {
+	target = LongPath(target)
+	budget := filter.newExtractBudget()
+	header := make([]byte, 110)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			return fmt.Errorf("failed to read cpio header: %v", err)
+		}
+		magic := string(header[0:6])
+		if magic != "070701" && magic != "070702" {
+			return fmt.Errorf("unsupported cpio format: %q", magic)
+		}
+
+		var fields [13]int64
+		for i := range fields {
+			value, err := strconv.ParseInt(string(header[6+i*8:14+i*8]), 16, 64)
+			if err != nil {
+				return fmt.Errorf("invalid cpio header: %v", err)
+			}
+			fields[i] = value
+		}
+		mode, uid, gid, fileSize, nameSize := fields[1], fields[2], fields[3], fields[6], fields[11]
+		if nameSize > maxCpioNameSize {
+			return fmt.Errorf("invalid cpio header: entry name of %d bytes exceeds %d bytes", nameSize, maxCpioNameSize)
+		}
+
+		// The name is NUL terminated and padded so that header and name end on a 4-byte boundary
+		name := make([]byte, nameSize+(4-(110+nameSize)%4)%4)
+		if _, err := io.ReadFull(r, name); err != nil {
+			return fmt.Errorf("failed to read cpio entry name: %v", err)
+		}
+		entryName := strings.TrimRight(string(name[:nameSize]), "\x00")
+		if entryName == "TRAILER!!!" {
+			return nil
+		}
+
+		data := io.LimitReader(r, fileSize)
+		targetPath := entryPath(target, entryName, tarNameEncoding)
+		switch mode & 0170000 {
+		case 0040000:
+			if filter.excludes(entryName) {
+				break
+			}
+			if err := fsys().MkdirAll(targetPath, 0755); err != nil {
+				return fmt.Errorf("failed to create directory %s: %v", targetPath, err)
+			}
+		case 0100000:
+			if !filter.includes(entryName) {
+				break
+			}
+			if err := options.writeFile(targetPath, budget.reader(entryName, data)); err != nil {
+				return err
+			}
+			if err := options.restoreMetadata(targetPath, os.FileMode(mode&0777), int(uid), int(gid), true); err != nil {
+				return err
+			}
+		}
+
+		// Skip unread data of links and special files, plus the padding to a 4-byte boundary
+		if _, err := io.Copy(io.Discard, data); err != nil {
+			return fmt.Errorf("failed to read cpio entry %s: %v", entryName, err)
+		}
+		if _, err := io.CopyN(io.Discard, r, (4-fileSize%4)%4); err != nil {
+			return fmt.Errorf("failed to read cpio entry %s: %v", entryName, err)
+		}
+	}
+}
+
+// sniffPackageArchiver returns the package archiver matching the file's magic number, if any.
+// Packages are often staged under a generic file name, so their extension cannot be relied on.
+func (h *ArchiveHandler) sniffPackageArchiver(source string) (Archiver, bool) {
+	file, err := fsys().Open(source)
+	if err != nil {
+		return nil, false
+	}
+	defer file.Close()
+
+	header := make([]byte, len(arMagic))
+	n, _ := io.ReadFull(file, header)
+	header = header[:n]
+	switch {
+	case bytes.HasPrefix(header, arMagic):
+		return h.archivers[".deb"], true
+	case bytes.HasPrefix(header, rpmLeadMagic):
+		return h.archivers[".rpm"], true
+	}
+	return nil, false
+}
@@ -0,0 +1,114 @@
+package archiver
+
+import (
+	"gitlab.com/locke-codes/go-binary-updater/pkg/filesystem"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/unicode/norm"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"unicode/utf8"
+)
+
+// windowsReservedNames are device names Windows does not allow as file names, with or without extension
+var windowsReservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// Encodings of entry names that are not valid UTF-8: zip archives default to code page 437,
+// tar and cpio archives written by older tools usually use Latin-1
+var (
+	zipNameEncoding = charmap.CodePage437
+	tarNameEncoding = charmap.ISO8859_1
+)
+
+// LongPath returns path in a form that is not limited to MAX_PATH (260 characters) on Windows:
+// the absolute path with the \\?\ prefix, or \\?\UNC\ for network shares. Deeply nested archive
+// entries would fail to extract otherwise. Other platforms and file systems return path unchanged.
+func LongPath(path string) string {
+	if runtime.GOOS != "windows" || path == "" || !filesystem.IsOS(fsys()) {
+		return path
+	}
+	absolute, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	return windowsLongPath(absolute)
+}
+
+// windowsLongPath adds the long path prefix to an absolute Windows path
+func windowsLongPath(path string) string {
+	switch {
+	case strings.HasPrefix(path, `\\?\`), strings.HasPrefix(path, `\\.\`):
+		return path
+	case strings.HasPrefix(path, `\\`):
+		return `\\?\UNC\` + path[2:]
+	}
+	return `\\?\` + path
+}
+
+// SanitizeFileName returns name as a valid file name for the platform. On macOS, whose file
+// systems normalize names themselves, names are normalized to Unicode NFC so extracted paths
+// match what is stored on disk; on Windows, characters that are invalid in file names are
+// replaced with "_", trailing dots and spaces are removed and reserved device names such as
+// "CON" get a "_" suffix. Names are otherwise returned unchanged.
+func SanitizeFileName(name string) string {
+	switch runtime.GOOS {
+	case "darwin":
+		return norm.NFC.String(name)
+	case "windows":
+		return sanitizeWindowsFileName(name)
+	}
+	return name
+}
+
+// sanitizeWindowsFileName applies the Windows file name rules of SanitizeFileName
+func sanitizeWindowsFileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || strings.ContainsRune(`<>:"/\|?*`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	name = strings.TrimRight(name, ". ")
+	if name == "" {
+		return "_"
+	}
+	base, extension, _ := strings.Cut(name, ".")
+	if windowsReservedNames[strings.ToUpper(strings.TrimRight(base, " "))] {
+		name = base + "_"
+		if extension != "" {
+			name += "." + extension
+		}
+	}
+	return name
+}
+
+// SanitizePath applies SanitizeFileName to every component of a slash-separated relative path,
+// such as the path of a binary inside an archive, and returns it with the platform's separator
+func SanitizePath(name string) string {
+	components := strings.Split(filepath.ToSlash(name), "/")
+	for i, component := range components {
+		if component != "" && component != "." && component != ".." {
+			components[i] = SanitizeFileName(component)
+		}
+	}
+	return filepath.FromSlash(strings.Join(components, "/"))
+}
+
+// entryPath resolves an archive entry inside the target directory. Names that are not valid UTF-8
+// are decoded with the archive format's legacy encoding, each component is sanitized for the
+// platform, and the entry is rooted before joining so ".." components cannot escape the target.
+func entryPath(target, name string, encoding *charmap.Charmap) string {
+	if !utf8.ValidString(name) {
+		if decoded, err := encoding.NewDecoder().String(name); err == nil {
+			name = decoded
+		} else {
+			name = strings.ToValidUTF8(name, "_")
+		}
+	}
+	rooted := filepath.Clean(string(filepath.Separator) + SanitizePath(name))
+	return filepath.Join(target, rooted)
+}
@@ -0,0 +1,77 @@
+package fileUtils
+
+import (
+	"bytes"
+	"debug/elf"
+	"fmt"
+	"io"
+	"os"
+)
+
+// appImageMagic identifies AppImage type 1 and 2 runtimes, stored in the ELF padding at offset 8
+var appImageMagic = [][]byte{{'A', 'I', 0x01}, {'A', 'I', 0x02}}
+
+// appImageUpdateSections hold the embedded update information used by AppImageUpdate and
+// self-updating AppImages, plus the signature that covers it
+var appImageUpdateSections = []string{".upd_info", ".sha256_sig", ".sig_key"}
+
+// IsAppImageFile reports whether the file at path is an AppImage
+func IsAppImageFile(path string) bool {
+	file, err := fsys().Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	header := make([]byte, 11)
+	if _, err := io.ReadFull(file, header); err != nil {
+		return false
+	}
+	if !bytes.Equal(header[0:4], []byte(elf.ELFMAG)) {
+		return false
+	}
+	for _, magic := range appImageMagic {
+		if bytes.Equal(header[8:11], magic) {
+			return true
+		}
+	}
+	return false
+}
+
+// StripAppImageUpdateInfo zeroes the embedded update information of an AppImage so it no longer
+// offers to update itself outside of the versioned installation. The embedded signature covers
+// the update information and is cleared as well. Files without these sections are left unchanged.
+func StripAppImageUpdateInfo(path string) error {
+	file, err := fsys().Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to read AppImage %s: %v", path, err)
+	}
+	elfFile, err := elf.NewFile(file)
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to read AppImage %s: %v", path, err)
+	}
+	type span struct{ offset, size int64 }
+	var spans []span
+	for _, name := range appImageUpdateSections {
+		if section := elfFile.Section(name); section != nil && section.Type != elf.SHT_NOBITS && section.Size > 0 {
+			spans = append(spans, span{int64(section.Offset), int64(section.Size)})
+		}
+	}
+	file.Close()
+	if len(spans) == 0 {
+		return nil
+	}
+
+	file, err = fsys().OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open AppImage %s: %v", path, err)
+	}
+	defer file.Close()
+	for _, s := range spans {
+		if _, err := file.WriteAt(make([]byte, s.size), s.offset); err != nil {
+			return fmt.Errorf("failed to strip update information from %s: %v", path, err)
+		}
+	}
+	return file.Close()
+}
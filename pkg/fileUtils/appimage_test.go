@@ -0,0 +1,114 @@
+package fileUtils
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTestAppImage writes a minimal type 2 AppImage ELF with a .upd_info section
+func writeTestAppImage(t *testing.T, path, updateInfo string) {
+	t.Helper()
+	shstrtab := []byte("\x00.shstrtab\x00.upd_info\x00")
+	updInfo := make([]byte, 64)
+	copy(updInfo, updateInfo)
+
+	const headerSize = 64
+	updOffset := uint64(headerSize + len(shstrtab))
+	sectionOffset := updOffset + uint64(len(updInfo))
+
+	header := elf.Header64{
+		Type:      uint16(elf.ET_EXEC),
+		Machine:   uint16(elf.EM_X86_64),
+		Version:   uint32(elf.EV_CURRENT),
+		Shoff:     sectionOffset,
+		Ehsize:    headerSize,
+		Phentsize: 56,
+		Shentsize: 64,
+		Shnum:     3,
+		Shstrndx:  1,
+	}
+	copy(header.Ident[:], elf.ELFMAG)
+	header.Ident[elf.EI_CLASS] = byte(elf.ELFCLASS64)
+	header.Ident[elf.EI_DATA] = byte(elf.ELFDATA2LSB)
+	header.Ident[elf.EI_VERSION] = byte(elf.EV_CURRENT)
+	copy(header.Ident[8:], "AI\x02")
+
+	sections := []elf.Section64{
+		{},
+		{Name: 1, Type: uint32(elf.SHT_STRTAB), Off: headerSize, Size: uint64(len(shstrtab)), Addralign: 1},
+		{Name: 11, Type: uint32(elf.SHT_PROGBITS), Off: updOffset, Size: uint64(len(updInfo)), Addralign: 1},
+	}
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, header)
+	buf.Write(shstrtab)
+	buf.Write(updInfo)
+	binary.Write(&buf, binary.LittleEndian, sections)
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatalf("Failed to write AppImage: %v", err)
+	}
+}
+
+func TestIsAppImageFile(t *testing.T) {
+	tempDir := t.TempDir()
+	appImage := filepath.Join(tempDir, "app.AppImage")
+	writeTestAppImage(t, appImage, "")
+	script := filepath.Join(tempDir, "script")
+	os.WriteFile(script, []byte("#!/bin/sh\n"), 0755)
+
+	if !IsAppImageFile(appImage) {
+		t.Error("Expected AppImage to be detected")
+	}
+	if IsAppImageFile(script) {
+		t.Error("Expected script not to be detected as AppImage")
+	}
+	if IsAppImageFile(filepath.Join(tempDir, "missing")) {
+		t.Error("Expected missing file not to be detected as AppImage")
+	}
+}
+
+func TestInstallBinary_AppImage(t *testing.T) {
+	updateInfo := "gh-releases-zsync|owner|app|latest|app-*x86_64.AppImage.zsync"
+
+	for _, strip := range []bool{false, true} {
+		tempDir := t.TempDir()
+		sourcePath := filepath.Join(tempDir, "binary-1.0.0.tar.gz")
+		writeTestAppImage(t, sourcePath, updateInfo)
+
+		// The AppImage is detected from its content despite the archive-like staging name
+		config := FileConfig{
+			BaseBinaryDirectory:     tempDir,
+			VersionedDirectoryName:  "versions",
+			BinaryName:              "app",
+			SourceArchivePath:       sourcePath,
+			StripAppImageUpdateInfo: strip,
+		}
+		if err := InstallBinary(config, "1.0.0"); err != nil {
+			t.Fatalf("InstallBinary() error = %v", err)
+		}
+
+		installedPath := GetVersionedBinaryPath(config, "1.0.0")
+		info, err := os.Stat(installedPath)
+		if err != nil {
+			t.Fatalf("Expected installed AppImage: %v", err)
+		}
+		if info.Mode().Perm()&0100 == 0 {
+			t.Errorf("Expected AppImage to be executable, mode %v", info.Mode())
+		}
+
+		elfFile, err := elf.Open(installedPath)
+		if err != nil {
+			t.Fatalf("Installed AppImage is not a valid ELF file: %v", err)
+		}
+		data, _ := elfFile.Section(".upd_info").Data()
+		elfFile.Close()
+		hasUpdateInfo := bytes.Contains(data, []byte(updateInfo))
+		if hasUpdateInfo == strip {
+			t.Errorf("strip=%v: update information present = %v", strip, hasUpdateInfo)
+		}
+	}
+}
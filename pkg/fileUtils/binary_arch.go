@@ -0,0 +1,176 @@
+package fileUtils
+
+import (
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"fmt"
+	"runtime"
+	"slices"
+)
+
+// Architecture check modes for FileConfig.ArchitectureCheck
+const (
+	ArchitectureCheckWarn = "warn" // Print a warning if the binary was built for another platform (default)
+	ArchitectureCheckFail = "fail" // Fail the installation before the symlink is updated
+	ArchitectureCheckOff  = "off"  // Skip the check, e.g. when installing binaries for another machine
+)
+
+// ErrArchitectureMismatch is returned (wrapped in an ArchitectureMismatchError) when the installed
+// binary was built for another platform than the host
+var ErrArchitectureMismatch = NewError(ErrorCodeNoMatch, "binary architecture mismatch")
+
+// ArchitectureMismatchError describes a binary built for another platform than the host
+type ArchitectureMismatchError struct {
+	Path   string         // Installed binary
+	Binary BinaryPlatform // Platform read from the binary's header
+	OS     string         // Host operating system
+	Arch   string         // Host architecture
+}
+
+func (e *ArchitectureMismatchError) Error() string {
+	return fmt.Sprintf("%s is a %s binary for %s, but this host is %s/%s", e.Path, e.Binary.Format,
+		e.Binary.Arch, e.OS, e.Arch)
+}
+
+// Is makes errors.Is(err, ErrArchitectureMismatch) match
+func (e *ArchitectureMismatchError) Is(target error) bool {
+	return target == ErrArchitectureMismatch
+}
+
+// ErrorCode returns ErrorCodeNoMatch
+func (e *ArchitectureMismatchError) ErrorCode() ErrorCode {
+	return ErrorCodeNoMatch
+}
+
+// BinaryPlatform is the platform an executable was built for, read from its header
+type BinaryPlatform struct {
+	Format string // "ELF", "Mach-O" or "PE"
+	Arch   string // Go architecture name, or the header's machine name if Go has none
+}
+
+// executableFormatOS lists the operating systems running each executable format
+var executableFormatOS = map[string][]string{
+	"Mach-O": {"darwin", "ios"},
+	"PE":     {"windows"},
+}
+
+// emulatedArchitectures lists architectures hosts run through emulation or compatibility modes,
+// keyed by "os/arch" of the host
+var emulatedArchitectures = map[string][]string{
+	"darwin/arm64":  {"amd64"},        // Rosetta 2
+	"windows/arm64": {"amd64", "386"}, // x64 and x86 emulation
+	"windows/amd64": {"386"},          // WOW64
+	"linux/amd64":   {"386"},          // IA-32 emulation
+}
+
+// elfMachineArch maps ELF machine types to Go architecture names. MIPS and PowerPC names also
+// depend on the class and byte order and are derived in elfArch.
+var elfMachineArch = map[elf.Machine]string{
+	elf.EM_386:       "386",
+	elf.EM_X86_64:    "amd64",
+	elf.EM_ARM:       "arm",
+	elf.EM_AARCH64:   "arm64",
+	elf.EM_RISCV:     "riscv64",
+	elf.EM_S390:      "s390x",
+	elf.EM_LOONGARCH: "loong64",
+	elf.EM_SPARCV9:   "sparc64",
+}
+
+// peMachineArch maps PE machine types to Go architecture names
+var peMachineArch = map[uint16]string{
+	pe.IMAGE_FILE_MACHINE_I386:  "386",
+	pe.IMAGE_FILE_MACHINE_AMD64: "amd64",
+	pe.IMAGE_FILE_MACHINE_ARMNT: "arm",
+	pe.IMAGE_FILE_MACHINE_ARM64: "arm64",
+}
+
+// ReadBinaryPlatform reads the executable format and architecture from an ELF, Mach-O or PE
+// header. The boolean is false for other files, such as scripts and universal binaries.
+func ReadBinaryPlatform(path string) (BinaryPlatform, bool, error) {
+	file, err := fsys().Open(path)
+	if err != nil {
+		return BinaryPlatform{}, false, fmt.Errorf("failed to read binary %s: %v", path, err)
+	}
+	defer file.Close()
+
+	if f, err := elf.NewFile(file); err == nil {
+		return BinaryPlatform{Format: "ELF", Arch: elfArch(f)}, true, nil
+	}
+	if f, err := macho.NewFile(file); err == nil {
+		arch, ok := machoCpuArch[f.Cpu]
+		if !ok {
+			arch = f.Cpu.String()
+		}
+		return BinaryPlatform{Format: "Mach-O", Arch: arch}, true, nil
+	}
+	if f, err := pe.NewFile(file); err == nil {
+		arch, ok := peMachineArch[f.Machine]
+		if !ok {
+			arch = fmt.Sprintf("machine 0x%x", f.Machine)
+		}
+		return BinaryPlatform{Format: "PE", Arch: arch}, true, nil
+	}
+	return BinaryPlatform{}, false, nil
+}
+
+// elfArch returns the Go architecture name of an ELF file
+func elfArch(f *elf.File) string {
+	little := f.Data == elf.ELFDATA2LSB
+	is64 := f.Class == elf.ELFCLASS64
+	switch f.Machine {
+	case elf.EM_MIPS:
+		arch := "mips"
+		if is64 {
+			arch = "mips64"
+		}
+		if little {
+			arch += "le"
+		}
+		return arch
+	case elf.EM_PPC64:
+		if little {
+			return "ppc64le"
+		}
+		return "ppc64"
+	}
+	if arch, ok := elfMachineArch[f.Machine]; ok {
+		return arch
+	}
+	return f.Machine.String()
+}
+
+// Matches reports whether a host can run the binary natively or through emulation
+func (p BinaryPlatform) Matches(goos, goarch string) bool {
+	if systems, ok := executableFormatOS[p.Format]; ok && !slices.Contains(systems, goos) {
+		return false
+	}
+	if p.Format == "ELF" && (goos == "darwin" || goos == "windows") {
+		return false
+	}
+	return p.Arch == goarch || slices.Contains(emulatedArchitectures[goos+"/"+goarch], p.Arch)
+}
+
+// verifyBinaryArchitecture checks that the installed binary was built for the host, printing a
+// warning or failing depending on config.ArchitectureCheck
+func verifyBinaryArchitecture(config FileConfig, path string) error {
+	return checkBinaryArchitecture(config, path, runtime.GOOS, runtime.GOARCH)
+}
+
+// checkBinaryArchitecture checks that the binary at path runs on goos/goarch
+func checkBinaryArchitecture(config FileConfig, path, goos, goarch string) error {
+	if config.ArchitectureCheck == ArchitectureCheckOff {
+		return nil
+	}
+	platform, ok, err := ReadBinaryPlatform(path)
+	if err != nil || !ok || platform.Matches(goos, goarch) {
+		return err
+	}
+
+	mismatch := &ArchitectureMismatchError{Path: path, Binary: platform, OS: goos, Arch: goarch}
+	if config.ArchitectureCheck == ArchitectureCheckFail {
+		return mismatch
+	}
+	fmt.Printf("Warning: %v\n", mismatch)
+	return nil
+}
@@ -0,0 +1,140 @@
+package fileUtils
+
+import (
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// writeELFHeader writes a minimal 64-bit little-endian ELF executable header
+func writeELFHeader(t *testing.T, path string, machine elf.Machine) {
+	t.Helper()
+	header := make([]byte, 64)
+	copy(header, elf.ELFMAG)
+	header[elf.EI_CLASS] = byte(elf.ELFCLASS64)
+	header[elf.EI_DATA] = byte(elf.ELFDATA2LSB)
+	header[elf.EI_VERSION] = byte(elf.EV_CURRENT)
+	binary.LittleEndian.PutUint16(header[16:], uint16(elf.ET_EXEC))
+	binary.LittleEndian.PutUint16(header[18:], uint16(machine))
+	binary.LittleEndian.PutUint32(header[20:], uint32(elf.EV_CURRENT))
+	binary.LittleEndian.PutUint16(header[52:], 64) // e_ehsize
+	if err := os.WriteFile(path, header, 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writeMachOHeader writes a minimal 64-bit Mach-O executable header
+func writeMachOHeader(t *testing.T, path string, cpu macho.Cpu) {
+	t.Helper()
+	header := make([]byte, 32)
+	binary.LittleEndian.PutUint32(header[0:], macho.Magic64)
+	binary.LittleEndian.PutUint32(header[4:], uint32(cpu))
+	binary.LittleEndian.PutUint32(header[12:], uint32(macho.TypeExec))
+	if err := os.WriteFile(path, header, 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// writePEHeader writes a minimal PE executable with a DOS stub and COFF file header
+func writePEHeader(t *testing.T, path string, machine uint16) {
+	t.Helper()
+	header := make([]byte, 0x80+4+20)
+	copy(header, "MZ")
+	binary.LittleEndian.PutUint32(header[0x3c:], 0x80)
+	copy(header[0x80:], "PE\x00\x00")
+	binary.LittleEndian.PutUint16(header[0x84:], machine)
+	if err := os.WriteFile(path, header, 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadBinaryPlatform(t *testing.T) {
+	dir := t.TempDir()
+	elfPath := filepath.Join(dir, "elf")
+	writeELFHeader(t, elfPath, elf.EM_AARCH64)
+	machoPath := filepath.Join(dir, "macho")
+	writeMachOHeader(t, machoPath, macho.CpuAmd64)
+	pePath := filepath.Join(dir, "pe")
+	writePEHeader(t, pePath, pe.IMAGE_FILE_MACHINE_AMD64)
+	scriptPath := filepath.Join(dir, "script")
+	os.WriteFile(scriptPath, []byte("#!/bin/sh\necho hi\n"), 0755)
+
+	tests := []struct {
+		path string
+		want BinaryPlatform
+		ok   bool
+	}{
+		{elfPath, BinaryPlatform{Format: "ELF", Arch: "arm64"}, true},
+		{machoPath, BinaryPlatform{Format: "Mach-O", Arch: "amd64"}, true},
+		{pePath, BinaryPlatform{Format: "PE", Arch: "amd64"}, true},
+		{scriptPath, BinaryPlatform{}, false},
+	}
+	for _, tt := range tests {
+		platform, ok, err := ReadBinaryPlatform(tt.path)
+		if err != nil {
+			t.Fatalf("ReadBinaryPlatform(%s) failed: %v", filepath.Base(tt.path), err)
+		}
+		if ok != tt.ok || platform != tt.want {
+			t.Errorf("ReadBinaryPlatform(%s) = %+v, %v; want %+v, %v", filepath.Base(tt.path), platform, ok, tt.want, tt.ok)
+		}
+	}
+
+	// The test binary itself was built for the host
+	self, err := os.Executable()
+	if err != nil {
+		t.Skip(err)
+	}
+	if platform, ok, err := ReadBinaryPlatform(self); err != nil || !ok || !platform.Matches(runtime.GOOS, runtime.GOARCH) {
+		t.Errorf("Expected the test binary to match %s/%s, got %+v, %v, %v", runtime.GOOS, runtime.GOARCH, platform, ok, err)
+	}
+}
+
+func TestBinaryPlatform_Matches(t *testing.T) {
+	tests := []struct {
+		platform   BinaryPlatform
+		goos, arch string
+		want       bool
+	}{
+		{BinaryPlatform{"ELF", "amd64"}, "linux", "amd64", true},
+		{BinaryPlatform{"ELF", "arm64"}, "linux", "amd64", false},
+		{BinaryPlatform{"ELF", "386"}, "linux", "amd64", true},
+		{BinaryPlatform{"ELF", "amd64"}, "freebsd", "amd64", true},
+		{BinaryPlatform{"ELF", "amd64"}, "darwin", "amd64", false},
+		{BinaryPlatform{"Mach-O", "amd64"}, "darwin", "arm64", true}, // Rosetta 2
+		{BinaryPlatform{"Mach-O", "arm64"}, "darwin", "amd64", false},
+		{BinaryPlatform{"Mach-O", "arm64"}, "linux", "arm64", false},
+		{BinaryPlatform{"PE", "amd64"}, "windows", "arm64", true},
+		{BinaryPlatform{"PE", "amd64"}, "linux", "amd64", false},
+	}
+	for _, tt := range tests {
+		if got := tt.platform.Matches(tt.goos, tt.arch); got != tt.want {
+			t.Errorf("%+v.Matches(%s/%s) = %v, want %v", tt.platform, tt.goos, tt.arch, got, tt.want)
+		}
+	}
+}
+
+func TestCheckBinaryArchitecture(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tool")
+	writeELFHeader(t, path, elf.EM_AARCH64)
+
+	err := checkBinaryArchitecture(FileConfig{ArchitectureCheck: ArchitectureCheckFail}, path, "linux", "amd64")
+	if !errors.Is(err, ErrArchitectureMismatch) {
+		t.Fatalf("Expected ErrArchitectureMismatch, got %v", err)
+	}
+	var mismatch *ArchitectureMismatchError
+	if !errors.As(err, &mismatch) || mismatch.Binary.Arch != "arm64" {
+		t.Errorf("Expected the binary's architecture in the error, got %v", err)
+	}
+
+	for _, mode := range []string{"", ArchitectureCheckWarn, ArchitectureCheckOff} {
+		if err := checkBinaryArchitecture(FileConfig{ArchitectureCheck: mode}, path, "linux", "amd64"); err != nil {
+			t.Errorf("Expected mode %q not to fail, got %v", mode, err)
+		}
+	}
+}
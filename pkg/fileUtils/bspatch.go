@@ -0,0 +1,107 @@
+package fileUtils
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"path/filepath"
+)
+
+// bsdiffMagic identifies patches in the classic bsdiff 4.x format
+const bsdiffMagic = "BSDIFF40"
+
+// maxPatchedSize guards against corrupt headers requesting absurd allocations
+const maxPatchedSize = 4 << 30
+
+// ApplyBSDiffPatch rebuilds a new file from oldPath and a bsdiff 4.x patch (as produced by
+// the bsdiff tool) and writes it to newPath with executable permissions
+func ApplyBSDiffPatch(oldPath, patchPath, newPath string) error {
+	oldData, err := fsys().ReadFile(oldPath)
+	if err != nil {
+		return fmt.Errorf("failed to read file to patch: %v", err)
+	}
+	patch, err := fsys().ReadFile(patchPath)
+	if err != nil {
+		return fmt.Errorf("failed to read patch: %v", err)
+	}
+
+	newData, err := bspatch(oldData, patch)
+	if err != nil {
+		return fmt.Errorf("failed to apply patch %s: %v", filepath.Base(patchPath), err)
+	}
+
+	if err := fsys().WriteFile(newPath, newData, 0755); err != nil {
+		return fmt.Errorf("failed to write patched file: %v", err)
+	}
+	return nil
+}
+
+// bspatch applies a bsdiff 4.x patch to old and returns the new content
+func bspatch(old, patch []byte) ([]byte, error) {
+	if len(patch) < 32 || string(patch[:8]) != bsdiffMagic {
+		return nil, fmt.Errorf("not a bsdiff 4.x patch")
+	}
+
+	ctrlLen := offtin(patch[8:16])
+	diffLen := offtin(patch[16:24])
+	newSize := offtin(patch[24:32])
+	// Compare each length with the remaining patch on its own so huge values cannot overflow the sum
+	if ctrlLen < 0 || diffLen < 0 || newSize < 0 || newSize > maxPatchedSize ||
+		ctrlLen > int64(len(patch))-32 || diffLen > int64(len(patch))-32-ctrlLen {
+		return nil, fmt.Errorf("corrupt patch header")
+	}
+
+	ctrlReader := bzip2.NewReader(bytes.NewReader(patch[32 : 32+ctrlLen]))
+	diffReader := bzip2.NewReader(bytes.NewReader(patch[32+ctrlLen : 32+ctrlLen+diffLen]))
+	extraReader := bzip2.NewReader(bytes.NewReader(patch[32+ctrlLen+diffLen:]))
+
+	newData := make([]byte, newSize)
+	var oldPos, newPos int64
+	ctrl := make([]byte, 24)
+	for newPos < newSize {
+		if _, err := io.ReadFull(ctrlReader, ctrl); err != nil {
+			return nil, fmt.Errorf("corrupt control block: %v", err)
+		}
+		diffCount := offtin(ctrl[0:8])
+		extraCount := offtin(ctrl[8:16])
+		seek := offtin(ctrl[16:24])
+
+		// Add old data to the diff block
+		if diffCount < 0 || diffCount > newSize-newPos {
+			return nil, fmt.Errorf("corrupt patch: diff block exceeds output size")
+		}
+		if _, err := io.ReadFull(diffReader, newData[newPos:newPos+diffCount]); err != nil {
+			return nil, fmt.Errorf("corrupt diff block: %v", err)
+		}
+		for i := int64(0); i < diffCount; i++ {
+			if oldPos+i >= 0 && oldPos+i < int64(len(old)) {
+				newData[newPos+i] += old[oldPos+i]
+			}
+		}
+		newPos += diffCount
+		oldPos += diffCount
+
+		// Copy the extra block verbatim
+		if extraCount < 0 || extraCount > newSize-newPos {
+			return nil, fmt.Errorf("corrupt patch: extra block exceeds output size")
+		}
+		if _, err := io.ReadFull(extraReader, newData[newPos:newPos+extraCount]); err != nil {
+			return nil, fmt.Errorf("corrupt extra block: %v", err)
+		}
+		newPos += extraCount
+		oldPos += seek
+	}
+
+	return newData, nil
+}
+
+// offtin decodes bsdiff's sign-magnitude little-endian 64-bit integers
+func offtin(buf []byte) int64 {
+	value := int64(binary.LittleEndian.Uint64(buf) &^ (1 << 63))
+	if buf[7]&0x80 != 0 {
+		value = -value
+	}
+	return value
+}
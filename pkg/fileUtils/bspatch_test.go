@@ -0,0 +1,83 @@
+package fileUtils
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testBSDiffPatch turns "#!/bin/sh\necho v1\n" into "#!/bin/sh\necho v2\necho patched\n" (generated with bsdiff 4.x format)
+const testBSDiffPatch = "QlNESUZGNDAsAAAAAAAAACgAAAAAAAAAHwAAAAAAAABCWmg5MUFZJlNZPCWvXwAABeAASAoQACAAISmm0GaBfArhdyRThQkDwlr18EJaaDkxQVkmU1lg6hk6AAAAwABgBCAAMMwJNModhdyRThQkGDqGToBCWmg5MUFZJlNZiWY2BQAAA1GAABBAAC5AxAAgADEAMCADal8hNgNCPF3JFOFCQiWY2BQ="
+
+func TestApplyBSDiffPatch(t *testing.T) {
+	tempDir := t.TempDir()
+	oldPath := filepath.Join(tempDir, "old")
+	patchPath := filepath.Join(tempDir, "update.bspatch")
+	newPath := filepath.Join(tempDir, "new")
+
+	patch, _ := base64.StdEncoding.DecodeString(testBSDiffPatch)
+	os.WriteFile(oldPath, []byte("#!/bin/sh\necho v1\n"), 0755)
+	os.WriteFile(patchPath, patch, 0644)
+
+	if err := ApplyBSDiffPatch(oldPath, patchPath, newPath); err != nil {
+		t.Fatalf("ApplyBSDiffPatch failed: %v", err)
+	}
+
+	content, err := os.ReadFile(newPath)
+	if err != nil {
+		t.Fatalf("Failed to read patched file: %v", err)
+	}
+	if string(content) != "#!/bin/sh\necho v2\necho patched\n" {
+		t.Errorf("Unexpected patched content: %q", content)
+	}
+}
+
+func TestApplyBSDiffPatch_Invalid(t *testing.T) {
+	tempDir := t.TempDir()
+	oldPath := filepath.Join(tempDir, "old")
+	os.WriteFile(oldPath, []byte("old"), 0755)
+
+	patch, _ := base64.StdEncoding.DecodeString(testBSDiffPatch)
+	// Control and diff lengths whose sum overflows int64
+	oversized := append([]byte(nil), patch...)
+	binary.LittleEndian.PutUint64(oversized[8:16], 1<<62)
+	binary.LittleEndian.PutUint64(oversized[16:24], 1<<62)
+	testCases := map[string][]byte{
+		"not a patch":      []byte("definitely not a bsdiff patch, just text"),
+		"truncated":        patch[:40],
+		"oversized header": oversized,
+	}
+
+	for name, content := range testCases {
+		t.Run(name, func(t *testing.T) {
+			patchPath := filepath.Join(tempDir, "bad.bspatch")
+			os.WriteFile(patchPath, content, 0644)
+			if err := ApplyBSDiffPatch(oldPath, patchPath, filepath.Join(tempDir, "new")); err == nil {
+				t.Error("Expected error for invalid patch")
+			}
+		})
+	}
+}
+
+func TestCurrentInstalledVersion(t *testing.T) {
+	tempDir := t.TempDir()
+	config := FileConfig{BaseBinaryDirectory: tempDir, VersionedDirectoryName: "versions", BinaryName: "myapp"}
+
+	if _, err := CurrentInstalledVersion(config); err == nil {
+		t.Error("Expected error without an installation")
+	}
+
+	versionDir := GetVersionedDirectoryPath(config, "v1.0.0")
+	os.MkdirAll(versionDir, 0755)
+	os.WriteFile(filepath.Join(versionDir, "myapp"), []byte("binary"), 0755)
+	if err := UpdateSymlink(GetSymlinkTargetPath(config, "v1.0.0"), filepath.Join(tempDir, "myapp")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	version, err := CurrentInstalledVersion(config)
+	if err != nil || version != "v1.0.0" {
+		t.Errorf("Expected v1.0.0, got %q (err: %v)", version, err)
+	}
+}
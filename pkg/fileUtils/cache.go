@@ -0,0 +1,224 @@
+package fileUtils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CacheConfig configures the optional on-disk download cache shared across installs
+type CacheConfig struct {
+	Enabled   bool          `json:"enabled"`   // Enable reuse of previously downloaded assets
+	Directory string        `json:"directory"` // Cache directory (default: DefaultCacheDirectory())
+	MaxSize   int64         `json:"max_size"`  // Maximum total cache size in bytes (0 = unlimited)
+	TTL       time.Duration `json:"ttl"`       // Evict entries not used within this duration (0 = never expire)
+}
+
+// DownloadCache stores downloaded assets keyed by URL and expected checksum
+type DownloadCache struct {
+	config CacheConfig
+}
+
+// DefaultCacheDirectory returns the platform cache directory for downloads (e.g. ~/.cache/go-binary-updater)
+func DefaultCacheDirectory() string {
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "go-binary-updater")
+	}
+	return filepath.Join(os.TempDir(), "go-binary-updater-cache")
+}
+
+// NewDownloadCache creates a download cache, creating the cache directory if needed
+func NewDownloadCache(config CacheConfig) (*DownloadCache, error) {
+	if config.Directory == "" {
+		config.Directory = DefaultCacheDirectory()
+	}
+	if err := fsys().MkdirAll(config.Directory, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %v", err)
+	}
+	return &DownloadCache{config: config}, nil
+}
+
+// Directory returns the directory the cache stores entries in
+func (c *DownloadCache) Directory() string {
+	return c.config.Directory
+}
+
+// entryPath returns the cache file path for a URL and optional checksum
+func (c *DownloadCache) entryPath(url, checksum string) string {
+	sum := sha256.Sum256([]byte(url + "\n" + strings.ToLower(checksum)))
+	return filepath.Join(c.config.Directory, hex.EncodeToString(sum[:]))
+}
+
+// Get copies a cached asset to destination. It returns false when there is no usable entry.
+// Entries that have expired or no longer match the expected checksum are removed.
+func (c *DownloadCache) Get(url, checksum, destination string) (bool, error) {
+	entry := c.entryPath(url, checksum)
+	info, err := fsys().Stat(entry)
+	if err != nil {
+		return false, nil
+	}
+
+	if c.config.TTL > 0 && time.Since(info.ModTime()) > c.config.TTL {
+		fsys().Remove(entry)
+		return false, nil
+	}
+
+	if checksum != "" {
+		actual, err := FileSHA256(entry)
+		if err != nil || !strings.EqualFold(actual, checksum) {
+			fsys().Remove(entry)
+			return false, nil
+		}
+	}
+
+	if err := copyFile(entry, destination); err != nil {
+		return false, fmt.Errorf("failed to copy cached asset: %v", err)
+	}
+
+	// Refresh the modification time so TTL and size-based eviction track last use
+	now := time.Now()
+	fsys().Chtimes(entry, now, now)
+	return true, nil
+}
+
+// Put stores the file at source in the cache and evicts entries exceeding TTL or MaxSize
+func (c *DownloadCache) Put(url, checksum, source string) error {
+	if checksum != "" {
+		actual, err := FileSHA256(source)
+		if err != nil {
+			return fmt.Errorf("failed to checksum downloaded asset: %v", err)
+		}
+		if !strings.EqualFold(actual, checksum) {
+			return &ChecksumError{Name: url, Expected: checksum, Actual: actual}
+		}
+	}
+	return c.store(url, checksum, source)
+}
+
+// store adds the file at source to the cache without verifying it against the checksum and evicts
+// entries exceeding TTL or MaxSize
+func (c *DownloadCache) store(url, checksum, source string) error {
+	entry := c.entryPath(url, checksum)
+	tempEntry := entry + ".tmp"
+	if err := copyFile(source, tempEntry); err != nil {
+		fsys().Remove(tempEntry)
+		return fmt.Errorf("failed to write cache entry: %v", err)
+	}
+	if err := fsys().Rename(tempEntry, entry); err != nil {
+		fsys().Remove(tempEntry)
+		return fmt.Errorf("failed to commit cache entry: %v", err)
+	}
+
+	return c.Evict()
+}
+
+// Evict removes expired entries and, if MaxSize is set, the least recently used entries
+// until the cache fits within the limit
+func (c *DownloadCache) Evict() error {
+	dirEntries, err := fsys().ReadDir(c.config.Directory)
+	if err != nil {
+		return fmt.Errorf("failed to read cache directory: %v", err)
+	}
+
+	type cacheEntry struct {
+		path     string
+		size     int64
+		lastUsed time.Time
+	}
+
+	var entries []cacheEntry
+	var totalSize int64
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() || strings.HasSuffix(dirEntry.Name(), ".tmp") {
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			continue
+		}
+		path := filepath.Join(c.config.Directory, dirEntry.Name())
+		if c.config.TTL > 0 && time.Since(info.ModTime()) > c.config.TTL {
+			fsys().Remove(path)
+			continue
+		}
+		entries = append(entries, cacheEntry{path: path, size: info.Size(), lastUsed: info.ModTime()})
+		totalSize += info.Size()
+	}
+
+	if c.config.MaxSize <= 0 || totalSize <= c.config.MaxSize {
+		return nil
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].lastUsed.Before(entries[j].lastUsed)
+	})
+	for _, entry := range entries {
+		if totalSize <= c.config.MaxSize {
+			break
+		}
+		if err := fsys().Remove(entry.path); err == nil {
+			totalSize -= entry.size
+		}
+	}
+	return nil
+}
+
+// DownloadFileWithCache downloads a file, reusing a cached copy when the cache is enabled.
+// checksum is optional; when provided it becomes part of the cache key and is verified.
+func DownloadFileWithCache(cacheConfig CacheConfig, link, destination, token, checksum string) error {
+	return DownloadFileWithConfig(FileConfig{Cache: cacheConfig}, link, destination, token, checksum)
+}
+
+// DownloadFileWithConfig downloads a file using the cache and chunked download settings of the
+// FileConfig. checksum is optional; when provided it becomes part of the cache key and the file is
+// verified against it, hashing it once, and removed if it does not match.
+func DownloadFileWithConfig(config FileConfig, link, destination, token, checksum string) error {
+	download := func() error {
+		var err error
+		if config.ChunkedDownload.Enabled {
+			err = downloadFileChunked(config.Context(), config.ChunkedDownload, link, destination, token)
+		} else {
+			err = DownloadFileWithContext(config.Context(), link, destination, token)
+		}
+		if err != nil || checksum == "" {
+			return err
+		}
+		if err := VerifyFileSHA256(destination, checksum); err != nil {
+			fsys().Remove(destination)
+			return err
+		}
+		return nil
+	}
+
+	if !config.Cache.Enabled {
+		return download()
+	}
+
+	cache, err := NewDownloadCache(config.Cache)
+	if err != nil {
+		return err
+	}
+
+	if hit, err := cache.Get(link, checksum, destination); err != nil {
+		return err
+	} else if hit {
+		fmt.Printf("Using cached download for %s\n", link)
+		return nil
+	}
+
+	if err := download(); err != nil {
+		return err
+	}
+
+	// The download was verified above, so the cache does not hash it again. The cache is only an
+	// optimization, so failing to fill it does not fail the download.
+	if err := cache.store(link, checksum, destination); err != nil {
+		fmt.Printf("Warning: failed to cache download: %v\n", err)
+	}
+	return nil
+}
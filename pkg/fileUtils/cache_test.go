@@ -0,0 +1,154 @@
+package fileUtils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDownloadFileWithCache_ReusesDownload(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("asset content"))
+	}))
+	defer server.Close()
+
+	cacheConfig := CacheConfig{Enabled: true, Directory: t.TempDir()}
+	for i := 0; i < 3; i++ {
+		dest := filepath.Join(t.TempDir(), "asset")
+		if err := DownloadFileWithCache(cacheConfig, server.URL+"/asset", dest, "", ""); err != nil {
+			t.Fatalf("DownloadFileWithCache failed: %v", err)
+		}
+		content, err := os.ReadFile(dest)
+		if err != nil || string(content) != "asset content" {
+			t.Fatalf("Unexpected content %q (err: %v)", content, err)
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected 1 network request, got %d", requests)
+	}
+}
+
+func TestDownloadFileWithCache_Disabled(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("asset content"))
+	}))
+	defer server.Close()
+
+	for i := 0; i < 2; i++ {
+		dest := filepath.Join(t.TempDir(), "asset")
+		if err := DownloadFileWithCache(CacheConfig{}, server.URL, dest, "", ""); err != nil {
+			t.Fatalf("DownloadFileWithCache failed: %v", err)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 network requests with cache disabled, got %d", requests)
+	}
+}
+
+func TestDownloadCache_ChecksumKeyAndVerification(t *testing.T) {
+	cache, err := NewDownloadCache(CacheConfig{Enabled: true, Directory: t.TempDir()})
+	if err != nil {
+		t.Fatalf("NewDownloadCache failed: %v", err)
+	}
+
+	source := filepath.Join(t.TempDir(), "asset")
+	os.WriteFile(source, []byte("payload"), 0644)
+	sum := sha256.Sum256([]byte("payload"))
+	checksum := hex.EncodeToString(sum[:])
+
+	if err := cache.Put("https://example.com/a", "deadbeef", source); err == nil {
+		t.Error("Expected checksum mismatch error")
+	}
+	if err := cache.Put("https://example.com/a", checksum, source); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	dest := filepath.Join(t.TempDir(), "out")
+	if hit, _ := cache.Get("https://example.com/a", "", dest); hit {
+		t.Error("Expected miss when checksum differs from the cache key")
+	}
+	if hit, err := cache.Get("https://example.com/a", checksum, dest); !hit || err != nil {
+		t.Errorf("Expected cache hit, got hit=%v err=%v", hit, err)
+	}
+}
+
+func TestDownloadCache_Eviction(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewDownloadCache(CacheConfig{Enabled: true, Directory: dir, MaxSize: 10})
+	if err != nil {
+		t.Fatalf("NewDownloadCache failed: %v", err)
+	}
+
+	source := filepath.Join(t.TempDir(), "asset")
+	os.WriteFile(source, []byte("123456"), 0644)
+
+	if err := cache.Put("https://example.com/old", "", source); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes(cache.entryPath("https://example.com/old", ""), old, old)
+
+	if err := cache.Put("https://example.com/new", "", source); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	if FileExists(cache.entryPath("https://example.com/old", "")) {
+		t.Error("Expected least recently used entry to be evicted")
+	}
+	if !FileExists(cache.entryPath("https://example.com/new", "")) {
+		t.Error("Expected newest entry to remain cached")
+	}
+
+	// TTL eviction
+	ttlCache, _ := NewDownloadCache(CacheConfig{Enabled: true, Directory: dir, TTL: time.Minute})
+	os.Chtimes(ttlCache.entryPath("https://example.com/new", ""), old, old)
+	if hit, _ := ttlCache.Get("https://example.com/new", "", filepath.Join(t.TempDir(), "out")); hit {
+		t.Error("Expected expired entry to be treated as a miss")
+	}
+}
+
+func TestDownloadFileWithCache_DisabledVerifiesChecksum(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("asset content"))
+	}))
+	defer server.Close()
+
+	dest := filepath.Join(t.TempDir(), "asset")
+	wrong := hex.EncodeToString(make([]byte, sha256.Size))
+	err := DownloadFileWithCache(CacheConfig{}, server.URL, dest, "", wrong)
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("Expected a checksum mismatch, got %v", err)
+	}
+	if _, statErr := os.Stat(dest); !os.IsNotExist(statErr) {
+		t.Errorf("Expected mismatching download to be removed, stat error: %v", statErr)
+	}
+}
+
+func TestDownloadFileWithCache_CacheWriteFailure(t *testing.T) {
+	cacheDir := filepath.Join(t.TempDir(), "cache")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Remove the cache directory during the download so storing the entry fails
+		os.RemoveAll(cacheDir)
+		w.Write([]byte("asset content"))
+	}))
+	defer server.Close()
+
+	dest := filepath.Join(t.TempDir(), "asset")
+	if err := DownloadFileWithCache(CacheConfig{Enabled: true, Directory: cacheDir}, server.URL, dest, "", ""); err != nil {
+		t.Fatalf("Expected a failed cache write not to fail the download, got %v", err)
+	}
+	if content, _ := os.ReadFile(dest); string(content) != "asset content" {
+		t.Errorf("Expected the downloaded content, got %q", content)
+	}
+}
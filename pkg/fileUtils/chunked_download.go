@@ -0,0 +1,220 @@
+package fileUtils
+
+import (
+	"context"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/filesystem"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultChunkSize is the size of each range request when ChunkedDownloadConfig.ChunkSize is unset
+	DefaultChunkSize = 16 << 20
+	// DefaultChunkConcurrency is the number of parallel range requests when ChunkedDownloadConfig.Concurrency is unset
+	DefaultChunkConcurrency = 4
+	// chunkAttempts is the number of times a failed chunk is requested before the download fails
+	chunkAttempts = 3
+)
+
+// ChunkedDownloadConfig configures parallel range-request downloads for large assets.
+// Servers that do not support range requests, and assets smaller than MinSize, are downloaded in one request.
+type ChunkedDownloadConfig struct {
+	Enabled     bool  `json:"enabled"`     // Download large assets with parallel range requests
+	ChunkSize   int64 `json:"chunk_size"`  // Bytes per range request (default: DefaultChunkSize)
+	Concurrency int   `json:"concurrency"` // Parallel range requests (default: DefaultChunkConcurrency)
+	MinSize     int64 `json:"min_size"`    // Smallest asset to split into chunks (default: two chunks)
+}
+
+// withDefaults fills unset values with their defaults
+func (c ChunkedDownloadConfig) withDefaults() ChunkedDownloadConfig {
+	if c.ChunkSize <= 0 {
+		c.ChunkSize = DefaultChunkSize
+	}
+	if c.Concurrency <= 0 {
+		c.Concurrency = DefaultChunkConcurrency
+	}
+	if c.MinSize <= 0 {
+		c.MinSize = 2 * c.ChunkSize
+	}
+	return c
+}
+
+// DownloadFileChunked downloads a file with parallel range requests and reassembles it at destination.
+// It falls back to a single request when the server does not support ranges or the file is small.
+func DownloadFileChunked(config ChunkedDownloadConfig, link, destination, token string) error {
+	return downloadFileChunked(context.Background(), config, link, destination, token)
+}
+
+// downloadFileChunked implements DownloadFileChunked, stopping the download when ctx is done
+func downloadFileChunked(ctx context.Context, config ChunkedDownloadConfig, link, destination, token string) error {
+	config = config.withDefaults()
+
+	size, err := probeRangeSupport(ctx, link, token)
+	if err != nil || size < config.MinSize {
+		return DownloadFileWithContext(ctx, link, destination, token)
+	}
+
+	if err := fsys().MkdirAll(filepath.Dir(destination), 0755); err != nil {
+		return fmt.Errorf("failed to create download directory: %w", err)
+	}
+	if err := CheckDiskSpace(filepath.Dir(destination), size); err != nil {
+		return err
+	}
+
+	out, err := fsys().Create(destination)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	if err := out.Truncate(size); err != nil {
+		out.Close()
+		fsys().Remove(destination)
+		return fmt.Errorf("failed to allocate file: %w", err)
+	}
+
+	start := time.Now()
+	err = downloadChunks(ctx, config, link, token, out, size)
+	if err != nil {
+		ObserveDownload("chunked", start, 0, err)
+	} else {
+		ObserveDownload("chunked", start, size, nil)
+	}
+	if closeErr := out.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to write file: %w", closeErr)
+	}
+	if err != nil {
+		fsys().Remove(destination)
+		return err
+	}
+	return nil
+}
+
+// downloadChunks fetches all ranges of the file with a bounded number of workers
+func downloadChunks(ctx context.Context, config ChunkedDownloadConfig, link, token string, out filesystem.File, size int64) error {
+	offsets := make(chan int64)
+	errs := make(chan error, config.Concurrency)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+
+	for i := 0; i < config.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for start := range offsets {
+				end := min(start+config.ChunkSize, size) - 1
+				if err := downloadChunkWithRetry(ctx, link, token, out, start, end); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	var err error
+	for start := int64(0); start < size && err == nil; start += config.ChunkSize {
+		select {
+		case offsets <- start:
+		case err = <-errs:
+		}
+	}
+	close(offsets)
+	<-done
+
+	if err == nil {
+		select {
+		case err = <-errs:
+		default:
+		}
+	}
+	return err
+}
+
+// downloadChunkWithRetry downloads bytes start-end (inclusive) into out, retrying transient failures
+func downloadChunkWithRetry(ctx context.Context, link, token string, out filesystem.File, start, end int64) error {
+	var err error
+	for attempt := 0; attempt < chunkAttempts; attempt++ {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+			break
+		}
+		if attempt > 0 {
+			GetMetrics().Counter(MetricHTTPRetries, 1, hostLabels(link))
+		}
+		if err = downloadChunk(ctx, link, token, out, start, end); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("failed to download bytes %d-%d: %w", start, end, err)
+}
+
+// downloadChunk downloads bytes start-end (inclusive) into out
+func downloadChunk(ctx context.Context, link, token string, out filesystem.File, start, end int64) error {
+	req, err := newDownloadRequest(ctx, link, token)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+
+	resp, err := pinnedHTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusPartialContent {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	// Writing another range at this chunk's offset would corrupt the file
+	contentRange := resp.Header.Get("Content-Range")
+	if want := fmt.Sprintf("bytes %d-%d/", start, end); !strings.HasPrefix(contentRange, want) {
+		return fmt.Errorf("unexpected Content-Range %q for bytes %d-%d", contentRange, start, end)
+	}
+
+	expected := end - start + 1
+	written, err := io.Copy(io.NewOffsetWriter(out, start), io.LimitReader(resp.Body, expected))
+	if err != nil {
+		return err
+	}
+	if written != expected {
+		return fmt.Errorf("short chunk: got %d of %d bytes", written, expected)
+	}
+	return nil
+}
+
+// probeRangeSupport requests the first byte of the file and returns its total size
+// if the server answers with a partial content response
+func probeRangeSupport(ctx context.Context, link, token string) (int64, error) {
+	req, err := newDownloadRequest(ctx, link, token)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Range", "bytes=0-0")
+
+	resp, err := pinnedHTTPClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusPartialContent {
+		return 0, fmt.Errorf("server does not support range requests")
+	}
+
+	// Content-Range: bytes 0-0/12345
+	contentRange := resp.Header.Get("Content-Range")
+	_, total, found := strings.Cut(contentRange, "/")
+	if !found || total == "*" {
+		return 0, fmt.Errorf("unknown content length in Content-Range %q", contentRange)
+	}
+	return strconv.ParseInt(total, 10, 64)
+}
@@ -0,0 +1,120 @@
+package fileUtils
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDownloadFileChunked(t *testing.T) {
+	content := make([]byte, 1<<20+123)
+	rand.New(rand.NewSource(1)).Read(content)
+
+	var rangeRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ranged":
+			if r.Header.Get("Range") != "" {
+				rangeRequests.Add(1)
+			}
+			http.ServeContent(w, r, "asset", time.Time{}, bytes.NewReader(content))
+		case "/plain":
+			w.Write(content)
+		case "/broken":
+			if r.Header.Get("Range") == "bytes=0-0" {
+				http.ServeContent(w, r, "asset", time.Time{}, bytes.NewReader(content))
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+		case "/misranged":
+			// Answers every chunk with the first bytes of the file
+			if r.Header.Get("Range") != "bytes=0-0" {
+				r.Header.Set("Range", fmt.Sprintf("bytes=0-%d", 64<<10-1))
+			}
+			http.ServeContent(w, r, "asset", time.Time{}, bytes.NewReader(content))
+		}
+	}))
+	defer server.Close()
+
+	config := ChunkedDownloadConfig{Enabled: true, ChunkSize: 64 << 10, Concurrency: 4}
+	tempDir := t.TempDir()
+
+	t.Run("parallel ranges", func(t *testing.T) {
+		destination := filepath.Join(tempDir, "ranged", "asset")
+		if err := DownloadFileChunked(config, server.URL+"/ranged", destination, ""); err != nil {
+			t.Fatalf("DownloadFileChunked() error = %v", err)
+		}
+		downloaded, _ := os.ReadFile(destination)
+		if !bytes.Equal(downloaded, content) {
+			t.Error("Reassembled file does not match the original content")
+		}
+		// One probe plus one request per chunk
+		if got, want := rangeRequests.Load(), int32(1+(len(content)+(64<<10)-1)/(64<<10)); got != want {
+			t.Errorf("Expected %d range requests, got %d", want, got)
+		}
+	})
+
+	t.Run("falls back without range support", func(t *testing.T) {
+		destination := filepath.Join(tempDir, "plain")
+		if err := DownloadFileChunked(config, server.URL+"/plain", destination, ""); err != nil {
+			t.Fatalf("DownloadFileChunked() error = %v", err)
+		}
+		downloaded, _ := os.ReadFile(destination)
+		if !bytes.Equal(downloaded, content) {
+			t.Error("Downloaded file does not match the original content")
+		}
+	})
+
+	t.Run("falls back for small files", func(t *testing.T) {
+		rangeRequests.Store(0)
+		small := config
+		small.MinSize = int64(len(content)) + 1
+		if err := DownloadFileChunked(small, server.URL+"/ranged", filepath.Join(tempDir, "small"), ""); err != nil {
+			t.Fatalf("DownloadFileChunked() error = %v", err)
+		}
+		if rangeRequests.Load() != 1 {
+			t.Errorf("Expected only the probe to use a range request, got %d", rangeRequests.Load())
+		}
+	})
+
+	t.Run("failed chunk removes partial file", func(t *testing.T) {
+		destination := filepath.Join(tempDir, "broken")
+		if err := DownloadFileChunked(config, server.URL+"/broken", destination, ""); err == nil {
+			t.Fatal("Expected error when chunks fail")
+		}
+		if FileExists(destination) {
+			t.Error("Expected partial download to be removed")
+		}
+	})
+	t.Run("mismatched Content-Range", func(t *testing.T) {
+		destination := filepath.Join(tempDir, "misranged")
+		err := DownloadFileChunked(config, server.URL+"/misranged", destination, "")
+		if err == nil || !strings.Contains(err.Error(), "Content-Range") {
+			t.Fatalf("Expected chunks with another range to be rejected, got %v", err)
+		}
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		out, err := os.Create(filepath.Join(tempDir, "cancelled"))
+		if err != nil {
+			t.Fatalf("os.Create() error = %v", err)
+		}
+		defer out.Close()
+		err = downloadChunkWithRetry(ctx, server.URL+"/ranged", "", out, 0, 99)
+		if !errors.Is(err, context.Canceled) || strings.Contains(err.Error(), "%!w") {
+			t.Errorf("Expected a wrapped context.Canceled, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,60 @@
+package fileUtils
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// DefaultUserAgent is the User-Agent sent when no ClientInfo has been configured
+const DefaultUserAgent = "go-binary-updater/1.0"
+
+// ClientInfo identifies the application embedding the library on all outgoing requests.
+// Some providers require a descriptive User-Agent with contact details for rate-limit exemptions.
+type ClientInfo struct {
+	AppName    string `json:"app_name"`    // Name of the embedding application (e.g. "mytool")
+	AppVersion string `json:"app_version"` // Version of the embedding application
+	ContactURL string `json:"contact_url"` // URL or email operators can use to reach the maintainers
+}
+
+var (
+	clientInfoMu sync.RWMutex
+	clientInfo   ClientInfo
+)
+
+// SetClientInfo configures the client identification used for every outgoing request
+func SetClientInfo(info ClientInfo) {
+	clientInfoMu.Lock()
+	defer clientInfoMu.Unlock()
+	clientInfo = info
+}
+
+// GetClientInfo returns the currently configured client identification
+func GetClientInfo() ClientInfo {
+	clientInfoMu.RLock()
+	defer clientInfoMu.RUnlock()
+	return clientInfo
+}
+
+// UserAgent builds the User-Agent header value, e.g. "mytool/1.2.3 (+https://example.com) go-binary-updater/1.0"
+func (c ClientInfo) UserAgent() string {
+	if c.AppName == "" {
+		return DefaultUserAgent
+	}
+
+	product := c.AppName
+	if c.AppVersion != "" {
+		product = fmt.Sprintf("%s/%s", c.AppName, c.AppVersion)
+	}
+	if c.ContactURL != "" {
+		product = fmt.Sprintf("%s (+%s)", product, c.ContactURL)
+	}
+	return fmt.Sprintf("%s %s", product, DefaultUserAgent)
+}
+
+// SetUserAgent sets the configured User-Agent on a request unless one is already present
+func SetUserAgent(req *http.Request) {
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", GetClientInfo().UserAgent())
+	}
+}
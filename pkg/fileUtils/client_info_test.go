@@ -0,0 +1,48 @@
+package fileUtils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestClientInfo_UserAgent(t *testing.T) {
+	tests := []struct {
+		name string
+		info ClientInfo
+		want string
+	}{
+		{"Default", ClientInfo{}, "go-binary-updater/1.0"},
+		{"NameOnly", ClientInfo{AppName: "mytool"}, "mytool go-binary-updater/1.0"},
+		{"NameAndVersion", ClientInfo{AppName: "mytool", AppVersion: "1.2.3"}, "mytool/1.2.3 go-binary-updater/1.0"},
+		{"Full", ClientInfo{AppName: "mytool", AppVersion: "1.2.3", ContactURL: "https://example.com"}, "mytool/1.2.3 (+https://example.com) go-binary-updater/1.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.info.UserAgent(); got != tt.want {
+				t.Errorf("UserAgent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDownloadFile_SendsClientInfo(t *testing.T) {
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	SetClientInfo(ClientInfo{AppName: "mytool", AppVersion: "2.0.0"})
+	defer SetClientInfo(ClientInfo{})
+
+	if err := DownloadFile(server.URL, filepath.Join(t.TempDir(), "file")); err != nil {
+		t.Fatalf("DownloadFile failed: %v", err)
+	}
+	if userAgent != "mytool/2.0.0 go-binary-updater/1.0" {
+		t.Errorf("Unexpected User-Agent: %q", userAgent)
+	}
+}
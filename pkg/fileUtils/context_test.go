@@ -0,0 +1,52 @@
+package fileUtils
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestInstall_CanceledContext(t *testing.T) {
+	tempDir := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	config := FileConfig{
+		BaseBinaryDirectory:    filepath.Join(tempDir, "bin"),
+		VersionedDirectoryName: "versions",
+		BinaryName:             "tool",
+		SourceArchivePath:      filepath.Join(tempDir, "tool.tar.gz"),
+	}.WithContext(ctx)
+	createTestArchiveWithFiles(t, config.SourceArchivePath, map[string]string{"tool": "version 1"})
+
+	if err := InstallArchivedBinary(config, "1.0.0"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the installation to stop with context.Canceled, got %v", err)
+	}
+	if _, err := os.Stat(config.BaseBinaryDirectory); !os.IsNotExist(err) {
+		t.Errorf("Expected nothing to be installed, got %v", err)
+	}
+	if (FileConfig{}).Context() != context.Background() {
+		t.Error("Expected a configuration without context to use context.Background")
+	}
+}
+
+func TestDownloadFileWithContext_Deadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := DownloadFileWithContext(ctx, server.URL, filepath.Join(t.TempDir(), "tool"), "")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the download to stop at the deadline, got %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package fileUtils
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// createSymlink creates symlinks, replaced in tests to simulate filesystems without symlinks
+var createSymlink = func(oldname, newname string) error {
+	return fsys().Symlink(oldname, newname)
+}
+
+// linkLocalBinary creates the local symlink to symlinkTarget. If the filesystem does not support
+// symlinks (FAT/exFAT, some network mounts and containers), the binary is copied to the local
+// symlink path instead. It returns the SymlinkStatus: "created", "copy" or "failed".
+func linkLocalBinary(symlinkTarget, binaryPath, localSymlinkPath string) string {
+	err := UpdateSymlink(symlinkTarget, localSymlinkPath)
+	if err == nil {
+		return "created"
+	}
+	fmt.Printf("Warning: Failed to create symlink %s -> %s: %v\n", localSymlinkPath, symlinkTarget, err)
+
+	if err := copyBinary(binaryPath, localSymlinkPath); err != nil {
+		fmt.Printf("Warning: Failed to copy binary to %s: %v\n", localSymlinkPath, err)
+		fmt.Printf("Binary is still available at: %s\n", binaryPath)
+		return "failed"
+	}
+	fmt.Printf("Copied binary to %s because symlinks are not supported\n", localSymlinkPath)
+	return "copy"
+}
+
+// copyBinary replaces destination with a copy of the binary, keeping its mode. The copy is
+// written next to destination and renamed over it, so a running copy is never truncated.
+func copyBinary(binaryPath, destination string) error {
+	info, err := fsys().Stat(binaryPath)
+	if err != nil {
+		return err
+	}
+	tempPath := filepath.Join(filepath.Dir(destination), "."+filepath.Base(destination)+".tmp")
+	if err := copyFile(binaryPath, tempPath); err != nil {
+		fsys().Remove(tempPath)
+		return err
+	}
+	if err := fsys().Chmod(tempPath, info.Mode().Perm()); err != nil {
+		fsys().Remove(tempPath)
+		return fmt.Errorf("failed to make copy executable: %w", err)
+	}
+	if err := fsys().Rename(tempPath, destination); err != nil {
+		fsys().Remove(tempPath)
+		return fmt.Errorf("failed to replace %s: %w", destination, err)
+	}
+	return nil
+}
+
+// isLocalCopyOf reports whether path is a regular file with the same content as the binary
+func isLocalCopyOf(path, binaryPath string) bool {
+	info, err := fsys().Lstat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	binaryInfo, err := fsys().Stat(binaryPath)
+	if err != nil || binaryInfo.Size() != info.Size() {
+		return false
+	}
+	copySum, err := FileSHA256(path)
+	if err != nil {
+		return false
+	}
+	binarySum, err := FileSHA256(binaryPath)
+	return err == nil && copySum == binarySum
+}
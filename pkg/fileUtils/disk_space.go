@@ -0,0 +1,143 @@
+package fileUtils
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/filesystem"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// archiveExpansionFactor is the assumed ratio of extracted to compressed size for archives whose
+// extracted size is not known in advance. Executables typically compress to a third or a quarter.
+const archiveExpansionFactor = 4
+
+// ErrInsufficientSpace is returned (wrapped in an InsufficientSpaceError) when a download or
+// extraction would not fit on the target filesystem
+var ErrInsufficientSpace = NewError(ErrorCodeDisk, "insufficient disk space")
+
+// errDiskSpaceUnsupported is returned by availableDiskSpace on platforms without free space queries
+var errDiskSpaceUnsupported = errors.New("disk space queries are not supported on this platform")
+
+// InsufficientSpaceError describes which directory lacks space and by how much
+type InsufficientSpaceError struct {
+	Path      string // Directory that was checked
+	Required  uint64 // Bytes needed
+	Available uint64 // Bytes available to unprivileged users
+}
+
+func (e *InsufficientSpaceError) Error() string {
+	return fmt.Sprintf("insufficient disk space in %s: %d bytes required, %d bytes available", e.Path, e.Required, e.Available)
+}
+
+// Is makes errors.Is(err, ErrInsufficientSpace) match
+func (e *InsufficientSpaceError) Is(target error) bool {
+	return target == ErrInsufficientSpace
+}
+
+// ErrorCode returns ErrorCodeDisk
+func (e *InsufficientSpaceError) ErrorCode() ErrorCode {
+	return ErrorCodeDisk
+}
+
+// AvailableDiskSpace returns the free space available to unprivileged users on the filesystem
+// containing path. Missing directories are resolved to their nearest existing parent.
+func AvailableDiskSpace(path string) (uint64, error) {
+	dir, err := filepath.Abs(path)
+	if err != nil {
+		return 0, err
+	}
+	for {
+		if _, err := os.Stat(dir); err == nil {
+			break
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return 0, fmt.Errorf("no existing parent directory for %s", path)
+		}
+		dir = parent
+	}
+	return availableDiskSpace(dir)
+}
+
+// CheckDiskSpace returns an InsufficientSpaceError if fewer than required bytes are free at path.
+// The check is skipped when the size is unknown, free space cannot be determined or the library
+// does not use the operating system's file system.
+func CheckDiskSpace(path string, required int64) error {
+	if required <= 0 || !filesystem.IsOS(fsys()) {
+		return nil
+	}
+	available, err := AvailableDiskSpace(path)
+	if err != nil {
+		return nil
+	}
+	if available < uint64(required) {
+		return &InsufficientSpaceError{Path: path, Required: uint64(required), Available: available}
+	}
+	return nil
+}
+
+// CheckInstallSpace verifies that an asset of the given size fits in the staging directory
+// (unless it is extracted while streaming) and in the install directory. The extracted size of
+// archives is not known before downloading them, so an estimate from the compressed size that
+// does not fit only produces a warning.
+func CheckInstallSpace(config FileConfig, size int64) error {
+	if !config.StreamExtraction {
+		if err := CheckDiskSpace(filepath.Dir(GetSourceArchivePath(config, "")), size); err != nil {
+			return err
+		}
+	}
+	if err := CheckDiskSpace(config.BaseBinaryDirectory, size); err != nil {
+		return err
+	}
+	if !config.IsDirectBinary && !config.IsAppImage {
+		return checkExtractionSpace(config.BaseBinaryDirectory, extractionSpace(size), false)
+	}
+	return nil
+}
+
+// checkExtractionSpace checks that extracting size bytes fits at path. Exact sizes fail with an
+// InsufficientSpaceError; estimates can exceed what the extraction needs, so they only warn.
+func checkExtractionSpace(path string, size int64, exact bool) error {
+	err := CheckDiskSpace(path, size)
+	if err == nil || exact {
+		return err
+	}
+	fmt.Printf("Warning: extraction may run out of space (size estimated from the archive): %v\n", err)
+	return nil
+}
+
+// extractionSpace estimates the space needed to extract an archive of the given compressed size
+func extractionSpace(size int64) int64 {
+	if size > math.MaxInt64/archiveExpansionFactor {
+		return math.MaxInt64
+	}
+	return size * archiveExpansionFactor
+}
+
+// archiveExtractedSize returns the space needed to extract the archive at path and whether it is
+// exact: the total of the entries of zip archives, whose central directory records it, and an
+// estimate from the compressed size otherwise. It returns 0 if the archive cannot be read.
+func archiveExtractedSize(path string) (int64, bool) {
+	if strings.EqualFold(filepath.Ext(path), ".zip") && filesystem.IsOS(fsys()) {
+		if reader, err := zip.OpenReader(path); err == nil {
+			defer reader.Close()
+			var total uint64
+			for _, file := range reader.File {
+				total += file.UncompressedSize64
+			}
+			if total > math.MaxInt64 {
+				return math.MaxInt64, true
+			}
+			return int64(total), true
+		}
+	}
+	info, err := fsys().Stat(path)
+	if err != nil {
+		return 0, false
+	}
+	return extractionSpace(info.Size()), false
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin && !freebsd && !windows
+
+package fileUtils
+
+// availableDiskSpace is not implemented on this platform, so disk space checks are skipped
+func availableDiskSpace(dir string) (uint64, error) {
+	return 0, errDiskSpaceUnsupported
+}
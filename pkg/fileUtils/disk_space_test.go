@@ -0,0 +1,117 @@
+package fileUtils
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckDiskSpace(t *testing.T) {
+	tempDir := t.TempDir()
+
+	if _, err := AvailableDiskSpace(tempDir); err != nil {
+		t.Skipf("Disk space queries unavailable: %v", err)
+	}
+
+	if err := CheckDiskSpace(tempDir, 1024); err != nil {
+		t.Errorf("Expected 1KB to fit, got %v", err)
+	}
+	if err := CheckDiskSpace(tempDir, 0); err != nil {
+		t.Errorf("Expected unknown sizes to skip the check, got %v", err)
+	}
+
+	missing := filepath.Join(tempDir, "not", "created", "yet")
+	err := CheckDiskSpace(missing, 1<<62)
+	if !errors.Is(err, ErrInsufficientSpace) {
+		t.Fatalf("Expected ErrInsufficientSpace, got %v", err)
+	}
+	var spaceErr *InsufficientSpaceError
+	if !errors.As(err, &spaceErr) {
+		t.Fatalf("Expected *InsufficientSpaceError, got %T", err)
+	}
+	if spaceErr.Path != missing || spaceErr.Required != 1<<62 {
+		t.Errorf("Unexpected error details: %+v", spaceErr)
+	}
+}
+
+func TestCheckInstallSpace(t *testing.T) {
+	tempDir := t.TempDir()
+	if _, err := AvailableDiskSpace(tempDir); err != nil {
+		t.Skipf("Disk space queries unavailable: %v", err)
+	}
+
+	config := FileConfig{
+		BaseBinaryDirectory: filepath.Join(tempDir, "bin"),
+		StagingDirectory:    filepath.Join(tempDir, "staging"),
+	}
+	if err := CheckInstallSpace(config, 1024); err != nil {
+		t.Errorf("Expected 1KB to fit, got %v", err)
+	}
+
+	var spaceErr *InsufficientSpaceError
+	if err := CheckInstallSpace(config, 1<<62); !errors.As(err, &spaceErr) || spaceErr.Path != config.StagingDirectory {
+		t.Errorf("Expected the staging directory to be reported first, got %v", err)
+	}
+
+	config.StreamExtraction = true
+	if err := CheckInstallSpace(config, 1<<62); !errors.As(err, &spaceErr) || spaceErr.Path != config.BaseBinaryDirectory {
+		t.Errorf("Expected streaming installs to only check the install directory, got %v", err)
+	}
+}
+
+func TestCheckInstallSpace_ExtractedSize(t *testing.T) {
+	tempDir := t.TempDir()
+	available, err := AvailableDiskSpace(tempDir)
+	if err != nil {
+		t.Skipf("Disk space queries unavailable: %v", err)
+	}
+
+	// An archive taking half the free space fits once extracted as far as is known beforehand
+	size := int64(available / 2)
+	config := FileConfig{
+		BaseBinaryDirectory: filepath.Join(tempDir, "bin"),
+		StagingDirectory:    filepath.Join(tempDir, "staging"),
+	}
+	if err := CheckInstallSpace(config, size); err != nil {
+		t.Errorf("Expected an estimated extracted size only to warn, got %v", err)
+	}
+	config.IsDirectBinary = true
+	if err := CheckInstallSpace(config, size); err != nil {
+		t.Errorf("Expected a direct binary of the same size to fit, got %v", err)
+	}
+
+	// Exact extracted sizes that do not fit fail
+	var spaceErr *InsufficientSpaceError
+	if err := checkExtractionSpace(tempDir, int64(available)+size, true); !errors.As(err, &spaceErr) {
+		t.Errorf("Expected an exact extracted size that does not fit to fail, got %v", err)
+	}
+	if err := checkExtractionSpace(tempDir, int64(available)+size, false); err != nil {
+		t.Errorf("Expected an estimate that does not fit only to warn, got %v", err)
+	}
+}
+
+func TestArchiveExtractedSize(t *testing.T) {
+	tempDir := t.TempDir()
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	entry, _ := writer.Create("myapp")
+	entry.Write(make([]byte, 1<<20))
+	writer.Close()
+	zipPath := filepath.Join(tempDir, "myapp.zip")
+	os.WriteFile(zipPath, buf.Bytes(), 0644)
+	if got, exact := archiveExtractedSize(zipPath); got != 1<<20 || !exact {
+		t.Errorf("Expected the exact uncompressed size of the zip entries, got %d (exact: %v)", got, exact)
+	}
+
+	tarPath := filepath.Join(tempDir, "myapp.tar.gz")
+	os.WriteFile(tarPath, make([]byte, 100), 0644)
+	if got, exact := archiveExtractedSize(tarPath); got != 100*archiveExpansionFactor || exact {
+		t.Errorf("Expected an estimate from the compressed size, got %d (exact: %v)", got, exact)
+	}
+	if got, _ := archiveExtractedSize(filepath.Join(tempDir, "missing.tar.gz")); got != 0 {
+		t.Errorf("Expected 0 for a missing archive, got %d", got)
+	}
+}
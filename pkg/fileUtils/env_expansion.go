@@ -0,0 +1,138 @@
+package fileUtils
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// ErrUndefinedVariable is returned when a path refers to an environment variable that is not set
+var ErrUndefinedVariable = NewError(ErrorCodeConfig, "undefined environment variable")
+
+// UnmarshalJSON decodes a FileConfig on top of the receiver and expands the environment variables
+// in its paths (see ExpandEnv), so configuration files can be shared across machines and users
+func (c *FileConfig) UnmarshalJSON(data []byte) error {
+	type plain FileConfig
+	if err := json.Unmarshal(data, (*plain)(c)); err != nil {
+		return err
+	}
+	expanded, err := c.ExpandEnv()
+	if err != nil {
+		return err
+	}
+	*c = expanded
+	return nil
+}
+
+// ExpandEnv returns the configuration with ${VAR}, $VAR and %VAR% placeholders replaced in
+// BaseBinaryDirectory, SourceArchivePath, GlobalSymlinkDirectory, SharedVersionsDirectory and
+// StagingDirectory. HOME, USERPROFILE, XDG_DATA_HOME, XDG_CONFIG_HOME and LOCALAPPDATA fall back
+// to their platform defaults when unset; any other unset variable is an ErrUndefinedVariable.
+func (c FileConfig) ExpandEnv() (FileConfig, error) {
+	fields := []struct {
+		name  string
+		value *string
+	}{
+		{"base_binary_directory", &c.BaseBinaryDirectory},
+		{"source_archive_path", &c.SourceArchivePath},
+		{"global_symlink_directory", &c.GlobalSymlinkDirectory},
+		{"shared_versions_directory", &c.SharedVersionsDirectory},
+		{"staging_directory", &c.StagingDirectory},
+	}
+	var env *platformEnv
+	for _, field := range fields {
+		if !strings.ContainsAny(*field.value, "$%") {
+			continue
+		}
+		if env == nil {
+			current, err := currentPlatformEnv()
+			if err != nil {
+				return c, err
+			}
+			env = &current
+		}
+		expanded, err := env.expand(*field.value)
+		if err != nil {
+			return c, fmt.Errorf("failed to expand %s: %w", field.name, err)
+		}
+		*field.value = expanded
+	}
+	return c, nil
+}
+
+// expand replaces the ${VAR}, $VAR and %VAR% placeholders in value. A "$" or "%" that does not
+// start a placeholder is kept.
+func (e platformEnv) expand(value string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		name, end := placeholderAt(value, i)
+		if name == "" {
+			b.WriteByte(value[i])
+			continue
+		}
+		resolved, err := e.lookup(name)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(resolved)
+		i = end - 1
+	}
+	return b.String(), nil
+}
+
+// placeholderAt returns the variable named by a placeholder starting at value[i] and the index
+// after the placeholder, or "" if no placeholder starts there
+func placeholderAt(value string, i int) (string, int) {
+	rest := value[i+1:]
+	switch value[i] {
+	case '$':
+		if strings.HasPrefix(rest, "{") {
+			if end := strings.IndexByte(rest, '}'); end > 1 && isVariableName(rest[1:end]) {
+				return rest[1:end], i + end + 2
+			}
+			return "", 0
+		}
+		end := 0
+		for end < len(rest) && isVariableName(rest[end:end+1]) {
+			end++
+		}
+		if end > 0 {
+			return rest[:end], i + end + 1
+		}
+	case '%':
+		if end := strings.IndexByte(rest, '%'); end > 0 && isVariableName(rest[:end]) {
+			return rest[:end], i + end + 2
+		}
+	}
+	return "", 0
+}
+
+// isVariableName reports whether name only contains letters, digits and underscores
+func isVariableName(name string) bool {
+	for _, r := range name {
+		if !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return name != ""
+}
+
+// lookup returns the value of an environment variable, falling back to the platform default of
+// the well-known directory variables
+func (e platformEnv) lookup(name string) (string, error) {
+	if value := e.getenv(name); value != "" {
+		return value, nil
+	}
+	switch name {
+	case "HOME", "USERPROFILE":
+		return e.home, nil
+	case "XDG_DATA_HOME":
+		return e.xdgDataHome(), nil
+	case "XDG_CONFIG_HOME":
+		return e.xdgConfigHome(), nil
+	case "LOCALAPPDATA":
+		return filepath.Join(e.home, "AppData", "Local"), nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrUndefinedVariable, name)
+}
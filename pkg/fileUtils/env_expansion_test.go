@@ -0,0 +1,57 @@
+package fileUtils
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestPlatformEnv_Expand(t *testing.T) {
+	home := filepath.FromSlash("/home/user")
+	env := platformEnv{goos: "linux", home: home, getenv: func(key string) string {
+		return map[string]string{"TOOLS": "/opt/tools", "LOCALAPPDATA": `C:\Users\user\AppData\Local`}[key]
+	}}
+	testCases := []struct {
+		value    string
+		expected string
+	}{
+		{"${HOME}/.local/bin", home + "/.local/bin"},
+		{"$TOOLS/bin", "/opt/tools/bin"},
+		{"${XDG_DATA_HOME}/tools", filepath.Join(home, ".local", "share") + "/tools"},
+		{`%LOCALAPPDATA%\tools`, `C:\Users\user\AppData\Local\tools`},
+		{"/opt/100%/$/${", "/opt/100%/$/${"},
+		{"/no/placeholders", "/no/placeholders"},
+	}
+	for _, tc := range testCases {
+		if got, err := env.expand(tc.value); err != nil || got != tc.expected {
+			t.Errorf("expand(%q) = %q, %v, want %q", tc.value, got, err, tc.expected)
+		}
+	}
+	if _, err := env.expand("${UNSET}/bin"); !errors.Is(err, ErrUndefinedVariable) {
+		t.Errorf("expand() of an unset variable error = %v, want ErrUndefinedVariable", err)
+	}
+}
+
+func TestFileConfig_UnmarshalJSONExpandsPaths(t *testing.T) {
+	t.Setenv("TOOLS_ROOT", "/srv/tools")
+	config := DefaultFileConfig()
+	data := `{"binary_name": "tool", "base_binary_directory": "${TOOLS_ROOT}/bin", "global_symlink_directory": "$TOOLS_ROOT/global"}`
+	if err := json.Unmarshal([]byte(data), &config); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if config.BaseBinaryDirectory != "/srv/tools/bin" || config.GlobalSymlinkDirectory != "/srv/tools/global" {
+		t.Errorf("Expected expanded paths, got %q and %q", config.BaseBinaryDirectory, config.GlobalSymlinkDirectory)
+	}
+	if !config.CreateLocalSymlink || config.AssetMatchingStrategy != "flexible" {
+		t.Error("Expected the settings missing from the JSON to be kept")
+	}
+	if GetGlobalSymlinkPath(config) != filepath.Join("/srv/tools/global", "tool") {
+		t.Errorf("Unexpected global symlink path %q", GetGlobalSymlinkPath(config))
+	}
+
+	err := json.Unmarshal([]byte(`{"source_archive_path": "${TOOLS_MISSING}/tool.tar.gz"}`), &config)
+	if !errors.Is(err, ErrUndefinedVariable) {
+		t.Errorf("Unmarshal() with an unset variable error = %v, want ErrUndefinedVariable", err)
+	}
+}
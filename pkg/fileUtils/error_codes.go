@@ -0,0 +1,94 @@
+package fileUtils
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"net"
+)
+
+// ErrorCode classifies a failure, so CLIs built on the library can map it to an exit status or
+// machine-readable output without matching error messages
+type ErrorCode string
+
+// Error codes reported by ErrorCodeOf
+const (
+	ErrorCodeUnknown    ErrorCode = "unknown"    // Not classified
+	ErrorCodeConfig     ErrorCode = "config"     // Invalid configuration
+	ErrorCodeNetwork    ErrorCode = "network"    // Unreachable host, timeout, rate limit or server error
+	ErrorCodeAuth       ErrorCode = "auth"       // Missing, expired or insufficient credentials
+	ErrorCodeNoMatch    ErrorCode = "no_match"   // No release, version or asset for this host
+	ErrorCodeChecksum   ErrorCode = "checksum"   // Checksum, digest, signature or TLS pin mismatch
+	ErrorCodeDisk       ErrorCode = "disk"       // Not enough disk space
+	ErrorCodePermission ErrorCode = "permission" // Install directories or files not writable
+)
+
+// exitCodes are the process exit statuses of the error codes. 1 is the generic failure status and
+// 2 the usual status of usage errors, which invalid configurations resemble.
+var exitCodes = map[ErrorCode]int{
+	ErrorCodeUnknown:    1,
+	ErrorCodeConfig:     2,
+	ErrorCodeNetwork:    3,
+	ErrorCodeAuth:       4,
+	ErrorCodeNoMatch:    5,
+	ErrorCodeChecksum:   6,
+	ErrorCodeDisk:       7,
+	ErrorCodePermission: 8,
+}
+
+// ExitCode returns a distinct process exit status for the code, 1 for unknown codes
+func (c ErrorCode) ExitCode() int {
+	if status, ok := exitCodes[c]; ok {
+		return status
+	}
+	return 1
+}
+
+// CodedError is implemented by errors that know their ErrorCode, e.g. ReadOnlyError and the
+// sentinel errors created with NewError
+type CodedError interface {
+	error
+	ErrorCode() ErrorCode
+}
+
+// codedError is a sentinel error with a fixed code
+type codedError struct {
+	text string
+	code ErrorCode
+}
+
+func (e *codedError) Error() string {
+	return e.text
+}
+
+func (e *codedError) ErrorCode() ErrorCode {
+	return e.code
+}
+
+// NewError returns a distinct error like errors.New whose ErrorCode is code, for sentinel errors
+func NewError(code ErrorCode, text string) error {
+	return &codedError{text: text, code: code}
+}
+
+// ErrorCodeOf classifies err by the first CodedError in its chain. Errors without one are
+// classified by the standard library errors they wrap: permission errors, a full disk, a missed
+// deadline and network errors. It returns "" for nil and ErrorCodeUnknown for anything else.
+func ErrorCodeOf(err error) ErrorCode {
+	if err == nil {
+		return ""
+	}
+	var coded CodedError
+	if errors.As(err, &coded) {
+		return coded.ErrorCode()
+	}
+	var netErr net.Error
+	switch {
+	case errors.Is(err, fs.ErrPermission):
+		return ErrorCodePermission
+	case isNoSpace(err):
+		return ErrorCodeDisk
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr):
+		return ErrorCodeNetwork
+	}
+	return ErrorCodeUnknown
+}
@@ -0,0 +1,13 @@
+//go:build !plan9
+
+package fileUtils
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isNoSpace reports whether err says the disk is full
+func isNoSpace(err error) bool {
+	return errors.Is(err, syscall.ENOSPC)
+}
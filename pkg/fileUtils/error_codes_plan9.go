@@ -0,0 +1,8 @@
+//go:build plan9
+
+package fileUtils
+
+// isNoSpace reports false, as Plan 9 errors are strings without an ENOSPC errno
+func isNoSpace(err error) bool {
+	return false
+}
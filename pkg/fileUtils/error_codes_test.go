@@ -0,0 +1,140 @@
+package fileUtils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/filesystem"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestErrorCodeOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorCode
+	}{
+		{"nil", nil, ""},
+		{"plain", errors.New("boom"), ErrorCodeUnknown},
+		{"invalid config", InvalidConfigError([]string{"binary_name is required"}), ErrorCodeConfig},
+		{"checksum", fmt.Errorf("download failed: %w", &ChecksumError{Name: "tool", Expected: "00", Actual: "ff"}), ErrorCodeChecksum},
+		{"signature", fmt.Errorf("%w: content does not match the signature", ErrInvalidSignature), ErrorCodeChecksum},
+		{"disk", &InsufficientSpaceError{Path: "/tmp", Required: 2, Available: 1}, ErrorCodeDisk},
+		{"read-only", fmt.Errorf("install: %w", &ReadOnlyError{Path: "/opt", Err: os.ErrPermission}), ErrorCodePermission},
+		{"architecture", &ArchitectureMismatchError{Path: "tool"}, ErrorCodeNoMatch},
+		{"not installed", fmt.Errorf("%w: 1.0.0", ErrVersionNotInstalled), ErrorCodeNoMatch},
+		{"path permission", &os.PathError{Op: "open", Path: "/opt/tool", Err: syscall.EACCES}, ErrorCodePermission},
+		{"disk full", &os.PathError{Op: "write", Path: "/tmp/tool", Err: syscall.ENOSPC}, ErrorCodeDisk},
+		{"deadline", fmt.Errorf("stopped: %w", context.DeadlineExceeded), ErrorCodeNetwork},
+		{"network", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, ErrorCodeNetwork},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorCodeOf(tt.err); got != tt.want {
+				t.Errorf("ErrorCodeOf(%v) = %q, want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorCode_ExitCode(t *testing.T) {
+	seen := map[int]ErrorCode{}
+	for code := range exitCodes {
+		status := code.ExitCode()
+		if other, ok := seen[status]; ok {
+			t.Errorf("%s and %s share exit code %d", code, other, status)
+		}
+		seen[status] = code
+		if status == 0 {
+			t.Errorf("%s has exit code 0", code)
+		}
+	}
+	if status := ErrorCode("other").ExitCode(); status != 1 {
+		t.Errorf("Expected exit code 1 for an unknown code, got %d", status)
+	}
+}
+
+// faultyFS is a MemFS failing the operations an installation can fail with on a real disk
+type faultyFS struct {
+	*filesystem.MemFS
+	mkdirErr   error // Returned by MkdirAll for version directories
+	symlinkErr error // Returned by Symlink
+	writeErr   error // Returned by writes to created files
+}
+
+func (f *faultyFS) MkdirAll(path string, perm os.FileMode) error {
+	if f.mkdirErr != nil && strings.Contains(path, "versions") {
+		return &os.PathError{Op: "mkdir", Path: path, Err: f.mkdirErr}
+	}
+	return f.MemFS.MkdirAll(path, perm)
+}
+
+func (f *faultyFS) Symlink(oldname, newname string) error {
+	if f.symlinkErr != nil {
+		return &os.LinkError{Op: "symlink", Old: oldname, New: newname, Err: f.symlinkErr}
+	}
+	return f.MemFS.Symlink(oldname, newname)
+}
+
+func (f *faultyFS) Create(name string) (filesystem.File, error) {
+	file, err := f.MemFS.Create(name)
+	if err != nil || f.writeErr == nil {
+		return file, err
+	}
+	return &faultyFile{File: file, err: f.writeErr}, nil
+}
+
+// faultyFile fails every write
+type faultyFile struct {
+	filesystem.File
+	err error
+}
+
+func (f *faultyFile) Write([]byte) (int, error) {
+	return 0, &os.PathError{Op: "write", Path: f.Name(), Err: f.err}
+}
+
+func TestErrorCodeOf_InstallFailures(t *testing.T) {
+	baseDir := filepath.Join(string(filepath.Separator), "opt", "tool")
+	config := FileConfig{
+		BaseBinaryDirectory:    baseDir,
+		VersionedDirectoryName: "versions",
+		BinaryName:             "tool",
+	}
+	tests := []struct {
+		name    string
+		fsys    *faultyFS
+		install func(source string) error
+		want    ErrorCode
+	}{
+		{"version directory permission", &faultyFS{mkdirErr: syscall.EACCES}, func(source string) error {
+			return InstallFromFile(config, source, "1.0.0", nil)
+		}, ErrorCodePermission},
+		{"disk full while copying", &faultyFS{writeErr: syscall.ENOSPC}, func(source string) error {
+			return InstallFromFile(config, source, "1.0.0", nil)
+		}, ErrorCodeDisk},
+		{"symlink permission", &faultyFS{symlinkErr: syscall.EPERM}, func(source string) error {
+			return UpdateSymlink(source, filepath.Join(baseDir, "tool"))
+		}, ErrorCodePermission},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fsys.MemFS = filesystem.NewMemFS()
+			source := filepath.Join(baseDir, "staging", "tool")
+			tt.fsys.MemFS.MkdirAll(filepath.Dir(source), 0755)
+			tt.fsys.MemFS.WriteFile(source, []byte("binary"), 0755)
+			filesystem.SetDefault(tt.fsys)
+			defer filesystem.SetDefault(nil)
+
+			err := tt.install(source)
+			if got := ErrorCodeOf(err); got != tt.want {
+				t.Errorf("ErrorCodeOf(%v) = %q, want %q", err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package fileUtils
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/archiver"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ExportManifestFileName is the name of the manifest embedded at the root of exported archives
+const ExportManifestFileName = "go-binary-updater-export.json"
+
+// ExportManifest describes an installed version packaged by ExportVersion
+type ExportManifest struct {
+	BinaryName   string    `json:"binary_name"`   // Name of the installed binary inside the archive
+	ProjectName  string    `json:"project_name"`  // Project name from the exporting configuration
+	Version      string    `json:"version"`       // Version that was exported
+	BinarySHA256 string    `json:"binary_sha256"` // SHA-256 of the binary for verification after transfer
+	ExportedAt   time.Time `json:"exported_at"`   // Time the export was created
+}
+
+// ExportVersion packages an installed versioned directory plus a manifest into a .tar.gz archive.
+// The resulting archive can be copied to an offline machine and installed there with InstallFromFile,
+// using BinaryName as the SourceBinaryName.
+func ExportVersion(config FileConfig, version, destArchive string) (*ExportManifest, error) {
+	versionDir := GetVersionedDirectoryPath(config, version)
+	binaryPath := GetVersionedBinaryPath(config, version)
+	if !FileExists(binaryPath) {
+		return nil, fmt.Errorf("version %s is not installed: %s not found", version, binaryPath)
+	}
+
+	checksum, err := FileSHA256(binaryPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to checksum binary: %v", err)
+	}
+
+	manifest := &ExportManifest{
+		BinaryName:   config.BinaryName,
+		ProjectName:  config.ProjectName,
+		Version:      version,
+		BinarySHA256: checksum,
+		ExportedAt:   time.Now().UTC(),
+	}
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode export manifest: %v", err)
+	}
+
+	if err := fsys().MkdirAll(filepath.Dir(destArchive), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create destination directory: %v", err)
+	}
+	extraFiles := map[string][]byte{ExportManifestFileName: manifestData}
+	if err := archiver.CreateTarGz(versionDir, destArchive, extraFiles); err != nil {
+		return nil, fmt.Errorf("failed to export version %s: %v", version, err)
+	}
+
+	return manifest, nil
+}
+
+// ReadExportManifest reads the manifest embedded in an archive created by ExportVersion
+func ReadExportManifest(archivePath string) (*ExportManifest, error) {
+	file, err := fsys().Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %v", err)
+	}
+	defer file.Close()
+
+	gzReader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gzip reader: %v", err)
+	}
+	defer gzReader.Close()
+
+	tarReader := tar.NewReader(gzReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tar entry: %v", err)
+		}
+		if header.Name != ExportManifestFileName {
+			continue
+		}
+
+		var manifest ExportManifest
+		if err := json.NewDecoder(tarReader).Decode(&manifest); err != nil {
+			return nil, fmt.Errorf("failed to decode export manifest: %v", err)
+		}
+		return &manifest, nil
+	}
+
+	return nil, fmt.Errorf("archive %s does not contain %s", archivePath, ExportManifestFileName)
+}
+
+// FileSHA256 returns the hex-encoded SHA-256 digest of a file
+func FileSHA256(path string) (string, error) {
+	file, err := fsys().Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// ErrChecksumMismatch is returned (wrapped in a ChecksumError) when a file's content differs from
+// the checksum or digest it was published with
+var ErrChecksumMismatch = NewError(ErrorCodeChecksum, "checksum mismatch")
+
+// ChecksumError describes a file whose content differs from its expected checksum
+type ChecksumError struct {
+	Name      string // File or URL that was checked
+	Algorithm string // Algorithm of a prefixed digest such as "sha256:<hex>"; empty for plain SHA-256 checksums
+	Expected  string // Expected hex digest
+	Actual    string // Hex digest of the content
+}
+
+func (e *ChecksumError) Error() string {
+	if e.Algorithm != "" {
+		return fmt.Sprintf("digest mismatch for %s: expected %s:%s, got %s:%s", e.Name, e.Algorithm, e.Expected, e.Algorithm, e.Actual)
+	}
+	return fmt.Sprintf("checksum mismatch for %s: expected %s, got %s", e.Name, e.Expected, e.Actual)
+}
+
+// Is makes errors.Is(err, ErrChecksumMismatch) match
+func (e *ChecksumError) Is(target error) bool {
+	return target == ErrChecksumMismatch
+}
+
+// ErrorCode returns ErrorCodeChecksum
+func (e *ChecksumError) ErrorCode() ErrorCode {
+	return ErrorCodeChecksum
+}
+
+// VerifyFileSHA256 checks that a file's SHA-256 digest matches the expected hex digest
+func VerifyFileSHA256(path, expected string) error {
+	actual, err := FileSHA256(path)
+	if err != nil {
+		return fmt.Errorf("failed to checksum %s: %v", path, err)
+	}
+	if !strings.EqualFold(actual, strings.TrimSpace(expected)) {
+		return &ChecksumError{Name: filepath.Base(path), Expected: expected, Actual: actual}
+	}
+	return nil
+}
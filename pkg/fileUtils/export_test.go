@@ -0,0 +1,74 @@
+package fileUtils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExportVersion_RoundTrip(t *testing.T) {
+	sourceDir := t.TempDir()
+	config := FileConfig{
+		BaseBinaryDirectory:     sourceDir,
+		BinaryName:              "myapp",
+		SourceBinaryName:        "myapp",
+		ProjectName:             "myapp",
+		UseVersionsSubdirectory: true,
+		CreateLocalSymlink:      true,
+	}
+
+	versionDir := GetVersionedDirectoryPath(config, "v1.2.3")
+	if err := os.MkdirAll(versionDir, 0755); err != nil {
+		t.Fatalf("Failed to create version dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(versionDir, "myapp"), []byte("binary content"), 0755); err != nil {
+		t.Fatalf("Failed to write binary: %v", err)
+	}
+
+	archivePath := filepath.Join(t.TempDir(), "export", "myapp-v1.2.3.tar.gz")
+	manifest, err := ExportVersion(config, "v1.2.3", archivePath)
+	if err != nil {
+		t.Fatalf("ExportVersion failed: %v", err)
+	}
+	if manifest.Version != "v1.2.3" || manifest.BinaryName != "myapp" {
+		t.Errorf("Unexpected manifest: %+v", manifest)
+	}
+	if manifest.BinarySHA256 == "" {
+		t.Error("Expected manifest to contain binary checksum")
+	}
+
+	readBack, err := ReadExportManifest(archivePath)
+	if err != nil {
+		t.Fatalf("ReadExportManifest failed: %v", err)
+	}
+	if readBack.BinarySHA256 != manifest.BinarySHA256 {
+		t.Errorf("Expected checksum %s, got %s", manifest.BinarySHA256, readBack.BinarySHA256)
+	}
+
+	// Install the exported archive on a "different machine"
+	targetConfig := config
+	targetConfig.BaseBinaryDirectory = t.TempDir()
+	if err := InstallFromFile(targetConfig, archivePath, readBack.Version, nil); err != nil {
+		t.Fatalf("InstallFromFile failed for exported archive: %v", err)
+	}
+
+	installed, err := os.ReadFile(GetVersionedBinaryPath(targetConfig, "v1.2.3"))
+	if err != nil {
+		t.Fatalf("Failed to read installed binary: %v", err)
+	}
+	if string(installed) != "binary content" {
+		t.Errorf("Installed binary content mismatch: %q", installed)
+	}
+}
+
+func TestExportVersion_NotInstalled(t *testing.T) {
+	config := FileConfig{
+		BaseBinaryDirectory:    t.TempDir(),
+		VersionedDirectoryName: "versions",
+		BinaryName:             "myapp",
+	}
+	_, err := ExportVersion(config, "v9.9.9", filepath.Join(t.TempDir(), "out.tar.gz"))
+	if err == nil {
+		t.Error("Expected error when exporting a version that is not installed")
+	}
+}
@@ -0,0 +1,150 @@
+package fileUtils
+
+import (
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/filesystem"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ExtraFilesConfig controls installation of shell completions and man pages shipped in release
+// archives next to the binary. Empty directories use XDG-style defaults below the home directory.
+type ExtraFilesConfig struct {
+	Completions             bool   `json:"completions"`               // Install bash, zsh and fish completion scripts
+	ManPages                bool   `json:"man_pages"`                 // Install man pages
+	BashCompletionDirectory string `json:"bash_completion_directory"` // Default: $XDG_DATA_HOME/bash-completion/completions
+	ZshCompletionDirectory  string `json:"zsh_completion_directory"`  // Default: $XDG_DATA_HOME/zsh/site-functions
+	FishCompletionDirectory string `json:"fish_completion_directory"` // Default: $XDG_CONFIG_HOME/fish/completions
+	ManDirectory            string `json:"man_directory"`             // Default: $XDG_DATA_HOME/man; pages go to man{section}/
+}
+
+// manPagePattern matches man pages such as tool.1 or tool.8.gz and captures the section
+var manPagePattern = regexp.MustCompile(`\.([1-9])(\.gz)?$`)
+
+// extraFileDirectories resolves the configured destinations, filling in the defaults
+func (c ExtraFilesConfig) extraFileDirectories() (ExtraFilesConfig, error) {
+	if c.BashCompletionDirectory != "" && c.ZshCompletionDirectory != "" &&
+		c.FishCompletionDirectory != "" && c.ManDirectory != "" {
+		return c, nil
+	}
+	env, err := currentPlatformEnv()
+	if err != nil {
+		return c, err
+	}
+	if c.BashCompletionDirectory == "" {
+		c.BashCompletionDirectory = filepath.Join(env.xdgDataHome(), "bash-completion", "completions")
+	}
+	if c.ZshCompletionDirectory == "" {
+		c.ZshCompletionDirectory = filepath.Join(env.xdgDataHome(), "zsh", "site-functions")
+	}
+	if c.FishCompletionDirectory == "" {
+		c.FishCompletionDirectory = filepath.Join(env.xdgConfigHome(), "fish", "completions")
+	}
+	if c.ManDirectory == "" {
+		c.ManDirectory = filepath.Join(env.xdgDataHome(), "man")
+	}
+	return c, nil
+}
+
+// extraFileDestination returns where an extracted file should be installed, or "" if it is
+// neither an enabled completion script nor an enabled man page
+func (c ExtraFilesConfig) extraFileDestination(relPath, binaryName string) string {
+	name := filepath.Base(relPath)
+	lowerPath := strings.ToLower(filepath.ToSlash(relPath))
+	lowerName := strings.ToLower(name)
+
+	if c.Completions {
+		inCompletionDir := strings.Contains(lowerPath, "complet")
+		switch {
+		case strings.HasSuffix(lowerName, ".fish") || (inCompletionDir && strings.Contains(lowerPath, "fish")):
+			return filepath.Join(c.FishCompletionDirectory, binaryName+".fish")
+		case strings.HasSuffix(lowerName, ".zsh") || (inCompletionDir && strings.Contains(lowerPath, "zsh")) || name == "_"+binaryName:
+			return filepath.Join(c.ZshCompletionDirectory, "_"+binaryName)
+		case strings.HasSuffix(lowerName, ".bash") || (inCompletionDir && strings.Contains(lowerPath, "bash")):
+			return filepath.Join(c.BashCompletionDirectory, binaryName)
+		}
+	}
+
+	// Man pages live in a man directory or are named after the binary, e.g. manpages/tool.1.gz
+	if c.ManPages && (strings.Contains(lowerPath, "man") || strings.HasPrefix(lowerName, strings.ToLower(binaryName)+".")) {
+		if match := manPagePattern.FindStringSubmatch(lowerName); match != nil {
+			return filepath.Join(c.ManDirectory, "man"+match[1], name)
+		}
+	}
+	return ""
+}
+
+// installExtraFiles copies the completions and man pages found in the version directory to their
+// configured locations and returns the installed paths. Failures only produce warnings because
+// the binary itself was installed successfully.
+func installExtraFiles(config FileConfig, versionDir string) []string {
+	sources, err := extraFileSources(config, versionDir)
+	if err != nil {
+		fmt.Printf("Warning: failed to install completions and man pages: %v\n", err)
+		return nil
+	}
+
+	var installed []string
+	for _, destination := range sortedKeys(sources) {
+		if err := installExtraFile(sources[destination], destination); err != nil {
+			relPath, _ := filepath.Rel(versionDir, sources[destination])
+			fmt.Printf("Warning: failed to install %s: %v\n", relPath, err)
+			continue
+		}
+		fmt.Printf("Installed %s\n", destination)
+		installed = append(installed, destination)
+	}
+	return installed
+}
+
+// extraFileSources maps the destination of every completion and man page in the version directory
+// to the extracted file it is copied from. The first file found for a destination wins.
+func extraFileSources(config FileConfig, versionDir string) (map[string]string, error) {
+	if !config.ExtraFiles.Completions && !config.ExtraFiles.ManPages {
+		return nil, nil
+	}
+	extraFiles, err := config.ExtraFiles.extraFileDirectories()
+	if err != nil {
+		return nil, err
+	}
+
+	binaryPath := filepath.Join(versionDir, config.BinaryName)
+	sources := make(map[string]string)
+	filesystem.WalkDir(fsys(), versionDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() || path == binaryPath {
+			return nil
+		}
+		relPath, _ := filepath.Rel(versionDir, path)
+		destination := extraFiles.extraFileDestination(relPath, config.BinaryName)
+		if _, ok := sources[destination]; destination != "" && !ok {
+			sources[destination] = path
+		}
+		return nil
+	})
+	return sources, nil
+}
+
+// installExtraFile copies an extracted completion script or man page to its destination
+func installExtraFile(source, destination string) error {
+	if err := fsys().MkdirAll(filepath.Dir(destination), 0755); err != nil {
+		return err
+	}
+	if err := copyFile(source, destination); err != nil {
+		return err
+	}
+	fsys().Chmod(destination, 0644)
+	return nil
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,127 @@
+package fileUtils
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// createTestArchiveWithFiles writes a .tar.gz containing the given files
+func createTestArchiveWithFiles(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create archive: %v", err)
+	}
+	defer file.Close()
+	gzWriter := gzip.NewWriter(file)
+	tarWriter := tar.NewWriter(gzWriter)
+	for name, content := range files {
+		tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		tarWriter.Write([]byte(content))
+	}
+	tarWriter.Close()
+	gzWriter.Close()
+}
+
+func TestExtraFilesConfig_Destination(t *testing.T) {
+	config := ExtraFilesConfig{
+		Completions:             true,
+		ManPages:                true,
+		BashCompletionDirectory: "/bash",
+		ZshCompletionDirectory:  "/zsh",
+		FishCompletionDirectory: "/fish",
+		ManDirectory:            "/man",
+	}
+
+	testCases := []struct {
+		path     string
+		expected string
+	}{
+		{"completions/tool.bash", filepath.Join("/bash", "tool")},
+		{"completions/bash/tool", filepath.Join("/bash", "tool")},
+		{"autocomplete/bash_autocomplete", filepath.Join("/bash", "tool")},
+		{"completions/_tool", filepath.Join("/zsh", "_tool")},
+		{"completions/tool.zsh", filepath.Join("/zsh", "_tool")},
+		{"_tool", filepath.Join("/zsh", "_tool")},
+		{"completions/tool.fish", filepath.Join("/fish", "tool.fish")},
+		{"completions/fish/tool", filepath.Join("/fish", "tool.fish")},
+		{"manpages/tool.1.gz", filepath.Join("/man", "man1", "tool.1.gz")},
+		{"tool.8", filepath.Join("/man", "man8", "tool.8")},
+		{"usr/share/man/man5/tool.conf.5.gz", filepath.Join("/man", "man5", "tool.conf.5.gz")},
+		{"docs/other-1.2", ""},
+		{"LICENSE", ""},
+		{"README.md", ""},
+	}
+
+	for _, tc := range testCases {
+		if got := config.extraFileDestination(tc.path, "tool"); got != tc.expected {
+			t.Errorf("extraFileDestination(%q) = %q, want %q", tc.path, got, tc.expected)
+		}
+	}
+
+	disabled := ExtraFilesConfig{}
+	if got := disabled.extraFileDestination("completions/tool.bash", "tool"); got != "" {
+		t.Errorf("Expected no destination when extra files are disabled, got %q", got)
+	}
+}
+
+func TestInstallBinary_ExtraFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", filepath.Join(tempDir, "share"))
+
+	archivePath := filepath.Join(tempDir, "tool.tar.gz")
+	createTestArchiveWithFiles(t, archivePath, map[string]string{
+		"tool":                  "binary",
+		"completions/tool.bash": "bash",
+		"completions/_tool":     "zsh",
+		"manpages/tool.1.gz":    "man",
+		"LICENSE":               "license",
+	})
+
+	config := FileConfig{
+		BaseBinaryDirectory:    filepath.Join(tempDir, "bin"),
+		VersionedDirectoryName: "versions",
+		SourceBinaryName:       "tool",
+		BinaryName:             "tool",
+		SourceArchivePath:      archivePath,
+		ExtraFiles: ExtraFilesConfig{
+			Completions:             true,
+			ManPages:                true,
+			BashCompletionDirectory: filepath.Join(tempDir, "bash-completion"),
+		},
+	}
+	if err := InstallBinary(config, "1.0.0"); err != nil {
+		t.Fatalf("InstallBinary() error = %v", err)
+	}
+
+	expected := []string{
+		filepath.Join(tempDir, "bash-completion", "tool"),
+		filepath.Join(tempDir, "share", "man", "man1", "tool.1.gz"),
+		filepath.Join(tempDir, "share", "zsh", "site-functions", "_tool"),
+	}
+	for _, path := range expected {
+		if !FileExists(path) {
+			t.Errorf("Expected %s to be installed", path)
+		}
+	}
+
+	receipt, err := GetInstallReceipt(config)
+	if err != nil {
+		t.Fatalf("GetInstallReceipt() error = %v", err)
+	}
+	if len(receipt.ExtraFiles) != len(expected) {
+		t.Errorf("Receipt extra files = %v, want %v", receipt.ExtraFiles, expected)
+	}
+
+	if _, err := Uninstall(config); err != nil {
+		t.Fatalf("Uninstall() error = %v", err)
+	}
+	for _, path := range expected {
+		if FileExists(path) {
+			t.Errorf("Expected %s to be removed by Uninstall", path)
+		}
+	}
+}
@@ -1,35 +1,135 @@
 package fileUtils
 
 import (
+	"context"
 	"fmt"
 	"gitlab.com/locke-codes/go-binary-updater/pkg/archiver"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/filesystem"
 	"io"
+	"math/rand/v2"
 	"net/http"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 )
 
+// fsys returns the file system installations operate on, see filesystem.SetDefault
+func fsys() filesystem.FS {
+	return filesystem.Default()
+}
+
 type FileConfig struct {
 	VersionedDirectoryName string `json:"versioned_directory_name"`
 	SourceBinaryName       string `json:"source_binary_name"`
+	SourceBinaryPattern    string `json:"source_binary_pattern"`    // Glob for the binary in the archive when its name varies, e.g. "helm-v3*" (overrides SourceBinaryName)
 	BinaryName             string `json:"binary_name"`
-	CreateGlobalSymlink    bool   `json:"create_global_symlink"`    // Create global symlink in /usr/local/bin (requires sudo)
+	CreateGlobalSymlink    bool   `json:"create_global_symlink"`    // Create global symlink in GlobalSymlinkDirectory (requires sudo)
+	GlobalSymlinkDirectory string `json:"global_symlink_directory"` // Directory of the global symlink (default: /usr/local/bin)
 	BaseBinaryDirectory    string `json:"base_binary_directory"`
 	SourceArchivePath      string `json:"source_archive_path"`
 
 	// Enhanced symlink control (preserving symlink-first approach)
 	CreateLocalSymlink     bool   `json:"create_local_symlink"`     // Create local symlink in BaseBinaryDirectory (default: true)
+	LocalSymlinkName       string `json:"local_symlink_name"`       // Name of the local symlink (default: BinaryName), e.g. "kubectl" for a "kubectl-1.28" binary
+	SymlinkAliases         []string `json:"symlink_aliases"`        // Additional symlinks in BaseBinaryDirectory pointing at the binary, e.g. "tf" for terraform
 
 	// Enhanced directory structure control
 	UseVersionsSubdirectory bool   `json:"use_versions_subdirectory"` // Use versions/{ProjectName}/ subdirectory pattern (default: false for backward compatibility)
 
 	// Enhanced configuration for flexible asset handling
 	IsDirectBinary         bool   `json:"is_direct_binary"`         // True if the downloaded asset is a direct binary, not an archive
+	IsCompressedBinary     bool   `json:"is_compressed_binary"`     // True if the asset is a single binary compressed with gzip, xz or zstd (no tar)
+	IsAppImage             bool   `json:"is_appimage"`              // True if the asset is an AppImage (also detected from the downloaded file)
+	StripAppImageUpdateInfo bool  `json:"strip_appimage_update_info"` // Clear an AppImage's embedded update information so it cannot update itself
 	ProjectName            string `json:"project_name"`             // Project name for asset matching (e.g., "k0s", "kubectl")
-	AssetMatchingStrategy  string `json:"asset_matching_strategy"`  // Strategy for asset matching: "standard", "flexible", "custom"
+
+	// Deprecated: AssetMatchingStrategy and CustomAssetPatterns only configure the release
+	// constructors that take no AssetMatchingConfig, which parse the name with release.ParseStrategy.
+	// Set AssetMatchingConfig.Strategy and CustomPatterns instead, e.g. with
+	// release.NewGithubReleaseWithAssetConfig.
+	AssetMatchingStrategy  string `json:"asset_matching_strategy"`  // Strategy for asset matching: "standard", "flexible", "custom", "cdn" or "hybrid"
 	CustomAssetPatterns    []string `json:"custom_asset_patterns"`  // Custom regex patterns for asset matching
+
+	// Optional on-disk cache so repeated installs of the same asset skip the download
+	Cache                  CacheConfig `json:"cache"`
+
+	// Extract archives while downloading instead of writing them to SourceArchivePath first
+	StreamExtraction       bool   `json:"stream_extraction"`
+
+	// Directory for downloads and extraction staging (default: os.TempDir())
+	StagingDirectory       string `json:"staging_directory"`
+
+	// Keep the downloaded archive after a successful installation instead of removing it.
+	// Downloads of failed installations are always kept for debugging.
+	KeepDownloads          bool   `json:"keep_downloads"`
+
+	// Download large assets with parallel range requests
+	ChunkedDownload        ChunkedDownloadConfig `json:"chunked_download"`
+
+	// Permissions of installed binaries and version directories (default: 0755 minus the umask).
+	// In JSON these are decimal numbers, e.g. 488 for 0750.
+	BinaryFileMode         os.FileMode `json:"binary_file_mode"`
+	DirectoryMode          os.FileMode `json:"directory_mode"`
+
+	// Shared root for versioned binaries (e.g. /opt/tools/versions), typically owned by root.
+	// BaseBinaryDirectory then only holds the current user's symlinks (e.g. ~/.local/bin).
+	SharedVersionsDirectory string `json:"shared_versions_directory"`
+
+	// Shell completions and man pages shipped in the archive next to the binary
+	ExtraFiles             ExtraFilesConfig `json:"extra_files"`
+
+	// File capabilities and SELinux context applied to the installed binary
+	PostInstall            PostInstallConfig `json:"post_install"`
+
+	// Check the installed binary's ELF, Mach-O or PE header against the host platform:
+	// "warn" (default), "fail" or "off"
+	ArchitectureCheck      string `json:"architecture_check"`
+
+	// Arguments to run the installed binary with before any symlink is updated, e.g. ["--version"].
+	// The installation fails and is rolled back unless the binary exits successfully within
+	// VerifyTimeout (default: DefaultVerifyTimeout).
+	VerifyCommand          []string      `json:"verify_command"`
+	VerifyTimeout          time.Duration `json:"verify_timeout"`
+
+	// How versions are mapped to directory names: "safe" (default), "strict" or "as-is",
+	// see VersionDirectoryName. Changing it leaves existing version directories unrecognized.
+	VersionDirectoryNaming string `json:"version_directory_naming"`
+
+	// Check that the install directories are writable before downloading: "fail" (default) or
+	// "off". See CheckInstallWritable.
+	ReadOnlyCheck          string `json:"read_only_check"`
+
+	// Context bounding downloads and installations, see WithContext
+	ctx context.Context
+}
+
+// WithContext returns a copy of the configuration whose downloads stop and whose installations are
+// not started once ctx is done, like http.Request.WithContext. Extraction and the VerifyCommand
+// are bounded by the context's deadline as a whole, not interrupted midway.
+func (c FileConfig) WithContext(ctx context.Context) FileConfig {
+	c.ctx = ctx
+	return c
+}
+
+// Context returns the context set with WithContext, or context.Background() if there is none
+func (c FileConfig) Context() context.Context {
+	if c.ctx != nil {
+		return c.ctx
+	}
+	return context.Background()
+}
+
+// defaultInstallMode is the mode of installed binaries and directories before the umask is applied
+const defaultInstallMode os.FileMode = 0755
+
+// maxTempSymlinkAttempts bounds the retries when a temporary symlink name is already taken
+const maxTempSymlinkAttempts = 100
+
+// UsesDirectInstall reports whether the asset is installed as a single file instead of being extracted from an archive
+func (c FileConfig) UsesDirectInstall() bool {
+	return c.IsDirectBinary || c.IsCompressedBinary || c.IsAppImage
 }
 
 // InstallationInfo provides comprehensive information about an installed binary
@@ -37,19 +137,19 @@ type InstallationInfo struct {
 	BinaryPath          string `json:"binary_path"`           // Preferred path to the binary (symlink if available, otherwise versioned path)
 	Version             string `json:"version"`               // Version of the installed binary
 	InstallationType    string `json:"installation_type"`     // "direct_binary" or "extracted_archive"
-	SymlinkStatus       string `json:"symlink_status"`        // "created", "failed", "disabled", "not_attempted"
-	LocalSymlinkPath    string `json:"local_symlink_path"`    // Path to local symlink (if created)
+	SymlinkStatus       string `json:"symlink_status"`        // "created", "copy", "failed", "disabled", "not_attempted"
+	LocalSymlinkPath    string `json:"local_symlink_path"`    // Path to local symlink (if created), or to the copy of the binary if symlinks are unsupported
 	GlobalSymlinkPath   string `json:"global_symlink_path"`   // Path to global symlink (if configured)
 	VersionedPath       string `json:"versioned_path"`        // Path to binary in versioned directory
 	LocalSymlinkCreated bool   `json:"local_symlink_created"` // Whether local symlink was successfully created
 	GlobalSymlinkNeeded bool   `json:"global_symlink_needed"` // Whether global symlink creation was requested
+	AliasPaths          []string `json:"alias_paths,omitempty"` // Alias symlinks pointing at this version
+	Warnings            []string `json:"warnings,omitempty"`  // Non-fatal notes about the installation (e.g. Rosetta fallback)
+	Provenance          *Provenance `json:"provenance,omitempty"` // Where the version was downloaded from, if recorded
 }
 
 // ExtractionConfig configures how binaries are extracted from archives
-type ExtractionConfig struct {
-	StripComponents int    `json:"strip_components"` // Number of directory components to strip (like tar --strip-components)
-	BinaryPath      string `json:"binary_path"`      // Specific path to binary within archive (e.g., "linux-amd64/helm")
-}
+type ExtractionConfig = archiver.ExtractionConfig
 
 // DefaultFileConfig returns a FileConfig with sensible defaults that preserve symlink-first behavior
 func DefaultFileConfig() FileConfig {
@@ -62,8 +162,80 @@ func DefaultFileConfig() FileConfig {
 	}
 }
 
+// GetStagingDirectory returns the directory used for downloads and extraction staging
+func GetStagingDirectory(config FileConfig) string {
+	if config.StagingDirectory != "" {
+		return config.StagingDirectory
+	}
+	return os.TempDir()
+}
+
+// GetBinaryFileMode returns the permissions for installed binaries: BinaryFileMode if set,
+// otherwise 0755 restricted by the process umask (unrestricted for shared installs)
+func GetBinaryFileMode(config FileConfig) os.FileMode {
+	if config.BinaryFileMode != 0 {
+		return config.BinaryFileMode.Perm()
+	}
+	if config.IsSharedInstall() {
+		return sharedInstallMode
+	}
+	return defaultInstallMode &^ currentUmask()
+}
+
+// GetDirectoryMode returns the permissions for version directories: DirectoryMode if set,
+// otherwise 0755 restricted by the process umask (unrestricted for shared installs)
+func GetDirectoryMode(config FileConfig) os.FileMode {
+	if config.DirectoryMode != 0 {
+		return config.DirectoryMode.Perm()
+	}
+	if config.IsSharedInstall() {
+		return sharedInstallMode
+	}
+	return defaultInstallMode &^ currentUmask()
+}
+
+// createVersionDirectory creates the version directory with the configured mode. An explicit
+// DirectoryMode, or the mode of a shared install, is applied with chmod so the umask cannot narrow it.
+func createVersionDirectory(config FileConfig, versionDir string) error {
+	if err := fsys().MkdirAll(versionDir, GetDirectoryMode(config)); err != nil {
+		return fmt.Errorf("failed to create version directory: %w", err)
+	}
+	if config.IsSharedInstall() {
+		if err := fsys().Chmod(sharedProjectDirectory(config), GetDirectoryMode(config)); err != nil {
+			return fmt.Errorf("failed to set shared directory permissions: %w", err)
+		}
+		if err := fsys().MkdirAll(config.BaseBinaryDirectory, 0755); err != nil {
+			return fmt.Errorf("failed to create symlink directory %s: %w", config.BaseBinaryDirectory, err)
+		}
+	}
+	if config.DirectoryMode != 0 || config.IsSharedInstall() {
+		if err := fsys().Chmod(versionDir, GetDirectoryMode(config)); err != nil {
+			return fmt.Errorf("failed to set version directory permissions: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetSourceArchivePath returns SourceArchivePath, or a version-specific file in the staging directory if it is unset
+func GetSourceArchivePath(config FileConfig, version string) string {
+	return GetStagingPath(config, "", version, "")
+}
+
+// GetSourceArchivePathForAsset returns SourceArchivePath, or the asset's file name in the staging
+// directory if it is unset. Keeping the asset's name preserves its extension for format detection.
+// Without a usable asset name the version-specific default of GetSourceArchivePath is returned.
+// See GetStagingPath for how the file name is prefixed.
+func GetSourceArchivePathForAsset(config FileConfig, version, assetName string) string {
+	return GetStagingPath(config, "", version, assetName)
+}
+
 // GetVersionedDirectoryPath returns the path to the versioned directory based on configuration
 func GetVersionedDirectoryPath(config FileConfig, version string) string {
+	version = VersionDirectoryName(config, version)
+	if config.IsSharedInstall() {
+		// Shared pattern: SharedVersionsDirectory/{ProjectName}/{version}/
+		return filepath.Join(sharedProjectDirectory(config), version)
+	}
 	if config.UseVersionsSubdirectory {
 		// New pattern: BaseBinaryDirectory/versions/{ProjectName}/{version}/
 		projectName := config.ProjectName
@@ -86,6 +258,11 @@ func GetVersionedBinaryPath(config FileConfig, version string) string {
 
 // GetSymlinkTargetPath returns the relative path from symlink to target for proper symlink creation
 func GetSymlinkTargetPath(config FileConfig, version string) string {
+	if config.IsSharedInstall() {
+		// Shared versions live outside BaseBinaryDirectory, so the target is absolute
+		return GetVersionedBinaryPath(config, version)
+	}
+	version = VersionDirectoryName(config, version)
 	if config.UseVersionsSubdirectory {
 		// New pattern: versions/{ProjectName}/{version}/{binary}
 		projectName := config.ProjectName
@@ -99,15 +276,39 @@ func GetSymlinkTargetPath(config FileConfig, version string) string {
 	}
 }
 
+// CurrentInstalledVersion returns the version the local symlink currently points to. For a copy
+// made because symlinks are unsupported, the version is taken from the install receipt.
+func CurrentInstalledVersion(config FileConfig) (string, error) {
+	localSymlinkPath := GetLocalSymlinkPath(config)
+	target, err := fsys().Readlink(localSymlinkPath)
+	if err != nil {
+		if receipt, receiptErr := GetInstallReceipt(config); receiptErr == nil && receipt.LocalCopy &&
+			isLocalCopyOf(localSymlinkPath, receipt.VersionedPath) {
+			return receipt.Version, nil
+		}
+		return "", fmt.Errorf("no active installation found at %s: %w", localSymlinkPath, err)
+	}
+
+	// Symlinks point to .../{version}/{binary}, with the version's directory name
+	version := filepath.Base(filepath.Dir(target))
+	if version == "." || version == string(filepath.Separator) {
+		return "", fmt.Errorf("cannot determine version from symlink target %s", target)
+	}
+	if receipt, err := GetInstallReceipt(config); err == nil {
+		version = originalVersion(config, version, append([]string{receipt.Version}, receipt.Versions...))
+	}
+	return version, nil
+}
+
 // GetInstalledBinaryPath returns the preferred path to the installed binary
 // Prefers symlink path when available, falls back to versioned directory path
 func GetInstalledBinaryPath(config FileConfig, version string) (string, error) {
-	localSymlinkPath := filepath.Join(config.BaseBinaryDirectory, config.BinaryName)
+	localSymlinkPath := GetLocalSymlinkPath(config)
 	versionedPath := GetVersionedBinaryPath(config, version)
 
 	// Prefer local symlink if it exists and points to the correct version
 	if config.CreateLocalSymlink && FileExists(localSymlinkPath) {
-		if resolvedPath, err := os.Readlink(localSymlinkPath); err == nil {
+		if resolvedPath, err := fsys().Readlink(localSymlinkPath); err == nil {
 			// For new pattern, resolve relative symlinks
 			if !filepath.IsAbs(resolvedPath) {
 				resolvedPath = filepath.Join(config.BaseBinaryDirectory, resolvedPath)
@@ -115,6 +316,8 @@ func GetInstalledBinaryPath(config FileConfig, version string) (string, error) {
 			if resolvedPath == versionedPath {
 				return localSymlinkPath, nil
 			}
+		} else if isLocalCopyOf(localSymlinkPath, versionedPath) {
+			return localSymlinkPath, nil
 		}
 	}
 
@@ -128,8 +331,8 @@ func GetInstalledBinaryPath(config FileConfig, version string) (string, error) {
 
 // GetInstallationInfo returns comprehensive information about an installed binary
 func GetInstallationInfo(config FileConfig, version string) (*InstallationInfo, error) {
-	localSymlinkPath := filepath.Join(config.BaseBinaryDirectory, config.BinaryName)
-	globalSymlinkPath := filepath.Join("/usr/local/bin", config.BinaryName)
+	localSymlinkPath := GetLocalSymlinkPath(config)
+	globalSymlinkPath := GetGlobalSymlinkPath(config)
 	versionedPath := GetVersionedBinaryPath(config, version)
 
 	info := &InstallationInfo{
@@ -138,10 +341,11 @@ func GetInstallationInfo(config FileConfig, version string) (*InstallationInfo,
 		GlobalSymlinkPath:   globalSymlinkPath,
 		VersionedPath:       versionedPath,
 		GlobalSymlinkNeeded: config.CreateGlobalSymlink,
+		AliasPaths:          linkedAliases(config, versionedPath),
 	}
 
 	// Determine installation type
-	if config.IsDirectBinary {
+	if config.UsesDirectInstall() {
 		info.InstallationType = "direct_binary"
 	} else {
 		info.InstallationType = "extracted_archive"
@@ -150,7 +354,7 @@ func GetInstallationInfo(config FileConfig, version string) (*InstallationInfo,
 	// Check local symlink status
 	if config.CreateLocalSymlink {
 		if FileExists(localSymlinkPath) {
-			if resolvedPath, err := os.Readlink(localSymlinkPath); err == nil {
+			if resolvedPath, err := fsys().Readlink(localSymlinkPath); err == nil {
 				// For new pattern, resolve relative symlinks
 				if !filepath.IsAbs(resolvedPath) {
 					resolvedPath = filepath.Join(config.BaseBinaryDirectory, resolvedPath)
@@ -163,6 +367,9 @@ func GetInstallationInfo(config FileConfig, version string) (*InstallationInfo,
 					info.SymlinkStatus = "failed"
 					info.BinaryPath = versionedPath
 				}
+			} else if isLocalCopyOf(localSymlinkPath, versionedPath) {
+				info.SymlinkStatus = "copy"
+				info.BinaryPath = localSymlinkPath
 			} else {
 				info.SymlinkStatus = "failed"
 				info.BinaryPath = versionedPath
@@ -180,32 +387,23 @@ func GetInstallationInfo(config FileConfig, version string) (*InstallationInfo,
 	if !FileExists(info.BinaryPath) {
 		return nil, fmt.Errorf("binary not found at expected path: %s", info.BinaryPath)
 	}
+	if receipt, err := GetInstallReceipt(config); err == nil {
+		if provenance, ok := receipt.Provenance[version]; ok {
+			info.Provenance = &provenance
+		}
+	}
 
 	return info, nil
 }
 
-// FindBinary searches for a specific binary file in a given directory and its subdirectories.
-// Returns the absolute path to the binary if found, otherwise an error if the binary is not found or an issue occurs.
-func FindBinary(directory, binaryName string) (string, error) {
-	var binaryPath string
-	err := filepath.Walk(directory, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		// Match the binary name
-		if info.Mode().IsRegular() && info.Name() == binaryName {
-			binaryPath = path
-			return filepath.SkipDir // Stop searching once found
-		}
-		return nil
-	})
+// GetActiveInstallationInfo returns information about the active version, the one the local
+// symlink points at
+func GetActiveInstallationInfo(config FileConfig) (*InstallationInfo, error) {
+	version, err := CurrentInstalledVersion(config)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	if binaryPath == "" {
-		return "", fmt.Errorf("binary %s not found in extracted files", binaryName)
-	}
-	return binaryPath, nil
+	return GetInstallationInfo(config, version)
 }
 
 // UpdateSymlink updates the symlink to point to the latest target.
@@ -227,22 +425,21 @@ func UpdateSymlink(target, symlinkPath string) error {
 		return fmt.Errorf("target file does not exist: %s", targetToCheck)
 	}
 
-	// Remove the symlink if it already exists
-	if _, err := os.Lstat(symlinkPath); err == nil {
-		if err := os.Remove(symlinkPath); err != nil {
-			return fmt.Errorf("failed to remove existing symlink: %v", err)
-		}
+	// Create the new symlink next to symlinkPath and rename it over the existing one, so symlinkPath
+	// always points at either the previous or the new target
+	tempPath, err := createTempSymlink(target, symlinkPath)
+	if err != nil {
+		return fmt.Errorf("failed to create symlink: %w", err)
 	}
-
-	// Create the new symlink
-	if err := os.Symlink(target, symlinkPath); err != nil {
-		return fmt.Errorf("failed to create symlink: %v", err)
+	if err := fsys().Rename(tempPath, symlinkPath); err != nil {
+		fsys().Remove(tempPath)
+		return fmt.Errorf("failed to replace existing symlink: %w", err)
 	}
 
 	// Verify the symlink
-	resolvedPath, err := os.Readlink(symlinkPath)
+	resolvedPath, err := fsys().Readlink(symlinkPath)
 	if err != nil {
-		return fmt.Errorf("failed to verify symlink: %v", err)
+		return fmt.Errorf("failed to verify symlink: %w", err)
 	}
 	if resolvedPath != target {
 		return fmt.Errorf("symlink was not set correctly: expected %s, got %s", target, resolvedPath)
@@ -251,6 +448,19 @@ func UpdateSymlink(target, symlinkPath string) error {
 	return nil
 }
 
+// createTempSymlink creates a symlink to target under an unused hidden name next to symlinkPath, so
+// concurrent updates of the same link never replace each other's temporary link
+func createTempSymlink(target, symlinkPath string) (string, error) {
+	for attempt := 0; ; attempt++ {
+		tempPath := filepath.Join(filepath.Dir(symlinkPath),
+			fmt.Sprintf(".%s.%d-%d.tmp-link", filepath.Base(symlinkPath), os.Getpid(), rand.Uint32()))
+		err := createSymlink(target, tempPath)
+		if err == nil || !os.IsExist(err) || attempt >= maxTempSymlinkAttempts {
+			return tempPath, err
+		}
+	}
+}
+
 // TryUpdateSymlink attempts to update a symlink with graceful fallback
 // Returns true if symlink was created successfully, false if it failed
 // Logs warnings for failures but doesn't return errors (graceful fallback)
@@ -271,34 +481,34 @@ func DownloadFile(link string, destination string) error {
 // DownloadFileWithAuth downloads a file from the given URL to the specified path,
 // optionally using a Bearer token for authentication (required for private repos).
 func DownloadFileWithAuth(link string, destination string, token string) error {
-	req, err := http.NewRequest("GET", link, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
+	return DownloadFileWithContext(context.Background(), link, destination, token)
+}
 
-	if token != "" {
-		req.Header.Set("Authorization", "Bearer "+token)
-		req.Header.Set("Accept", "application/octet-stream")
-	}
+// DownloadFileWithContext is DownloadFileWithAuth, stopping the download when ctx is done
+func DownloadFileWithContext(ctx context.Context, link, destination, token string) (err error) {
+	start := time.Now()
+	var written int64
+	defer func() { ObserveDownload("http", start, written, err) }()
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := openDownload(ctx, link, token)
 	if err != nil {
-		return fmt.Errorf("failed to download file: %w", err)
+		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	if err := fsys().MkdirAll(filepath.Dir(destination), 0755); err != nil {
+		return fmt.Errorf("failed to create download directory: %w", err)
 	}
-
-	out, err := os.Create(destination)
+	if err := CheckDiskSpace(filepath.Dir(destination), resp.ContentLength); err != nil {
+		return err
+	}
+	out, err := fsys().Create(destination)
 	if err != nil {
 		return fmt.Errorf("failed to create file: %w", err)
 	}
 	defer out.Close()
 
-	_, err = io.Copy(out, resp.Body)
+	written, err = io.Copy(out, resp.Body)
 	if err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
@@ -306,17 +516,60 @@ func DownloadFileWithAuth(link string, destination string, token string) error {
 	return nil
 }
 
+// openDownload starts a download and returns the response once a 200 OK status is received.
+// The caller must close the response body.
+func openDownload(ctx context.Context, link string, token string) (*http.Response, error) {
+	req, err := newDownloadRequest(ctx, link, token)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := pinnedHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	return resp, nil
+}
+
+// newDownloadRequest builds a GET request for an asset, authenticated when a token is given
+func newDownloadRequest(ctx context.Context, link string, token string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", link, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	SetUserAgent(req)
+
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Accept", "application/octet-stream")
+	}
+	return req, nil
+}
+
 // InstallBinary extracts an archive and installs the binary into a versioned folder with a symlink.
-// If IsDirectBinary is true, it handles direct binary files instead of archives.
+// Direct binaries, compressed binaries and AppImages are installed as single files instead.
 func InstallBinary(fileConfig FileConfig, version string) error {
-	if fileConfig.IsDirectBinary {
+	if fileConfig.UsesDirectInstall() {
+		return InstallDirectBinary(fileConfig, version)
+	}
+	if IsAppImageFile(fileConfig.SourceArchivePath) {
+		fileConfig.IsAppImage = true
 		return InstallDirectBinary(fileConfig, version)
 	}
 	return InstallArchivedBinary(fileConfig, version)
 }
 
 // InstallDirectBinary installs a direct binary file (not archived) into a versioned folder with enhanced symlink control.
-func InstallDirectBinary(fileConfig FileConfig, version string) error {
+// With IsCompressedBinary the file is decompressed instead of copied, and with IsAppImage and
+// StripAppImageUpdateInfo the AppImage's embedded update information is cleared.
+func InstallDirectBinary(fileConfig FileConfig, version string) (err error) {
+	defer observeInstall("direct_binary", time.Now(), &err)
+
 	// Apply defaults for backward compatibility
 	config := fileConfig
 	if config.CreateLocalSymlink == false && config.CreateGlobalSymlink == false {
@@ -325,12 +578,33 @@ func InstallDirectBinary(fileConfig FileConfig, version string) error {
 	}
 
 	versionDir := GetVersionedDirectoryPath(config, version)
-	localSymlinkPath := filepath.Join(config.BaseBinaryDirectory, config.BinaryName)
-	globalSymlinkPath := filepath.Join("/usr/local/bin", config.BinaryName)
+	localSymlinkPath := GetLocalSymlinkPath(config)
+	globalSymlinkPath := GetGlobalSymlinkPath(config)
+
+	if err := validateSymlinkNames(config); err != nil {
+		return err
+	}
+	if err := config.Context().Err(); err != nil {
+		return fmt.Errorf("installation of %s not started: %w", version, err)
+	}
+	if err := CheckInstallWritable(config); err != nil {
+		return err
+	}
 
-	// Step 1: Create version directory
-	if err := os.MkdirAll(versionDir, 0755); err != nil {
-		return fmt.Errorf("failed to create version directory: %v", err)
+	// Shared installs only link the user's symlink when the version is already present
+	if linked, err := linkSharedVersion(config, version); linked || err != nil {
+		return err
+	}
+
+	// Step 1: Create version directory, moving a previous installation of the version aside
+	tx := &installTransaction{}
+	defer tx.finish(&err)
+	if err := tx.backupDirectory(versionDir); err != nil {
+		return err
+	}
+	if err := tx.createDirectories(func() error { return createVersionDirectory(config, versionDir) },
+		versionDir, config.BaseBinaryDirectory); err != nil {
+		return err
 	}
 
 	// Step 2: Install the binary to the versioned folder
@@ -338,29 +612,64 @@ func InstallDirectBinary(fileConfig FileConfig, version string) error {
 	finalBinaryPath := filepath.Join(versionDir, config.BinaryName)
 
 	// Validate that we're not trying to extract a direct binary
-	if !config.IsDirectBinary {
+	if !config.UsesDirectInstall() {
 		return fmt.Errorf("InstallDirectBinary called but IsDirectBinary is false - this indicates a configuration error")
 	}
 
-	// Copy the downloaded binary to the final location
-	if err := copyFile(config.SourceArchivePath, finalBinaryPath); err != nil {
-		return fmt.Errorf("failed to copy binary to versioned directory: %v", err)
+	if config.IsCompressedBinary {
+		// Decompress the downloaded binary to the final location
+		if err := archiver.DecompressFile(config.SourceArchivePath, finalBinaryPath); err != nil {
+			return fmt.Errorf("failed to decompress binary to versioned directory: %w", err)
+		}
+	} else if err := copyFile(config.SourceArchivePath, finalBinaryPath); err != nil {
+		// Copy the downloaded binary to the final location
+		return fmt.Errorf("failed to copy binary to versioned directory: %w", err)
 	}
 
 	// Make the binary executable
-	if err := os.Chmod(finalBinaryPath, 0755); err != nil {
-		return fmt.Errorf("failed to make binary executable: %v", err)
+	if err := fsys().Chmod(finalBinaryPath, GetBinaryFileMode(config)); err != nil {
+		return fmt.Errorf("failed to make binary executable: %w", err)
 	}
 
-	// Step 3: Create/update local symlink (with graceful fallback)
-	localSymlinkCreated := false
+	if config.IsAppImage && config.StripAppImageUpdateInfo {
+		if err := StripAppImageUpdateInfo(finalBinaryPath); err != nil {
+			return err
+		}
+	}
+
+	// Universal (fat) binaries install as-is but must contain the host architecture
+	if err := verifyUniversalBinary(finalBinaryPath, runtime.GOARCH); err != nil {
+		return err
+	}
+
+	// Catch binaries for another platform, e.g. a wrongly selected asset, before the symlink is updated
+	if err := verifyBinaryArchitecture(config, finalBinaryPath); err != nil {
+		return err
+	}
+
+	if err := applyPostInstallAttributes(config, finalBinaryPath); err != nil {
+		return err
+	}
+
+	// Only a binary that passed every check is linked, so the symlinks never point at a broken binary
+	if err := verifyInstalledBinary(config, finalBinaryPath); err != nil {
+		return err
+	}
+
+	// Step 3: Create/update local symlink (falling back to a copy of the binary)
+	localSymlinkStatus := "disabled"
+	var aliases []string
 	if config.CreateLocalSymlink {
 		fmt.Println("Creating local symlink...")
+		if err := tx.saveLinks(append([]string{localSymlinkPath}, GetSymlinkAliasPaths(config)...)...); err != nil {
+			return err
+		}
 		symlinkTarget := GetSymlinkTargetPath(config, version)
-		localSymlinkCreated = TryUpdateSymlink(symlinkTarget, localSymlinkPath)
-		if localSymlinkCreated {
+		localSymlinkStatus = linkLocalBinary(symlinkTarget, finalBinaryPath, localSymlinkPath)
+		if localSymlinkStatus == "created" {
 			fmt.Printf("Local symlink created: %s -> %s\n", localSymlinkPath, symlinkTarget)
 		}
+		aliases = createSymlinkAliases(config, symlinkTarget)
 	} else {
 		fmt.Println("Local symlink creation disabled")
 	}
@@ -368,7 +677,7 @@ func InstallDirectBinary(fileConfig FileConfig, version string) error {
 	// Step 4: Handle global symlink (provide instructions)
 	if config.CreateGlobalSymlink {
 		fmt.Println("Global symlink requested...")
-		if localSymlinkCreated {
+		if localSymlinkStatus == "created" || localSymlinkStatus == "copy" {
 			fmt.Println("To create global symlink, run:")
 			fmt.Printf("sudo ln -s %s %s\n", localSymlinkPath, globalSymlinkPath)
 		} else {
@@ -377,10 +686,15 @@ func InstallDirectBinary(fileConfig FileConfig, version string) error {
 		}
 	}
 
+	recordInstallReceipt(config, version, localSymlinkStatus, aliases, nil)
+
 	fmt.Println("Installation successful!")
 	fmt.Printf("Binary installed at: %s\n", finalBinaryPath)
-	if localSymlinkCreated {
+	switch localSymlinkStatus {
+	case "created":
 		fmt.Printf("Available via symlink: %s\n", localSymlinkPath)
+	case "copy":
+		fmt.Printf("Available via copy: %s\n", localSymlinkPath)
 	}
 
 	return nil
@@ -393,6 +707,53 @@ func InstallArchivedBinary(fileConfig FileConfig, version string) error {
 
 // InstallArchivedBinaryWithConfig extracts an archive with enhanced configuration and installs the binary
 func InstallArchivedBinaryWithConfig(fileConfig FileConfig, version string, extractionConfig *ExtractionConfig) error {
+	return installArchivedBinary(fileConfig, version, extractionConfig,
+		func(handler *archiver.ArchiveHandler, versionDir string, archiverConfig *archiver.ExtractionConfig) error {
+			size, exact := archiveExtractedSize(fileConfig.SourceArchivePath)
+			if err := checkExtractionSpace(versionDir, size, exact); err != nil {
+				return err
+			}
+			fmt.Printf("Extracting %s...\n", fileConfig.SourceArchivePath)
+			return handler.ExtractArchiveWithConfig(fileConfig.SourceArchivePath, versionDir, archiverConfig)
+		})
+}
+
+// StreamInstallArchivedBinary downloads an archive and extracts it while it is being downloaded,
+// so the archive is never written to SourceArchivePath. assetName selects the archive format.
+func StreamInstallArchivedBinary(fileConfig FileConfig, version, link, token, assetName string, extractionConfig *ExtractionConfig) error {
+	return installArchivedBinary(fileConfig, version, extractionConfig,
+		func(handler *archiver.ArchiveHandler, versionDir string, archiverConfig *archiver.ExtractionConfig) (err error) {
+			fmt.Printf("Streaming %s...\n", assetName)
+			start := time.Now()
+			body := &countingReader{}
+			defer func() { ObserveDownload("stream", start, body.n, err) }()
+
+			resp, err := openDownload(fileConfig.Context(), link, token)
+			if err != nil {
+				return err
+			}
+			defer resp.Body.Close()
+			if err := CheckDiskSpace(versionDir, resp.ContentLength); err != nil {
+				return err
+			}
+			if err := checkExtractionSpace(versionDir, extractionSpace(resp.ContentLength), false); err != nil {
+				return err
+			}
+			body.Reader = resp.Body
+			return handler.ExtractStreamWithConfig(assetName, body, versionDir, archiverConfig)
+		})
+}
+
+// CanStreamExtract reports whether an asset can be installed with StreamInstallArchivedBinary
+func CanStreamExtract(assetName string) bool {
+	return archiver.NewArchiveHandler().IsSupported(assetName)
+}
+
+// installArchivedBinary runs extract to populate the versioned directory, then locates the binary and creates symlinks
+func installArchivedBinary(fileConfig FileConfig, version string, extractionConfig *ExtractionConfig,
+	extract func(handler *archiver.ArchiveHandler, versionDir string, archiverConfig *archiver.ExtractionConfig) error) (err error) {
+	defer observeInstall("extracted_archive", time.Now(), &err)
+
 	// Apply defaults for backward compatibility
 	config := fileConfig
 	if config.CreateLocalSymlink == false && config.CreateGlobalSymlink == false {
@@ -401,80 +762,127 @@ func InstallArchivedBinaryWithConfig(fileConfig FileConfig, version string, extr
 	}
 
 	versionDir := GetVersionedDirectoryPath(config, version)
-	localSymlinkPath := filepath.Join(config.BaseBinaryDirectory, config.BinaryName)
-	globalSymlinkPath := filepath.Join("/usr/local/bin", config.BinaryName)
+	localSymlinkPath := GetLocalSymlinkPath(config)
+	globalSymlinkPath := GetGlobalSymlinkPath(config)
 
 	// Validate that we're trying to extract an archive
-	if config.IsDirectBinary {
-		return fmt.Errorf("InstallArchivedBinary called but IsDirectBinary is true - this indicates a configuration error")
+	if config.UsesDirectInstall() {
+		return fmt.Errorf("InstallArchivedBinary called but the configuration describes a single-file asset - this indicates a configuration error")
+	}
+	if err := validateSymlinkNames(config); err != nil {
+		return err
+	}
+	if err := config.Context().Err(); err != nil {
+		return fmt.Errorf("installation of %s not started: %w", version, err)
+	}
+	if err := CheckInstallWritable(config); err != nil {
+		return err
 	}
 
-	// Step 1: Extract the archive with enhanced configuration
-	handler := archiver.NewArchiveHandler()
-	fmt.Printf("Extracting %s...\n", config.SourceArchivePath)
-
-	// Convert our ExtractionConfig to archiver.ExtractionConfig if needed
-	var archiverConfig *archiver.ExtractionConfig
-	if extractionConfig != nil {
-		archiverConfig = &archiver.ExtractionConfig{
-			StripComponents: extractionConfig.StripComponents,
-			BinaryPath:      extractionConfig.BinaryPath,
-		}
+	// Shared installs only link the user's symlink when the version is already present
+	if linked, err := linkSharedVersion(config, version); linked || err != nil {
+		return err
 	}
 
-	if err := handler.ExtractArchiveWithConfig(config.SourceArchivePath, versionDir, archiverConfig); err != nil {
-		return fmt.Errorf("failed to extract archive: %v", err)
+	// Step 1: Extract the archive with enhanced configuration into a new version directory,
+	// moving a previous installation of the version aside
+	tx := &installTransaction{}
+	defer tx.finish(&err)
+	if err := tx.backupDirectory(versionDir); err != nil {
+		return err
+	}
+	if err := tx.createDirectories(func() error { return createVersionDirectory(config, versionDir) },
+		versionDir, config.BaseBinaryDirectory); err != nil {
+		return err
+	}
+	handler := archiver.NewArchiveHandler()
+
+	if err := extract(handler, versionDir, extractionConfig); err != nil {
+		return fmt.Errorf("failed to extract archive: %w", err)
 	}
 
 	// Step 2: Locate the binary file (with enhanced path handling)
 	fmt.Println("Locating the binary...")
 	var binaryPath string
-	var err error
 
 	if extractionConfig != nil && extractionConfig.BinaryPath != "" {
 		// Use specific binary path from extraction config
-		specificPath := extractionConfig.BinaryPath
-		// Replace placeholders in binary path
-		specificPath = strings.ReplaceAll(specificPath, "{os}", runtime.GOOS)
-		// Note: MapArch is in release package, we'll need to handle this differently
-		// For now, use runtime.GOARCH directly
-		specificPath = strings.ReplaceAll(specificPath, "{arch}", runtime.GOARCH)
-
-		binaryPath = filepath.Join(versionDir, specificPath)
-		if !FileExists(binaryPath) {
-			return fmt.Errorf("binary not found at specified path: %s", binaryPath)
+		projectName := config.ProjectName
+		if projectName == "" {
+			projectName = config.BinaryName
+		}
+		binaryPath, err = locateBinaryPath(versionDir, extractionConfig, projectName, version)
+		if err != nil {
+			return err
 		}
 	} else {
 		// Use standard binary finding logic
-		binaryPath, err = FindBinary(versionDir, config.SourceBinaryName)
+		search := BinarySearch{Name: archiver.SanitizeFileName(config.SourceBinaryName)}
+		if config.SourceBinaryPattern != "" {
+			search = BinarySearch{Pattern: config.SourceBinaryPattern}
+		}
+		matches, err := FindBinaries(versionDir, search)
 		if err != nil {
-			return fmt.Errorf("failed to locate binary %s: %v", config.SourceBinaryName, err)
+			return fmt.Errorf("failed to locate binary %s: %v", search.describe(), err)
 		}
+		binaryPath = matches[0]
 	}
 
 	// Step 3: Move the binary to the expected location
 	fmt.Println("Installing the binary...")
 	finalBinaryPath := filepath.Join(versionDir, config.BinaryName)
 	if binaryPath != finalBinaryPath {
-		if err := os.Rename(binaryPath, finalBinaryPath); err != nil {
-			return fmt.Errorf("failed to move binary to versioned directory: %v", err)
+		if err := tx.rename(binaryPath, finalBinaryPath); err != nil {
+			return fmt.Errorf("failed to move binary to versioned directory: %w", err)
 		}
 	}
 
-	// Make the binary executable
-	if err := os.Chmod(finalBinaryPath, 0755); err != nil {
-		return fmt.Errorf("failed to make binary executable: %v", err)
+	// Make the binary executable, keeping a preserved archive mode if it already is
+	preserveMode := false
+	if extractionConfig != nil && extractionConfig.PreservePermissions {
+		if info, err := fsys().Stat(finalBinaryPath); err == nil && info.Mode().Perm()&0111 != 0 {
+			preserveMode = true
+		}
+	}
+	if !preserveMode {
+		if err := fsys().Chmod(finalBinaryPath, GetBinaryFileMode(config)); err != nil {
+			return fmt.Errorf("failed to make binary executable: %w", err)
+		}
+	}
+
+	// Universal (fat) binaries install as-is but must contain the host architecture
+	if err := verifyUniversalBinary(finalBinaryPath, runtime.GOARCH); err != nil {
+		return err
 	}
 
-	// Step 4: Create/update local symlink (with graceful fallback)
-	localSymlinkCreated := false
+	// Catch binaries for another platform, e.g. a wrongly selected asset, before the symlink is updated
+	if err := verifyBinaryArchitecture(config, finalBinaryPath); err != nil {
+		return err
+	}
+
+	if err := applyPostInstallAttributes(config, finalBinaryPath); err != nil {
+		return err
+	}
+
+	// Only a binary that passed every check is linked, so the symlinks never point at a broken binary
+	if err := verifyInstalledBinary(config, finalBinaryPath); err != nil {
+		return err
+	}
+
+	// Step 4: Create/update local symlink (falling back to a copy of the binary)
+	localSymlinkStatus := "disabled"
+	var aliases []string
 	if config.CreateLocalSymlink {
 		fmt.Println("Creating local symlink...")
+		if err := tx.saveLinks(append([]string{localSymlinkPath}, GetSymlinkAliasPaths(config)...)...); err != nil {
+			return err
+		}
 		symlinkTarget := GetSymlinkTargetPath(config, version)
-		localSymlinkCreated = TryUpdateSymlink(symlinkTarget, localSymlinkPath)
-		if localSymlinkCreated {
+		localSymlinkStatus = linkLocalBinary(symlinkTarget, finalBinaryPath, localSymlinkPath)
+		if localSymlinkStatus == "created" {
 			fmt.Printf("Local symlink created: %s -> %s\n", localSymlinkPath, symlinkTarget)
 		}
+		aliases = createSymlinkAliases(config, symlinkTarget)
 	} else {
 		fmt.Println("Local symlink creation disabled")
 	}
@@ -482,7 +890,7 @@ func InstallArchivedBinaryWithConfig(fileConfig FileConfig, version string, extr
 	// Step 5: Handle global symlink (provide instructions)
 	if config.CreateGlobalSymlink {
 		fmt.Println("Global symlink requested...")
-		if localSymlinkCreated {
+		if localSymlinkStatus == "created" || localSymlinkStatus == "copy" {
 			fmt.Println("To create global symlink, run:")
 			fmt.Printf("sudo ln -s %s %s\n", localSymlinkPath, globalSymlinkPath)
 		} else {
@@ -491,18 +899,71 @@ func InstallArchivedBinaryWithConfig(fileConfig FileConfig, version string, extr
 		}
 	}
 
+	// Step 6: Install shell completions and man pages shipped in the archive
+	extraFiles := installExtraFiles(config, versionDir)
+
+	recordInstallReceipt(config, version, localSymlinkStatus, aliases, extraFiles)
+
 	fmt.Println("Installation successful!")
 	fmt.Printf("Binary installed at: %s\n", finalBinaryPath)
-	if localSymlinkCreated {
+	switch localSymlinkStatus {
+	case "created":
 		fmt.Printf("Available via symlink: %s\n", localSymlinkPath)
+	case "copy":
+		fmt.Printf("Available via copy: %s\n", localSymlinkPath)
 	}
 
 	return nil
 }
 
+// InstallFromFile installs a pre-staged archive or binary without any network access.
+// The file at sourcePath is run through the normal extraction/symlink pipeline. Archives
+// are detected by extension, single compressed files (.gz, .xz, .zst) are decompressed,
+// AppImages are detected by content, and anything else is treated as a direct binary.
+func InstallFromFile(fileConfig FileConfig, sourcePath, version string, extractionConfig *ExtractionConfig) error {
+	if version == "" {
+		return fmt.Errorf("version is required to install from file")
+	}
+	if !FileExists(sourcePath) {
+		return fmt.Errorf("source file does not exist: %s", sourcePath)
+	}
+
+	config := fileConfig
+	config.SourceArchivePath = sourcePath
+	if !config.UsesDirectInstall() && !archiver.NewArchiveHandler().IsSupported(sourcePath) {
+		if archiver.IsCompressedFile(sourcePath) {
+			config.IsCompressedBinary = true
+		} else if IsAppImageFile(sourcePath) {
+			config.IsAppImage = true
+		} else {
+			config.IsDirectBinary = true
+		}
+	}
+
+	if config.UsesDirectInstall() {
+		return InstallDirectBinary(config, version)
+	}
+	return InstallArchivedBinaryWithConfig(config, version, extractionConfig)
+}
+
+// expandBinaryPath replaces the {os}, {arch}, {project} and {version} placeholders of the
+// extraction BinaryPath. {arch} is mapped by the config's ArchMapping, defaulting to GOARCH.
+func expandBinaryPath(extractionConfig *ExtractionConfig, project, version string) string {
+	arch := runtime.GOARCH
+	if mapped, ok := extractionConfig.ArchMapping[arch]; ok {
+		arch = mapped
+	}
+	return strings.NewReplacer(
+		"{os}", runtime.GOOS,
+		"{arch}", arch,
+		"{project}", project,
+		"{version}", version,
+	).Replace(extractionConfig.BinaryPath)
+}
+
 // FileExists checks if the given file exists and is not a directory
 func FileExists(path string) bool {
-	info, err := os.Stat(path)
+	info, err := fsys().Stat(path)
 	if os.IsNotExist(err) {
 		return false
 	}
@@ -512,21 +973,21 @@ func FileExists(path string) bool {
 
 // copyFile copies a file from src to dst
 func copyFile(src, dst string) error {
-	sourceFile, err := os.Open(src)
+	sourceFile, err := fsys().Open(src)
 	if err != nil {
-		return fmt.Errorf("failed to open source file: %v", err)
+		return fmt.Errorf("failed to open source file: %w", err)
 	}
 	defer sourceFile.Close()
 
-	destFile, err := os.Create(dst)
+	destFile, err := fsys().Create(dst)
 	if err != nil {
-		return fmt.Errorf("failed to create destination file: %v", err)
+		return fmt.Errorf("failed to create destination file: %w", err)
 	}
 	defer destFile.Close()
 
 	_, err = io.Copy(destFile, sourceFile)
 	if err != nil {
-		return fmt.Errorf("failed to copy file: %v", err)
+		return fmt.Errorf("failed to copy file: %w", err)
 	}
 
 	return nil
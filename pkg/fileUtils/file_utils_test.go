@@ -2,12 +2,23 @@ package fileUtils
 
 import (
 	"archive/tar"
+	"archive/zip"
+	"bytes"
 	"compress/gzip"
+	"errors"
+	"fmt"
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/archiver"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/filesystem"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -347,3 +358,587 @@ func TestInstallBinary(t *testing.T) {
 		})
 	}
 }
+
+func TestInstallFromFile(t *testing.T) {
+	tempDir := t.TempDir()
+	archivePath := path.Join(tempDir, "offline.tar.gz")
+	if err := createTestArchive(archivePath, "binary"); err != nil {
+		t.Fatalf("Failed to create test archive: %v", err)
+	}
+
+	config := FileConfig{
+		BaseBinaryDirectory:    tempDir,
+		VersionedDirectoryName: "offline",
+		SourceBinaryName:       "binary",
+		BinaryName:             "binary",
+		SourceArchivePath:      "/does/not/matter.tar.gz",
+	}
+
+	if err := InstallFromFile(config, archivePath, "1.0.0", nil); err != nil {
+		t.Fatalf("InstallFromFile() error = %v", err)
+	}
+	if !FileExists(GetVersionedBinaryPath(config, "1.0.0")) {
+		t.Errorf("Expected binary to be installed at %s", GetVersionedBinaryPath(config, "1.0.0"))
+	}
+
+	if err := InstallFromFile(config, path.Join(tempDir, "missing.tar.gz"), "1.0.0", nil); err == nil {
+		t.Error("Expected error for missing source file")
+	}
+	if err := InstallFromFile(config, archivePath, "", nil); err == nil {
+		t.Error("Expected error for empty version")
+	}
+}
+
+func TestInstallBinary_CompressedBinary(t *testing.T) {
+	content := []byte("#!/bin/sh\necho compressed\n")
+	compressors := map[string]func(io.Writer) (io.WriteCloser, error){
+		".gz":  func(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil },
+		".xz":  func(w io.Writer) (io.WriteCloser, error) { return xz.NewWriter(w) },
+		".zst": func(w io.Writer) (io.WriteCloser, error) { return zstd.NewWriter(w) },
+	}
+
+	for ext, newWriter := range compressors {
+		t.Run(ext, func(t *testing.T) {
+			tempDir := t.TempDir()
+			var compressed bytes.Buffer
+			writer, err := newWriter(&compressed)
+			if err != nil {
+				t.Fatalf("Failed to create compressor: %v", err)
+			}
+			writer.Write(content)
+			writer.Close()
+
+			// The staged file has a generic name, so the format must be detected from its content
+			sourcePath := filepath.Join(tempDir, "binary-1.0.0.download")
+			if err := os.WriteFile(sourcePath, compressed.Bytes(), 0644); err != nil {
+				t.Fatalf("Failed to write compressed binary: %v", err)
+			}
+
+			config := FileConfig{
+				BaseBinaryDirectory:    tempDir,
+				VersionedDirectoryName: "versions",
+				BinaryName:             "tool",
+				SourceArchivePath:      sourcePath,
+				IsCompressedBinary:     true,
+			}
+			if err := InstallBinary(config, "1.0.0"); err != nil {
+				t.Fatalf("InstallBinary() error = %v", err)
+			}
+
+			installed, err := os.ReadFile(GetVersionedBinaryPath(config, "1.0.0"))
+			if err != nil {
+				t.Fatalf("Expected installed binary: %v", err)
+			}
+			if !bytes.Equal(installed, content) {
+				t.Errorf("Installed binary = %q, want %q", installed, content)
+			}
+			info, _ := os.Stat(GetVersionedBinaryPath(config, "1.0.0"))
+			if info.Mode().Perm()&0100 == 0 {
+				t.Errorf("Expected installed binary to be executable, mode %v", info.Mode())
+			}
+			if installation, _ := GetInstallationInfo(config, "1.0.0"); installation.InstallationType != "direct_binary" {
+				t.Errorf("InstallationType = %q, want direct_binary", installation.InstallationType)
+			}
+		})
+	}
+}
+
+func TestInstallFromFile_DetectsCompressedBinary(t *testing.T) {
+	tempDir := t.TempDir()
+	var compressed bytes.Buffer
+	gzWriter := gzip.NewWriter(&compressed)
+	gzWriter.Write([]byte("binary content"))
+	gzWriter.Close()
+
+	sourcePath := filepath.Join(tempDir, "tool-linux-amd64.gz")
+	if err := os.WriteFile(sourcePath, compressed.Bytes(), 0644); err != nil {
+		t.Fatalf("Failed to write compressed binary: %v", err)
+	}
+
+	config := FileConfig{
+		BaseBinaryDirectory:    tempDir,
+		VersionedDirectoryName: "versions",
+		BinaryName:             "tool",
+	}
+	if err := InstallFromFile(config, sourcePath, "2.0.0", nil); err != nil {
+		t.Fatalf("InstallFromFile() error = %v", err)
+	}
+	installed, _ := os.ReadFile(GetVersionedBinaryPath(config, "2.0.0"))
+	if string(installed) != "binary content" {
+		t.Errorf("Installed binary = %q, want decompressed content", installed)
+	}
+}
+
+// createTestDeb builds a .deb whose xz-compressed data.tar installs the binary to usr/bin
+func createTestDeb(t *testing.T, path, binaryName string, content []byte) {
+	t.Helper()
+	var data bytes.Buffer
+	xzWriter, _ := xz.NewWriter(&data)
+	tarWriter := tar.NewWriter(xzWriter)
+	tarWriter.WriteHeader(&tar.Header{Name: "./usr/bin/", Typeflag: tar.TypeDir, Mode: 0755})
+	tarWriter.WriteHeader(&tar.Header{Name: "./usr/bin/" + binaryName, Typeflag: tar.TypeReg, Mode: 0755, Size: int64(len(content))})
+	tarWriter.Write(content)
+	tarWriter.WriteHeader(&tar.Header{Name: "./usr/share/doc/link", Typeflag: tar.TypeSymlink, Linkname: "../../bin/" + binaryName})
+	tarWriter.Close()
+	xzWriter.Close()
+
+	var deb bytes.Buffer
+	deb.WriteString("!<arch>\n")
+	for _, member := range []struct {
+		name    string
+		content []byte
+	}{
+		{"debian-binary", []byte("2.0\n")},
+		{"control.tar.gz", []byte("ignored")},
+		{"data.tar.xz", data.Bytes()},
+	} {
+		fmt.Fprintf(&deb, "%-16s%-12d%-6d%-6d%-8s%-10d`\n", member.name+"/", 0, 0, 0, "100644", len(member.content))
+		deb.Write(member.content)
+		if len(member.content)%2 == 1 {
+			deb.WriteByte('\n')
+		}
+	}
+	if err := os.WriteFile(path, deb.Bytes(), 0644); err != nil {
+		t.Fatalf("Failed to write deb: %v", err)
+	}
+}
+
+// createTestRpm builds a minimal .rpm with empty headers and a gzip-compressed cpio payload
+func createTestRpm(t *testing.T, path, binaryName string, content []byte) {
+	t.Helper()
+	var cpio bytes.Buffer
+	writeEntry := func(name string, mode int, data []byte) {
+		fmt.Fprintf(&cpio, "070701%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X%08X",
+			0, mode, 0, 0, 1, 0, len(data), 0, 0, 0, 0, len(name)+1, 0)
+		cpio.WriteString(name + "\x00")
+		for cpio.Len()%4 != 0 {
+			cpio.WriteByte(0)
+		}
+		cpio.Write(data)
+		for cpio.Len()%4 != 0 {
+			cpio.WriteByte(0)
+		}
+	}
+	writeEntry("./usr", 0040755, nil)
+	writeEntry("./usr/bin/"+binaryName, 0100755, content)
+	writeEntry("TRAILER!!!", 0, nil)
+
+	var rpm bytes.Buffer
+	lead := make([]byte, 96)
+	copy(lead, []byte{0xed, 0xab, 0xee, 0xdb, 3, 0})
+	rpm.Write(lead)
+	emptyHeader := []byte{0x8e, 0xad, 0xe8, 0x01, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	rpm.Write(emptyHeader) // signature
+	rpm.Write(emptyHeader) // header
+	gzWriter := gzip.NewWriter(&rpm)
+	gzWriter.Write(cpio.Bytes())
+	gzWriter.Close()
+	if err := os.WriteFile(path, rpm.Bytes(), 0644); err != nil {
+		t.Fatalf("Failed to write rpm: %v", err)
+	}
+}
+
+func TestInstallFromFile_Packages(t *testing.T) {
+	content := []byte("packaged binary")
+	packages := map[string]func(*testing.T, string, string, []byte){
+		"tool_1.0.0_amd64.deb":    createTestDeb,
+		"tool-1.0.0-1.x86_64.rpm": createTestRpm,
+		"binary-1.0.0.tar.gz":     createTestDeb, // staged under a generic name, detected by content
+	}
+
+	for name, create := range packages {
+		t.Run(name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			packagePath := filepath.Join(tempDir, name)
+			create(t, packagePath, "tool", content)
+
+			config := FileConfig{
+				BaseBinaryDirectory:    tempDir,
+				VersionedDirectoryName: "versions",
+				SourceBinaryName:       "tool",
+				BinaryName:             "tool",
+			}
+			if err := InstallFromFile(config, packagePath, "1.0.0", nil); err != nil {
+				t.Fatalf("InstallFromFile() error = %v", err)
+			}
+			installed, err := os.ReadFile(GetVersionedBinaryPath(config, "1.0.0"))
+			if err != nil {
+				t.Fatalf("Expected installed binary: %v", err)
+			}
+			if !bytes.Equal(installed, content) {
+				t.Errorf("Installed binary = %q, want %q", installed, content)
+			}
+		})
+	}
+}
+
+func TestStreamInstallArchivedBinary(t *testing.T) {
+	tempDir := t.TempDir()
+	tarPath := path.Join(tempDir, "stream.tar.gz")
+	if err := createTestArchive(tarPath, "binary"); err != nil {
+		t.Fatalf("Failed to create test archive: %v", err)
+	}
+	tarContent, _ := os.ReadFile(tarPath)
+
+	var zipBuffer bytes.Buffer
+	zipWriter := zip.NewWriter(&zipBuffer)
+	entry, _ := zipWriter.Create("dist/binary")
+	entry.Write([]byte("zip binary"))
+	zipWriter.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/asset.tar.gz":
+			w.Write(tarContent)
+		case "/asset.zip":
+			w.Write(zipBuffer.Bytes())
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		assetName string
+		version   string
+	}{
+		{"asset.tar.gz", "1.0.0"},
+		{"asset.zip", "2.0.0"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.assetName, func(t *testing.T) {
+			config := FileConfig{
+				BaseBinaryDirectory:    tempDir,
+				VersionedDirectoryName: "streamed",
+				SourceBinaryName:       "binary",
+				BinaryName:             "binary",
+				SourceArchivePath:      path.Join(tempDir, "should-not-exist-"+tc.assetName),
+			}
+
+			if err := StreamInstallArchivedBinary(config, tc.version, server.URL+"/"+tc.assetName, "", tc.assetName, nil); err != nil {
+				t.Fatalf("StreamInstallArchivedBinary() error = %v", err)
+			}
+			if !FileExists(GetVersionedBinaryPath(config, tc.version)) {
+				t.Errorf("Expected binary to be installed at %s", GetVersionedBinaryPath(config, tc.version))
+			}
+			if FileExists(config.SourceArchivePath) {
+				t.Error("Expected no archive to be written to SourceArchivePath")
+			}
+
+			entries, _ := os.ReadDir(GetVersionedDirectoryPath(config, tc.version))
+			for _, e := range entries {
+				if strings.HasPrefix(e.Name(), ".spool-") {
+					t.Errorf("Expected spool file to be removed, found %s", e.Name())
+				}
+			}
+		})
+	}
+
+	config := FileConfig{BaseBinaryDirectory: tempDir, VersionedDirectoryName: "streamed", BinaryName: "binary"}
+	if err := StreamInstallArchivedBinary(config, "3.0.0", server.URL+"/missing.tar.gz", "", "missing.tar.gz", nil); err == nil {
+		t.Error("Expected error for failed download")
+	}
+}
+
+func TestInstallFromFile_PreservePermissions(t *testing.T) {
+	tempDir := t.TempDir()
+	files := []struct {
+		name string
+		mode os.FileMode
+	}{
+		{"binary", 0750},
+		{"config.json", 0600},
+		{"scripts/run.sh", 0700},
+	}
+
+	zipPath := filepath.Join(tempDir, "asset.zip")
+	var zipBuffer bytes.Buffer
+	zipWriter := zip.NewWriter(&zipBuffer)
+	for _, f := range files {
+		header := &zip.FileHeader{Name: f.name, Method: zip.Deflate}
+		header.SetMode(f.mode)
+		entry, _ := zipWriter.CreateHeader(header)
+		entry.Write([]byte("content of " + f.name))
+	}
+	zipWriter.Close()
+	os.WriteFile(zipPath, zipBuffer.Bytes(), 0644)
+
+	tarPath := filepath.Join(tempDir, "asset.tar.gz")
+	var tarBuffer bytes.Buffer
+	gzipWriter := gzip.NewWriter(&tarBuffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, f := range files {
+		content := []byte("content of " + f.name)
+		tarWriter.WriteHeader(&tar.Header{Name: f.name, Mode: int64(f.mode), Size: int64(len(content)), Typeflag: tar.TypeReg})
+		tarWriter.Write(content)
+	}
+	tarWriter.Close()
+	gzipWriter.Close()
+	os.WriteFile(tarPath, tarBuffer.Bytes(), 0644)
+
+	for i, archivePath := range []string{zipPath, tarPath} {
+		t.Run(filepath.Base(archivePath), func(t *testing.T) {
+			config := FileConfig{
+				BaseBinaryDirectory:    tempDir,
+				VersionedDirectoryName: "preserved",
+				SourceBinaryName:       "binary",
+				BinaryName:             "binary",
+				CreateLocalSymlink:     true,
+			}
+			version := fmt.Sprintf("1.0.%d", i)
+			if err := InstallFromFile(config, archivePath, version, &ExtractionConfig{PreservePermissions: true}); err != nil {
+				t.Fatalf("InstallFromFile() error = %v", err)
+			}
+
+			versionDir := GetVersionedDirectoryPath(config, version)
+			for _, f := range files {
+				info, err := os.Stat(filepath.Join(versionDir, f.name))
+				if err != nil {
+					t.Fatalf("Expected %s to be extracted: %v", f.name, err)
+				}
+				if info.Mode().Perm() != f.mode {
+					t.Errorf("Expected %s to have mode %v, got %v", f.name, f.mode, info.Mode().Perm())
+				}
+			}
+		})
+	}
+}
+
+func TestInstallFromFile_EntryNames(t *testing.T) {
+	tempDir := t.TempDir()
+	// Deeper than MAX_PATH (260 characters) on Windows
+	deepDir := strings.Repeat("nested-directory-name/", 20)
+	// Decomposed names are only normalized to NFC on macOS
+	decomposed := "cafe\u0301-nfd.txt"
+	if runtime.GOOS == "darwin" {
+		decomposed = "caf\u00e9-nfd.txt"
+	}
+	entries := []struct {
+		name     string // Name stored in the archive
+		expected string // Extracted path relative to the version directory
+	}{
+		{deepDir + "tool", deepDir + "tool"},
+		{"cafe\u0301-nfd.txt", decomposed},
+		{"../escape.txt", "escape.txt"},
+	}
+
+	zipPath := filepath.Join(tempDir, "asset.zip")
+	var zipBuffer bytes.Buffer
+	zipWriter := zip.NewWriter(&zipBuffer)
+	for _, name := range append([]string{"caf\x82-cp437.txt"}, entries[0].name, entries[1].name, entries[2].name) {
+		entry, _ := zipWriter.Create(name)
+		entry.Write([]byte("content"))
+	}
+	zipWriter.Close()
+	os.WriteFile(zipPath, zipBuffer.Bytes(), 0644)
+
+	tarPath := filepath.Join(tempDir, "asset.tar.gz")
+	var tarBuffer bytes.Buffer
+	gzipWriter := gzip.NewWriter(&tarBuffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, name := range append([]string{"caf\xe9-latin1.txt"}, entries[0].name, entries[1].name, entries[2].name) {
+		tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: 7, Typeflag: tar.TypeReg, Format: tar.FormatGNU})
+		tarWriter.Write([]byte("content"))
+	}
+	tarWriter.Close()
+	gzipWriter.Close()
+	os.WriteFile(tarPath, tarBuffer.Bytes(), 0644)
+
+	legacyNames := map[string]string{zipPath: "caf\u00e9-cp437.txt", tarPath: "caf\u00e9-latin1.txt"}
+	for i, archivePath := range []string{zipPath, tarPath} {
+		t.Run(filepath.Base(archivePath), func(t *testing.T) {
+			config := FileConfig{
+				BaseBinaryDirectory:    filepath.Join(tempDir, "bin"),
+				VersionedDirectoryName: "versions",
+				BinaryName:             "tool",
+				CreateLocalSymlink:     true,
+			}
+			version := fmt.Sprintf("1.0.%d", i)
+			if err := InstallFromFile(config, archivePath, version, &ExtractionConfig{BinaryPath: entries[0].name}); err != nil {
+				t.Fatalf("InstallFromFile() error = %v", err)
+			}
+
+			versionDir := GetVersionedDirectoryPath(config, version)
+			if !FileExists(filepath.Join(versionDir, "tool")) {
+				t.Error("Expected the deeply nested binary to be installed")
+			}
+			for _, name := range []string{entries[1].expected, entries[2].expected, legacyNames[archivePath]} {
+				if !FileExists(filepath.Join(versionDir, name)) {
+					t.Errorf("Expected %q to be extracted", name)
+				}
+			}
+			if FileExists(filepath.Join(config.BaseBinaryDirectory, "versions", "escape.txt")) {
+				t.Error("Expected entries not to escape the version directory")
+			}
+		})
+	}
+}
+
+func TestInstallFromFile_MemFS(t *testing.T) {
+	mem := filesystem.NewMemFS()
+	filesystem.SetDefault(mem)
+	defer filesystem.SetDefault(nil)
+
+	var archive bytes.Buffer
+	gzipWriter := gzip.NewWriter(&archive)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, name := range []string{"tool_1.0.0/tool", "tool_1.0.0/README.md"} {
+		tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: 7, Typeflag: tar.TypeReg})
+		tarWriter.Write([]byte("content"))
+	}
+	tarWriter.Close()
+	gzipWriter.Close()
+
+	baseDir := filepath.Join(string(filepath.Separator), "opt", "tool")
+	archivePath := filepath.Join(baseDir, "staging", "tool.tar.gz")
+	mem.MkdirAll(filepath.Dir(archivePath), 0755)
+	mem.WriteFile(archivePath, archive.Bytes(), 0644)
+
+	config := FileConfig{
+		BaseBinaryDirectory:    baseDir,
+		VersionedDirectoryName: "versions",
+		BinaryName:             "tool",
+		CreateLocalSymlink:     true,
+	}
+	if err := InstallFromFile(config, archivePath, "1.0.0", &ExtractionConfig{BinaryPath: "tool_1.0.0/tool"}); err != nil {
+		t.Fatalf("InstallFromFile() error = %v", err)
+	}
+
+	data, err := mem.ReadFile(GetVersionedBinaryPath(config, "1.0.0"))
+	if err != nil || string(data) != "content" {
+		t.Errorf("Expected the binary to be installed on the MemFS, got %q, %v", data, err)
+	}
+	info, err := GetInstallationInfo(config, "1.0.0")
+	if err != nil {
+		t.Fatalf("GetInstallationInfo() error = %v", err)
+	}
+	if !info.LocalSymlinkCreated {
+		t.Errorf("Expected the local symlink to be created on the MemFS, status %q", info.SymlinkStatus)
+	}
+	if _, err := os.Lstat(baseDir); !os.IsNotExist(err) {
+		t.Errorf("Expected nothing to be written to disk, Lstat(%s) error = %v", baseDir, err)
+	}
+}
+
+func TestInstallFromFile_ExtractionFilters(t *testing.T) {
+	tempDir := t.TempDir()
+	archivePath := filepath.Join(tempDir, "tool.tar.gz")
+	createTestArchiveWithFiles(t, archivePath, map[string]string{
+		"tool":           "binary",
+		"docs/guide.txt": strings.Repeat("x", 4096),
+	})
+	config := FileConfig{
+		BaseBinaryDirectory:    filepath.Join(tempDir, "bin"),
+		VersionedDirectoryName: "versions",
+		BinaryName:             "tool",
+		SourceBinaryName:       "tool",
+		CreateLocalSymlink:     true,
+	}
+
+	err := InstallFromFile(config, archivePath, "1.0.0", &ExtractionConfig{MaxTotalSize: 1024})
+	if !errors.Is(err, archiver.ErrArchiveTooLarge) {
+		t.Fatalf("InstallFromFile() error = %v, want archiver.ErrArchiveTooLarge", err)
+	}
+	if _, err := os.Stat(GetVersionedDirectoryPath(config, "1.0.0")); !os.IsNotExist(err) {
+		t.Errorf("Expected the oversized extraction to be rolled back, Stat() error = %v", err)
+	}
+
+	// Skipped entries do not count towards the limit
+	if err := InstallFromFile(config, archivePath, "1.0.0", &ExtractionConfig{MaxTotalSize: 1024, Exclude: []string{"docs"}}); err != nil {
+		t.Fatalf("InstallFromFile() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(GetVersionedDirectoryPath(config, "1.0.0"), "docs")); !os.IsNotExist(err) {
+		t.Errorf("Expected docs to be excluded, Stat() error = %v", err)
+	}
+}
+
+func TestGetSourceArchivePath(t *testing.T) {
+	config := FileConfig{}
+	if GetStagingDirectory(config) != os.TempDir() {
+		t.Errorf("Expected default staging directory %s, got %s", os.TempDir(), GetStagingDirectory(config))
+	}
+	if got := GetSourceArchivePath(config, "1.0.0"); got != filepath.Join(os.TempDir(), "binary-1.0.0.tar.gz") {
+		t.Errorf("Unexpected default source archive path: %s", got)
+	}
+
+	config.StagingDirectory = "/var/lib/updater/staging"
+	if got := GetSourceArchivePath(config, "1.0.0"); got != filepath.Join("/var/lib/updater/staging", "binary-1.0.0.tar.gz") {
+		t.Errorf("Expected source archive in staging directory, got %s", got)
+	}
+
+	config.SourceArchivePath = "/explicit/archive.tar.gz"
+	if got := GetSourceArchivePath(config, "1.0.0"); got != "/explicit/archive.tar.gz" {
+		t.Errorf("Expected explicit SourceArchivePath to win, got %s", got)
+	}
+}
+
+func TestGetSourceArchivePathForAsset(t *testing.T) {
+	config := FileConfig{StagingDirectory: "/var/lib/updater/staging"}
+	testCases := []struct {
+		assetName string
+		expected  string
+	}{
+		{"tool_Linux_x86_64.zip", "/var/lib/updater/staging/tool_Linux_x86_64.zip"},
+		{"nested/dir/tool.AppImage", "/var/lib/updater/staging/tool.AppImage"},
+		{`..\..\tool.exe`, "/var/lib/updater/staging/tool.exe"},
+		{"", "/var/lib/updater/staging/binary-1.0.0.tar.gz"},
+		{"..", "/var/lib/updater/staging/binary-1.0.0.tar.gz"},
+	}
+	for _, tc := range testCases {
+		if got := GetSourceArchivePathForAsset(config, "1.0.0", tc.assetName); got != tc.expected {
+			t.Errorf("GetSourceArchivePathForAsset(%q) = %s, want %s", tc.assetName, got, tc.expected)
+		}
+	}
+
+	config.SourceArchivePath = "/explicit/archive.tar.gz"
+	if got := GetSourceArchivePathForAsset(config, "1.0.0", "tool.zip"); got != "/explicit/archive.tar.gz" {
+		t.Errorf("Expected explicit SourceArchivePath to win, got %s", got)
+	}
+}
+
+func TestInstallBinary_FileModes(t *testing.T) {
+	tempDir := t.TempDir()
+	sourcePath := filepath.Join(tempDir, "download")
+	if err := os.WriteFile(sourcePath, []byte("binary"), 0644); err != nil {
+		t.Fatalf("Failed to write binary: %v", err)
+	}
+
+	config := FileConfig{
+		BaseBinaryDirectory:    tempDir,
+		VersionedDirectoryName: "versions",
+		BinaryName:             "tool",
+		SourceArchivePath:      sourcePath,
+		IsDirectBinary:         true,
+		BinaryFileMode:         0750,
+		DirectoryMode:          0700,
+	}
+	if err := InstallBinary(config, "1.0.0"); err != nil {
+		t.Fatalf("InstallBinary() error = %v", err)
+	}
+
+	binaryInfo, err := os.Stat(GetVersionedBinaryPath(config, "1.0.0"))
+	if err != nil {
+		t.Fatalf("Expected installed binary: %v", err)
+	}
+	if binaryInfo.Mode().Perm() != 0750 {
+		t.Errorf("Binary mode = %v, want 0750", binaryInfo.Mode().Perm())
+	}
+	dirInfo, _ := os.Stat(GetVersionedDirectoryPath(config, "1.0.0"))
+	if dirInfo.Mode().Perm() != 0700 {
+		t.Errorf("Directory mode = %v, want 0700", dirInfo.Mode().Perm())
+	}
+}
+
+func TestGetBinaryFileMode_Default(t *testing.T) {
+	mode := GetBinaryFileMode(FileConfig{})
+	if mode&0700 != 0700 {
+		t.Errorf("Default binary mode %v must be executable by the owner", mode)
+	}
+	if mode&^0755 != 0 {
+		t.Errorf("Default binary mode %v must not exceed 0755", mode)
+	}
+	if mode != GetDirectoryMode(FileConfig{}) {
+		t.Errorf("Default binary and directory modes differ: %v vs %v", mode, GetDirectoryMode(FileConfig{}))
+	}
+}
@@ -0,0 +1,202 @@
+package fileUtils
+
+import (
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/archiver"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/filesystem"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// BinarySearch describes which files FindBinaries matches. Name, Pattern and Regex are combined:
+// a file must satisfy every criterion that is set, and at least one must be set.
+type BinarySearch struct {
+	Name       string // Exact file name; on Windows "name.exe" matches as well
+	Pattern    string // Glob matched against file names, e.g. "helm-v3*" (path.Match syntax)
+	Regex      string // Regular expression matched against file names, e.g. `^kubectl(\.exe)?$`
+	MaxDepth   int    // Directory levels to search, 1 for only the top directory (default: unlimited)
+	Executable bool   // Only match files with an executable bit, or an executable extension on Windows
+	All        bool   // Return every match instead of stopping at the first
+}
+
+// windowsExecutableExtensions are the extensions Windows runs without an executable bit
+var windowsExecutableExtensions = []string{".exe", ".bat", ".cmd", ".com", ".ps1"}
+
+// FindBinary searches for a specific binary file in a given directory and its subdirectories.
+// Returns the path to the first match in lexical walk order, or an error if the binary is not found or an issue occurs.
+func FindBinary(directory, binaryName string) (string, error) {
+	matches, err := FindBinaries(directory, BinarySearch{Name: binaryName})
+	if err != nil {
+		return "", err
+	}
+	return matches[0], nil
+}
+
+// FindBinaries searches a directory and its subdirectories for regular files matching the search.
+// Without search.All the walk ends at the first match. Returns an error if nothing matches.
+func FindBinaries(directory string, search BinarySearch) ([]string, error) {
+	if search.Name == "" && search.Pattern == "" && search.Regex == "" {
+		return nil, fmt.Errorf("binary search requires a name, pattern or regex")
+	}
+	if search.Pattern != "" {
+		if _, err := filepath.Match(search.Pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid binary pattern %q: %v", search.Pattern, err)
+		}
+	}
+	var regex *regexp.Regexp
+	if search.Regex != "" {
+		var err error
+		if regex, err = regexp.Compile(search.Regex); err != nil {
+			return nil, fmt.Errorf("invalid binary regex %q: %v", search.Regex, err)
+		}
+	}
+
+	root := filepath.Clean(directory)
+	var matches []string
+	err := filesystem.WalkDir(fsys(), root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		depth := 0
+		if path != root {
+			depth = strings.Count(path[len(root):], string(filepath.Separator))
+		}
+		if entry.IsDir() {
+			if search.MaxDepth > 0 && depth >= search.MaxDepth {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() || !search.matches(entry.Name(), regex) {
+			return nil
+		}
+		if search.Executable && !isExecutableEntry(entry) {
+			return nil
+		}
+
+		matches = append(matches, path)
+		if !search.All {
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("binary %s not found in extracted files", search.describe())
+	}
+	return matches, nil
+}
+
+// matches reports whether a file name satisfies the name, pattern and regex of the search
+func (s BinarySearch) matches(name string, regex *regexp.Regexp) bool {
+	if s.Name != "" && name != s.Name && !(runtime.GOOS == "windows" && strings.EqualFold(name, s.Name+".exe")) {
+		return false
+	}
+	if s.Pattern != "" {
+		if ok, _ := filepath.Match(s.Pattern, name); !ok {
+			return false
+		}
+	}
+	return regex == nil || regex.MatchString(name)
+}
+
+// describe returns the search criteria for error messages
+func (s BinarySearch) describe() string {
+	var parts []string
+	if s.Name != "" {
+		parts = append(parts, s.Name)
+	}
+	if s.Pattern != "" {
+		parts = append(parts, "matching "+s.Pattern)
+	}
+	if s.Regex != "" {
+		parts = append(parts, "matching /"+s.Regex+"/")
+	}
+	return strings.Join(parts, " ")
+}
+
+// isExecutableEntry reports whether a file can be executed: an executable bit on Unix, an
+// executable extension on Windows
+func isExecutableEntry(entry fs.DirEntry) bool {
+	if runtime.GOOS == "windows" {
+		return containsString(windowsExecutableExtensions, strings.ToLower(filepath.Ext(entry.Name())))
+	}
+	info, err := entry.Info()
+	return err == nil && info.Mode().Perm()&0111 != 0
+}
+
+// locateBinaryPath resolves the extraction BinaryPath below the version directory. The path is
+// tried with {version} as given and without a "v" prefix, since archives commonly nest binaries
+// under e.g. tool-1.2.3/ for the release v1.2.3.
+func locateBinaryPath(versionDir string, extractionConfig *ExtractionConfig, project, version string) (string, error) {
+	var candidates []string
+	for _, v := range []string{version, strings.TrimPrefix(version, "v")} {
+		candidate := expandBinaryPath(extractionConfig, project, v)
+		if len(candidates) == 0 || candidates[0] != candidate {
+			candidates = append(candidates, candidate)
+		}
+	}
+	for _, candidate := range candidates {
+		matches, err := matchBinaryPath(versionDir, candidate)
+		if err != nil {
+			return "", err
+		}
+		switch len(matches) {
+		case 0:
+			continue
+		case 1:
+			return matches[0], nil
+		default:
+			return "", fmt.Errorf("binary path %q matches %d files, make it more specific: %s",
+				candidate, len(matches), strings.Join(matches, ", "))
+		}
+	}
+	return "", fmt.Errorf("binary not found at specified path: %s", filepath.Join(versionDir, candidates[0]))
+}
+
+// matchBinaryPath returns the regular files below directory matching the slash-separated path.
+// Components containing *, ? or [ are filepath.Match patterns, e.g. "*/bin/tool"; the other
+// components are sanitized like extracted entry names.
+func matchBinaryPath(directory, binaryPath string) ([]string, error) {
+	paths := []string{directory}
+	for _, component := range strings.Split(filepath.ToSlash(binaryPath), "/") {
+		if component == "" || component == "." {
+			continue
+		}
+		if !strings.ContainsAny(component, "*?[") {
+			for i := range paths {
+				paths[i] = filepath.Join(paths[i], archiver.SanitizePath(component))
+			}
+			continue
+		}
+		if _, err := filepath.Match(component, ""); err != nil {
+			return nil, fmt.Errorf("invalid binary path pattern %q: %v", binaryPath, err)
+		}
+		var next []string
+		for _, parent := range paths {
+			entries, err := fsys().ReadDir(parent)
+			if err != nil {
+				continue // Not a directory, so nothing below it matches
+			}
+			for _, entry := range entries {
+				if matched, _ := filepath.Match(component, entry.Name()); matched {
+					next = append(next, filepath.Join(parent, entry.Name()))
+				}
+			}
+		}
+		paths = next
+	}
+
+	var matches []string
+	for _, path := range paths {
+		if FileExists(path) {
+			matches = append(matches, path)
+		}
+	}
+	return matches, nil
+}
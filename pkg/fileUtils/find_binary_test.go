@@ -0,0 +1,131 @@
+package fileUtils
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestFindBinaries(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]os.FileMode{
+		"README.md":                0644,
+		"a/helm-v3":                0755,
+		"a/b/helm-v3.1":            0755,
+		"a/b/c/helm":               0755,
+		"docs/helm-v3.md":          0644,
+		"linux-amd64/kubectl":      0755,
+		"linux-amd64/kubectl.sha1": 0644,
+	}
+	for name, mode := range files {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("content"), mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rel := func(paths ...string) []string {
+		for i, path := range paths {
+			paths[i] = filepath.Join(tmpDir, path)
+		}
+		return paths
+	}
+
+	tests := []struct {
+		name    string
+		search  BinarySearch
+		want    []string
+		wantErr bool
+	}{
+		{"first glob match stops the walk", BinarySearch{Pattern: "helm-v3*"}, rel("a/b/helm-v3.1"), false},
+		{"all glob matches", BinarySearch{Pattern: "helm-v3*", All: true}, rel("a/b/helm-v3.1", "a/helm-v3", "docs/helm-v3.md"), false},
+		{"executable only", BinarySearch{Pattern: "helm*", Executable: true, All: true}, rel("a/b/c/helm", "a/b/helm-v3.1", "a/helm-v3"), false},
+		{"regex", BinarySearch{Regex: `^kubectl(\.exe)?$`}, rel("linux-amd64/kubectl"), false},
+		{"depth limit", BinarySearch{Pattern: "helm*", MaxDepth: 2, All: true}, rel("a/helm-v3", "docs/helm-v3.md"), false},
+		{"top directory only", BinarySearch{Name: "kubectl", MaxDepth: 1}, nil, true},
+		{"name and pattern combined", BinarySearch{Name: "helm", Pattern: "h*", All: true}, rel("a/b/c/helm"), false},
+		{"no criteria", BinarySearch{}, nil, true},
+		{"invalid regex", BinarySearch{Regex: "("}, nil, true},
+		{"invalid glob", BinarySearch{Pattern: "["}, nil, true},
+	}
+	if runtime.GOOS == "windows" {
+		// Executable bits do not exist on Windows
+		tests = tests[:2]
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FindBinaries(tmpDir, tt.search)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FindBinaries() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindBinaries() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindBinary_WindowsExtension(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		t.Skip("the .exe suffix is only matched on Windows")
+	}
+	tmpDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tmpDir, "kubectl.exe"), []byte("MZ"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if path, err := FindBinary(tmpDir, "kubectl"); err != nil || filepath.Base(path) != "kubectl.exe" {
+		t.Errorf("FindBinary() = %s, %v", path, err)
+	}
+}
+
+func TestLocateBinaryPath(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, name := range []string{
+		"tool-1.2.3/bin/tool",
+		"tool-1.2.3/README.md",
+		runtime.GOOS + "-" + runtime.GOARCH + "/tool",
+		"dup/a/tool",
+		"dup/b/tool",
+	} {
+		path := filepath.Join(tmpDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("content"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		binaryPath string
+		version    string
+		want       string
+		wantErr    bool
+	}{
+		{"project and version", "{project}-{version}/bin/tool", "1.2.3", "tool-1.2.3/bin/tool", false},
+		{"version without v prefix", "{project}-{version}/bin/tool", "v1.2.3", "tool-1.2.3/bin/tool", false},
+		{"os and arch", "{os}-{arch}/{project}", "1.2.3", runtime.GOOS + "-" + runtime.GOARCH + "/tool", false},
+		{"glob directory", "*/bin/tool", "1.2.3", "tool-1.2.3/bin/tool", false},
+		{"glob file name", "tool-1.2.3/bin/t??l", "1.2.3", "tool-1.2.3/bin/tool", false},
+		{"ambiguous glob", "dup/*/tool", "1.2.3", "", true},
+		{"missing version", "{project}-{version}/bin/tool", "2.0.0", "", true},
+		{"invalid glob", "[/tool", "1.2.3", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &ExtractionConfig{BinaryPath: tt.binaryPath}
+			got, err := locateBinaryPath(tmpDir, config, "tool", tt.version)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("locateBinaryPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != filepath.Join(tmpDir, tt.want) {
+				t.Errorf("locateBinaryPath() = %s, want %s", got, filepath.Join(tmpDir, tt.want))
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package fileUtils
+
+import (
+	"io"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Metric names reported to Metrics implementations
+const (
+	MetricDownloadBytes    = "download_bytes"    // Counter: bytes downloaded (label "source")
+	MetricDownloadDuration = "download_duration" // Duration: completed downloads (label "source")
+	MetricDownloadFailures = "download_failures" // Counter: failed downloads (label "source")
+	MetricHTTPRetries      = "http_retries"      // Counter: retried HTTP requests (label "host")
+	MetricHTTPFailures     = "http_failures"     // Counter: failed HTTP attempts, including 429 and 5xx responses (label "host")
+	MetricInstallDuration  = "install_duration"  // Duration: completed installations (label "type")
+	MetricInstallFailures  = "install_failures"  // Counter: failed installations (label "type")
+)
+
+// Metrics receives telemetry from downloads, HTTP retries and installations, so applications
+// embedding the library can forward them to Prometheus, OpenTelemetry or similar systems.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	Counter(name string, value int64, labels map[string]string)             // Add value to the named counter
+	Duration(name string, duration time.Duration, labels map[string]string) // Record how long the named operation took
+}
+
+// NopMetrics discards all metrics
+type NopMetrics struct{}
+
+func (NopMetrics) Counter(string, int64, map[string]string)          {}
+func (NopMetrics) Duration(string, time.Duration, map[string]string) {}
+
+var (
+	metricsMu sync.RWMutex
+	metrics   Metrics = NopMetrics{}
+)
+
+// SetMetrics installs the Metrics implementation used by the library; nil disables metrics
+func SetMetrics(m Metrics) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	if m == nil {
+		m = NopMetrics{}
+	}
+	metrics = m
+}
+
+// GetMetrics returns the configured Metrics implementation
+func GetMetrics() Metrics {
+	metricsMu.RLock()
+	defer metricsMu.RUnlock()
+	return metrics
+}
+
+// ObserveDownload reports the size and duration of a download from source ("http", "chunked",
+// "stream" or "cdn"), or a failure if err is non-nil
+func ObserveDownload(source string, start time.Time, bytes int64, err error) {
+	labels := map[string]string{"source": source}
+	m := GetMetrics()
+	if bytes > 0 {
+		m.Counter(MetricDownloadBytes, bytes, labels)
+	}
+	if err != nil {
+		m.Counter(MetricDownloadFailures, 1, labels)
+		return
+	}
+	m.Duration(MetricDownloadDuration, time.Since(start), labels)
+}
+
+// observeInstall reports the duration of an installation, or a failure if *err is non-nil
+func observeInstall(installationType string, start time.Time, err *error) {
+	labels := map[string]string{"type": installationType}
+	if *err != nil {
+		GetMetrics().Counter(MetricInstallFailures, 1, labels)
+		return
+	}
+	GetMetrics().Duration(MetricInstallDuration, time.Since(start), labels)
+}
+
+// hostLabels returns the labels identifying the host of a URL
+func hostLabels(link string) map[string]string {
+	host := link
+	if parsed, err := url.Parse(link); err == nil && parsed.Host != "" {
+		host = parsed.Host
+	}
+	return map[string]string{"host": host}
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,76 @@
+package fileUtils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingMetrics collects counters and durations by metric name
+type recordingMetrics struct {
+	mu        sync.Mutex
+	counters  map[string]int64
+	durations map[string]int
+}
+
+func newRecordingMetrics() *recordingMetrics {
+	return &recordingMetrics{counters: make(map[string]int64), durations: make(map[string]int)}
+}
+
+func (m *recordingMetrics) Counter(name string, value int64, labels map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counters[name] += value
+}
+
+func (m *recordingMetrics) Duration(name string, duration time.Duration, labels map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.durations[name]++
+}
+
+func TestMetrics_DownloadAndInstall(t *testing.T) {
+	metrics := newRecordingMetrics()
+	SetMetrics(metrics)
+	defer SetMetrics(nil)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("binary content"))
+	}))
+	defer server.Close()
+
+	tempDir := t.TempDir()
+	download := filepath.Join(tempDir, "download")
+	if err := DownloadFile(server.URL+"/asset", download); err != nil {
+		t.Fatalf("DownloadFile() error = %v", err)
+	}
+	if err := DownloadFile(server.URL+"/missing", filepath.Join(tempDir, "missing")); err == nil {
+		t.Fatal("Expected error for missing asset")
+	}
+
+	config := FileConfig{BaseBinaryDirectory: tempDir, VersionedDirectoryName: "versions", BinaryName: "tool", IsDirectBinary: true}
+	if err := InstallFromFile(config, download, "v1.0.0", nil); err != nil {
+		t.Fatalf("InstallFromFile() error = %v", err)
+	}
+	config.IsDirectBinary = false
+	if err := InstallArchivedBinary(config, "v1.0.1"); err == nil {
+		t.Fatal("Expected error when the archive is missing")
+	}
+
+	if got := metrics.counters[MetricDownloadBytes]; got != int64(len("binary content")) {
+		t.Errorf("Expected %d downloaded bytes, got %d", len("binary content"), got)
+	}
+	if metrics.durations[MetricDownloadDuration] != 1 || metrics.counters[MetricDownloadFailures] != 1 {
+		t.Errorf("Expected one completed and one failed download, got %v / %v", metrics.durations, metrics.counters)
+	}
+	if metrics.durations[MetricInstallDuration] != 1 || metrics.counters[MetricInstallFailures] != 1 {
+		t.Errorf("Expected one completed and one failed installation, got %v / %v", metrics.durations, metrics.counters)
+	}
+}
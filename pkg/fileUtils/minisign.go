@@ -0,0 +1,254 @@
+package fileUtils
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"golang.org/x/crypto/blake2b"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// MinisignSignatureSuffix is appended to a file's path to locate its minisign signature
+const MinisignSignatureSuffix = ".minisig"
+
+// ErrInvalidSignature is returned (wrapped) when a minisign signature does not verify
+var ErrInvalidSignature = NewError(ErrorCodeChecksum, "invalid signature")
+
+const (
+	minisignUntrustedPrefix = "untrusted comment: "
+	minisignTrustedPrefix   = "trusted comment: "
+)
+
+var (
+	minisignAlgorithm         = []byte("Ed") // Ed25519 over the message
+	minisignHashedAlgorithm   = []byte("ED") // Ed25519 over the BLAKE2b-512 hash of the message (minisign's default)
+	minisignChecksumAlgorithm = []byte("B2")
+)
+
+// MinisignPublicKey verifies signatures made with minisign (https://jedisct1.github.io/minisign/)
+// or MinisignPrivateKey
+type MinisignPublicKey struct {
+	KeyID [8]byte
+	Key   ed25519.PublicKey
+}
+
+// MinisignPrivateKey signs files in minisign's format. Keys are stored unencrypted, like
+// `minisign -G -W`; password-protected minisign keys are not supported.
+type MinisignPrivateKey struct {
+	KeyID [8]byte
+	Key   ed25519.PrivateKey
+}
+
+// GenerateMinisignKey creates a new signing key with a random key ID
+func GenerateMinisignKey() (*MinisignPrivateKey, error) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %v", err)
+	}
+	key := &MinisignPrivateKey{Key: private}
+	if _, err := rand.Read(key.KeyID[:]); err != nil {
+		return nil, fmt.Errorf("failed to generate key ID: %v", err)
+	}
+	return key, nil
+}
+
+// Public returns the public key for verifying the key's signatures
+func (k *MinisignPrivateKey) Public() *MinisignPublicKey {
+	return &MinisignPublicKey{KeyID: k.KeyID, Key: k.Key.Public().(ed25519.PublicKey)}
+}
+
+// keyIDString formats a key ID the way minisign prints it
+func keyIDString(id [8]byte) string {
+	return fmt.Sprintf("%016X", binary.LittleEndian.Uint64(id[:]))
+}
+
+// String returns the key in minisign's public key file format
+func (k *MinisignPublicKey) String() string {
+	data := append(append(append([]byte{}, minisignAlgorithm...), k.KeyID[:]...), k.Key...)
+	return minisignUntrustedPrefix + "minisign public key " + keyIDString(k.KeyID) + "\n" +
+		base64.StdEncoding.EncodeToString(data) + "\n"
+}
+
+// String returns the key in minisign's unencrypted secret key file format
+func (k *MinisignPrivateKey) String() string {
+	var data bytes.Buffer
+	data.Write(minisignAlgorithm)
+	data.Write([]byte{0, 0}) // No key derivation: the key is not encrypted
+	data.Write(minisignChecksumAlgorithm)
+	data.Write(make([]byte, 32+8+8)) // Unused salt, opslimit and memlimit
+	data.Write(k.KeyID[:])
+	data.Write(k.Key)
+	data.Write(k.checksum())
+	return minisignUntrustedPrefix + "minisign secret key " + keyIDString(k.KeyID) + "\n" +
+		base64.StdEncoding.EncodeToString(data.Bytes()) + "\n"
+}
+
+// checksum is the BLAKE2b-256 checksum minisign stores with secret keys
+func (k *MinisignPrivateKey) checksum() []byte {
+	sum := blake2b.Sum256(append(append(append([]byte{}, minisignAlgorithm...), k.KeyID[:]...), k.Key...))
+	return sum[:]
+}
+
+// minisignKeyData decodes the base64 line of a minisign key file. A bare base64 key, as passed
+// to `minisign -P`, is accepted as well.
+func minisignKeyData(text string) ([]byte, error) {
+	var encoded string
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, minisignUntrustedPrefix) {
+			encoded = line
+			break
+		}
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || encoded == "" {
+		return nil, fmt.Errorf("invalid minisign key encoding")
+	}
+	return data, nil
+}
+
+// ParseMinisignPublicKey parses a minisign public key file or its base64 key line
+func ParseMinisignPublicKey(text string) (*MinisignPublicKey, error) {
+	data, err := minisignKeyData(text)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != 2+8+ed25519.PublicKeySize || !bytes.Equal(data[:2], minisignAlgorithm) {
+		return nil, fmt.Errorf("unsupported minisign public key")
+	}
+	key := &MinisignPublicKey{Key: ed25519.PublicKey(data[10:])}
+	copy(key.KeyID[:], data[2:10])
+	return key, nil
+}
+
+// ParseMinisignPrivateKey parses an unencrypted minisign secret key file
+func ParseMinisignPrivateKey(text string) (*MinisignPrivateKey, error) {
+	data, err := minisignKeyData(text)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != 2+2+2+32+8+8+8+ed25519.PrivateKeySize+32 || !bytes.Equal(data[:2], minisignAlgorithm) ||
+		!bytes.Equal(data[4:6], minisignChecksumAlgorithm) {
+		return nil, fmt.Errorf("unsupported minisign secret key")
+	}
+	if data[2] != 0 || data[3] != 0 {
+		return nil, fmt.Errorf("encrypted minisign secret keys are not supported; create the key with `minisign -G -W`")
+	}
+	key := &MinisignPrivateKey{Key: ed25519.PrivateKey(bytes.Clone(data[62:126]))}
+	copy(key.KeyID[:], data[54:62])
+	if !bytes.Equal(key.checksum(), data[126:]) {
+		return nil, fmt.Errorf("minisign secret key checksum mismatch")
+	}
+	return key, nil
+}
+
+// ReadMinisignPublicKey reads a minisign public key file
+func ReadMinisignPublicKey(path string) (*MinisignPublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read public key: %v", err)
+	}
+	return ParseMinisignPublicKey(string(data))
+}
+
+// ReadMinisignPrivateKey reads an unencrypted minisign secret key file
+func ReadMinisignPrivateKey(path string) (*MinisignPrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret key: %v", err)
+	}
+	return ParseMinisignPrivateKey(string(data))
+}
+
+// Sign returns a prehashed minisign signature of data. An empty trusted comment defaults to the
+// signing time, like minisign's.
+func (k *MinisignPrivateKey) Sign(data []byte, trustedComment string) []byte {
+	if trustedComment == "" {
+		trustedComment = fmt.Sprintf("timestamp:%d", time.Now().Unix())
+	}
+	prehashed := blake2b.Sum512(data)
+	signature := ed25519.Sign(k.Key, prehashed[:])
+	globalSignature := ed25519.Sign(k.Key, append(append([]byte{}, signature...), trustedComment...))
+
+	encoded := append(append(append([]byte{}, minisignHashedAlgorithm...), k.KeyID[:]...), signature...)
+	return []byte(minisignUntrustedPrefix + "signature from go-binary-updater secret key\n" +
+		base64.StdEncoding.EncodeToString(encoded) + "\n" +
+		minisignTrustedPrefix + trustedComment + "\n" +
+		base64.StdEncoding.EncodeToString(globalSignature) + "\n")
+}
+
+// VerifyMinisign verifies a minisign signature of data, including its trusted comment, and
+// returns the trusted comment
+func VerifyMinisign(data, signatureFile []byte, key *MinisignPublicKey) (string, error) {
+	lines := strings.Split(strings.TrimRight(strings.ReplaceAll(string(signatureFile), "\r\n", "\n"), "\n"), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], minisignUntrustedPrefix) || !strings.HasPrefix(lines[2], minisignTrustedPrefix) {
+		return "", fmt.Errorf("malformed minisign signature")
+	}
+	encoded, err := base64.StdEncoding.DecodeString(lines[1])
+	if err != nil || len(encoded) != 2+8+ed25519.SignatureSize {
+		return "", fmt.Errorf("malformed minisign signature")
+	}
+	globalSignature, err := base64.StdEncoding.DecodeString(lines[3])
+	if err != nil || len(globalSignature) != ed25519.SignatureSize {
+		return "", fmt.Errorf("malformed minisign trusted comment signature")
+	}
+
+	var keyID [8]byte
+	copy(keyID[:], encoded[2:10])
+	if keyID != key.KeyID {
+		return "", fmt.Errorf("%w: signed with key %s, expected %s", ErrInvalidSignature, keyIDString(keyID), keyIDString(key.KeyID))
+	}
+	message := data
+	switch {
+	case bytes.Equal(encoded[:2], minisignHashedAlgorithm):
+		prehashed := blake2b.Sum512(data)
+		message = prehashed[:]
+	case !bytes.Equal(encoded[:2], minisignAlgorithm):
+		return "", fmt.Errorf("unsupported minisign signature algorithm %q", encoded[:2])
+	}
+	signature := encoded[10:]
+	if !ed25519.Verify(key.Key, message, signature) {
+		return "", fmt.Errorf("%w: content does not match the signature", ErrInvalidSignature)
+	}
+	trustedComment := strings.TrimPrefix(lines[2], minisignTrustedPrefix)
+	if !ed25519.Verify(key.Key, append(append([]byte{}, signature...), trustedComment...), globalSignature) {
+		return "", fmt.Errorf("%w: trusted comment does not match the signature", ErrInvalidSignature)
+	}
+	return trustedComment, nil
+}
+
+// SignFile writes a minisign signature of the file next to it (path + MinisignSignatureSuffix)
+func SignFile(path string, key *MinisignPrivateKey) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s for signing: %v", path, err)
+	}
+	trustedComment := fmt.Sprintf("timestamp:%d\tfile:%s\thashed", time.Now().Unix(), filepath.Base(path))
+	if err := os.WriteFile(path+MinisignSignatureSuffix, key.Sign(data, trustedComment), 0644); err != nil {
+		return fmt.Errorf("failed to write signature: %v", err)
+	}
+	return nil
+}
+
+// ReadVerifiedFile reads a file after verifying its minisign signature (path + MinisignSignatureSuffix).
+// The content is returned from the same read that was verified, so it cannot change in between.
+func ReadVerifiedFile(path string, key *MinisignPublicKey) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	signature, err := os.ReadFile(path + MinisignSignatureSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signature of %s: %v", path, err)
+	}
+	if _, err := VerifyMinisign(data, signature, key); err != nil {
+		return nil, fmt.Errorf("signature verification of %s failed: %w", path, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,102 @@
+package fileUtils
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMinisignKeyRoundTrip(t *testing.T) {
+	key, err := GenerateMinisignKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsedPrivate, err := ParseMinisignPrivateKey(key.String())
+	if err != nil {
+		t.Fatalf("ParseMinisignPrivateKey failed: %v", err)
+	}
+	if parsedPrivate.KeyID != key.KeyID || !parsedPrivate.Key.Equal(key.Key) {
+		t.Error("Expected the secret key to survive a round trip")
+	}
+
+	publicText := key.Public().String()
+	if !strings.HasPrefix(publicText, "untrusted comment: minisign public key ") {
+		t.Errorf("Unexpected public key file: %q", publicText)
+	}
+	// Both the key file and the bare base64 line are accepted
+	for _, text := range []string{publicText, strings.Split(publicText, "\n")[1]} {
+		parsed, err := ParseMinisignPublicKey(text)
+		if err != nil || parsed.KeyID != key.KeyID || !parsed.Key.Equal(key.Public().Key) {
+			t.Errorf("ParseMinisignPublicKey(%q) = %+v, %v", text, parsed, err)
+		}
+	}
+
+	// Corrupted secret keys are detected by their checksum
+	data, _ := base64.StdEncoding.DecodeString(strings.Split(key.String(), "\n")[1])
+	data[100] ^= 1
+	if _, err := ParseMinisignPrivateKey(base64.StdEncoding.EncodeToString(data)); err == nil {
+		t.Error("Expected a checksum error for a corrupted secret key")
+	}
+}
+
+func TestVerifyMinisign(t *testing.T) {
+	key, _ := GenerateMinisignKey()
+	data := []byte(`{"version": 1}`)
+	signature := key.Sign(data, "timestamp:1700000000\tfile:tools.lock.json\thashed")
+
+	comment, err := VerifyMinisign(data, signature, key.Public())
+	if err != nil || comment != "timestamp:1700000000\tfile:tools.lock.json\thashed" {
+		t.Fatalf("VerifyMinisign() = %q, %v", comment, err)
+	}
+
+	if _, err := VerifyMinisign([]byte(`{"version": 2}`), signature, key.Public()); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature for changed content, got %v", err)
+	}
+	forgedComment := strings.Replace(string(signature), "timestamp:1700000000", "timestamp:1800000000", 1)
+	if _, err := VerifyMinisign(data, []byte(forgedComment), key.Public()); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature for a changed trusted comment, got %v", err)
+	}
+	other, _ := GenerateMinisignKey()
+	if _, err := VerifyMinisign(data, signature, other.Public()); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature for another key, got %v", err)
+	}
+	if _, err := VerifyMinisign(data, []byte("not a signature"), key.Public()); err == nil {
+		t.Error("Expected an error for a malformed signature")
+	}
+
+	// Legacy signatures over the message itself ("Ed") are accepted as well
+	legacy := ed25519.Sign(key.Key, data)
+	global := ed25519.Sign(key.Key, append(append([]byte{}, legacy...), "legacy"...))
+	encoded := append(append([]byte("Ed"), key.KeyID[:]...), legacy...)
+	legacySignature := "untrusted comment: legacy\n" + base64.StdEncoding.EncodeToString(encoded) +
+		"\ntrusted comment: legacy\n" + base64.StdEncoding.EncodeToString(global) + "\n"
+	if _, err := VerifyMinisign(data, []byte(legacySignature), key.Public()); err != nil {
+		t.Errorf("Expected a legacy signature to verify, got %v", err)
+	}
+}
+
+func TestSignFileAndReadVerifiedFile(t *testing.T) {
+	key, _ := GenerateMinisignKey()
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	os.WriteFile(path, []byte(`{"tools": {}}`), 0644)
+
+	if err := SignFile(path, key); err != nil {
+		t.Fatalf("SignFile failed: %v", err)
+	}
+	if data, err := ReadVerifiedFile(path, key.Public()); err != nil || string(data) != `{"tools": {}}` {
+		t.Fatalf("ReadVerifiedFile() = %q, %v", data, err)
+	}
+
+	os.WriteFile(path, []byte(`{"tools": {"evil": {}}}`), 0644)
+	if _, err := ReadVerifiedFile(path, key.Public()); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected a tampered file to be refused, got %v", err)
+	}
+	os.Remove(path + MinisignSignatureSuffix)
+	if _, err := ReadVerifiedFile(path, key.Public()); err == nil {
+		t.Error("Expected an unsigned file to be refused")
+	}
+}
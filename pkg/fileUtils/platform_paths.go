@@ -0,0 +1,95 @@
+package fileUtils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// defaultApplicationName names the per-application directories when the caller does not
+const defaultApplicationName = "go-binary-updater"
+
+// platformEnv resolves platform default paths from an OS, environment and home directory
+type platformEnv struct {
+	goos   string
+	getenv func(string) string
+	home   string
+}
+
+// currentPlatformEnv returns the platform environment of the running process
+func currentPlatformEnv() (platformEnv, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return platformEnv{}, fmt.Errorf("failed to determine home directory: %v", err)
+	}
+	return platformEnv{goos: runtime.GOOS, getenv: os.Getenv, home: home}, nil
+}
+
+// DefaultDataDirectory returns the per-user data directory of an application:
+// $XDG_DATA_HOME/{app} (default ~/.local/share/{app}) on Linux and other Unix systems,
+// ~/Library/Application Support/{app} on macOS and %LOCALAPPDATA%\{app} on Windows
+func DefaultDataDirectory(appName string) (string, error) {
+	env, err := currentPlatformEnv()
+	if err != nil {
+		return "", err
+	}
+	return env.dataDirectory(appName), nil
+}
+
+// DefaultBaseBinaryDirectory returns a sensible per-user BaseBinaryDirectory: $XDG_BIN_HOME (default
+// ~/.local/bin, which the XDG specification names for user executables whatever $XDG_DATA_HOME is)
+// on Linux and other Unix systems, and the bin directory inside DefaultDataDirectory on macOS and
+// Windows, which have no shared user bin
+func DefaultBaseBinaryDirectory(appName string) (string, error) {
+	env, err := currentPlatformEnv()
+	if err != nil {
+		return "", err
+	}
+	return env.binaryDirectory(appName), nil
+}
+
+// dataDirectory implements DefaultDataDirectory
+func (e platformEnv) dataDirectory(appName string) string {
+	if appName == "" {
+		appName = defaultApplicationName
+	}
+	switch e.goos {
+	case "darwin":
+		return filepath.Join(e.home, "Library", "Application Support", appName)
+	case "windows":
+		if localAppData := e.getenv("LOCALAPPDATA"); localAppData != "" {
+			return filepath.Join(localAppData, appName)
+		}
+		return filepath.Join(e.home, "AppData", "Local", appName)
+	}
+	return filepath.Join(e.xdgDataHome(), appName)
+}
+
+// binaryDirectory implements DefaultBaseBinaryDirectory
+func (e platformEnv) binaryDirectory(appName string) string {
+	switch e.goos {
+	case "darwin", "windows":
+		return filepath.Join(e.dataDirectory(appName), "bin")
+	}
+	if binHome := e.getenv("XDG_BIN_HOME"); filepath.IsAbs(binHome) {
+		return binHome
+	}
+	return filepath.Join(e.home, ".local", "bin")
+}
+
+// xdgDataHome returns $XDG_DATA_HOME, ignoring relative values as the specification requires
+func (e platformEnv) xdgDataHome() string {
+	if dataHome := e.getenv("XDG_DATA_HOME"); filepath.IsAbs(dataHome) {
+		return dataHome
+	}
+	return filepath.Join(e.home, ".local", "share")
+}
+
+// xdgConfigHome returns $XDG_CONFIG_HOME, ignoring relative values as the specification requires
+func (e platformEnv) xdgConfigHome() string {
+	if configHome := e.getenv("XDG_CONFIG_HOME"); filepath.IsAbs(configHome) {
+		return configHome
+	}
+	return filepath.Join(e.home, ".config")
+}
@@ -0,0 +1,88 @@
+package fileUtils
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestPlatformDefaultDirectories(t *testing.T) {
+	home := filepath.Join("/home", "user")
+	testCases := []struct {
+		name       string
+		goos       string
+		env        map[string]string
+		wantData   string
+		wantBinary string
+	}{
+		{
+			name:       "linux defaults",
+			goos:       "linux",
+			wantData:   filepath.Join(home, ".local", "share", "mytool"),
+			wantBinary: filepath.Join(home, ".local", "bin"),
+		},
+		{
+			name:       "linux XDG_DATA_HOME",
+			goos:       "linux",
+			env:        map[string]string{"XDG_DATA_HOME": "/data/user/share"},
+			wantData:   filepath.Join("/data/user/share", "mytool"),
+			wantBinary: filepath.Join(home, ".local", "bin"),
+		},
+		{
+			name:       "linux XDG_BIN_HOME",
+			goos:       "linux",
+			env:        map[string]string{"XDG_BIN_HOME": "/data/bin"},
+			wantData:   filepath.Join(home, ".local", "share", "mytool"),
+			wantBinary: "/data/bin",
+		},
+		{
+			name:       "relative XDG values are ignored",
+			goos:       "freebsd",
+			env:        map[string]string{"XDG_DATA_HOME": "share", "XDG_BIN_HOME": "bin"},
+			wantData:   filepath.Join(home, ".local", "share", "mytool"),
+			wantBinary: filepath.Join(home, ".local", "bin"),
+		},
+		{
+			name:       "macOS",
+			goos:       "darwin",
+			wantData:   filepath.Join(home, "Library", "Application Support", "mytool"),
+			wantBinary: filepath.Join(home, "Library", "Application Support", "mytool", "bin"),
+		},
+		{
+			name:       "windows LOCALAPPDATA",
+			goos:       "windows",
+			env:        map[string]string{"LOCALAPPDATA": filepath.Join("C:", "Users", "user", "AppData", "Local")},
+			wantData:   filepath.Join("C:", "Users", "user", "AppData", "Local", "mytool"),
+			wantBinary: filepath.Join("C:", "Users", "user", "AppData", "Local", "mytool", "bin"),
+		},
+		{
+			name:       "windows without LOCALAPPDATA",
+			goos:       "windows",
+			wantData:   filepath.Join(home, "AppData", "Local", "mytool"),
+			wantBinary: filepath.Join(home, "AppData", "Local", "mytool", "bin"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := platformEnv{goos: tc.goos, home: home, getenv: func(key string) string { return tc.env[key] }}
+			if got := env.dataDirectory("mytool"); got != tc.wantData {
+				t.Errorf("dataDirectory() = %s, want %s", got, tc.wantData)
+			}
+			if got := env.binaryDirectory("mytool"); got != tc.wantBinary {
+				t.Errorf("binaryDirectory() = %s, want %s", got, tc.wantBinary)
+			}
+		})
+	}
+}
+
+func TestDefaultBaseBinaryDirectory(t *testing.T) {
+	t.Setenv("XDG_BIN_HOME", "")
+	t.Setenv("XDG_DATA_HOME", "")
+	dir, err := DefaultBaseBinaryDirectory("")
+	if err != nil {
+		t.Fatalf("DefaultBaseBinaryDirectory() error = %v", err)
+	}
+	if !filepath.IsAbs(dir) {
+		t.Errorf("Expected an absolute directory, got %s", dir)
+	}
+}
@@ -0,0 +1,96 @@
+package fileUtils
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// PostInstallConfig sets file attributes some tools need on the installed binary, e.g. k0s
+// binding privileged ports without running as root. Attributes are applied with setcap and chcon
+// on Linux hosts that support them; elsewhere, or if the command fails, e.g. without the privileges
+// setcap needs, they are skipped with a warning unless Required.
+type PostInstallConfig struct {
+	Capabilities   string `json:"capabilities"`    // setcap capability set, e.g. "cap_net_bind_service=+ep"
+	SELinuxContext string `json:"selinux_context"` // Full context ("system_u:object_r:bin_t:s0") or only a type ("bin_t")
+	Required       bool   `json:"required"`        // Fail the installation if the attributes cannot be applied
+}
+
+// postInstallHost describes what the host supports and runs the attribute commands
+type postInstallHost struct {
+	goos           string
+	lookPath       func(string) (string, error)
+	selinuxEnabled func() bool
+	run            func(name string, args ...string) ([]byte, error)
+}
+
+// currentPostInstallHost runs the attribute commands on the current host; replaced in tests
+var currentPostInstallHost = postInstallHost{
+	goos:     runtime.GOOS,
+	lookPath: exec.LookPath,
+	selinuxEnabled: func() bool {
+		_, err := os.Stat("/sys/fs/selinux/enforce")
+		return err == nil
+	},
+	run: func(name string, args ...string) ([]byte, error) {
+		return exec.Command(name, args...).CombinedOutput()
+	},
+}
+
+// applyPostInstallAttributes sets the configured capabilities and SELinux context on the binary
+func applyPostInstallAttributes(config FileConfig, binaryPath string) error {
+	postInstall := config.PostInstall
+	host := currentPostInstallHost
+
+	if postInstall.Capabilities != "" {
+		if err := host.apply(postInstall, "capabilities", "setcap", true,
+			postInstall.Capabilities, binaryPath); err != nil {
+			return err
+		}
+	}
+
+	if postInstall.SELinuxContext != "" {
+		args := []string{postInstall.SELinuxContext, binaryPath}
+		if !strings.Contains(postInstall.SELinuxContext, ":") {
+			args = []string{"-t", postInstall.SELinuxContext, binaryPath}
+		}
+		if err := host.apply(postInstall, "SELinux context", "chcon", host.selinuxEnabled(), args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// apply runs one attribute command. Unless the attributes are required, a host that does not
+// support it or a failing command only produces a warning.
+func (h postInstallHost) apply(config PostInstallConfig, attribute, command string, enabled bool, args ...string) error {
+	var unsupported string
+	if h.goos != "linux" {
+		unsupported = fmt.Sprintf("%s are not supported on %s", attribute, h.goos)
+	} else if !enabled {
+		unsupported = "SELinux is not enabled on this host"
+	} else if _, err := h.lookPath(command); err != nil {
+		unsupported = fmt.Sprintf("%s is not installed", command)
+	}
+	if unsupported != "" {
+		if config.Required {
+			return fmt.Errorf("cannot set %s on the installed binary: %s", attribute, unsupported)
+		}
+		fmt.Printf("Warning: not setting %s on the installed binary: %s\n", attribute, unsupported)
+		return nil
+	}
+
+	if output, err := h.run(command, args...); err != nil {
+		err = fmt.Errorf("failed to set %s with %s %s: %v: %s", attribute, command,
+			strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+		if config.Required {
+			return err
+		}
+		fmt.Printf("Warning: %v\n", err)
+		return nil
+	}
+	fmt.Printf("Set %s on %s\n", attribute, args[len(args)-1])
+	return nil
+}
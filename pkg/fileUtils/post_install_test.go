@@ -0,0 +1,115 @@
+package fileUtils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakePostInstallHost records attribute commands instead of running them
+func fakePostInstallHost(t *testing.T, goos string, selinux bool, commands *[]string) {
+	t.Helper()
+	original := currentPostInstallHost
+	currentPostInstallHost = postInstallHost{
+		goos:           goos,
+		lookPath:       func(name string) (string, error) { return "/usr/sbin/" + name, nil },
+		selinuxEnabled: func() bool { return selinux },
+		run: func(name string, args ...string) ([]byte, error) {
+			*commands = append(*commands, name+" "+strings.Join(args, " "))
+			return nil, nil
+		},
+	}
+	t.Cleanup(func() { currentPostInstallHost = original })
+}
+
+func TestInstallBinary_PostInstallAttributes(t *testing.T) {
+	var commands []string
+	fakePostInstallHost(t, "linux", true, &commands)
+
+	tempDir := t.TempDir()
+	sourcePath := filepath.Join(tempDir, "k0s")
+	os.WriteFile(sourcePath, []byte("#!/bin/sh\n"), 0644)
+
+	config := FileConfig{
+		BaseBinaryDirectory:    filepath.Join(tempDir, "bin"),
+		VersionedDirectoryName: "versions",
+		SourceBinaryName:       "k0s",
+		BinaryName:             "k0s",
+		SourceArchivePath:      sourcePath,
+		IsDirectBinary:         true,
+		PostInstall: PostInstallConfig{
+			Capabilities:   "cap_net_bind_service=+ep",
+			SELinuxContext: "bin_t",
+		},
+	}
+	if err := InstallBinary(config, "1.0.0"); err != nil {
+		t.Fatalf("InstallBinary() error = %v", err)
+	}
+
+	binaryPath := GetVersionedBinaryPath(config, "1.0.0")
+	expected := []string{
+		"setcap cap_net_bind_service=+ep " + binaryPath,
+		"chcon -t bin_t " + binaryPath,
+	}
+	if strings.Join(commands, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Commands = %q, want %q", commands, expected)
+	}
+
+	// Full SELinux contexts are passed as-is
+	commands = nil
+	config.PostInstall = PostInstallConfig{SELinuxContext: "system_u:object_r:bin_t:s0"}
+	if err := applyPostInstallAttributes(config, binaryPath); err != nil {
+		t.Fatalf("applyPostInstallAttributes() error = %v", err)
+	}
+	if len(commands) != 1 || commands[0] != "chcon system_u:object_r:bin_t:s0 "+binaryPath {
+		t.Errorf("Unexpected commands: %q", commands)
+	}
+}
+
+func TestApplyPostInstallAttributes_Unsupported(t *testing.T) {
+	var commands []string
+	config := FileConfig{PostInstall: PostInstallConfig{
+		Capabilities:   "cap_net_bind_service=+ep",
+		SELinuxContext: "bin_t",
+	}}
+
+	// Skipped with a warning on hosts without support
+	fakePostInstallHost(t, "darwin", false, &commands)
+	if err := applyPostInstallAttributes(config, "/tmp/k0s"); err != nil {
+		t.Errorf("Expected unsupported attributes to be skipped, got %v", err)
+	}
+	if len(commands) != 0 {
+		t.Errorf("Expected no commands on darwin, got %q", commands)
+	}
+
+	// SELinux disabled on Linux: capabilities are still set
+	fakePostInstallHost(t, "linux", false, &commands)
+	if err := applyPostInstallAttributes(config, "/tmp/k0s"); err != nil {
+		t.Errorf("applyPostInstallAttributes() error = %v", err)
+	}
+	if len(commands) != 1 || !strings.HasPrefix(commands[0], "setcap ") {
+		t.Errorf("Expected only setcap to run, got %q", commands)
+	}
+
+	// Required attributes fail instead
+	config.PostInstall.Required = true
+	if err := applyPostInstallAttributes(config, "/tmp/k0s"); err == nil {
+		t.Error("Expected error for required SELinux context without SELinux")
+	}
+
+	// Command failures only produce a warning unless the attributes are required
+	config.PostInstall = PostInstallConfig{Capabilities: "cap_net_bind_service=+ep"}
+	currentPostInstallHost.run = func(name string, args ...string) ([]byte, error) {
+		return []byte("Operation not permitted"), errors.New("exit status 1")
+	}
+	if err := applyPostInstallAttributes(config, "/tmp/k0s"); err != nil {
+		t.Errorf("Expected an optional setcap failure to be skipped, got %v", err)
+	}
+	config.PostInstall.Required = true
+	err := applyPostInstallAttributes(config, "/tmp/k0s")
+	if err == nil || !strings.Contains(err.Error(), "Operation not permitted") {
+		t.Errorf("Expected a required setcap failure to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+package fileUtils
+
+import "fmt"
+
+// Provenance providers
+const (
+	ProvenanceGitHub = "github" // Release asset of a GitHub repository
+	ProvenanceGitLab = "gitlab" // Release link of a GitLab project
+	ProvenanceCDN    = "cdn"    // File on the CDN of a GitHub repository or GitLab project
+	ProvenanceLocal  = "local"  // Pre-staged file installed without a download
+)
+
+// Provenance records where an installed version was downloaded from, so auditors can trace each
+// binary on a host back to its source
+type Provenance struct {
+	Provider    string `json:"provider"`               // ProvenanceGitHub, ProvenanceGitLab, ProvenanceCDN or ProvenanceLocal
+	Repository  string `json:"repository,omitempty"`   // GitHub repository ("owner/name") or GitLab project ID
+	AssetName   string `json:"asset_name,omitempty"`   // Release asset or CDN file that was installed
+	DownloadURL string `json:"download_url,omitempty"` // URL the asset was downloaded from (the file path for local files)
+	Checksum    string `json:"checksum,omitempty"`     // Hex-encoded SHA-256 of the asset, if known
+}
+
+// RecordProvenance records in the install receipt where the installed version came from. It is
+// called by the release providers after a successful installation.
+func RecordProvenance(config FileConfig, version string, provenance Provenance) error {
+	manifest, err := ReadInstallManifest(config)
+	if err != nil {
+		return err
+	}
+	receipt, ok := manifest.Tools[config.BinaryName]
+	if !ok || !containsString(receipt.Versions, version) {
+		return fmt.Errorf("%w: %s", ErrVersionNotInstalled, version)
+	}
+	if receipt.Provenance == nil {
+		receipt.Provenance = make(map[string]Provenance)
+	}
+	receipt.Provenance[version] = provenance
+	manifest.Tools[config.BinaryName] = receipt
+	return writeInstallManifest(config, manifest)
+}
@@ -0,0 +1,54 @@
+package fileUtils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecordProvenance(t *testing.T) {
+	tempDir := t.TempDir()
+	config := FileConfig{
+		BaseBinaryDirectory:    filepath.Join(tempDir, "bin"),
+		VersionedDirectoryName: "versions",
+		BinaryName:             "tool",
+		CreateLocalSymlink:     true,
+	}
+	provenance := Provenance{Provider: ProvenanceGitHub, Repository: "owner/tool", AssetName: "tool.bin",
+		DownloadURL: "https://github.com/owner/tool/releases/download/v1.0.0/tool.bin", Checksum: "abc"}
+	if err := RecordProvenance(config, "v1.0.0", provenance); !errors.Is(err, ErrVersionNotInstalled) {
+		t.Errorf("RecordProvenance() of a missing version error = %v, want ErrVersionNotInstalled", err)
+	}
+
+	for _, version := range []string{"v1.0.0", "v2.0.0"} {
+		source := filepath.Join(tempDir, "tool-"+version)
+		os.WriteFile(source, []byte("version "+version), 0755)
+		if err := InstallFromFile(config, source, version, nil); err != nil {
+			t.Fatalf("InstallFromFile(%s) error = %v", version, err)
+		}
+	}
+	if err := RecordProvenance(config, "v1.0.0", provenance); err != nil {
+		t.Fatalf("RecordProvenance() error = %v", err)
+	}
+	if err := ActivateVersion(config, "v1.0.0"); err != nil {
+		t.Fatalf("ActivateVersion() error = %v", err)
+	}
+	info, err := GetInstallationInfo(config, "v1.0.0")
+	if err != nil || info.Provenance == nil || *info.Provenance != provenance {
+		t.Errorf("GetInstallationInfo() provenance = %+v, %v, want %+v", info.Provenance, err, provenance)
+	}
+	if info, _ := GetInstallationInfo(config, "v2.0.0"); info != nil && info.Provenance != nil {
+		t.Errorf("Expected no provenance for v2.0.0, got %+v", info.Provenance)
+	}
+
+	if err := ActivateVersion(config, "v2.0.0"); err != nil {
+		t.Fatalf("ActivateVersion() error = %v", err)
+	}
+	if err := RemoveVersion(config, "v1.0.0"); err != nil {
+		t.Fatalf("RemoveVersion() error = %v", err)
+	}
+	if receipt, _ := GetInstallReceipt(config); receipt == nil || len(receipt.Provenance) != 0 {
+		t.Errorf("Expected the provenance of the removed version to be dropped, got %+v", receipt)
+	}
+}
@@ -0,0 +1,114 @@
+package fileUtils
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// ErrReadOnlyInstallation is returned (wrapped in a ReadOnlyError) when the current user cannot
+// write to the directories an installation needs, e.g. a store owned by another user or mounted
+// read-only as in Nix-like setups
+var ErrReadOnlyInstallation = NewError(ErrorCodePermission, "installation directory is read-only")
+
+// Read-only check modes for FileConfig.ReadOnlyCheck
+const (
+	ReadOnlyCheckFail = "fail" // Fail before downloading if an install directory is not writable (default)
+	ReadOnlyCheckOff  = "off"  // Skip the check, e.g. when access is granted in ways the probe does not see
+)
+
+// ReadOnlyError describes which directory cannot be written and why
+type ReadOnlyError struct {
+	Path       string // Directory that was probed, the nearest existing one of the configured path
+	Reason     string // Why it cannot be written, e.g. "the file system is mounted read-only"
+	Suggestion string // Per-user BaseBinaryDirectory to use instead, if one could be determined
+	Err        error  // Error of the write probe
+}
+
+func (e *ReadOnlyError) Error() string {
+	suggestion := "a per-user directory"
+	if e.Suggestion != "" {
+		suggestion = fmt.Sprintf("a per-user directory such as %s", e.Suggestion)
+	}
+	return fmt.Sprintf("cannot install into %s: %s; set BaseBinaryDirectory to %s", e.Path, e.Reason, suggestion)
+}
+
+// Is makes errors.Is(err, ErrReadOnlyInstallation) match
+func (e *ReadOnlyError) Is(target error) bool {
+	return target == ErrReadOnlyInstallation
+}
+
+// ErrorCode returns ErrorCodePermission
+func (e *ReadOnlyError) ErrorCode() ErrorCode {
+	return ErrorCodePermission
+}
+
+func (e *ReadOnlyError) Unwrap() error {
+	return e.Err
+}
+
+// CheckInstallWritable returns a ReadOnlyError if the current user cannot create the version
+// directory or the symlinks of an installation, so it fails before anything is downloaded
+// instead of halfway through with a raw permission error. Shared installs only check the symlink
+// directory: their read-only shared root is expected and handled when the version is linked.
+func CheckInstallWritable(config FileConfig) error {
+	if config.ReadOnlyCheck == ReadOnlyCheckOff || config.BaseBinaryDirectory == "" {
+		return nil
+	}
+	dirs := []string{config.BaseBinaryDirectory}
+	if !config.IsSharedInstall() {
+		dirs = append(dirs, filepath.Dir(GetVersionedDirectoryPath(config, "version")))
+	}
+	for _, dir := range dirs {
+		if err := checkWritable(existingParent(dir)); err != nil {
+			err.Suggestion = userBinaryDirectory(config)
+			return err
+		}
+	}
+	return nil
+}
+
+// checkWritable probes dir by creating and removing a temporary file
+func checkWritable(dir string) *ReadOnlyError {
+	probe, err := fsys().CreateTemp(dir, ".write-check-*")
+	if err != nil {
+		return &ReadOnlyError{Path: dir, Reason: readOnlyReason(dir, err), Err: err}
+	}
+	probe.Close()
+	fsys().Remove(probe.Name())
+	return nil
+}
+
+// readOnlyReason explains why the probe of dir failed with err
+func readOnlyReason(dir string, err error) string {
+	if isReadOnlyFileSystem(err) {
+		return "the file system is mounted read-only"
+	}
+	if info, statErr := fsys().Stat(dir); statErr == nil {
+		if owner, ok := fileOwner(info); ok && owner != currentUser() {
+			return fmt.Sprintf("it is owned by another user (uid %d)", owner)
+		}
+	}
+	return fmt.Sprintf("it is not writable by the current user (%v)", err)
+}
+
+// existingParent returns path or its nearest existing parent directory
+func existingParent(path string) string {
+	for !isExistingDirectory(path) {
+		parent := filepath.Dir(path)
+		if parent == path {
+			break
+		}
+		path = parent
+	}
+	return path
+}
+
+// userBinaryDirectory returns the per-user BaseBinaryDirectory to suggest, or "" if it is unknown
+// or the configured directory already is the per-user one
+func userBinaryDirectory(config FileConfig) string {
+	dir, err := DefaultBaseBinaryDirectory(config.ProjectName)
+	if err != nil || filepath.Clean(dir) == filepath.Clean(config.BaseBinaryDirectory) {
+		return ""
+	}
+	return dir
+}
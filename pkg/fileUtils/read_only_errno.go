@@ -0,0 +1,13 @@
+//go:build !plan9
+
+package fileUtils
+
+import (
+	"errors"
+	"syscall"
+)
+
+// isReadOnlyFileSystem reports whether err says the file system is mounted read-only
+func isReadOnlyFileSystem(err error) bool {
+	return errors.Is(err, syscall.EROFS)
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package fileUtils
+
+import "os"
+
+// fileOwner is not available on this platform, where access is governed by ACLs
+func fileOwner(info os.FileInfo) (int, bool) {
+	return 0, false
+}
+
+// currentUser returns -1, as there are no uids on this platform
+func currentUser() int {
+	return -1
+}
@@ -0,0 +1,8 @@
+//go:build plan9
+
+package fileUtils
+
+// isReadOnlyFileSystem reports false, as Plan 9 errors are strings without an EROFS errno
+func isReadOnlyFileSystem(err error) bool {
+	return false
+}
@@ -0,0 +1,61 @@
+package fileUtils
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestCheckInstallWritable(t *testing.T) {
+	tempDir := t.TempDir()
+	config := FileConfig{
+		BaseBinaryDirectory:     filepath.Join(tempDir, "bin"),
+		BinaryName:              "myapp",
+		UseVersionsSubdirectory: true,
+	}
+	if err := CheckInstallWritable(config); err != nil {
+		t.Errorf("Expected missing directories below a writable one to pass, got %v", err)
+	}
+	if entries, _ := os.ReadDir(tempDir); len(entries) != 0 {
+		t.Errorf("Expected the check to leave nothing behind, found %d entries", len(entries))
+	}
+
+	if os.Geteuid() == 0 {
+		t.Skip("permission checks do not apply to root")
+	}
+	os.Chmod(tempDir, 0555)
+	defer os.Chmod(tempDir, 0755)
+
+	err := CheckInstallWritable(config)
+	var readOnly *ReadOnlyError
+	if !errors.As(err, &readOnly) || !errors.Is(err, ErrReadOnlyInstallation) || !errors.Is(err, fs.ErrPermission) {
+		t.Fatalf("Expected a ReadOnlyError wrapping the permission error, got %v", err)
+	}
+	if readOnly.Path != tempDir || !strings.Contains(err.Error(), "set BaseBinaryDirectory") {
+		t.Errorf("Expected %s to be reported with a suggestion, got %v", tempDir, err)
+	}
+	if err := InstallBinary(config, "1.0.0"); !errors.Is(err, ErrReadOnlyInstallation) {
+		t.Errorf("Expected the installation to fail early, got %v", err)
+	}
+
+	config.ReadOnlyCheck = ReadOnlyCheckOff
+	if err := CheckInstallWritable(config); err != nil {
+		t.Errorf("Expected the check to be skipped, got %v", err)
+	}
+}
+
+func TestReadOnlyReason(t *testing.T) {
+	dir := t.TempDir()
+	err := &fs.PathError{Op: "open", Path: dir, Err: syscall.EROFS}
+	if reason := readOnlyReason(dir, err); reason != "the file system is mounted read-only" {
+		t.Errorf("Unexpected reason for EROFS: %q", reason)
+	}
+	err = &fs.PathError{Op: "open", Path: dir, Err: fs.ErrPermission}
+	if reason := readOnlyReason(dir, err); !strings.Contains(reason, "not writable by the current user") {
+		t.Errorf("Unexpected reason for a directory of the current user: %q", reason)
+	}
+}
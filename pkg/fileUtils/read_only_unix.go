@@ -0,0 +1,22 @@
+//go:build unix
+
+package fileUtils
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileOwner returns the uid owning the file
+func fileOwner(info os.FileInfo) (int, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return int(stat.Uid), true
+}
+
+// currentUser returns the effective uid, which file permissions are checked against
+func currentUser() int {
+	return os.Geteuid()
+}
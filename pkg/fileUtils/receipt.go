@@ -0,0 +1,377 @@
+package fileUtils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ManifestFileName is the state file in BaseBinaryDirectory that records every installed tool
+const ManifestFileName = ".go-binary-updater.json"
+
+// globalSymlinkDirectory is where global symlinks are created unless GlobalSymlinkDirectory is set
+var globalSymlinkDirectory = "/usr/local/bin"
+
+// InstallReceipt records what an installation created, similar to Homebrew's install receipts
+type InstallReceipt struct {
+	BinaryName       string                `json:"binary_name"`                  // Name of the installed binary
+	ProjectName      string                `json:"project_name,omitempty"`       // Project the binary belongs to
+	Version          string                `json:"version"`                      // Most recently installed version
+	Versions         []string              `json:"versions"`                     // Every version installed in the versioned directory
+	InstallationType string                `json:"installation_type"`            // "direct_binary" or "extracted_archive"
+	VersionedPath    string                `json:"versioned_path"`               // Binary in the versioned directory of Version
+	LocalSymlinkPath string                `json:"local_symlink_path,omitempty"` // Local symlink, if one was created
+	LocalCopy        bool                  `json:"local_copy,omitempty"`         // LocalSymlinkPath is a copy of the binary because symlinks are unsupported
+	Aliases          []string              `json:"aliases,omitempty"`            // Alias symlinks created for the binary
+	ExtraFiles       []string              `json:"extra_files,omitempty"`        // Installed shell completions and man pages
+	Checksums        map[string]string     `json:"checksums,omitempty"`          // Hex-encoded SHA-256 of each installed version's binary
+	Provenance       map[string]Provenance `json:"provenance,omitempty"`         // Where each installed version was downloaded from
+	InstalledAt      time.Time             `json:"installed_at"`                 // Time of the most recent installation
+}
+
+// ErrVersionNotInstalled is returned by CheckInstalledVersion when the version's binary does not exist
+var ErrVersionNotInstalled = NewError(ErrorCodeNoMatch, "version is not installed")
+
+// InstallManifest is the state of all tools installed into a BaseBinaryDirectory, keyed by binary name
+type InstallManifest struct {
+	Tools map[string]InstallReceipt `json:"tools"`
+}
+
+// UninstallResult lists what Uninstall removed, or would remove in a dry run
+type UninstallResult struct {
+	DryRun          bool     `json:"dry_run"`            // True if nothing was actually removed
+	Removed         []string `json:"removed"`            // Paths removed (or to be removed)
+	ManifestUpdated bool     `json:"manifest_updated"`   // True if the tool's manifest entry was (or would be) removed
+	Warnings        []string `json:"warnings,omitempty"` // Paths that could not be removed, e.g. global symlinks needing sudo
+}
+
+// manifestPath returns the location of the state manifest for the configuration
+func manifestPath(config FileConfig) string {
+	return filepath.Join(config.BaseBinaryDirectory, ManifestFileName)
+}
+
+// ReadInstallManifest loads the state manifest of the configured BaseBinaryDirectory.
+// A missing manifest yields an empty one.
+func ReadInstallManifest(config FileConfig) (*InstallManifest, error) {
+	manifest := &InstallManifest{Tools: make(map[string]InstallReceipt)}
+	data, err := fsys().ReadFile(manifestPath(config))
+	if os.IsNotExist(err) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read install manifest: %v", err)
+	}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse install manifest: %v", err)
+	}
+	if manifest.Tools == nil {
+		manifest.Tools = make(map[string]InstallReceipt)
+	}
+	return manifest, nil
+}
+
+// writeInstallManifest atomically replaces the state manifest, removing it when no tools remain
+func writeInstallManifest(config FileConfig, manifest *InstallManifest) error {
+	path := manifestPath(config)
+	if len(manifest.Tools) == 0 {
+		if err := fsys().Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove install manifest: %v", err)
+		}
+		return nil
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode install manifest: %v", err)
+	}
+	tempPath := path + ".tmp"
+	if err := fsys().WriteFile(tempPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write install manifest: %v", err)
+	}
+	if err := fsys().Rename(tempPath, path); err != nil {
+		fsys().Remove(tempPath)
+		return fmt.Errorf("failed to write install manifest: %v", err)
+	}
+	return nil
+}
+
+// GetInstallReceipt returns the receipt of the configured binary from the state manifest
+func GetInstallReceipt(config FileConfig) (*InstallReceipt, error) {
+	manifest, err := ReadInstallManifest(config)
+	if err != nil {
+		return nil, err
+	}
+	receipt, ok := manifest.Tools[config.BinaryName]
+	if !ok {
+		return nil, fmt.Errorf("no install receipt found for %s", config.BinaryName)
+	}
+	return &receipt, nil
+}
+
+// CheckInstalledVersion verifies that version is installed and active: its versioned binary exists,
+// matches the checksum recorded in the install receipt when it was installed (receipts written before
+// checksums were recorded are not verified), and the local symlink (or copy) points at it.
+// Installing the version again is unnecessary when it returns nil.
+func CheckInstalledVersion(config FileConfig, version string) error {
+	binaryPath := GetVersionedBinaryPath(config, version)
+	if !FileExists(binaryPath) {
+		return fmt.Errorf("%w: %s", ErrVersionNotInstalled, version)
+	}
+	receipt, err := GetInstallReceipt(config)
+	if err != nil {
+		return err
+	}
+	if checksum := receipt.Checksums[version]; checksum != "" {
+		if err := VerifyFileSHA256(binaryPath, checksum); err != nil {
+			return fmt.Errorf("installed binary %s was modified: %v", binaryPath, err)
+		}
+	}
+
+	// Installations enable the local symlink when no symlink is configured at all
+	if config.CreateLocalSymlink || !config.CreateGlobalSymlink {
+		current, err := CurrentInstalledVersion(config)
+		if err != nil {
+			return err
+		}
+		if current != version {
+			return fmt.Errorf("%s %s is installed but %s is active", config.BinaryName, version, current)
+		}
+	}
+	return nil
+}
+
+// recordInstallReceipt adds the installed version, its alias symlinks and its extra files to the
+// state manifest. Failures only produce a warning because the binary itself was installed successfully.
+func recordInstallReceipt(config FileConfig, version, localSymlinkStatus string, aliases, extraFiles []string) {
+	manifest, err := ReadInstallManifest(config)
+	if err != nil {
+		fmt.Printf("Warning: failed to record installation: %v\n", err)
+		return
+	}
+
+	receipt := manifest.Tools[config.BinaryName]
+	receipt.BinaryName = config.BinaryName
+	receipt.ProjectName = config.ProjectName
+	receipt.Version = version
+	receipt.VersionedPath = GetVersionedBinaryPath(config, version)
+	receipt.InstalledAt = time.Now().UTC()
+	receipt.InstallationType = "extracted_archive"
+	if config.UsesDirectInstall() {
+		receipt.InstallationType = "direct_binary"
+	}
+	receipt.LocalSymlinkPath = ""
+	if localSymlinkStatus == "created" || localSymlinkStatus == "copy" {
+		receipt.LocalSymlinkPath = GetLocalSymlinkPath(config)
+	}
+	receipt.LocalCopy = localSymlinkStatus == "copy"
+	for _, path := range aliases {
+		if !containsString(receipt.Aliases, path) {
+			receipt.Aliases = append(receipt.Aliases, path)
+		}
+	}
+	sort.Strings(receipt.Aliases)
+	if !containsString(receipt.Versions, version) {
+		receipt.Versions = append(receipt.Versions, version)
+		sort.Strings(receipt.Versions)
+	}
+	if checksum, err := FileSHA256(receipt.VersionedPath); err == nil {
+		if receipt.Checksums == nil {
+			receipt.Checksums = make(map[string]string)
+		}
+		receipt.Checksums[version] = checksum
+	}
+	for _, path := range extraFiles {
+		if !containsString(receipt.ExtraFiles, path) {
+			receipt.ExtraFiles = append(receipt.ExtraFiles, path)
+		}
+	}
+	sort.Strings(receipt.ExtraFiles)
+	manifest.Tools[config.BinaryName] = receipt
+
+	if err := writeInstallManifest(config, manifest); err != nil {
+		fmt.Printf("Warning: failed to record installation: %v\n", err)
+	}
+}
+
+// PlanUninstall reports what Uninstall would remove without changing anything (dry run)
+func PlanUninstall(config FileConfig) (*UninstallResult, error) {
+	return uninstall(config, true)
+}
+
+// Uninstall removes everything installed for the configured binary: all versioned directories,
+// the local symlink, installed completions and man pages, the global symlink if CreateGlobalSymlink is set and it points into this
+// installation, and the tool's entry in the state manifest. Versions in a shared root
+// (SharedVersionsDirectory) are kept because other users may link to them.
+func Uninstall(config FileConfig) (*UninstallResult, error) {
+	return uninstall(config, false)
+}
+
+func uninstall(config FileConfig, dryRun bool) (*UninstallResult, error) {
+	if config.BinaryName == "" || config.BaseBinaryDirectory == "" {
+		return nil, fmt.Errorf("uninstall requires BinaryName and BaseBinaryDirectory")
+	}
+
+	manifest, err := ReadInstallManifest(config)
+	if err != nil {
+		return nil, err
+	}
+	receipt, hasReceipt := manifest.Tools[config.BinaryName]
+
+	result := &UninstallResult{DryRun: dryRun}
+	remove := func(path string) {
+		if !dryRun {
+			if err := fsys().RemoveAll(path); err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("failed to remove %s: %v", path, err))
+				return
+			}
+		}
+		result.Removed = append(result.Removed, path)
+	}
+
+	versionDirs := installedVersionDirectories(config, receipt.Versions)
+	localSymlinkPath := GetLocalSymlinkPath(config)
+
+	// The global symlink is only removed if it points at the local symlink or into a version directory
+	if config.CreateGlobalSymlink {
+		globalSymlinkPath := GetGlobalSymlinkPath(config)
+		target, err := fsys().Readlink(globalSymlinkPath)
+		if err == nil && !filepath.IsAbs(target) {
+			target = filepath.Join(GetGlobalSymlinkDirectory(config), target)
+		}
+		if err == nil && pointsInto(target, localSymlinkPath, versionDirs) {
+			if dryRun {
+				result.Removed = append(result.Removed, globalSymlinkPath)
+			} else if err := fsys().Remove(globalSymlinkPath); err != nil {
+				result.Warnings = append(result.Warnings,
+					fmt.Sprintf("failed to remove global symlink %s (run: sudo rm %s): %v", globalSymlinkPath, globalSymlinkPath, err))
+			} else {
+				result.Removed = append(result.Removed, globalSymlinkPath)
+			}
+		}
+	}
+
+	if info, err := fsys().Lstat(localSymlinkPath); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		remove(localSymlinkPath)
+	} else if err == nil && receipt.LocalCopy && receipt.LocalSymlinkPath == localSymlinkPath {
+		// Copies made because symlinks are unsupported belong to the installation as well
+		remove(localSymlinkPath)
+	}
+	// Aliases are only removed if they still point at this installation
+	for _, aliasPath := range aliasCandidates(config, receipt.Aliases) {
+		if target, err := resolveSymlink(config, aliasPath); err == nil && pointsInto(target, localSymlinkPath, versionDirs) {
+			remove(aliasPath)
+		}
+	}
+	for _, path := range receipt.ExtraFiles {
+		if FileExists(path) {
+			remove(path)
+		}
+	}
+	if config.IsSharedInstall() {
+		// Other users may still link to the shared versions
+		if len(versionDirs) > 0 {
+			result.Warnings = append(result.Warnings,
+				fmt.Sprintf("shared versions in %s were left in place for other users", sharedProjectDirectory(config)))
+		}
+	} else {
+		for _, dir := range versionDirs {
+			remove(dir)
+		}
+	}
+
+	// Clean up the per-project versions directory once it is empty
+	if !dryRun && config.UseVersionsSubdirectory {
+		fsys().Remove(filepath.Dir(GetVersionedDirectoryPath(config, "version")))
+	}
+
+	if hasReceipt {
+		if !dryRun {
+			delete(manifest.Tools, config.BinaryName)
+			if err := writeInstallManifest(config, manifest); err != nil {
+				return result, err
+			}
+		}
+		result.ManifestUpdated = true
+	}
+
+	if len(result.Removed) == 0 && !result.ManifestUpdated {
+		return result, fmt.Errorf("%s is not installed in %s", config.BinaryName, config.BaseBinaryDirectory)
+	}
+	return result, nil
+}
+
+// installedVersionDirectories returns the existing version directories of the binary: the versions
+// from the receipt, the active version, and any sibling version directory that contains the binary
+func installedVersionDirectories(config FileConfig, versions []string) []string {
+	candidates := append([]string{}, versions...)
+	if current, err := CurrentInstalledVersion(config); err == nil {
+		candidates = append(candidates, current)
+	}
+
+	// Scanning is only safe when the versions live in a dedicated directory
+	parent := filepath.Dir(GetVersionedDirectoryPath(config, "version"))
+	if filepath.Clean(parent) != filepath.Clean(config.BaseBinaryDirectory) {
+		if entries, err := fsys().ReadDir(parent); err == nil {
+			for _, entry := range entries {
+				if entry.IsDir() && FileExists(filepath.Join(parent, entry.Name(), config.BinaryName)) {
+					candidates = append(candidates, entry.Name())
+				}
+			}
+		}
+	}
+
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, version := range candidates {
+		dir := GetVersionedDirectoryPath(config, version)
+		if version == "" || seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		if _, err := fsys().Stat(dir); err == nil {
+			dirs = append(dirs, dir)
+		}
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// aliasCandidates returns the recorded and configured alias symlinks without duplicates
+func aliasCandidates(config FileConfig, recorded []string) []string {
+	var candidates []string
+	for _, path := range append(append([]string{}, recorded...), GetSymlinkAliasPaths(config)...) {
+		if !containsString(candidates, path) {
+			candidates = append(candidates, path)
+		}
+	}
+	return candidates
+}
+
+// pointsInto reports whether a symlink target is the local symlink or lies inside one of the directories
+func pointsInto(target, localSymlinkPath string, dirs []string) bool {
+	target = filepath.Clean(target)
+	if target == filepath.Clean(localSymlinkPath) {
+		return true
+	}
+	for _, dir := range dirs {
+		rel, err := filepath.Rel(dir, target)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
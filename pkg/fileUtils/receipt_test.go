@@ -0,0 +1,126 @@
+package fileUtils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUninstall(t *testing.T) {
+	tempDir := t.TempDir()
+	binaryPath := filepath.Join(tempDir, "download")
+	if err := os.WriteFile(binaryPath, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	baseDir := filepath.Join(tempDir, "bin")
+	config := FileConfig{
+		BaseBinaryDirectory:     baseDir,
+		BinaryName:              "mytool",
+		ProjectName:             "mytool",
+		IsDirectBinary:          true,
+		CreateLocalSymlink:      true,
+		CreateGlobalSymlink:     true,
+		UseVersionsSubdirectory: true,
+	}
+	for _, version := range []string{"v1.0.0", "v1.1.0"} {
+		if err := InstallFromFile(config, binaryPath, version, nil); err != nil {
+			t.Fatalf("InstallFromFile(%s) error = %v", version, err)
+		}
+	}
+
+	receipt, err := GetInstallReceipt(config)
+	if err != nil {
+		t.Fatalf("GetInstallReceipt() error = %v", err)
+	}
+	if receipt.Version != "v1.1.0" || len(receipt.Versions) != 2 {
+		t.Errorf("Unexpected receipt: version %s, versions %v", receipt.Version, receipt.Versions)
+	}
+
+	// A global symlink pointing at the local symlink is removed too
+	globalDir := filepath.Join(tempDir, "global")
+	os.MkdirAll(globalDir, 0755)
+	originalGlobalDir := globalSymlinkDirectory
+	globalSymlinkDirectory = globalDir
+	defer func() { globalSymlinkDirectory = originalGlobalDir }()
+	globalSymlink := filepath.Join(globalDir, "mytool")
+	if err := os.Symlink(filepath.Join(baseDir, "mytool"), globalSymlink); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another tool in the same directory must survive
+	other := config
+	other.BinaryName = "othertool"
+	other.ProjectName = "othertool"
+	if err := InstallFromFile(other, binaryPath, "v2.0.0", nil); err != nil {
+		t.Fatalf("InstallFromFile(othertool) error = %v", err)
+	}
+
+	plan, err := PlanUninstall(config)
+	if err != nil {
+		t.Fatalf("PlanUninstall() error = %v", err)
+	}
+	if !plan.DryRun || len(plan.Removed) != 4 || !plan.ManifestUpdated {
+		t.Errorf("Expected dry run to list global symlink, local symlink and two versions, got %v", plan.Removed)
+	}
+	if !FileExists(GetVersionedBinaryPath(config, "v1.0.0")) {
+		t.Fatal("Dry run must not remove anything")
+	}
+
+	result, err := Uninstall(config)
+	if err != nil {
+		t.Fatalf("Uninstall() error = %v", err)
+	}
+	if len(result.Warnings) != 0 {
+		t.Errorf("Unexpected warnings: %v", result.Warnings)
+	}
+	for _, path := range []string{globalSymlink, filepath.Join(baseDir, "mytool"), filepath.Join(baseDir, "versions", "mytool")} {
+		if _, err := os.Lstat(path); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be removed", path)
+		}
+	}
+	if _, err := GetInstallReceipt(config); err == nil {
+		t.Error("Expected manifest entry to be removed")
+	}
+	if _, err := GetInstallReceipt(other); err != nil || !FileExists(GetVersionedBinaryPath(other, "v2.0.0")) {
+		t.Errorf("Expected othertool to remain installed, got %v", err)
+	}
+
+	if _, err := Uninstall(config); err == nil {
+		t.Error("Expected error when uninstalling a tool that is not installed")
+	}
+}
+
+func TestCheckInstalledVersion(t *testing.T) {
+	tempDir := t.TempDir()
+	config := FileConfig{
+		BaseBinaryDirectory:     filepath.Join(tempDir, "bin"),
+		BinaryName:              "mytool",
+		IsDirectBinary:          true,
+		CreateLocalSymlink:      true,
+		UseVersionsSubdirectory: true,
+	}
+	if err := CheckInstalledVersion(config, "v1.0.0"); !errors.Is(err, ErrVersionNotInstalled) {
+		t.Errorf("CheckInstalledVersion() before installing error = %v, want ErrVersionNotInstalled", err)
+	}
+
+	binaryPath := filepath.Join(tempDir, "download")
+	for _, version := range []string{"v1.0.0", "v1.1.0"} {
+		os.WriteFile(binaryPath, []byte("#!/bin/sh\necho "+version+"\n"), 0755)
+		if err := InstallFromFile(config, binaryPath, version, nil); err != nil {
+			t.Fatalf("InstallFromFile(%s) error = %v", version, err)
+		}
+	}
+	if err := CheckInstalledVersion(config, "v1.1.0"); err != nil {
+		t.Errorf("CheckInstalledVersion() of the active version error = %v", err)
+	}
+	if err := CheckInstalledVersion(config, "v1.0.0"); err == nil {
+		t.Error("Expected an installed but inactive version to fail the check")
+	}
+
+	os.WriteFile(GetVersionedBinaryPath(config, "v1.1.0"), []byte("modified"), 0755)
+	if err := CheckInstalledVersion(config, "v1.1.0"); err == nil {
+		t.Error("Expected a modified binary to fail the check")
+	}
+}
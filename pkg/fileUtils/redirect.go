@@ -0,0 +1,26 @@
+package fileUtils
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// credentialHeaders are removed from requests that are redirected to another host
+var credentialHeaders = []string{"Authorization", "Private-Token", "Job-Token"}
+
+// dropCredentialsOnRedirect keeps credentials from following redirects to other hosts, such as the
+// pre-signed storage URLs GitHub and GitLab redirect asset downloads to. Unlike the default policy
+// it also drops them for subdomains, other ports and scheme downgrades.
+func dropCredentialsOnRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+	original := via[0].URL
+	if !strings.EqualFold(req.URL.Host, original.Host) || !strings.EqualFold(req.URL.Scheme, original.Scheme) {
+		for _, header := range credentialHeaders {
+			req.Header.Del(header)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package fileUtils
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestDropCredentialsOnRedirect(t *testing.T) {
+	newRequest := func(link string) *http.Request {
+		req, _ := http.NewRequest("GET", link, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		req.Header.Set("Private-Token", "secret")
+		return req
+	}
+	via := []*http.Request{newRequest("https://gitlab.example.com/group/project/-/releases/v1/downloads/app")}
+
+	sameHostRedirect := newRequest("https://gitlab.example.com/group/project/-/package_files/1/download")
+	if err := dropCredentialsOnRedirect(sameHostRedirect, via); err != nil || sameHostRedirect.Header.Get("Authorization") == "" {
+		t.Errorf("Expected credentials to be kept on the same host (%v)", err)
+	}
+	for _, link := range []string{
+		"https://storage.gitlab.example.com/bucket/app",
+		"https://objects.example.net/bucket/app",
+		"http://gitlab.example.com/group/project/-/package_files/1/download",
+	} {
+		redirect := newRequest(link)
+		if err := dropCredentialsOnRedirect(redirect, via); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if redirect.Header.Get("Authorization") != "" || redirect.Header.Get("Private-Token") != "" {
+			t.Errorf("Expected credentials to be dropped for %s", link)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package fileUtils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// RepairResult lists what RepairInstallation restored
+type RepairResult struct {
+	Version        string   `json:"version"`         // Installed version that was checked
+	Repaired       []string `json:"repaired"`        // Paths that were restored
+	NeedsReinstall bool     `json:"needs_reinstall"` // The binary is missing or modified and must be downloaded again
+}
+
+// RepairInstallation validates the active installation against its install receipt and restores
+// only the broken parts: the binary's executable mode, the local symlink (or copy), alias symlinks
+// and completions and man pages. A missing or modified binary cannot be restored from the local
+// files, so NeedsReinstall is set instead and nothing else is changed. Receipts written before
+// checksums were recorded leave the binary's content unverified.
+func RepairInstallation(config FileConfig) (*RepairResult, error) {
+	if config.CreateLocalSymlink == false && config.CreateGlobalSymlink == false {
+		// If both are false, assume this is an old config and enable local symlinks by default
+		config.CreateLocalSymlink = true
+	}
+	receipt, err := GetInstallReceipt(config)
+	if err != nil {
+		return nil, err
+	}
+	result := &RepairResult{Version: receipt.Version}
+
+	binaryPath := GetVersionedBinaryPath(config, receipt.Version)
+	checksum := receipt.Checksums[receipt.Version]
+	if !FileExists(binaryPath) || (checksum != "" && VerifyFileSHA256(binaryPath, checksum) != nil) {
+		result.NeedsReinstall = true
+		return result, nil
+	}
+
+	info, err := fsys().Stat(binaryPath)
+	if err != nil {
+		return result, fmt.Errorf("failed to inspect %s: %v", binaryPath, err)
+	}
+	if mode := GetBinaryFileMode(config); info.Mode().Perm() != mode {
+		if err := fsys().Chmod(binaryPath, mode); err != nil {
+			return result, fmt.Errorf("failed to make binary executable: %v", err)
+		}
+		result.Repaired = append(result.Repaired, binaryPath)
+	}
+
+	if config.CreateLocalSymlink {
+		symlinkTarget := GetSymlinkTargetPath(config, receipt.Version)
+		localSymlinkPath := GetLocalSymlinkPath(config)
+		if !linksTo(config, localSymlinkPath, binaryPath) && !isLocalCopyOf(localSymlinkPath, binaryPath) {
+			if status := linkLocalBinary(symlinkTarget, binaryPath, localSymlinkPath); status == "failed" {
+				return result, fmt.Errorf("failed to restore %s", localSymlinkPath)
+			}
+			result.Repaired = append(result.Repaired, localSymlinkPath)
+		}
+		for _, aliasPath := range aliasCandidates(config, receipt.Aliases) {
+			if linksTo(config, aliasPath, binaryPath) {
+				continue
+			}
+			if info, err := fsys().Lstat(aliasPath); err == nil && info.Mode()&os.ModeSymlink == 0 {
+				fmt.Printf("Warning: not replacing %s with an alias symlink because it is not a symlink\n", aliasPath)
+				continue
+			}
+			if err := UpdateSymlink(symlinkTarget, aliasPath); err != nil {
+				return result, fmt.Errorf("failed to restore alias %s: %v", aliasPath, err)
+			}
+			result.Repaired = append(result.Repaired, aliasPath)
+		}
+	}
+
+	sources, err := extraFileSources(config, filepath.Dir(binaryPath))
+	if err != nil {
+		return result, fmt.Errorf("failed to check completions and man pages: %v", err)
+	}
+	for _, destination := range sortedKeys(sources) {
+		if filesMatch(sources[destination], destination) {
+			continue
+		}
+		if err := installExtraFile(sources[destination], destination); err != nil {
+			return result, fmt.Errorf("failed to restore %s: %v", destination, err)
+		}
+		result.Repaired = append(result.Repaired, destination)
+	}
+
+	if len(result.Repaired) > 0 {
+		fmt.Printf("Repaired %d file(s) of %s %s\n", len(result.Repaired), config.BinaryName, receipt.Version)
+	}
+	return result, nil
+}
+
+// linksTo reports whether symlinkPath is a symlink resolving to binaryPath
+func linksTo(config FileConfig, symlinkPath, binaryPath string) bool {
+	target, err := resolveSymlink(config, symlinkPath)
+	return err == nil && filepath.Clean(target) == filepath.Clean(binaryPath)
+}
+
+// filesMatch reports whether destination exists with the same content as source
+func filesMatch(source, destination string) bool {
+	destinationSum, err := FileSHA256(destination)
+	if err != nil {
+		return false
+	}
+	sourceSum, err := FileSHA256(source)
+	return err == nil && sourceSum == destinationSum
+}
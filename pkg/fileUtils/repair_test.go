@@ -0,0 +1,105 @@
+package fileUtils
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRepairInstallation(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", filepath.Join(tempDir, "share"))
+
+	archivePath := filepath.Join(tempDir, "tool.tar.gz")
+	createTestArchiveWithFiles(t, archivePath, map[string]string{
+		"tool":                  "binary",
+		"completions/tool.bash": "bash",
+	})
+	config := FileConfig{
+		BaseBinaryDirectory:    filepath.Join(tempDir, "bin"),
+		VersionedDirectoryName: "versions",
+		SourceBinaryName:       "tool",
+		BinaryName:             "tool",
+		SourceArchivePath:      archivePath,
+		CreateLocalSymlink:     true,
+		SymlinkAliases:         []string{"t"},
+		BinaryFileMode:         0755,
+		ExtraFiles: ExtraFilesConfig{
+			Completions:             true,
+			BashCompletionDirectory: filepath.Join(tempDir, "bash-completion"),
+		},
+	}
+	if err := InstallBinary(config, "1.0.0"); err != nil {
+		t.Fatalf("InstallBinary() error = %v", err)
+	}
+
+	// An intact installation is left alone
+	result, err := RepairInstallation(config)
+	if err != nil || result.NeedsReinstall || len(result.Repaired) != 0 {
+		t.Fatalf("RepairInstallation() = %+v, %v, want nothing to repair", result, err)
+	}
+
+	binaryPath := GetVersionedBinaryPath(config, "1.0.0")
+	localSymlinkPath := GetLocalSymlinkPath(config)
+	aliasPath := filepath.Join(config.BaseBinaryDirectory, "t")
+	completionPath := filepath.Join(tempDir, "bash-completion", "tool")
+	os.Chmod(binaryPath, 0644)
+	os.Remove(localSymlinkPath)
+	os.Remove(aliasPath)
+	os.Symlink("elsewhere", aliasPath)
+	os.WriteFile(completionPath, []byte("truncated"), 0644)
+
+	result, err = RepairInstallation(config)
+	if err != nil {
+		t.Fatalf("RepairInstallation() error = %v", err)
+	}
+	want := []string{binaryPath, localSymlinkPath, aliasPath, completionPath}
+	if result.Version != "1.0.0" || result.NeedsReinstall || !reflect.DeepEqual(result.Repaired, want) {
+		t.Errorf("RepairInstallation() = %+v, want %v repaired", result, want)
+	}
+	if err := CheckInstalledVersion(config, "1.0.0"); err != nil {
+		t.Errorf("CheckInstalledVersion() after repair error = %v", err)
+	}
+	if info, _ := os.Stat(binaryPath); info.Mode().Perm() != 0755 {
+		t.Errorf("Expected the binary to be executable again, mode = %v", info.Mode())
+	}
+	if !linksTo(config, aliasPath, binaryPath) {
+		t.Error("Expected the alias to point at the binary again")
+	}
+	if data, _ := os.ReadFile(completionPath); string(data) != "bash" {
+		t.Errorf("Expected the completion to be restored, got %q", data)
+	}
+
+	// Receipts written before checksums were recorded don't force a reinstall
+	manifest, err := ReadInstallManifest(config)
+	if err != nil {
+		t.Fatalf("ReadInstallManifest() error = %v", err)
+	}
+	receipt := manifest.Tools[config.BinaryName]
+	receipt.Checksums = nil
+	manifest.Tools[config.BinaryName] = receipt
+	if err := writeInstallManifest(config, manifest); err != nil {
+		t.Fatalf("writeInstallManifest() error = %v", err)
+	}
+	if result, err := RepairInstallation(config); err != nil || result.NeedsReinstall {
+		t.Errorf("RepairInstallation() without checksums = %+v, %v, want no reinstall", result, err)
+	}
+	if err := CheckInstalledVersion(config, "1.0.0"); err != nil {
+		t.Errorf("CheckInstalledVersion() without checksums error = %v", err)
+	}
+	if err := InstallBinary(config, "1.0.0"); err != nil {
+		t.Fatalf("InstallBinary() error = %v", err)
+	}
+
+	// A modified binary cannot be restored from the version directory
+	os.WriteFile(binaryPath, []byte("corrupted"), 0755)
+	os.Remove(localSymlinkPath)
+	result, err = RepairInstallation(config)
+	if err != nil || !result.NeedsReinstall {
+		t.Errorf("RepairInstallation() = %+v, %v, want NeedsReinstall", result, err)
+	}
+	if _, err := os.Lstat(localSymlinkPath); !os.IsNotExist(err) {
+		t.Error("Expected nothing to be repaired when the binary needs to be reinstalled")
+	}
+}
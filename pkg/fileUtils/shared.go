@@ -0,0 +1,104 @@
+package fileUtils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// sharedInstallMode is the default mode of shared binaries and directories. The umask is ignored
+// because every user linking to the shared root must be able to run the binaries.
+const sharedInstallMode os.FileMode = 0755
+
+// IsSharedInstall reports whether versioned binaries live in a shared root (SharedVersionsDirectory)
+// while BaseBinaryDirectory only holds the current user's symlinks
+func (c FileConfig) IsSharedInstall() bool {
+	return c.SharedVersionsDirectory != ""
+}
+
+// sharedProjectDirectory returns the directory holding all shared versions of the binary
+func sharedProjectDirectory(config FileConfig) string {
+	projectName := config.ProjectName
+	if projectName == "" {
+		projectName = config.BinaryName
+	}
+	return filepath.Join(config.SharedVersionsDirectory, projectName)
+}
+
+// IsSharedVersionInstalled reports whether the version is already installed in the shared root
+func IsSharedVersionInstalled(config FileConfig, version string) bool {
+	return config.IsSharedInstall() && FileExists(GetVersionedBinaryPath(config, version))
+}
+
+// CheckSharedBinary verifies that a binary in the shared root can safely be linked by every user:
+// it must be readable and executable by others and must not be writable by group or others
+func CheckSharedBinary(path string) error {
+	info, err := fsys().Stat(path)
+	if err != nil {
+		return fmt.Errorf("shared binary %s is not accessible: %v", path, err)
+	}
+	mode := info.Mode().Perm()
+	if mode&0005 != 0005 {
+		return fmt.Errorf("shared binary %s is not readable and executable by other users (mode %v); reinstall it as its owner with BinaryFileMode 0755", path, mode)
+	}
+	if mode&0022 != 0 {
+		return fmt.Errorf("shared binary %s is writable by other users (mode %v); refusing to link to it", path, mode)
+	}
+	return nil
+}
+
+// checkSharedRootWritable returns a descriptive error if the current user cannot install new
+// versions into the shared root, which is typically owned by root
+func checkSharedRootWritable(config FileConfig, version string) error {
+	if err := checkWritable(existingParent(sharedProjectDirectory(config))); err != nil {
+		return fmt.Errorf("version %s of %s is not installed in the shared directory %s, and the current user cannot install it there (%v); "+
+			"install it once as the directory's owner (e.g. with sudo), after which users can link to it without elevated permissions",
+			version, config.BinaryName, config.SharedVersionsDirectory, err.Err)
+	}
+	return nil
+}
+
+// isExistingDirectory reports whether path exists and is a directory
+func isExistingDirectory(path string) bool {
+	info, err := fsys().Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// linkSharedVersion links the current user's symlink to a version that is already installed in
+// the shared root. It reports false if the version still has to be installed, after verifying that
+// the current user is allowed to do so.
+func linkSharedVersion(config FileConfig, version string) (bool, error) {
+	if !config.IsSharedInstall() {
+		return false, nil
+	}
+	if !IsSharedVersionInstalled(config, version) {
+		return false, checkSharedRootWritable(config, version)
+	}
+
+	versionedPath := GetVersionedBinaryPath(config, version)
+	if err := CheckSharedBinary(versionedPath); err != nil {
+		return true, err
+	}
+	fmt.Printf("Version %s is already installed in %s\n", version, config.SharedVersionsDirectory)
+
+	localSymlinkStatus := "disabled"
+	var aliases []string
+	if config.CreateLocalSymlink {
+		if err := fsys().MkdirAll(config.BaseBinaryDirectory, 0755); err != nil {
+			return true, fmt.Errorf("failed to create symlink directory %s: %v", config.BaseBinaryDirectory, err)
+		}
+		localSymlinkPath := GetLocalSymlinkPath(config)
+		localSymlinkStatus = linkLocalBinary(versionedPath, versionedPath, localSymlinkPath)
+		if localSymlinkStatus == "failed" {
+			return true, fmt.Errorf("failed to link %s to the shared installation", localSymlinkPath)
+		}
+		if localSymlinkStatus == "created" {
+			fmt.Printf("Local symlink created: %s -> %s\n", localSymlinkPath, versionedPath)
+		}
+		aliases = createSymlinkAliases(config, versionedPath)
+	}
+
+	extraFiles := installExtraFiles(config, GetVersionedDirectoryPath(config, version))
+	recordInstallReceipt(config, version, localSymlinkStatus, aliases, extraFiles)
+	return true, nil
+}
@@ -0,0 +1,113 @@
+package fileUtils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func sharedTestConfig(sharedDir, userDir, source string) FileConfig {
+	return FileConfig{
+		SharedVersionsDirectory: sharedDir,
+		BaseBinaryDirectory:     userDir,
+		BinaryName:              "tool",
+		ProjectName:             "tool",
+		SourceArchivePath:       source,
+		IsDirectBinary:          true,
+		CreateLocalSymlink:      true,
+	}
+}
+
+func TestSharedInstall_LinksPerUser(t *testing.T) {
+	tempDir := t.TempDir()
+	sharedDir := filepath.Join(tempDir, "opt", "tools", "versions")
+	source := filepath.Join(tempDir, "download")
+	if err := os.WriteFile(source, []byte("binary"), 0600); err != nil {
+		t.Fatalf("Failed to write binary: %v", err)
+	}
+
+	// The first user installs the version into the shared root
+	alice := sharedTestConfig(sharedDir, filepath.Join(tempDir, "alice", ".local", "bin"), source)
+	if err := InstallBinary(alice, "1.0.0"); err != nil {
+		t.Fatalf("InstallBinary() error = %v", err)
+	}
+	sharedBinary := filepath.Join(sharedDir, "tool", "1.0.0", "tool")
+	info, err := os.Stat(sharedBinary)
+	if err != nil {
+		t.Fatalf("Expected binary in shared root: %v", err)
+	}
+	if info.Mode().Perm() != 0755 {
+		t.Errorf("Shared binary mode = %v, want 0755", info.Mode().Perm())
+	}
+
+	// A second user only gets a symlink; the download is not needed
+	bob := sharedTestConfig(sharedDir, filepath.Join(tempDir, "bob", ".local", "bin"), filepath.Join(tempDir, "missing"))
+	if err := InstallBinary(bob, "1.0.0"); err != nil {
+		t.Fatalf("InstallBinary() for second user error = %v", err)
+	}
+	for _, config := range []FileConfig{alice, bob} {
+		target, err := os.Readlink(filepath.Join(config.BaseBinaryDirectory, "tool"))
+		if err != nil {
+			t.Fatalf("Expected symlink in %s: %v", config.BaseBinaryDirectory, err)
+		}
+		if target != sharedBinary {
+			t.Errorf("Symlink target = %s, want %s", target, sharedBinary)
+		}
+		if version, _ := CurrentInstalledVersion(config); version != "1.0.0" {
+			t.Errorf("CurrentInstalledVersion() = %q, want 1.0.0", version)
+		}
+	}
+
+	// Uninstalling for one user keeps the shared version for the other
+	result, err := Uninstall(bob)
+	if err != nil {
+		t.Fatalf("Uninstall() error = %v", err)
+	}
+	if !FileExists(sharedBinary) {
+		t.Error("Expected shared binary to survive a per-user uninstall")
+	}
+	if len(result.Warnings) == 0 {
+		t.Error("Expected a warning about the shared versions left in place")
+	}
+}
+
+func TestSharedInstall_UnwritableRoot(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("permission checks do not apply to root")
+	}
+	tempDir := t.TempDir()
+	sharedDir := filepath.Join(tempDir, "versions")
+	os.MkdirAll(sharedDir, 0555)
+	defer os.Chmod(sharedDir, 0755)
+
+	config := sharedTestConfig(sharedDir, filepath.Join(tempDir, "bin"), filepath.Join(tempDir, "download"))
+	err := InstallBinary(config, "1.0.0")
+	if err == nil || !strings.Contains(err.Error(), "cannot install it there") {
+		t.Errorf("Expected an error explaining the shared root is not writable, got %v", err)
+	}
+}
+
+func TestCheckSharedBinary(t *testing.T) {
+	tempDir := t.TempDir()
+	testCases := []struct {
+		mode    os.FileMode
+		wantErr bool
+	}{
+		{0755, false},
+		{0555, false},
+		{0700, true},
+		{0750, true},
+		{0775, true},
+		{0757, true},
+	}
+
+	for _, tc := range testCases {
+		path := filepath.Join(tempDir, tc.mode.String())
+		os.WriteFile(path, []byte("binary"), 0600)
+		os.Chmod(path, tc.mode)
+		if err := CheckSharedBinary(path); (err != nil) != tc.wantErr {
+			t.Errorf("CheckSharedBinary(%v) error = %v, wantErr %v", tc.mode, err, tc.wantErr)
+		}
+	}
+}
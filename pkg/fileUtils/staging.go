@@ -0,0 +1,88 @@
+package fileUtils
+
+import (
+	"errors"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/archiver"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ErrStagingCollision is returned when two tools would download to the same staging path
+var ErrStagingCollision = errors.New("staging path collision")
+
+var (
+	stagingClaimsMu sync.Mutex
+	stagingClaims   = map[string]string{} // Staging path -> owner of the download in progress
+)
+
+// GetStagingPath returns SourceArchivePath, or where a download of the asset is staged: the asset's
+// file name in the staging directory, prefixed with the project name and provider so that tools
+// sharing a staging directory never write to the same file. Without a usable asset name
+// "binary-{version}.tar.gz" is used. The name is sanitized for the platform, see archiver.SanitizeFileName.
+func GetStagingPath(config FileConfig, provider, version, assetName string) string {
+	if config.SourceArchivePath != "" {
+		return config.SourceArchivePath
+	}
+	name := path.Base(strings.ReplaceAll(assetName, "\\", "/"))
+	if assetName == "" || name == "." || name == ".." || name == "/" {
+		name = fmt.Sprintf("binary-%s.tar.gz", VersionDirectoryName(config, version))
+	}
+	name = archiver.SanitizeFileName(name)
+	return filepath.Join(GetStagingDirectory(config), stagingFilePrefix(config, provider)+name)
+}
+
+// stagingFilePrefix returns "{project}-{provider}-" for staged file names, leaving out parts that are unset
+func stagingFilePrefix(config FileConfig, provider string) string {
+	project := config.ProjectName
+	if project == "" {
+		project = config.BinaryName
+	}
+	var prefix string
+	for _, part := range []string{project, provider} {
+		if part = sanitizeFileNamePart(part); part != "" {
+			prefix += part + "-"
+		}
+	}
+	return prefix
+}
+
+// sanitizeFileNamePart replaces characters that are unsafe in file names, such as path separators
+func sanitizeFileNamePart(part string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '_', r == '-':
+			return r
+		}
+		return '_'
+	}, strings.Trim(part, "."))
+}
+
+// ClaimStagingPath reserves a staging path for owner, e.g. "github:owner/repo", until
+// ReleaseStagingPath is called. Claiming a path held by another owner fails with
+// ErrStagingCollision instead of letting one tool overwrite another tool's download, which happens
+// when tools share an explicit SourceArchivePath. Claiming a path twice for the same owner succeeds.
+// Claims are held in memory, so they only protect against tools running in the same process.
+func ClaimStagingPath(stagingPath, owner string) error {
+	key := filepath.Clean(stagingPath)
+	stagingClaimsMu.Lock()
+	defer stagingClaimsMu.Unlock()
+	if holder, ok := stagingClaims[key]; ok && holder != owner {
+		return fmt.Errorf("%w: %s is in use by %s, configure a distinct SourceArchivePath or StagingDirectory for %s",
+			ErrStagingCollision, stagingPath, holder, owner)
+	}
+	stagingClaims[key] = owner
+	return nil
+}
+
+// ReleaseStagingPath releases a staging path claimed by owner
+func ReleaseStagingPath(stagingPath, owner string) {
+	key := filepath.Clean(stagingPath)
+	stagingClaimsMu.Lock()
+	defer stagingClaimsMu.Unlock()
+	if stagingClaims[key] == owner {
+		delete(stagingClaims, key)
+	}
+}
@@ -0,0 +1,80 @@
+package fileUtils
+
+import (
+	"errors"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestGetStagingPath(t *testing.T) {
+	staging := "/var/lib/updater/staging"
+	testCases := []struct {
+		name      string
+		config    FileConfig
+		provider  string
+		assetName string
+		expected  string
+	}{
+		{"project and provider", FileConfig{StagingDirectory: staging, ProjectName: "helm"}, "github", "helm-linux-amd64.tar.gz", "helm-github-helm-linux-amd64.tar.gz"},
+		{"binary name fallback", FileConfig{StagingDirectory: staging, BinaryName: "tool"}, "gitlab", "tool.zip", "tool-gitlab-tool.zip"},
+		{"default name", FileConfig{StagingDirectory: staging, ProjectName: "kubectl"}, "github", "", "kubectl-github-binary-1.0.0.tar.gz"},
+		{"unsafe project name", FileConfig{StagingDirectory: staging, ProjectName: "../group/tool"}, "gitlab", "tool", "_group_tool-gitlab-tool"},
+		{"no project or provider", FileConfig{StagingDirectory: staging}, "", "tool.zip", "tool.zip"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := GetStagingPath(tc.config, tc.provider, "1.0.0", tc.assetName); got != filepath.Join(staging, tc.expected) {
+				t.Errorf("GetStagingPath() = %s, want %s", got, filepath.Join(staging, tc.expected))
+			}
+		})
+	}
+
+	// Two tools releasing the same version no longer share the default archive path
+	helm := FileConfig{StagingDirectory: staging, ProjectName: "helm"}
+	kubectl := FileConfig{StagingDirectory: staging, ProjectName: "kubectl"}
+	if GetStagingPath(helm, "github", "1.0.0", "") == GetStagingPath(kubectl, "github", "1.0.0", "") {
+		t.Error("Expected different staging paths for different projects")
+	}
+
+	explicit := FileConfig{SourceArchivePath: "/explicit/archive.tar.gz", ProjectName: "helm"}
+	if got := GetStagingPath(explicit, "github", "1.0.0", "tool.zip"); got != "/explicit/archive.tar.gz" {
+		t.Errorf("Expected explicit SourceArchivePath to win, got %s", got)
+	}
+}
+
+func TestGetStagingPath_SanitizesAssetName(t *testing.T) {
+	config := FileConfig{StagingDirectory: "/staging"}
+	expected := "tool_v1_.zip"
+	if runtime.GOOS != "windows" {
+		expected = "tool:v1?.zip"
+	}
+	if got := GetStagingPath(config, "", "1.0.0", "tool:v1?.zip"); got != filepath.Join("/staging", expected) {
+		t.Errorf("GetStagingPath() = %s, want %s", got, filepath.Join("/staging", expected))
+	}
+}
+
+func TestClaimStagingPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "archive.tar.gz")
+
+	if err := ClaimStagingPath(path, "github:owner/helm"); err != nil {
+		t.Fatalf("ClaimStagingPath failed: %v", err)
+	}
+	if err := ClaimStagingPath(path, "github:owner/helm"); err != nil {
+		t.Errorf("Expected the owner to claim its path again, got %v", err)
+	}
+	if err := ClaimStagingPath(path, "gitlab:123"); !errors.Is(err, ErrStagingCollision) {
+		t.Errorf("Expected ErrStagingCollision, got %v", err)
+	}
+
+	// Only the owner releases its claim
+	ReleaseStagingPath(path, "gitlab:123")
+	if err := ClaimStagingPath(path, "gitlab:123"); !errors.Is(err, ErrStagingCollision) {
+		t.Errorf("Expected the claim to survive a release by another owner, got %v", err)
+	}
+	ReleaseStagingPath(path, "github:owner/helm")
+	if err := ClaimStagingPath(path, "gitlab:123"); err != nil {
+		t.Errorf("Expected the released path to be claimable, got %v", err)
+	}
+	ReleaseStagingPath(path, "gitlab:123")
+}
@@ -0,0 +1,97 @@
+package fileUtils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// GetLocalSymlinkName returns the name of the local symlink: LocalSymlinkName if set, otherwise BinaryName
+func GetLocalSymlinkName(config FileConfig) string {
+	if config.LocalSymlinkName != "" {
+		return config.LocalSymlinkName
+	}
+	return config.BinaryName
+}
+
+// GetLocalSymlinkPath returns the path of the local symlink in BaseBinaryDirectory
+func GetLocalSymlinkPath(config FileConfig) string {
+	return filepath.Join(config.BaseBinaryDirectory, GetLocalSymlinkName(config))
+}
+
+// GetGlobalSymlinkDirectory returns GlobalSymlinkDirectory, or /usr/local/bin if it is not set
+func GetGlobalSymlinkDirectory(config FileConfig) string {
+	if config.GlobalSymlinkDirectory != "" {
+		return config.GlobalSymlinkDirectory
+	}
+	return globalSymlinkDirectory
+}
+
+// GetGlobalSymlinkPath returns the path of the global symlink in GetGlobalSymlinkDirectory
+func GetGlobalSymlinkPath(config FileConfig) string {
+	return filepath.Join(GetGlobalSymlinkDirectory(config), GetLocalSymlinkName(config))
+}
+
+// GetSymlinkAliasPaths returns the paths of the configured alias symlinks in BaseBinaryDirectory
+func GetSymlinkAliasPaths(config FileConfig) []string {
+	var paths []string
+	for _, alias := range config.SymlinkAliases {
+		paths = append(paths, filepath.Join(config.BaseBinaryDirectory, alias))
+	}
+	return paths
+}
+
+// validateSymlinkNames rejects symlink names that would escape BaseBinaryDirectory or collide
+func validateSymlinkNames(config FileConfig) error {
+	names := append([]string{GetLocalSymlinkName(config)}, config.SymlinkAliases...)
+	for i, name := range names {
+		if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+			return fmt.Errorf("invalid symlink name %q: must be a plain file name", name)
+		}
+		if containsString(names[:i], name) {
+			return fmt.Errorf("duplicate symlink name %q", name)
+		}
+	}
+	return nil
+}
+
+// createSymlinkAliases points every alias at target and returns the aliases that were created.
+// Existing files that are not symlinks are left alone, and failures only produce warnings.
+func createSymlinkAliases(config FileConfig, target string) []string {
+	var created []string
+	for _, aliasPath := range GetSymlinkAliasPaths(config) {
+		if info, err := fsys().Lstat(aliasPath); err == nil && info.Mode()&os.ModeSymlink == 0 {
+			fmt.Printf("Warning: not replacing %s with an alias symlink because it is not a symlink\n", aliasPath)
+			continue
+		}
+		if TryUpdateSymlink(target, aliasPath) {
+			fmt.Printf("Alias symlink created: %s -> %s\n", aliasPath, target)
+			created = append(created, aliasPath)
+		}
+	}
+	return created
+}
+
+// resolveSymlink returns the absolute target of a symlink in BaseBinaryDirectory
+func resolveSymlink(config FileConfig, symlinkPath string) (string, error) {
+	target, err := fsys().Readlink(symlinkPath)
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(config.BaseBinaryDirectory, target)
+	}
+	return target, nil
+}
+
+// linkedAliases returns the configured aliases that point at the binary
+func linkedAliases(config FileConfig, binaryPath string) []string {
+	var linked []string
+	for _, aliasPath := range GetSymlinkAliasPaths(config) {
+		if target, err := resolveSymlink(config, aliasPath); err == nil && filepath.Clean(target) == filepath.Clean(binaryPath) {
+			linked = append(linked, aliasPath)
+		}
+	}
+	return linked
+}
@@ -3,6 +3,7 @@ package fileUtils
 import (
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 )
 
@@ -307,3 +308,197 @@ func TestBackwardCompatibility_SymlinkDefaults(t *testing.T) {
 		t.Errorf("Expected symlink status 'disabled', got %s", info.SymlinkStatus)
 	}
 }
+
+func TestLocalSymlinkNameAndAliases(t *testing.T) {
+	tempDir := t.TempDir()
+	binaryPath := filepath.Join(tempDir, "download")
+	if err := os.WriteFile(binaryPath, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	baseDir := filepath.Join(tempDir, "bin")
+	config := FileConfig{
+		BaseBinaryDirectory:     baseDir,
+		BinaryName:              "terraform-1.5",
+		ProjectName:             "terraform",
+		LocalSymlinkName:        "terraform",
+		SymlinkAliases:          []string{"tf"},
+		IsDirectBinary:          true,
+		CreateLocalSymlink:      true,
+		UseVersionsSubdirectory: true,
+	}
+	if err := InstallFromFile(config, binaryPath, "v1.5.7", nil); err != nil {
+		t.Fatalf("InstallFromFile() error = %v", err)
+	}
+
+	versionedPath := GetVersionedBinaryPath(config, "v1.5.7")
+	for _, name := range []string{"terraform", "tf"} {
+		target, err := resolveSymlink(config, filepath.Join(baseDir, name))
+		if err != nil || target != versionedPath {
+			t.Errorf("Expected %s to link to %s, got %s (%v)", name, versionedPath, target, err)
+		}
+	}
+	if FileExists(filepath.Join(baseDir, "terraform-1.5")) {
+		t.Error("Expected no symlink named after the versioned binary")
+	}
+
+	info, err := GetInstallationInfo(config, "v1.5.7")
+	if err != nil {
+		t.Fatalf("GetInstallationInfo() error = %v", err)
+	}
+	if info.BinaryPath != filepath.Join(baseDir, "terraform") || len(info.AliasPaths) != 1 {
+		t.Errorf("Unexpected installation info: %+v", info)
+	}
+	if version, err := CurrentInstalledVersion(config); err != nil || version != "v1.5.7" {
+		t.Errorf("CurrentInstalledVersion() = %s, %v", version, err)
+	}
+	receipt, err := GetInstallReceipt(config)
+	if err != nil || len(receipt.Aliases) != 1 || receipt.Aliases[0] != filepath.Join(baseDir, "tf") {
+		t.Fatalf("Expected the alias in the receipt, got %+v (%v)", receipt, err)
+	}
+
+	// Aliases are removed with the installation, even once dropped from the configuration
+	config.SymlinkAliases = nil
+	if _, err := Uninstall(config); err != nil {
+		t.Fatalf("Uninstall() error = %v", err)
+	}
+	for _, name := range []string{"terraform", "tf"} {
+		if _, err := os.Lstat(filepath.Join(baseDir, name)); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be removed, got %v", name, err)
+		}
+	}
+}
+
+func TestValidateSymlinkNames(t *testing.T) {
+	tests := []struct {
+		config  FileConfig
+		wantErr bool
+	}{
+		{FileConfig{BinaryName: "terraform", SymlinkAliases: []string{"tf"}}, false},
+		{FileConfig{BinaryName: "kubectl-1.28", LocalSymlinkName: "kubectl"}, false},
+		{FileConfig{BinaryName: "terraform", SymlinkAliases: []string{"../tf"}}, true},
+		{FileConfig{BinaryName: "terraform", SymlinkAliases: []string{"terraform"}}, true},
+		{FileConfig{BinaryName: "terraform", LocalSymlinkName: ".."}, true},
+	}
+	for _, tt := range tests {
+		if err := validateSymlinkNames(tt.config); (err != nil) != tt.wantErr {
+			t.Errorf("validateSymlinkNames(%+v) error = %v, wantErr %v", tt.config, err, tt.wantErr)
+		}
+	}
+}
+
+func TestCopyFallbackWithoutSymlinks(t *testing.T) {
+	originalCreateSymlink := createSymlink
+	createSymlink = func(string, string) error { return &os.LinkError{Op: "symlink", Err: os.ErrPermission} }
+	defer func() { createSymlink = originalCreateSymlink }()
+
+	tempDir := t.TempDir()
+	binaryPath := filepath.Join(tempDir, "download")
+	baseDir := filepath.Join(tempDir, "bin")
+	config := FileConfig{
+		BaseBinaryDirectory:     baseDir,
+		BinaryName:              "mytool",
+		IsDirectBinary:          true,
+		CreateLocalSymlink:      true,
+		UseVersionsSubdirectory: true,
+	}
+	for _, version := range []string{"v1.0.0", "v1.1.0"} {
+		if err := os.WriteFile(binaryPath, []byte("#!/bin/sh\necho "+version+"\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := InstallFromFile(config, binaryPath, version, nil); err != nil {
+			t.Fatalf("InstallFromFile(%s) error = %v", version, err)
+		}
+	}
+
+	localPath := filepath.Join(baseDir, "mytool")
+	content, err := os.ReadFile(localPath)
+	if err != nil || string(content) != "#!/bin/sh\necho v1.1.0\n" {
+		t.Fatalf("Expected a copy of the latest binary at %s, got %q (%v)", localPath, content, err)
+	}
+	if info, err := os.Stat(localPath); err != nil || info.Mode().Perm()&0111 == 0 {
+		t.Errorf("Expected the copy to be executable, got %v (%v)", info.Mode(), err)
+	}
+
+	info, err := GetInstallationInfo(config, "v1.1.0")
+	if err != nil {
+		t.Fatalf("GetInstallationInfo() error = %v", err)
+	}
+	if info.SymlinkStatus != "copy" || info.BinaryPath != localPath {
+		t.Errorf("Expected the copy to be reported, got %+v", info)
+	}
+	if info, _ := GetInstallationInfo(config, "v1.0.0"); info.SymlinkStatus != "failed" {
+		t.Errorf("Expected the older version not to be linked, got %s", info.SymlinkStatus)
+	}
+	if version, err := CurrentInstalledVersion(config); err != nil || version != "v1.1.0" {
+		t.Errorf("CurrentInstalledVersion() = %s, %v", version, err)
+	}
+
+	if _, err := Uninstall(config); err != nil {
+		t.Fatalf("Uninstall() error = %v", err)
+	}
+	if FileExists(localPath) {
+		t.Error("Expected Uninstall to remove the copy")
+	}
+}
+
+func TestUpdateSymlink_ReplacesAtomically(t *testing.T) {
+	tempDir := t.TempDir()
+	for _, version := range []string{"1.0.0", "2.0.0"} {
+		os.MkdirAll(filepath.Join(tempDir, version), 0755)
+		os.WriteFile(filepath.Join(tempDir, version, "tool"), []byte(version), 0755)
+	}
+	linkPath := filepath.Join(tempDir, "tool")
+	if err := UpdateSymlink(filepath.Join("1.0.0", "tool"), linkPath); err != nil {
+		t.Fatalf("UpdateSymlink() error = %v", err)
+	}
+
+	// A failure to create the new link keeps the previous one
+	originalCreateSymlink := createSymlink
+	createSymlink = func(string, string) error { return &os.LinkError{Op: "symlink", Err: os.ErrPermission} }
+	err := UpdateSymlink(filepath.Join("2.0.0", "tool"), linkPath)
+	createSymlink = originalCreateSymlink
+	if err == nil {
+		t.Fatal("Expected UpdateSymlink() to fail")
+	}
+	if target, err := os.Readlink(linkPath); err != nil || target != filepath.Join("1.0.0", "tool") {
+		t.Errorf("Expected the previous link to be kept, got %q, %v", target, err)
+	}
+
+	if err := UpdateSymlink(filepath.Join("2.0.0", "tool"), linkPath); err != nil {
+		t.Fatalf("UpdateSymlink() error = %v", err)
+	}
+	if data, err := os.ReadFile(linkPath); err != nil || string(data) != "2.0.0" {
+		t.Errorf("Expected the link to point at 2.0.0, got %q, %v", data, err)
+	}
+	entries, _ := os.ReadDir(tempDir)
+	if len(entries) != 3 {
+		t.Errorf("Expected no temporary link to be left behind, found %d entries", len(entries))
+	}
+}
+
+func TestUpdateSymlink_Concurrent(t *testing.T) {
+	tempDir := t.TempDir()
+	os.WriteFile(filepath.Join(tempDir, "tool-1.0.0"), []byte("binary"), 0755)
+	linkPath := filepath.Join(tempDir, "tool")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+	for i := 0; i < cap(errs); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- UpdateSymlink("tool-1.0.0", linkPath)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("UpdateSymlink() error = %v", err)
+		}
+	}
+	if entries, _ := os.ReadDir(tempDir); len(entries) != 2 {
+		t.Errorf("Expected only the binary and the link, found %d entries", len(entries))
+	}
+}
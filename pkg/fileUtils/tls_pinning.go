@@ -0,0 +1,214 @@
+package fileUtils
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrTLSPinMismatch is returned (wrapped in a TLSPinError) when a pinned host presents a
+// certificate chain that matches none of its pins
+var ErrTLSPinMismatch = NewError(ErrorCodeChecksum, "TLS certificate does not match pinned keys")
+
+// TLSPin restricts the certificates a provider or CDN host may present. A connection is accepted
+// if any certificate in the presented or verified chain matches one of the pins, so pinning an
+// intermediate CA keeps working across leaf certificate renewals. Hosts are matched by the
+// server name sent during the handshake, so connections to bare IP addresses cannot be pinned.
+type TLSPin struct {
+	Host              string   `json:"host"`               // Host name, or "*.example.com" for all subdomains
+	SPKISHA256        []string `json:"spki_sha256"`        // Base64 SHA-256 of the SubjectPublicKeyInfo (as in "pin-sha256")
+	CertificateSHA256 []string `json:"certificate_sha256"` // Hex SHA-256 of the DER-encoded certificate
+}
+
+// TLSPinError describes which host failed pin verification and what it presented
+type TLSPinError struct {
+	Host      string   // Host name the connection was made to
+	Presented []string // Base64 SPKI SHA-256 pins of the presented certificates, leaf first
+}
+
+func (e *TLSPinError) Error() string {
+	return fmt.Sprintf("TLS certificate of %s does not match pinned keys (presented pin-sha256: %s)",
+		e.Host, strings.Join(e.Presented, ", "))
+}
+
+// Is makes errors.Is(err, ErrTLSPinMismatch) match
+func (e *TLSPinError) Is(target error) bool {
+	return target == ErrTLSPinMismatch
+}
+
+// ErrorCode returns ErrorCodeChecksum
+func (e *TLSPinError) ErrorCode() ErrorCode {
+	return ErrorCodeChecksum
+}
+
+var (
+	tlsPinsMu sync.RWMutex
+	tlsPins   []TLSPin
+)
+
+// SetTLSPins configures certificate pinning for all connections made by the library; nil disables
+// pinning. Hosts without a pin are verified with the regular certificate checks only.
+func SetTLSPins(pins []TLSPin) error {
+	for _, pin := range pins {
+		if pin.Host == "" {
+			return fmt.Errorf("TLS pin without a host")
+		}
+		if len(pin.SPKISHA256) == 0 && len(pin.CertificateSHA256) == 0 {
+			return fmt.Errorf("TLS pin for %s has no SPKI or certificate hashes", pin.Host)
+		}
+		for _, value := range pin.SPKISHA256 {
+			if hash, err := base64.StdEncoding.DecodeString(value); err != nil || len(hash) != sha256.Size {
+				return fmt.Errorf("invalid SPKI SHA-256 pin %q for %s: expected base64-encoded SHA-256", value, pin.Host)
+			}
+		}
+		for _, value := range pin.CertificateSHA256 {
+			if hash, err := hex.DecodeString(normalizeFingerprint(value)); err != nil || len(hash) != sha256.Size {
+				return fmt.Errorf("invalid certificate SHA-256 pin %q for %s: expected hex-encoded SHA-256", value, pin.Host)
+			}
+		}
+	}
+
+	tlsPinsMu.Lock()
+	tlsPins = append([]TLSPin(nil), pins...)
+	tlsPinsMu.Unlock()
+
+	// Connections verified against the previous pins must not be reused
+	pinnedTransportMu.Lock()
+	previous := pinnedTransport
+	pinnedTransport = nil
+	pinnedTransportMu.Unlock()
+	if previous != nil {
+		previous.CloseIdleConnections()
+	}
+	return nil
+}
+
+// GetTLSPins returns the configured certificate pins
+func GetTLSPins() []TLSPin {
+	tlsPinsMu.RLock()
+	defer tlsPinsMu.RUnlock()
+	return append([]TLSPin(nil), tlsPins...)
+}
+
+// SPKIPin returns the base64 SHA-256 pin of a certificate's public key, the value for SPKISHA256
+func SPKIPin(cert *x509.Certificate) string {
+	hash := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+// NewHTTPClient returns an HTTP client that enforces the configured TLS pins and drops credentials
+// on redirects to other hosts. Pins are looked up at connection time, so clients created before
+// SetTLSPins enforce them as well. All clients share one transport and its connection pool.
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{Transport: sharedTransport{}, Timeout: timeout, CheckRedirect: dropCredentialsOnRedirect}
+}
+
+var (
+	pinnedTransportMu sync.Mutex
+	pinnedTransport   *http.Transport // Built on first use and again after the pins change
+)
+
+// newPinnedTransport returns a transport like http.DefaultTransport that verifies the TLS pins
+func newPinnedTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{VerifyConnection: verifyTLSPins}
+	return transport
+}
+
+// currentPinnedTransport returns the shared pinned transport, building it if needed
+func currentPinnedTransport() *http.Transport {
+	pinnedTransportMu.Lock()
+	defer pinnedTransportMu.Unlock()
+	if pinnedTransport == nil {
+		pinnedTransport = newPinnedTransport()
+	}
+	return pinnedTransport
+}
+
+// sharedTransport sends requests through the shared pinned transport, so idle connections are
+// reused by every client instead of each client keeping a pool of its own
+type sharedTransport struct{}
+
+func (sharedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return currentPinnedTransport().RoundTrip(req)
+}
+
+// CloseIdleConnections closes the idle connections of the shared transport
+func (sharedTransport) CloseIdleConnections() {
+	currentPinnedTransport().CloseIdleConnections()
+}
+
+// pinnedHTTPClient is shared by downloads that do not need their own timeout
+var pinnedHTTPClient = NewHTTPClient(0)
+
+// verifyTLSPins checks the connection against the pins configured for its server name
+func verifyTLSPins(state tls.ConnectionState) error {
+	pin, ok := tlsPinForHost(state.ServerName)
+	if !ok {
+		return nil
+	}
+
+	certificates := state.PeerCertificates
+	for _, chain := range state.VerifiedChains {
+		certificates = append(certificates, chain...)
+	}
+	for _, cert := range certificates {
+		if pin.matches(cert) {
+			return nil
+		}
+	}
+
+	presented := make([]string, 0, len(state.PeerCertificates))
+	for _, cert := range state.PeerCertificates {
+		presented = append(presented, SPKIPin(cert))
+	}
+	return &TLSPinError{Host: state.ServerName, Presented: presented}
+}
+
+// tlsPinForHost returns the pin for a host, preferring exact matches over wildcards
+func tlsPinForHost(host string) (TLSPin, bool) {
+	host = strings.ToLower(host)
+	var wildcard *TLSPin
+	for _, pin := range GetTLSPins() {
+		pattern := strings.ToLower(pin.Host)
+		if pattern == host {
+			return pin, true
+		}
+		if suffix, ok := strings.CutPrefix(pattern, "*"); ok && strings.HasSuffix(host, suffix) && wildcard == nil {
+			wildcard = &pin
+		}
+	}
+	if wildcard != nil {
+		return *wildcard, true
+	}
+	return TLSPin{}, false
+}
+
+// matches reports whether the certificate's public key or DER encoding is pinned
+func (p TLSPin) matches(cert *x509.Certificate) bool {
+	spki := SPKIPin(cert)
+	for _, value := range p.SPKISHA256 {
+		if value == spki {
+			return true
+		}
+	}
+	fingerprint := sha256.Sum256(cert.Raw)
+	for _, value := range p.CertificateSHA256 {
+		if strings.EqualFold(normalizeFingerprint(value), hex.EncodeToString(fingerprint[:])) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeFingerprint strips the colons of fingerprints in "AB:CD:..." notation
+func normalizeFingerprint(value string) string {
+	return strings.ReplaceAll(value, ":", "")
+}
@@ -0,0 +1,136 @@
+package fileUtils
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// pinnedTestClient returns a client that trusts the test server and reaches it as example.com,
+// the name in httptest's certificate, so pins are matched by host name
+func pinnedTestClient(server *httptest.Server) *http.Client {
+	transport := newPinnedTransport()
+	client := &http.Client{Transport: transport}
+	transport.TLSClientConfig.RootCAs = x509.NewCertPool()
+	transport.TLSClientConfig.RootCAs.AddCert(server.Certificate())
+	transport.DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, server.Listener.Addr().String())
+	}
+	return client
+}
+
+func TestNewHTTPClient_TLSPinning(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	t.Cleanup(func() { SetTLSPins(nil) })
+
+	fingerprint := sha256.Sum256(server.Certificate().Raw)
+	colonFingerprint := strings.ToUpper(hex.EncodeToString(fingerprint[:]))
+	for i := len(colonFingerprint) - 2; i > 0; i -= 2 {
+		colonFingerprint = colonFingerprint[:i] + ":" + colonFingerprint[i:]
+	}
+	otherPin := "47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="
+
+	testCases := []struct {
+		name     string
+		pins     []TLSPin
+		mismatch bool
+	}{
+		{"no pins", nil, false},
+		{"other host pinned", []TLSPin{{Host: "github.com", SPKISHA256: []string{otherPin}}}, false},
+		{"matching SPKI", []TLSPin{{Host: "example.com", SPKISHA256: []string{otherPin, SPKIPin(server.Certificate())}}}, false},
+		{"matching certificate", []TLSPin{{Host: "example.com", CertificateSHA256: []string{colonFingerprint}}}, false},
+		{"mismatch", []TLSPin{{Host: "example.com", SPKISHA256: []string{otherPin}}}, true},
+		{"wildcard mismatch", []TLSPin{{Host: "*.com", SPKISHA256: []string{otherPin}}}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := SetTLSPins(tc.pins); err != nil {
+				t.Fatalf("SetTLSPins() error = %v", err)
+			}
+			resp, err := pinnedTestClient(server).Get("https://example.com/")
+			if tc.mismatch {
+				var pinErr *TLSPinError
+				if !errors.Is(err, ErrTLSPinMismatch) || !errors.As(err, &pinErr) {
+					t.Fatalf("Expected TLSPinError, got %v", err)
+				}
+				if pinErr.Host != "example.com" || len(pinErr.Presented) == 0 || pinErr.Presented[0] != SPKIPin(server.Certificate()) {
+					t.Errorf("Unexpected pin error: %+v", pinErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected request to succeed, got %v", err)
+			}
+			resp.Body.Close()
+		})
+	}
+}
+
+func TestSetTLSPins_Invalid(t *testing.T) {
+	t.Cleanup(func() { SetTLSPins(nil) })
+
+	invalid := [][]TLSPin{
+		{{SPKISHA256: []string{"47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU="}}},
+		{{Host: "example.com"}},
+		{{Host: "example.com", SPKISHA256: []string{"not base64"}}},
+		{{Host: "example.com", CertificateSHA256: []string{"abcd"}}},
+	}
+	for _, pins := range invalid {
+		if err := SetTLSPins(pins); err == nil {
+			t.Errorf("SetTLSPins(%+v) expected error", pins)
+		}
+	}
+	if len(GetTLSPins()) != 0 {
+		t.Error("Expected invalid pins not to be stored")
+	}
+}
+
+func TestNewHTTPClient_SharesConnections(t *testing.T) {
+	var connections atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+	t.Cleanup(func() { SetTLSPins(nil) })
+
+	get := func(client *http.Client) {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+	get(NewHTTPClient(0))
+	get(NewHTTPClient(time.Minute))
+	if n := connections.Load(); n != 1 {
+		t.Errorf("Expected clients to reuse one connection, got %d connections", n)
+	}
+
+	// Changing the pins drops connections verified against the previous ones
+	SetTLSPins(nil)
+	get(NewHTTPClient(0))
+	if n := connections.Load(); n != 2 {
+		t.Errorf("Expected a new connection after the pins changed, got %d connections", n)
+	}
+}
@@ -0,0 +1,189 @@
+package fileUtils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// installStep is an entry of the installation transaction log
+type installStep struct {
+	action string       // What the step did: "mkdir", "backup", "rename" or "symlink"
+	path   string       // Path the step changed
+	undo   func() error // Reverts the step
+}
+
+// installTransaction logs the changes an installation makes to the file system. A failed
+// installation is rolled back in reverse order, so the new version directory is removed and the
+// previous version directory and symlinks are restored instead of pointing at a partial install.
+// Extracted and copied files are not logged individually because they are written inside a version
+// directory the transaction created.
+type installTransaction struct {
+	steps   []installStep
+	cleanup []func() // Discards backups once the installation succeeded
+}
+
+// record appends a step to the transaction log
+func (tx *installTransaction) record(action, path string, undo func() error) {
+	tx.steps = append(tx.steps, installStep{action: action, path: path, undo: undo})
+}
+
+// createDirectories runs create and logs the topmost directory it created for each of paths, so a
+// rollback removes them along with any parents that did not exist before
+func (tx *installTransaction) createDirectories(create func() error, paths ...string) error {
+	var missing []string
+	for _, path := range paths {
+		if created := firstMissingDirectory(path); created != "" {
+			missing = append(missing, created)
+		}
+	}
+	err := create()
+	for _, path := range missing {
+		if _, statErr := fsys().Lstat(path); statErr == nil {
+			tx.record("mkdir", path, func() error { return fsys().RemoveAll(path) })
+		}
+	}
+	return err
+}
+
+// firstMissingDirectory returns the topmost ancestor of path, or path itself, that does not exist
+func firstMissingDirectory(path string) string {
+	path = filepath.Clean(path)
+	missing := ""
+	for {
+		if _, err := fsys().Lstat(path); err == nil {
+			return missing
+		}
+		missing = path
+		parent := filepath.Dir(path)
+		if parent == path {
+			return missing
+		}
+		path = parent
+	}
+}
+
+// backupDirectory moves an existing directory, such as the version directory of a reinstalled
+// version, aside so the installation starts from an empty directory. A rollback restores it and a
+// successful installation deletes it.
+func (tx *installTransaction) backupDirectory(path string) error {
+	info, err := fsys().Lstat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to inspect %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		return nil
+	}
+	backupPath := rollbackPath(path)
+	fsys().RemoveAll(backupPath)
+	if err := fsys().Rename(path, backupPath); err != nil {
+		return fmt.Errorf("failed to back up %s: %w", path, err)
+	}
+	tx.record("backup", path, func() error {
+		if err := fsys().RemoveAll(path); err != nil {
+			return err
+		}
+		return fsys().Rename(backupPath, path)
+	})
+	tx.cleanup = append(tx.cleanup, func() { fsys().RemoveAll(backupPath) })
+	return nil
+}
+
+// rename moves a file and logs the move so a rollback moves it back
+func (tx *installTransaction) rename(oldpath, newpath string) error {
+	if err := fsys().Rename(oldpath, newpath); err != nil {
+		return err
+	}
+	tx.record("rename", newpath, func() error { return fsys().Rename(newpath, oldpath) })
+	return nil
+}
+
+// saveLinks logs the current state of symlink paths before they are updated. A rollback points
+// symlinks back at their previous target, restores a previous copy of the binary (see
+// linkLocalBinary) and removes paths that did not exist.
+func (tx *installTransaction) saveLinks(paths ...string) error {
+	for _, path := range paths {
+		info, err := fsys().Lstat(path)
+		switch {
+		case os.IsNotExist(err):
+			tx.record("symlink", path, func() error { return removeIfExists(path) })
+		case err != nil:
+			return fmt.Errorf("failed to inspect %s: %w", path, err)
+		case info.Mode()&os.ModeSymlink != 0:
+			target, err := fsys().Readlink(path)
+			if err != nil {
+				return fmt.Errorf("failed to read symlink %s: %w", path, err)
+			}
+			tx.record("symlink", path, func() error {
+				if err := removeIfExists(path); err != nil {
+					return err
+				}
+				return fsys().Symlink(target, path)
+			})
+		case info.Mode().IsRegular():
+			// A copy made because symlinks are unsupported is replaced by the link step, so keep it aside
+			backupPath := rollbackPath(path)
+			if err := copyBinary(path, backupPath); err != nil {
+				return fmt.Errorf("failed to back up %s: %w", path, err)
+			}
+			tx.record("symlink", path, func() error { return fsys().Rename(backupPath, path) })
+			tx.cleanup = append(tx.cleanup, func() { fsys().Remove(backupPath) })
+		}
+	}
+	return nil
+}
+
+// removeIfExists removes a file or symlink, ignoring paths that do not exist
+func removeIfExists(path string) error {
+	if err := fsys().Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// rollbackPath returns the hidden path a backup of path is kept at during an installation
+func rollbackPath(path string) string {
+	return filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".rollback")
+}
+
+// finish commits the transaction when the installation succeeded and rolls it back when it failed
+// or panicked. It must be deferred by the installation so err holds the installation's result.
+func (tx *installTransaction) finish(err *error) {
+	if recovered := recover(); recovered != nil {
+		tx.rollback(fmt.Errorf("panic: %v", recovered))
+		panic(recovered)
+	}
+	if *err != nil {
+		*err = tx.rollback(*err)
+		return
+	}
+	for _, discard := range tx.cleanup {
+		discard()
+	}
+	tx.steps, tx.cleanup = nil, nil
+}
+
+// rollback reverts the logged steps in reverse order and returns cause, extended with the steps
+// that could not be reverted
+func (tx *installTransaction) rollback(cause error) error {
+	if len(tx.steps) == 0 {
+		return cause
+	}
+	fmt.Printf("Installation failed, rolling back: %v\n", cause)
+	var failures []string
+	for i := len(tx.steps) - 1; i >= 0; i-- {
+		step := tx.steps[i]
+		if err := step.undo(); err != nil {
+			failures = append(failures, fmt.Sprintf("%s %s: %v", step.action, step.path, err))
+		}
+	}
+	tx.steps, tx.cleanup = nil, nil
+	if len(failures) > 0 {
+		return fmt.Errorf("%w (rollback incomplete: %s)", cause, strings.Join(failures, "; "))
+	}
+	return cause
+}
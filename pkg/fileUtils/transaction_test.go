@@ -0,0 +1,153 @@
+package fileUtils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInstallFromFile_RollsBackFailedInstall(t *testing.T) {
+	tempDir := t.TempDir()
+	config := FileConfig{
+		BaseBinaryDirectory:    filepath.Join(tempDir, "bin"),
+		VersionedDirectoryName: "versions",
+		BinaryName:             "tool",
+		SourceBinaryName:       "tool",
+		CreateLocalSymlink:     true,
+	}
+
+	goodArchive := filepath.Join(tempDir, "good.tar.gz")
+	createTestArchiveWithFiles(t, goodArchive, map[string]string{"tool": "version 1"})
+	if err := InstallFromFile(config, goodArchive, "1.0.0", nil); err != nil {
+		t.Fatalf("InstallFromFile() error = %v", err)
+	}
+
+	// The archive does not contain the binary, so the installation fails after extraction
+	brokenArchive := filepath.Join(tempDir, "broken.tar.gz")
+	createTestArchiveWithFiles(t, brokenArchive, map[string]string{"README.md": "no binary"})
+	testCases := []struct {
+		name    string
+		version string
+	}{
+		{"NewVersion", "2.0.0"},
+		{"ReinstalledVersion", "1.0.0"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := InstallFromFile(config, brokenArchive, tc.version, nil); err == nil {
+				t.Fatal("Expected installing an archive without the binary to fail")
+			}
+
+			if current, err := CurrentInstalledVersion(config); err != nil || current != "1.0.0" {
+				t.Errorf("CurrentInstalledVersion() = %q, %v, want the symlink to keep pointing at 1.0.0", current, err)
+			}
+			if data, err := os.ReadFile(GetLocalSymlinkPath(config)); err != nil || string(data) != "version 1" {
+				t.Errorf("Expected the previous binary through the symlink, got %q, %v", data, err)
+			}
+			if _, err := os.Stat(GetVersionedDirectoryPath(config, "2.0.0")); !os.IsNotExist(err) {
+				t.Errorf("Expected the failed version directory to be removed, Stat() error = %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(GetVersionedDirectoryPath(config, "1.0.0"), "README.md")); !os.IsNotExist(err) {
+				t.Error("Expected the previous version directory to be restored without the failed extraction")
+			}
+			if _, err := os.Stat(rollbackPath(GetVersionedDirectoryPath(config, "1.0.0"))); !os.IsNotExist(err) {
+				t.Error("Expected no backup to be left behind")
+			}
+		})
+	}
+
+	// A successful reinstallation replaces the version directory and discards the backup
+	createTestArchiveWithFiles(t, goodArchive, map[string]string{"tool": "version 1, rebuilt"})
+	if err := InstallFromFile(config, goodArchive, "1.0.0", nil); err != nil {
+		t.Fatalf("InstallFromFile() reinstall error = %v", err)
+	}
+	if data, _ := os.ReadFile(GetVersionedBinaryPath(config, "1.0.0")); string(data) != "version 1, rebuilt" {
+		t.Errorf("Expected the reinstalled binary, got %q", data)
+	}
+	if _, err := os.Stat(rollbackPath(GetVersionedDirectoryPath(config, "1.0.0"))); !os.IsNotExist(err) {
+		t.Error("Expected the backup to be discarded after a successful installation")
+	}
+}
+
+func TestInstallFromFile_RollbackRemovesCreatedDirectories(t *testing.T) {
+	tempDir := t.TempDir()
+	config := FileConfig{
+		BaseBinaryDirectory:    filepath.Join(tempDir, "bin"),
+		VersionedDirectoryName: "versions",
+		BinaryName:             "tool",
+		SourceBinaryName:       "tool",
+		CreateLocalSymlink:     true,
+	}
+	archivePath := filepath.Join(tempDir, "broken.tar.gz")
+	createTestArchiveWithFiles(t, archivePath, map[string]string{"README.md": "no binary"})
+
+	if err := InstallFromFile(config, archivePath, "1.0.0", nil); err == nil {
+		t.Fatal("Expected installing an archive without the binary to fail")
+	}
+	if _, err := os.Stat(config.BaseBinaryDirectory); !os.IsNotExist(err) {
+		t.Errorf("Expected the directories created by the failed installation to be removed, Stat() error = %v", err)
+	}
+}
+
+func TestInstallTransaction_RestoresLinks(t *testing.T) {
+	tempDir := t.TempDir()
+	for _, version := range []string{"1.0.0", "2.0.0"} {
+		os.MkdirAll(filepath.Join(tempDir, version), 0755)
+		os.WriteFile(filepath.Join(tempDir, version, "tool"), []byte(version), 0755)
+	}
+	linkPath := filepath.Join(tempDir, "tool")
+	os.Symlink(filepath.Join("1.0.0", "tool"), linkPath)
+	copyPath := filepath.Join(tempDir, "tool-copy")
+	os.WriteFile(copyPath, []byte("1.0.0"), 0755)
+	aliasPath := filepath.Join(tempDir, "t")
+
+	tx := &installTransaction{}
+	if err := tx.saveLinks(linkPath, copyPath, aliasPath); err != nil {
+		t.Fatalf("saveLinks() error = %v", err)
+	}
+	UpdateSymlink(filepath.Join("2.0.0", "tool"), linkPath)
+	copyBinary(filepath.Join(tempDir, "2.0.0", "tool"), copyPath)
+	UpdateSymlink(filepath.Join("2.0.0", "tool"), aliasPath)
+
+	failure := errors.New("verification failed")
+	err := failure
+	tx.finish(&err)
+	if !errors.Is(err, failure) {
+		t.Errorf("finish() error = %v, want the installation's error", err)
+	}
+	if target, err := os.Readlink(linkPath); err != nil || target != filepath.Join("1.0.0", "tool") {
+		t.Errorf("Expected the symlink to point at 1.0.0 again, got %q, %v", target, err)
+	}
+	if data, _ := os.ReadFile(copyPath); string(data) != "1.0.0" {
+		t.Errorf("Expected the previous copy to be restored, got %q", data)
+	}
+	if _, err := os.Lstat(aliasPath); !os.IsNotExist(err) {
+		t.Errorf("Expected the new alias to be removed, Lstat() error = %v", err)
+	}
+	if _, err := os.Lstat(rollbackPath(copyPath)); !os.IsNotExist(err) {
+		t.Error("Expected no backup of the copy to be left behind")
+	}
+}
+
+func TestInstallTransaction_RollsBackOnPanic(t *testing.T) {
+	tempDir := t.TempDir()
+	versionDir := filepath.Join(tempDir, "versions", "1.0.0")
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected the panic to be propagated")
+			}
+		}()
+		var err error
+		tx := &installTransaction{}
+		defer tx.finish(&err)
+		tx.createDirectories(func() error { return os.MkdirAll(versionDir, 0755) }, versionDir)
+		panic("extraction bug")
+	}()
+
+	if _, err := os.Stat(filepath.Join(tempDir, "versions")); !os.IsNotExist(err) {
+		t.Errorf("Expected the created directories to be removed, Stat() error = %v", err)
+	}
+}
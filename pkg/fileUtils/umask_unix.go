@@ -0,0 +1,37 @@
+//go:build unix
+
+package fileUtils
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+var (
+	umaskOnce sync.Once
+	umask     os.FileMode
+)
+
+// currentUmask returns the process umask. Linux exposes it in /proc without modifying it;
+// elsewhere it is read by setting and immediately restoring it, once per process.
+func currentUmask() os.FileMode {
+	umaskOnce.Do(func() {
+		if data, err := os.ReadFile("/proc/self/status"); err == nil {
+			for _, line := range strings.Split(string(data), "\n") {
+				if value, ok := strings.CutPrefix(line, "Umask:"); ok {
+					if mask, err := strconv.ParseUint(strings.TrimSpace(value), 8, 32); err == nil {
+						umask = os.FileMode(mask)
+						return
+					}
+				}
+			}
+		}
+		mask := syscall.Umask(0)
+		syscall.Umask(mask)
+		umask = os.FileMode(mask)
+	})
+	return umask
+}
@@ -0,0 +1,67 @@
+package fileUtils
+
+import (
+	"debug/macho"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// machoCpuArch maps Mach-O CPU types to Go architecture names
+var machoCpuArch = map[macho.Cpu]string{
+	macho.Cpu386:   "386",
+	macho.CpuAmd64: "amd64",
+	macho.CpuArm:   "arm",
+	macho.CpuArm64: "arm64",
+	macho.CpuPpc:   "ppc",
+	macho.CpuPpc64: "ppc64",
+}
+
+// UniversalBinaryArchitectures returns the Go architecture names contained in a macOS
+// universal (lipo-style fat) binary. The boolean is false if the file is not a universal binary.
+func UniversalBinaryArchitectures(path string) ([]string, bool, error) {
+	file, err := fsys().Open(path)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read universal binary %s: %v", path, err)
+	}
+	defer file.Close()
+	fat, err := macho.NewFatFile(file)
+	if err != nil {
+		if errors.Is(err, macho.ErrNotFat) {
+			return nil, false, nil
+		}
+		var formatErr *macho.FormatError
+		if errors.As(err, &formatErr) {
+			// Not a Mach-O file at all (e.g. ELF, PE, scripts)
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to read universal binary %s: %v", path, err)
+	}
+	defer fat.Close()
+
+	arches := make([]string, 0, len(fat.Arches))
+	for _, arch := range fat.Arches {
+		if name, ok := machoCpuArch[arch.Cpu]; ok {
+			arches = append(arches, name)
+		} else {
+			arches = append(arches, arch.Cpu.String())
+		}
+	}
+	return arches, true, nil
+}
+
+// verifyUniversalBinary ensures a universal binary contains a slice for the target architecture.
+// Non-universal binaries are accepted unchanged.
+func verifyUniversalBinary(path, goarch string) error {
+	arches, isUniversal, err := UniversalBinaryArchitectures(path)
+	if err != nil || !isUniversal {
+		return err
+	}
+
+	for _, arch := range arches {
+		if arch == goarch {
+			return nil
+		}
+	}
+	return fmt.Errorf("universal binary %s does not contain %s (contains: %s)", path, goarch, strings.Join(arches, ", "))
+}
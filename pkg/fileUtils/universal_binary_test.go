@@ -0,0 +1,85 @@
+package fileUtils
+
+import (
+	"debug/macho"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFatBinary writes a minimal universal binary containing empty 64-bit Mach-O slices
+func writeFatBinary(t *testing.T, path string, cpus ...macho.Cpu) {
+	t.Helper()
+	const sliceSize = 32
+	const align = 12
+
+	header := make([]byte, 8+20*len(cpus))
+	binary.BigEndian.PutUint32(header[0:], macho.MagicFat)
+	binary.BigEndian.PutUint32(header[4:], uint32(len(cpus)))
+
+	data := make([]byte, 0, (len(cpus)+1)<<align)
+	data = append(data, header...)
+	for i, cpu := range cpus {
+		offset := uint32(i+1) << align
+		entry := header[8+20*i:]
+		binary.BigEndian.PutUint32(entry[0:], uint32(cpu))
+		binary.BigEndian.PutUint32(entry[8:], offset)
+		binary.BigEndian.PutUint32(entry[12:], sliceSize)
+		binary.BigEndian.PutUint32(entry[16:], align)
+
+		slice := make([]byte, sliceSize)
+		binary.LittleEndian.PutUint32(slice[0:], macho.Magic64)
+		binary.LittleEndian.PutUint32(slice[4:], uint32(cpu))
+		binary.LittleEndian.PutUint32(slice[12:], uint32(macho.TypeExec))
+
+		for uint32(len(data)) < offset {
+			data = append(data, 0)
+		}
+		data = append(data, slice...)
+	}
+	copy(data, header)
+
+	if err := os.WriteFile(path, data, 0755); err != nil {
+		t.Fatalf("Failed to write fat binary: %v", err)
+	}
+}
+
+func TestUniversalBinaryArchitectures(t *testing.T) {
+	tempDir := t.TempDir()
+
+	fatPath := filepath.Join(tempDir, "universal")
+	writeFatBinary(t, fatPath, macho.CpuAmd64, macho.CpuArm64)
+
+	arches, isUniversal, err := UniversalBinaryArchitectures(fatPath)
+	if err != nil {
+		t.Fatalf("UniversalBinaryArchitectures failed: %v", err)
+	}
+	if !isUniversal {
+		t.Fatal("Expected file to be detected as universal binary")
+	}
+	if len(arches) != 2 || arches[0] != "amd64" || arches[1] != "arm64" {
+		t.Errorf("Expected [amd64 arm64], got %v", arches)
+	}
+
+	plainPath := filepath.Join(tempDir, "plain")
+	if err := os.WriteFile(plainPath, []byte("#!/bin/sh\necho hello\n"), 0755); err != nil {
+		t.Fatalf("Failed to write plain binary: %v", err)
+	}
+	if _, isUniversal, err := UniversalBinaryArchitectures(plainPath); err != nil || isUniversal {
+		t.Errorf("Expected plain file to be non-universal without error, got universal=%v err=%v", isUniversal, err)
+	}
+}
+
+func TestVerifyUniversalBinary(t *testing.T) {
+	tempDir := t.TempDir()
+	fatPath := filepath.Join(tempDir, "universal")
+	writeFatBinary(t, fatPath, macho.CpuAmd64, macho.CpuArm64)
+
+	if err := verifyUniversalBinary(fatPath, "arm64"); err != nil {
+		t.Errorf("Expected arm64 slice to be accepted: %v", err)
+	}
+	if err := verifyUniversalBinary(fatPath, "riscv64"); err == nil {
+		t.Error("Expected error for architecture missing from universal binary")
+	}
+}
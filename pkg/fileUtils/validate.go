@@ -0,0 +1,113 @@
+package fileUtils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidConfig is returned by the Validate methods of the configuration structs
+var ErrInvalidConfig = NewError(ErrorCodeConfig, "invalid configuration")
+
+// InvalidConfigError returns an ErrInvalidConfig listing the problems, or nil if there are none
+func InvalidConfigError(problems []string) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrInvalidConfig, strings.Join(problems, "; "))
+}
+
+// CheckPatterns returns a problem for every pattern in the field that is not a valid regular
+// expression once the {OS}, {ARCH} and {PROJECT} placeholders are replaced
+func CheckPatterns(field string, patterns []string) []string {
+	placeholders := strings.NewReplacer("{OS}", "(os)", "{ARCH}", "(arch)", "{PROJECT}", "project")
+	var problems []string
+	for _, pattern := range patterns {
+		if _, err := regexp.Compile(placeholders.Replace(pattern)); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: invalid pattern %q: %v", field, pattern, err))
+		}
+	}
+	return problems
+}
+
+// Validate checks the configuration for missing required fields, conflicting flags and invalid
+// patterns without touching the file system or the network. Every problem is reported in the
+// returned ErrInvalidConfig.
+func (c FileConfig) Validate() error {
+	var problems []string
+	add := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if c.BinaryName == "" {
+		add("binary_name is required")
+	} else if err := validateSymlinkNames(c); err != nil {
+		add("%v", err)
+	}
+	if c.BaseBinaryDirectory == "" {
+		add("base_binary_directory is required")
+	}
+
+	kinds := 0
+	for _, set := range []bool{c.IsDirectBinary, c.IsCompressedBinary, c.IsAppImage} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds > 1 {
+		add("only one of is_direct_binary, is_compressed_binary and is_appimage may be set")
+	}
+	if c.SourceBinaryPattern != "" {
+		if _, err := filepath.Match(c.SourceBinaryPattern, ""); err != nil {
+			add("source_binary_pattern: invalid glob %q: %v", c.SourceBinaryPattern, err)
+		}
+	}
+
+	switch strings.ToLower(c.AssetMatchingStrategy) {
+	case "", "standard", "flexible", "cdn", "hybrid":
+	case "custom":
+		if len(c.CustomAssetPatterns) == 0 {
+			add("asset_matching_strategy custom requires custom_asset_patterns")
+		}
+	default:
+		add("asset_matching_strategy must be standard, flexible, custom, cdn or hybrid, got %q", c.AssetMatchingStrategy)
+	}
+	problems = append(problems, CheckPatterns("custom_asset_patterns", c.CustomAssetPatterns)...)
+
+	switch c.ArchitectureCheck {
+	case "", ArchitectureCheckWarn, ArchitectureCheckFail, ArchitectureCheckOff:
+	default:
+		add("architecture_check must be warn, fail or off, got %q", c.ArchitectureCheck)
+	}
+	switch c.VersionDirectoryNaming {
+	case "", VersionNamingSafe, VersionNamingStrict, VersionNamingAsIs:
+	default:
+		add("version_directory_naming must be safe, strict or as-is, got %q", c.VersionDirectoryNaming)
+	}
+	switch c.ReadOnlyCheck {
+	case "", ReadOnlyCheckFail, ReadOnlyCheckOff:
+	default:
+		add("read_only_check must be fail or off, got %q", c.ReadOnlyCheck)
+	}
+	if c.BinaryFileMode&^os.ModePerm != 0 {
+		add("binary_file_mode may only contain permission bits, got %v", c.BinaryFileMode)
+	}
+	if c.DirectoryMode&^os.ModePerm != 0 {
+		add("directory_mode may only contain permission bits, got %v", c.DirectoryMode)
+	}
+	if len(c.VerifyCommand) > 0 && c.VerifyCommand[0] == "" {
+		add("verify_command must not start with an empty argument")
+	}
+	if c.VerifyTimeout < 0 {
+		add("verify_timeout must not be negative")
+	}
+	if c.Cache.MaxSize < 0 || c.Cache.TTL < 0 {
+		add("cache max_size and ttl must not be negative")
+	}
+	if c.ChunkedDownload.ChunkSize < 0 || c.ChunkedDownload.Concurrency < 0 || c.ChunkedDownload.MinSize < 0 {
+		add("chunked_download chunk_size, concurrency and min_size must not be negative")
+	}
+	return InvalidConfigError(problems)
+}
@@ -0,0 +1,53 @@
+package fileUtils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFileConfig_Validate(t *testing.T) {
+	valid := DefaultFileConfig()
+	valid.BinaryName = "tool"
+	valid.BaseBinaryDirectory = "/opt/tools"
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Validate() of a valid config error = %v", err)
+	}
+
+	testCases := []struct {
+		name     string
+		modify   func(*FileConfig)
+		expected string
+	}{
+		{"MissingBinaryName", func(c *FileConfig) { c.BinaryName = "" }, "binary_name is required"},
+		{"MissingBaseDirectory", func(c *FileConfig) { c.BaseBinaryDirectory = "" }, "base_binary_directory is required"},
+		{"ConflictingKinds", func(c *FileConfig) { c.IsDirectBinary, c.IsAppImage = true, true }, "only one of"},
+		{"BadGlob", func(c *FileConfig) { c.SourceBinaryPattern = "tool[" }, "source_binary_pattern"},
+		{"BadRegex", func(c *FileConfig) {
+			c.AssetMatchingStrategy = "custom"
+			c.CustomAssetPatterns = []string{"{PROJECT}_{OS}_(", "{OS}.zip"}
+		}, `invalid pattern "{PROJECT}_{OS}_("`},
+		{"CustomWithoutPatterns", func(c *FileConfig) { c.AssetMatchingStrategy = "custom" }, "requires custom_asset_patterns"},
+		{"UnknownStrategy", func(c *FileConfig) { c.AssetMatchingStrategy = "fuzzy" }, "asset_matching_strategy"},
+		{"SymlinkName", func(c *FileConfig) { c.SymlinkAliases = []string{"../t"} }, "invalid symlink name"},
+		{"FileMode", func(c *FileConfig) { c.BinaryFileMode = 04755 }, "binary_file_mode"},
+		{"VersionNaming", func(c *FileConfig) { c.VersionDirectoryNaming = "lower" }, "version_directory_naming"},
+		{"ReadOnlyCheck", func(c *FileConfig) { c.ReadOnlyCheck = "warn" }, "read_only_check"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := valid
+			tc.modify(&config)
+			err := config.Validate()
+			if !errors.Is(err, ErrInvalidConfig) || !strings.Contains(err.Error(), tc.expected) {
+				t.Errorf("Validate() error = %v, want ErrInvalidConfig mentioning %q", err, tc.expected)
+			}
+		})
+	}
+
+	// Every problem is reported at once
+	err := FileConfig{ArchitectureCheck: "strict"}.Validate()
+	if err == nil || strings.Count(err.Error(), ";") != 2 {
+		t.Errorf("Expected three problems, got %v", err)
+	}
+}
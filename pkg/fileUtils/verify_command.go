@@ -0,0 +1,65 @@
+package fileUtils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/filesystem"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// DefaultVerifyTimeout bounds a VerifyCommand run, e.g. of a binary that waits for input
+const DefaultVerifyTimeout = 30 * time.Second
+
+// maxVerifyOutput is the number of bytes of a failed VerifyCommand's output included in the error
+const maxVerifyOutput = 512
+
+// ErrBinaryVerification is returned when the installed binary fails VerifyCommand
+var ErrBinaryVerification = errors.New("installed binary failed verification")
+
+// runVerifyCommand runs the installed binary with args and returns its combined output; replaced in tests
+var runVerifyCommand = func(ctx context.Context, binaryPath string, args []string) ([]byte, error) {
+	return exec.CommandContext(ctx, binaryPath, args...).CombinedOutput()
+}
+
+// verifyInstalledBinary runs the binary with VerifyCommand, e.g. ["--version"], before any symlink
+// is updated. A non-zero exit, a crash or a timeout fails the installation, which is then rolled back.
+func verifyInstalledBinary(config FileConfig, binaryPath string) error {
+	if len(config.VerifyCommand) == 0 {
+		return nil
+	}
+	command := strings.TrimSpace(binaryPath + " " + strings.Join(config.VerifyCommand, " "))
+	if !filesystem.IsOS(fsys()) {
+		fmt.Printf("Warning: not running %s because the binary is not on the operating system's file system\n", command)
+		return nil
+	}
+
+	timeout := config.VerifyTimeout
+	if timeout <= 0 {
+		timeout = DefaultVerifyTimeout
+	}
+	ctx, cancel := context.WithTimeout(config.Context(), timeout)
+	defer cancel()
+
+	fmt.Printf("Verifying the binary with %s...\n", command)
+	output, err := runVerifyCommand(ctx, binaryPath, config.VerifyCommand)
+	if err := config.Context().Err(); err != nil {
+		return fmt.Errorf("verification of %s stopped: %w", binaryPath, err)
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("timed out after %v", timeout)
+	}
+	if err != nil {
+		detail := strings.TrimSpace(string(output))
+		if len(detail) > maxVerifyOutput {
+			detail = detail[:maxVerifyOutput] + "..."
+		}
+		if detail != "" {
+			return fmt.Errorf("%w: %s: %v: %s", ErrBinaryVerification, command, err, detail)
+		}
+		return fmt.Errorf("%w: %s: %v", ErrBinaryVerification, command, err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package fileUtils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInstallFromFile_VerifyCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts are not executable on Windows")
+	}
+	tempDir := t.TempDir()
+	config := FileConfig{
+		BaseBinaryDirectory:    filepath.Join(tempDir, "bin"),
+		VersionedDirectoryName: "versions",
+		BinaryName:             "tool",
+		IsDirectBinary:         true,
+		CreateLocalSymlink:     true,
+		SymlinkAliases:         []string{"t"},
+		VerifyCommand:          []string{"--version"},
+	}
+	install := func(version, script string) error {
+		path := filepath.Join(tempDir, "tool-"+version)
+		if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		return InstallFromFile(config, path, version, nil)
+	}
+
+	if err := install("1.0.0", `[ "$1" = --version ] && echo "tool 1.0.0"`); err != nil {
+		t.Fatalf("InstallFromFile() error = %v", err)
+	}
+
+	testCases := []struct {
+		name     string
+		script   string
+		timeout  time.Duration
+		expected string // Part of the error message
+	}{
+		{"Crash", "echo 'segmentation fault' >&2; exit 139", 2 * time.Second, "segmentation fault"},
+		{"Hang", "exec sleep 10", 100 * time.Millisecond, "timed out"},
+	}
+	for i, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config.VerifyTimeout = tc.timeout
+			version := fmt.Sprintf("2.0.%d", i)
+			err := install(version, tc.script)
+			if !errors.Is(err, ErrBinaryVerification) || !strings.Contains(err.Error(), tc.expected) {
+				t.Fatalf("InstallFromFile() error = %v, want ErrBinaryVerification mentioning %q", err, tc.expected)
+			}
+			for _, link := range []string{GetLocalSymlinkPath(config), filepath.Join(config.BaseBinaryDirectory, "t")} {
+				if target, err := resolveSymlink(config, link); err != nil || target != GetVersionedBinaryPath(config, "1.0.0") {
+					t.Errorf("Expected %s to keep pointing at 1.0.0, got %q, %v", link, target, err)
+				}
+			}
+			if _, err := os.Stat(GetVersionedDirectoryPath(config, version)); !os.IsNotExist(err) {
+				t.Errorf("Expected the unverified version to be removed, Stat() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifyInstalledBinary_Skipped(t *testing.T) {
+	originalRun := runVerifyCommand
+	defer func() { runVerifyCommand = originalRun }()
+	runVerifyCommand = func(_ context.Context, _ string, _ []string) ([]byte, error) {
+		t.Error("Expected the binary not to be run without VerifyCommand")
+		return nil, nil
+	}
+	if err := verifyInstalledBinary(FileConfig{}, "/nonexistent/tool"); err != nil {
+		t.Errorf("verifyInstalledBinary() error = %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package fileUtils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Version directory naming modes for FileConfig.VersionDirectoryNaming
+const (
+	VersionNamingSafe   = "safe"   // Replace path separators and characters Windows forbids in file names with "_" (default)
+	VersionNamingStrict = "strict" // Keep only letters, digits, ".", "-" and "_", e.g. for "+" in k0s versions
+	VersionNamingAsIs   = "as-is"  // Use versions as directory names unchanged, as before normalization
+)
+
+// VersionDirectoryName returns the name of the directory the version is installed in. Tags such
+// as "cli/v1.2.0" or "v1.30.0+k0s.0" are mapped to a safe name according to VersionDirectoryNaming,
+// while receipts and the state manifest keep the original version. Mapping a name again returns it
+// unchanged, so scanned directory names are recognized.
+func VersionDirectoryName(config FileConfig, version string) string {
+	if config.VersionDirectoryNaming == VersionNamingAsIs || version == "" {
+		return version
+	}
+	strict := config.VersionDirectoryNaming == VersionNamingStrict
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case strict && (r == '.' || r == '-' || r == '_' || r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))):
+			return r
+		case strict:
+			return '_'
+		case r < ' ' || strings.ContainsRune(`<>:"/\|?*`, r):
+			return '_'
+		}
+		return r
+	}, version)
+	if strings.Trim(name, ".") == "" {
+		// "." and ".." would name the parent directories
+		name = strings.Repeat("_", len(name))
+	}
+	return name
+}
+
+// originalVersion returns the recorded version installed in the directory named name, or name
+// itself if none of the versions maps to it
+func originalVersion(config FileConfig, name string, versions []string) string {
+	for _, version := range versions {
+		if version != name && VersionDirectoryName(config, version) == name {
+			return version
+		}
+	}
+	return name
+}
@@ -0,0 +1,71 @@
+package fileUtils
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestVersionDirectoryName(t *testing.T) {
+	tests := []struct {
+		naming, version, want string
+	}{
+		{"", "v1.2.3", "v1.2.3"},
+		{"", "cli/v1.2.3", "cli_v1.2.3"},
+		{"", `tool\v1:rc?`, "tool_v1_rc_"},
+		{"", "v1.30.0+k0s.0", "v1.30.0+k0s.0"},
+		{"", "..", "__"},
+		{VersionNamingStrict, "v1.30.0+k0s.0", "v1.30.0_k0s.0"},
+		{VersionNamingStrict, "cli/v1.2.3 (beta)", "cli_v1.2.3__beta_"},
+		{VersionNamingAsIs, "v1.30.0+k0s.0", "v1.30.0+k0s.0"},
+		{VersionNamingAsIs, "cli/v1.2.3", "cli/v1.2.3"},
+	}
+	for _, tt := range tests {
+		config := FileConfig{VersionDirectoryNaming: tt.naming}
+		got := VersionDirectoryName(config, tt.version)
+		if got != tt.want {
+			t.Errorf("VersionDirectoryName(%q, %q) = %q, want %q", tt.naming, tt.version, got, tt.want)
+		}
+		if again := VersionDirectoryName(config, got); again != got {
+			t.Errorf("VersionDirectoryName(%q, %q) = %q, want it unchanged", tt.naming, got, again)
+		}
+	}
+}
+
+func TestVersionDirectoryName_KeepsOriginalVersions(t *testing.T) {
+	tempDir := t.TempDir()
+	config := FileConfig{
+		BaseBinaryDirectory:     filepath.Join(tempDir, "bin"),
+		UseVersionsSubdirectory: true,
+		BinaryName:              "k0s",
+		SourceBinaryName:        "k0s",
+		CreateLocalSymlink:      true,
+		VersionDirectoryNaming:  VersionNamingStrict,
+	}
+	versions := []string{"k0s/v1.29.0+k0s.0", "k0s/v1.30.0+k0s.0"}
+	for _, version := range versions {
+		archivePath := filepath.Join(tempDir, "k0s.tar.gz")
+		createTestArchiveWithFiles(t, archivePath, map[string]string{"k0s": "version " + version})
+		if err := InstallFromFile(config, archivePath, version, nil); err != nil {
+			t.Fatalf("InstallFromFile(%s) error = %v", version, err)
+		}
+	}
+
+	wantDir := filepath.Join(config.BaseBinaryDirectory, "versions", "k0s", "k0s_v1.30.0_k0s.0")
+	if !FileExists(filepath.Join(wantDir, "k0s")) {
+		t.Errorf("Expected the binary in %s", wantDir)
+	}
+	if current, err := CurrentInstalledVersion(config); err != nil || current != versions[1] {
+		t.Errorf("CurrentInstalledVersion() = %q, %v, want %q", current, err, versions[1])
+	}
+	if err := CheckInstalledVersion(config, versions[1]); err != nil {
+		t.Errorf("CheckInstalledVersion() error = %v", err)
+	}
+	if installed, err := ListInstalledVersions(config); err != nil || !reflect.DeepEqual(installed, versions) {
+		t.Errorf("ListInstalledVersions() = %v, %v, want %v", installed, err, versions)
+	}
+	receipt, err := GetInstallReceipt(config)
+	if err != nil || receipt.Version != versions[1] {
+		t.Errorf("Expected the receipt to keep the tag %q, got %+v, %v", versions[1], receipt, err)
+	}
+}
@@ -0,0 +1,104 @@
+package fileUtils
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+)
+
+// ErrVersionActive is returned by RemoveVersion for the version the local symlink points at
+var ErrVersionActive = errors.New("version is active")
+
+// ListInstalledVersions returns the versions of the binary that are installed in version
+// directories, sorted by directory name
+func ListInstalledVersions(config FileConfig) ([]string, error) {
+	manifest, err := ReadInstallManifest(config)
+	if err != nil {
+		return nil, err
+	}
+	recorded := manifest.Tools[config.BinaryName].Versions
+	var versions []string
+	for _, dir := range installedVersionDirectories(config, recorded) {
+		versions = append(versions, originalVersion(config, filepath.Base(dir), recorded))
+	}
+	return versions, nil
+}
+
+// ActivateVersion points the local symlink, its aliases and the installed completions and man
+// pages at an installed version without downloading anything, e.g. to roll back an update. A
+// binary that no longer matches the checksum recorded when it was installed is refused.
+func ActivateVersion(config FileConfig, version string) (err error) {
+	if config.CreateLocalSymlink == false && config.CreateGlobalSymlink == false {
+		// If both are false, assume this is an old config and enable local symlinks by default
+		config.CreateLocalSymlink = true
+	}
+	if !config.CreateLocalSymlink {
+		return fmt.Errorf("activating a version requires the local symlink (CreateLocalSymlink)")
+	}
+	binaryPath := GetVersionedBinaryPath(config, version)
+	if !FileExists(binaryPath) {
+		return fmt.Errorf("%w: %s", ErrVersionNotInstalled, version)
+	}
+	if receipt, err := GetInstallReceipt(config); err == nil && receipt.Checksums[version] != "" {
+		if err := VerifyFileSHA256(binaryPath, receipt.Checksums[version]); err != nil {
+			return fmt.Errorf("installed binary %s was modified: %v", binaryPath, err)
+		}
+	}
+
+	tx := &installTransaction{}
+	defer tx.finish(&err)
+	localSymlinkPath := GetLocalSymlinkPath(config)
+	if err := tx.saveLinks(append([]string{localSymlinkPath}, GetSymlinkAliasPaths(config)...)...); err != nil {
+		return err
+	}
+	symlinkTarget := GetSymlinkTargetPath(config, version)
+	localSymlinkStatus := linkLocalBinary(symlinkTarget, binaryPath, localSymlinkPath)
+	if localSymlinkStatus == "failed" {
+		return fmt.Errorf("failed to point %s at %s", localSymlinkPath, version)
+	}
+	aliases := createSymlinkAliases(config, symlinkTarget)
+	extraFiles := installExtraFiles(config, filepath.Dir(binaryPath))
+	recordInstallReceipt(config, version, localSymlinkStatus, aliases, extraFiles)
+
+	fmt.Printf("Activated %s %s\n", config.BinaryName, version)
+	return nil
+}
+
+// RemoveVersion deletes the version directory of an installed version that is not active and
+// removes it from the install receipt. Versions in a shared root (SharedVersionsDirectory) are
+// kept because other users may link to them.
+func RemoveVersion(config FileConfig, version string) error {
+	if config.IsSharedInstall() {
+		return fmt.Errorf("shared versions in %s are not removed because other users may link to them", sharedProjectDirectory(config))
+	}
+	versionDir := GetVersionedDirectoryPath(config, version)
+	if info, err := fsys().Stat(versionDir); version == "" || err != nil || !info.IsDir() {
+		return fmt.Errorf("%w: %s", ErrVersionNotInstalled, version)
+	}
+	if current, err := CurrentInstalledVersion(config); err == nil && current == version {
+		return fmt.Errorf("%w: %s", ErrVersionActive, version)
+	}
+	if err := fsys().RemoveAll(versionDir); err != nil {
+		return fmt.Errorf("failed to remove %s: %v", versionDir, err)
+	}
+
+	manifest, err := ReadInstallManifest(config)
+	if err != nil {
+		return err
+	}
+	receipt, ok := manifest.Tools[config.BinaryName]
+	if !ok {
+		return nil
+	}
+	var versions []string
+	for _, v := range receipt.Versions {
+		if v != version {
+			versions = append(versions, v)
+		}
+	}
+	receipt.Versions = versions
+	delete(receipt.Checksums, version)
+	delete(receipt.Provenance, version)
+	manifest.Tools[config.BinaryName] = receipt
+	return writeInstallManifest(config, manifest)
+}
@@ -0,0 +1,71 @@
+package fileUtils
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestActivateAndRemoveVersion(t *testing.T) {
+	tempDir := t.TempDir()
+	config := FileConfig{
+		BaseBinaryDirectory:    filepath.Join(tempDir, "bin"),
+		VersionedDirectoryName: "versions",
+		BinaryName:             "tool",
+		SourceBinaryName:       "tool",
+		CreateLocalSymlink:     true,
+		SymlinkAliases:         []string{"t"},
+	}
+	for _, version := range []string{"1.0.0", "1.1.0", "2.0.0"} {
+		archivePath := filepath.Join(tempDir, version+".tar.gz")
+		createTestArchiveWithFiles(t, archivePath, map[string]string{"tool": "version " + version})
+		if err := InstallFromFile(config, archivePath, version, nil); err != nil {
+			t.Fatalf("InstallFromFile(%s) error = %v", version, err)
+		}
+	}
+
+	versions, err := ListInstalledVersions(config)
+	if err != nil || !reflect.DeepEqual(versions, []string{"1.0.0", "1.1.0", "2.0.0"}) {
+		t.Fatalf("ListInstalledVersions() = %v, %v", versions, err)
+	}
+
+	// Rolling back only flips the links
+	if err := ActivateVersion(config, "1.1.0"); err != nil {
+		t.Fatalf("ActivateVersion() error = %v", err)
+	}
+	if err := CheckInstalledVersion(config, "1.1.0"); err != nil {
+		t.Errorf("CheckInstalledVersion() after activation error = %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(config.BaseBinaryDirectory, "t")); string(data) != "version 1.1.0" {
+		t.Errorf("Expected the alias to point at 1.1.0, got %q", data)
+	}
+	if err := ActivateVersion(config, "3.0.0"); !errors.Is(err, ErrVersionNotInstalled) {
+		t.Errorf("ActivateVersion() of a missing version error = %v, want ErrVersionNotInstalled", err)
+	}
+	os.WriteFile(GetVersionedBinaryPath(config, "2.0.0"), []byte("tampered"), 0755)
+	if err := ActivateVersion(config, "2.0.0"); err == nil {
+		t.Error("Expected a modified binary to be refused")
+	}
+	if current, _ := CurrentInstalledVersion(config); current != "1.1.0" {
+		t.Errorf("Expected 1.1.0 to stay active, got %q", current)
+	}
+
+	if err := RemoveVersion(config, "1.1.0"); !errors.Is(err, ErrVersionActive) {
+		t.Errorf("RemoveVersion() of the active version error = %v, want ErrVersionActive", err)
+	}
+	if err := RemoveVersion(config, "1.0.0"); err != nil {
+		t.Fatalf("RemoveVersion() error = %v", err)
+	}
+	if _, err := os.Stat(GetVersionedDirectoryPath(config, "1.0.0")); !os.IsNotExist(err) {
+		t.Errorf("Expected the version directory to be removed, Stat() error = %v", err)
+	}
+	receipt, _ := GetInstallReceipt(config)
+	if !reflect.DeepEqual(receipt.Versions, []string{"1.1.0", "2.0.0"}) || receipt.Checksums["1.0.0"] != "" {
+		t.Errorf("Expected 1.0.0 to be removed from the receipt, got %v, %v", receipt.Versions, receipt.Checksums)
+	}
+	if err := RemoveVersion(config, "1.0.0"); !errors.Is(err, ErrVersionNotInstalled) {
+		t.Errorf("RemoveVersion() of a removed version error = %v, want ErrVersionNotInstalled", err)
+	}
+}
@@ -0,0 +1,170 @@
+package filesystem
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FS is the file system the library installs, links and extracts binaries on. The default is the
+// operating system's file system; SetDefault replaces it, e.g. with a MemFS in unit tests or an
+// implementation backed by remote storage.
+type FS interface {
+	Open(name string) (File, error)                                 // Opens a file for reading
+	Create(name string) (File, error)                               // Creates or truncates a file for writing
+	OpenFile(name string, flag int, perm os.FileMode) (File, error) // Opens a file with os.OpenFile flags
+	CreateTemp(dir, pattern string) (File, error)                   // Creates a new file with a unique name, like os.CreateTemp
+	MkdirTemp(dir, pattern string) (string, error)                  // Creates a new directory with a unique name, like os.MkdirTemp
+	Stat(name string) (os.FileInfo, error)                          // Describes a file, following symlinks
+	Lstat(name string) (os.FileInfo, error)                         // Describes a file without following a final symlink
+	ReadDir(name string) ([]os.DirEntry, error)                     // Lists a directory sorted by name
+	ReadFile(name string) ([]byte, error)                           // Reads a whole file
+	WriteFile(name string, data []byte, perm os.FileMode) error     // Creates or replaces a file with data
+	MkdirAll(path string, perm os.FileMode) error                   // Creates a directory and its missing parents
+	Remove(name string) error                                       // Removes a file or empty directory
+	RemoveAll(path string) error                                    // Removes a path and its children
+	Rename(oldpath, newpath string) error                           // Moves a file, replacing newpath
+	Symlink(oldname, newname string) error                          // Creates newname as a symlink to oldname
+	Readlink(name string) (string, error)                           // Returns the target of a symlink
+	Chmod(name string, mode os.FileMode) error                      // Changes the mode of a file
+	Lchown(name string, uid, gid int) error                         // Changes the owner of a file without following symlinks
+	Chtimes(name string, atime time.Time, mtime time.Time) error    // Changes the access and modification times of a file
+}
+
+// File is an open file of an FS
+type File interface {
+	io.Reader
+	io.ReaderAt
+	io.Writer
+	io.WriterAt
+	io.Seeker
+	io.Closer
+	Name() string               // Name the file was opened with
+	Stat() (os.FileInfo, error) // Describes the file
+	Truncate(size int64) error  // Changes the size of the file
+}
+
+// OSFS is the operating system's file system
+type OSFS struct{}
+
+func (OSFS) Open(name string) (File, error) {
+	return wrapOSFile(os.Open(name))
+}
+
+func (OSFS) Create(name string) (File, error) {
+	return wrapOSFile(os.Create(name))
+}
+
+func (OSFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	return wrapOSFile(os.OpenFile(name, flag, perm))
+}
+
+func (OSFS) CreateTemp(dir, pattern string) (File, error) {
+	return wrapOSFile(os.CreateTemp(dir, pattern))
+}
+
+func (OSFS) MkdirTemp(dir, pattern string) (string, error) { return os.MkdirTemp(dir, pattern) }
+func (OSFS) Stat(name string) (os.FileInfo, error)         { return os.Stat(name) }
+func (OSFS) Lstat(name string) (os.FileInfo, error)        { return os.Lstat(name) }
+func (OSFS) ReadDir(name string) ([]os.DirEntry, error)    { return os.ReadDir(name) }
+func (OSFS) ReadFile(name string) ([]byte, error)          { return os.ReadFile(name) }
+func (OSFS) MkdirAll(path string, perm os.FileMode) error  { return os.MkdirAll(path, perm) }
+func (OSFS) Remove(name string) error                      { return os.Remove(name) }
+func (OSFS) RemoveAll(path string) error                   { return os.RemoveAll(path) }
+func (OSFS) Rename(oldpath, newpath string) error          { return os.Rename(oldpath, newpath) }
+func (OSFS) Symlink(oldname, newname string) error         { return os.Symlink(oldname, newname) }
+func (OSFS) Readlink(name string) (string, error)          { return os.Readlink(name) }
+func (OSFS) Chmod(name string, mode os.FileMode) error     { return os.Chmod(name, mode) }
+func (OSFS) Lchown(name string, uid, gid int) error        { return os.Lchown(name, uid, gid) }
+
+func (OSFS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+
+func (OSFS) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	return os.Chtimes(name, atime, mtime)
+}
+
+// wrapOSFile avoids returning a nil *os.File as a non-nil File
+func wrapOSFile(file *os.File, err error) (File, error) {
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+var (
+	defaultMu sync.RWMutex
+	defaultFS FS = OSFS{}
+)
+
+// SetDefault installs the file system used by the library; nil restores the operating system's
+func SetDefault(fsys FS) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if fsys == nil {
+		fsys = OSFS{}
+	}
+	defaultFS = fsys
+}
+
+// Default returns the file system used by the library
+func Default() FS {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultFS
+}
+
+// IsOS reports whether fsys is the operating system's file system
+func IsOS(fsys FS) bool {
+	_, ok := fsys.(OSFS)
+	return ok
+}
+
+// WalkDir walks the file tree rooted at root on fsys like filepath.WalkDir, calling fn for each
+// file and directory in lexical order without following symlinks
+func WalkDir(fsys FS, root string, fn fs.WalkDirFunc) error {
+	info, err := fsys.Lstat(root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		err = walkDir(fsys, root, fs.FileInfoToDirEntry(info), fn)
+	}
+	if err == filepath.SkipDir || err == filepath.SkipAll {
+		return nil
+	}
+	return err
+}
+
+// walkDir calls fn for path and, if it is a directory, recursively for its entries
+func walkDir(fsys FS, path string, entry fs.DirEntry, fn fs.WalkDirFunc) error {
+	if err := fn(path, entry, nil); err != nil || !entry.IsDir() {
+		if err == filepath.SkipDir && entry.IsDir() {
+			err = nil
+		}
+		return err
+	}
+
+	entries, err := fsys.ReadDir(path)
+	if err != nil {
+		// Report the failure to read the directory; fn decides whether the walk continues
+		if err = fn(path, entry, err); err != nil {
+			if err == filepath.SkipDir {
+				err = nil
+			}
+			return err
+		}
+	}
+	for _, child := range entries {
+		if err := walkDir(fsys, filepath.Join(path, child.Name()), child, fn); err != nil {
+			if err == filepath.SkipDir {
+				break
+			}
+			return err
+		}
+	}
+	return nil
+}
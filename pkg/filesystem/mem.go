@@ -0,0 +1,622 @@
+package filesystem
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxSymlinkHops limits symlink resolution like the operating system's ELOOP
+const maxSymlinkHops = 40
+
+var (
+	errNotDir      = errors.New("not a directory")
+	errIsDir       = errors.New("is a directory")
+	errNotEmpty    = errors.New("directory not empty")
+	errTooManyLink = errors.New("too many levels of symbolic links")
+)
+
+// MemFS is an in-memory FS with directories, regular files and symlinks, for unit tests that
+// should not touch the disk. Relative paths are resolved against the root directory. It is safe
+// for concurrent use.
+type MemFS struct {
+	mu      sync.Mutex
+	nodes   map[string]*memNode // Cleaned absolute path -> node, without the root directory
+	counter int                 // Suffix source for CreateTemp and MkdirTemp
+}
+
+// memNode is a file, directory or symlink of a MemFS
+type memNode struct {
+	mode     os.FileMode
+	data     []byte
+	target   string // Target of a symlink
+	modTime  time.Time
+	uid, gid int
+}
+
+// NewMemFS returns an empty in-memory file system
+func NewMemFS() *MemFS {
+	return &MemFS{nodes: map[string]*memNode{}}
+}
+
+// clean returns the absolute, cleaned form of name
+func (m *MemFS) clean(name string) string {
+	if !filepath.IsAbs(name) {
+		name = filepath.Join(string(filepath.Separator), name)
+	}
+	return filepath.Clean(name)
+}
+
+// isRoot reports whether path is the root directory of its volume
+func isRoot(path string) bool {
+	return filepath.Dir(path) == path
+}
+
+// lookup returns the node at a cleaned path; the root directory always exists
+func (m *MemFS) lookup(path string) (*memNode, bool) {
+	if isRoot(path) {
+		return &memNode{mode: os.ModeDir | 0755}, true
+	}
+	node, ok := m.nodes[path]
+	return node, ok
+}
+
+// resolve returns the cleaned path name refers to after following symlinks in its directories,
+// and in its final component if followLast is set. Missing components are kept as they are.
+func (m *MemFS) resolve(name string, followLast bool) (string, error) {
+	path := m.clean(name)
+	for hops := 0; ; hops++ {
+		if hops > maxSymlinkHops {
+			return "", errTooManyLink
+		}
+		volume := filepath.VolumeName(path)
+		components := strings.Split(strings.Trim(path[len(volume):], string(filepath.Separator)), string(filepath.Separator))
+		current := volume + string(filepath.Separator)
+		resolved := true
+		for i, component := range components {
+			if component == "" {
+				continue
+			}
+			current = filepath.Join(current, component)
+			node, ok := m.nodes[current]
+			if !ok || node.mode&os.ModeSymlink == 0 || (i == len(components)-1 && !followLast) {
+				continue
+			}
+			target := node.target
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(filepath.Dir(current), target)
+			}
+			path = m.clean(filepath.Join(append([]string{target}, components[i+1:]...)...))
+			resolved = false
+			break
+		}
+		if resolved {
+			return path, nil
+		}
+	}
+}
+
+// resolveNode resolves name and returns its node
+func (m *MemFS) resolveNode(op, name string, followLast bool) (string, *memNode, error) {
+	path, err := m.resolve(name, followLast)
+	if err != nil {
+		return "", nil, &os.PathError{Op: op, Path: name, Err: err}
+	}
+	node, ok := m.lookup(path)
+	if !ok {
+		return path, nil, &os.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	return path, node, nil
+}
+
+// checkParent fails unless the parent of a cleaned path is an existing directory
+func (m *MemFS) checkParent(op, name, path string) error {
+	parent, ok := m.lookup(filepath.Dir(path))
+	if !ok {
+		return &os.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	if !parent.mode.IsDir() {
+		return &os.PathError{Op: op, Path: name, Err: errNotDir}
+	}
+	return nil
+}
+
+// children returns the cleaned paths of the direct children of a directory, sorted by name
+func (m *MemFS) children(path string) []string {
+	var children []string
+	for child := range m.nodes {
+		if filepath.Dir(child) == path && child != path {
+			children = append(children, child)
+		}
+	}
+	sort.Strings(children)
+	return children
+}
+
+// Open opens a file for reading
+func (m *MemFS) Open(name string) (File, error) {
+	return m.OpenFile(name, os.O_RDONLY, 0)
+}
+
+// Create creates or truncates a file for writing
+func (m *MemFS) Create(name string) (File, error) {
+	return m.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+}
+
+// OpenFile opens a file with os.OpenFile flags
+func (m *MemFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.openFile(name, flag, perm)
+}
+
+// openFile implements OpenFile with the lock held
+func (m *MemFS) openFile(name string, flag int, perm os.FileMode) (File, error) {
+	path, node, err := m.resolveNode("open", name, true)
+	writable := flag&(os.O_WRONLY|os.O_RDWR) != 0
+	switch {
+	case err == nil:
+		if flag&os.O_CREATE != 0 && flag&os.O_EXCL != 0 {
+			return nil, &os.PathError{Op: "open", Path: name, Err: fs.ErrExist}
+		}
+		if node.mode.IsDir() && writable {
+			return nil, &os.PathError{Op: "open", Path: name, Err: errIsDir}
+		}
+		if flag&os.O_TRUNC != 0 && writable {
+			node.data = nil
+			node.modTime = time.Now()
+		}
+	case path != "" && flag&os.O_CREATE != 0:
+		if err := m.checkParent("open", name, path); err != nil {
+			return nil, err
+		}
+		node = &memNode{mode: perm.Perm(), modTime: time.Now()}
+		m.nodes[path] = node
+	default:
+		return nil, err
+	}
+	return &memFile{fs: m, node: node, name: name, flag: flag}, nil
+}
+
+// CreateTemp creates a new file with a unique name in dir, like os.CreateTemp
+func (m *MemFS) CreateTemp(dir, pattern string) (File, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for {
+		file, err := m.openFile(m.tempName(dir, pattern), os.O_RDWR|os.O_CREATE|os.O_EXCL, 0600)
+		if !errors.Is(err, fs.ErrExist) {
+			return file, err
+		}
+	}
+}
+
+// MkdirTemp creates a new directory with a unique name in dir, like os.MkdirTemp
+func (m *MemFS) MkdirTemp(dir, pattern string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for {
+		name := m.tempName(dir, pattern)
+		path := m.clean(name)
+		if _, exists := m.nodes[path]; exists {
+			continue
+		}
+		if err := m.checkParent("mkdirtemp", name, path); err != nil {
+			return "", err
+		}
+		m.nodes[path] = &memNode{mode: os.ModeDir | 0700, modTime: time.Now()}
+		return name, nil
+	}
+}
+
+// tempName returns the next candidate name for pattern, replacing its last "*" with a counter
+func (m *MemFS) tempName(dir, pattern string) string {
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	m.counter++
+	prefix, suffix := pattern, ""
+	if i := strings.LastIndex(pattern, "*"); i >= 0 {
+		prefix, suffix = pattern[:i], pattern[i+1:]
+	}
+	return filepath.Join(dir, prefix+strconv.Itoa(m.counter)+suffix)
+}
+
+// Stat describes a file, following symlinks
+func (m *MemFS) Stat(name string) (os.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, node, err := m.resolveNode("stat", name, true)
+	if err != nil {
+		return nil, err
+	}
+	return node.info(filepath.Base(name)), nil
+}
+
+// Lstat describes a file without following a final symlink
+func (m *MemFS) Lstat(name string) (os.FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, node, err := m.resolveNode("lstat", name, false)
+	if err != nil {
+		return nil, err
+	}
+	return node.info(filepath.Base(name)), nil
+}
+
+// ReadDir lists a directory sorted by name
+func (m *MemFS) ReadDir(name string) ([]os.DirEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	path, node, err := m.resolveNode("readdir", name, true)
+	if err != nil {
+		return nil, err
+	}
+	if !node.mode.IsDir() {
+		return nil, &os.PathError{Op: "readdir", Path: name, Err: errNotDir}
+	}
+	var entries []os.DirEntry
+	for _, child := range m.children(path) {
+		entries = append(entries, fs.FileInfoToDirEntry(m.nodes[child].info(filepath.Base(child))))
+	}
+	return entries, nil
+}
+
+// ReadFile reads a whole file
+func (m *MemFS) ReadFile(name string) ([]byte, error) {
+	file, err := m.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
+
+// WriteFile creates or replaces a file with data
+func (m *MemFS) WriteFile(name string, data []byte, perm os.FileMode) error {
+	file, err := m.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// MkdirAll creates a directory and its missing parents
+func (m *MemFS) MkdirAll(path string, perm os.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	resolved, err := m.resolve(path, true)
+	if err != nil {
+		return &os.PathError{Op: "mkdir", Path: path, Err: err}
+	}
+	var missing []string
+	for current := resolved; !isRoot(current); current = filepath.Dir(current) {
+		node, ok := m.nodes[current]
+		if ok {
+			if !node.mode.IsDir() {
+				return &os.PathError{Op: "mkdir", Path: current, Err: errNotDir}
+			}
+			break
+		}
+		missing = append(missing, current)
+	}
+	for i := len(missing) - 1; i >= 0; i-- {
+		m.nodes[missing[i]] = &memNode{mode: os.ModeDir | perm.Perm(), modTime: time.Now()}
+	}
+	return nil
+}
+
+// Remove removes a file or empty directory
+func (m *MemFS) Remove(name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	path, node, err := m.resolveNode("remove", name, false)
+	if err != nil {
+		return err
+	}
+	if node.mode.IsDir() && len(m.children(path)) > 0 {
+		return &os.PathError{Op: "remove", Path: name, Err: errNotEmpty}
+	}
+	delete(m.nodes, path)
+	return nil
+}
+
+// RemoveAll removes a path and its children; a missing path is not an error
+func (m *MemFS) RemoveAll(path string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	resolved, err := m.resolve(path, false)
+	if err != nil {
+		return &os.PathError{Op: "removeall", Path: path, Err: err}
+	}
+	for child := range m.nodes {
+		if child == resolved || strings.HasPrefix(child, resolved+string(filepath.Separator)) {
+			delete(m.nodes, child)
+		}
+	}
+	return nil
+}
+
+// Rename moves a file or directory, replacing a file or empty directory at newpath
+func (m *MemFS) Rename(oldpath, newpath string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	from, node, err := m.resolveNode("rename", oldpath, false)
+	if err != nil {
+		return err
+	}
+	to, err := m.resolve(newpath, false)
+	if err != nil {
+		return &os.PathError{Op: "rename", Path: newpath, Err: err}
+	}
+	if from == to {
+		return nil
+	}
+	if err := m.checkParent("rename", newpath, to); err != nil {
+		return err
+	}
+	if existing, ok := m.nodes[to]; ok && existing.mode.IsDir() && len(m.children(to)) > 0 {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: errNotEmpty}
+	}
+	delete(m.nodes, from)
+	m.nodes[to] = node
+	prefix := from + string(filepath.Separator)
+	for child, childNode := range m.nodes {
+		if strings.HasPrefix(child, prefix) {
+			delete(m.nodes, child)
+			m.nodes[to+string(filepath.Separator)+strings.TrimPrefix(child, prefix)] = childNode
+		}
+	}
+	return nil
+}
+
+// Symlink creates newname as a symlink to oldname
+func (m *MemFS) Symlink(oldname, newname string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	path, err := m.resolve(newname, false)
+	if err != nil {
+		return &os.LinkError{Op: "symlink", Old: oldname, New: newname, Err: err}
+	}
+	if _, exists := m.lookup(path); exists {
+		return &os.LinkError{Op: "symlink", Old: oldname, New: newname, Err: fs.ErrExist}
+	}
+	if err := m.checkParent("symlink", newname, path); err != nil {
+		return err
+	}
+	m.nodes[path] = &memNode{mode: os.ModeSymlink | 0777, target: oldname, modTime: time.Now()}
+	return nil
+}
+
+// Readlink returns the target of a symlink
+func (m *MemFS) Readlink(name string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, node, err := m.resolveNode("readlink", name, false)
+	if err != nil {
+		return "", err
+	}
+	if node.mode&os.ModeSymlink == 0 {
+		return "", &os.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+	return node.target, nil
+}
+
+// Chmod changes the mode of a file, following symlinks
+func (m *MemFS) Chmod(name string, mode os.FileMode) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, node, err := m.resolveNode("chmod", name, true)
+	if err != nil {
+		return err
+	}
+	node.mode = node.mode&os.ModeType | mode&(os.ModePerm|os.ModeSetuid|os.ModeSetgid|os.ModeSticky)
+	return nil
+}
+
+// Lchown changes the owner of a file without following symlinks
+func (m *MemFS) Lchown(name string, uid, gid int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, node, err := m.resolveNode("lchown", name, false)
+	if err != nil {
+		return err
+	}
+	node.uid, node.gid = uid, gid
+	return nil
+}
+
+// Chtimes changes the modification time of a file; access times are not tracked
+func (m *MemFS) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, node, err := m.resolveNode("chtimes", name, true)
+	if err != nil {
+		return err
+	}
+	node.modTime = mtime
+	return nil
+}
+
+// info describes the node under the given name
+func (n *memNode) info(name string) os.FileInfo {
+	size := int64(len(n.data))
+	if n.mode&os.ModeSymlink != 0 {
+		size = int64(len(n.target))
+	}
+	return &memFileInfo{name: name, size: size, mode: n.mode, modTime: n.modTime}
+}
+
+// memFileInfo describes a file of a MemFS
+type memFileInfo struct {
+	name    string
+	size    int64
+	mode    os.FileMode
+	modTime time.Time
+}
+
+func (i *memFileInfo) Name() string       { return i.name }
+func (i *memFileInfo) Size() int64        { return i.size }
+func (i *memFileInfo) Mode() os.FileMode  { return i.mode }
+func (i *memFileInfo) ModTime() time.Time { return i.modTime }
+func (i *memFileInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i *memFileInfo) Sys() any           { return nil }
+
+// memFile is an open file of a MemFS
+type memFile struct {
+	fs     *MemFS
+	node   *memNode
+	name   string
+	flag   int
+	offset int64
+	closed bool
+}
+
+// check fails for closed files, directories and, when writing, files opened read-only
+func (f *memFile) check(op string, write bool) error {
+	switch {
+	case f.closed:
+		return &os.PathError{Op: op, Path: f.name, Err: os.ErrClosed}
+	case f.node.mode.IsDir():
+		return &os.PathError{Op: op, Path: f.name, Err: errIsDir}
+	case write && f.flag&(os.O_WRONLY|os.O_RDWR) == 0:
+		return &os.PathError{Op: op, Path: f.name, Err: fs.ErrPermission}
+	}
+	return nil
+}
+
+func (f *memFile) Read(p []byte) (int, error) {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	if err := f.check("read", false); err != nil {
+		return 0, err
+	}
+	if f.offset >= int64(len(f.node.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, f.node.data[f.offset:])
+	f.offset += int64(n)
+	return n, nil
+}
+
+func (f *memFile) ReadAt(p []byte, offset int64) (int, error) {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	if err := f.check("read", false); err != nil {
+		return 0, err
+	}
+	if offset < 0 {
+		return 0, &os.PathError{Op: "readat", Path: f.name, Err: fs.ErrInvalid}
+	}
+	if offset >= int64(len(f.node.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, f.node.data[offset:])
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (f *memFile) Write(p []byte) (int, error) {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	if f.flag&os.O_APPEND != 0 {
+		f.offset = int64(len(f.node.data))
+	}
+	n, err := f.writeAt(p, f.offset)
+	f.offset += int64(n)
+	return n, err
+}
+
+func (f *memFile) WriteAt(p []byte, offset int64) (int, error) {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	return f.writeAt(p, offset)
+}
+
+// writeAt implements WriteAt with the lock held
+func (f *memFile) writeAt(p []byte, offset int64) (int, error) {
+	if err := f.check("write", true); err != nil {
+		return 0, err
+	}
+	if offset < 0 {
+		return 0, &os.PathError{Op: "write", Path: f.name, Err: fs.ErrInvalid}
+	}
+	if end := offset + int64(len(p)); end > int64(len(f.node.data)) {
+		f.node.data = append(f.node.data, make([]byte, end-int64(len(f.node.data)))...)
+	}
+	copy(f.node.data[offset:], p)
+	f.node.modTime = time.Now()
+	return len(p), nil
+}
+
+func (f *memFile) Seek(offset int64, whence int) (int64, error) {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	if f.closed {
+		return 0, &os.PathError{Op: "seek", Path: f.name, Err: os.ErrClosed}
+	}
+	switch whence {
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += int64(len(f.node.data))
+	}
+	if offset < 0 {
+		return 0, &os.PathError{Op: "seek", Path: f.name, Err: fs.ErrInvalid}
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+func (f *memFile) Truncate(size int64) error {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	if err := f.check("truncate", true); err != nil {
+		return err
+	}
+	if size < 0 {
+		return &os.PathError{Op: "truncate", Path: f.name, Err: fs.ErrInvalid}
+	}
+	if size <= int64(len(f.node.data)) {
+		f.node.data = f.node.data[:size]
+	} else {
+		f.node.data = append(f.node.data, make([]byte, size-int64(len(f.node.data)))...)
+	}
+	f.node.modTime = time.Now()
+	return nil
+}
+
+func (f *memFile) Close() error {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	if f.closed {
+		return &os.PathError{Op: "close", Path: f.name, Err: os.ErrClosed}
+	}
+	f.closed = true
+	return nil
+}
+
+func (f *memFile) Name() string {
+	return f.name
+}
+
+func (f *memFile) Stat() (os.FileInfo, error) {
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	if f.closed {
+		return nil, &os.PathError{Op: "stat", Path: f.name, Err: os.ErrClosed}
+	}
+	return f.node.info(filepath.Base(f.name)), nil
+}
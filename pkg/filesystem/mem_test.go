@@ -0,0 +1,263 @@
+package filesystem
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fileSystems returns a MemFS and the operating system's file system rooted at a temporary
+// directory, so every behavior is checked against the real implementation
+func fileSystems(t *testing.T) map[string]struct {
+	fsys FS
+	root string
+} {
+	return map[string]struct {
+		fsys FS
+		root string
+	}{
+		"MemFS": {NewMemFS(), filepath.Join(string(filepath.Separator), "work")},
+		"OSFS":  {OSFS{}, t.TempDir()},
+	}
+}
+
+func TestFS_FilesAndDirectories(t *testing.T) {
+	for name, tc := range fileSystems(t) {
+		t.Run(name, func(t *testing.T) {
+			fsys, root := tc.fsys, tc.root
+			dir := filepath.Join(root, "a", "b")
+			if err := fsys.MkdirAll(dir, 0755); err != nil {
+				t.Fatalf("MkdirAll() error = %v", err)
+			}
+			file := filepath.Join(dir, "file.txt")
+			if err := fsys.WriteFile(file, []byte("hello"), 0644); err != nil {
+				t.Fatalf("WriteFile() error = %v", err)
+			}
+			if data, err := fsys.ReadFile(file); err != nil || string(data) != "hello" {
+				t.Errorf("ReadFile() = %q, %v, want \"hello\"", data, err)
+			}
+			if err := fsys.MkdirAll(file, 0755); err == nil {
+				t.Error("Expected MkdirAll() over a file to fail")
+			}
+			if _, err := fsys.Create(filepath.Join(root, "missing", "file.txt")); !errors.Is(err, fs.ErrNotExist) {
+				t.Errorf("Create() in a missing directory error = %v, want fs.ErrNotExist", err)
+			}
+
+			out, err := fsys.OpenFile(file, os.O_WRONLY|os.O_APPEND, 0)
+			if err != nil {
+				t.Fatalf("OpenFile() error = %v", err)
+			}
+			out.Write([]byte(", world"))
+			out.Close()
+			in, err := fsys.Open(file)
+			if err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+			defer in.Close()
+			buffer := make([]byte, 5)
+			if _, err := in.ReadAt(buffer, 7); err != nil || string(buffer) != "world" {
+				t.Errorf("ReadAt() = %q, %v, want \"world\"", buffer, err)
+			}
+			if info, err := in.Stat(); err != nil || info.Size() != 12 {
+				t.Errorf("Stat() size = %v, %v, want 12", info, err)
+			}
+			if _, err := in.Write([]byte("x")); err == nil {
+				t.Error("Expected writing to a file opened for reading to fail")
+			}
+
+			if err := fsys.Remove(filepath.Join(root, "a")); err == nil {
+				t.Error("Expected Remove() of a non-empty directory to fail")
+			}
+			if err := fsys.RemoveAll(filepath.Join(root, "a")); err != nil {
+				t.Fatalf("RemoveAll() error = %v", err)
+			}
+			if _, err := fsys.Stat(file); !errors.Is(err, fs.ErrNotExist) {
+				t.Errorf("Stat() after RemoveAll() error = %v, want fs.ErrNotExist", err)
+			}
+		})
+	}
+}
+
+func TestFS_Symlinks(t *testing.T) {
+	for name, tc := range fileSystems(t) {
+		t.Run(name, func(t *testing.T) {
+			fsys, root := tc.fsys, tc.root
+			versionDir := filepath.Join(root, "versions", "1.0.0")
+			fsys.MkdirAll(versionDir, 0755)
+			fsys.WriteFile(filepath.Join(versionDir, "tool"), []byte("binary"), 0755)
+
+			link := filepath.Join(root, "tool")
+			if err := fsys.Symlink(filepath.Join("versions", "1.0.0", "tool"), link); err != nil {
+				t.Fatalf("Symlink() error = %v", err)
+			}
+			if err := fsys.Symlink("elsewhere", link); !errors.Is(err, fs.ErrExist) {
+				t.Errorf("Symlink() over an existing link error = %v, want fs.ErrExist", err)
+			}
+			if target, err := fsys.Readlink(link); err != nil || target != filepath.Join("versions", "1.0.0", "tool") {
+				t.Errorf("Readlink() = %q, %v", target, err)
+			}
+			if info, err := fsys.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+				t.Errorf("Lstat() = %v, %v, want a symlink", info, err)
+			}
+			if info, err := fsys.Stat(link); err != nil || !info.Mode().IsRegular() {
+				t.Errorf("Stat() = %v, %v, want the target file", info, err)
+			}
+			if data, err := fsys.ReadFile(link); err != nil || string(data) != "binary" {
+				t.Errorf("ReadFile() through the link = %q, %v", data, err)
+			}
+
+			// Symlinked directories are followed in the middle of paths
+			current := filepath.Join(root, "current")
+			fsys.Symlink(versionDir, current)
+			if data, err := fsys.ReadFile(filepath.Join(current, "tool")); err != nil || string(data) != "binary" {
+				t.Errorf("ReadFile() through a directory link = %q, %v", data, err)
+			}
+
+			dangling := filepath.Join(root, "dangling")
+			fsys.Symlink(filepath.Join(root, "missing"), dangling)
+			if _, err := fsys.Stat(dangling); !errors.Is(err, fs.ErrNotExist) {
+				t.Errorf("Stat() of a dangling link error = %v, want fs.ErrNotExist", err)
+			}
+			if err := fsys.Remove(dangling); err != nil {
+				t.Errorf("Remove() of a dangling link error = %v", err)
+			}
+			if err := fsys.Remove(current); err != nil {
+				t.Fatalf("Remove() of a directory link error = %v", err)
+			}
+			if _, err := fsys.Stat(filepath.Join(versionDir, "tool")); err != nil {
+				t.Errorf("Expected removing a link to keep its target, Stat() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestFS_Rename(t *testing.T) {
+	for name, tc := range fileSystems(t) {
+		t.Run(name, func(t *testing.T) {
+			fsys, root := tc.fsys, tc.root
+			fsys.MkdirAll(filepath.Join(root, "staging", "nested"), 0755)
+			fsys.WriteFile(filepath.Join(root, "staging", "nested", "file"), []byte("data"), 0644)
+			fsys.WriteFile(filepath.Join(root, "old"), []byte("old"), 0644)
+			fsys.WriteFile(filepath.Join(root, "new"), []byte("new"), 0644)
+
+			if err := fsys.Rename(filepath.Join(root, "new"), filepath.Join(root, "old")); err != nil {
+				t.Fatalf("Rename() over a file error = %v", err)
+			}
+			if data, _ := fsys.ReadFile(filepath.Join(root, "old")); string(data) != "new" {
+				t.Errorf("Expected Rename() to replace the file, got %q", data)
+			}
+			if err := fsys.Rename(filepath.Join(root, "staging"), filepath.Join(root, "installed")); err != nil {
+				t.Fatalf("Rename() of a directory error = %v", err)
+			}
+			if data, err := fsys.ReadFile(filepath.Join(root, "installed", "nested", "file")); err != nil || string(data) != "data" {
+				t.Errorf("Expected Rename() to move the directory's children, got %q, %v", data, err)
+			}
+			if _, err := fsys.Stat(filepath.Join(root, "staging")); !errors.Is(err, fs.ErrNotExist) {
+				t.Errorf("Stat() of the renamed directory error = %v, want fs.ErrNotExist", err)
+			}
+		})
+	}
+}
+
+func TestFS_ReadDirAndWalkDir(t *testing.T) {
+	for name, tc := range fileSystems(t) {
+		t.Run(name, func(t *testing.T) {
+			fsys, root := tc.fsys, tc.root
+			fsys.MkdirAll(filepath.Join(root, "b", "skipped"), 0755)
+			fsys.MkdirAll(filepath.Join(root, "a"), 0755)
+			fsys.WriteFile(filepath.Join(root, "c.txt"), nil, 0644)
+			fsys.WriteFile(filepath.Join(root, "a", "file"), nil, 0644)
+			fsys.WriteFile(filepath.Join(root, "b", "skipped", "file"), nil, 0644)
+			fsys.Symlink(filepath.Join(root, "a"), filepath.Join(root, "link"))
+
+			entries, err := fsys.ReadDir(root)
+			if err != nil {
+				t.Fatalf("ReadDir() error = %v", err)
+			}
+			var names []string
+			for _, entry := range entries {
+				names = append(names, entry.Name())
+			}
+			if want := []string{"a", "b", "c.txt", "link"}; !reflect.DeepEqual(names, want) {
+				t.Errorf("ReadDir() = %v, want %v", names, want)
+			}
+
+			var walked []string
+			err = WalkDir(fsys, root, func(path string, entry fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if entry.Name() == "skipped" {
+					return filepath.SkipDir
+				}
+				relative, _ := filepath.Rel(root, path)
+				walked = append(walked, filepath.ToSlash(relative))
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("WalkDir() error = %v", err)
+			}
+			if want := []string{".", "a", "a/file", "b", "c.txt", "link"}; !reflect.DeepEqual(walked, want) {
+				t.Errorf("WalkDir() visited %v, want %v", walked, want)
+			}
+		})
+	}
+}
+
+func TestFS_Temp(t *testing.T) {
+	for name, tc := range fileSystems(t) {
+		t.Run(name, func(t *testing.T) {
+			fsys, root := tc.fsys, tc.root
+			fsys.MkdirAll(root, 0755)
+			dir, err := fsys.MkdirTemp(root, "work-*")
+			if err != nil {
+				t.Fatalf("MkdirTemp() error = %v", err)
+			}
+			if !strings.HasPrefix(filepath.Base(dir), "work-") {
+				t.Errorf("MkdirTemp() = %q, want the work- prefix", dir)
+			}
+			first, err := fsys.CreateTemp(dir, "download-*.tmp")
+			if err != nil {
+				t.Fatalf("CreateTemp() error = %v", err)
+			}
+			second, _ := fsys.CreateTemp(dir, "download-*.tmp")
+			if first.Name() == second.Name() {
+				t.Errorf("Expected unique temporary names, got %q twice", first.Name())
+			}
+			if !strings.HasSuffix(first.Name(), ".tmp") || filepath.Dir(first.Name()) != dir {
+				t.Errorf("CreateTemp() = %q, want a .tmp file in %q", first.Name(), dir)
+			}
+
+			first.Write([]byte("partial download"))
+			first.Truncate(7)
+			first.Seek(0, io.SeekStart)
+			data, _ := io.ReadAll(first)
+			if string(data) != "partial" {
+				t.Errorf("Expected Truncate() to shorten the file, got %q", data)
+			}
+			first.Close()
+			second.Close()
+		})
+	}
+}
+
+func TestSetDefault(t *testing.T) {
+	defer SetDefault(nil)
+	if !IsOS(Default()) {
+		t.Fatal("Expected the operating system's file system by default")
+	}
+	mem := NewMemFS()
+	SetDefault(mem)
+	if Default() != FS(mem) || IsOS(Default()) {
+		t.Error("Expected SetDefault() to install the MemFS")
+	}
+	SetDefault(nil)
+	if !IsOS(Default()) {
+		t.Error("Expected SetDefault(nil) to restore the operating system's file system")
+	}
+}
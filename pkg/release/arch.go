@@ -1,100 +1,98 @@
 package release
 
 import (
+	"slices"
 	"strings"
 )
 
-// MapArch converts runtime.GOARCH values to common release asset naming conventions.
-// It handles both Go architecture names and provides fallback logic for unmapped architectures.
-func MapArch(arch string) string {
-	// Normalize input to lowercase for consistent matching
-	normalizedArch := strings.ToLower(strings.TrimSpace(arch))
-
-	switch normalizedArch {
-	// AMD64 / x86_64 variants
-	case "amd64", "x86_64", "x64":
-		return "x86_64"
-
-	// ARM64 variants
-	case "arm64", "aarch64":
-		return "arm64"
-
-	// ARM 32-bit variants
-	case "arm", "armv6", "armv7", "armhf":
-		return "arm"
-
-	// 386 / i386 variants
-	case "386", "i386", "i686", "x86":
-		return "i386"
-
-	// MIPS variants
-	case "mips":
-		return "mips"
-	case "mipsle":
-		return "mipsle"
-	case "mips64":
-		return "mips64"
-	case "mips64le":
-		return "mips64le"
-
-	// PowerPC variants
-	case "ppc64":
-		return "ppc64"
-	case "ppc64le":
-		return "ppc64le"
-
-	// IBM System z
-	case "s390x":
-		return "s390x"
+// architecture describes how one architecture is named by Go and in release assets
+type architecture struct {
+	goarch   string   // runtime.GOARCH value
+	name     string   // Common asset naming convention returned by MapArch
+	variants []string // Names mapped to this architecture, as returned by GetArchVariants
+	aliases  []string // Further asset name indicators used by the asset matcher only
+}
 
-	// RISC-V
-	case "riscv64":
-		return "riscv64"
+// architectures lists every architecture Go (gc or gccgo) can target. To support a new port,
+// add an entry here; MapArch, GetArchVariants and the default asset matching aliases follow.
+var architectures = []architecture{
+	{goarch: "amd64", name: "x86_64", variants: []string{"x86_64", "amd64", "x64"}},
+	{goarch: "arm64", name: "arm64", variants: []string{"arm64", "aarch64"}},
+	{goarch: "arm", name: "arm", variants: []string{"arm", "armv6", "armv7", "armhf"}, aliases: []string{"armv5", "armel"}},
+	{goarch: "386", name: "i386", variants: []string{"i386", "386", "i686", "x86"}},
+	{goarch: "loong64", name: "loong64", variants: []string{"loong64", "loongarch64"}},
+	{goarch: "mips", name: "mips", variants: []string{"mips"}},
+	{goarch: "mipsle", name: "mipsle", variants: []string{"mipsle", "mipsel"}},
+	{goarch: "mips64", name: "mips64", variants: []string{"mips64"}},
+	{goarch: "mips64le", name: "mips64le", variants: []string{"mips64le", "mips64el"}},
+	{goarch: "ppc64", name: "ppc64", variants: []string{"ppc64", "powerpc64"}},
+	{goarch: "ppc64le", name: "ppc64le", variants: []string{"ppc64le", "powerpc64le"}},
+	{goarch: "riscv64", name: "riscv64", variants: []string{"riscv64"}},
+	{goarch: "s390x", name: "s390x", variants: []string{"s390x"}},
+	{goarch: "sparc64", name: "sparc64", variants: []string{"sparc64"}},
+	{goarch: "wasm", name: "wasm", variants: []string{"wasm"}},
+}
 
-	// WebAssembly
-	case "wasm":
-		return "wasm"
+// lookupArchitecture finds the architecture a name refers to, ignoring case and whitespace
+func lookupArchitecture(arch string) (architecture, bool) {
+	normalizedArch := strings.ToLower(strings.TrimSpace(arch))
+	for _, a := range architectures {
+		if slices.Contains(a.variants, normalizedArch) {
+			return a, true
+		}
+	}
+	return architecture{}, false
+}
 
+// MapArch converts runtime.GOARCH values to common release asset naming conventions.
+// It handles both Go architecture names and provides fallback logic for unmapped architectures.
+func MapArch(arch string) string {
+	if a, ok := lookupArchitecture(arch); ok {
+		return a.name
+	}
 	// Fallback: return the original architecture if no mapping found
 	// This ensures compatibility with future or uncommon architectures
-	default:
-		return arch
-	}
+	return arch
 }
 
 // GetArchVariants returns common variants for a given architecture.
 // This can be used for fuzzy matching when exact architecture match fails.
 func GetArchVariants(arch string) []string {
-	normalizedArch := strings.ToLower(strings.TrimSpace(arch))
+	if a, ok := lookupArchitecture(arch); ok {
+		return slices.Clone(a.variants)
+	}
+	return []string{arch}
+}
+
+// defaultArchitectureAliases returns the built-in asset name aliases of each architecture,
+// keyed by GOARCH with the GOARCH name first
+func defaultArchitectureAliases() map[string][]string {
+	aliases := make(map[string][]string, len(architectures))
+	for _, a := range architectures {
+		names := []string{a.goarch}
+		for _, name := range slices.Concat(a.variants, a.aliases) {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+		aliases[a.goarch] = names
+	}
+	return aliases
+}
+
+// archIndicators holds every known architecture name, used to detect assets built for another
+// architecture
+var archIndicators = architectureIndicators()
 
-	switch normalizedArch {
-	case "amd64", "x86_64", "x64":
-		return []string{"x86_64", "amd64", "x64"}
-	case "arm64", "aarch64":
-		return []string{"arm64", "aarch64"}
-	case "arm", "armv6", "armv7", "armhf":
-		return []string{"arm", "armv6", "armv7", "armhf"}
-	case "386", "i386", "i686", "x86":
-		return []string{"i386", "386", "i686", "x86"}
-	case "mips":
-		return []string{"mips"}
-	case "mipsle":
-		return []string{"mipsle"}
-	case "mips64":
-		return []string{"mips64"}
-	case "mips64le":
-		return []string{"mips64le"}
-	case "ppc64":
-		return []string{"ppc64"}
-	case "ppc64le":
-		return []string{"ppc64le"}
-	case "s390x":
-		return []string{"s390x"}
-	case "riscv64":
-		return []string{"riscv64"}
-	case "wasm":
-		return []string{"wasm"}
-	default:
-		return []string{arch}
+// architectureIndicators collects the names of all architectures
+func architectureIndicators() []string {
+	var indicators []string
+	for _, a := range architectures {
+		for _, name := range slices.Concat(a.variants, a.aliases) {
+			if !slices.Contains(indicators, name) {
+				indicators = append(indicators, name)
+			}
+		}
 	}
+	return indicators
 }
@@ -50,6 +50,14 @@ func TestMapArch(t *testing.T) {
 		// WebAssembly
 		{"WASM", "wasm", "wasm"},
 
+		// LoongArch, SPARC and Debian-style names
+		{"LOONG64", "loong64", "loong64"},
+		{"loongarch64", "loongarch64", "loong64"},
+		{"SPARC64", "sparc64", "sparc64"},
+		{"mipsel", "mipsel", "mipsle"},
+		{"mips64el", "mips64el", "mips64le"},
+		{"powerpc64le", "powerpc64le", "ppc64le"},
+
 		// Case insensitive
 		{"AMD64 uppercase", "AMD64", "x86_64"},
 		{"ARM64 uppercase", "ARM64", "arm64"},
@@ -139,3 +147,19 @@ func TestGetArchVariants(t *testing.T) {
 		})
 	}
 }
+
+func TestArchitecturesCoverGOARCH(t *testing.T) {
+	// Architectures supported by gc (go tool dist list) plus gccgo's sparc64
+	goarches := []string{"386", "amd64", "arm", "arm64", "loong64", "mips", "mips64", "mips64le", "mipsle",
+		"ppc64", "ppc64le", "riscv64", "s390x", "sparc64", "wasm"}
+
+	defaults := DefaultAssetMatchingConfig().ArchitectureAliases
+	for _, goarch := range goarches {
+		if _, ok := lookupArchitecture(goarch); !ok {
+			t.Errorf("GOARCH %q has no architecture mapping", goarch)
+		}
+		if aliases := defaults[goarch]; len(aliases) == 0 || aliases[0] != goarch {
+			t.Errorf("Default aliases for %q should start with the GOARCH name, got %v", goarch, aliases)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package release
+
+import (
+	"bufio"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+// armVariantAliases lists the asset name indicators for each 32-bit ARM architecture version
+var armVariantAliases = map[int][]string{
+	5: {"armv5", "armel", "arm5"},
+	6: {"armv6", "arm6"},
+	7: {"armv7", "armhf", "arm7"},
+}
+
+// DetectARMVersion returns the 32-bit ARM architecture version of the host ("5", "6" or "7"),
+// or "" if it cannot be determined or the host is not 32-bit ARM.
+// Detection order: GOARM environment variable, /proc/cpuinfo (Linux), GOARM the program was built with.
+func DetectARMVersion() string {
+	if runtime.GOARCH != "arm" {
+		return ""
+	}
+
+	if version := normalizeARMVersion(os.Getenv("GOARM")); version != "" {
+		return version
+	}
+
+	if runtime.GOOS == "linux" {
+		if content, err := os.ReadFile("/proc/cpuinfo"); err == nil {
+			if version := parseCPUInfoARMVersion(string(content)); version != "" {
+				return version
+			}
+		}
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "GOARM" {
+				return normalizeARMVersion(setting.Value)
+			}
+		}
+	}
+
+	return ""
+}
+
+// parseCPUInfoARMVersion extracts the ARM version from /proc/cpuinfo content.
+// Hosts without hardware floating point are reported as ARMv5 (soft-float) regardless of the CPU architecture.
+func parseCPUInfoARMVersion(content string) string {
+	architecture := ""
+	modelVersion := ""
+	hasVFP := false
+	hasFeatures := false
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), ":")
+		if !found {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "CPU architecture":
+			if architecture == "" {
+				architecture = value
+			}
+		case "model name", "Processor":
+			// ARM1176 (Raspberry Pi 1/Zero) reports "CPU architecture: 7" but is ARMv6
+			if idx := strings.Index(strings.ToLower(value), "armv"); idx >= 0 && modelVersion == "" {
+				modelVersion = normalizeARMVersion(strings.Fields(value[idx:])[0])
+			}
+		case "Features":
+			hasFeatures = true
+			for _, feature := range strings.Fields(value) {
+				if strings.HasPrefix(feature, "vfp") {
+					hasVFP = true
+				}
+			}
+		}
+	}
+
+	version := normalizeARMVersion(architecture)
+	if modelVersion != "" && (version == "" || modelVersion < version) {
+		version = modelVersion
+	}
+	if version != "" && hasFeatures && !hasVFP {
+		return "5"
+	}
+	return version
+}
+
+// normalizeARMVersion converts values like "7", "v7", "armv7", "7,softfloat" or "8" (AArch32 on ARMv8) to "5", "6" or "7"
+func normalizeARMVersion(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	value, _, _ = strings.Cut(value, ",")
+	value = strings.TrimPrefix(value, "armv")
+	value = strings.TrimPrefix(value, "v")
+	if end := strings.IndexFunc(value, func(r rune) bool { return r < '0' || r > '9' }); end >= 0 {
+		value = value[:end]
+	}
+
+	version, err := strconv.Atoi(value)
+	if err != nil || version < 5 {
+		return ""
+	}
+	if version > 7 {
+		// ARMv8 CPUs running 32-bit userspace execute ARMv7 binaries
+		version = 7
+	}
+	return strconv.Itoa(version)
+}
+
+// assetARMVersion returns the ARM version an asset name targets, or 0 if it does not name one
+func assetARMVersion(lowerName string) int {
+	for version := 7; version >= 5; version-- {
+		for _, alias := range armVariantAliases[version] {
+			if strings.Contains(lowerName, alias) {
+				return version
+			}
+		}
+	}
+	return 0
+}
+
+// scoreARMVariant adjusts an asset score for the host's ARM version: exact variants are preferred,
+// older variants remain compatible and newer variants cannot run on the host
+func scoreARMVariant(lowerName, hostVersion string) int {
+	host, err := strconv.Atoi(hostVersion)
+	if err != nil {
+		return 0
+	}
+
+	switch asset := assetARMVersion(lowerName); {
+	case asset == 0:
+		return 0
+	case asset == host:
+		return 6
+	case asset < host:
+		return 2 - (host - asset)
+	default:
+		return -25
+	}
+}
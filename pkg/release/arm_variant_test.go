@@ -0,0 +1,82 @@
+package release
+
+import "testing"
+
+func TestParseCPUInfoARMVersion(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"raspberry pi zero", "processor\t: 0\nmodel name\t: ARMv6-compatible processor rev 7 (v6l)\nFeatures\t: half thumb fastmult vfp edsp java tls\nCPU architecture: 7\n", "6"},
+		{"raspberry pi 3 32-bit", "processor\t: 0\nFeatures\t: half thumb fastmult vfp edsp neon vfpv3 tls vfpv4\nCPU architecture: 8\n", "7"},
+		{"armv6", "Features\t: half thumb fastmult vfp edsp java tls\nCPU architecture: 6\n", "6"},
+		{"soft-float", "Features\t: swp half thumb fastmult edsp\nCPU architecture: 7\n", "5"},
+		{"no architecture", "processor\t: 0\n", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := parseCPUInfoARMVersion(tc.content); got != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestNormalizeARMVersion(t *testing.T) {
+	testCases := map[string]string{
+		"7":            "7",
+		"v6":           "6",
+		"armv7l":       "7",
+		"6,softfloat":  "6",
+		"8":            "7",
+		"4":            "",
+		"":             "",
+		"not-a-number": "",
+	}
+
+	for input, expected := range testCases {
+		if got := normalizeARMVersion(input); got != expected {
+			t.Errorf("normalizeARMVersion(%q): expected %q, got %q", input, expected, got)
+		}
+	}
+}
+
+func TestAssetMatcher_ARMVariants(t *testing.T) {
+	assetNames := []string{
+		"app-linux-armv6.tar.gz",
+		"app-linux-armv7.tar.gz",
+		"app-linux-arm64.tar.gz",
+	}
+
+	testCases := []struct {
+		armVersion string
+		assets     []string
+		expected   string
+	}{
+		{"6", assetNames, "app-linux-armv6.tar.gz"},
+		{"7", assetNames, "app-linux-armv7.tar.gz"},
+		{"7", []string{"app-linux-armv6.tar.gz", "app-linux-arm64.tar.gz"}, "app-linux-armv6.tar.gz"},
+		{"6", []string{"app-linux-armhf.tar.gz", "app-linux-arm.tar.gz"}, "app-linux-arm.tar.gz"},
+	}
+
+	for _, tc := range testCases {
+		t.Run("armv"+tc.armVersion, func(t *testing.T) {
+			config := DefaultAssetMatchingConfig()
+			config.ARMVersion = tc.armVersion
+
+			matcher := NewAssetMatcher(config)
+			matcher.arch = "arm"
+			matcher.os = "linux"
+
+			bestMatch, err := matcher.FindBestMatch(tc.assets)
+			if err != nil {
+				t.Fatalf("Expected to find a match, got error: %v", err)
+			}
+			if bestMatch != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, bestMatch)
+			}
+		})
+	}
+}
@@ -2,9 +2,13 @@ package release
 
 import (
 	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
 	"regexp"
 	"runtime"
+	"slices"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 // AssetMatchingStrategy defines how to match release assets
@@ -25,28 +29,79 @@ const (
 
 // AssetMatchingConfig configures how assets are matched and handled
 type AssetMatchingConfig struct {
-	Strategy           AssetMatchingStrategy `json:"strategy"`
-	CustomPatterns     []string              `json:"custom_patterns"`     // Custom regex patterns for asset matching
-	IsDirectBinary     bool                  `json:"is_direct_binary"`    // True if asset is a direct binary, not an archive
-	ProjectName        string                `json:"project_name"`        // Project name for pattern matching
-	ArchitectureAliases map[string][]string  `json:"architecture_aliases"` // Custom architecture aliases
-	OSAliases          map[string][]string   `json:"os_aliases"`          // Custom OS aliases
-	FileExtensions     []string              `json:"file_extensions"`     // Expected file extensions
+	Strategy                   AssetMatchingStrategy `json:"strategy"`
+	CustomPatterns             []string              `json:"custom_patterns"`                        // Custom regex patterns for asset matching
+	AssetNameTemplates         []string              `json:"asset_name_templates,omitempty"`         // text/template names tried before the strategy, e.g. "{{.Project}}_{{.Version}}_{{.OS}}_{{.Arch}}.tar.gz" (see AssetTemplateData)
+	AssetLabelTemplates        []string              `json:"asset_label_templates,omitempty"`        // Like AssetNameTemplates for GitHub asset labels, e.g. "Linux {{.Arch}} binary"; tried first
+	IsDirectBinary             bool                  `json:"is_direct_binary"`                       // True if asset is a direct binary, not an archive
+	ProjectName                string                `json:"project_name"`                           // Project name for pattern matching
+	ArchitectureAliases        map[string][]string   `json:"architecture_aliases"`                   // Custom architecture aliases; a key replaces the default aliases of that architecture
+	OSAliases                  map[string][]string   `json:"os_aliases"`                             // Custom OS aliases; a key replaces the default aliases of that OS
+	ExtraArchitectureAliases   map[string][]string   `json:"extra_architecture_aliases,omitempty"`   // Aliases added to the defaults, e.g. {"loong64": {"loongarch64"}}
+	ExtraOSAliases             map[string][]string   `json:"extra_os_aliases,omitempty"`             // Aliases added to the defaults, e.g. {"solaris": {"sunos"}}
+	RemovedArchitectureAliases map[string][]string   `json:"removed_architecture_aliases,omitempty"` // Aliases dropped from the defaults, e.g. {"amd64": {"x64"}}
+	RemovedOSAliases           map[string][]string   `json:"removed_os_aliases,omitempty"`           // Aliases dropped from the defaults, e.g. {"windows": {"win", "Win"}}
+	FileExtensions             []string              `json:"file_extensions"`                        // Expected file extensions
+	CaseSensitive              bool                  `json:"case_sensitive"`                         // Match OS/arch aliases and exclude/priority patterns exactly as written instead of ignoring case
 
 	// Enhanced filtering and CDN support
-	ExcludePatterns     []string                 `json:"exclude_patterns"`     // Patterns to explicitly exclude (airgap, signatures)
-	PriorityPatterns    []string                 `json:"priority_patterns"`    // Patterns that get higher priority scores
-	CDNBaseURL          string                   `json:"cdn_base_url"`         // Base URL for CDN downloads (e.g., get.helm.sh)
-	CDNPattern          string                   `json:"cdn_pattern"`          // URL pattern for CDN downloads with {version}, {os}, {arch} placeholders
-	CDNVersionFormat    string                   `json:"cdn_version_format"`   // Version format for CDN: "as-is", "with-v", "without-v"
-	CDNArchMapping      map[string]string        `json:"cdn_arch_mapping"`     // Custom architecture mapping for this CDN
-	ExtractionConfig    *ExtractionConfig        `json:"extraction_config"`    // Configuration for complex archive extraction
+	ExcludePatterns     []string                `json:"exclude_patterns"`                // Patterns to explicitly exclude (airgap, signatures)
+	PriorityPatterns    []string                `json:"priority_patterns"`               // Patterns that get higher priority scores
+	CDNBaseURL          string                  `json:"cdn_base_url"`                    // Base URL for CDN downloads (e.g., get.helm.sh)
+	CDNPattern          string                  `json:"cdn_pattern"`                     // URL pattern for CDN downloads with {version}, {os}, {arch} placeholders
+	CDNMirrors          []string                `json:"cdn_mirrors,omitempty"`           // Additional base URLs serving the same layout; the fastest healthy one is preferred
+	CDNVersionFormat    string                  `json:"cdn_version_format"`              // Version format for CDN: "as-is", "with-v", "without-v"
+	CDNArchMapping      map[string]string       `json:"cdn_arch_mapping"`                // Custom architecture mapping for this CDN
+	CDNHTTPConfig       *HTTPClientConfig       `json:"cdn_http_config,omitempty"`       // Retry and timeout settings for CDN requests (default: DefaultCDNHTTPClientConfig)
+	CDNVersionDiscovery *VersionDiscoveryConfig `json:"cdn_version_discovery,omitempty"` // Endpoint reporting the latest CDN version (default: built-in for known CDNs)
+	CDNRequestSigner    RequestSigner           `json:"-"`                               // Signs CDN requests, e.g. for CloudFront signed URLs (see CDNDownloader.RequestSigner)
+	ExtractionConfig    *ExtractionConfig       `json:"extraction_config"`               // Configuration for complex archive extraction
+	LinkTypes           []string                `json:"link_types,omitempty"`            // GitLab only: restrict matching to release links of these types (e.g. "package"), excluding "other" links such as checksums
+
+	// macOS universal ("fat") binary handling
+	UniversalBinaryPreference string `json:"universal_binary_preference"` // "prefer", "avoid", or "" to accept universal assets as an architecture match
+
+	// Linux AppImage handling
+	AppImagePreference string `json:"appimage_preference"` // "prefer", "avoid", or "" to rank AppImages like other assets
+
+	// Apple Silicon fallback to darwin/amd64 assets run through Rosetta 2
+	AllowRosettaFallback bool `json:"allow_rosetta_fallback"` // Select darwin/amd64 assets on darwin/arm64 when no native asset exists
+
+	// 32-bit ARM variant selection
+	ARMVersion string `json:"arm_version"` // Override the detected ARM version ("5", "6" or "7"); empty to auto-detect
+
+	// Delta updates via binary patches
+	Delta DeltaConfig `json:"delta"` // Patch the installed binary instead of downloading the full asset when possible
 }
 
+// Universal binary preferences for AssetMatchingConfig.UniversalBinaryPreference
+const (
+	UniversalBinaryPrefer = "prefer" // Prefer universal assets over architecture-specific ones on macOS
+	UniversalBinaryAvoid  = "avoid"  // Only pick universal assets when nothing architecture-specific exists
+)
+
+// AppImage preferences for AssetMatchingConfig.AppImagePreference
+const (
+	AppImagePrefer = "prefer" // Prefer .AppImage assets over archives on Linux
+	AppImageAvoid  = "avoid"  // Only pick .AppImage assets when nothing else matches
+)
+
+// universalAssetPattern matches macOS universal binary indicators as separate name components
+var universalAssetPattern = regexp.MustCompile(`(^|[-_.])(universal|all|fat)([-_.]|$)`)
+
 // ExtractionConfig configures how binaries are extracted from archives
-type ExtractionConfig struct {
-	StripComponents int    `json:"strip_components"` // Number of directory components to strip (like tar --strip-components)
-	BinaryPath      string `json:"binary_path"`      // Specific path to binary within archive (e.g., "linux-amd64/helm")
+type ExtractionConfig = fileUtils.ExtractionConfig
+
+// extractionConfig returns the ExtractionConfig to install with. When CDNArchMapping is set and
+// ExtractionConfig.ArchMapping is not, {arch} in BinaryPath is mapped like the CDN URL, so layouts
+// such as Helm's linux-amd64/helm resolve on every platform.
+func (c AssetMatchingConfig) extractionConfig() *ExtractionConfig {
+	if c.ExtractionConfig == nil || c.ExtractionConfig.ArchMapping != nil || c.CDNArchMapping == nil {
+		return c.ExtractionConfig
+	}
+	config := *c.ExtractionConfig
+	config.ArchMapping = map[string]string{runtime.GOARCH: mapCDNArch(c.CDNArchMapping, runtime.GOARCH)}
+	return &config
 }
 
 // DefaultAssetMatchingConfig returns a sensible default configuration
@@ -63,48 +118,83 @@ func DefaultAssetMatchingConfig() AssetMatchingConfig {
 			"\\.sha256$", // Exclude checksum files
 			"\\.sha512$", // Exclude checksum files
 			"\\.md5$",    // Exclude checksum files
+			"\\.zsync$",  // Exclude AppImage zsync update metadata
 		},
-		ArchitectureAliases: map[string][]string{
-			"amd64":   {"amd64", "x86_64", "x64"},
-			"arm64":   {"arm64", "aarch64"},
-			"arm":     {"arm", "armv6", "armv7", "armhf"},
-			"386":     {"386", "i386", "i686", "x86"},
-			"mips":    {"mips"},
-			"mips64":  {"mips64"},
-			"ppc64":   {"ppc64"},
-			"ppc64le": {"ppc64le"},
-			"s390x":   {"s390x"},
-			"riscv64": {"riscv64"},
-		},
-		OSAliases: map[string][]string{
-			"linux":   {"linux", "Linux"},
-			"darwin":  {"darwin", "Darwin", "macos", "macOS", "osx", "OSX"},
-			"windows": {"windows", "Windows", "win", "Win"},
-			"freebsd": {"freebsd", "FreeBSD"},
-			"openbsd": {"openbsd", "OpenBSD"},
-			"netbsd":  {"netbsd", "NetBSD"},
-		},
+		ArchitectureAliases: defaultArchitectureAliases(),
+		OSAliases:           defaultOSAliases(),
+	}
+}
+
+// defaultOSAliases returns the built-in asset name aliases of each operating system
+func defaultOSAliases() map[string][]string {
+	return map[string][]string{
+		"linux":   {"linux", "Linux"},
+		"darwin":  {"darwin", "Darwin", "macos", "macOS", "osx", "OSX"},
+		"windows": {"windows", "Windows", "win", "Win"},
+		"freebsd": {"freebsd", "FreeBSD"},
+		"openbsd": {"openbsd", "OpenBSD"},
+		"netbsd":  {"netbsd", "NetBSD"},
 	}
 }
 
 // AssetMatcher provides flexible asset matching capabilities
 type AssetMatcher struct {
-	config AssetMatchingConfig
-	os     string
-	arch   string
+	config     AssetMatchingConfig
+	os         string
+	arch       string
+	armVersion string
+	warnings   []string
+	patterns   *assetPatterns // Compiled regular expressions (see compiledPatterns)
 }
 
+// RosettaFallbackWarning is reported when an amd64 asset was selected for an Apple Silicon host
+const RosettaFallbackWarning = "no native darwin/arm64 asset found; selected darwin/amd64 asset which requires Rosetta 2"
+
 // NewAssetMatcher creates a new asset matcher with the given configuration
 func NewAssetMatcher(config AssetMatchingConfig) *AssetMatcher {
-	return &AssetMatcher{
-		config: config,
-		os:     runtime.GOOS,
-		arch:   runtime.GOARCH,
+	armVersion := normalizeARMVersion(config.ARMVersion)
+	if armVersion == "" {
+		armVersion = DetectARMVersion()
+	}
+
+	am := &AssetMatcher{
+		config:     config,
+		os:         runtime.GOOS,
+		arch:       runtime.GOARCH,
+		armVersion: armVersion,
 	}
+	am.compilePatterns()
+	return am
 }
 
 // FindBestMatch finds the best matching asset from a list of asset names
 func (am *AssetMatcher) FindBestMatch(assetNames []string) (string, error) {
+	am.warnings = nil
+
+	match, err := am.findBestMatch(assetNames)
+	if err != nil && am.shouldTryRosettaFallback() {
+		fallback := &AssetMatcher{config: am.config, os: am.os, arch: "amd64"}
+		if fallbackMatch, fallbackErr := fallback.findBestMatch(assetNames); fallbackErr == nil {
+			am.warnings = append(am.warnings, RosettaFallbackWarning)
+			return fallbackMatch, nil
+		}
+	}
+	return match, err
+}
+
+// Warnings returns non-fatal notes about the last FindBestMatch call (e.g. a Rosetta fallback)
+func (am *AssetMatcher) Warnings() []string {
+	return am.warnings
+}
+
+// shouldTryRosettaFallback reports whether darwin/amd64 assets may be used on this host
+func (am *AssetMatcher) shouldTryRosettaFallback() bool {
+	return am.config.AllowRosettaFallback && am.os == "darwin" && am.arch == "arm64" &&
+		am.config.Strategy != CDNStrategy
+}
+
+// findBestMatch finds the best matching asset for the matcher's platform
+func (am *AssetMatcher) findBestMatch(assetNames []string) (string, error) {
 	if len(assetNames) == 0 {
 		return "", fmt.Errorf("no assets provided")
 	}
@@ -178,20 +268,10 @@ func (am *AssetMatcher) findCustomMatch(assetNames []string) (string, error) {
 		return "", fmt.Errorf("no custom patterns defined")
 	}
 
-	osAliases := am.getOSAliases(am.os)
-	archAliases := am.getArchAliases(am.arch)
-
-	for _, pattern := range am.config.CustomPatterns {
-		// Replace placeholders in pattern
-		expandedPattern := am.expandPattern(pattern, osAliases, archAliases)
-		
-		regex, err := regexp.Compile(expandedPattern)
-		if err != nil {
-			continue // Skip invalid patterns
-		}
-
+	// Patterns have their placeholders expanded; invalid patterns are skipped
+	for _, pattern := range am.compiledPatterns().custom {
 		for _, assetName := range assetNames {
-			if regex.MatchString(assetName) {
+			if pattern.regex.MatchString(assetName) {
 				return assetName, nil
 			}
 		}
@@ -208,7 +288,7 @@ func (am *AssetMatcher) scoreAsset(assetName string, osAliases, archAliases []st
 	// Check for OS matches
 	osMatched := false
 	for _, osAlias := range osAliases {
-		if strings.Contains(lowerName, strings.ToLower(osAlias)) {
+		if am.containsAlias(assetName, osAlias) {
 			score += 10
 			osMatched = true
 			break
@@ -218,13 +298,50 @@ func (am *AssetMatcher) scoreAsset(assetName string, osAliases, archAliases []st
 	// Check for architecture matches
 	archMatched := false
 	for _, archAlias := range archAliases {
-		if strings.Contains(lowerName, strings.ToLower(archAlias)) {
+		if am.containsAlias(assetName, archAlias) {
 			score += 10
 			archMatched = true
 			break
 		}
 	}
 
+	// macOS universal binaries run on every architecture
+	if am.isUniversalAsset(lowerName) {
+		if !archMatched {
+			score += 10
+			archMatched = true
+		}
+		switch am.config.UniversalBinaryPreference {
+		case UniversalBinaryPrefer:
+			score += 20
+		case UniversalBinaryAvoid:
+			score -= 15
+		}
+	}
+
+	// AppImages only run on Linux and rarely name the OS
+	if strings.HasSuffix(lowerName, ".appimage") {
+		if am.os != "linux" {
+			score -= 20
+		} else {
+			if !osMatched {
+				score += 10
+				osMatched = true
+			}
+			switch am.config.AppImagePreference {
+			case AppImagePrefer:
+				score += 20
+			case AppImageAvoid:
+				score -= 15
+			}
+		}
+	}
+
+	// Prefer the ARM variant (v5/v6/v7) the host can actually run
+	if archMatched && am.arch == "arm" && am.armVersion != "" {
+		score += scoreARMVariant(lowerName, am.armVersion)
+	}
+
 	// Bonus points for having both OS and arch
 	if osMatched && archMatched {
 		score += 5
@@ -237,13 +354,13 @@ func (am *AssetMatcher) scoreAsset(assetName string, osAliases, archAliases []st
 	}
 
 	// Check for common patterns
-	if am.matchesCommonPatterns(lowerName, osAliases, archAliases) {
+	if am.matchesCommonPatterns(lowerName) {
 		score += 3
 	}
 
 	// Bonus for priority patterns
-	for _, priorityPattern := range am.config.PriorityPatterns {
-		if matched, _ := regexp.MatchString(strings.ToLower(priorityPattern), lowerName); matched {
+	for _, priorityPattern := range am.compiledPatterns().priority {
+		if priorityPattern.MatchString(am.foldCase(assetName)) {
 			score += 15 // High bonus for priority patterns
 			break
 		}
@@ -267,33 +384,19 @@ func (am *AssetMatcher) scoreAsset(assetName string, osAliases, archAliases []st
 	return score
 }
 
-// matchesCommonPatterns checks for common naming patterns
-func (am *AssetMatcher) matchesCommonPatterns(assetName string, osAliases, archAliases []string) bool {
-	// Pattern: {project}-{version}-{arch} (like k0s)
-	if am.config.ProjectName != "" {
-		for _, archAlias := range archAliases {
-			projectPattern := fmt.Sprintf("%s-.*-%s", strings.ToLower(am.config.ProjectName), strings.ToLower(archAlias))
-			if matched, _ := regexp.MatchString(projectPattern, assetName); matched {
-				return true
-			}
-		}
-	}
-
-	// Pattern: {os}-{arch} or {arch}-{os}
-	for _, osAlias := range osAliases {
-		for _, archAlias := range archAliases {
-			pattern1 := fmt.Sprintf("%s.*%s", strings.ToLower(osAlias), strings.ToLower(archAlias))
-			pattern2 := fmt.Sprintf("%s.*%s", strings.ToLower(archAlias), strings.ToLower(osAlias))
+// isUniversalAsset reports whether an asset is a macOS universal binary for the current platform
+func (am *AssetMatcher) isUniversalAsset(lowerName string) bool {
+	return am.os == "darwin" && universalAssetPattern.MatchString(lowerName)
+}
 
-			if matched, _ := regexp.MatchString(pattern1, assetName); matched {
-				return true
-			}
-			if matched, _ := regexp.MatchString(pattern2, assetName); matched {
-				return true
-			}
+// matchesCommonPatterns checks for common naming patterns:
+// {project}-{version}-{arch} (like k0s), {os}-{arch} or {arch}-{os}
+func (am *AssetMatcher) matchesCommonPatterns(assetName string) bool {
+	for _, pattern := range am.compiledPatterns().common {
+		if pattern.MatchString(assetName) {
+			return true
 		}
 	}
-
 	return false
 }
 
@@ -302,48 +405,83 @@ func (am *AssetMatcher) containsWrongPlatform(assetName string, osAliases, archA
 	// Check for wrong OS
 	allOSAliases := []string{"linux", "darwin", "windows", "freebsd", "openbsd", "netbsd", "macos", "osx", "win"}
 	for _, wrongOS := range allOSAliases {
-		if strings.Contains(assetName, wrongOS) {
-			// Check if this is actually our OS
-			isOurOS := false
-			for _, ourOS := range osAliases {
-				if strings.EqualFold(wrongOS, ourOS) {
-					isOurOS = true
-					break
-				}
-			}
-			if !isOurOS {
-				return true
-			}
+		if containsWord(assetName, wrongOS) && !isOwnAlias(assetName, wrongOS, osAliases) {
+			return true
 		}
 	}
 
 	// Check for wrong architecture
-	allArchAliases := []string{"amd64", "x86_64", "arm64", "aarch64", "arm", "386", "i386", "mips", "ppc64"}
-	for _, wrongArch := range allArchAliases {
-		if strings.Contains(assetName, wrongArch) {
-			// Check if this is actually our arch
-			isOurArch := false
-			for _, ourArch := range archAliases {
-				if strings.EqualFold(wrongArch, ourArch) {
-					isOurArch = true
-					break
-				}
-			}
-			if !isOurArch {
-				return true
-			}
+	for _, wrongArch := range archIndicators {
+		if containsWord(assetName, wrongArch) && !isOwnAlias(assetName, wrongArch, archAliases) {
+			return true
 		}
 	}
 
 	return false
 }
 
+// isOwnAlias reports whether a platform indicator found in an asset name belongs to the current platform,
+// either directly or as part of one of our aliases present in the name (e.g. "win" in "darwin", "arm" in "arm64")
+func isOwnAlias(assetName, indicator string, ourAliases []string) bool {
+	for _, ourAlias := range ourAliases {
+		lowerAlias := strings.ToLower(ourAlias)
+		if strings.EqualFold(indicator, ourAlias) {
+			return true
+		}
+		if strings.Contains(lowerAlias, indicator) && containsWord(assetName, lowerAlias) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsAlias reports whether a platform alias appears in an asset name as a word,
+// ignoring case unless CaseSensitive is set
+func (am *AssetMatcher) containsAlias(assetName, alias string) bool {
+	return containsWord(am.foldCase(assetName), am.foldCase(alias))
+}
+
+// foldCase lowercases a value unless matching is case-sensitive
+func (am *AssetMatcher) foldCase(value string) string {
+	if am.config.CaseSensitive {
+		return value
+	}
+	return strings.ToLower(value)
+}
+
+// containsWord reports whether word occurs in s without a letter directly before or after it, so
+// that "windows" matches "tool-windows-amd64" and "tool_win64" matches "win" but "windowsill"
+// and "darwin" do not. Digits count as boundaries as they commonly follow platform names
+// ("linux64", "arm7"). Runes are decoded so multi-byte characters are handled correctly.
+func containsWord(s, word string) bool {
+	if word == "" {
+		return false
+	}
+	for offset := 0; offset <= len(s)-len(word); {
+		index := strings.Index(s[offset:], word)
+		if index < 0 {
+			return false
+		}
+		start := offset + index
+		end := start + len(word)
+
+		before, _ := utf8.DecodeLastRuneInString(s[:start])
+		after, _ := utf8.DecodeRuneInString(s[end:])
+		if (start == 0 || !unicode.IsLetter(before)) && (end == len(s) || !unicode.IsLetter(after)) {
+			return true
+		}
+		_, size := utf8.DecodeRuneInString(s[start:])
+		offset = start + size
+	}
+	return false
+}
+
 // containsWrongOS checks if the asset contains indicators for wrong OS
 func (am *AssetMatcher) containsWrongOS(assetName string, osAliases []string) bool {
 	// Check for wrong OS
 	allOSAliases := []string{"linux", "darwin", "windows", "freebsd", "openbsd", "netbsd", "macos", "osx", "win"}
 	for _, wrongOS := range allOSAliases {
-		if strings.Contains(assetName, wrongOS) {
+		if containsWord(assetName, wrongOS) {
 			// Check if this is actually our OS
 			isOurOS := false
 			for _, ourOS := range osAliases {
@@ -362,19 +500,51 @@ func (am *AssetMatcher) containsWrongOS(assetName string, osAliases []string) bo
 
 // getOSAliases returns all aliases for the given OS
 func (am *AssetMatcher) getOSAliases(os string) []string {
-	if aliases, exists := am.config.OSAliases[os]; exists {
-		return aliases
-	}
-	return []string{os}
+	return resolveAliases([]string{os}, []string{os}, am.config.OSAliases, defaultOSAliases(),
+		am.config.ExtraOSAliases, am.config.RemovedOSAliases)
 }
 
-// getArchAliases returns all aliases for the given architecture
+// getArchAliases returns all aliases for the given architecture, looked up by its Go name
+// ("amd64") or its normalized name ("x86_64")
 func (am *AssetMatcher) getArchAliases(arch string) []string {
 	mappedArch := MapArch(arch)
-	if aliases, exists := am.config.ArchitectureAliases[mappedArch]; exists {
-		return aliases
+	return resolveAliases([]string{arch, mappedArch}, []string{arch, mappedArch}, am.config.ArchitectureAliases,
+		defaultArchitectureAliases(), am.config.ExtraArchitectureAliases, am.config.RemovedArchitectureAliases)
+}
+
+// resolveAliases merges the alias configuration of the first key found in each map: configured
+// aliases replace the defaults, extra aliases are appended and removed aliases are dropped.
+// The fallback is used if no aliases remain.
+func resolveAliases(keys, fallback []string, configured, defaults, extra, removed map[string][]string) []string {
+	lookup := func(aliases map[string][]string) ([]string, bool) {
+		for _, key := range keys {
+			if values, exists := aliases[key]; exists {
+				return values, true
+			}
+		}
+		return nil, false
+	}
+
+	base, exists := lookup(configured)
+	if !exists {
+		if base, exists = lookup(defaults); !exists {
+			base = fallback
+		}
+	}
+	extraAliases, _ := lookup(extra)
+	removedAliases, _ := lookup(removed)
+
+	var aliases []string
+	for _, alias := range append(append([]string(nil), base...), extraAliases...) {
+		if slices.Contains(aliases, alias) || slices.Contains(removedAliases, alias) {
+			continue
+		}
+		aliases = append(aliases, alias)
+	}
+	if len(aliases) == 0 {
+		return fallback
 	}
-	return []string{arch, mappedArch}
+	return aliases
 }
 
 // expandPattern expands pattern placeholders with actual values
@@ -404,10 +574,10 @@ func (am *AssetMatcher) filterExcludedAssets(assetNames []string) []string {
 	var filtered []string
 	for _, assetName := range assetNames {
 		excluded := false
-		lowerName := strings.ToLower(assetName)
+		name := am.foldCase(assetName)
 
-		for _, excludePattern := range am.config.ExcludePatterns {
-			if matched, _ := regexp.MatchString(strings.ToLower(excludePattern), lowerName); matched {
+		for _, excludePattern := range am.compiledPatterns().exclude {
+			if excludePattern.regex.MatchString(name) {
 				excluded = true
 				break
 			}
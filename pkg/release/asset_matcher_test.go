@@ -1,6 +1,7 @@
 package release
 
 import (
+	"fmt"
 	"runtime"
 	"testing"
 )
@@ -282,6 +283,107 @@ func TestAssetMatcher_OSVariants(t *testing.T) {
 	}
 }
 
+func TestAssetMatcher_UniversalBinaryPreference(t *testing.T) {
+	assetNames := []string{
+		"app-darwin-arm64.tar.gz",
+		"app-darwin-amd64.tar.gz",
+		"app-darwin-universal.tar.gz",
+		"app-linux-amd64.tar.gz",
+	}
+
+	testCases := []struct {
+		name       string
+		preference string
+		assets     []string
+		expected   string
+	}{
+		{"default prefers specific", "", assetNames, "app-darwin-arm64.tar.gz"},
+		{"prefer universal", UniversalBinaryPrefer, assetNames, "app-darwin-universal.tar.gz"},
+		{"avoid universal", UniversalBinaryAvoid, assetNames, "app-darwin-arm64.tar.gz"},
+		{"avoid falls back to universal", UniversalBinaryAvoid, []string{"app-darwin-all.tar.gz", "app-linux-arm64.tar.gz"}, "app-darwin-all.tar.gz"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := DefaultAssetMatchingConfig()
+			config.Strategy = FlexibleStrategy
+			config.UniversalBinaryPreference = tc.preference
+
+			matcher := NewAssetMatcher(config)
+			matcher.arch = "arm64"
+			matcher.os = "darwin"
+
+			bestMatch, err := matcher.FindBestMatch(tc.assets)
+			if err != nil {
+				t.Fatalf("Expected to find a match, got error: %v", err)
+			}
+			if bestMatch != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, bestMatch)
+			}
+		})
+	}
+}
+
+func TestAssetMatcher_UniversalIgnoredOffDarwin(t *testing.T) {
+	config := DefaultAssetMatchingConfig()
+	config.Strategy = FlexibleStrategy
+	config.UniversalBinaryPreference = UniversalBinaryPrefer
+
+	matcher := NewAssetMatcher(config)
+	matcher.arch = "amd64"
+	matcher.os = "linux"
+
+	bestMatch, err := matcher.FindBestMatch([]string{"app-linux-all.tar.gz", "app-linux-amd64.tar.gz"})
+	if err != nil {
+		t.Fatalf("Expected to find a match, got error: %v", err)
+	}
+	if bestMatch != "app-linux-amd64.tar.gz" {
+		t.Errorf("Expected architecture-specific asset off macOS, got %s", bestMatch)
+	}
+}
+
+func TestAssetMatcher_RosettaFallback(t *testing.T) {
+	assetNames := []string{
+		"app-darwin-amd64.tar.gz",
+		"app-linux-arm64.tar.gz",
+	}
+
+	config := DefaultAssetMatchingConfig()
+	config.Strategy = FlexibleStrategy
+
+	matcher := NewAssetMatcher(config)
+	matcher.arch = "arm64"
+	matcher.os = "darwin"
+	if _, err := matcher.FindBestMatch(assetNames); err == nil {
+		t.Fatal("Expected no match without Rosetta fallback")
+	}
+
+	config.AllowRosettaFallback = true
+	matcher = NewAssetMatcher(config)
+	matcher.arch = "arm64"
+	matcher.os = "darwin"
+
+	bestMatch, err := matcher.FindBestMatch(assetNames)
+	if err != nil {
+		t.Fatalf("Expected Rosetta fallback match, got error: %v", err)
+	}
+	if bestMatch != "app-darwin-amd64.tar.gz" {
+		t.Errorf("Expected app-darwin-amd64.tar.gz, got %s", bestMatch)
+	}
+	if len(matcher.Warnings()) != 1 || matcher.Warnings()[0] != RosettaFallbackWarning {
+		t.Errorf("Expected Rosetta warning, got %v", matcher.Warnings())
+	}
+
+	// A native asset always wins and produces no warning
+	bestMatch, err = matcher.FindBestMatch(append(assetNames, "app-darwin-arm64.tar.gz"))
+	if err != nil || bestMatch != "app-darwin-arm64.tar.gz" {
+		t.Errorf("Expected native arm64 asset, got %s (err: %v)", bestMatch, err)
+	}
+	if len(matcher.Warnings()) != 0 {
+		t.Errorf("Expected no warnings for native match, got %v", matcher.Warnings())
+	}
+}
+
 func TestAssetMatcher_NoMatch(t *testing.T) {
 	// Test when no suitable asset is found
 	assetNames := []string{
@@ -337,3 +439,222 @@ func BenchmarkAssetMatcher_FlexibleStrategy(b *testing.B) {
 		matcher.FindBestMatch(assetNames)
 	}
 }
+
+// kubernetesStyleAssets returns the asset names of a release with hundreds of assets, like a
+// Kubernetes release with binaries, checksums and signatures for every platform
+func kubernetesStyleAssets() []string {
+	binaries := []string{"kubectl", "kubelet", "kubeadm", "kube-apiserver", "kube-proxy", "kube-scheduler"}
+	platforms := []string{"linux-amd64", "linux-arm64", "linux-arm", "linux-386", "linux-ppc64le", "linux-s390x",
+		"darwin-amd64", "darwin-arm64", "windows-amd64", "windows-386", "windows-arm64"}
+	var assets []string
+	for _, binary := range binaries {
+		for _, platform := range platforms {
+			name := fmt.Sprintf("%s-v1.30.0-%s.tar.gz", binary, platform)
+			assets = append(assets, name, name+".sha256", name+".sig", name+".pem")
+		}
+	}
+	return assets
+}
+
+func TestContainsWord(t *testing.T) {
+	testCases := []struct {
+		s, word string
+		want    bool
+	}{
+		{"tool-windows-amd64.zip", "windows", true},
+		{"windowsill-linux-amd64.tar.gz", "windows", false},
+		{"tool-darwin-amd64", "win", false},
+		{"tool_win64.zip", "win", true},
+		{"jq-linux64", "linux", true},
+		{"tool-linux-arm64", "arm", true},
+		{"tool-linux-armv7", "arm", false},
+		{"armadillo-linux-amd64", "arm", false},
+		{"darwin", "darwin", true},
+		{"ünïcode-linux-x86_64", "linux", true},
+		{"outilélinux-amd64", "linux", false}, // "é" is a letter even though it is multi-byte
+		{"tool-linux·amd64", "amd64", true},   // "·" is punctuation
+		{"tool", "", false},
+	}
+
+	for _, tc := range testCases {
+		if got := containsWord(tc.s, tc.word); got != tc.want {
+			t.Errorf("containsWord(%q, %q) = %v, want %v", tc.s, tc.word, got, tc.want)
+		}
+	}
+}
+
+func TestAssetMatcher_PathologicalNames(t *testing.T) {
+	testCases := []struct {
+		name     string
+		os, arch string
+		project  string
+		assets   []string
+		expected string
+	}{
+		{"project name containing an OS", "linux", "amd64", "windowsill",
+			[]string{"windowsill-darwin-amd64.tar.gz", "windowsill-linux-amd64.tar.gz"}, "windowsill-linux-amd64.tar.gz"},
+		{"project name containing an OS on windows", "windows", "amd64", "windowsill",
+			[]string{"windowsill-linux-amd64.tar.gz", "windowsill-windows-amd64.zip"}, "windowsill-windows-amd64.zip"},
+		{"project name containing an architecture", "linux", "arm", "armada",
+			[]string{"armada-linux-amd64.tar.gz", "armada-linux-armv7.tar.gz"}, "armada-linux-armv7.tar.gz"},
+		{"capitalized OS", "darwin", "arm64", "",
+			[]string{"Tool-Linux-arm64.tar.gz", "Tool-DARWIN-arm64.tar.gz"}, "Tool-DARWIN-arm64.tar.gz"},
+		{"non-ASCII project name", "linux", "amd64", "größe",
+			[]string{"größe-darwin-amd64.tar.gz", "größe-linux-amd64.tar.gz"}, "größe-linux-amd64.tar.gz"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := DefaultAssetMatchingConfig()
+			config.ProjectName = tc.project
+			matcher := NewAssetMatcher(config)
+			matcher.os, matcher.arch = tc.os, tc.arch
+
+			match, err := matcher.FindBestMatch(tc.assets)
+			if err != nil || match != tc.expected {
+				t.Errorf("Expected %q, got %q (err: %v)", tc.expected, match, err)
+			}
+		})
+	}
+}
+
+func TestAssetMatcher_CaseSensitive(t *testing.T) {
+	config := DefaultAssetMatchingConfig()
+	config.CaseSensitive = true
+	config.OSAliases = map[string][]string{"linux": {"Linux"}}
+	config.ExcludePatterns = []string{`-DEBUG`}
+	matcher := NewAssetMatcher(config)
+	matcher.os, matcher.arch = "linux", "amd64"
+
+	match, err := matcher.FindBestMatch([]string{"tool-linux-amd64-debug.tar.gz", "tool-Linux-amd64-DEBUG.tar.gz", "tool-Linux-amd64.tar.gz"})
+	if err != nil || match != "tool-Linux-amd64.tar.gz" {
+		t.Errorf("Expected tool-Linux-amd64.tar.gz, got %q (err: %v)", match, err)
+	}
+	if excluded := matcher.excludedBy("tool-linux-amd64-debug.tar.gz"); excluded != "" {
+		t.Errorf("Expected case-sensitive exclude pattern to ignore lowercase names, got %q", excluded)
+	}
+}
+
+func TestAssetMatcher_AliasMerging(t *testing.T) {
+	config := AssetMatchingConfig{
+		Strategy:                   FlexibleStrategy,
+		OSAliases:                  map[string][]string{"darwin": {"macos"}},
+		ExtraOSAliases:             map[string][]string{"solaris": {"sunos"}},
+		ExtraArchitectureAliases:   map[string][]string{"loong64": {"loongarch64"}},
+		RemovedArchitectureAliases: map[string][]string{"amd64": {"x64"}},
+	}
+	matcher := NewAssetMatcher(config)
+
+	testCases := []struct {
+		name     string
+		aliases  []string
+		expected []string
+	}{
+		{"defaults kept for unconfigured keys", matcher.getOSAliases("linux"), []string{"linux", "Linux"}},
+		{"configured key replaces defaults", matcher.getOSAliases("darwin"), []string{"macos"}},
+		{"extra aliases extend unknown OS", matcher.getOSAliases("solaris"), []string{"solaris", "sunos"}},
+		{"extra aliases extend unknown arch", matcher.getArchAliases("loong64"), []string{"loong64", "loongarch64"}},
+		{"removed aliases dropped", matcher.getArchAliases("amd64"), []string{"amd64", "x86_64"}},
+		{"arm64 defaults", matcher.getArchAliases("arm64"), []string{"arm64", "aarch64"}},
+	}
+	for _, tc := range testCases {
+		if fmt.Sprint(tc.aliases) != fmt.Sprint(tc.expected) {
+			t.Errorf("%s: got %v, want %v", tc.name, tc.aliases, tc.expected)
+		}
+	}
+
+	matcher.os, matcher.arch = "solaris", "amd64"
+	match, err := matcher.FindBestMatch([]string{"tool-linux-amd64.tar.gz", "tool-sunos-amd64.tar.gz"})
+	if err != nil || match != "tool-sunos-amd64.tar.gz" {
+		t.Errorf("Expected the sunos asset, got %q (err: %v)", match, err)
+	}
+}
+
+func TestAssetMatcher_PrecompiledPatterns(t *testing.T) {
+	config := DefaultAssetMatchingConfig()
+	config.Strategy = CustomStrategy
+	config.CustomPatterns = []string{`([invalid`, `^tool-{OS}-{ARCH}$`}
+	matcher := NewAssetMatcher(config)
+	if matcher.patterns == nil || len(matcher.patterns.custom) != 1 {
+		t.Fatalf("Expected NewAssetMatcher to precompile the valid custom pattern, got %+v", matcher.patterns)
+	}
+
+	// Platform-dependent patterns follow changes to the matcher's platform
+	matcher.os, matcher.arch = "linux", "arm64"
+	match, err := matcher.FindBestMatch([]string{"tool-linux-amd64", "tool-linux-arm64"})
+	if err != nil || match != "tool-linux-arm64" {
+		t.Errorf("Expected tool-linux-arm64, got %q (err: %v)", match, err)
+	}
+}
+
+func BenchmarkAssetMatcher_ManyAssets(b *testing.B) {
+	assetNames := kubernetesStyleAssets()
+
+	config := DefaultAssetMatchingConfig()
+	config.ProjectName = "kubectl"
+	config.ExcludePatterns = append(config.ExcludePatterns, `\.sig$`, `\.pem$`)
+	config.PriorityPatterns = []string{`^kubectl-`}
+	matcher := NewAssetMatcher(config)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		matcher.FindBestMatch(assetNames)
+	}
+}
+
+func BenchmarkAssetMatcher_ManyAssetsCustomPatterns(b *testing.B) {
+	assetNames := kubernetesStyleAssets()
+
+	config := DefaultAssetMatchingConfig()
+	config.Strategy = CustomStrategy
+	config.CustomPatterns = []string{`^kube-proxy-.*-{OS}-{ARCH}\.zip$`, `^kubectl-.*-{OS}-{ARCH}\.tar\.gz$`}
+	matcher := NewAssetMatcher(config)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		matcher.FindBestMatch(assetNames)
+	}
+}
+
+func TestAssetMatcher_AppImagePreference(t *testing.T) {
+	assetNames := []string{
+		"app-1.0.0-x86_64.AppImage",
+		"app-1.0.0-x86_64.AppImage.zsync",
+		"app-1.0.0-linux-amd64.tar.gz",
+		"app-1.0.0-darwin-amd64.tar.gz",
+	}
+
+	testCases := []struct {
+		name       string
+		os         string
+		preference string
+		assets     []string
+		expected   string
+	}{
+		{"default prefers archive", "linux", "", assetNames, "app-1.0.0-linux-amd64.tar.gz"},
+		{"prefer AppImage", "linux", AppImagePrefer, assetNames, "app-1.0.0-x86_64.AppImage"},
+		{"avoid AppImage", "linux", AppImageAvoid, assetNames, "app-1.0.0-linux-amd64.tar.gz"},
+		{"avoid falls back to AppImage", "linux", AppImageAvoid, []string{"app-1.0.0-x86_64.AppImage", "app-1.0.0-x86_64.AppImage.zsync"}, "app-1.0.0-x86_64.AppImage"},
+		{"AppImage ignored off Linux", "darwin", AppImagePrefer, assetNames, "app-1.0.0-darwin-amd64.tar.gz"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := DefaultAssetMatchingConfig()
+			config.Strategy = FlexibleStrategy
+			config.AppImagePreference = tc.preference
+
+			matcher := NewAssetMatcher(config)
+			matcher.arch = "amd64"
+			matcher.os = tc.os
+
+			bestMatch, err := matcher.FindBestMatch(tc.assets)
+			if err != nil {
+				t.Fatalf("Expected to find a match, got error: %v", err)
+			}
+			if bestMatch != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, bestMatch)
+			}
+		})
+	}
+}
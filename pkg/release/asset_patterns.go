@@ -0,0 +1,98 @@
+package release
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// namedPattern is a compiled pattern together with the configured source it was built from
+type namedPattern struct {
+	source string
+	regex  *regexp.Regexp
+}
+
+// assetPatterns holds the matcher's compiled regular expressions, so that matching releases with
+// hundreds of assets does not recompile them for every asset
+type assetPatterns struct {
+	exclude  []namedPattern   // ExcludePatterns, matched against lowercase asset names unless CaseSensitive
+	priority []*regexp.Regexp // PriorityPatterns, matched against lowercase asset names unless CaseSensitive
+
+	// Patterns expanded with the platform aliases, valid while os and arch are unchanged
+	os, arch string
+	common   []*regexp.Regexp // {project}-*-{arch}, {os}*{arch} and {arch}*{os} naming patterns
+	custom   []namedPattern   // CustomPatterns with {OS}, {ARCH} and {PROJECT} expanded
+}
+
+// compilePatterns compiles the configured patterns for the matcher's platform. Invalid patterns
+// are skipped, as they never match.
+func (am *AssetMatcher) compilePatterns() {
+	am.patterns = &assetPatterns{
+		exclude: compileNamedPatterns(am.config.ExcludePatterns, am.foldCase),
+	}
+	for _, pattern := range am.config.PriorityPatterns {
+		if regex, err := regexp.Compile(am.foldCase(pattern)); err == nil {
+			am.patterns.priority = append(am.patterns.priority, regex)
+		}
+	}
+	am.compilePlatformPatterns()
+}
+
+// compilePlatformPatterns compiles the patterns that depend on the platform aliases
+func (am *AssetMatcher) compilePlatformPatterns() {
+	osAliases := am.getOSAliases(am.os)
+	archAliases := am.getArchAliases(am.arch)
+
+	var common []string
+	if am.config.ProjectName != "" {
+		for _, archAlias := range archAliases {
+			common = append(common, fmt.Sprintf("%s-.*-%s", strings.ToLower(am.config.ProjectName), strings.ToLower(archAlias)))
+		}
+	}
+	for _, osAlias := range osAliases {
+		for _, archAlias := range archAliases {
+			common = append(common,
+				fmt.Sprintf("%s.*%s", strings.ToLower(osAlias), strings.ToLower(archAlias)),
+				fmt.Sprintf("%s.*%s", strings.ToLower(archAlias), strings.ToLower(osAlias)))
+		}
+	}
+
+	am.patterns.os, am.patterns.arch = am.os, am.arch
+	am.patterns.common = compilePatterns(common)
+	am.patterns.custom = compileNamedPatterns(am.config.CustomPatterns, func(pattern string) string {
+		return am.expandPattern(pattern, osAliases, archAliases)
+	})
+}
+
+// compiledPatterns returns the compiled patterns, recompiling the platform patterns if the
+// matcher's platform changed since they were built
+func (am *AssetMatcher) compiledPatterns() *assetPatterns {
+	if am.patterns == nil {
+		am.compilePatterns()
+	} else if am.patterns.os != am.os || am.patterns.arch != am.arch {
+		am.compilePlatformPatterns()
+	}
+	return am.patterns
+}
+
+// compileNamedPatterns compiles the transformed sources, skipping invalid patterns
+func compileNamedPatterns(sources []string, transform func(string) string) []namedPattern {
+	var compiled []namedPattern
+	for _, source := range sources {
+		if regex, err := regexp.Compile(transform(source)); err == nil {
+			compiled = append(compiled, namedPattern{source: source, regex: regex})
+		}
+	}
+	return compiled
+}
+
+// compilePatterns compiles the sources, skipping invalid patterns
+func compilePatterns(sources []string) []*regexp.Regexp {
+	var compiled []*regexp.Regexp
+	for _, source := range sources {
+		if regex, err := regexp.Compile(source); err == nil {
+			compiled = append(compiled, regex)
+		}
+	}
+	return compiled
+}
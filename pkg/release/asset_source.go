@@ -0,0 +1,229 @@
+package release
+
+import (
+	"fmt"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// AssetInfo describes a single downloadable asset of a release, independent of the provider
+type AssetInfo struct {
+	Name        string `json:"name"`                // File name of the asset
+	URL         string `json:"url"`                 // Direct download URL (browser_download_url / direct_asset_url)
+	APIURL      string `json:"api_url"`             // Provider API URL for authenticated downloads (if available)
+	Size        int64  `json:"size"`                // Size in bytes (0 if unknown)
+	ContentType string `json:"content_type"`        // MIME type reported by the provider (if available)
+	LinkType    string `json:"link_type,omitempty"` // GitLab release link type: "package", "image", "runbook" or "other"
+	Digest      string `json:"digest,omitempty"`    // Content digest reported by the provider, e.g. "sha256:<hex>" (GitHub only)
+	Label       string `json:"label,omitempty"`     // Display label of the asset (GitHub only), e.g. "Linux x86_64 binary"
+}
+
+// ReleaseInfo is the provider-agnostic description of a release and its assets
+type ReleaseInfo struct {
+	Version     string      `json:"version"`       // Tag name of the release, or the version derived from it (see Tag)
+	Tag         string      `json:"tag,omitempty"` // Tag name when Version was derived from it by ReleaseSelection.TagPrefix or TagPattern, e.g. "toolA/v1.2.3"
+	Name        string      `json:"name"`          // Human-readable release title
+	Description string      `json:"description"`   // Release notes / changelog (usually Markdown)
+	PublishedAt time.Time   `json:"published_at"`  // When the release was published (zero if unknown)
+	Prerelease  bool        `json:"prerelease"`    // Marked as a prerelease by the provider (GitHub only)
+	Assets      []AssetInfo `json:"assets"`        // Assets attached to the release
+}
+
+// ReleaseResponse is implemented by the API response types of the providers. Responses are
+// normalized into a ReleaseInfo as soon as they are decoded; asset selection, version checks and
+// installation only work on the shared model, so a new provider only needs a response type and
+// an AssetSource.
+type ReleaseResponse interface {
+	ToReleaseInfo() *ReleaseInfo // Converts the response into the provider-agnostic release model
+}
+
+var (
+	_ ReleaseResponse = (*GithubReleaseResponse)(nil)
+	_ ReleaseResponse = (*GitlabReleaseResponse)(nil)
+)
+
+// AssetSource abstracts the provider interaction behind GitHub and GitLab releases.
+// Implementations return the release metadata and assets; asset selection, download
+// and installation stay in the release types. Inject a custom AssetSource to use fakes
+// in unit tests or to add new providers.
+type AssetSource interface {
+	LatestRelease() (*ReleaseInfo, error) // Returns the latest release with its assets
+}
+
+// ReleaseLister is implemented by asset sources that can list all releases.
+// It is required for selecting releases with a VersionConstraint or a ReleaseSelection policy.
+type ReleaseLister interface {
+	Releases() ([]ReleaseInfo, error) // Returns the available releases with their assets
+}
+
+// ReleaseFetcher is implemented by asset sources that can fetch a release by its tag.
+// Sources without it are searched with ReleaseLister instead.
+type ReleaseFetcher interface {
+	Release(version string) (*ReleaseInfo, error) // Returns the release tagged version with its assets
+}
+
+// releaseByVersion returns the release tagged version. Listed releases also match if their tags
+// differ from version only by a "v" prefix.
+func releaseByVersion(source AssetSource, version string) (*ReleaseInfo, error) {
+	if version == "" {
+		return nil, fmt.Errorf("version cannot be empty")
+	}
+	if fetcher, ok := source.(ReleaseFetcher); ok {
+		return fetcher.Release(version)
+	}
+
+	lister, ok := source.(ReleaseLister)
+	if !ok {
+		return nil, fmt.Errorf("asset source cannot fetch or list releases, required for release %s", version)
+	}
+	releases, err := lister.Releases()
+	if err != nil {
+		return nil, err
+	}
+	for i := range releases {
+		if sameVersion(releases[i].Version, version) {
+			return &releases[i], nil
+		}
+	}
+	return nil, noMatchErrorf(ErrNoMatchingRelease, "release %s not found", version)
+}
+
+// sameVersion reports whether two tags are equal, ignoring a "v" prefix
+func sameVersion(a, b string) bool {
+	return strings.TrimPrefix(a, "v") == strings.TrimPrefix(b, "v")
+}
+
+// describeRelease names the release tagged version, or the latest release if version is empty,
+// for use in error messages
+func describeRelease(version string) string {
+	if version == "" {
+		return "latest release"
+	}
+	return "release " + version
+}
+
+// TagName returns the tag of the release
+func (r *ReleaseInfo) TagName() string {
+	if r.Tag != "" {
+		return r.Tag
+	}
+	return r.Version
+}
+
+// ReleaseNotes returns the release notes with surrounding whitespace removed
+func (r *ReleaseInfo) ReleaseNotes() string {
+	return strings.TrimSpace(r.Description)
+}
+
+// AssetNames returns the names of all assets in the release
+func (r *ReleaseInfo) AssetNames() []string {
+	names := make([]string, len(r.Assets))
+	for i, asset := range r.Assets {
+		names[i] = asset.Name
+	}
+	return names
+}
+
+// FindAsset returns the asset with the given name
+func (r *ReleaseInfo) FindAsset(name string) (AssetInfo, bool) {
+	for _, asset := range r.Assets {
+		if asset.Name == name {
+			return asset, true
+		}
+	}
+	return AssetInfo{}, false
+}
+
+// SelectAsset picks the asset matching AssetLabelTemplates or AssetNameTemplates, otherwise the
+// best asset for the current platform using the asset matcher, falling back to legacy
+// {OS}_{ARCH} matching for backward compatibility
+func (r *ReleaseInfo) SelectAsset(config AssetMatchingConfig) (AssetInfo, error) {
+	asset, _, err := r.selectAsset(config)
+	return asset, err
+}
+
+// selectAsset picks the best asset and also returns the matcher's warnings
+func (r *ReleaseInfo) selectAsset(config AssetMatchingConfig) (AssetInfo, []string, error) {
+	candidates := r.filterLinkTypes(config.LinkTypes)
+	if len(config.AssetLabelTemplates) > 0 || len(config.AssetNameTemplates) > 0 {
+		asset, ok, err := candidates.matchAssetTemplates(config)
+		if err != nil || ok {
+			return asset, nil, err
+		}
+	}
+
+	matcher := NewAssetMatcher(config)
+	bestMatch, err := matcher.FindBestMatch(candidates.AssetNames())
+	if err != nil {
+		bestMatch = legacyMatchAssetName(candidates.AssetNames())
+	}
+
+	if asset, ok := candidates.FindAsset(bestMatch); ok && bestMatch != "" {
+		return asset, matcher.Warnings(), nil
+	}
+	return AssetInfo{}, nil, noMatchErrorf(ErrNoMatchingAsset, "no suitable asset found for current platform (%s/%s) in release %s",
+		runtime.GOOS, runtime.GOARCH, r.Version)
+}
+
+// filterLinkTypes returns the release restricted to assets whose link type is listed.
+// Assets without a link type (e.g. GitHub assets) are always kept; no types keeps every asset.
+func (r *ReleaseInfo) filterLinkTypes(linkTypes []string) *ReleaseInfo {
+	if len(linkTypes) == 0 {
+		return r
+	}
+
+	filtered := *r
+	filtered.Assets = nil
+	for _, asset := range r.Assets {
+		if asset.LinkType == "" {
+			filtered.Assets = append(filtered.Assets, asset)
+			continue
+		}
+		for _, linkType := range linkTypes {
+			if strings.EqualFold(asset.LinkType, linkType) {
+				filtered.Assets = append(filtered.Assets, asset)
+				break
+			}
+		}
+	}
+	return &filtered
+}
+
+// legacyMatchAssetName provides backward compatibility with the original {OS}_{ARCH} matching logic
+func legacyMatchAssetName(assetNames []string) string {
+	runtimeOS := runtime.GOOS
+	arch := MapArch(runtime.GOARCH)
+
+	title := cases.Title(language.AmericanEnglish)
+	primarySearchKey := fmt.Sprintf("%s_%s", title.String(runtimeOS), arch)
+
+	// Try exact match first
+	for _, name := range assetNames {
+		if strings.Contains(name, primarySearchKey) {
+			return name
+		}
+	}
+
+	// Try with architecture variants for better compatibility
+	for _, archVariant := range GetArchVariants(runtime.GOARCH) {
+		searchKey := fmt.Sprintf("%s_%s", title.String(runtimeOS), archVariant)
+		for _, name := range assetNames {
+			if strings.Contains(name, searchKey) {
+				return name
+			}
+		}
+	}
+
+	// Try case-insensitive matching as fallback
+	fallbackSearchKey := fmt.Sprintf("%s_%s", strings.ToLower(runtimeOS), strings.ToLower(arch))
+	for _, name := range assetNames {
+		if strings.Contains(strings.ToLower(name), fallbackSearchKey) {
+			return name
+		}
+	}
+
+	return ""
+}
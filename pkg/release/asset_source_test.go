@@ -0,0 +1,459 @@
+package release
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeAssetSource is an in-memory AssetSource used to test release flows without HTTP
+type fakeAssetSource struct {
+	release *ReleaseInfo
+	err     error
+	calls   int
+}
+
+func (f *fakeAssetSource) LatestRelease() (*ReleaseInfo, error) {
+	f.calls++
+	return f.release, f.err
+}
+
+func fakeLinuxRelease() *ReleaseInfo {
+	return &ReleaseInfo{
+		Version: "v3.1.0",
+		Assets: []AssetInfo{
+			{Name: "myapp-Darwin_arm64.tar.gz", URL: "https://example.com/darwin", APIURL: "https://api.example.com/1"},
+			{Name: "myapp-Linux_x86_64.tar.gz", URL: "https://example.com/linux", APIURL: "https://api.example.com/2"},
+			{Name: "checksums.txt.sha256", URL: "https://example.com/checksums"},
+		},
+	}
+}
+
+func TestGithubRelease_WithAssetSource(t *testing.T) {
+	source := &fakeAssetSource{release: fakeLinuxRelease()}
+	release := NewGithubRelease("owner/repo", fileUtils.FileConfig{ProjectName: "myapp"})
+	release.Source = source
+
+	if err := release.GetLatestRelease(); err != nil {
+		t.Fatalf("GetLatestRelease failed: %v", err)
+	}
+	if source.calls != 1 {
+		t.Errorf("Expected the asset source to be called once, got %d", source.calls)
+	}
+	if release.Version != "v3.1.0" {
+		t.Errorf("Expected version v3.1.0, got %s", release.Version)
+	}
+	if release.ReleaseLink != "https://example.com/linux" {
+		t.Errorf("Expected linux asset link, got %s", release.ReleaseLink)
+	}
+	if release.APILink != "https://api.example.com/2" {
+		t.Errorf("Expected linux API link, got %s", release.APILink)
+	}
+}
+
+func TestGitLabRelease_WithAssetSource(t *testing.T) {
+	source := &fakeAssetSource{release: fakeLinuxRelease()}
+	release := NewGitlabRelease("123", fileUtils.FileConfig{ProjectName: "myapp"})
+	release.Source = source
+
+	if err := release.GetLatestRelease(); err != nil {
+		t.Fatalf("GetLatestRelease failed: %v", err)
+	}
+	if release.ReleaseLink != "https://example.com/linux" {
+		t.Errorf("Expected linux asset link, got %s", release.ReleaseLink)
+	}
+}
+
+func TestAssetSource_ErrorPropagation(t *testing.T) {
+	sourceErr := errors.New("provider unavailable")
+	release := NewGithubRelease("owner/repo", fileUtils.FileConfig{})
+	release.Source = &fakeAssetSource{err: sourceErr}
+
+	if err := release.GetLatestRelease(); !errors.Is(err, sourceErr) {
+		t.Errorf("Expected source error to propagate, got %v", err)
+	}
+}
+
+func TestReleaseInfo_SelectAsset_NoMatch(t *testing.T) {
+	info := &ReleaseInfo{
+		Version: "v1.0.0",
+		Assets:  []AssetInfo{{Name: "myapp-Windows_x86_64.zip", URL: "https://example.com/windows"}},
+	}
+	if _, err := info.SelectAsset(AssetMatchingConfig{}); err == nil {
+		t.Error("Expected error when no asset matches the current platform")
+	}
+}
+
+func TestGithubRelease_ReleaseNotes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"tag_name": "v1.2.0",
+			"name": "Release 1.2.0",
+			"body": "\n## What's new\n- Faster downloads\n",
+			"published_at": "2024-05-01T12:00:00Z",
+			"assets": [{"name": "myapp-Linux_x86_64.tar.gz", "browser_download_url": "https://example.com/a"}]
+		}`))
+	}))
+	defer server.Close()
+
+	release := NewGithubRelease("owner/repo", fileUtils.FileConfig{ProjectName: "myapp"})
+	release.BaseURL = server.URL
+
+	if _, err := release.ReleaseNotes(); err == nil {
+		t.Error("Expected error before GetLatestRelease is called")
+	}
+	if err := release.GetLatestRelease(); err != nil {
+		t.Fatalf("GetLatestRelease failed: %v", err)
+	}
+
+	notes, err := release.ReleaseNotes()
+	if err != nil {
+		t.Fatalf("ReleaseNotes failed: %v", err)
+	}
+	if notes != "## What's new\n- Faster downloads" {
+		t.Errorf("Unexpected release notes: %q", notes)
+	}
+	if release.Info.Name != "Release 1.2.0" {
+		t.Errorf("Expected release name, got %q", release.Info.Name)
+	}
+	if !release.Info.PublishedAt.Equal(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected published date: %v", release.Info.PublishedAt)
+	}
+}
+
+func TestGitLabRelease_ReleaseNotes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{
+			"tag_name": "v2.0.0",
+			"name": "Big release",
+			"description": "Breaking changes ahead",
+			"released_at": "2024-06-01T00:00:00Z",
+			"assets": {"links": [{"name": "myapp-Linux_x86_64.tar.gz", "direct_asset_url": "https://example.com/a"}]}
+		}]`))
+	}))
+	defer server.Close()
+
+	release := NewGitlabRelease("123", fileUtils.FileConfig{ProjectName: "myapp"})
+	release.GitLabConfig.BaseURL = server.URL
+	if err := release.GetLatestRelease(); err != nil {
+		t.Fatalf("GetLatestRelease failed: %v", err)
+	}
+
+	notes, err := release.ReleaseNotes()
+	if err != nil || notes != "Breaking changes ahead" {
+		t.Errorf("Expected GitLab description as release notes, got %q (err: %v)", notes, err)
+	}
+	if release.Info.Name != "Big release" || release.Info.PublishedAt.IsZero() {
+		t.Errorf("Expected name and release date, got %+v", release.Info)
+	}
+}
+
+func TestGithubRelease_Assets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"tag_name": "v1.2.0",
+			"assets": [
+				{"name": "myapp-Plan9_mips.tar.gz", "browser_download_url": "https://example.com/a", "size": 1024, "content_type": "application/gzip"},
+				{"name": "myapp.sha256", "browser_download_url": "https://example.com/b", "size": 64, "content_type": "text/plain"}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	release := NewGithubRelease("owner/repo", fileUtils.FileConfig{ProjectName: "myapp"})
+	release.BaseURL = server.URL
+	if assets := release.Assets(); assets != nil {
+		t.Errorf("Expected no assets before GetLatestRelease, got %+v", assets)
+	}
+
+	// The assets are available for manual selection even if none matches the platform
+	if err := release.GetLatestRelease(); err == nil {
+		t.Fatal("Expected no asset to match the current platform")
+	}
+	assets := release.Assets()
+	if len(assets) != 2 {
+		t.Fatalf("Expected 2 assets, got %d", len(assets))
+	}
+	want := AssetInfo{Name: "myapp-Plan9_mips.tar.gz", URL: "https://example.com/a", Size: 1024, ContentType: "application/gzip"}
+	if assets[0] != want {
+		t.Errorf("Expected %+v, got %+v", want, assets[0])
+	}
+
+	assets[0].Name = "modified"
+	if release.Assets()[0].Name != "myapp-Plan9_mips.tar.gz" {
+		t.Error("Expected Assets to return a copy")
+	}
+}
+
+func TestGithubRelease_StreamExtraction(t *testing.T) {
+	tempDir := t.TempDir()
+	archivePath := filepath.Join(tempDir, "fixture.tar.gz")
+	writeTestTarGz(t, archivePath, map[string]string{"myapp": "#!/bin/sh\necho streamed\n"})
+	archive, _ := os.ReadFile(archivePath)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive)
+	}))
+	defer server.Close()
+
+	fileConfig := fileUtils.FileConfig{
+		BaseBinaryDirectory:    tempDir,
+		VersionedDirectoryName: "versions",
+		SourceBinaryName:       "myapp",
+		BinaryName:             "myapp",
+		ProjectName:            "myapp",
+		CreateLocalSymlink:     true,
+		StreamExtraction:       true,
+		SourceArchivePath:      filepath.Join(tempDir, "download.tar.gz"),
+	}
+	release := NewGithubRelease("owner/repo", fileConfig)
+	release.Source = &fakeAssetSource{release: &ReleaseInfo{
+		Version: "v1.0.0",
+		Assets:  []AssetInfo{{Name: "myapp-Linux_x86_64.tar.gz", URL: server.URL + "/myapp-Linux_x86_64.tar.gz"}},
+	}}
+
+	if err := release.DownloadLatestRelease(); err != nil {
+		t.Fatalf("DownloadLatestRelease failed: %v", err)
+	}
+	if fileUtils.FileExists(fileConfig.SourceArchivePath) {
+		t.Error("Expected no archive on disk with streaming extraction")
+	}
+	if err := release.InstallLatestRelease(); err != nil {
+		t.Fatalf("InstallLatestRelease failed: %v", err)
+	}
+	if !fileUtils.FileExists(fileUtils.GetVersionedBinaryPath(fileConfig, "v1.0.0")) {
+		t.Error("Expected streamed binary to be installed")
+	}
+}
+
+func TestGithubRelease_StagingDirectory(t *testing.T) {
+	tempDir := t.TempDir()
+	archivePath := filepath.Join(tempDir, "fixture.tar.gz")
+	writeTestTarGz(t, archivePath, map[string]string{"myapp": "#!/bin/sh\necho staged\n"})
+	archive, _ := os.ReadFile(archivePath)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive)
+	}))
+	defer server.Close()
+
+	stagingDir := filepath.Join(tempDir, "staging", "nested")
+	fileConfig := fileUtils.FileConfig{
+		BaseBinaryDirectory:    filepath.Join(tempDir, "bin"),
+		VersionedDirectoryName: "versions",
+		SourceBinaryName:       "myapp",
+		BinaryName:             "myapp",
+		ProjectName:            "myapp",
+		CreateLocalSymlink:     true,
+		StagingDirectory:       stagingDir,
+	}
+	release := NewGithubRelease("owner/repo", fileConfig)
+	release.Source = &fakeAssetSource{release: &ReleaseInfo{
+		Version: "v1.0.0",
+		Assets:  []AssetInfo{{Name: "myapp-Linux_x86_64.tar.gz", URL: server.URL + "/myapp-Linux_x86_64.tar.gz"}},
+	}}
+
+	if err := release.DownloadLatestRelease(); err != nil {
+		t.Fatalf("DownloadLatestRelease failed: %v", err)
+	}
+	if !fileUtils.FileExists(filepath.Join(stagingDir, "myapp-github-myapp-Linux_x86_64.tar.gz")) {
+		t.Error("Expected asset to be downloaded into the staging directory")
+	}
+	if err := release.InstallLatestRelease(); err != nil {
+		t.Fatalf("InstallLatestRelease failed: %v", err)
+	}
+	if !fileUtils.FileExists(fileUtils.GetVersionedBinaryPath(fileConfig, "v1.0.0")) {
+		t.Error("Expected binary to be installed from the staging directory")
+	}
+}
+
+func TestGithubRelease_InsufficientDiskSpace(t *testing.T) {
+	tempDir := t.TempDir()
+	fileConfig := fileUtils.FileConfig{
+		BaseBinaryDirectory: filepath.Join(tempDir, "bin"),
+		BinaryName:          "myapp",
+		ProjectName:         "myapp",
+		StagingDirectory:    filepath.Join(tempDir, "staging"),
+	}
+	release := NewGithubRelease("owner/repo", fileConfig)
+	release.Source = &fakeAssetSource{release: &ReleaseInfo{
+		Version: "v1.0.0",
+		Assets:  []AssetInfo{{Name: "myapp-Linux_x86_64.tar.gz", URL: "http://127.0.0.1:1/unreachable", Size: 1 << 62}},
+	}}
+
+	err := release.DownloadLatestRelease()
+	if !errors.Is(err, fileUtils.ErrInsufficientSpace) {
+		t.Fatalf("Expected ErrInsufficientSpace before downloading, got %v", err)
+	}
+}
+
+func TestReleaseInfo_SelectAsset_LinkTypes(t *testing.T) {
+	info := &ReleaseInfo{
+		Version: "v1.0.0",
+		Assets: []AssetInfo{
+			{Name: "myapp-Linux_x86_64.tar.gz.sbom", URL: "https://example.com/sbom", LinkType: "other"},
+			{Name: "myapp-Linux_x86_64.tar.gz", URL: "https://example.com/linux", LinkType: "package"},
+			{Name: "checksums.txt", URL: "https://example.com/checksums", LinkType: "other"},
+		},
+	}
+
+	asset, err := info.SelectAsset(AssetMatchingConfig{LinkTypes: []string{"package"}})
+	if err != nil {
+		t.Fatalf("SelectAsset() error = %v", err)
+	}
+	if asset.URL != "https://example.com/linux" {
+		t.Errorf("Expected package link to be selected, got %s", asset.URL)
+	}
+
+	if _, err := info.SelectAsset(AssetMatchingConfig{LinkTypes: []string{"image"}}); err == nil {
+		t.Error("Expected error when no link has an allowed type")
+	}
+
+	// Assets without a link type are never filtered out
+	untyped := fakeLinuxRelease()
+	if _, err := untyped.SelectAsset(AssetMatchingConfig{LinkTypes: []string{"package"}}); err != nil {
+		t.Errorf("Expected untyped assets to remain selectable, got %v", err)
+	}
+}
+
+// fakeListingSource is an in-memory AssetSource that can also list releases
+type fakeListingSource struct {
+	releases []ReleaseInfo
+}
+
+func (f *fakeListingSource) LatestRelease() (*ReleaseInfo, error) {
+	return &f.releases[0], nil
+}
+
+func (f *fakeListingSource) Releases() ([]ReleaseInfo, error) {
+	return f.releases, nil
+}
+
+func TestGithubRelease_InstallRelease(t *testing.T) {
+	tempDir := t.TempDir()
+	archives := map[string][]byte{}
+	for _, version := range []string{"v1.0.0", "v2.0.0"} {
+		archivePath := filepath.Join(tempDir, version+".tar.gz")
+		writeTestTarGz(t, archivePath, map[string]string{"myapp": "#!/bin/sh\necho " + version + "\n"})
+		archives["/"+version+"/myapp-Linux_x86_64.tar.gz"], _ = os.ReadFile(archivePath)
+	}
+	downloads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downloads++
+		w.Write(archives[r.URL.Path])
+	}))
+	defer server.Close()
+
+	fileConfig := fileUtils.FileConfig{
+		BaseBinaryDirectory:    filepath.Join(tempDir, "bin"),
+		VersionedDirectoryName: "versions",
+		SourceBinaryName:       "myapp",
+		BinaryName:             "myapp",
+		ProjectName:            "myapp",
+		CreateLocalSymlink:     true,
+		StagingDirectory:       filepath.Join(tempDir, "staging"),
+	}
+	var releases []ReleaseInfo
+	for _, version := range []string{"v2.0.0", "v1.0.0"} {
+		releases = append(releases, ReleaseInfo{
+			Version: version,
+			Assets:  []AssetInfo{{Name: "myapp-Linux_x86_64.tar.gz", URL: server.URL + "/" + version + "/myapp-Linux_x86_64.tar.gz"}},
+		})
+	}
+	release := NewGithubRelease("owner/repo", fileConfig)
+	release.Source = &fakeListingSource{releases: releases}
+
+	var _ VersionedRelease = release
+	if err := release.DownloadRelease("1.0.0"); err != nil {
+		t.Fatalf("DownloadRelease failed: %v", err)
+	}
+	if err := release.InstallRelease("1.0.0"); err != nil {
+		t.Fatalf("InstallRelease failed: %v", err)
+	}
+	if downloads != 1 {
+		t.Errorf("Expected InstallRelease to reuse the download, got %d downloads", downloads)
+	}
+	if release.Version != "v1.0.0" {
+		t.Errorf("Expected version v1.0.0, got %s", release.Version)
+	}
+	if !fileUtils.FileExists(fileUtils.GetVersionedBinaryPath(fileConfig, "v1.0.0")) {
+		t.Error("Expected the pinned version to be installed")
+	}
+	if fileUtils.FileExists(fileUtils.GetVersionedBinaryPath(fileConfig, "v2.0.0")) {
+		t.Error("Expected the latest version not to be installed")
+	}
+
+	if err := release.InstallRelease("v3.0.0"); err == nil {
+		t.Error("Expected an error for a missing release")
+	}
+}
+
+func TestGithubRelease_StreamExtractionVerifiesDigest(t *testing.T) {
+	tempDir := t.TempDir()
+	archivePath := filepath.Join(tempDir, "fixture.tar.gz")
+	writeTestTarGz(t, archivePath, map[string]string{"myapp": "#!/bin/sh\necho streamed\n"})
+	archive, _ := os.ReadFile(archivePath)
+	sum := sha256.Sum256(archive)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive)
+	}))
+	defer server.Close()
+
+	for _, tc := range []struct {
+		name    string
+		digest  string
+		wantErr bool
+	}{
+		{"matching digest", "sha256:" + hex.EncodeToString(sum[:]), false},
+		{"mismatching digest", "sha256:" + strings.Repeat("0", 64), true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			fileConfig := fileUtils.FileConfig{
+				BaseBinaryDirectory:    dir,
+				VersionedDirectoryName: "versions",
+				SourceBinaryName:       "myapp",
+				BinaryName:             "myapp",
+				ProjectName:            "myapp",
+				CreateLocalSymlink:     true,
+				StreamExtraction:       true,
+				SourceArchivePath:      filepath.Join(dir, "download.tar.gz"),
+			}
+			release := NewGithubRelease("owner/repo", fileConfig)
+			release.Source = &fakeAssetSource{release: &ReleaseInfo{
+				Version: "v1.0.0",
+				Assets: []AssetInfo{{
+					Name:   "myapp-Linux_x86_64.tar.gz",
+					URL:    server.URL + "/myapp-Linux_x86_64.tar.gz",
+					Digest: tc.digest,
+				}},
+			}}
+
+			err := release.DownloadLatestRelease()
+			if tc.wantErr {
+				if !errors.Is(err, fileUtils.ErrChecksumMismatch) {
+					t.Fatalf("Expected a digest mismatch instead of streaming, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DownloadLatestRelease failed: %v", err)
+			}
+			if err := release.InstallLatestRelease(); err != nil {
+				t.Fatalf("InstallLatestRelease failed: %v", err)
+			}
+			if !fileUtils.FileExists(fileUtils.GetVersionedBinaryPath(fileConfig, "v1.0.0")) {
+				t.Error("Expected the verified binary to be installed")
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package release
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// AssetTemplateData is the data AssetNameTemplates and AssetLabelTemplates are executed with
+type AssetTemplateData struct {
+	Project string // ProjectName
+	Version string // Version without a leading "v", e.g. "1.2.3"
+	Tag     string // Tag of the release, e.g. "v1.2.3"
+	OS      string // Alias of the operating system, e.g. "linux" or "Linux"
+	Arch    string // Alias of the architecture, e.g. "amd64" or "x86_64"
+}
+
+// assetTemplateFuncs are the functions available in asset templates
+var assetTemplateFuncs = template.FuncMap{
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// parseAssetTemplate parses an asset name or label template and checks that it executes
+func parseAssetTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("asset").Funcs(assetTemplateFuncs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	if err := tmpl.Execute(new(strings.Builder), AssetTemplateData{}); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+// matchAssetTemplates returns the asset whose label matches one of AssetLabelTemplates or whose
+// name matches one of AssetNameTemplates. Each template is executed for every OS and architecture
+// alias of the current platform, in alias order, and the first template with a match wins.
+// Names are compared ignoring case unless CaseSensitive is set; labels are compared exactly.
+func (r *ReleaseInfo) matchAssetTemplates(config AssetMatchingConfig) (AssetInfo, bool, error) {
+	matcher := NewAssetMatcher(config)
+	data := AssetTemplateData{
+		Project: config.ProjectName,
+		Version: strings.TrimPrefix(r.Version, "v"),
+		Tag:     r.TagName(),
+	}
+	byLabel := func(asset AssetInfo, value string) bool { return asset.Label != "" && asset.Label == value }
+	byName := func(asset AssetInfo, value string) bool {
+		return matcher.foldCase(asset.Name) == matcher.foldCase(value)
+	}
+
+	for _, set := range []struct {
+		field     string
+		templates []string
+		matches   func(AssetInfo, string) bool
+	}{
+		{"asset_label_templates", config.AssetLabelTemplates, byLabel},
+		{"asset_name_templates", config.AssetNameTemplates, byName},
+	} {
+		for _, text := range set.templates {
+			tmpl, err := parseAssetTemplate(text)
+			if err != nil {
+				return AssetInfo{}, false, fmt.Errorf("%s: invalid template %q: %v", set.field, text, err)
+			}
+			for _, data.OS = range matcher.getOSAliases(matcher.os) {
+				for _, data.Arch = range matcher.getArchAliases(matcher.arch) {
+					var value strings.Builder
+					if err := tmpl.Execute(&value, data); err != nil {
+						return AssetInfo{}, false, fmt.Errorf("%s: template %q: %v", set.field, text, err)
+					}
+					for _, asset := range r.Assets {
+						if set.matches(asset, value.String()) {
+							return asset, true, nil
+						}
+					}
+				}
+			}
+		}
+	}
+	return AssetInfo{}, false, nil
+}
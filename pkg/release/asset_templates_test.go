@@ -0,0 +1,49 @@
+package release
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestReleaseInfo_SelectAssetByTemplate(t *testing.T) {
+	platform := runtime.GOOS + "_" + runtime.GOARCH
+	info := &ReleaseInfo{
+		Version: "v1.2.3",
+		Assets: []AssetInfo{
+			{Name: "tool_1.2.3_plan9_mips.tar.gz", Label: "plan9 mips binary"},
+			{Name: "tool-full_1.2.3_" + platform + ".tar.gz"},
+			{Name: "tool_1.2.3_" + platform + ".tar.gz"},
+			{Name: "tool.bin", Label: runtime.GOOS + " " + runtime.GOARCH + " binary"},
+		},
+	}
+
+	config := DefaultAssetMatchingConfig()
+	config.ProjectName = "tool"
+	config.AssetNameTemplates = []string{"{{.Project}}_{{.Version}}_{{.OS}}_{{.Arch}}.tar.gz"}
+	asset, err := info.SelectAsset(config)
+	if err != nil || asset.Name != "tool_1.2.3_"+platform+".tar.gz" {
+		t.Errorf("SelectAsset() by name template = %q, %v", asset.Name, err)
+	}
+
+	config.AssetNameTemplates = []string{"{{upper .Project}}_{{.Tag}}.tar.gz", "{{.Project}}_{{.Version}}_{{.OS}}_{{.Arch}}.tar.gz"}
+	if asset, err := info.SelectAsset(config); err != nil || asset.Name != "tool_1.2.3_"+platform+".tar.gz" {
+		t.Errorf("SelectAsset() with a template matching nothing first = %q, %v", asset.Name, err)
+	}
+
+	config.AssetLabelTemplates = []string{"{{.OS}} {{.Arch}} binary"}
+	if asset, err := info.SelectAsset(config); err != nil || asset.Name != "tool.bin" {
+		t.Errorf("SelectAsset() by label template = %q, %v, want tool.bin", asset.Name, err)
+	}
+
+	// Without a template match the strategy picks the asset
+	config.AssetLabelTemplates = []string{"nothing"}
+	config.AssetNameTemplates = []string{"nothing"}
+	if asset, err := info.SelectAsset(config); err != nil || asset.Name == "tool.bin" {
+		t.Errorf("SelectAsset() falling back to the strategy = %q, %v", asset.Name, err)
+	}
+
+	config.AssetNameTemplates = []string{"{{.Platform}}"}
+	if _, err := info.SelectAsset(config); err == nil {
+		t.Error("Expected an error for a template with an unknown field")
+	}
+}
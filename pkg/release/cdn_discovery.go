@@ -0,0 +1,236 @@
+package release
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// VersionDiscoveryFormat selects how a version discovery endpoint is parsed
+type VersionDiscoveryFormat string
+
+const (
+	// DiscoveryText reads the version from a plain text file such as kubectl's stable.txt
+	DiscoveryText VersionDiscoveryFormat = "text"
+	// DiscoveryJSON reads the version from a field of a JSON document
+	DiscoveryJSON VersionDiscoveryFormat = "json"
+	// DiscoveryHTML extracts versions from an HTML directory listing with a regular expression
+	DiscoveryHTML VersionDiscoveryFormat = "html"
+	// DiscoveryGCS extracts versions from a Google Cloud Storage (or S3) XML bucket listing
+	DiscoveryGCS VersionDiscoveryFormat = "gcs"
+)
+
+// maxDiscoveryResponseSize bounds how much of a discovery response is read
+const maxDiscoveryResponseSize = 10 << 20
+
+// defaultHTMLVersionPattern matches version directories in listings like releases.hashicorp.com
+const defaultHTMLVersionPattern = `href="[^"]*?/?v?(\d+\.\d+\.\d+[^"/]*)/?"`
+
+// defaultGCSVersionPattern matches versions in bucket keys and prefixes like "release/v1.28.0/"
+const defaultGCSVersionPattern = `(v?\d+\.\d+\.\d+[^/]*)/?`
+
+// VersionDiscoveryConfig describes an endpoint that reports the latest version published on a CDN.
+// When an endpoint lists several versions, the highest stable version wins.
+type VersionDiscoveryConfig struct {
+	URL               string                 `json:"url"`                          // Endpoint to query
+	Format            VersionDiscoveryFormat `json:"format"`                       // "text", "json", "html" or "gcs"
+	JSONField         string                 `json:"json_field,omitempty"`         // Dotted path to the version (e.g. "current_version" or "versions"); objects and arrays are treated as version lists
+	Pattern           string                 `json:"pattern,omitempty"`            // Regular expression whose first capture group is a version (html/gcs, optional for text)
+	IncludePrerelease bool                   `json:"include_prerelease,omitempty"` // Consider prereleases when picking the highest version
+}
+
+// builtinVersionDiscovery returns discovery settings for well-known CDNs, or nil if there are none
+func builtinVersionDiscovery(baseURL string) *VersionDiscoveryConfig {
+	switch {
+	case strings.Contains(baseURL, "dl.k8s.io"):
+		return &VersionDiscoveryConfig{URL: "https://dl.k8s.io/release/stable.txt", Format: DiscoveryText}
+	case strings.Contains(baseURL, "get.helm.sh"):
+		return &VersionDiscoveryConfig{URL: "https://get.helm.sh/helm-latest-version", Format: DiscoveryText}
+	case strings.Contains(baseURL, "releases.hashicorp.com"):
+		return &VersionDiscoveryConfig{URL: baseURL, Format: DiscoveryHTML}
+	}
+	return nil
+}
+
+// DiscoverVersion queries a discovery endpoint and returns the latest version it reports
+func (c *CDNDownloader) DiscoverVersion(discovery VersionDiscoveryConfig) (string, error) {
+	if discovery.URL == "" {
+		return "", fmt.Errorf("version discovery URL cannot be empty")
+	}
+
+	req, err := c.newRequest("GET", discovery.URL)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to query version discovery endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("version discovery endpoint %s returned status %w", discovery.URL, newHTTPStatusError(resp))
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxDiscoveryResponseSize))
+	if err != nil {
+		return "", fmt.Errorf("failed to read version discovery response: %w", err)
+	}
+
+	version, err := parseDiscoveryResponse(discovery, body)
+	if err != nil {
+		return "", fmt.Errorf("failed to discover version from %s: %w", discovery.URL, err)
+	}
+	return version, nil
+}
+
+// parseDiscoveryResponse extracts the latest version from a discovery response body
+func parseDiscoveryResponse(discovery VersionDiscoveryConfig, body []byte) (string, error) {
+	switch discovery.Format {
+	case DiscoveryText, "":
+		if discovery.Pattern != "" {
+			return highestMatchingVersion(discovery.Pattern, []string{string(body)}, discovery.IncludePrerelease)
+		}
+		for _, line := range strings.Split(string(body), "\n") {
+			if version := strings.TrimSpace(line); version != "" {
+				return version, nil
+			}
+		}
+		return "", fmt.Errorf("empty version in response")
+
+	case DiscoveryJSON:
+		var document interface{}
+		if err := json.Unmarshal(body, &document); err != nil {
+			return "", fmt.Errorf("invalid JSON: %w", err)
+		}
+		value, err := lookupJSONField(document, discovery.JSONField)
+		if err != nil {
+			return "", err
+		}
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case map[string]interface{}:
+			candidates := make([]string, 0, len(v))
+			for key := range v {
+				candidates = append(candidates, key)
+			}
+			return highestVersion(candidates, discovery.IncludePrerelease)
+		case []interface{}:
+			var candidates []string
+			for _, item := range v {
+				if s, ok := item.(string); ok {
+					candidates = append(candidates, s)
+				}
+			}
+			return highestVersion(candidates, discovery.IncludePrerelease)
+		}
+		return "", fmt.Errorf("JSON field %q is not a version", discovery.JSONField)
+
+	case DiscoveryHTML:
+		pattern := discovery.Pattern
+		if pattern == "" {
+			pattern = defaultHTMLVersionPattern
+		}
+		return highestMatchingVersion(pattern, []string{string(body)}, discovery.IncludePrerelease)
+
+	case DiscoveryGCS:
+		var listing struct {
+			Contents []struct {
+				Key string `xml:"Key"`
+			} `xml:"Contents"`
+			CommonPrefixes []struct {
+				Prefix string `xml:"Prefix"`
+			} `xml:"CommonPrefixes"`
+		}
+		if err := xml.Unmarshal(body, &listing); err != nil {
+			return "", fmt.Errorf("invalid bucket listing: %w", err)
+		}
+		var entries []string
+		for _, prefix := range listing.CommonPrefixes {
+			entries = append(entries, prefix.Prefix)
+		}
+		for _, object := range listing.Contents {
+			entries = append(entries, object.Key)
+		}
+		pattern := discovery.Pattern
+		if pattern == "" {
+			pattern = defaultGCSVersionPattern
+		}
+		return highestMatchingVersion(pattern, entries, discovery.IncludePrerelease)
+	}
+	return "", fmt.Errorf("unsupported version discovery format: %s", discovery.Format)
+}
+
+// lookupJSONField follows a dotted path of object keys and array indexes through a JSON document
+func lookupJSONField(document interface{}, path string) (interface{}, error) {
+	if path == "" {
+		return document, nil
+	}
+
+	value := document
+	for _, part := range strings.Split(path, ".") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			next, ok := v[part]
+			if !ok {
+				return nil, fmt.Errorf("JSON field %q not found", path)
+			}
+			value = next
+		case []interface{}:
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil, fmt.Errorf("JSON field %q not found", path)
+			}
+			value = v[index]
+		default:
+			return nil, fmt.Errorf("JSON field %q not found", path)
+		}
+	}
+	return value, nil
+}
+
+// highestMatchingVersion collects the first capture group of every pattern match and returns the highest version
+func highestMatchingVersion(pattern string, texts []string, includePrerelease bool) (string, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid version pattern %q: %w", pattern, err)
+	}
+	if re.NumSubexp() < 1 {
+		return "", fmt.Errorf("version pattern %q must contain a capture group", pattern)
+	}
+
+	var candidates []string
+	for _, text := range texts {
+		for _, match := range re.FindAllStringSubmatch(text, -1) {
+			candidates = append(candidates, match[1])
+		}
+	}
+	return highestVersion(candidates, includePrerelease)
+}
+
+// highestVersion returns the highest semantic version among candidates, skipping unparsable entries
+func highestVersion(candidates []string, includePrerelease bool) (string, error) {
+	best := ""
+	var bestVersion SemVersion
+	for _, candidate := range candidates {
+		version, err := ParseSemVersion(candidate)
+		if err != nil || (version.Prerelease != "" && !includePrerelease) {
+			continue
+		}
+		if best == "" || version.Compare(bestVersion) > 0 {
+			best = candidate
+			bestVersion = version
+		}
+	}
+
+	if best == "" {
+		return "", fmt.Errorf("no versions found in response")
+	}
+	return best, nil
+}
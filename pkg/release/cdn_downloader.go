@@ -1,10 +1,14 @@
 package release
 
 import (
+	"context"
 	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
 	"io"
 	"net/http"
-	"os"
+	"net/url"
+	"path"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"time"
@@ -15,17 +19,28 @@ type CDNDownloader struct {
 	BaseURL     string
 	Pattern     string
 	ArchMapping map[string]string // Custom architecture mapping for this CDN
-	HTTPClient  *http.Client
+	HTTPClient  *RetryableHTTPClient // Retries transient CDN failures (see DefaultCDNHTTPClientConfig)
+	Cache       *fileUtils.DownloadCache // Optional download cache (nil disables caching)
+	Mirrors     []string // Additional base URLs serving the same files as BaseURL
+	Ranking     *MirrorRanking // Mirror health ranking (nil uses the process-wide ranking)
+
+	VersionDiscovery *VersionDiscoveryConfig // Latest version endpoint (nil uses built-in endpoints for known CDNs)
+	RequestSigner    RequestSigner           // Signs every CDN request before it is sent (nil sends requests unchanged)
+
+	ctx context.Context // Bounds every request, see FileConfig.WithContext (nil: no bound)
 }
 
+// RequestSigner modifies an outgoing CDN request, e.g. to add the query parameters of a CloudFront
+// signed URL or an authorization header. It is called once per request, before retries, and an
+// error aborts the request.
+type RequestSigner func(req *http.Request) error
+
 // NewCDNDownloader creates a new CDN downloader with the given configuration
 func NewCDNDownloader(baseURL, pattern string) *CDNDownloader {
 	return &CDNDownloader{
 		BaseURL: baseURL,
 		Pattern: pattern,
-		HTTPClient: &http.Client{
-			Timeout: 30 * time.Minute, // Long timeout for large binaries
-		},
+		HTTPClient: NewRetryableHTTPClient(DefaultCDNHTTPClientConfig()),
 	}
 }
 
@@ -35,12 +50,40 @@ func NewCDNDownloaderWithArchMapping(baseURL, pattern string, archMapping map[st
 		BaseURL:     baseURL,
 		Pattern:     pattern,
 		ArchMapping: archMapping,
-		HTTPClient: &http.Client{
-			Timeout: 30 * time.Minute, // Long timeout for large binaries
-		},
+		HTTPClient: NewRetryableHTTPClient(DefaultCDNHTTPClientConfig()),
 	}
 }
 
+// newConfiguredCDNDownloader creates a CDN downloader from asset matching and file configuration,
+// applying custom architecture mapping, HTTP retry settings, version discovery and the download cache when configured
+func newConfiguredCDNDownloader(assetConfig AssetMatchingConfig, fileConfig fileUtils.FileConfig) *CDNDownloader {
+	var cdnDownloader *CDNDownloader
+	if assetConfig.CDNArchMapping != nil {
+		cdnDownloader = NewCDNDownloaderWithArchMapping(assetConfig.CDNBaseURL, assetConfig.CDNPattern, assetConfig.CDNArchMapping)
+	} else {
+		cdnDownloader = NewCDNDownloader(assetConfig.CDNBaseURL, assetConfig.CDNPattern)
+	}
+	if assetConfig.CDNHTTPConfig != nil {
+		cdnDownloader.HTTPClient = NewRetryableHTTPClient(*assetConfig.CDNHTTPConfig)
+	}
+	cdnDownloader.VersionDiscovery = assetConfig.CDNVersionDiscovery
+	cdnDownloader.Mirrors = assetConfig.CDNMirrors
+	cdnDownloader.RequestSigner = assetConfig.CDNRequestSigner
+	cdnDownloader.ctx = fileConfig.Context()
+
+	if fileConfig.Cache.Enabled {
+		if cache, err := fileUtils.NewDownloadCache(fileConfig.Cache); err == nil {
+			cdnDownloader.Cache = cache
+			if len(cdnDownloader.Mirrors) > 0 {
+				cdnDownloader.Ranking = LoadMirrorRanking(filepath.Join(cache.Directory(), MirrorRankingFileName))
+			}
+		} else {
+			fmt.Printf("Warning: download cache disabled: %v\n", err)
+		}
+	}
+	return cdnDownloader
+}
+
 // ConstructURL builds the download URL for the given version and platform
 func (c *CDNDownloader) ConstructURL(version, os, arch string) string {
 	return c.ConstructURLWithVersionFormat(version, os, arch, "as-is")
@@ -48,7 +91,12 @@ func (c *CDNDownloader) ConstructURL(version, os, arch string) string {
 
 // ConstructURLWithVersionFormat builds the download URL with configurable version formatting
 func (c *CDNDownloader) ConstructURLWithVersionFormat(version, os, arch, versionFormat string) string {
-	url := c.BaseURL + c.Pattern
+	return c.constructURL(c.BaseURL, version, os, arch, versionFormat)
+}
+
+// constructURL builds the download URL on the given base URL
+func (c *CDNDownloader) constructURL(baseURL, version, os, arch, versionFormat string) string {
+	url := baseURL + c.Pattern
 
 	// Format version according to the specified format
 	versionToUse := FormatVersionForCDN(version, versionFormat)
@@ -89,8 +137,67 @@ func (c *CDNDownloader) Download(version, destinationPath string) error {
 	return c.DownloadWithVersionFormat(version, destinationPath, "as-is")
 }
 
-// DownloadWithVersionFormat downloads a binary from the CDN with configurable version formatting
-func (c *CDNDownloader) DownloadWithVersionFormat(version, destinationPath, versionFormat string) error {
+// ErrVersionNotOnCDN is returned when the CDN does not (yet) serve the requested version
+var ErrVersionNotOnCDN = fileUtils.NewError(fileUtils.ErrorCodeNoMatch, "version not on CDN yet")
+
+// CDNProbeResult describes a CDN download without fetching it
+type CDNProbeResult struct {
+	URL        string // Download URL for the current platform
+	StatusCode int    // HTTP status of the HEAD request
+	Size       int64  // Content-Length (-1 if unknown)
+	ETag       string // ETag header, if provided
+	Available  bool   // True if the CDN answered with a 2xx status
+}
+
+// Probe issues a HEAD request for the version's download URL on the current platform
+func (c *CDNDownloader) Probe(version string) (*CDNProbeResult, error) {
+	return c.ProbeWithVersionFormat(version, "as-is")
+}
+
+// ProbeWithVersionFormat issues a HEAD request for the download URL with configurable version formatting
+func (c *CDNDownloader) ProbeWithVersionFormat(version, versionFormat string) (*CDNProbeResult, error) {
+	url := c.mirrorURL(c.rankedBaseURLs()[0], version, versionFormat)
+
+	req, err := c.newRequest("HEAD", url)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to probe CDN: %w", err)
+	}
+	resp.Body.Close()
+
+	return &CDNProbeResult{
+		URL:        url,
+		StatusCode: resp.StatusCode,
+		Size:       resp.ContentLength,
+		ETag:       resp.Header.Get("ETag"),
+		Available:  resp.StatusCode >= 200 && resp.StatusCode < 300,
+	}, nil
+}
+
+// ensureOnCDN probes the CDN and returns ErrVersionNotOnCDN if the version is missing.
+// Other statuses (e.g. CDNs rejecting HEAD requests) are left for the download to handle.
+func (c *CDNDownloader) ensureOnCDN(version, versionFormat string) error {
+	result, err := c.ProbeWithVersionFormat(version, versionFormat)
+	if err != nil {
+		return err
+	}
+	if result.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: %s is not available at %s", ErrVersionNotOnCDN, version, result.URL)
+	}
+	return nil
+}
+
+// platformURL builds the download URL for the current platform
+func (c *CDNDownloader) platformURL(version, versionFormat string) string {
+	return c.mirrorURL(c.BaseURL, version, versionFormat)
+}
+
+// mirrorURL builds the download URL for the current platform on the given base URL
+func (c *CDNDownloader) mirrorURL(baseURL, version, versionFormat string) string {
 	// Use current platform for CDN downloads
 	osName := runtime.GOOS
 	archName := c.mapArchForCDN(runtime.GOARCH)
@@ -104,42 +211,155 @@ func (c *CDNDownloader) DownloadWithVersionFormat(version, destinationPath, vers
 		// This will be handled by the specific CDN configuration
 	}
 
-	url := c.ConstructURLWithVersionFormat(version, osName, archName, versionFormat)
-	
-	fmt.Printf("Downloading from CDN: %s\n", url)
-	
-	// Create HTTP request
-	req, err := http.NewRequest("GET", url, nil)
+	return c.constructURL(baseURL, version, osName, archName, versionFormat)
+}
+
+// FileName returns the file name of the version's download for the current platform
+func (c *CDNDownloader) FileName(version, versionFormat string) string {
+	parsed, err := url.Parse(c.platformURL(version, versionFormat))
 	if err != nil {
-		return fmt.Errorf("failed to create request: %v", err)
+		return ""
 	}
-	
-	// Set user agent
-	req.Header.Set("User-Agent", "go-binary-updater/1.0")
-	
-	// Make the request
-	resp, err := c.HTTPClient.Do(req)
+	return path.Base(parsed.Path)
+}
+
+// DownloadToStaging downloads the version for the current platform to the path configured by
+// SourceArchivePath, or to the CDN file's name in the staging directory, and returns that path
+func (c *CDNDownloader) DownloadToStaging(config fileUtils.FileConfig, version, versionFormat string) (string, error) {
+	destinationPath := fileUtils.GetSourceArchivePathForAsset(config, version, c.FileName(version, versionFormat))
+	return destinationPath, c.DownloadWithVersionFormat(version, destinationPath, versionFormat)
+}
+
+// newRequest creates a CDN request signed by RequestSigner. The signature stays out of URLs in
+// messages and cache keys, which use the unsigned URL.
+func (c *CDNDownloader) newRequest(method, url string) (*http.Request, error) {
+	ctx := c.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
 	if err != nil {
-		return fmt.Errorf("failed to download from CDN: %v", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
-	defer resp.Body.Close()
+	fileUtils.SetUserAgent(req)
+	if c.RequestSigner != nil {
+		if err := c.RequestSigner(req); err != nil {
+			return nil, fmt.Errorf("failed to sign CDN request for %s: %w", url, err)
+		}
+	}
+	return req, nil
+}
+
+// rankedBaseURLs returns BaseURL and the mirrors, fastest healthy mirror first
+func (c *CDNDownloader) rankedBaseURLs() []string {
+	if len(c.Mirrors) == 0 {
+		return []string{c.BaseURL}
+	}
+	return c.mirrorRanking().Rank(append([]string{c.BaseURL}, c.Mirrors...))
+}
+
+// mirrorRanking returns the configured ranking or the process-wide one
+func (c *CDNDownloader) mirrorRanking() *MirrorRanking {
+	if c.Ranking != nil {
+		return c.Ranking
+	}
+	return processMirrorRanking
+}
+
+// fetchFromMirrors requests the download from each mirror in ranked order until one answers with
+// 200 OK, recording the outcome of every attempt in the mirror ranking
+func (c *CDNDownloader) fetchFromMirrors(version, versionFormat string) (*http.Response, error) {
+	var lastErr error
+	for _, baseURL := range c.rankedBaseURLs() {
+		url := c.mirrorURL(baseURL, version, versionFormat)
+		if len(c.Mirrors) > 0 {
+			fmt.Printf("Downloading from CDN: %s\n", url)
+		}
+
+		req, err := c.newRequest("GET", url)
+		if err != nil {
+			return nil, err
+		}
+
+		start := time.Now()
+		resp, err := c.HTTPClient.Do(req)
+		if err != nil {
+			lastErr = fmt.Errorf("failed to download from CDN: %w", err)
+		} else if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			lastErr = fmt.Errorf("CDN download failed with status %w", newHTTPStatusError(resp))
+		} else {
+			lastErr = nil
+		}
+
+		if len(c.Mirrors) == 0 {
+			return resp, lastErr
+		}
+		c.mirrorRanking().Record(baseURL, time.Since(start), lastErr)
+		if lastErr == nil {
+			return resp, nil
+		}
+		fmt.Printf("Warning: mirror %s failed: %v\n", baseURL, lastErr)
+	}
+	return nil, fmt.Errorf("all %d CDN mirrors failed, last error: %w", len(c.Mirrors)+1, lastErr)
+}
+
+// DownloadWithVersionFormat downloads a binary from the CDN with configurable version formatting
+func (c *CDNDownloader) DownloadWithVersionFormat(version, destinationPath, versionFormat string) (err error) {
+	url := c.platformURL(version, versionFormat)
+
+	if c.Cache != nil {
+		hit, err := c.Cache.Get(url, "", destinationPath)
+		if err != nil {
+			return err
+		}
+		if hit {
+			fmt.Printf("Using cached download for %s\n", url)
+			return nil
+		}
+	}
+
+	if len(c.Mirrors) == 0 {
+		fmt.Printf("Downloading from CDN: %s\n", url)
+	}
+	start := time.Now()
+	var written int64
+	defer func() { fileUtils.ObserveDownload("cdn", start, written, err) }()
 	
-	// Check response status
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("CDN download failed with status %d: %s", resp.StatusCode, resp.Status)
+	// Request the download from the best mirror that serves it
+	resp, err := c.fetchFromMirrors(version, versionFormat)
+	if err != nil {
+		return err
 	}
+	defer resp.Body.Close()
 	
 	// Create destination file
-	destFile, err := os.Create(destinationPath)
+	if err := fsys().MkdirAll(filepath.Dir(destinationPath), 0755); err != nil {
+		return fmt.Errorf("failed to create destination directory: %w", err)
+	}
+	if err := fileUtils.CheckDiskSpace(filepath.Dir(destinationPath), resp.ContentLength); err != nil {
+		return err
+	}
+	destFile, err := fsys().Create(destinationPath)
 	if err != nil {
-		return fmt.Errorf("failed to create destination file: %v", err)
+		return fmt.Errorf("failed to create destination file: %w", err)
 	}
 	defer destFile.Close()
 	
 	// Copy response body to file
-	_, err = io.Copy(destFile, resp.Body)
+	written, err = io.Copy(destFile, resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to write downloaded content: %v", err)
+		return fmt.Errorf("failed to write downloaded content: %w", err)
+	}
+	if err := destFile.Close(); err != nil {
+		return fmt.Errorf("failed to finalize downloaded content: %w", err)
+	}
+
+	if c.Cache != nil {
+		// The download succeeded, so a cache that cannot be filled only costs the next download
+		if err := c.Cache.Put(url, "", destinationPath); err != nil {
+			fmt.Printf("Warning: failed to cache download: %v\n", err)
+		}
 	}
 	
 	fmt.Printf("Successfully downloaded to: %s\n", destinationPath)
@@ -148,10 +368,15 @@ func (c *CDNDownloader) DownloadWithVersionFormat(version, destinationPath, vers
 
 // mapArchForCDN maps architecture names using configurable mapping or fallback to standard mapping
 func (c *CDNDownloader) mapArchForCDN(arch string) string {
+	return mapCDNArch(c.ArchMapping, arch)
+}
+
+// mapCDNArch maps an architecture name with a CDN architecture mapping, falling back to MapArch
+func mapCDNArch(archMapping map[string]string, arch string) string {
 	// If custom architecture mapping is configured, use it
-	if c.ArchMapping != nil {
+	if archMapping != nil {
 		normalizedArch := strings.ToLower(strings.TrimSpace(arch))
-		if mappedArch, exists := c.ArchMapping[normalizedArch]; exists {
+		if mappedArch, exists := archMapping[normalizedArch]; exists {
 			return mappedArch
 		}
 	}
@@ -285,49 +510,17 @@ func GetDockerConfig() AssetMatchingConfig {
 	return config
 }
 
-// TryDiscoverLatestVersion attempts to discover the latest version from CDN-specific endpoints
-// This is a best-effort approach that works for some CDNs but may not work for all
+// TryDiscoverLatestVersion attempts to discover the latest version from the CDN's discovery endpoint.
+// VersionDiscovery is used when set; otherwise built-in endpoints for kubectl, Helm and HashiCorp are tried.
 func (c *CDNDownloader) TryDiscoverLatestVersion() (string, error) {
-	// For now, this is a placeholder that could be extended to support specific CDN version discovery
-	// Different CDNs have different ways to discover latest versions:
-	// - Helm: Could check https://api.github.com/repos/helm/helm/releases/latest
-	// - kubectl: Could check https://dl.k8s.io/release/stable.txt
-	// - Terraform: Could parse https://releases.hashicorp.com/terraform/
-
-	// For kubectl, we can try the stable.txt endpoint
-	if strings.Contains(c.BaseURL, "dl.k8s.io") {
-		return c.discoverKubectlLatestVersion()
+	discovery := c.VersionDiscovery
+	if discovery == nil {
+		discovery = builtinVersionDiscovery(c.BaseURL)
 	}
-
-	// For other CDNs, we don't have a generic way to discover versions
-	return "", fmt.Errorf("version discovery not supported for this CDN: %s", c.BaseURL)
-}
-
-// discoverKubectlLatestVersion discovers the latest kubectl version from the Kubernetes CDN
-func (c *CDNDownloader) discoverKubectlLatestVersion() (string, error) {
-	stableURL := "https://dl.k8s.io/release/stable.txt"
-
-	resp, err := c.HTTPClient.Get(stableURL)
-	if err != nil {
-		return "", fmt.Errorf("failed to get kubectl stable version: %v", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("kubectl stable version endpoint returned status %d", resp.StatusCode)
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("failed to read kubectl stable version response: %v", err)
-	}
-
-	version := strings.TrimSpace(string(body))
-	if version == "" {
-		return "", fmt.Errorf("empty version returned from kubectl stable endpoint")
+	if discovery == nil {
+		return "", fmt.Errorf("version discovery not supported for this CDN: %s", c.BaseURL)
 	}
-
-	return version, nil
+	return c.DiscoverVersion(*discovery)
 }
 
 // ValidateCDNConfig validates that a CDN configuration is properly set up
@@ -374,20 +567,7 @@ func ValidateCDNConfig(config AssetMatchingConfig) error {
 	return nil
 }
 
-// GetPresetConfig returns a preset configuration for common binaries
+// GetPresetConfig returns a preset configuration for common binaries from DefaultPresetRegistry
 func GetPresetConfig(binaryName string) (AssetMatchingConfig, error) {
-	switch strings.ToLower(binaryName) {
-	case "helm":
-		return GetHelmCDNConfig(), nil
-	case "kubectl":
-		return GetKubectlCDNConfig(), nil
-	case "k0s":
-		return GetK0sConfig(), nil
-	case "terraform":
-		return GetTerraformConfig(), nil
-	case "docker":
-		return GetDockerConfig(), nil
-	default:
-		return AssetMatchingConfig{}, fmt.Errorf("no preset configuration available for binary: %s", binaryName)
-	}
+	return DefaultPresetRegistry.Get(binaryName)
 }
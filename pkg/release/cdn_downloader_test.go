@@ -0,0 +1,272 @@
+package release
+
+import (
+	"errors"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCDNDownloader_RetriesTransientErrors(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("binary content"))
+	}))
+	defer server.Close()
+
+	httpConfig := DefaultCDNHTTPClientConfig()
+	httpConfig.InitialDelay = 10 * time.Millisecond
+
+	assetConfig := DefaultAssetMatchingConfig()
+	assetConfig.CDNBaseURL = server.URL + "/"
+	assetConfig.CDNPattern = "tool-{version}-{os}-{arch}"
+	assetConfig.CDNHTTPConfig = &httpConfig
+
+	downloader := newConfiguredCDNDownloader(assetConfig, fileUtils.FileConfig{})
+	destination := filepath.Join(t.TempDir(), "tool")
+	if err := downloader.Download("v1.0.0", destination); err != nil {
+		t.Fatalf("Expected download to succeed after retries, got: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d", attempts)
+	}
+	content, _ := os.ReadFile(destination)
+	if string(content) != "binary content" {
+		t.Errorf("Unexpected downloaded content: %q", content)
+	}
+}
+
+func TestCDNDownloader_Probe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("Expected HEAD request, got %s", r.Method)
+		}
+		if !strings.Contains(r.URL.Path, "v1.0.0") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("ETag", `"abc123"`)
+		w.Header().Set("Content-Length", "4096")
+	}))
+	defer server.Close()
+
+	downloader := NewCDNDownloader(server.URL+"/", "tool-{version}-{os}-{arch}")
+
+	result, err := downloader.Probe("v1.0.0")
+	if err != nil {
+		t.Fatalf("Probe failed: %v", err)
+	}
+	if !result.Available || result.StatusCode != http.StatusOK || result.Size != 4096 || result.ETag != `"abc123"` {
+		t.Errorf("Unexpected probe result: %+v", result)
+	}
+
+	result, err = downloader.Probe("v2.0.0")
+	if err != nil {
+		t.Fatalf("Probe failed: %v", err)
+	}
+	if result.Available || result.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected missing version to be unavailable, got %+v", result)
+	}
+	if err := downloader.ensureOnCDN("v2.0.0", "as-is"); !errors.Is(err, ErrVersionNotOnCDN) {
+		t.Errorf("Expected ErrVersionNotOnCDN, got %v", err)
+	}
+}
+
+func TestGithubRelease_HybridFallsBackWhenNotOnCDN(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/cdn/") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("release asset"))
+	}))
+	defer server.Close()
+
+	assetConfig := DefaultAssetMatchingConfig()
+	assetConfig.Strategy = HybridStrategy
+	assetConfig.CDNBaseURL = server.URL + "/cdn/"
+	assetConfig.CDNPattern = "myapp-{version}-{os}-{arch}.tar.gz"
+
+	tempDir := t.TempDir()
+	fileConfig := fileUtils.FileConfig{ProjectName: "myapp", StagingDirectory: tempDir}
+
+	release := NewGithubReleaseWithAssetConfig("owner/repo", fileConfig, assetConfig)
+	release.Version = "v1.0.0"
+	release.Source = &fakeAssetSource{release: &ReleaseInfo{
+		Version: "v1.0.0",
+		Assets:  []AssetInfo{{Name: "myapp-Linux_x86_64.tar.gz", URL: server.URL + "/assets/myapp-Linux_x86_64.tar.gz"}},
+	}}
+
+	if err := release.DownloadLatestRelease(); err != nil {
+		t.Fatalf("Expected fallback to release assets, got: %v", err)
+	}
+	content, _ := os.ReadFile(filepath.Join(tempDir, "myapp-github-myapp-Linux_x86_64.tar.gz"))
+	if string(content) != "release asset" {
+		t.Errorf("Expected release asset to be downloaded, got %q", content)
+	}
+
+	release.AssetMatchingConfig.Strategy = CDNStrategy
+	if err := release.DownloadCDNVersion("v1.0.0"); !errors.Is(err, ErrVersionNotOnCDN) {
+		t.Errorf("Expected CDN-only download to fail fast with ErrVersionNotOnCDN, got %v", err)
+	}
+}
+
+func TestParseDiscoveryResponse(t *testing.T) {
+	hashicorpListing := `<ul>
+  <li><a href="../">../</a></li>
+  <li><a href="/terraform/1.7.0-alpha20231025/">terraform_1.7.0-alpha20231025</a></li>
+  <li><a href="/terraform/1.6.2/">terraform_1.6.2</a></li>
+  <li><a href="/terraform/1.10.0/">terraform_1.10.0</a></li>
+  <li><a href="/terraform/1.9.8/">terraform_1.9.8</a></li>
+</ul>`
+	gcsListing := `<?xml version='1.0' encoding='UTF-8'?>
+<ListBucketResult xmlns="http://doc.s3.amazonaws.com/2006-03-01">
+  <Name>tools</Name>
+  <CommonPrefixes><Prefix>release/v1.27.4/</Prefix></CommonPrefixes>
+  <CommonPrefixes><Prefix>release/v1.28.0/</Prefix></CommonPrefixes>
+  <CommonPrefixes><Prefix>release/v1.29.0-rc.1/</Prefix></CommonPrefixes>
+</ListBucketResult>`
+
+	tests := []struct {
+		name      string
+		discovery VersionDiscoveryConfig
+		body      string
+		expected  string
+		wantErr   bool
+	}{
+		{"plain text", VersionDiscoveryConfig{Format: DiscoveryText}, "\nv1.28.3\n", "v1.28.3", false},
+		{"text with pattern", VersionDiscoveryConfig{Format: DiscoveryText, Pattern: `version: (\S+)`}, "version: 2.1.0\n", "2.1.0", false},
+		{"empty text", VersionDiscoveryConfig{Format: DiscoveryText}, "  \n", "", true},
+		{"json field", VersionDiscoveryConfig{Format: DiscoveryJSON, JSONField: "current_version"}, `{"product":"terraform","current_version":"1.9.8"}`, "1.9.8", false},
+		{"json nested array index", VersionDiscoveryConfig{Format: DiscoveryJSON, JSONField: "releases.0.tag"}, `{"releases":[{"tag":"v3.1.0"},{"tag":"v3.0.0"}]}`, "v3.1.0", false},
+		{"json object keys", VersionDiscoveryConfig{Format: DiscoveryJSON, JSONField: "versions"}, `{"versions":{"1.5.0":{},"1.10.1":{},"1.11.0-beta1":{}}}`, "1.10.1", false},
+		{"json array", VersionDiscoveryConfig{Format: DiscoveryJSON}, `["0.9.0","0.10.0","0.2.0"]`, "0.10.0", false},
+		{"json missing field", VersionDiscoveryConfig{Format: DiscoveryJSON, JSONField: "missing"}, `{}`, "", true},
+		{"html listing", VersionDiscoveryConfig{Format: DiscoveryHTML}, hashicorpListing, "1.10.0", false},
+		{"html listing with prereleases", VersionDiscoveryConfig{Format: DiscoveryHTML, IncludePrerelease: true, Pattern: `href="/terraform/([^/"]+)/"`}, hashicorpListing, "1.10.0", false},
+		{"gcs listing", VersionDiscoveryConfig{Format: DiscoveryGCS}, gcsListing, "v1.28.0", false},
+		{"gcs listing with prereleases", VersionDiscoveryConfig{Format: DiscoveryGCS, IncludePrerelease: true}, gcsListing, "v1.29.0-rc.1", false},
+		{"pattern without group", VersionDiscoveryConfig{Format: DiscoveryHTML, Pattern: `\d+`}, hashicorpListing, "", true},
+		{"unknown format", VersionDiscoveryConfig{Format: "yaml"}, "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, err := parseDiscoveryResponse(tt.discovery, []byte(tt.body))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDiscoveryResponse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if version != tt.expected {
+				t.Errorf("Expected version %q, got %q", tt.expected, version)
+			}
+		})
+	}
+}
+
+func TestCDNDownloader_TryDiscoverLatestVersion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"tag_name":"v3.14.2"}`))
+	}))
+	defer server.Close()
+
+	assetConfig := GetHelmCDNConfig()
+	assetConfig.CDNVersionDiscovery = &VersionDiscoveryConfig{URL: server.URL, Format: DiscoveryJSON, JSONField: "tag_name"}
+
+	downloader := newConfiguredCDNDownloader(assetConfig, fileUtils.FileConfig{})
+	version, err := downloader.TryDiscoverLatestVersion()
+	if err != nil {
+		t.Fatalf("TryDiscoverLatestVersion failed: %v", err)
+	}
+	if version != "v3.14.2" {
+		t.Errorf("Expected v3.14.2, got %s", version)
+	}
+
+	unknown := NewCDNDownloader("https://cdn.example.com/", "tool-{version}")
+	if _, err := unknown.TryDiscoverLatestVersion(); err == nil {
+		t.Error("Expected error for CDN without discovery configuration")
+	}
+	for _, baseURL := range []string{"https://dl.k8s.io/release/", "https://get.helm.sh/", "https://releases.hashicorp.com/terraform/"} {
+		if builtinVersionDiscovery(baseURL) == nil {
+			t.Errorf("Expected built-in version discovery for %s", baseURL)
+		}
+	}
+}
+
+func TestCDNDownloader_DownloadToStaging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("cdn content"))
+	}))
+	defer server.Close()
+
+	downloader := NewCDNDownloader(server.URL+"/releases/", "tool-{version}.zip?channel=stable")
+	if name := downloader.FileName("v1.0.0", "without-v"); name != "tool-1.0.0.zip" {
+		t.Errorf("Expected file name without query, got %q", name)
+	}
+
+	stagingDir := t.TempDir()
+	path, err := downloader.DownloadToStaging(fileUtils.FileConfig{StagingDirectory: stagingDir}, "v1.0.0", "without-v")
+	if err != nil {
+		t.Fatalf("DownloadToStaging failed: %v", err)
+	}
+	if path != filepath.Join(stagingDir, "tool-1.0.0.zip") {
+		t.Errorf("Expected download to be staged under the CDN file name, got %s", path)
+	}
+	if content, _ := os.ReadFile(path); string(content) != "cdn content" {
+		t.Errorf("Unexpected staged content: %q", content)
+	}
+}
+
+func TestCDNDownloader_RequestSigner(t *testing.T) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("Signature") != "sig-"+r.Method {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		methods = append(methods, r.Method)
+		w.Write([]byte("signed content"))
+	}))
+	defer server.Close()
+
+	assetConfig := DefaultAssetMatchingConfig()
+	assetConfig.CDNBaseURL = server.URL + "/"
+	assetConfig.CDNPattern = "tool-{version}-{os}-{arch}"
+	assetConfig.CDNRequestSigner = func(req *http.Request) error {
+		query := req.URL.Query()
+		query.Set("Signature", "sig-"+req.Method)
+		req.URL.RawQuery = query.Encode()
+		return nil
+	}
+	downloader := newConfiguredCDNDownloader(assetConfig, fileUtils.FileConfig{})
+
+	result, err := downloader.Probe("v1.0.0")
+	if err != nil || !result.Available || strings.Contains(result.URL, "Signature") {
+		t.Errorf("Expected the signed probe to succeed without exposing the signature, got %+v, %v", result, err)
+	}
+	destination := filepath.Join(t.TempDir(), "tool")
+	if err := downloader.Download("v1.0.0", destination); err != nil {
+		t.Fatalf("Download failed: %v", err)
+	}
+	if content, _ := os.ReadFile(destination); string(content) != "signed content" {
+		t.Errorf("Unexpected downloaded content: %q", content)
+	}
+	if strings.Join(methods, ",") != "HEAD,GET" {
+		t.Errorf("Expected signed HEAD and GET requests, got %v", methods)
+	}
+
+	signErr := errors.New("key expired")
+	downloader.RequestSigner = func(*http.Request) error { return signErr }
+	if err := downloader.Download("v1.0.0", destination); !errors.Is(err, signErr) {
+		t.Errorf("Expected the signer's error, got %v", err)
+	}
+}
@@ -206,3 +206,32 @@ func TestHybridStrategyFromFileConfig(t *testing.T) {
 			githubRelease.AssetMatchingConfig.Strategy)
 	}
 }
+
+// TestGitLabCDNConfigSurvivesConstruction ensures the GitLab constructors keep the caller's asset config
+func TestGitLabCDNConfigSurvivesConstruction(t *testing.T) {
+	config := fileUtils.FileConfig{
+		VersionedDirectoryName: "versions",
+		SourceBinaryName:       "helm",
+		BinaryName:             "helm",
+		BaseBinaryDirectory:    "/tmp/test",
+		ProjectName:            "helm",
+	}
+	helmConfig := GetHelmCDNConfig()
+
+	for name, gitlabRelease := range map[string]*GitLabRelease{
+		"NewGitlabReleaseWithAssetConfig": NewGitlabReleaseWithAssetConfig("group/helm", config, helmConfig),
+		"NewGitlabReleaseWithCDNConfig":   NewGitlabReleaseWithCDNConfig("group/helm", config, helmConfig),
+	} {
+		got := gitlabRelease.AssetMatchingConfig
+		if got.Strategy != CDNStrategy {
+			t.Errorf("%s: expected CDNStrategy, got %v", name, got.Strategy)
+		}
+		if got.CDNBaseURL != helmConfig.CDNBaseURL || got.CDNPattern != helmConfig.CDNPattern {
+			t.Errorf("%s: expected CDN base %q and pattern %q, got %q and %q",
+				name, helmConfig.CDNBaseURL, helmConfig.CDNPattern, got.CDNBaseURL, got.CDNPattern)
+		}
+		if got.CDNArchMapping["amd64"] != helmConfig.CDNArchMapping["amd64"] {
+			t.Errorf("%s: expected the CDN architecture mapping to be kept", name)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package release
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+)
+
+// UpdateChecker is implemented by providers that can determine whether a newer version exists
+// without downloading anything, e.g. to print "an update is available" banners
+type UpdateChecker interface {
+	CheckForUpdate() (*UpdateCheck, error) // Resolves the latest release and compares it with the installed version
+}
+
+// UpdateCheck describes the outcome of a notification-only update check
+type UpdateCheck struct {
+	UpdateAvailable bool      `json:"update_available"` // True if LatestVersion is newer than CurrentVersion
+	CurrentVersion  string    `json:"current_version"`  // Installed version (empty if nothing is installed)
+	LatestVersion   string    `json:"latest_version"`   // Latest version that would be installed
+	ReleaseName     string    `json:"release_name"`     // Human-readable release title
+	ReleaseNotes    string    `json:"release_notes"`    // Release notes / changelog of the latest release
+	PublishedAt     time.Time `json:"published_at"`     // When the latest release was published (zero if unknown)
+	AssetName       string    `json:"asset_name"`       // Asset that would be downloaded
+	AssetURL        string    `json:"asset_url"`        // Browser download URL of the asset (empty for local artifacts)
+}
+
+// newUpdateCheck compares the resolved release with the installed version
+func newUpdateCheck(installedVersion func() (string, error), version string, info *ReleaseInfo, assetName, assetURL string) *UpdateCheck {
+	check := &UpdateCheck{
+		LatestVersion: version,
+		AssetName:     assetName,
+		AssetURL:      assetURL,
+	}
+	// An error means nothing is installed yet, so any release is an update
+	if current, err := installedVersion(); err == nil {
+		check.CurrentVersion = current
+	}
+	if info != nil {
+		check.ReleaseName = info.Name
+		check.ReleaseNotes = info.ReleaseNotes()
+		check.PublishedAt = info.PublishedAt
+	}
+	check.UpdateAvailable = IsNewerVersion(check.LatestVersion, check.CurrentVersion)
+	return check
+}
+
+// Banner returns a one-line notice for the update, e.g. "tool 1.2.0 is available (installed: 1.1.0),
+// run `tool update` to install". It returns "" if no update is available.
+func (c *UpdateCheck) Banner(name, updateCommand string) string {
+	if !c.UpdateAvailable {
+		return ""
+	}
+	banner := fmt.Sprintf("%s %s is available", name, c.LatestVersion)
+	if c.CurrentVersion != "" {
+		banner += fmt.Sprintf(" (installed: %s)", c.CurrentVersion)
+	}
+	if updateCommand != "" {
+		banner += fmt.Sprintf(", run `%s` to install", updateCommand)
+	}
+	return banner
+}
+
+// CheckForUpdate resolves the latest GitHub release and reports whether it is newer than the
+// installed version, without downloading it. CDN and hybrid configurations fall back to the
+// latest tag for repositories without releases.
+func (g *GithubRelease) CheckForUpdate() (*UpdateCheck, error) {
+	resolve := g.GetLatestRelease
+	if g.AssetMatchingConfig.Strategy == CDNStrategy || g.AssetMatchingConfig.Strategy == HybridStrategy {
+		resolve = g.resolveLatestVersion
+	}
+	if err := resolve(); err != nil {
+		return nil, fmt.Errorf("error getting latest release from GitHub: %w", err)
+	}
+	return newUpdateCheck(g.InstalledVersion, g.Version, g.Info, g.AssetName, g.ReleaseLink), nil
+}
+
+// CheckForUpdate resolves the latest GitLab release and reports whether it is newer than the
+// installed version, without downloading it
+func (r *GitLabRelease) CheckForUpdate() (*UpdateCheck, error) {
+	if err := r.GetLatestRelease(); err != nil {
+		return nil, fmt.Errorf("error getting latest release from GitLab: %w", err)
+	}
+	return newUpdateCheck(r.InstalledVersion, r.Version, r.Info, r.AssetName, r.ReleaseLink), nil
+}
+
+// CheckForUpdate reports whether the local artifact's version is newer than the installed version
+func (l *LocalRelease) CheckForUpdate() (*UpdateCheck, error) {
+	if err := l.GetLatestRelease(); err != nil {
+		return nil, err
+	}
+	return newUpdateCheck(l.InstalledVersion, l.Version, nil, filepath.Base(l.ArtifactPath), ""), nil
+}
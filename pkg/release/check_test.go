@@ -0,0 +1,80 @@
+package release
+
+import (
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGithubRelease_CheckForUpdate(t *testing.T) {
+	tempDir := t.TempDir()
+	archivePath := filepath.Join(tempDir, "myapp.tar.gz")
+	writeTestTarGz(t, archivePath, map[string]string{"myapp": "#!/bin/sh\n"})
+
+	fileConfig := fileUtils.FileConfig{
+		BaseBinaryDirectory:    filepath.Join(tempDir, "bin"),
+		VersionedDirectoryName: "versions",
+		SourceBinaryName:       "myapp",
+		BinaryName:             "myapp",
+		CreateLocalSymlink:     true,
+		StagingDirectory:       filepath.Join(tempDir, "staging"),
+	}
+
+	published := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	release := NewGithubRelease("owner/repo", fileConfig)
+	release.Source = &fakeAssetSource{release: &ReleaseInfo{
+		Version:     "v1.2.0",
+		Name:        "Release 1.2.0",
+		Description: "Faster downloads",
+		PublishedAt: published,
+		Assets:      []AssetInfo{{Name: "myapp-Linux_x86_64.tar.gz", URL: "https://example.com/myapp-Linux_x86_64.tar.gz"}},
+	}}
+
+	// Nothing installed: any release is an update
+	check, err := release.CheckForUpdate()
+	if err != nil {
+		t.Fatalf("CheckForUpdate() error = %v", err)
+	}
+	if !check.UpdateAvailable || check.CurrentVersion != "" || check.LatestVersion != "v1.2.0" {
+		t.Errorf("Unexpected check result: %+v", check)
+	}
+	if check.ReleaseName != "Release 1.2.0" || check.ReleaseNotes != "Faster downloads" || !check.PublishedAt.Equal(published) {
+		t.Errorf("Expected release metadata in check result, got %+v", check)
+	}
+	if check.AssetName != "myapp-Linux_x86_64.tar.gz" || check.AssetURL != "https://example.com/myapp-Linux_x86_64.tar.gz" {
+		t.Errorf("Unexpected asset in check result: %+v", check)
+	}
+	if entries, _ := os.ReadDir(fileConfig.StagingDirectory); len(entries) != 0 {
+		t.Errorf("Expected CheckForUpdate not to download anything, found %d staged files", len(entries))
+	}
+
+	// Older version installed
+	if err := NewLocalRelease(archivePath, "v1.1.0", fileConfig).InstallLatestRelease(); err != nil {
+		t.Fatalf("InstallLatestRelease() error = %v", err)
+	}
+	check, err = release.CheckForUpdate()
+	if err != nil {
+		t.Fatalf("CheckForUpdate() error = %v", err)
+	}
+	if !check.UpdateAvailable || check.CurrentVersion != "v1.1.0" {
+		t.Errorf("Expected update from v1.1.0, got %+v", check)
+	}
+	expected := "myapp v1.2.0 is available (installed: v1.1.0), run `myapp update` to install"
+	if banner := check.Banner("myapp", "myapp update"); banner != expected {
+		t.Errorf("Banner() = %q, want %q", banner, expected)
+	}
+
+	// Latest version installed
+	if err := NewLocalRelease(archivePath, "v1.2.0", fileConfig).InstallLatestRelease(); err != nil {
+		t.Fatalf("InstallLatestRelease() error = %v", err)
+	}
+	check, err = release.CheckForUpdate()
+	if err != nil {
+		t.Fatalf("CheckForUpdate() error = %v", err)
+	}
+	if check.UpdateAvailable || check.Banner("myapp", "myapp update") != "" {
+		t.Errorf("Expected no update when the latest version is installed, got %+v", check)
+	}
+}
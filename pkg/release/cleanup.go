@@ -0,0 +1,31 @@
+package release
+
+import (
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"os"
+)
+
+// Download cleanup statuses reported in InstallResult.DownloadCleanup
+const (
+	DownloadCleanupRemoved  = "removed"  // The downloaded file was deleted after a successful installation
+	DownloadCleanupDisabled = "disabled" // KeepDownloads is set, so the downloaded file was kept
+	DownloadCleanupFailed   = "failed"   // Deleting the downloaded file failed; it was kept
+	DownloadCleanupNone     = "none"     // No downloaded file was left behind (e.g. streamed extraction or local artifacts)
+)
+
+// cleanupDownload removes the downloaded file once it has been installed unless KeepDownloads is
+// set. Failed installations never reach this point, so their downloads stay for debugging.
+func cleanupDownload(config fileUtils.FileConfig, path string) string {
+	if config.KeepDownloads {
+		return DownloadCleanupDisabled
+	}
+	if err := fsys().Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return DownloadCleanupNone
+		}
+		fmt.Printf("Warning: failed to remove downloaded file %s: %v\n", path, err)
+		return DownloadCleanupFailed
+	}
+	return DownloadCleanupRemoved
+}
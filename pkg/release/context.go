@@ -0,0 +1,233 @@
+package release
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ContextRelease is implemented by providers whose metadata requests, downloads and installation
+// stop when a context is done, so a whole update can be bounded by one deadline. The per-request
+// timeouts of the API and CDN clients still apply within it.
+type ContextRelease interface {
+	GetLatestReleaseContext(ctx context.Context) error               // Like GetLatestRelease, bounded by ctx
+	DownloadLatestReleaseContext(ctx context.Context) error          // Like DownloadLatestRelease, bounded by ctx
+	InstallLatestReleaseContext(ctx context.Context) error           // Like InstallLatestRelease, bounded by ctx
+	InstallReleaseContext(ctx context.Context, version string) error // Like InstallRelease, bounded by ctx
+}
+
+// ResultContextRelease is implemented by providers that describe what they downloaded and
+// installed, bounded by a context
+type ResultContextRelease interface {
+	DownloadLatestReleaseWithResultContext(ctx context.Context) (*DownloadResult, error)         // Like DownloadLatestReleaseWithResult, bounded by ctx
+	InstallLatestReleaseWithResultContext(ctx context.Context) (*InstallResult, error)           // Like InstallLatestReleaseWithResult, bounded by ctx
+	InstallReleaseWithResultContext(ctx context.Context, version string) (*InstallResult, error) // Like InstallReleaseWithResult, bounded by ctx
+}
+
+var (
+	_ ContextRelease       = (*GithubRelease)(nil)
+	_ ResultContextRelease = (*GithubRelease)(nil)
+	_ ResultContextRelease = (*GitLabRelease)(nil)
+	_ ContextRelease       = (*GitLabRelease)(nil)
+	_ ContextRelease       = (*LocalRelease)(nil)
+)
+
+// UpdateContext downloads and installs the latest release before ctx is done, e.g. within
+// context.WithTimeout(ctx, 10*time.Minute) for an update that must finish in ten minutes.
+// Providers implementing ContextRelease are stopped at the deadline; others are only checked
+// between the download and the installation. A missed deadline matches context.DeadlineExceeded.
+func UpdateContext(ctx context.Context, r Release) error {
+	if c, ok := r.(ContextRelease); ok {
+		if err := c.DownloadLatestReleaseContext(ctx); err != nil {
+			return err
+		}
+		return c.InstallLatestReleaseContext(ctx)
+	}
+	return runWithContext(ctx, func() error {
+		if err := r.DownloadLatestRelease(); err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("installation not started: %w", err)
+		}
+		return r.InstallLatestRelease()
+	})
+}
+
+// runWithContext runs operation unless ctx is already done. Failures caused by ctx are reported as
+// its error, whatever the failing step made of it.
+func runWithContext(ctx context.Context, operation func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	err := operation()
+	if err != nil && ctx.Err() != nil && !errors.Is(err, ctx.Err()) {
+		err = fmt.Errorf("%w: %v", ctx.Err(), err)
+	}
+	return err
+}
+
+// withContext runs operation on a copy of the provider whose configuration carries ctx, so every
+// request and installation step it makes is bounded by ctx, then keeps the copy's results. The
+// provider's own configuration is never bound to ctx, so other callers don't inherit it.
+func (g *GithubRelease) withContext(ctx context.Context, operation func(*GithubRelease) error) error {
+	config := g.Config
+	bound := *g
+	bound.Config = config.WithContext(ctx)
+	err := runWithContext(ctx, func() error { return operation(&bound) })
+	bound.Config = config
+	*g = bound
+	return err
+}
+
+// withContext runs operation on a copy of the provider whose configuration carries ctx, see
+// GithubRelease.withContext
+func (r *GitLabRelease) withContext(ctx context.Context, operation func(*GitLabRelease) error) error {
+	config := r.Config
+	bound := *r
+	bound.Config = config.WithContext(ctx)
+	err := runWithContext(ctx, func() error { return operation(&bound) })
+	bound.Config = config
+	*r = bound
+	return err
+}
+
+// withContext runs operation on a copy of the provider whose configuration carries ctx, see
+// GithubRelease.withContext
+func (l *LocalRelease) withContext(ctx context.Context, operation func(*LocalRelease) error) error {
+	config := l.Config
+	bound := *l
+	bound.Config = config.WithContext(ctx)
+	err := runWithContext(ctx, func() error { return operation(&bound) })
+	bound.Config = config
+	*l = bound
+	return err
+}
+
+// GetLatestReleaseContext is GetLatestRelease, bounded by ctx
+func (g *GithubRelease) GetLatestReleaseContext(ctx context.Context) error {
+	return g.withContext(ctx, (*GithubRelease).GetLatestRelease)
+}
+
+// DownloadLatestReleaseContext is DownloadLatestRelease, bounded by ctx
+func (g *GithubRelease) DownloadLatestReleaseContext(ctx context.Context) error {
+	return g.withContext(ctx, (*GithubRelease).DownloadLatestRelease)
+}
+
+// InstallLatestReleaseContext is InstallLatestRelease, bounded by ctx
+func (g *GithubRelease) InstallLatestReleaseContext(ctx context.Context) error {
+	defer g.releaseStagedDownload()
+	return g.withContext(ctx, (*GithubRelease).InstallLatestRelease)
+}
+
+// InstallReleaseContext is InstallRelease, bounded by ctx
+func (g *GithubRelease) InstallReleaseContext(ctx context.Context, version string) error {
+	defer g.releaseStagedDownload()
+	return g.withContext(ctx, func(bound *GithubRelease) error { return bound.InstallRelease(version) })
+}
+
+// DownloadLatestReleaseWithResultContext is DownloadLatestReleaseWithResult, bounded by ctx
+func (g *GithubRelease) DownloadLatestReleaseWithResultContext(ctx context.Context) (*DownloadResult, error) {
+	var result *DownloadResult
+	err := g.withContext(ctx, func(bound *GithubRelease) (err error) {
+		result, err = bound.DownloadLatestReleaseWithResult()
+		return err
+	})
+	return result, err
+}
+
+// InstallLatestReleaseWithResultContext is InstallLatestReleaseWithResult, bounded by ctx
+func (g *GithubRelease) InstallLatestReleaseWithResultContext(ctx context.Context) (*InstallResult, error) {
+	defer g.releaseStagedDownload()
+	var result *InstallResult
+	err := g.withContext(ctx, func(bound *GithubRelease) (err error) {
+		result, err = bound.InstallLatestReleaseWithResult()
+		return err
+	})
+	return result, err
+}
+
+// InstallReleaseWithResultContext is InstallReleaseWithResult, bounded by ctx
+func (g *GithubRelease) InstallReleaseWithResultContext(ctx context.Context, version string) (*InstallResult, error) {
+	defer g.releaseStagedDownload()
+	var result *InstallResult
+	err := g.withContext(ctx, func(bound *GithubRelease) (err error) {
+		result, err = bound.InstallReleaseWithResult(version)
+		return err
+	})
+	return result, err
+}
+
+// GetLatestReleaseContext is GetLatestRelease, bounded by ctx
+func (r *GitLabRelease) GetLatestReleaseContext(ctx context.Context) error {
+	return r.withContext(ctx, (*GitLabRelease).GetLatestRelease)
+}
+
+// DownloadLatestReleaseContext is DownloadLatestRelease, bounded by ctx
+func (r *GitLabRelease) DownloadLatestReleaseContext(ctx context.Context) error {
+	return r.withContext(ctx, (*GitLabRelease).DownloadLatestRelease)
+}
+
+// InstallLatestReleaseContext is InstallLatestRelease, bounded by ctx
+func (r *GitLabRelease) InstallLatestReleaseContext(ctx context.Context) error {
+	defer r.releaseStagedDownload()
+	return r.withContext(ctx, (*GitLabRelease).InstallLatestRelease)
+}
+
+// InstallReleaseContext is InstallRelease, bounded by ctx
+func (r *GitLabRelease) InstallReleaseContext(ctx context.Context, version string) error {
+	defer r.releaseStagedDownload()
+	return r.withContext(ctx, func(bound *GitLabRelease) error { return bound.InstallRelease(version) })
+}
+
+// DownloadLatestReleaseWithResultContext is DownloadLatestReleaseWithResult, bounded by ctx
+func (r *GitLabRelease) DownloadLatestReleaseWithResultContext(ctx context.Context) (*DownloadResult, error) {
+	var result *DownloadResult
+	err := r.withContext(ctx, func(bound *GitLabRelease) (err error) {
+		result, err = bound.DownloadLatestReleaseWithResult()
+		return err
+	})
+	return result, err
+}
+
+// InstallLatestReleaseWithResultContext is InstallLatestReleaseWithResult, bounded by ctx
+func (r *GitLabRelease) InstallLatestReleaseWithResultContext(ctx context.Context) (*InstallResult, error) {
+	defer r.releaseStagedDownload()
+	var result *InstallResult
+	err := r.withContext(ctx, func(bound *GitLabRelease) (err error) {
+		result, err = bound.InstallLatestReleaseWithResult()
+		return err
+	})
+	return result, err
+}
+
+// InstallReleaseWithResultContext is InstallReleaseWithResult, bounded by ctx
+func (r *GitLabRelease) InstallReleaseWithResultContext(ctx context.Context, version string) (*InstallResult, error) {
+	defer r.releaseStagedDownload()
+	var result *InstallResult
+	err := r.withContext(ctx, func(bound *GitLabRelease) (err error) {
+		result, err = bound.InstallReleaseWithResult(version)
+		return err
+	})
+	return result, err
+}
+
+// GetLatestReleaseContext is GetLatestRelease, bounded by ctx
+func (l *LocalRelease) GetLatestReleaseContext(ctx context.Context) error {
+	return l.withContext(ctx, (*LocalRelease).GetLatestRelease)
+}
+
+// DownloadLatestReleaseContext is DownloadLatestRelease, bounded by ctx
+func (l *LocalRelease) DownloadLatestReleaseContext(ctx context.Context) error {
+	return l.withContext(ctx, (*LocalRelease).DownloadLatestRelease)
+}
+
+// InstallLatestReleaseContext is InstallLatestRelease, bounded by ctx
+func (l *LocalRelease) InstallLatestReleaseContext(ctx context.Context) error {
+	return l.withContext(ctx, (*LocalRelease).InstallLatestRelease)
+}
+
+// InstallReleaseContext installs the local artifact as version, bounded by ctx
+func (l *LocalRelease) InstallReleaseContext(ctx context.Context, version string) error {
+	return l.withContext(ctx, func(bound *LocalRelease) error { return bound.InstallFromFile(bound.ArtifactPath, version) })
+}
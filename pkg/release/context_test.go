@@ -0,0 +1,89 @@
+package release
+
+import (
+	"context"
+	"errors"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestUpdateContext_Deadline(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	newRelease := func() *GithubRelease {
+		release := NewGithubRelease("owner/repo", fileUtils.FileConfig{
+			BinaryName:          "myapp",
+			BaseBinaryDirectory: t.TempDir(),
+		})
+		release.BaseURL = server.URL
+		return release
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := UpdateContext(ctx, newRelease())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the deadline to be exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected the update to stop at the deadline, took %v", elapsed)
+	}
+
+	requests.Store(0)
+	canceled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	release := newRelease()
+	if err := release.InstallReleaseContext(canceled, "v1.0.0"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a canceled context to stop the installation, got %v", err)
+	}
+	if n := requests.Load(); n != 0 {
+		t.Errorf("Expected no request with a canceled context, got %d", n)
+	}
+	if release.Config.Context() != context.Background() {
+		t.Error("Expected the configuration's context to be restored")
+	}
+}
+
+func TestGetLatestReleaseContext_DoesNotBindProvider(t *testing.T) {
+	started, unblock := make(chan struct{}), make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-unblock
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"tag_name": "v1.0.0", "assets": []}`))
+	}))
+	defer server.Close()
+
+	release := NewGithubRelease("owner/repo", fileUtils.FileConfig{BinaryName: "myapp", BaseBinaryDirectory: t.TempDir()})
+	release.BaseURL = server.URL
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- release.GetLatestReleaseContext(ctx) }()
+	<-started
+	if release.Config.Context() != context.Background() {
+		t.Error("Expected the provider's configuration not to carry the operation's context")
+	}
+	close(unblock)
+	<-done
+	if release.Version != "v1.0.0" {
+		t.Errorf("Expected the operation's results to be kept, got version %q", release.Version)
+	}
+	if release.Config.Context() != context.Background() {
+		t.Error("Expected the configuration's context to be unchanged")
+	}
+}
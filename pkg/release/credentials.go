@@ -0,0 +1,158 @@
+package release
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// CredentialProvider resolves a secret token when it is needed, so tokens don't have to be
+// stored as plain strings in code or config files. Every CredentialProvider is a TokenProvider.
+type CredentialProvider interface {
+	TokenProvider
+}
+
+// defaultCredentialCommandTimeout bounds how long a credential helper command may run
+const defaultCredentialCommandTimeout = 30 * time.Second
+
+// StaticCredential is a token supplied directly
+type StaticCredential string
+
+// Token returns the static token
+func (s StaticCredential) Token() (string, error) {
+	if s == "" {
+		return "", fmt.Errorf("static credential is empty")
+	}
+	return string(s), nil
+}
+
+// EnvCredential reads the token from an environment variable
+type EnvCredential struct {
+	Name string // Environment variable name, e.g. "GITHUB_TOKEN"
+}
+
+// Token returns the value of the environment variable
+func (e EnvCredential) Token() (string, error) {
+	token := strings.TrimSpace(os.Getenv(e.Name))
+	if token == "" {
+		return "", fmt.Errorf("environment variable %s is not set", e.Name)
+	}
+	return token, nil
+}
+
+// FileCredential reads the token from a file, such as a mounted Kubernetes or Docker secret
+type FileCredential struct {
+	Path string // File containing only the token; surrounding whitespace is ignored
+}
+
+// Token returns the trimmed contents of the file
+func (f FileCredential) Token() (string, error) {
+	data, err := os.ReadFile(f.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read credential file: %w", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", fmt.Errorf("credential file %s is empty", f.Path)
+	}
+	return token, nil
+}
+
+// CommandCredential runs a credential helper and uses its standard output as the token,
+// e.g. CommandCredential{Command: []string{"gh", "auth", "token"}}
+type CommandCredential struct {
+	Command []string      // Program and arguments
+	Timeout time.Duration // Maximum run time (default: 30s)
+}
+
+// Token runs the command and returns its trimmed output
+func (c CommandCredential) Token() (string, error) {
+	if len(c.Command) == 0 {
+		return "", fmt.Errorf("credential command cannot be empty")
+	}
+
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = defaultCredentialCommandTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, c.Command[0], c.Command[1:]...)
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("credential command %s failed: %v: %s", c.Command[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	token := strings.TrimSpace(string(output))
+	if token == "" {
+		return "", fmt.Errorf("credential command %s printed no token", c.Command[0])
+	}
+	return token, nil
+}
+
+// KeychainCredential reads the token from the operating system's credential store: the macOS
+// keychain, the Secret Service (via secret-tool) on Linux and BSD, or the Windows Credential Manager
+type KeychainCredential struct {
+	Service string // Keychain service, Secret Service "service" attribute, or Windows credential target name
+	Account string // Account name (optional on Windows)
+}
+
+// Token looks up the token in the OS credential store
+func (k KeychainCredential) Token() (string, error) {
+	if k.Service == "" {
+		return "", fmt.Errorf("keychain credential requires a service name")
+	}
+	token, err := keychainLookup(k.Service, k.Account)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s from the OS keychain: %v", k.Service, err)
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", fmt.Errorf("keychain entry %s is empty", k.Service)
+	}
+	return token, nil
+}
+
+// CredentialConfig describes a credential provider in configuration files
+type CredentialConfig struct {
+	Source  string   `json:"source"`            // "static", "env", "file", "keychain" or "command"
+	Value   string   `json:"value,omitempty"`   // Token for the static source
+	Env     string   `json:"env,omitempty"`     // Variable name for the env source
+	Path    string   `json:"path,omitempty"`    // File for the file source
+	Service string   `json:"service,omitempty"` // Service for the keychain source
+	Account string   `json:"account,omitempty"` // Account for the keychain source
+	Command []string `json:"command,omitempty"` // Program and arguments for the command source
+}
+
+// Provider builds the credential provider described by the configuration
+func (c CredentialConfig) Provider() (CredentialProvider, error) {
+	switch strings.ToLower(c.Source) {
+	case "static":
+		return StaticCredential(c.Value), nil
+	case "env":
+		return EnvCredential{Name: c.Env}, nil
+	case "file":
+		return FileCredential{Path: c.Path}, nil
+	case "keychain":
+		return KeychainCredential{Service: c.Service, Account: c.Account}, nil
+	case "command":
+		return CommandCredential{Command: c.Command}, nil
+	}
+	return nil, fmt.Errorf("unsupported credential source: %q", c.Source)
+}
+
+// Token resolves the configured credential
+func (c CredentialConfig) Token() (string, error) {
+	provider, err := c.Provider()
+	if err != nil {
+		return "", err
+	}
+	return provider.Token()
+}
@@ -0,0 +1,18 @@
+//go:build darwin
+
+package release
+
+import "os/exec"
+
+// keychainLookup reads a generic password from the macOS keychain
+func keychainLookup(service, account string) (string, error) {
+	args := []string{"find-generic-password", "-s", service, "-w"}
+	if account != "" {
+		args = append(args, "-a", account)
+	}
+	output, err := exec.Command("security", args...).Output()
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
@@ -0,0 +1,18 @@
+//go:build !darwin && !windows
+
+package release
+
+import "os/exec"
+
+// keychainLookup reads a secret from the Secret Service (GNOME Keyring, KWallet) using secret-tool
+func keychainLookup(service, account string) (string, error) {
+	args := []string{"lookup", "service", service}
+	if account != "" {
+		args = append(args, "account", account)
+	}
+	output, err := exec.Command("secret-tool", args...).Output()
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
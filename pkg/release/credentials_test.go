@@ -0,0 +1,89 @@
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCredentialProviders(t *testing.T) {
+	t.Setenv("TEST_UPDATER_TOKEN", " env-token\n")
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("file-token\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		provider CredentialProvider
+		want     string
+		wantErr  bool
+	}{
+		{name: "static", provider: StaticCredential("static-token"), want: "static-token"},
+		{name: "empty static", provider: StaticCredential(""), wantErr: true},
+		{name: "env", provider: EnvCredential{Name: "TEST_UPDATER_TOKEN"}, want: "env-token"},
+		{name: "unset env", provider: EnvCredential{Name: "TEST_UPDATER_TOKEN_UNSET"}, wantErr: true},
+		{name: "file", provider: FileCredential{Path: tokenFile}, want: "file-token"},
+		{name: "missing file", provider: FileCredential{Path: tokenFile + ".missing"}, wantErr: true},
+		{name: "command", provider: CommandCredential{Command: []string{"echo", "command-token"}}, want: "command-token"},
+		{name: "failing command", provider: CommandCredential{Command: []string{"false"}}, wantErr: true},
+		{name: "keychain without service", provider: KeychainCredential{}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.provider.Token()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Token() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Token() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCredentialConfig(t *testing.T) {
+	var config CredentialConfig
+	if err := json.Unmarshal([]byte(`{"source": "env", "env": "TEST_UPDATER_TOKEN"}`), &config); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if provider, err := config.Provider(); err != nil || provider != (EnvCredential{Name: "TEST_UPDATER_TOKEN"}) {
+		t.Errorf("Provider() = %v, %v; want env credential", provider, err)
+	}
+
+	if _, err := (CredentialConfig{Source: "vault"}).Provider(); err == nil {
+		t.Error("Expected error for unsupported credential source")
+	}
+}
+
+func TestGitLabRelease_Credentials(t *testing.T) {
+	t.Setenv("TEST_GITLAB_TOKEN", "gitlab-secret")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer gitlab-secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `[{"tag_name": "v1.0.0", "assets": {"links": [{"name": "Linux_x86_64", "direct_asset_url": "https://example.com/download"}]}}]`)
+	}))
+	defer server.Close()
+
+	release := NewGitlabRelease("12345", fileUtils.FileConfig{})
+	release.GitLabConfig.BaseURL = server.URL
+	release.GitLabConfig.Token = ""
+	release.GitLabConfig.Credentials = &CredentialConfig{Source: "env", Env: "TEST_GITLAB_TOKEN"}
+
+	if err := release.GetLatestRelease(); err != nil {
+		t.Fatalf("GetLatestRelease() error = %v", err)
+	}
+
+	release.GitLabConfig.Credentials.Env = "TEST_GITLAB_TOKEN_UNSET"
+	if err := release.GetLatestRelease(); err == nil {
+		t.Error("Expected error when the credential cannot be resolved")
+	}
+}
@@ -0,0 +1,290 @@
+package release
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// jsonSnippetRadius is the number of bytes shown on each side of a decoding error
+const jsonSnippetRadius = 40
+
+// responseTimeLayouts are the timestamp formats accepted when a provider drifts from RFC 3339
+var responseTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+var (
+	timeType        = reflect.TypeOf(time.Time{})
+	unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// decodeResponse decodes a provider API response into v. Unknown fields are ignored, and fields
+// whose type drifted from the expected schema, such as IDs sent as strings or timestamps in another
+// format, are converted or left at their zero value with a warning instead of failing the whole
+// response. Malformed JSON fails with a snippet of the offending input.
+func decodeResponse(provider string, body []byte, v any) error {
+	err := json.Unmarshal(body, v)
+	if err == nil {
+		return nil
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return fmt.Errorf("error decoding response from %s: %v near %s", provider, err, jsonSnippet(body, syntaxErr.Offset))
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var raw any
+	if decodeErr := decoder.Decode(&raw); decodeErr != nil {
+		return fmt.Errorf("error decoding response from %s: %v near %s", provider, err, jsonSnippet(body, decoder.InputOffset()))
+	}
+	target := reflect.ValueOf(v).Elem()
+	var drifted []string
+	value := coerceJSON(raw, target.Type(), "", &drifted)
+	if value == nil && raw != nil {
+		// The response is not the expected object or list at all, e.g. an error message
+		return fmt.Errorf("error decoding response from %s: %v: %s", provider, err, jsonSnippet(body, 0))
+	}
+	coerced, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("error decoding response from %s: %v", provider, err)
+	}
+	target.Set(reflect.Zero(target.Type()))
+	if err := json.Unmarshal(coerced, v); err != nil {
+		return fmt.Errorf("error decoding response from %s: %v near %s", provider, err, jsonSnippet(body, 0))
+	}
+	if len(drifted) > 0 {
+		log.Printf("Warning: %s response does not match the expected schema, converted or ignored: %s",
+			provider, strings.Join(drifted, ", "))
+	}
+	return nil
+}
+
+// decodeReleaseList streams a JSON list of releases, decoding and handing one release at a time to
+// visit, so the whole list is never held in memory
+func decodeReleaseList[T any](provider string, r io.Reader, visit func(*T)) error {
+	decoder := json.NewDecoder(r)
+	token, err := decoder.Token()
+	if err != nil {
+		return fmt.Errorf("error decoding response from %s: %w", provider, err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("error decoding response from %s: expected a list of releases, got %v", provider, token)
+	}
+	for decoder.More() {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return fmt.Errorf("error decoding response from %s: %w", provider, err)
+		}
+		var item T
+		if err := decodeResponse(provider, raw, &item); err != nil {
+			return err
+		}
+		visit(&item)
+	}
+	if _, err := decoder.Token(); err != nil {
+		return fmt.Errorf("error decoding response from %s: %w", provider, err)
+	}
+	return nil
+}
+
+// decodeRelease decodes a single release response and normalizes it into the shared release model.
+// A release without a tag fails, because the provider's response schema changed.
+func decodeRelease[T any, R interface {
+	*T
+	ReleaseResponse
+}](provider string, body []byte) (*ReleaseInfo, error) {
+	var response T
+	if err := decodeResponse(provider, body, &response); err != nil {
+		return nil, err
+	}
+	info := R(&response).ToReleaseInfo()
+	if err := requireField(provider, "tag_name", info.Version, body); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// requireField fails when a field the updater depends on is missing, which means the provider's
+// response schema changed
+func requireField(provider, field, value string, body []byte) error {
+	if value != "" {
+		return nil
+	}
+	return fmt.Errorf("%s response has no %s, the API schema may have changed: %s", provider, field, jsonSnippet(body, 0))
+}
+
+// jsonSnippet returns the input around offset for error messages
+func jsonSnippet(body []byte, offset int64) string {
+	start := max(int(offset)-jsonSnippetRadius, 0)
+	end := min(int(offset)+jsonSnippetRadius, len(body))
+	if start > end {
+		start = end
+	}
+	return strconv.Quote(string(body[start:end]))
+}
+
+// coerceJSON converts a generically decoded JSON value to the shape of t, recording the path of
+// every value that had to be converted or dropped
+func coerceJSON(value any, t reflect.Type, path string, drifted *[]string) any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if value == nil {
+		return nil
+	}
+	drop := func() any {
+		*drifted = append(*drifted, displayPath(path))
+		return nil
+	}
+	convert := func(converted any) any {
+		*drifted = append(*drifted, displayPath(path))
+		return converted
+	}
+
+	if t == timeType {
+		text, ok := value.(string)
+		if !ok {
+			return drop()
+		}
+		for i, layout := range responseTimeLayouts {
+			if parsed, err := time.Parse(layout, text); err == nil {
+				if i == 0 {
+					return parsed.Format(time.RFC3339Nano)
+				}
+				return convert(parsed.Format(time.RFC3339Nano))
+			}
+		}
+		return drop()
+	}
+	if reflect.PointerTo(t).Implements(unmarshalerType) {
+		return value
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := value.(map[string]any)
+		if !ok {
+			return drop()
+		}
+		for key, field := range object {
+			if structField, ok := jsonField(t, key); ok {
+				object[key] = coerceJSON(field, structField.Type, path+"."+key, drifted)
+			}
+		}
+		return object
+	case reflect.Map:
+		object, ok := value.(map[string]any)
+		if !ok {
+			return drop()
+		}
+		for key, field := range object {
+			object[key] = coerceJSON(field, t.Elem(), path+"."+key, drifted)
+		}
+		return object
+	case reflect.Slice, reflect.Array:
+		list, ok := value.([]any)
+		if !ok {
+			return drop()
+		}
+		for i, item := range list {
+			list[i] = coerceJSON(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), drifted)
+		}
+		return list
+	case reflect.String:
+		switch typed := value.(type) {
+		case string:
+			return typed
+		case json.Number:
+			return convert(typed.String())
+		case bool:
+			return convert(strconv.FormatBool(typed))
+		}
+		return drop()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var text string
+		switch typed := value.(type) {
+		case json.Number:
+			text = typed.String()
+		case string:
+			text = strings.TrimSpace(typed)
+		default:
+			return drop()
+		}
+		if number, err := strconv.ParseInt(text, 10, 64); err == nil {
+			if _, isNumber := value.(json.Number); isNumber {
+				return json.Number(text)
+			}
+			return convert(json.Number(strconv.FormatInt(number, 10)))
+		}
+		if number, err := strconv.ParseFloat(text, 64); err == nil {
+			return convert(json.Number(strconv.FormatInt(int64(number), 10)))
+		}
+		return drop()
+	case reflect.Float32, reflect.Float64:
+		switch typed := value.(type) {
+		case json.Number:
+			return typed
+		case string:
+			if _, err := strconv.ParseFloat(strings.TrimSpace(typed), 64); err == nil {
+				return convert(json.Number(strings.TrimSpace(typed)))
+			}
+		}
+		return drop()
+	case reflect.Bool:
+		switch typed := value.(type) {
+		case bool:
+			return typed
+		case string:
+			if parsed, err := strconv.ParseBool(strings.TrimSpace(typed)); err == nil {
+				return convert(parsed)
+			}
+		}
+		return drop()
+	}
+	return value
+}
+
+// jsonField finds the struct field a JSON key decodes into, matching names case-insensitively
+// like encoding/json
+func jsonField(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if strings.EqualFold(name, key) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// displayPath formats a JSON path for warnings, e.g. "assets[0].size"
+func displayPath(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return strings.TrimPrefix(path, ".")
+}
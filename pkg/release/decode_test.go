@@ -0,0 +1,122 @@
+package release
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeResponse_SchemaDrift(t *testing.T) {
+	body := []byte(`{
+		"id": "42",
+		"tag_name": "v1.2.0",
+		"draft": "false",
+		"prerelease": 1,
+		"published_at": "2024-03-01 12:00:00",
+		"new_field": {"nested": true},
+		"assets": [
+			{"id": 7.0, "name": "myapp-Linux_x86_64.tar.gz", "size": "1024", "browser_download_url": "https://example.com/a", "label": 5},
+			{"id": 8, "name": "myapp-Darwin_arm64.tar.gz", "size": null, "created_at": "yesterday"}
+		]
+	}`)
+
+	var response GithubReleaseResponse
+	if err := decodeResponse("GitHub", body, &response); err != nil {
+		t.Fatalf("decodeResponse failed: %v", err)
+	}
+	if response.ID != 42 || response.TagName != "v1.2.0" || response.Prerelease {
+		t.Errorf("Unexpected release fields: %+v", response)
+	}
+	if want := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC); !response.PublishedAt.Equal(want) {
+		t.Errorf("Expected published_at %v, got %v", want, response.PublishedAt)
+	}
+	if len(response.Assets) != 2 {
+		t.Fatalf("Expected 2 assets, got %d", len(response.Assets))
+	}
+	first := response.Assets[0]
+	if first.ID != 7 || first.Size != 1024 || first.Label != "5" || first.BrowserDownloadUrl != "https://example.com/a" {
+		t.Errorf("Unexpected first asset: %+v", first)
+	}
+	if second := response.Assets[1]; second.Size != 0 || !second.CreatedAt.IsZero() || second.Name != "myapp-Darwin_arm64.tar.gz" {
+		t.Errorf("Expected unconvertible fields of the second asset to be left empty, got %+v", second)
+	}
+}
+
+func TestDecodeResponse_Errors(t *testing.T) {
+	var response GithubReleaseResponse
+	err := decodeResponse("GitHub", []byte(`{"tag_name": "v1.0.0", "assets": [}`), &response)
+	if err == nil || !strings.Contains(err.Error(), `[}"`) {
+		t.Errorf("Expected a syntax error with the offending snippet, got %v", err)
+	}
+
+	var releases []GitlabReleaseResponse
+	if err := decodeResponse("GitLab", []byte(`{"message": "404 Not Found"}`), &releases); err == nil {
+		t.Error("Expected an object where a list is expected to fail")
+	}
+
+	err = requireField("GitHub", "tag_name", "", []byte(`{"tagName": "v1.0.0"}`))
+	if err == nil || !strings.Contains(err.Error(), "tagName") {
+		t.Errorf("Expected a missing field error with the response snippet, got %v", err)
+	}
+}
+
+func TestCheckGitLabAPIVersion(t *testing.T) {
+	tests := map[string]bool{
+		"https://gitlab.com/api/v4":                 true,
+		"https://gitlab.example.com/gitlab/api/v4/": true,
+		"https://gitlab.example.com":                true,
+		"https://gitlab.example.com/api/v3":         false,
+		"https://gitlab.example.com/api/v5":         false,
+	}
+	for baseURL, valid := range tests {
+		if err := checkGitLabAPIVersion(baseURL); (err == nil) != valid {
+			t.Errorf("checkGitLabAPIVersion(%s) = %v, want valid %v", baseURL, err, valid)
+		}
+	}
+}
+
+func FuzzDecodeGithubRelease(f *testing.F) {
+	f.Add([]byte(`{"tag_name": "v1.0.0", "assets": [{"id": 1, "name": "a", "size": 10}]}`))
+	f.Add([]byte(`{"id": "1", "published_at": "2024-01-01", "assets": [{"size": "12"}]}`))
+	f.Add([]byte(`[{"tag_name": "v1"}]`))
+	f.Add([]byte(`{"assets": {"id": 1}}`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var response GithubReleaseResponse
+		if decodeResponse("GitHub", data, &response) == nil {
+			response.ToReleaseInfo()
+		}
+		var responses []GithubReleaseResponse
+		decodeResponse("GitHub", data, &responses)
+	})
+}
+
+func FuzzDecodeGitlabRelease(f *testing.F) {
+	f.Add([]byte(`{"tag_name": "v1.0.0", "assets": {"links": [{"id": 1, "name": "a", "direct_asset_url": "https://example.com"}]}}`))
+	f.Add([]byte(`[{"tag_name": "v1", "released_at": "2024-01-01T00:00:00Z", "upcoming_release": "false"}]`))
+	f.Add([]byte(`{"assets": {"links": {"id": "x"}}}`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var response GitlabReleaseResponse
+		if decodeResponse("GitLab", data, &response) == nil {
+			response.ToReleaseInfo()
+		}
+		var responses []GitlabReleaseResponse
+		decodeResponse("GitLab", data, &responses)
+	})
+}
+
+func TestDecodeReleaseList(t *testing.T) {
+	var tags []string
+	err := decodeReleaseList("GitHub", strings.NewReader(`[{"tag_name": "v2", "id": "2"}, {"tag_name": "v1"}]`),
+		func(response *GithubReleaseResponse) { tags = append(tags, response.TagName) })
+	if err != nil || strings.Join(tags, ",") != "v2,v1" {
+		t.Errorf("Expected both releases in order, got %v (%v)", tags, err)
+	}
+
+	visit := func(*GithubReleaseResponse) {}
+	if err := decodeReleaseList("GitHub", strings.NewReader(`{"message": "Not Found"}`), visit); err == nil {
+		t.Error("Expected an object instead of a list to fail")
+	}
+	if err := decodeReleaseList("GitHub", strings.NewReader(`[{"tag_name": "v1"}, {"tag_`), visit); err == nil {
+		t.Error("Expected a truncated list to fail")
+	}
+}
@@ -0,0 +1,168 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// DefaultDeltaPatchPattern is the patch asset name used when DeltaConfig.PatchPattern is empty
+const DefaultDeltaPatchPattern = "{project}-{from}-to-{to}.bspatch"
+
+// DeltaConfig enables delta updates: instead of the full asset, a bsdiff patch from the installed
+// version to the new one is downloaded and applied to the installed binary. If the patch is missing,
+// fails to apply or cannot be verified, the full asset is downloaded instead.
+type DeltaConfig struct {
+	Enabled      bool   `json:"enabled"`       // Try delta updates before downloading the full asset
+	FromVersion  string `json:"from_version"`  // Installed version to patch (defaults to the version the local symlink points to)
+	PatchPattern string `json:"patch_pattern"` // Patch asset name with {project}, {from}, {to}, {os} and {arch} placeholders
+}
+
+// patchAssetNames returns the candidate patch asset names, with and without a "v" prefix on versions
+func (d DeltaConfig) patchAssetNames(project, from, to string) []string {
+	pattern := d.PatchPattern
+	if pattern == "" {
+		pattern = DefaultDeltaPatchPattern
+	}
+
+	expand := func(from, to string) string {
+		name := strings.ReplaceAll(pattern, "{project}", project)
+		name = strings.ReplaceAll(name, "{os}", runtime.GOOS)
+		name = strings.ReplaceAll(name, "{arch}", runtime.GOARCH)
+		name = strings.ReplaceAll(name, "{from}", from)
+		return strings.ReplaceAll(name, "{to}", to)
+	}
+
+	names := []string{expand(from, to)}
+	if trimmed := expand(strings.TrimPrefix(from, "v"), strings.TrimPrefix(to, "v")); trimmed != names[0] {
+		names = append(names, trimmed)
+	}
+	return names
+}
+
+// applyDeltaUpdate builds the binary for info.Version by patching the installed binary.
+// It returns the path of the patched binary inside a temporary directory the caller must remove.
+// The patched binary is verified against a "<patch>.sha256" asset holding its digest or, without
+// one, against digest, the provider digest of the full asset when it is installed unchanged.
+// Patches that can be verified by neither are not applied.
+func applyDeltaUpdate(info *ReleaseInfo, delta DeltaConfig, fileConfig fileUtils.FileConfig, token, digest string) (string, error) {
+	if info == nil {
+		return "", fmt.Errorf("no release information available")
+	}
+
+	from := delta.FromVersion
+	if from == "" {
+		installed, err := fileUtils.CurrentInstalledVersion(fileConfig)
+		if err != nil {
+			return "", err
+		}
+		from = installed
+	}
+	if from == info.Version {
+		return "", fmt.Errorf("version %s is already installed", from)
+	}
+
+	oldBinary := fileUtils.GetVersionedBinaryPath(fileConfig, from)
+	if !fileUtils.FileExists(oldBinary) {
+		return "", fmt.Errorf("installed binary for version %s not found at %s", from, oldBinary)
+	}
+
+	project := fileConfig.ProjectName
+	if project == "" {
+		project = fileConfig.BinaryName
+	}
+
+	var patchAsset AssetInfo
+	found := false
+	for _, name := range delta.patchAssetNames(project, from, info.Version) {
+		if patchAsset, found = info.FindAsset(name); found {
+			break
+		}
+	}
+	if !found {
+		return "", fmt.Errorf("no patch from %s to %s found in release assets", from, info.Version)
+	}
+	checksumAsset, hasChecksum := info.FindAsset(patchAsset.Name + ".sha256")
+	if !hasChecksum && !verifiableDigest(digest) {
+		return "", fmt.Errorf("no checksum or digest to verify the patch from %s to %s against", from, info.Version)
+	}
+
+	stagingDir := fileUtils.GetStagingDirectory(fileConfig)
+	if err := fsys().MkdirAll(stagingDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create staging directory: %v", err)
+	}
+	workDir, err := fsys().MkdirTemp(stagingDir, "go-binary-updater-delta-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create delta work directory: %v", err)
+	}
+
+	patchedPath, err := downloadAndApplyPatch(fileConfig.Context(), patchAsset, checksumAsset, digest, oldBinary, workDir, token)
+	if err != nil {
+		fsys().RemoveAll(workDir)
+		return "", err
+	}
+	return patchedPath, nil
+}
+
+// downloadAndApplyPatch downloads and applies the patch, then verifies the patched binary against
+// checksumAsset when it is set and against digest otherwise
+func downloadAndApplyPatch(ctx context.Context, patchAsset, checksumAsset AssetInfo, digest, oldBinary, workDir, token string) (string, error) {
+	patchPath := filepath.Join(workDir, patchAsset.Name)
+	link, assetToken := assetDownload(patchAsset, token)
+	if err := fileUtils.DownloadFileWithContext(ctx, link, patchPath, assetToken); err != nil {
+		return "", fmt.Errorf("failed to download patch %s: %v", patchAsset.Name, err)
+	}
+
+	patchedPath := filepath.Join(workDir, "patched-binary")
+	if err := fileUtils.ApplyBSDiffPatch(oldBinary, patchPath, patchedPath); err != nil {
+		return "", err
+	}
+
+	if checksumAsset.Name == "" {
+		if err := verifyAssetDigest(patchedPath, digest); err != nil {
+			return "", fmt.Errorf("patched binary failed verification: %w", err)
+		}
+		return patchedPath, nil
+	}
+
+	checksumPath := filepath.Join(workDir, checksumAsset.Name)
+	link, assetToken = assetDownload(checksumAsset, token)
+	if err := fileUtils.DownloadFileWithContext(ctx, link, checksumPath, assetToken); err != nil {
+		return "", fmt.Errorf("failed to download patch checksum: %v", err)
+	}
+	content, err := fsys().ReadFile(checksumPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read patch checksum: %v", err)
+	}
+	fields := strings.Fields(string(content))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("patch checksum %s is empty", checksumAsset.Name)
+	}
+	if err := fileUtils.VerifyFileSHA256(patchedPath, fields[0]); err != nil {
+		return "", fmt.Errorf("patched binary failed verification: %v", err)
+	}
+	return patchedPath, nil
+}
+
+// assetDownload returns the URL and token to download an asset with. Authenticated downloads use
+// the API URL, which serves assets of private repositories as application/octet-stream; browser
+// download URLs are requested without the token.
+func assetDownload(asset AssetInfo, token string) (string, string) {
+	if token != "" && asset.APIURL != "" {
+		return asset.APIURL, token
+	}
+	return asset.URL, ""
+}
+
+// installPatchedBinary installs a binary produced by a delta update and removes its work directory
+func installPatchedBinary(fileConfig fileUtils.FileConfig, patchedPath, version string) error {
+	defer fsys().RemoveAll(filepath.Dir(patchedPath))
+
+	config := fileConfig
+	config.IsDirectBinary = true
+	config.IsCompressedBinary = false
+	return fileUtils.InstallFromFile(config, patchedPath, version, nil)
+}
@@ -0,0 +1,111 @@
+package release
+
+import (
+	"encoding/base64"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testDeltaPatch turns "#!/bin/sh\necho v1\n" into "#!/bin/sh\necho v2\necho patched\n" (bsdiff 4.x format)
+const testDeltaPatch = "QlNESUZGNDAsAAAAAAAAACgAAAAAAAAAHwAAAAAAAABCWmg5MUFZJlNZPCWvXwAABeAASAoQACAAISmm0GaBfArhdyRThQkDwlr18EJaaDkxQVkmU1lg6hk6AAAAwABgBCAAMMwJNModhdyRThQkGDqGToBCWmg5MUFZJlNZiWY2BQAAA1GAABBAAC5AxAAgADEAMCADal8hNgNCPF3JFOFCQiWY2BQ="
+
+const testDeltaPatchedSHA256 = "e559736c2c231fd9d77b698c8a40749b736dd6a3a399781cecbc8d4a3339bf5d"
+
+func TestGithubRelease_DeltaUpdate(t *testing.T) {
+	patch, _ := base64.StdEncoding.DecodeString(testDeltaPatch)
+
+	testCases := []struct {
+		name          string
+		withPatch     bool
+		checksum      string // Content of the patch's .sha256 asset, which is omitted when empty
+		digest        string // Digest reported for the full asset
+		expectDelta   bool
+		expectContent string
+	}{
+		{"patch applied", true, testDeltaPatchedSHA256, "", true, "#!/bin/sh\necho v2\necho patched\n"},
+		{"patch verified by asset digest", true, "", "sha256:" + testDeltaPatchedSHA256, true, "#!/bin/sh\necho v2\necho patched\n"},
+		{"checksum mismatch falls back", true, "0000", "", false, "full download"},
+		{"unverifiable patch falls back", true, "", "", false, "full download"},
+		{"missing patch falls back", false, "", "", false, "full download"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/patch":
+					w.Write(patch)
+				case "/patch.sha256":
+					w.Write([]byte(tc.checksum + "  myapp-v1.0.0-to-v2.0.0.bspatch\n"))
+				case "/full":
+					w.Write([]byte("full download"))
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer server.Close()
+
+			tempDir := t.TempDir()
+			fileConfig := fileUtils.FileConfig{
+				BaseBinaryDirectory:    tempDir,
+				VersionedDirectoryName: "versions",
+				BinaryName:             "myapp",
+				ProjectName:            "myapp",
+				IsDirectBinary:         true,
+				CreateLocalSymlink:     true,
+				SourceArchivePath:      filepath.Join(tempDir, "download"),
+			}
+
+			// Install v1.0.0 so there is something to patch
+			oldBinary := filepath.Join(tempDir, "old")
+			os.WriteFile(oldBinary, []byte("#!/bin/sh\necho v1\n"), 0755)
+			if err := fileUtils.InstallFromFile(fileConfig, oldBinary, "v1.0.0", nil); err != nil {
+				t.Fatalf("Failed to install initial version: %v", err)
+			}
+
+			info := &ReleaseInfo{
+				Version: "v2.0.0",
+				Assets:  []AssetInfo{{Name: "myapp-Linux_x86_64", URL: server.URL + "/full", Digest: tc.digest}},
+			}
+			if tc.withPatch {
+				info.Assets = append(info.Assets, AssetInfo{Name: "myapp-v1.0.0-to-v2.0.0.bspatch", URL: server.URL + "/patch"})
+			}
+			if tc.checksum != "" {
+				info.Assets = append(info.Assets, AssetInfo{Name: "myapp-v1.0.0-to-v2.0.0.bspatch.sha256", URL: server.URL + "/patch.sha256"})
+			}
+
+			release := NewGithubRelease("owner/repo", fileConfig)
+			release.Source = &fakeAssetSource{release: info}
+			release.AssetMatchingConfig.Delta.Enabled = true
+
+			if err := release.DownloadLatestRelease(); err != nil {
+				t.Fatalf("DownloadLatestRelease failed: %v", err)
+			}
+			if release.DeltaApplied != tc.expectDelta {
+				t.Errorf("Expected DeltaApplied=%v, got %v", tc.expectDelta, release.DeltaApplied)
+			}
+			if err := release.InstallLatestRelease(); err != nil {
+				t.Fatalf("InstallLatestRelease failed: %v", err)
+			}
+
+			content, err := os.ReadFile(fileUtils.GetVersionedBinaryPath(fileConfig, "v2.0.0"))
+			if err != nil {
+				t.Fatalf("Failed to read installed binary: %v", err)
+			}
+			if string(content) != tc.expectContent {
+				t.Errorf("Expected installed content %q, got %q", tc.expectContent, content)
+			}
+		})
+	}
+}
+
+func TestDeltaConfig_PatchAssetNames(t *testing.T) {
+	names := DeltaConfig{}.patchAssetNames("tool", "v1.2.3", "v1.2.4")
+	if len(names) != 2 || names[0] != "tool-v1.2.3-to-v1.2.4.bspatch" || names[1] != "tool-1.2.3-to-1.2.4.bspatch" {
+		t.Errorf("Unexpected patch asset names: %v", names)
+	}
+}
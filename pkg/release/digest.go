@@ -0,0 +1,127 @@
+package release
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"hash"
+	"io"
+	"log"
+	"path/filepath"
+	"strings"
+)
+
+// digestAlgorithms are the provider digest algorithms downloads can be verified against
+var digestAlgorithms = map[string]func() hash.Hash{
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+// parseAssetDigest splits a provider digest such as "sha256:<hex>" into its algorithm and
+// lowercase hex value
+func parseAssetDigest(digest string) (string, string, error) {
+	algorithm, value, ok := strings.Cut(strings.TrimSpace(digest), ":")
+	if !ok || algorithm == "" || value == "" {
+		return "", "", fmt.Errorf("malformed asset digest %q", digest)
+	}
+	if _, err := hex.DecodeString(value); err != nil {
+		return "", "", fmt.Errorf("malformed asset digest %q", digest)
+	}
+	return strings.ToLower(algorithm), strings.ToLower(value), nil
+}
+
+// assetDigestSHA256 returns the hex SHA-256 of a provider digest, or "" when the digest uses
+// another algorithm or is missing
+func assetDigestSHA256(digest string) string {
+	algorithm, value, err := parseAssetDigest(digest)
+	if err != nil || algorithm != "sha256" {
+		return ""
+	}
+	return value
+}
+
+// downloadVerified runs download and verifies the file at destination against the digest the
+// provider reported for it. SHA-256 digests are passed to download, which verifies the file while
+// keying the download cache with them, so it is hashed only once; other algorithms are verified after.
+func downloadVerified(destination, digest string, download func(checksum string) error) error {
+	checksum := assetDigestSHA256(digest)
+	if err := download(checksum); err != nil {
+		return err
+	}
+	if checksum != "" {
+		return nil
+	}
+	return verifyAssetDigest(destination, digest)
+}
+
+// verifiableDigest reports whether digest is well-formed and uses a supported algorithm
+func verifiableDigest(digest string) bool {
+	algorithm, _, err := parseAssetDigest(digest)
+	_, supported := digestAlgorithms[algorithm]
+	return err == nil && supported
+}
+
+// verifyAssetDigest checks a downloaded file against the digest its provider reported. Assets
+// without a digest pass, and malformed digests or digests using unknown algorithms are skipped
+// with a warning. A file that does not match is removed.
+func verifyAssetDigest(path, digest string) error {
+	if digest == "" {
+		return nil
+	}
+	algorithm, expected, err := parseAssetDigest(digest)
+	if err != nil {
+		log.Printf("Warning: skipping verification of %s: %v", filepath.Base(path), err)
+		return nil
+	}
+	newHash, ok := digestAlgorithms[algorithm]
+	if !ok {
+		log.Printf("Warning: skipping verification of %s: unsupported digest algorithm %s", filepath.Base(path), algorithm)
+		return nil
+	}
+
+	file, err := fsys().Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to verify %s: %v", filepath.Base(path), err)
+	}
+	h := newHash()
+	_, err = io.Copy(h, file)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("failed to verify %s: %v", filepath.Base(path), err)
+	}
+	if actual := hex.EncodeToString(h.Sum(nil)); actual != expected {
+		fsys().Remove(path)
+		return &fileUtils.ChecksumError{Name: filepath.Base(path), Algorithm: algorithm, Expected: expected, Actual: actual}
+	}
+	return nil
+}
+
+// deltaDigest returns the digest to verify a delta-patched binary against: the digest of the
+// selected asset when it is installed unchanged, otherwise ""
+func (g *GithubRelease) deltaDigest() string {
+	if !g.Config.IsDirectBinary || g.Config.IsCompressedBinary || g.Config.IsAppImage {
+		return ""
+	}
+	return g.assetDigest()
+}
+
+// assetDigest returns the digest GitHub reported for the selected asset, if any
+func (g *GithubRelease) assetDigest() string {
+	if g.Info == nil {
+		return ""
+	}
+	asset, _ := g.Info.FindAsset(g.AssetName)
+	return asset.Digest
+}
+
+// assetDigest returns the digest the asset source reported for the selected asset, if any. The
+// GitLab API reports none, but custom asset sources may.
+func (r *GitLabRelease) assetDigest() string {
+	if r.Info == nil {
+		return ""
+	}
+	asset, _ := r.Info.FindAsset(r.AssetName)
+	return asset.Digest
+}
@@ -0,0 +1,113 @@
+package release
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVerifyAssetDigest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "asset")
+	write := func() {
+		if err := os.WriteFile(path, []byte("hello"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	const sha256Hello = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+
+	tests := []struct {
+		name    string
+		digest  string
+		wantErr string
+	}{
+		{"no digest", "", ""},
+		{"sha256", "sha256:" + sha256Hello, ""},
+		{"uppercase", "SHA256:" + strings.ToUpper(sha256Hello), ""},
+		{"sha512", "sha512:9b71d224bd62f3785d96d46ad3ea3d73319bfbc2890caadae2dff72519673ca72323c3d99ba5c11d7c7acc6e14b8c5da0c4663475c2e5c3adef46f73bcdec043", ""},
+		{"unknown algorithm", "md5:5d41402abc4b2a76b9719d911017c592", ""},
+		{"mismatch", "sha256:" + strings.Repeat("0", 64), "digest mismatch"},
+		{"malformed", "sha256", ""},
+		{"not hex", "sha256:xyz", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			write()
+			err := verifyAssetDigest(path, tt.digest)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected %q to verify, got %v", tt.digest, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected an error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	write()
+	verifyAssetDigest(path, "sha256:"+strings.Repeat("0", 64))
+	if fileUtils.FileExists(path) {
+		t.Error("Expected a mismatching download to be removed")
+	}
+}
+
+func TestGithubRelease_DownloadVerifiesAssetDigest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("release archive"))
+	}))
+	defer server.Close()
+	checksum := fmt.Sprintf("sha256:%x", sha256.Sum256([]byte("release archive")))
+
+	tests := []struct {
+		name    string
+		digest  string
+		wantErr bool
+	}{
+		{"matching digest", checksum, false},
+		{"missing digest", "", false},
+		{"mismatching digest", "sha256:" + strings.Repeat("0", 64), true},
+		{"malformed digest", "sha256:xyz", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := fileUtils.FileConfig{
+				BaseBinaryDirectory:    filepath.Join(t.TempDir(), "bin"),
+				VersionedDirectoryName: "versions",
+				BinaryName:             "myapp",
+				ProjectName:            "myapp",
+				StagingDirectory:       filepath.Join(t.TempDir(), "staging"),
+			}
+			release := NewGithubRelease("owner/repo", config)
+			release.Source = &fakeAssetSource{release: &ReleaseInfo{
+				Version: "v1.0.0",
+				Assets: []AssetInfo{{
+					Name:   "myapp-Linux_x86_64.tar.gz",
+					URL:    server.URL + "/myapp-Linux_x86_64.tar.gz",
+					Digest: tt.digest,
+				}},
+			}}
+
+			err := release.DownloadLatestRelease()
+			if tt.wantErr {
+				if !errors.Is(err, fileUtils.ErrChecksumMismatch) {
+					t.Fatalf("Expected a digest mismatch, got %v", err)
+				}
+				if fileUtils.FileExists(release.getTempSourceArchivePath()) {
+					t.Error("Expected the mismatching download to be removed")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DownloadLatestRelease failed: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package release
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// MatchCandidate describes how the asset matcher judged one asset
+type MatchCandidate struct {
+	Name     string // Asset name
+	Score    int    // Platform score (flexible and hybrid strategies only)
+	Scored   bool   // False if the strategy does not score assets
+	Excluded string // Exclude pattern that removed the asset, if any
+	Matched  string // Pattern the asset matched (standard and custom strategies only)
+	Winner   bool   // True for the asset FindBestMatch selects
+}
+
+// MatchExplanation is the result of a matching dry run
+type MatchExplanation struct {
+	Platform   string           // Platform matched against, e.g. "linux/amd64"
+	Strategy   string           // Strategy name
+	Candidates []MatchCandidate // Winner first, then by descending score, excluded assets last
+	Winner     string           // Selected asset or CDN URL (empty if nothing matched)
+	Err        error            // Why nothing matched
+	Warnings   []string         // Non-fatal notes, e.g. a Rosetta fallback
+}
+
+// ExplainMatch runs the asset matcher without downloading anything and returns a table of the
+// candidates, their scores and the winner, for use in "doctor" commands debugging asset selection
+func ExplainMatch(config AssetMatchingConfig, assetNames []string) string {
+	return NewAssetMatcher(config).Explain(assetNames).String()
+}
+
+// Explain runs a matching dry run and reports how every asset was judged
+func (am *AssetMatcher) Explain(assetNames []string) *MatchExplanation {
+	explanation := &MatchExplanation{
+		Platform: am.os + "/" + am.arch,
+		Strategy: am.config.Strategy.String(),
+	}
+	explanation.Winner, explanation.Err = am.FindBestMatch(assetNames)
+	explanation.Warnings = am.Warnings()
+
+	osAliases := am.getOSAliases(am.os)
+	archAliases := am.getArchAliases(am.arch)
+	for _, name := range assetNames {
+		candidate := MatchCandidate{Name: name, Excluded: am.excludedBy(name), Winner: name == explanation.Winner}
+		if candidate.Excluded == "" {
+			switch am.config.Strategy {
+			case StandardStrategy:
+				searchKey := fmt.Sprintf("%s_%s", strings.Title(strings.ToLower(am.os)), MapArch(am.arch))
+				if strings.Contains(name, searchKey) {
+					candidate.Matched = searchKey
+				}
+			case CustomStrategy:
+				candidate.Matched = am.customPatternFor(name)
+			case CDNStrategy:
+			default:
+				candidate.Score = am.scoreAsset(name, osAliases, archAliases)
+				candidate.Scored = true
+			}
+		}
+		explanation.Candidates = append(explanation.Candidates, candidate)
+	}
+
+	sort.SliceStable(explanation.Candidates, func(i, j int) bool {
+		a, b := explanation.Candidates[i], explanation.Candidates[j]
+		if a.Winner != b.Winner {
+			return a.Winner
+		}
+		if (a.Excluded == "") != (b.Excluded == "") {
+			return a.Excluded == ""
+		}
+		return a.Score > b.Score
+	})
+	return explanation
+}
+
+// String formats the explanation as a table
+func (e *MatchExplanation) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Platform: %s, strategy: %s\n\n", e.Platform, e.Strategy)
+
+	if len(e.Candidates) > 0 {
+		w := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ASSET\tSCORE\tRESULT")
+		for _, candidate := range e.Candidates {
+			score := "-"
+			if candidate.Scored {
+				score = fmt.Sprintf("%d", candidate.Score)
+			}
+			var result []string
+			if candidate.Winner {
+				result = append(result, "selected")
+			}
+			if candidate.Excluded != "" {
+				result = append(result, fmt.Sprintf("excluded by %q", candidate.Excluded))
+			}
+			if candidate.Matched != "" {
+				result = append(result, fmt.Sprintf("matches %q", candidate.Matched))
+			}
+			if candidate.Scored && candidate.Score <= 0 && !candidate.Winner {
+				result = append(result, "not a platform match")
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", candidate.Name, score, strings.Join(result, ", "))
+		}
+		w.Flush()
+		b.WriteString("\n")
+	}
+
+	if e.Err != nil {
+		fmt.Fprintf(&b, "No match: %v\n", e.Err)
+	} else {
+		fmt.Fprintf(&b, "Winner: %s\n", e.Winner)
+	}
+	for _, warning := range e.Warnings {
+		fmt.Fprintf(&b, "Warning: %s\n", warning)
+	}
+	return b.String()
+}
+
+// excludedBy returns the exclude pattern matching the asset, or an empty string
+func (am *AssetMatcher) excludedBy(assetName string) string {
+	name := am.foldCase(assetName)
+	for _, excludePattern := range am.compiledPatterns().exclude {
+		if excludePattern.regex.MatchString(name) {
+			return excludePattern.source
+		}
+	}
+	return ""
+}
+
+// customPatternFor returns the first custom pattern matching the asset, or an empty string
+func (am *AssetMatcher) customPatternFor(assetName string) string {
+	for _, pattern := range am.compiledPatterns().custom {
+		if pattern.regex.MatchString(assetName) {
+			return pattern.source
+		}
+	}
+	return ""
+}
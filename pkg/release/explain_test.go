@@ -0,0 +1,62 @@
+package release
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAssetMatcher_Explain(t *testing.T) {
+	config := DefaultAssetMatchingConfig()
+	config.ExcludePatterns = []string{`\.sha256$`}
+
+	matcher := NewAssetMatcher(config)
+	matcher.os = "linux"
+	matcher.arch = "amd64"
+
+	assets := []string{
+		"tool_darwin_arm64.tar.gz",
+		"tool_linux_amd64.tar.gz.sha256",
+		"tool_linux_amd64.tar.gz",
+	}
+	explanation := matcher.Explain(assets)
+
+	if explanation.Winner != "tool_linux_amd64.tar.gz" || explanation.Err != nil {
+		t.Fatalf("Unexpected winner %q (err: %v)", explanation.Winner, explanation.Err)
+	}
+	if len(explanation.Candidates) != len(assets) {
+		t.Fatalf("Expected %d candidates, got %d", len(assets), len(explanation.Candidates))
+	}
+
+	winner := explanation.Candidates[0]
+	if !winner.Winner || !winner.Scored || winner.Score <= 0 {
+		t.Errorf("Expected the scored winner first, got %+v", winner)
+	}
+	if wrong := explanation.Candidates[1]; wrong.Name != "tool_darwin_arm64.tar.gz" || wrong.Score >= winner.Score {
+		t.Errorf("Expected the lower-scored darwin asset second, got %+v", wrong)
+	}
+	if excluded := explanation.Candidates[2]; excluded.Excluded != `\.sha256$` {
+		t.Errorf("Expected the checksum to be excluded last, got %+v", excluded)
+	}
+
+	table := explanation.String()
+	for _, want := range []string{"Platform: linux/amd64, strategy: flexible", "ASSET", "selected",
+		`excluded by "\\.sha256$"`, "Winner: tool_linux_amd64.tar.gz"} {
+		if !strings.Contains(table, want) {
+			t.Errorf("Expected table to contain %q:\n%s", want, table)
+		}
+	}
+}
+
+func TestExplainMatch_NoMatch(t *testing.T) {
+	config := DefaultAssetMatchingConfig()
+	config.Strategy = CustomStrategy
+	config.CustomPatterns = []string{`^nothing-matches$`}
+
+	table := ExplainMatch(config, []string{"tool.tar.gz"})
+	if !strings.Contains(table, "No match: no asset matched custom patterns") {
+		t.Errorf("Expected the matching error in the table:\n%s", table)
+	}
+	if !strings.Contains(table, "strategy: custom") {
+		t.Errorf("Expected the strategy in the table:\n%s", table)
+	}
+}
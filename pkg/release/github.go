@@ -1,17 +1,28 @@
 package release
 
 import (
-	"encoding/json"
+	"bytes"
+	"errors"
 	"fmt"
 	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"io"
 	"log"
 	"net/http"
-	"path"
+	"net/url"
+	"os"
 	"runtime"
+	"slices"
 	"strings"
+	"time"
 )
 
-const githubApiUrl = "https://api.github.com/repos/%s/releases/latest"
+const githubApiUrl = "%s/repos/%s/releases/latest"
+
+// DefaultGitHubAPIURL is the REST API root of github.com
+const DefaultGitHubAPIURL = "https://api.github.com"
+
+// GitHubAPIVersion is the REST API version requests are pinned to and responses are decoded against
+const GitHubAPIVersion = "2022-11-28"
 
 type GithubRelease struct {
 	Repository  string               `json:"repository"`   // Format: "owner/repo"
@@ -20,15 +31,55 @@ type GithubRelease struct {
 	Version     string               `json:"version"`      // Tag name of the release
 	Config      fileUtils.FileConfig `json:"config"`       // File configuration
 	BaseURL     string               // Added to allow overriding API URL for tests
+	APIURL      string               `json:"api_url"`      // REST API root (default: GITHUB_API_URL or https://api.github.com); GitHub Enterprise Server hosts get /api/v3 appended
+	WebURL      string               `json:"web_url"`      // Web root for resolving the latest release when the API is rate limited (default: derived from APIURL)
+	DownloadURL string               `json:"download_url"` // Root replacing scheme and host of browser download URLs, e.g. when GitHub Enterprise Server assets are served through another host (default: the URLs the API returns)
+	DisableWebFallback bool          `json:"disable_web_fallback"` // Fail with the rate-limit error instead of resolving the latest release through the web pages
 	Token       string               // Optional GitHub token for authentication
+	TokenProvider TokenProvider      `json:"-"`            // Optional token source (e.g. GitHubAppTokenProvider), takes precedence over Token
+	Credentials *CredentialConfig    `json:"credentials,omitempty"` // Optional credential source from configuration, used when TokenProvider is nil
 	AssetMatchingConfig AssetMatchingConfig `json:"asset_matching_config"` // Configuration for asset matching
+	MetadataCache *MetadataCache     `json:"-"`            // Optional cache enabling conditional (ETag) API requests
+	MaxMetadataSize int64            `json:"max_metadata_size"` // Maximum API response size in bytes (default: DefaultMaxMetadataResponseSize)
+	MetadataTimeout time.Duration    `json:"metadata_timeout"`  // Timeout of API requests, including reading the response (default: DefaultMetadataTimeout)
+	NotModified bool                 `json:"not_modified"` // True if the last GetLatestRelease was answered from cache via 304
+	Source      AssetSource          `json:"-"`            // Optional provider override (defaults to the GitHub API)
+	Warnings    []string             `json:"warnings"`     // Non-fatal notes from asset selection (e.g. Rosetta fallback)
+	VersionConstraint string         `json:"version_constraint"` // Optional semver range (e.g. "^1.28", "~3.12") limiting which release is installed
+	Selection   ReleaseSelection     `json:"selection"`    // How the latest release is chosen (default: the provider's latest release)
+	Info        *ReleaseInfo         `json:"release_info"` // Metadata of the release found by GetLatestRelease (name, notes, date, assets)
+	DeltaApplied bool                `json:"delta_applied"` // True if the last download was produced by applying a delta patch
+	Force       bool                 `json:"force"`        // Download and install the resolved version even if it is already installed
+	AlreadyInstalled bool            `json:"already_installed"` // True if the last download was skipped because the resolved version is already installed (see Force)
+	AssetName   string               `json:"asset_name"`   // File name of the selected asset
+	deltaBinaryPath string           // Patched binary awaiting installation after a delta update
+	downloadedFrom  string           // URL of the last full download (release asset or CDN)
+	cdnFileName     string           // File name of the last CDN download (empty for release assets)
+	downloadCleanup string           // Download cleanup status of the last installation
+	downloadedVersion string         // Version downloaded by the last DownloadRelease call
+	lastDownload    *DownloadResult  // Result of the last DownloadLatestReleaseWithResult
 }
 
+// getTempSourceArchivePath returns where the asset is downloaded to, defaulting to the downloaded
+// file's name in the staging directory
 func (g *GithubRelease) getTempSourceArchivePath() string {
-	if g.Config.SourceArchivePath != "" {
-		return g.Config.SourceArchivePath
+	return fileUtils.GetStagingPath(g.Config, "github", g.Version, g.stagedFileName())
+}
+
+// stagedFileName returns the name of the downloaded file: the CDN file for CDN downloads,
+// otherwise the selected release asset
+func (g *GithubRelease) stagedFileName() string {
+	if g.cdnFileName != "" {
+		return g.cdnFileName
 	}
-	return path.Join("/tmp", fmt.Sprintf("binary-%s.tar.gz", g.Version))
+	return g.AssetName
+}
+
+// stagedConfig returns the file configuration with SourceArchivePath resolved to the download location
+func (g *GithubRelease) stagedConfig() fileUtils.FileConfig {
+	config := g.Config
+	config.SourceArchivePath = g.getTempSourceArchivePath()
+	return config
 }
 
 func (g *GithubRelease) GetApiUrl() (string, error) {
@@ -43,93 +94,376 @@ func (g *GithubRelease) GetApiUrl() (string, error) {
 	}
 
 	if g.BaseURL == "" {
-		return fmt.Sprintf(githubApiUrl, g.Repository), nil
+		return fmt.Sprintf(githubApiUrl, NormalizeGitHubAPIURL(g.APIURL), g.Repository), nil
 	}
 	// Construct the request URL for testing
 	return g.BaseURL + "/" + g.Repository + "/releases/latest", nil
 }
 
+// NormalizeGitHubAPIURL turns a GitHub or GitHub Enterprise Server address into its REST API root.
+// "https://github.com" maps to https://api.github.com and a bare GHES host such as
+// "https://github.example.com" maps to "https://github.example.com/api/v3"; explicit API paths are kept.
+func NormalizeGitHubAPIURL(raw string) string {
+	raw = strings.TrimSuffix(strings.TrimSpace(raw), "/")
+	if raw == "" {
+		return DefaultGitHubAPIURL
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" || parsed.Path != "" {
+		return raw
+	}
+
+	host := parsed.Hostname()
+	switch {
+	case host == "github.com" || host == "www.github.com":
+		return DefaultGitHubAPIURL
+	case strings.HasPrefix(host, "api."):
+		// api.github.com and GHE.com data residency hosts serve the API at the root
+		return raw
+	default:
+		return raw + "/api/v3"
+	}
+}
+
+// NormalizeGitHubUploadURL returns the release asset upload root of the GitHub instance whose API
+// root is apiURL: https://uploads.github.com for github.com, "<host>/api/uploads" for GitHub
+// Enterprise Server and uploads.<domain> for GHE.com data residency hosts.
+func NormalizeGitHubUploadURL(apiURL string) string {
+	apiRoot := NormalizeGitHubAPIURL(apiURL)
+	if apiRoot == DefaultGitHubAPIURL {
+		return "https://uploads.github.com"
+	}
+	if root, ok := strings.CutSuffix(apiRoot, "/api/v3"); ok {
+		return root + "/api/uploads"
+	}
+	if parsed, err := url.Parse(apiRoot); err == nil && parsed.Path == "" && strings.HasPrefix(parsed.Host, "api.") {
+		parsed.Host = "uploads." + strings.TrimPrefix(parsed.Host, "api.")
+		return parsed.String()
+	}
+	return apiRoot
+}
+
+// downloadLink returns a browser download URL moved onto DownloadURL, if one is configured
+func (g *GithubRelease) downloadLink(assetURL string) string {
+	if g.DownloadURL == "" || assetURL == "" {
+		return assetURL
+	}
+	parsed, err := url.Parse(assetURL)
+	if err != nil {
+		return assetURL
+	}
+	link := strings.TrimSuffix(g.DownloadURL, "/") + parsed.EscapedPath()
+	if parsed.RawQuery != "" {
+		link += "?" + parsed.RawQuery
+	}
+	return link
+}
+
 func (g *GithubRelease) GetLatestRelease() error {
 	log.Println("Fetching latest release from GitHub")
-	apiURL, err := g.GetApiUrl()
+	info, err := latestReleaseMatching(g.assetSource(), g.VersionConstraint, g.Selection)
 	if err != nil {
-		return fmt.Errorf("error constructing GitHub API URL: %w", err)
+		return err
 	}
+	return g.useRelease(info)
+}
 
-	req, err := http.NewRequest("GET", apiURL, nil)
+// GetRelease fetches the release tagged version and selects its asset for the current platform
+func (g *GithubRelease) GetRelease(version string) error {
+	log.Printf("Fetching release %s from GitHub", version)
+	info, err := releaseByVersion(g.assetSource(), g.Selection.Tag(version))
 	if err != nil {
-		return fmt.Errorf("error creating HTTP request: %w", err)
+		return err
 	}
+	return g.useRelease(g.Selection.withTagVersion(info))
+}
 
-	// Add authentication header if token is provided
-	if g.Token != "" {
-		req.Header.Set("Authorization", "Bearer "+g.Token)
+// useRelease records the release and selects its asset for the current platform
+func (g *GithubRelease) useRelease(info *ReleaseInfo) error {
+	// Extract release information
+	g.Info = info
+	g.Version = info.Version
+	asset, warnings, err := info.selectAsset(g.AssetMatchingConfig)
+	if err != nil || asset.URL == "" {
+		return noMatchErrorf(ErrNoMatchingAsset, "no suitable asset found for current platform (%s/%s) in GitHub release %s",
+			runtime.GOOS, runtime.GOARCH, info.Version)
+	}
+	g.ReleaseLink = g.downloadLink(asset.URL)
+	g.APILink = asset.APIURL
+	g.AssetName = asset.Name
+	g.Warnings = warnings
+
+	return nil
+}
+
+// assetSource returns the configured AssetSource, defaulting to the GitHub REST API
+func (g *GithubRelease) assetSource() AssetSource {
+	if g.Source != nil {
+		return g.Source
 	}
-	req.Header.Set("Accept", "application/vnd.github+json")
-	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	return &githubAPISource{release: g}
+}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+// githubAPISource fetches release metadata from the GitHub REST API
+type githubAPISource struct {
+	release *GithubRelease
+}
+
+// LatestRelease fetches the latest release from the GitHub API
+func (s *githubAPISource) LatestRelease() (*ReleaseInfo, error) {
+	apiURL, err := s.release.GetApiUrl()
+	if err != nil {
+		return nil, fmt.Errorf("error constructing GitHub API URL: %w", err)
+	}
+
+	body, err := s.fetch(apiURL)
+	if errors.Is(err, ErrRateLimited) && !s.release.DisableWebFallback {
+		// Only instances whose web pages are known are asked
+		if root, rootErr := s.release.webRoot(); rootErr == nil {
+			info, webErr := s.release.latestReleaseFromWeb(root)
+			if webErr != nil {
+				return nil, fmt.Errorf("%w (web fallback failed: %v)", err, webErr)
+			}
+			return info, nil
+		}
+	}
 	if err != nil {
-		return fmt.Errorf("error making HTTP request to GitHub: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected status code from GitHub: %d", resp.StatusCode)
+	return decodeRelease[GithubReleaseResponse]("GitHub", body)
+}
+
+// Release fetches the release tagged version from the GitHub API
+func (s *githubAPISource) Release(version string) (*ReleaseInfo, error) {
+	apiURL, err := s.release.GetApiUrl()
+	if err != nil {
+		return nil, fmt.Errorf("error constructing GitHub API URL: %w", err)
 	}
+	tagURL := strings.TrimSuffix(apiURL, "/latest") + "/tags/" + url.PathEscape(version)
 
-	var response GithubReleaseResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return fmt.Errorf("error decoding response from GitHub: %w", err)
+	body, err := s.fetch(tagURL)
+	if err != nil {
+		return nil, err
 	}
 
-	// Extract release information
-	g.Version = response.TagName
-	releaseLink := response.GetReleaseLinkWithConfig(g.AssetMatchingConfig)
-	if releaseLink == "" {
-		return fmt.Errorf("no suitable asset found for current platform (%s/%s) in GitHub release %s",
-			runtime.GOOS, runtime.GOARCH, response.TagName)
+	return decodeRelease[GithubReleaseResponse]("GitHub", body)
+}
+
+// Releases fetches the most recent releases (up to 100) from the GitHub API
+func (s *githubAPISource) Releases() ([]ReleaseInfo, error) {
+	apiURL, err := s.release.GetApiUrl()
+	if err != nil {
+		return nil, fmt.Errorf("error constructing GitHub API URL: %w", err)
 	}
-	g.ReleaseLink = releaseLink
-	g.APILink = response.GetAPILinkWithConfig(g.AssetMatchingConfig)
+	listURL := strings.TrimSuffix(apiURL, "/latest") + "?per_page=100"
 
-	return nil
+	stream, err := s.open(listURL)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	// Drafts are dropped while streaming, so only published releases are kept in memory
+	var releases []ReleaseInfo
+	err = decodeReleaseList("GitHub", stream, func(response *GithubReleaseResponse) {
+		if !response.Draft {
+			releases = append(releases, *response.ToReleaseInfo())
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(releases) == 0 {
+		return nil, fmt.Errorf("%w in repository %s", errNoGitHubReleases, s.release.Repository)
+	}
+	return releases, nil
+}
+
+// fetch performs an authenticated GitHub API request and returns the response body
+func (s *githubAPISource) fetch(apiURL string) ([]byte, error) {
+	stream, err := s.open(apiURL)
+	if err != nil {
+		return nil, err
+	}
+	return readMetadata("GitHub", stream)
+}
+
+// open performs an authenticated GitHub API request, answering from the metadata cache on 304.
+// The returned body is limited to MaxMetadataSize and must be closed.
+func (s *githubAPISource) open(apiURL string) (io.ReadCloser, error) {
+	g := s.release
+	req, err := http.NewRequestWithContext(g.Config.Context(), "GET", apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating HTTP request: %w", err)
+	}
+
+	// Add authentication header if token is provided
+	token, err := g.authToken()
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", GitHubAPIVersion)
+	fileUtils.SetUserAgent(req)
+
+	// Send conditional request headers if a cached response is available
+	cached := g.MetadataCache.load(apiURL)
+	for key, value := range cached.conditionalHeaders() {
+		req.Header.Set(key, value)
+	}
+
+	timeout := g.MetadataTimeout
+	if timeout == 0 {
+		timeout = DefaultMetadataTimeout
+	}
+	client := fileUtils.NewHTTPClient(timeout)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making HTTP request to GitHub: %w", err)
+	}
+
+	g.NotModified = false
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		resp.Body.Close()
+		g.NotModified = true
+		return io.NopCloser(bytes.NewReader(cached.Body)), nil
+	case resp.StatusCode == http.StatusOK:
+		checkGitHubAPIVersion(resp.Header)
+		body, err := newLimitedBody(resp, g.MaxMetadataSize)
+		if err != nil {
+			return nil, fmt.Errorf("error reading response body from GitHub: %w", err)
+		}
+		if g.MetadataCache == nil {
+			return body, nil
+		}
+		// Cached responses are read completely to store them
+		data, err := readMetadata("GitHub", body)
+		if err != nil {
+			return nil, err
+		}
+		if err := g.MetadataCache.store(apiURL, resp.Header, data); err != nil {
+			log.Printf("Warning: failed to cache GitHub release metadata: %v", err)
+		}
+		return io.NopCloser(bytes.NewReader(data)), nil
+	default:
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status code from GitHub: %w", newHTTPStatusError(resp))
+	}
 }
 
+// DownloadLatestRelease downloads the latest release to the staging path, which stays claimed for
+// this tool until the release is installed or ReleaseDownload is called
 func (g *GithubRelease) DownloadLatestRelease() error {
+	g.downloadedVersion = ""
+	return g.download("")
+}
+
+// DownloadRelease downloads the release tagged version, e.g. to pin or roll back an installation.
+// Like DownloadLatestRelease it claims the staging path until installing or ReleaseDownload.
+func (g *GithubRelease) DownloadRelease(version string) error {
+	if version == "" {
+		return fmt.Errorf("version cannot be empty")
+	}
+	g.downloadedVersion = ""
+	if err := g.download(version); err != nil {
+		return err
+	}
+	g.downloadedVersion = version
+	return nil
+}
+
+// download downloads the release tagged version, or the latest release if version is empty
+func (g *GithubRelease) download(version string) error {
+	g.AlreadyInstalled = false
+
 	// Handle CDN downloads
 	if g.AssetMatchingConfig.Strategy == CDNStrategy || g.AssetMatchingConfig.Strategy == HybridStrategy {
-		return g.downloadFromCDN()
+		return g.downloadFromCDN(version)
 	}
+	return g.downloadReleaseAsset(version)
+}
 
-	err := g.GetLatestRelease()
+// downloadReleaseAsset downloads the matching asset of the GitHub release tagged version, or of the
+// latest release if version is empty
+func (g *GithubRelease) downloadReleaseAsset(version string) error {
+	g.cdnFileName = ""
+	var err error
+	if version == "" {
+		err = g.GetLatestRelease()
+	} else {
+		err = g.GetRelease(version)
+	}
 	if err != nil {
-		return fmt.Errorf("error getting latest release from GitHub: %w", err)
+		return fmt.Errorf("error getting %s from GitHub: %w", describeRelease(version), err)
 	}
 	if g.Version == "" || g.ReleaseLink == "" {
 		return fmt.Errorf("could not find a valid release to download")
 	}
+	if g.AlreadyInstalled = skipInstalled(g.Config, g.Version, g.Force); g.AlreadyInstalled {
+		return nil
+	}
+	if err := fileUtils.CheckInstallWritable(g.Config); err != nil {
+		return err
+	}
 
-	// For authenticated requests, use the API URL which supports private repo downloads.
-	// The API URL with Accept: application/octet-stream returns a pre-signed redirect.
-	downloadURL := g.ReleaseLink
-	if g.Token != "" && g.APILink != "" {
-		downloadURL = g.APILink
+	token, err := g.authToken()
+	if err != nil {
+		return err
+	}
+
+	// Prefer a delta patch against the installed version, falling back to the full asset
+	g.DeltaApplied = false
+	if g.AssetMatchingConfig.Delta.Enabled {
+		patchedPath, err := applyDeltaUpdate(g.Info, g.AssetMatchingConfig.Delta, g.Config, token, g.deltaDigest())
+		if err == nil {
+			g.deltaBinaryPath = patchedPath
+			g.DeltaApplied = true
+			return nil
+		}
+		log.Printf("Delta update not used, downloading full asset: %v", err)
 	}
 
-	err = fileUtils.DownloadFileWithAuth(downloadURL, g.Config.SourceArchivePath, g.Token)
+	// Fail early instead of running out of space halfway through the download or extraction
+	if err := g.checkDiskSpace(); err != nil {
+		return err
+	}
+
+	link, assetToken := g.assetDownload(token)
+	g.downloadedFrom = link
+
+	// Streaming extraction downloads the archive during installation instead
+	if g.streamExtractionEnabled() {
+		return nil
+	}
+
+	// GitHub reports a digest for newer assets, which verifies the download without a checksum asset
+	digest := g.assetDigest()
+	destination := g.getTempSourceArchivePath()
+	err = stageDownload(destination, g.stagingOwner(), func() error {
+		return downloadVerified(destination, digest, func(checksum string) error {
+			return fileUtils.DownloadFileWithConfig(g.Config, link, destination, assetToken, checksum)
+		})
+	})
 	if err != nil {
-		return fmt.Errorf("error downloading latest release from GitHub: %w", err)
+		return fmt.Errorf("error downloading %s from GitHub: %w", describeRelease(version), err)
 	}
 	return nil
 }
 
-// downloadFromCDN downloads binary from CDN instead of GitHub releases
-func (g *GithubRelease) downloadFromCDN() error {
-	if g.Version == "" {
+// downloadFromCDN downloads binary from CDN instead of GitHub releases. An empty version downloads
+// the latest release.
+func (g *GithubRelease) downloadFromCDN(version string) error {
+	if version != "" {
+		g.Version = version
+	} else if g.Version == "" {
 		// Try to discover version from CDN first, fall back to GitHub if needed
-		cdnDownloader := NewCDNDownloader(g.AssetMatchingConfig.CDNBaseURL, g.AssetMatchingConfig.CDNPattern)
+		cdnDownloader := newConfiguredCDNDownloader(g.AssetMatchingConfig, g.Config)
 
 		version, err := cdnDownloader.TryDiscoverLatestVersion()
 		if err == nil {
@@ -138,30 +472,40 @@ func (g *GithubRelease) downloadFromCDN() error {
 		} else {
 			// Fall back to GitHub for version information
 			fmt.Printf("CDN version discovery failed (%v), falling back to GitHub for version info\n", err)
-			err := g.GetLatestRelease()
+			err := g.resolveLatestVersion()
 			if err != nil {
 				return fmt.Errorf("error getting version information from GitHub: %w", err)
 			}
 		}
 	}
 
-	// Create CDN downloader with custom architecture mapping if configured
-	var cdnDownloader *CDNDownloader
-	if g.AssetMatchingConfig.CDNArchMapping != nil {
-		cdnDownloader = NewCDNDownloaderWithArchMapping(
-			g.AssetMatchingConfig.CDNBaseURL,
-			g.AssetMatchingConfig.CDNPattern,
-			g.AssetMatchingConfig.CDNArchMapping,
-		)
-	} else {
-		cdnDownloader = NewCDNDownloader(g.AssetMatchingConfig.CDNBaseURL, g.AssetMatchingConfig.CDNPattern)
+	if g.AlreadyInstalled = skipInstalled(g.Config, g.Version, g.Force); g.AlreadyInstalled {
+		return nil
+	}
+	if err := fileUtils.CheckInstallWritable(g.Config); err != nil {
+		return err
 	}
 
+	cdnDownloader := newConfiguredCDNDownloader(g.AssetMatchingConfig, g.Config)
+
 	versionFormat := g.AssetMatchingConfig.CDNVersionFormat
 	if versionFormat == "" {
 		versionFormat = "as-is" // Default to as-is if not specified
 	}
-	return cdnDownloader.DownloadWithVersionFormat(g.Version, g.Config.SourceArchivePath, versionFormat)
+
+	// Check the CDN before downloading; hybrid configurations fall back to release assets
+	if err := cdnDownloader.ensureOnCDN(g.Version, versionFormat); err != nil {
+		if g.AssetMatchingConfig.Strategy == HybridStrategy && errors.Is(err, ErrVersionNotOnCDN) {
+			fmt.Printf("%v, falling back to GitHub release assets\n", err)
+			return g.downloadReleaseAsset(version)
+		}
+		return err
+	}
+	g.downloadedFrom = cdnDownloader.platformURL(g.Version, versionFormat)
+	g.cdnFileName = cdnDownloader.FileName(g.Version, versionFormat)
+	return stageDownload(g.getTempSourceArchivePath(), g.stagingOwner(), func() error {
+		return cdnDownloader.DownloadWithVersionFormat(g.Version, g.getTempSourceArchivePath(), versionFormat)
+	})
 }
 
 // DownloadCDNVersion downloads a specific version from CDN without GitHub API calls
@@ -177,65 +521,155 @@ func (g *GithubRelease) DownloadCDNVersion(version string) error {
 
 	// Set the version directly to avoid GitHub API calls
 	g.Version = version
-
-	// Create CDN downloader with custom architecture mapping if configured
-	var cdnDownloader *CDNDownloader
-	if g.AssetMatchingConfig.CDNArchMapping != nil {
-		cdnDownloader = NewCDNDownloaderWithArchMapping(
-			g.AssetMatchingConfig.CDNBaseURL,
-			g.AssetMatchingConfig.CDNPattern,
-			g.AssetMatchingConfig.CDNArchMapping,
-		)
-	} else {
-		cdnDownloader = NewCDNDownloader(g.AssetMatchingConfig.CDNBaseURL, g.AssetMatchingConfig.CDNPattern)
+	if g.AlreadyInstalled = skipInstalled(g.Config, version, g.Force); g.AlreadyInstalled {
+		return nil
+	}
+	if err := fileUtils.CheckInstallWritable(g.Config); err != nil {
+		return err
 	}
 
+	cdnDownloader := newConfiguredCDNDownloader(g.AssetMatchingConfig, g.Config)
+
 	versionFormat := g.AssetMatchingConfig.CDNVersionFormat
 	if versionFormat == "" {
 		versionFormat = "as-is" // Default to as-is if not specified
 	}
-	return cdnDownloader.DownloadWithVersionFormat(g.Version, g.Config.SourceArchivePath, versionFormat)
+	if err := cdnDownloader.ensureOnCDN(version, versionFormat); err != nil {
+		return err
+	}
+	g.downloadedFrom = cdnDownloader.platformURL(version, versionFormat)
+	g.cdnFileName = cdnDownloader.FileName(version, versionFormat)
+	return stageDownload(g.getTempSourceArchivePath(), g.stagingOwner(), func() error {
+		return cdnDownloader.DownloadWithVersionFormat(g.Version, g.getTempSourceArchivePath(), versionFormat)
+	})
 }
 
 func (g *GithubRelease) InstallLatestRelease() error {
+	defer g.releaseStagedDownload()
+	g.downloadCleanup = DownloadCleanupNone
+	if g.AlreadyInstalled {
+		fmt.Printf("%s %s is already installed\n", g.Config.BinaryName, g.Version)
+		return nil
+	}
+	if g.deltaBinaryPath != "" {
+		patchedPath := g.deltaBinaryPath
+		g.deltaBinaryPath = ""
+		if err := installPatchedBinary(g.Config, patchedPath, g.Version); err != nil {
+			return err
+		}
+		recordProvenance(g.Config, g.Version, g.provenance(), "")
+		g.downloadCleanup = cleanupDownload(g.Config, patchedPath)
+		return nil
+	}
+	if g.streamExtractionEnabled() {
+		token, err := g.authToken()
+		if err != nil {
+			return err
+		}
+		link, token := g.assetDownload(token)
+		if err := fileUtils.StreamInstallArchivedBinary(g.Config, g.Version, link, token, g.AssetName,
+			g.AssetMatchingConfig.extractionConfig()); err != nil {
+			return err
+		}
+		recordProvenance(g.Config, g.Version, g.provenance(), "")
+		return nil
+	}
+
 	// Use enhanced installation with extraction config if available
-	if g.AssetMatchingConfig.ExtractionConfig != nil && !g.Config.IsDirectBinary {
-		// Convert ExtractionConfig to fileUtils.ExtractionConfig
-		fileUtilsConfig := &fileUtils.ExtractionConfig{
-			StripComponents: g.AssetMatchingConfig.ExtractionConfig.StripComponents,
-			BinaryPath:      g.AssetMatchingConfig.ExtractionConfig.BinaryPath,
+	var err error
+	if g.AssetMatchingConfig.ExtractionConfig != nil && !g.Config.UsesDirectInstall() {
+		err = fileUtils.InstallArchivedBinaryWithConfig(g.stagedConfig(), g.Version, g.AssetMatchingConfig.extractionConfig())
+	} else {
+		err = fileUtils.InstallBinary(g.stagedConfig(), g.Version)
+	}
+	if err != nil {
+		return err
+	}
+	recordProvenance(g.Config, g.Version, g.provenance(), g.getTempSourceArchivePath())
+	g.downloadCleanup = cleanupDownload(g.Config, g.getTempSourceArchivePath())
+	return nil
+}
+
+// InstallRelease installs the release tagged version, downloading it unless DownloadRelease already did
+func (g *GithubRelease) InstallRelease(version string) error {
+	if version == "" || g.downloadedVersion != version {
+		if err := g.DownloadRelease(version); err != nil {
+			return err
 		}
-		return fileUtils.InstallArchivedBinaryWithConfig(g.Config, g.Version, fileUtilsConfig)
 	}
-	return fileUtils.InstallBinary(g.Config, g.Version)
+	g.downloadedVersion = ""
+	return g.InstallLatestRelease()
 }
 
-func NewGithubRelease(repository string, fileConfig fileUtils.FileConfig) *GithubRelease {
-	assetConfig := DefaultAssetMatchingConfig()
-	assetConfig.ProjectName = fileConfig.ProjectName
-	assetConfig.IsDirectBinary = fileConfig.IsDirectBinary
-
-	// Configure asset matching strategy based on FileConfig
-	switch fileConfig.AssetMatchingStrategy {
-	case "standard":
-		assetConfig.Strategy = StandardStrategy
-	case "flexible":
-		assetConfig.Strategy = FlexibleStrategy
-	case "custom":
-		assetConfig.Strategy = CustomStrategy
-		assetConfig.CustomPatterns = fileConfig.CustomAssetPatterns
-	case "cdn":
-		assetConfig.Strategy = CDNStrategy
-	case "hybrid":
-		assetConfig.Strategy = HybridStrategy
-	default:
-		assetConfig.Strategy = FlexibleStrategy
+// checkGitHubAPIVersion warns when GitHub answered with another API version than requested, whose
+// schema may differ from the one responses are decoded against
+func checkGitHubAPIVersion(header http.Header) {
+	if selected := header.Get("X-GitHub-Api-Version-Selected"); selected != "" && selected != GitHubAPIVersion {
+		log.Printf("Warning: GitHub answered with API version %s instead of %s", selected, GitHubAPIVersion)
+	}
+}
+
+// assetDownload returns the URL and token to download the selected asset with. With a token the
+// API URL is used, which supports private repositories: requested with Accept:
+// application/octet-stream it redirects to a pre-signed storage URL that receives no token.
+func (g *GithubRelease) assetDownload(token string) (string, string) {
+	return assetDownload(AssetInfo{URL: g.ReleaseLink, APIURL: g.APILink}, token)
+}
+
+// authToken returns the token for API requests and downloads, preferring the TokenProvider,
+// then configured Credentials, then the plain Token
+func (g *GithubRelease) authToken() (string, error) {
+	provider := g.TokenProvider
+	if provider == nil && g.Credentials != nil {
+		provider = g.Credentials
+	}
+	if provider == nil {
+		return g.Token, nil
+	}
+	token, err := provider.Token()
+	if err != nil {
+		return "", fmt.Errorf("error obtaining GitHub token: %w", err)
 	}
+	return token, nil
+}
+
+// checkDiskSpace compares the selected asset's size with the free space in the staging and install directories
+func (g *GithubRelease) checkDiskSpace() error {
+	if g.Info == nil {
+		return nil
+	}
+	asset, ok := g.Info.FindAsset(g.AssetName)
+	if !ok {
+		return nil
+	}
+	config := g.stagedConfig()
+	config.StreamExtraction = g.streamExtractionEnabled()
+	return fileUtils.CheckInstallSpace(config, asset.Size)
+}
+
+// streamExtractionEnabled reports whether the selected archive is extracted while downloading.
+// Assets with a verifiable digest are downloaded first instead, so the digest is checked before installing.
+func (g *GithubRelease) streamExtractionEnabled() bool {
+	return g.Config.StreamExtraction && !g.Config.UsesDirectInstall() && fileUtils.CanStreamExtract(g.AssetName) &&
+		!verifiableDigest(g.assetDigest())
+}
+
+// InstallFromFile installs a pre-staged archive or binary without contacting GitHub
+func (g *GithubRelease) InstallFromFile(path, version string) error {
+	g.Version = version
+	if err := fileUtils.InstallFromFile(g.Config, path, version, g.AssetMatchingConfig.extractionConfig()); err != nil {
+		return err
+	}
+	recordProvenance(g.Config, version, localProvenance(path), path)
+	return nil
+}
 
+func NewGithubRelease(repository string, fileConfig fileUtils.FileConfig) *GithubRelease {
 	return &GithubRelease{
 		Repository:          repository,
+		APIURL:              os.Getenv("GITHUB_API_URL"),
 		Config:              fileConfig,
-		AssetMatchingConfig: assetConfig,
+		AssetMatchingConfig: assetConfigFromFileConfig(fileConfig),
 	}
 }
 
@@ -260,6 +694,7 @@ func NewGithubReleaseWithAssetConfig(repository string, fileConfig fileUtils.Fil
 
 	return &GithubRelease{
 		Repository:          repository,
+		APIURL:              os.Getenv("GITHUB_API_URL"),
 		Config:              fileConfig,
 		AssetMatchingConfig: assetConfig,
 	}
@@ -279,6 +714,22 @@ func NewGithubReleaseWithToken(repository string, token string, fileConfig fileU
 	return release
 }
 
+// NewGithubReleaseWithTokenProvider creates a GitHub release instance authenticated by a TokenProvider,
+// such as a GitHubAppTokenProvider for organizations that don't allow personal access tokens
+func NewGithubReleaseWithTokenProvider(repository string, provider TokenProvider, fileConfig fileUtils.FileConfig) *GithubRelease {
+	release := NewGithubRelease(repository, fileConfig)
+	release.TokenProvider = provider
+	if app, ok := provider.(*GitHubAppTokenProvider); ok {
+		if app.Repository == "" {
+			app.Repository = repository
+		}
+		if app.APIURL == "" {
+			app.APIURL = release.APIURL
+		}
+	}
+	return release
+}
+
 // GetInstalledBinaryPath returns the preferred path to the installed binary
 // Prefers symlink path when available, falls back to versioned directory path
 func (g *GithubRelease) GetInstalledBinaryPath() (string, error) {
@@ -288,10 +739,44 @@ func (g *GithubRelease) GetInstalledBinaryPath() (string, error) {
 	return fileUtils.GetInstalledBinaryPath(g.Config, g.Version)
 }
 
+// ReleaseNotes returns the notes of the release found by GetLatestRelease, so updaters can show
+// what's new before installing
+func (g *GithubRelease) ReleaseNotes() (string, error) {
+	if g.Info == nil {
+		return "", fmt.Errorf("no release information available - call GetLatestRelease() first")
+	}
+	return g.Info.ReleaseNotes(), nil
+}
+
+// Assets returns every asset of the release found by GetLatestRelease or GetRelease, including
+// assets the matcher did not select, so UIs can let users pick one manually. It returns nil
+// before a release was fetched.
+func (g *GithubRelease) Assets() []AssetInfo {
+	if g.Info == nil {
+		return nil
+	}
+	return slices.Clone(g.Info.Assets)
+}
+
+// LatestVersion returns the version resolved by GetLatestRelease
+func (g *GithubRelease) LatestVersion() string {
+	return g.Version
+}
+
+// InstalledVersion returns the version the local symlink currently points to
+func (g *GithubRelease) InstalledVersion() (string, error) {
+	return fileUtils.CurrentInstalledVersion(g.Config)
+}
+
 // GetInstallationInfo returns comprehensive information about the installed binary
 func (g *GithubRelease) GetInstallationInfo() (*fileUtils.InstallationInfo, error) {
 	if g.Version == "" {
 		return nil, fmt.Errorf("no version information available - call GetLatestRelease() first")
 	}
-	return fileUtils.GetInstallationInfo(g.Config, g.Version)
+	info, err := fileUtils.GetInstallationInfo(g.Config, g.Version)
+	if err != nil {
+		return nil, err
+	}
+	info.Warnings = append(info.Warnings, g.Warnings...)
+	return info, nil
 }
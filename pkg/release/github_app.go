@@ -0,0 +1,188 @@
+package release
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenProvider supplies the bearer token used for API requests and asset downloads.
+// Implementations may refresh short-lived tokens, so Token is called before every operation.
+type TokenProvider interface {
+	Token() (string, error)
+}
+
+// githubAppJWTLifetime is how long app JWTs are valid; GitHub allows at most 10 minutes
+const githubAppJWTLifetime = 9 * time.Minute
+
+// githubAppTokenRefreshMargin refreshes installation tokens this long before they expire
+const githubAppTokenRefreshMargin = 5 * time.Minute
+
+// GitHubAppTokenProvider authenticates as a GitHub App installation, exchanging a JWT signed with
+// the app's private key for an installation token that is cached and refreshed before it expires
+type GitHubAppTokenProvider struct {
+	AppID          int64           // GitHub App ID
+	InstallationID int64           // Installation ID; looked up from Repository when zero
+	Repository     string          // "owner/repo" used to find the installation when InstallationID is zero
+	PrivateKey     *rsa.PrivateKey // The app's private key
+	APIURL         string          // REST API root (default: https://api.github.com)
+	HTTPClient     *http.Client    // Client for token requests (default: http.Client with a 30s timeout)
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// NewGitHubAppTokenProvider creates a token provider from an app ID, installation ID and PEM encoded private key
+func NewGitHubAppTokenProvider(appID, installationID int64, privateKeyPEM []byte) (*GitHubAppTokenProvider, error) {
+	key, err := parseGitHubAppPrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+	return &GitHubAppTokenProvider{
+		AppID:          appID,
+		InstallationID: installationID,
+		PrivateKey:     key,
+	}, nil
+}
+
+// parseGitHubAppPrivateKey reads a PKCS#1 or PKCS#8 RSA private key
+func parseGitHubAppPrivateKey(privateKeyPEM []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(privateKeyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("GitHub App private key is not PEM encoded")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GitHub App private key: %v", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("GitHub App private key must be an RSA key")
+	}
+	return key, nil
+}
+
+// Token returns a valid installation token, requesting a new one when the cached token is about to expire
+func (p *GitHubAppTokenProvider) Token() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" && time.Until(p.expiresAt) > githubAppTokenRefreshMargin {
+		return p.token, nil
+	}
+
+	jwt, err := p.appJWT(time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	if p.InstallationID == 0 {
+		if err := p.lookupInstallation(jwt); err != nil {
+			return "", err
+		}
+	}
+
+	var response struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	tokenURL := fmt.Sprintf("%s/app/installations/%d/access_tokens", NormalizeGitHubAPIURL(p.APIURL), p.InstallationID)
+	if err := p.request("POST", tokenURL, jwt, http.StatusCreated, &response); err != nil {
+		return "", fmt.Errorf("failed to create GitHub App installation token: %w", err)
+	}
+	if response.Token == "" {
+		return "", fmt.Errorf("failed to create GitHub App installation token: empty token in response")
+	}
+
+	p.token = response.Token
+	p.expiresAt = response.ExpiresAt
+	return p.token, nil
+}
+
+// lookupInstallation finds the app's installation on the configured repository
+func (p *GitHubAppTokenProvider) lookupInstallation(jwt string) error {
+	if p.Repository == "" {
+		return fmt.Errorf("GitHub App authentication requires an installation ID or repository")
+	}
+
+	var response struct {
+		ID int64 `json:"id"`
+	}
+	installationURL := fmt.Sprintf("%s/repos/%s/installation", NormalizeGitHubAPIURL(p.APIURL), p.Repository)
+	if err := p.request("GET", installationURL, jwt, http.StatusOK, &response); err != nil {
+		return fmt.Errorf("failed to find GitHub App installation for %s: %w", p.Repository, err)
+	}
+	p.InstallationID = response.ID
+	return nil
+}
+
+// request sends an app-authenticated API request and decodes the JSON response
+func (p *GitHubAppTokenProvider) request(method, url, jwt string, expectedStatus int, result interface{}) error {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return fmt.Errorf("error creating HTTP request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", GitHubAPIVersion)
+	fileUtils.SetUserAgent(req)
+
+	client := p.HTTPClient
+	if client == nil {
+		client = fileUtils.NewHTTPClient(30 * time.Second)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expectedStatus {
+		return fmt.Errorf("unexpected status code from GitHub: %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("error decoding response from GitHub: %w", err)
+	}
+	return nil
+}
+
+// appJWT creates the RS256 signed JWT that identifies the app itself
+func (p *GitHubAppTokenProvider) appJWT(now time.Time) (string, error) {
+	if p.PrivateKey == nil {
+		return "", fmt.Errorf("GitHub App private key is not configured")
+	}
+	if p.AppID == 0 {
+		return "", fmt.Errorf("GitHub App ID is not configured")
+	}
+
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]int64{
+		"iat": now.Add(-60 * time.Second).Unix(), // Allow for clock drift
+		"exp": now.Add(githubAppJWTLifetime).Unix(),
+		"iss": p.AppID,
+	})
+
+	encoding := base64.RawURLEncoding
+	unsigned := encoding.EncodeToString(header) + "." + encoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, p.PrivateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign GitHub App JWT: %v", err)
+	}
+	return strings.Join([]string{unsigned, encoding.EncodeToString(signature)}, "."), nil
+}
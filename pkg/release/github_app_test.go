@@ -0,0 +1,95 @@
+package release
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGitHubAppTokenProvider(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	// verifyJWT checks the RS256 signature of the app JWT against the public key
+	verifyJWT := func(r *http.Request) bool {
+		parts := strings.Split(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), ".")
+		if len(parts) != 3 {
+			return false
+		}
+		signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+		if err != nil {
+			return false
+		}
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		return rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature) == nil
+	}
+
+	var tokenRequests int
+	server := httptest.NewServer(http.StripPrefix("/api/v3", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/repos/owner/repo/installation" && verifyJWT(r):
+			fmt.Fprint(w, `{"id": 42}`)
+		case r.Method == "POST" && r.URL.Path == "/app/installations/42/access_tokens" && verifyJWT(r):
+			tokenRequests++
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"token": "ghs_%d", "expires_at": %q}`, tokenRequests, time.Now().Add(time.Hour).Format(time.RFC3339))
+		case r.URL.Path == "/repos/owner/repo/releases/latest" && r.Header.Get("Authorization") == "Bearer ghs_1":
+			fmt.Fprint(w, `{"tag_name": "v1.0.0", "assets": [{"name": "myapp-Linux_x86_64.tar.gz", "browser_download_url": "https://example.com/asset"}]}`)
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	})))
+	defer server.Close()
+
+	provider, err := NewGitHubAppTokenProvider(1234, 0, keyPEM)
+	if err != nil {
+		t.Fatalf("NewGitHubAppTokenProvider() error = %v", err)
+	}
+
+	// The test server acts as a GitHub Enterprise Server instance
+	t.Setenv("GITHUB_API_URL", server.URL)
+	release := NewGithubReleaseWithTokenProvider("owner/repo", provider, fileUtils.FileConfig{ProjectName: "myapp"})
+	if err := release.GetLatestRelease(); err != nil {
+		t.Fatalf("GetLatestRelease() error = %v", err)
+	}
+	if provider.InstallationID != 42 {
+		t.Errorf("Expected installation 42 to be looked up, got %d", provider.InstallationID)
+	}
+
+	// The cached installation token is reused until it nears expiry
+	if _, err := provider.Token(); err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if tokenRequests != 1 {
+		t.Errorf("Expected the installation token to be cached, got %d token requests", tokenRequests)
+	}
+
+	provider.expiresAt = time.Now().Add(time.Minute)
+	token, err := provider.Token()
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if token != "ghs_2" {
+		t.Errorf("Expected a refreshed token, got %s", token)
+	}
+}
+
+func TestNewGitHubAppTokenProvider_InvalidKey(t *testing.T) {
+	if _, err := NewGitHubAppTokenProvider(1, 1, []byte("not a key")); err == nil {
+		t.Error("Expected error for a key that is not PEM encoded")
+	}
+}
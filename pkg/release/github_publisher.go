@@ -0,0 +1,157 @@
+package release
+
+import (
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// GitHubPublisher uploads release assets to a GitHub repository
+type GitHubPublisher struct {
+	Repository    string        // Format: "owner/repo"
+	APIURL        string        // REST API root (default: https://api.github.com); normalized like GithubRelease.APIURL
+	UploadURL     string        // Upload root, e.g. for a proxy in front of GitHub Enterprise Server (default: the release's upload_url, else derived from APIURL)
+	Token         string        // GitHub token with contents write permission
+	TokenProvider TokenProvider // Optional token source (e.g. GitHubAppTokenProvider), takes precedence over Token
+}
+
+// githubPublishRelease is the part of a GitHub release needed to upload assets
+type githubPublishRelease struct {
+	ID        int64  `json:"id"`
+	UploadURL string `json:"upload_url"`
+	Assets    []struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	} `json:"assets"`
+}
+
+// NewGitHubPublisher creates a publisher for a repository, using GITHUB_API_URL if set
+func NewGitHubPublisher(repository, token string) *GitHubPublisher {
+	return &GitHubPublisher{
+		Repository: repository,
+		APIURL:     os.Getenv("GITHUB_API_URL"),
+		Token:      token,
+	}
+}
+
+// Publish creates the GitHub release if it does not exist and uploads the assets
+func (p *GitHubPublisher) Publish(request PublishRequest) (*ReleaseInfo, error) {
+	assets, err := request.prepare()
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Split(p.Repository, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid repository format: %s (expected 'owner/repo')", p.Repository)
+	}
+	headers, err := p.headers()
+	if err != nil {
+		return nil, err
+	}
+	client := fileUtils.NewHTTPClient(0)
+	releasesURL := NormalizeGitHubAPIURL(p.APIURL) + "/repos/" + p.Repository + "/releases"
+	tagURL := releasesURL + "/tags/" + url.PathEscape(request.Version)
+
+	var release githubPublishRelease
+	err = publishRequest(client, http.MethodGet, tagURL, headers, nil, 0, http.StatusOK, &release)
+	if isNotFound(err) {
+		body, length, encodeErr := jsonBody(struct {
+			TagName         string `json:"tag_name"`
+			Name            string `json:"name"`
+			Body            string `json:"body,omitempty"`
+			TargetCommitish string `json:"target_commitish,omitempty"`
+		}{request.Version, request.releaseName(), request.Description, request.Ref})
+		if encodeErr != nil {
+			return nil, encodeErr
+		}
+		err = publishRequest(client, http.MethodPost, releasesURL, headers, body, length, http.StatusCreated, &release)
+		if err != nil {
+			return nil, fmt.Errorf("error creating GitHub release %s: %w", request.Version, err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("error fetching GitHub release %s: %w", request.Version, err)
+	}
+
+	// Check every name before uploading, so a conflict does not leave a partially published release
+	existing := make(map[string]int64)
+	for _, asset := range release.Assets {
+		existing[asset.Name] = asset.ID
+	}
+	for _, asset := range assets {
+		if _, ok := existing[asset.name]; ok && !request.Replace {
+			return nil, fmt.Errorf("asset %s already exists in GitHub release %s", asset.name, request.Version)
+		}
+	}
+
+	uploadURL := p.assetUploadURL(release)
+	for _, asset := range assets {
+		if id, ok := existing[asset.name]; ok {
+			assetURL := fmt.Sprintf("%s/assets/%d", releasesURL, id)
+			if err := publishRequest(client, http.MethodDelete, assetURL, headers, nil, 0, http.StatusNoContent, nil); err != nil {
+				return nil, fmt.Errorf("error replacing GitHub release asset %s: %w", asset.name, err)
+			}
+		}
+		if err := p.upload(client, uploadURL, headers, asset); err != nil {
+			return nil, fmt.Errorf("error uploading GitHub release asset %s: %w", asset.name, err)
+		}
+	}
+
+	var response GithubReleaseResponse
+	if err := publishRequest(client, http.MethodGet, tagURL, headers, nil, 0, http.StatusOK, &response); err != nil {
+		return nil, fmt.Errorf("error fetching GitHub release %s: %w", request.Version, err)
+	}
+	return response.ToReleaseInfo(), nil
+}
+
+// assetUploadURL returns the URL the assets of release are uploaded to. Without a configured
+// UploadURL the upload_url GitHub returned is used, which already names the instance's upload host.
+func (p *GitHubPublisher) assetUploadURL(release githubPublishRelease) string {
+	if p.UploadURL == "" {
+		if uploadURL, _, _ := strings.Cut(release.UploadURL, "{"); uploadURL != "" {
+			return uploadURL
+		}
+	}
+	root := p.UploadURL
+	if root == "" {
+		root = NormalizeGitHubUploadURL(p.APIURL)
+	}
+	return fmt.Sprintf("%s/repos/%s/releases/%d/assets", strings.TrimSuffix(root, "/"), p.Repository, release.ID)
+}
+
+// upload attaches one asset to the release behind uploadURL
+func (p *GitHubPublisher) upload(client *http.Client, uploadURL string, headers map[string]string, asset publishAsset) error {
+	content, size, err := asset.open()
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	uploadHeaders := map[string]string{"Content-Type": "application/octet-stream"}
+	for key, value := range headers {
+		uploadHeaders[key] = value
+	}
+	return publishRequest(client, http.MethodPost, uploadURL+"?name="+url.QueryEscape(asset.name), uploadHeaders,
+		content, size, http.StatusCreated, nil)
+}
+
+// headers returns the authentication and API version headers
+func (p *GitHubPublisher) headers() (map[string]string, error) {
+	token := p.Token
+	if p.TokenProvider != nil {
+		var err error
+		if token, err = p.TokenProvider.Token(); err != nil {
+			return nil, fmt.Errorf("error obtaining GitHub token: %w", err)
+		}
+	}
+	if token == "" {
+		return nil, fmt.Errorf("publishing to GitHub requires a token")
+	}
+	return map[string]string{
+		"Authorization":        "Bearer " + token,
+		"Accept":               "application/vnd.github+json",
+		"X-GitHub-Api-Version": GitHubAPIVersion,
+	}, nil
+}
@@ -1,11 +1,6 @@
 package release
 
 import (
-	"fmt"
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
-	"runtime"
-	"strings"
 	"time"
 )
 
@@ -27,62 +22,51 @@ type GithubReleaseResponse struct {
 		DownloadCount      int       `json:"download_count"`
 		Url                string    `json:"url"`
 		BrowserDownloadUrl string    `json:"browser_download_url"`
+		Digest             string    `json:"digest"` // "sha256:<hex>", absent for assets uploaded before GitHub computed digests
 		CreatedAt          time.Time `json:"created_at"`
 		UpdatedAt          time.Time `json:"updated_at"`
 	} `json:"assets"`
 }
 
+// ToReleaseInfo converts the GitHub API response into the provider-agnostic release model
+func (g *GithubReleaseResponse) ToReleaseInfo() *ReleaseInfo {
+	info := &ReleaseInfo{
+		Version:     g.TagName,
+		Name:        g.Name,
+		Description: g.Body,
+		PublishedAt: g.PublishedAt,
+		Prerelease:  g.Prerelease,
+		Assets:      make([]AssetInfo, len(g.Assets)),
+	}
+	for i, asset := range g.Assets {
+		info.Assets[i] = AssetInfo{
+			Name:        asset.Name,
+			URL:         asset.BrowserDownloadUrl,
+			APIURL:      asset.Url,
+			Size:        int64(asset.Size),
+			ContentType: asset.ContentType,
+			Digest:      asset.Digest,
+			Label:       asset.Label,
+		}
+	}
+	return info
+}
+
+// GetReleaseLink returns the download URL of the asset for the current platform
 func (g *GithubReleaseResponse) GetReleaseLink() string {
 	return g.GetReleaseLinkWithConfig(DefaultAssetMatchingConfig())
 }
 
+// GetReleaseLinkWithConfig returns the download URL of the asset selected by config, or "" if no
+// asset matches. Selection happens on the shared release model (see ReleaseInfo.SelectAsset).
 func (g *GithubReleaseResponse) GetReleaseLinkWithConfig(config AssetMatchingConfig) string {
-	browser, _ := g.getMatchedAssetURLs(config)
-	return browser
+	asset, _ := g.ToReleaseInfo().SelectAsset(config)
+	return asset.URL
 }
 
 // GetAPILinkWithConfig returns the GitHub API URL for the matched asset.
 // Use this with Accept: application/octet-stream for authenticated downloads from private repos.
 func (g *GithubReleaseResponse) GetAPILinkWithConfig(config AssetMatchingConfig) string {
-	_, api := g.getMatchedAssetURLs(config)
-	return api
-}
-
-func (g *GithubReleaseResponse) getMatchedAssetURLs(config AssetMatchingConfig) (browserURL, apiURL string) {
-	// Extract asset names
-	assetNames := make([]string, len(g.Assets))
-	browserMap := make(map[string]string)
-	apiMap := make(map[string]string)
-
-	for i, asset := range g.Assets {
-		assetNames[i] = asset.Name
-		browserMap[asset.Name] = asset.BrowserDownloadUrl
-		apiMap[asset.Name] = asset.Url
-	}
-
-	// Use asset matcher to find the best match
-	matcher := NewAssetMatcher(config)
-	bestMatch, err := matcher.FindBestMatch(assetNames)
-	if err != nil {
-		// Fallback to legacy matching for backward compatibility
-		return g.getLegacyReleaseLink(), ""
-	}
-
-	return browserMap[bestMatch], apiMap[bestMatch]
-}
-
-// getLegacyReleaseLink provides backward compatibility with the old matching logic
-func (g *GithubReleaseResponse) getLegacyReleaseLink() string {
-	runtimeOS := runtime.GOOS
-	arch := MapArch(runtime.GOARCH)
-
-	title := cases.Title(language.AmericanEnglish)
-	searchKey := fmt.Sprintf("%s_%s", title.String(runtimeOS), arch)
-
-	for _, asset := range g.Assets {
-		if strings.Contains(asset.Name, searchKey) {
-			return asset.BrowserDownloadUrl
-		}
-	}
-	return ""
+	asset, _ := g.ToReleaseInfo().SelectAsset(config)
+	return asset.APIURL
 }
@@ -24,6 +24,7 @@ func TestGithubReleaseResponse_GetReleaseLink(t *testing.T) {
 			DownloadCount      int       `json:"download_count"`
 			Url                string    `json:"url"`
 			BrowserDownloadUrl string    `json:"browser_download_url"`
+			Digest             string    `json:"digest"`
 			CreatedAt          time.Time `json:"created_at"`
 			UpdatedAt          time.Time `json:"updated_at"`
 		}
@@ -45,6 +46,7 @@ func TestGithubReleaseResponse_GetReleaseLink(t *testing.T) {
 					DownloadCount      int       `json:"download_count"`
 					Url                string    `json:"url"`
 					BrowserDownloadUrl string    `json:"browser_download_url"`
+					Digest             string    `json:"digest"`
 					CreatedAt          time.Time `json:"created_at"`
 					UpdatedAt          time.Time `json:"updated_at"`
 				}{
@@ -72,6 +74,7 @@ func TestGithubReleaseResponse_GetReleaseLink(t *testing.T) {
 					DownloadCount      int       `json:"download_count"`
 					Url                string    `json:"url"`
 					BrowserDownloadUrl string    `json:"browser_download_url"`
+					Digest             string    `json:"digest"`
 					CreatedAt          time.Time `json:"created_at"`
 					UpdatedAt          time.Time `json:"updated_at"`
 				}{
@@ -95,6 +98,7 @@ func TestGithubReleaseResponse_GetReleaseLink(t *testing.T) {
 					DownloadCount      int       `json:"download_count"`
 					Url                string    `json:"url"`
 					BrowserDownloadUrl string    `json:"browser_download_url"`
+					Digest             string    `json:"digest"`
 					CreatedAt          time.Time `json:"created_at"`
 					UpdatedAt          time.Time `json:"updated_at"`
 				}{},
@@ -113,6 +117,7 @@ func TestGithubReleaseResponse_GetReleaseLink(t *testing.T) {
 					DownloadCount      int       `json:"download_count"`
 					Url                string    `json:"url"`
 					BrowserDownloadUrl string    `json:"browser_download_url"`
+					Digest             string    `json:"digest"`
 					CreatedAt          time.Time `json:"created_at"`
 					UpdatedAt          time.Time `json:"updated_at"`
 				}{
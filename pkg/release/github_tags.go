@@ -0,0 +1,93 @@
+package release
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// errNoGitHubReleases is returned when a repository lists no published releases
+var errNoGitHubReleases = noMatchErrorf(ErrNoMatchingRelease, "no published GitHub releases")
+
+// githubTagsPerPage is the number of tags requested, the most GitHub returns per page
+const githubTagsPerPage = 100
+
+// tagReleases lists one release without assets per tag, so tags are selected like releases
+type tagReleases []ReleaseInfo
+
+// LatestRelease is not used: tags are always selected from the list
+func (t tagReleases) LatestRelease() (*ReleaseInfo, error) {
+	return nil, fmt.Errorf("tags have no latest release")
+}
+
+// Releases returns the tags as releases
+func (t tagReleases) Releases() ([]ReleaseInfo, error) {
+	return t, nil
+}
+
+// Tags fetches the names of the repository's most recent tags (up to 100) from the GitHub API
+func (s *githubAPISource) Tags() ([]string, error) {
+	apiURL, err := s.release.GetApiUrl()
+	if err != nil {
+		return nil, fmt.Errorf("error constructing GitHub API URL: %w", err)
+	}
+	tagsURL := fmt.Sprintf("%s/tags?per_page=%d", strings.TrimSuffix(apiURL, "/releases/latest"), githubTagsPerPage)
+
+	body, err := s.fetch(tagsURL)
+	if err != nil {
+		return nil, err
+	}
+	var tags []struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(body, &tags); err != nil {
+		return nil, fmt.Errorf("error decoding GitHub tags: %v", err)
+	}
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+	return names, nil
+}
+
+// latestTag returns the highest stable semver tag of the repository, honoring VersionConstraint and
+// the TagPattern and IgnoreTags of Selection
+func (g *GithubRelease) latestTag() (string, error) {
+	names, err := (&githubAPISource{release: g}).Tags()
+	if err != nil {
+		return "", err
+	}
+	releases := make(tagReleases, len(names))
+	for i, name := range names {
+		releases[i] = ReleaseInfo{Version: name}
+	}
+	selection := g.Selection
+	selection.Policy = SelectBySemver
+	latest, err := latestReleaseMatching(releases, g.VersionConstraint, selection)
+	if err != nil {
+		return "", err
+	}
+	return latest.Version, nil
+}
+
+// resolveLatestVersion resolves the latest release for CDN downloads, which only need its version.
+// Repositories that push tags without creating releases answer releases/latest with 404 and list
+// no releases; their highest semver tag is used instead.
+func (g *GithubRelease) resolveLatestVersion() error {
+	err := g.GetLatestRelease()
+	var statusErr *HTTPStatusError
+	notFound := errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound
+	if err == nil || !(notFound || errors.Is(err, errNoGitHubReleases)) {
+		return err
+	}
+	tag, tagErr := g.latestTag()
+	if tagErr != nil {
+		return fmt.Errorf("%w (tags fallback failed: %v)", err, tagErr)
+	}
+	fmt.Printf("No GitHub release found, using the latest tag %s\n", tag)
+	g.Version = tag
+	g.Info = &ReleaseInfo{Version: tag}
+	return nil
+}
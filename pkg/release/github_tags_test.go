@@ -0,0 +1,65 @@
+package release
+
+import (
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestGithubRelease_TagsFallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/cdn/myapp-1.10.0-"+runtime.GOOS+"-"):
+			fmt.Fprint(w, "binary")
+		case r.URL.Path == "/api/owner/repo/releases":
+			fmt.Fprint(w, `[]`)
+		case r.URL.Path == "/api/owner/repo/tags":
+			fmt.Fprint(w, `[{"name": "v2.0.0-rc.1"}, {"name": "v1.9.0"}, {"name": "nightly"}, {"name": "v1.10.0"}, {"name": "v1.2.0"}]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	newRelease := func() *GithubRelease {
+		assetConfig := DefaultAssetMatchingConfig()
+		assetConfig.Strategy = CDNStrategy
+		assetConfig.CDNBaseURL = server.URL + "/cdn/"
+		assetConfig.CDNPattern = "myapp-{version}-{os}-{arch}"
+		assetConfig.CDNVersionFormat = "without-v"
+		assetConfig.IsDirectBinary = true
+		release := NewGithubReleaseWithAssetConfig("owner/repo", fileUtils.FileConfig{
+			BinaryName:          "myapp",
+			BaseBinaryDirectory: t.TempDir(),
+			SourceArchivePath:   filepath.Join(t.TempDir(), "myapp"),
+			IsDirectBinary:      true,
+		}, assetConfig)
+		release.BaseURL = server.URL + "/api"
+		return release
+	}
+
+	// releases/latest answers 404 without releases; the highest stable semver tag is used
+	release := newRelease()
+	if err := release.DownloadLatestRelease(); err != nil {
+		t.Fatalf("DownloadLatestRelease failed: %v", err)
+	}
+	if release.Version != "v1.10.0" {
+		t.Errorf("Expected the latest tag v1.10.0, got %s", release.Version)
+	}
+	if data, _ := os.ReadFile(release.Config.SourceArchivePath); string(data) != "binary" {
+		t.Errorf("Expected the CDN binary to be downloaded, got %q", data)
+	}
+
+	// Version constraints list releases, which are empty, and then select among the tags
+	constrained := newRelease()
+	constrained.VersionConstraint = "~1.9"
+	if err := constrained.resolveLatestVersion(); err != nil || constrained.Version != "v1.9.0" {
+		t.Errorf("Expected v1.9.0 for ~1.9, got %s, %v", constrained.Version, err)
+	}
+}
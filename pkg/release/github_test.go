@@ -4,6 +4,8 @@ import (
 	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -62,6 +64,7 @@ func TestGithubRelease_GetApiUrl(t *testing.T) {
 		name       string
 		repository string
 		baseURL    string
+		apiURL     string
 		want       string
 		wantErr    bool
 	}{
@@ -79,6 +82,13 @@ func TestGithubRelease_GetApiUrl(t *testing.T) {
 			want:       "https://api.example.com/owner/repo/releases/latest",
 			wantErr:    false,
 		},
+		{
+			name:       "GitHub Enterprise Server host",
+			repository: "owner/repo",
+			apiURL:     "https://github.example.com/",
+			want:       "https://github.example.com/api/v3/repos/owner/repo/releases/latest",
+			wantErr:    false,
+		},
 		{
 			name:       "Invalid repository format - missing slash",
 			repository: "ownerrepo",
@@ -114,6 +124,7 @@ func TestGithubRelease_GetApiUrl(t *testing.T) {
 			g := &GithubRelease{
 				Repository: tt.repository,
 				BaseURL:    tt.baseURL,
+				APIURL:     tt.apiURL,
 			}
 			got, err := g.GetApiUrl()
 			if (err != nil) != tt.wantErr {
@@ -292,3 +303,167 @@ func TestNewGithubReleaseWithToken(t *testing.T) {
 		t.Errorf("Expected binary name 'test-binary', got '%s'", release.Config.BinaryName)
 	}
 }
+
+func TestNormalizeGitHubAPIURL(t *testing.T) {
+	tests := map[string]string{
+		"":                                   "https://api.github.com",
+		"https://github.com":                 "https://api.github.com",
+		"https://api.github.com/":            "https://api.github.com",
+		"https://github.example.com":         "https://github.example.com/api/v3",
+		"https://github.example.com/api/v3/": "https://github.example.com/api/v3",
+		"https://api.acme.ghe.com":           "https://api.acme.ghe.com",
+	}
+	for input, want := range tests {
+		if got := NormalizeGitHubAPIURL(input); got != want {
+			t.Errorf("NormalizeGitHubAPIURL(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestNormalizeGitHubUploadURL(t *testing.T) {
+	tests := map[string]string{
+		"":                           "https://uploads.github.com",
+		"https://github.com":         "https://uploads.github.com",
+		"https://github.example.com": "https://github.example.com/api/uploads",
+		"https://api.acme.ghe.com":   "https://uploads.acme.ghe.com",
+	}
+	for input, want := range tests {
+		if got := NormalizeGitHubUploadURL(input); got != want {
+			t.Errorf("NormalizeGitHubUploadURL(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestGithubRelease_DownloadURL(t *testing.T) {
+	release := NewGithubRelease("owner/repo", fileUtils.FileConfig{BinaryName: "myapp"})
+	release.DownloadURL = "https://assets.example.com/github/"
+	release.AssetMatchingConfig.CustomPatterns = []string{"myapp.tar.gz"}
+	release.Source = &fakeAssetSource{release: &ReleaseInfo{
+		Version: "v1.0.0",
+		Assets: []AssetInfo{{
+			Name:   "myapp.tar.gz",
+			URL:    "https://github.example.com/owner/repo/releases/download/v1.0.0/myapp.tar.gz",
+			APIURL: "https://github.example.com/api/v3/repos/owner/repo/releases/assets/1",
+		}},
+	}}
+	if err := release.GetLatestRelease(); err != nil {
+		t.Fatalf("GetLatestRelease failed: %v", err)
+	}
+	if want := "https://assets.example.com/github/owner/repo/releases/download/v1.0.0/myapp.tar.gz"; release.ReleaseLink != want {
+		t.Errorf("Expected the download link %s, got %s", want, release.ReleaseLink)
+	}
+	if release.APILink != "https://github.example.com/api/v3/repos/owner/repo/releases/assets/1" {
+		t.Errorf("Expected the API link to stay on the API host, got %s", release.APILink)
+	}
+}
+
+func TestNewGithubRelease_APIURLFromEnvironment(t *testing.T) {
+	t.Setenv("GITHUB_API_URL", "https://github.example.com/api/v3")
+
+	release := NewGithubRelease("owner/repo", fileUtils.FileConfig{})
+	got, err := release.GetApiUrl()
+	if err != nil {
+		t.Fatalf("GetApiUrl() error = %v", err)
+	}
+	if want := "https://github.example.com/api/v3/repos/owner/repo/releases/latest"; got != want {
+		t.Errorf("GetApiUrl() = %s, want %s", got, want)
+	}
+}
+
+func TestGithubRelease_GetRelease(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/owner/repo/releases/tags/v1.2.0" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{
+			"tag_name": "v1.2.0",
+			"assets": [{
+				"name": "myapp-Linux_x86_64.tar.gz",
+				"browser_download_url": "https://example.com/v1.2.0/linux"
+			}]
+		}`))
+	}))
+	defer server.Close()
+
+	release := NewGithubRelease("owner/repo", fileUtils.FileConfig{ProjectName: "myapp"})
+	release.BaseURL = server.URL
+
+	if err := release.GetRelease("v1.2.0"); err != nil {
+		t.Fatalf("GetRelease failed: %v", err)
+	}
+	if release.Version != "v1.2.0" || release.ReleaseLink != "https://example.com/v1.2.0/linux" {
+		t.Errorf("Expected the v1.2.0 linux asset, got %s (%s)", release.ReleaseLink, release.Version)
+	}
+	if err := release.GetRelease("v9.9.9"); err == nil {
+		t.Error("Expected an error for a missing release")
+	}
+}
+
+func TestGithubRelease_DownloadPrivateAsset(t *testing.T) {
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Error("Expected no token at the pre-signed storage URL")
+		}
+		w.Write([]byte("private archive"))
+	}))
+	defer storage.Close()
+	var browserAuth []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/repo/releases/assets/1":
+			if r.Header.Get("Authorization") != "Bearer secret" || r.Header.Get("Accept") != "application/octet-stream" {
+				http.NotFound(w, r)
+				return
+			}
+			http.Redirect(w, r, storage.URL+"/signed?token=abc", http.StatusFound)
+		case "/owner/repo/releases/download/v1.0.0/myapp-Linux_x86_64.tar.gz":
+			browserAuth = append(browserAuth, r.Header.Get("Authorization"))
+			w.Write([]byte("public archive"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer api.Close()
+
+	newRelease := func(token, apiURL string) *GithubRelease {
+		release := NewGithubReleaseWithToken("owner/repo", token, fileUtils.FileConfig{
+			BaseBinaryDirectory: filepath.Join(t.TempDir(), "bin"),
+			ProjectName:         "myapp",
+			StagingDirectory:    t.TempDir(),
+		})
+		release.Source = &fakeAssetSource{release: &ReleaseInfo{
+			Version: "v1.0.0",
+			Assets: []AssetInfo{{
+				Name:   "myapp-Linux_x86_64.tar.gz",
+				URL:    api.URL + "/owner/repo/releases/download/v1.0.0/myapp-Linux_x86_64.tar.gz",
+				APIURL: apiURL,
+			}},
+		}}
+		return release
+	}
+	download := func(release *GithubRelease) string {
+		t.Helper()
+		if err := release.DownloadLatestRelease(); err != nil {
+			t.Fatalf("DownloadLatestRelease failed: %v", err)
+		}
+		data, _ := os.ReadFile(release.getTempSourceArchivePath())
+		return string(data)
+	}
+
+	if got := download(newRelease("secret", api.URL+"/repos/owner/repo/releases/assets/1")); got != "private archive" {
+		t.Errorf("Expected the asset from the API URL, got %q", got)
+	}
+	// Without a token, or without an API URL, the browser URL is used and receives no token
+	if got := download(newRelease("", api.URL+"/repos/owner/repo/releases/assets/1")); got != "public archive" {
+		t.Errorf("Expected the browser download without a token, got %q", got)
+	}
+	if got := download(newRelease("secret", "")); got != "public archive" {
+		t.Errorf("Expected the browser download without an API URL, got %q", got)
+	}
+	for _, auth := range browserAuth {
+		if auth != "" {
+			t.Errorf("Expected no token at the browser download URL, got %q", auth)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package release
+
+import (
+	"encoding/xml"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"html"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// latestReleaseFromWeb resolves the latest release through github.com pages, which do not count
+// against the API rate limit, so unauthenticated CI runs can still install. The tag is read from
+// the releases/latest redirect, falling back to the releases Atom feed, and the asset names from
+// the release's expanded assets page. Sizes, digests and API download URLs stay unknown, so only
+// public repositories can be installed this way.
+func (g *GithubRelease) latestReleaseFromWeb(root string) (*ReleaseInfo, error) {
+	tag, err := g.latestTagFromRedirect(root)
+	if err != nil {
+		var feedErr error
+		if tag, feedErr = g.latestTagFromFeed(root); feedErr != nil {
+			return nil, fmt.Errorf("%v; %v", err, feedErr)
+		}
+	}
+	log.Printf("GitHub API rate limited, resolved the latest release %s from %s", tag, root)
+
+	assets, err := g.webAssets(root, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &ReleaseInfo{Version: tag, Assets: assets}, nil
+}
+
+// webRoot returns the web root of the repository's GitHub instance, e.g. https://github.com for
+// the public API and https://github.example.com for GitHub Enterprise Server. It is unknown for
+// test API URLs (BaseURL) and data residency hosts unless WebURL is set.
+func (g *GithubRelease) webRoot() (string, error) {
+	if g.WebURL != "" {
+		return strings.TrimSuffix(g.WebURL, "/"), nil
+	}
+	if g.BaseURL != "" {
+		return "", fmt.Errorf("no WebURL configured for the API at %s", g.BaseURL)
+	}
+	apiRoot := NormalizeGitHubAPIURL(g.APIURL)
+	if apiRoot == DefaultGitHubAPIURL {
+		return "https://github.com", nil
+	}
+	if root, ok := strings.CutSuffix(apiRoot, "/api/v3"); ok {
+		return root, nil
+	}
+	return "", fmt.Errorf("cannot derive the web URL from the API URL %s, set WebURL", apiRoot)
+}
+
+// latestTagFromRedirect reads the tag from the redirect of releases/latest to releases/tag/<tag>
+func (g *GithubRelease) latestTagFromRedirect(root string) (string, error) {
+	client := g.webClient()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	resp, err := g.webGet(client, root+"/"+g.Repository+"/releases/latest")
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	location, err := resp.Location()
+	if err != nil {
+		return "", fmt.Errorf("GitHub did not redirect releases/latest: %w", newHTTPStatusError(resp))
+	}
+	_, escapedTag, ok := strings.Cut(location.EscapedPath(), "/releases/tag/")
+	if !ok || escapedTag == "" {
+		return "", fmt.Errorf("GitHub redirected releases/latest to %s, which names no release", location)
+	}
+	return url.PathUnescape(escapedTag)
+}
+
+// latestTagFromFeed reads the tag of the newest stable entry of the releases Atom feed. Unlike the
+// redirect, the feed also lists prereleases without marking them, so tags with a semver prerelease
+// suffix (e.g. v1.2.0-rc.1) are skipped.
+func (g *GithubRelease) latestTagFromFeed(root string) (string, error) {
+	body, err := g.webPage(root + "/" + g.Repository + "/releases.atom")
+	if err != nil {
+		return "", err
+	}
+	var feed struct {
+		Entries []struct {
+			Link struct {
+				Href string `xml:"href,attr"`
+			} `xml:"link"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal(body, &feed); err != nil {
+		return "", fmt.Errorf("error decoding GitHub releases feed: %v", err)
+	}
+	for _, entry := range feed.Entries {
+		_, escapedTag, ok := strings.Cut(entry.Link.Href, "/releases/tag/")
+		if !ok || escapedTag == "" {
+			continue
+		}
+		tag, err := url.PathUnescape(escapedTag)
+		if err != nil {
+			continue
+		}
+		if version, err := ParseSemVersion(tag); err == nil && version.Prerelease != "" {
+			continue
+		}
+		return tag, nil
+	}
+	return "", fmt.Errorf("GitHub releases feed of %s lists no stable release", g.Repository)
+}
+
+// webAssetLink matches release download links on a GitHub release page
+var webAssetLink = regexp.MustCompile(`href="([^"]*/releases/download/[^"]+)"`)
+
+// webAssets lists the assets linked from the release's expanded assets page
+func (g *GithubRelease) webAssets(root, tag string) ([]AssetInfo, error) {
+	body, err := g.webPage(root + "/" + g.Repository + "/releases/expanded_assets/" + url.PathEscape(tag))
+	if err != nil {
+		return nil, err
+	}
+	base, err := url.Parse(root + "/")
+	if err != nil {
+		return nil, err
+	}
+	prefix := "/" + g.Repository + "/releases/download/"
+	var assets []AssetInfo
+	seen := map[string]bool{}
+	for _, match := range webAssetLink.FindAllStringSubmatch(string(body), -1) {
+		link, err := base.Parse(html.UnescapeString(match[1]))
+		if err != nil || !strings.Contains(link.Path, prefix) {
+			continue
+		}
+		name := path.Base(link.Path)
+		if !seen[name] {
+			seen[name] = true
+			assets = append(assets, AssetInfo{Name: name, URL: link.String()})
+		}
+	}
+	if len(assets) == 0 {
+		return nil, fmt.Errorf("no assets found on the GitHub page of release %s", tag)
+	}
+	return assets, nil
+}
+
+// webPage fetches a GitHub page, limited to MaxMetadataSize
+func (g *GithubRelease) webPage(pageURL string) ([]byte, error) {
+	resp, err := g.webGet(g.webClient(), pageURL)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status code from %s: %w", pageURL, newHTTPStatusError(resp))
+	}
+	body, err := newLimitedBody(resp, g.MaxMetadataSize)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", pageURL, err)
+	}
+	return readMetadata("GitHub", body)
+}
+
+// webGet requests a GitHub page without credentials, which pages do not need
+func (g *GithubRelease) webGet(client *http.Client, pageURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(g.Config.Context(), "GET", pageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating HTTP request: %w", err)
+	}
+	fileUtils.SetUserAgent(req)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making HTTP request to GitHub: %w", err)
+	}
+	return resp, nil
+}
+
+// webClient returns a client with the metadata timeout
+func (g *GithubRelease) webClient() *http.Client {
+	timeout := g.MetadataTimeout
+	if timeout == 0 {
+		timeout = DefaultMetadataTimeout
+	}
+	return fileUtils.NewHTTPClient(timeout)
+}
@@ -0,0 +1,92 @@
+package release
+
+import (
+	"errors"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+)
+
+func TestGithubRelease_WebFallbackWhenRateLimited(t *testing.T) {
+	asset := fmt.Sprintf("myapp_%s_%s.tar.gz", runtime.GOOS, runtime.GOARCH)
+	redirect := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/owner/repo/releases/latest":
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.WriteHeader(http.StatusForbidden)
+		case "/owner/repo/releases/latest":
+			if !redirect {
+				http.NotFound(w, r)
+				return
+			}
+			http.Redirect(w, r, "/owner/repo/releases/tag/v1.2.3", http.StatusFound)
+		case "/owner/repo/releases.atom":
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <entry><link rel="alternate" type="text/html" href="https://github.com/owner/repo/releases/tag/v1.3.0-rc.1"/></entry>
+  <entry><link rel="alternate" type="text/html" href="https://github.com/owner/repo/releases/tag/v1.2.3"/></entry>
+  <entry><link rel="alternate" type="text/html" href="https://github.com/owner/repo/releases/tag/v1.2.2"/></entry>
+</feed>`)
+		case "/owner/repo/releases/expanded_assets/v1.2.3":
+			fmt.Fprintf(w, `<a href="/owner/repo/releases/download/v1.2.3/%[1]s" rel="nofollow">%[1]s</a>
+<a href="/owner/repo/releases/download/v1.2.3/checksums.txt" rel="nofollow">checksums.txt</a>
+<a href="/other/repo/releases/download/v1.2.3/other.tar.gz" rel="nofollow">other.tar.gz</a>
+<a href="/owner/repo/archive/refs/tags/v1.2.3.zip" rel="nofollow">Source code</a>`, asset)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	newRelease := func() *GithubRelease {
+		release := NewGithubRelease("owner/repo", fileUtils.FileConfig{BinaryName: "myapp"})
+		release.BaseURL = server.URL + "/api"
+		release.WebURL = server.URL
+		return release
+	}
+
+	for _, useRedirect := range []bool{true, false} {
+		redirect = useRedirect
+		release := newRelease()
+		if err := release.GetLatestRelease(); err != nil {
+			t.Fatalf("GetLatestRelease (redirect %v) failed: %v", useRedirect, err)
+		}
+		want := server.URL + "/owner/repo/releases/download/v1.2.3/" + asset
+		if release.Version != "v1.2.3" || release.ReleaseLink != want {
+			t.Errorf("Expected v1.2.3 with %s, got %s with %s", want, release.Version, release.ReleaseLink)
+		}
+		if len(release.Info.Assets) != 2 {
+			t.Errorf("Expected the two release assets, got %+v", release.Info.Assets)
+		}
+	}
+
+	release := newRelease()
+	release.DisableWebFallback = true
+	if err := release.GetLatestRelease(); !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected the rate-limit error without the fallback, got %v", err)
+	}
+}
+
+func TestGithubRelease_WebRoot(t *testing.T) {
+	tests := []struct {
+		apiURL, webURL, want string
+	}{
+		{"", "", "https://github.com"},
+		{"https://github.com", "", "https://github.com"},
+		{"https://github.example.com", "", "https://github.example.com"},
+		{"https://github.example.com/api/v3/", "", "https://github.example.com"},
+		{"https://api.example.ghe.com", "https://example.ghe.com/", "https://example.ghe.com"},
+		{"https://api.example.ghe.com", "", ""},
+	}
+	for _, tt := range tests {
+		release := &GithubRelease{APIURL: tt.apiURL, WebURL: tt.webURL}
+		got, err := release.webRoot()
+		if (err != nil) != (tt.want == "") || got != tt.want {
+			t.Errorf("webRoot(%q, %q) = %q, %v; want %q", tt.apiURL, tt.webURL, got, err, tt.want)
+		}
+	}
+}
@@ -1,14 +1,17 @@
 package release
 
 import (
-	"encoding/json"
+	"bytes"
+	"errors"
 	"fmt"
 	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
-	"path"
 	"runtime"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -16,8 +19,9 @@ import (
 
 // Default GitLab API configuration
 const (
-	DefaultGitLabAPIURL = "https://gitlab.com/api/v4"
-	GitLabAPIVersion    = "v4"
+	DefaultGitLabAPIURL  = "https://gitlab.com/api/v4"
+	GitLabAPIVersion     = "v4"
+	DefaultGitLabPerPage = 100 // Releases per page; also the maximum GitLab allows
 )
 
 // GitLabConfig holds configuration for GitLab API access
@@ -26,6 +30,20 @@ type GitLabConfig struct {
 	Token         string            // Personal Access Token or Project Access Token
 	HTTPConfig    HTTPClientConfig  // HTTP client configuration with retry logic
 	CustomHeaders map[string]string // Additional headers for requests
+	TokenProvider TokenProvider     `json:"-"`                     // Optional token source, takes precedence over Token
+	Credentials   *CredentialConfig `json:"credentials,omitempty"` // Optional credential source from configuration, used when TokenProvider is nil
+	MaxMetadataSize int64           `json:"max_metadata_size"`     // Maximum API response size in bytes (default: DefaultMaxMetadataResponseSize)
+	PerPage       int               `json:"per_page"`              // Releases requested per page, at most 100 (default: DefaultGitLabPerPage)
+	MaxPages      int               `json:"max_pages"`             // Maximum number of release pages fetched when listing releases (default: 0, all pages)
+	LinkMetadata  bool              `json:"link_metadata"`         // Complete link types from the release links endpoint and the selected asset's size with a HEAD request
+}
+
+// perPage returns the configured page size, limited to what GitLab serves
+func (c GitLabConfig) perPage() int {
+	if c.PerPage <= 0 || c.PerPage > DefaultGitLabPerPage {
+		return DefaultGitLabPerPage
+	}
+	return c.PerPage
 }
 
 // DefaultGitLabConfig returns a default GitLab configuration
@@ -44,14 +62,47 @@ type GitLabRelease struct {
 	Config      fileUtils.FileConfig `json:"config"`
 	GitLabConfig GitLabConfig        `json:"gitlab_config"` // Enhanced configuration
 	httpClient  *RetryableHTTPClient // HTTP client with retry logic
+	assetClient *RetryableHTTPClient // HTTP client for authenticated asset downloads
 	AssetMatchingConfig AssetMatchingConfig `json:"asset_matching_config"` // Configuration for asset matching
+	MetadataCache *MetadataCache     `json:"-"`            // Optional cache enabling conditional (ETag) API requests
+	NotModified bool                 `json:"not_modified"` // True if the last GetLatestRelease was answered from cache via 304
+	Source      AssetSource          `json:"-"`            // Optional provider override (defaults to the GitLab API)
+	Warnings    []string             `json:"warnings"`     // Non-fatal notes from asset selection (e.g. Rosetta fallback)
+	VersionConstraint string         `json:"version_constraint"` // Optional semver range (e.g. "^1.28", "~3.12") limiting which release is installed
+	Selection   ReleaseSelection     `json:"selection"`    // How the latest release is chosen (default: the provider's latest release)
+	Info        *ReleaseInfo         `json:"release_info"` // Metadata of the release found by GetLatestRelease (name, notes, date, assets)
+	DeltaApplied bool                `json:"delta_applied"` // True if the last download was produced by applying a delta patch
+	Force       bool                 `json:"force"`        // Download and install the resolved version even if it is already installed
+	AlreadyInstalled bool            `json:"already_installed"` // True if the last download was skipped because the resolved version is already installed (see Force)
+	AssetName   string               `json:"asset_name"`   // File name of the selected asset
+	deltaBinaryPath string           // Patched binary awaiting installation after a delta update
+	downloadedFrom  string           // URL of the last full download (release asset or CDN)
+	cdnFileName     string           // File name of the last CDN download (empty for release assets)
+	downloadCleanup string           // Download cleanup status of the last installation
+	downloadedVersion string         // Version downloaded by the last DownloadRelease call
+	lastDownload    *DownloadResult  // Result of the last DownloadLatestReleaseWithResult
 }
 
+// getTempSourceArchivePath returns where the asset is downloaded to, defaulting to the downloaded
+// file's name in the staging directory
 func (r *GitLabRelease) getTempSourceArchivePath() string {
-	if r.Config.SourceArchivePath != "" {
-		return r.Config.SourceArchivePath
+	return fileUtils.GetStagingPath(r.Config, "gitlab", r.Version, r.stagedFileName())
+}
+
+// stagedFileName returns the name of the downloaded file: the CDN file for CDN downloads,
+// otherwise the selected release asset
+func (r *GitLabRelease) stagedFileName() string {
+	if r.cdnFileName != "" {
+		return r.cdnFileName
 	}
-	return path.Join("/tmp", fmt.Sprintf("binary-%s.tar.gz", r.Version))
+	return r.AssetName
+}
+
+// stagedConfig returns the file configuration with SourceArchivePath resolved to the download location
+func (r *GitLabRelease) stagedConfig() fileUtils.FileConfig {
+	config := r.Config
+	config.SourceArchivePath = r.getTempSourceArchivePath()
+	return config
 }
 
 // initializeHTTPClient initializes the HTTP client if not already done
@@ -65,6 +116,23 @@ func (r *GitLabRelease) initializeHTTPClient() {
 	}
 }
 
+// checkGitLabAPIVersion rejects base URLs that address another GitLab API version than the one
+// responses are decoded against
+func checkGitLabAPIVersion(baseURL string) error {
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid GitLab base URL %s: %w", baseURL, err)
+	}
+	segments := strings.Split(strings.Trim(parsed.Path, "/"), "/")
+	for i := 0; i+1 < len(segments); i++ {
+		version := segments[i+1]
+		if segments[i] == "api" && strings.HasPrefix(version, "v") && version != GitLabAPIVersion {
+			return fmt.Errorf("unsupported GitLab API version %s in %s, expected %s", version, baseURL, GitLabAPIVersion)
+		}
+	}
+	return nil
+}
+
 // GetApiUrl constructs the GitLab API URL for releases
 func (r *GitLabRelease) GetApiUrl() (string, error) {
 	// Validate project ID
@@ -89,18 +157,41 @@ func (r *GitLabRelease) GetApiUrl() (string, error) {
 
 	// Remove trailing slash if present
 	baseURL = strings.TrimSuffix(baseURL, "/")
+	if err := checkGitLabAPIVersion(baseURL); err != nil {
+		return "", err
+	}
 
 	// Construct the releases endpoint URL
 	return fmt.Sprintf("%s/projects/%s/releases", baseURL, r.ProjectId), nil
 }
 
+// authToken returns the API token, preferring the TokenProvider, then configured Credentials, then the plain Token
+func (r *GitLabRelease) authToken() (string, error) {
+	provider := r.GitLabConfig.TokenProvider
+	if provider == nil && r.GitLabConfig.Credentials != nil {
+		provider = r.GitLabConfig.Credentials
+	}
+	if provider == nil {
+		return r.GitLabConfig.Token, nil
+	}
+	token, err := provider.Token()
+	if err != nil {
+		return "", fmt.Errorf("error obtaining GitLab token: %w", err)
+	}
+	return token, nil
+}
+
 // getAuthHeaders returns authentication headers if token is configured
-func (r *GitLabRelease) getAuthHeaders() map[string]string {
+func (r *GitLabRelease) getAuthHeaders() (map[string]string, error) {
 	headers := make(map[string]string)
 
 	// Add authentication header if token is provided
-	if r.GitLabConfig.Token != "" {
-		headers["Authorization"] = "Bearer " + r.GitLabConfig.Token
+	token, err := r.authToken()
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		headers["Authorization"] = "Bearer " + token
 	}
 
 	// Add custom headers
@@ -110,108 +201,344 @@ func (r *GitLabRelease) getAuthHeaders() map[string]string {
 
 	// Add standard headers
 	headers["Accept"] = "application/json"
-	headers["User-Agent"] = "go-binary-updater/1.0"
+	headers["User-Agent"] = fileUtils.GetClientInfo().UserAgent()
 
-	return headers
+	return headers, nil
 }
 
 func (r *GitLabRelease) GetLatestRelease() error {
 	log.Println("Fetching latest release from GitLab")
+	info, err := latestReleaseMatching(r.assetSource(), r.VersionConstraint, r.Selection)
+	if err != nil {
+		return err
+	}
+	return r.useRelease(info)
+}
 
-	// Initialize HTTP client
-	r.initializeHTTPClient()
+// GetRelease fetches the release tagged version and selects its asset for the current platform
+func (r *GitLabRelease) GetRelease(version string) error {
+	log.Printf("Fetching release %s from GitLab", version)
+	info, err := releaseByVersion(r.assetSource(), r.Selection.Tag(version))
+	if err != nil {
+		return err
+	}
+	return r.useRelease(r.Selection.withTagVersion(info))
+}
+
+// useRelease records the release and selects its asset for the current platform
+func (r *GitLabRelease) useRelease(info *ReleaseInfo) error {
+	r.Info = info
+	r.Version = info.Version
+	if r.GitLabConfig.LinkMetadata {
+		r.resolveLinkTypes(info)
+	}
+
+	// Find platform-specific release link
+	asset, warnings, err := info.selectAsset(r.AssetMatchingConfig)
+	if err != nil || asset.URL == "" {
+		return noMatchErrorf(ErrNoMatchingAsset, "no suitable asset found for current platform (%s/%s) in GitLab release %s",
+			runtime.GOOS, runtime.GOARCH, info.Version)
+	}
+	if r.GitLabConfig.LinkMetadata {
+		r.resolveAssetSize(info, &asset)
+	}
+
+	r.ReleaseLink = asset.URL
+	r.AssetName = asset.Name
+	r.Warnings = warnings
+	return nil
+}
+
+// assetSource returns the configured AssetSource, defaulting to the GitLab REST API
+func (r *GitLabRelease) assetSource() AssetSource {
+	if r.Source != nil {
+		return r.Source
+	}
+	return &gitlabAPISource{release: r}
+}
+
+// gitlabAPISource fetches release metadata from the GitLab REST API
+type gitlabAPISource struct {
+	release *GitLabRelease
+}
+
+// LatestRelease returns the most recently released release from the GitLab API. Pages are
+// fetched until an installable release is found, which usually takes a single request.
+func (s *gitlabAPISource) LatestRelease() (*ReleaseInfo, error) {
+	var latest *ReleaseInfo
+	err := s.walkReleases(func(info *ReleaseInfo) bool {
+		latest = info
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	if latest == nil {
+		return nil, noMatchErrorf(ErrNoMatchingRelease, "no GitLab releases found for project ID %s", s.release.ProjectId)
+	}
+	return latest, nil
+}
+
+// Releases fetches all releases from the GitLab API, or those on the first MaxPages pages, most
+// recently released first
+func (s *gitlabAPISource) Releases() ([]ReleaseInfo, error) {
+	var releases []ReleaseInfo
+	err := s.walkReleases(func(info *ReleaseInfo) bool {
+		releases = append(releases, *info)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(releases) == 0 {
+		return nil, noMatchErrorf(ErrNoMatchingRelease, "no GitLab releases found for project ID %s", s.release.ProjectId)
+	}
+
+	// Sort releases by release date (most recent first)
+	sort.Slice(releases, func(i, j int) bool {
+		return releases[i].PublishedAt.After(releases[j].PublishedAt)
+	})
+	return releases, nil
+}
 
+// walkReleases hands the installable releases to visit page by page, most recently released first,
+// until visit returns false, the last page was read or MaxPages pages were fetched. Upcoming
+// releases are not published yet and historical ones were backfilled later; they are dropped while
+// streaming, so only one page is decoded at a time.
+func (s *gitlabAPISource) walkReleases(visit func(*ReleaseInfo) bool) error {
+	r := s.release
 	apiURL, err := r.GetApiUrl()
 	if err != nil {
 		return fmt.Errorf("error constructing GitLab API URL: %w", err)
 	}
+	perPage := r.GitLabConfig.perPage()
+
+	// The list only counts as unchanged if every page was answered from the metadata cache
+	notModified := true
+	defer func() { r.NotModified = notModified }()
+	for page := 1; r.GitLabConfig.MaxPages <= 0 || page <= r.GitLabConfig.MaxPages; page++ {
+		pageURL := fmt.Sprintf("%s?order_by=released_at&sort=desc&per_page=%d&page=%d", apiURL, perPage, page)
+		stream, err := s.open(pageURL)
+		if err != nil {
+			notModified = false
+			return err
+		}
+		notModified = notModified && r.NotModified
+
+		count, done := 0, false
+		err = decodeReleaseList("GitLab", stream, func(response *GitlabReleaseResponse) {
+			count++
+			if !done && !response.Upcoming && !response.Historical {
+				done = !visit(response.ToReleaseInfo())
+			}
+		})
+		stream.Close()
+		if err != nil {
+			notModified = false
+			return err
+		}
+		// A page with fewer releases than requested is the last one
+		if done || count < perPage {
+			return nil
+		}
+	}
+	return nil
+}
+
+// Release fetches the release tagged version from the GitLab API
+func (s *gitlabAPISource) Release(version string) (*ReleaseInfo, error) {
+	apiURL, err := s.release.GetApiUrl()
+	if err != nil {
+		return nil, fmt.Errorf("error constructing GitLab API URL: %w", err)
+	}
+
+	body, err := s.fetch(apiURL + "/" + url.PathEscape(version))
+	if err != nil {
+		return nil, fmt.Errorf("error fetching GitLab release %s: %w", version, err)
+	}
+
+	return decodeRelease[GitlabReleaseResponse]("GitLab", body)
+}
+
+// fetch performs an authenticated GitLab API request, answering from the metadata cache on 304
+func (s *gitlabAPISource) fetch(apiURL string) ([]byte, error) {
+	stream, err := s.open(apiURL)
+	if err != nil {
+		return nil, err
+	}
+	return readMetadata("GitLab", stream)
+}
 
-	// Get authentication headers
-	headers := r.getAuthHeaders()
+// open performs an authenticated GitLab API request, answering from the metadata cache on 304.
+// The returned body is limited to MaxMetadataSize and must be closed.
+func (s *gitlabAPISource) open(apiURL string) (io.ReadCloser, error) {
+	r := s.release
+
+	// Initialize HTTP client
+	r.initializeHTTPClient()
+
+	// Get authentication headers, plus conditional headers if a cached response is available
+	headers, err := r.getAuthHeaders()
+	if err != nil {
+		return nil, err
+	}
+	cached := r.MetadataCache.load(apiURL)
+	for key, value := range cached.conditionalHeaders() {
+		headers[key] = value
+	}
 
 	// Make request with retry logic
-	resp, err := r.httpClient.GetWithHeaders(apiURL, headers)
+	resp, err := r.httpClient.GetWithHeadersContext(r.Config.Context(), apiURL, headers)
 	if err != nil {
-		return fmt.Errorf("error making HTTP request to GitLab: %w", err)
+		return nil, fmt.Errorf("error making HTTP request to GitLab: %w", err)
+	}
+
+	// Only successful responses are streamed to the caller
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
 	}
-	defer resp.Body.Close()
 
 	// Handle different status codes
+	r.NotModified = false
 	switch resp.StatusCode {
 	case http.StatusOK:
 		// Success - continue processing
+	case http.StatusNotModified:
+		if cached == nil {
+			return nil, fmt.Errorf("unexpected status code from GitLab: %d", resp.StatusCode)
+		}
+		// Reuse the cached body when the release list has not changed
+		r.NotModified = true
+		return io.NopCloser(bytes.NewReader(cached.Body)), nil
 	case http.StatusNotFound:
-		return fmt.Errorf("GitLab project not found (ID: %s). Check project ID and permissions", r.ProjectId)
+		return nil, fmt.Errorf("GitLab project not found (ID: %s). Check project ID and permissions", r.ProjectId)
 	case http.StatusForbidden:
-		return fmt.Errorf("access denied to GitLab project (ID: %s). Check authentication token and permissions", r.ProjectId)
+		return nil, fmt.Errorf("access denied to GitLab project (ID: %s). Check authentication token and permissions", r.ProjectId)
 	case http.StatusUnauthorized:
-		return fmt.Errorf("authentication failed for GitLab project (ID: %s). Check token validity", r.ProjectId)
+		return nil, fmt.Errorf("authentication failed for GitLab project (ID: %s). Check token validity", r.ProjectId)
 	default:
-		return fmt.Errorf("unexpected status code from GitLab: %d", resp.StatusCode)
+		return nil, fmt.Errorf("unexpected status code from GitLab: %w", newHTTPStatusError(resp))
 	}
 
-	// Read response body
-	body, err := ReadResponseBody(resp)
+	body, err := newLimitedBody(resp, r.GitLabConfig.MaxMetadataSize)
 	if err != nil {
-		return fmt.Errorf("error reading response body from GitLab: %w", err)
+		return nil, fmt.Errorf("error reading response body from GitLab: %w", err)
 	}
-
-	var responses []GitlabReleaseResponse
-	if err := json.Unmarshal(body, &responses); err != nil {
-		return fmt.Errorf("error decoding response from GitLab: %w", err)
+	if r.MetadataCache == nil {
+		return body, nil
 	}
-
-	if len(responses) == 0 {
-		return fmt.Errorf("no GitLab releases found for project ID %s", r.ProjectId)
+	// Cached responses are read completely to store them
+	data, err := readMetadata("GitLab", body)
+	if err != nil {
+		return nil, err
 	}
+	if err := r.MetadataCache.store(apiURL, resp.Header, data); err != nil {
+		log.Printf("Warning: failed to cache GitLab release metadata: %v", err)
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
 
-	// Sort releases by release date (most recent first)
-	sort.Slice(responses, func(i, j int) bool {
-		return responses[i].ReleasedAt.After(responses[j].ReleasedAt)
-	})
-
-	// Get the latest release
-	latestRelease := responses[0]
-	r.Version = latestRelease.TagName
+// DownloadLatestRelease downloads the latest release to the staging path, which stays claimed for
+// this tool until the release is installed or ReleaseDownload is called
+func (r *GitLabRelease) DownloadLatestRelease() error {
+	r.downloadedVersion = ""
+	return r.download("")
+}
 
-	// Find platform-specific release link
-	releaseLink := latestRelease.GetReleaseLinkWithConfig(r.AssetMatchingConfig)
-	if releaseLink == "" {
-		return fmt.Errorf("no suitable asset found for current platform (%s/%s) in GitLab release %s",
-			runtime.GOOS, runtime.GOARCH, latestRelease.TagName)
+// DownloadRelease downloads the release tagged version, e.g. to pin or roll back an installation.
+// Like DownloadLatestRelease it claims the staging path until installing or ReleaseDownload.
+func (r *GitLabRelease) DownloadRelease(version string) error {
+	if version == "" {
+		return fmt.Errorf("version cannot be empty")
 	}
-
-	r.ReleaseLink = releaseLink
+	r.downloadedVersion = ""
+	if err := r.download(version); err != nil {
+		return err
+	}
+	r.downloadedVersion = version
 	return nil
 }
 
-func (r *GitLabRelease) DownloadLatestRelease() error {
+// download downloads the release tagged version, or the latest release if version is empty
+func (r *GitLabRelease) download(version string) error {
+	r.AlreadyInstalled = false
+
 	// Handle CDN downloads
 	if r.AssetMatchingConfig.Strategy == CDNStrategy || r.AssetMatchingConfig.Strategy == HybridStrategy {
-		return r.downloadFromCDN()
+		return r.downloadFromCDN(version)
 	}
+	return r.downloadReleaseAsset(version)
+}
 
-	err := r.GetLatestRelease()
+// downloadReleaseAsset downloads the matching asset of the GitLab release tagged version, or of the
+// latest release if version is empty
+func (r *GitLabRelease) downloadReleaseAsset(version string) error {
+	r.cdnFileName = ""
+	var err error
+	if version == "" {
+		err = r.GetLatestRelease()
+	} else {
+		err = r.GetRelease(version)
+	}
 	if err != nil {
-		return fmt.Errorf("error getting latest release from GitLab: %w", err)
+		return fmt.Errorf("error getting %s from GitLab: %w", describeRelease(version), err)
 	}
 	if r.Version == "" || r.ReleaseLink == "" {
 		return fmt.Errorf("could not find a valid release to download")
 	}
-	err = fileUtils.DownloadFile(r.ReleaseLink, r.Config.SourceArchivePath)
+	if r.AlreadyInstalled = skipInstalled(r.Config, r.Version, r.Force); r.AlreadyInstalled {
+		return nil
+	}
+	if err := fileUtils.CheckInstallWritable(r.Config); err != nil {
+		return err
+	}
+
+	// Prefer a delta patch against the installed version, falling back to the full asset
+	r.DeltaApplied = false
+	if r.AssetMatchingConfig.Delta.Enabled {
+		patchedPath, err := applyDeltaUpdate(r.Info, r.AssetMatchingConfig.Delta, r.Config, "", "")
+		if err == nil {
+			r.deltaBinaryPath = patchedPath
+			r.DeltaApplied = true
+			return nil
+		}
+		log.Printf("Delta update not used, downloading full asset: %v", err)
+	}
+
+	// Fail early instead of running out of space halfway through the download or extraction
+	if err := r.checkDiskSpace(); err != nil {
+		return err
+	}
+
+	r.downloadedFrom = r.ReleaseLink
+
+	// Streaming extraction downloads the archive during installation instead
+	if r.streamExtractionEnabled() {
+		return nil
+	}
+	digest := r.assetDigest()
+	destination := r.getTempSourceArchivePath()
+	err = stageDownload(destination, r.stagingOwner(), func() error {
+		return downloadVerified(destination, digest, func(checksum string) error {
+			return r.downloadAsset(r.ReleaseLink, destination, checksum)
+		})
+	})
 	if err != nil {
 		return fmt.Errorf(
-			"error downloading latest release from GitLab: %w",
-			err)
+			"error downloading %s from GitLab: %w",
+			describeRelease(version), err)
 	}
 	return nil
 }
 
-// downloadFromCDN downloads binary from CDN instead of GitLab releases
-func (r *GitLabRelease) downloadFromCDN() error {
-	if r.Version == "" {
+// downloadFromCDN downloads binary from CDN instead of GitLab releases. An empty version downloads
+// the latest release.
+func (r *GitLabRelease) downloadFromCDN(version string) error {
+	if version != "" {
+		r.Version = version
+	} else if r.Version == "" {
 		// Try to discover version from CDN first, fall back to GitLab if needed
-		cdnDownloader := NewCDNDownloader(r.AssetMatchingConfig.CDNBaseURL, r.AssetMatchingConfig.CDNPattern)
+		cdnDownloader := newConfiguredCDNDownloader(r.AssetMatchingConfig, r.Config)
 
 		version, err := cdnDownloader.TryDiscoverLatestVersion()
 		if err == nil {
@@ -227,23 +554,33 @@ func (r *GitLabRelease) downloadFromCDN() error {
 		}
 	}
 
-	// Create CDN downloader with custom architecture mapping if configured
-	var cdnDownloader *CDNDownloader
-	if r.AssetMatchingConfig.CDNArchMapping != nil {
-		cdnDownloader = NewCDNDownloaderWithArchMapping(
-			r.AssetMatchingConfig.CDNBaseURL,
-			r.AssetMatchingConfig.CDNPattern,
-			r.AssetMatchingConfig.CDNArchMapping,
-		)
-	} else {
-		cdnDownloader = NewCDNDownloader(r.AssetMatchingConfig.CDNBaseURL, r.AssetMatchingConfig.CDNPattern)
+	if r.AlreadyInstalled = skipInstalled(r.Config, r.Version, r.Force); r.AlreadyInstalled {
+		return nil
+	}
+	if err := fileUtils.CheckInstallWritable(r.Config); err != nil {
+		return err
 	}
 
+	cdnDownloader := newConfiguredCDNDownloader(r.AssetMatchingConfig, r.Config)
+
 	versionFormat := r.AssetMatchingConfig.CDNVersionFormat
 	if versionFormat == "" {
 		versionFormat = "as-is" // Default to as-is if not specified
 	}
-	return cdnDownloader.DownloadWithVersionFormat(r.Version, r.Config.SourceArchivePath, versionFormat)
+
+	// Check the CDN before downloading; hybrid configurations fall back to release assets
+	if err := cdnDownloader.ensureOnCDN(r.Version, versionFormat); err != nil {
+		if r.AssetMatchingConfig.Strategy == HybridStrategy && errors.Is(err, ErrVersionNotOnCDN) {
+			fmt.Printf("%v, falling back to GitLab release assets\n", err)
+			return r.downloadReleaseAsset(version)
+		}
+		return err
+	}
+	r.downloadedFrom = cdnDownloader.platformURL(r.Version, versionFormat)
+	r.cdnFileName = cdnDownloader.FileName(r.Version, versionFormat)
+	return stageDownload(r.getTempSourceArchivePath(), r.stagingOwner(), func() error {
+		return cdnDownloader.DownloadWithVersionFormat(r.Version, r.getTempSourceArchivePath(), versionFormat)
+	})
 }
 
 // DownloadCDNVersion downloads a specific version from CDN without GitLab API calls
@@ -259,40 +596,115 @@ func (r *GitLabRelease) DownloadCDNVersion(version string) error {
 
 	// Set the version directly to avoid GitLab API calls
 	r.Version = version
-
-	// Create CDN downloader with custom architecture mapping if configured
-	var cdnDownloader *CDNDownloader
-	if r.AssetMatchingConfig.CDNArchMapping != nil {
-		cdnDownloader = NewCDNDownloaderWithArchMapping(
-			r.AssetMatchingConfig.CDNBaseURL,
-			r.AssetMatchingConfig.CDNPattern,
-			r.AssetMatchingConfig.CDNArchMapping,
-		)
-	} else {
-		cdnDownloader = NewCDNDownloader(r.AssetMatchingConfig.CDNBaseURL, r.AssetMatchingConfig.CDNPattern)
+	if r.AlreadyInstalled = skipInstalled(r.Config, version, r.Force); r.AlreadyInstalled {
+		return nil
+	}
+	if err := fileUtils.CheckInstallWritable(r.Config); err != nil {
+		return err
 	}
 
+	cdnDownloader := newConfiguredCDNDownloader(r.AssetMatchingConfig, r.Config)
+
 	versionFormat := r.AssetMatchingConfig.CDNVersionFormat
 	if versionFormat == "" {
 		versionFormat = "as-is" // Default to as-is if not specified
 	}
-	return cdnDownloader.DownloadWithVersionFormat(r.Version, r.Config.SourceArchivePath, versionFormat)
+	if err := cdnDownloader.ensureOnCDN(version, versionFormat); err != nil {
+		return err
+	}
+	r.downloadedFrom = cdnDownloader.platformURL(version, versionFormat)
+	r.cdnFileName = cdnDownloader.FileName(version, versionFormat)
+	return stageDownload(r.getTempSourceArchivePath(), r.stagingOwner(), func() error {
+		return cdnDownloader.DownloadWithVersionFormat(r.Version, r.getTempSourceArchivePath(), versionFormat)
+	})
 }
 
 func (r *GitLabRelease) InstallLatestRelease() error {
+	defer r.releaseStagedDownload()
+	r.downloadCleanup = DownloadCleanupNone
+	if r.AlreadyInstalled {
+		fmt.Printf("%s %s is already installed\n", r.Config.BinaryName, r.Version)
+		return nil
+	}
+	if r.deltaBinaryPath != "" {
+		patchedPath := r.deltaBinaryPath
+		r.deltaBinaryPath = ""
+		if err := installPatchedBinary(r.Config, patchedPath, r.Version); err != nil {
+			return err
+		}
+		recordProvenance(r.Config, r.Version, r.provenance(), "")
+		r.downloadCleanup = cleanupDownload(r.Config, patchedPath)
+		return nil
+	}
+	if r.streamExtractionEnabled() {
+		token, err := r.assetToken(r.ReleaseLink)
+		if err != nil {
+			return err
+		}
+		if err := fileUtils.StreamInstallArchivedBinary(r.Config, r.Version, r.ReleaseLink, token, r.AssetName,
+			r.AssetMatchingConfig.extractionConfig()); err != nil {
+			return err
+		}
+		recordProvenance(r.Config, r.Version, r.provenance(), "")
+		return nil
+	}
+
 	// Use enhanced installation with extraction config if available
-	if r.AssetMatchingConfig.ExtractionConfig != nil && !r.Config.IsDirectBinary {
-		// Convert ExtractionConfig to fileUtils.ExtractionConfig
-		fileUtilsConfig := &fileUtils.ExtractionConfig{
-			StripComponents: r.AssetMatchingConfig.ExtractionConfig.StripComponents,
-			BinaryPath:      r.AssetMatchingConfig.ExtractionConfig.BinaryPath,
+	var err error
+	if r.AssetMatchingConfig.ExtractionConfig != nil && !r.Config.UsesDirectInstall() {
+		err = fileUtils.InstallArchivedBinaryWithConfig(r.stagedConfig(), r.Version, r.AssetMatchingConfig.extractionConfig())
+	} else {
+		err = fileUtils.InstallBinary(r.stagedConfig(), r.Version)
+	}
+	if err != nil {
+		return err
+	}
+	recordProvenance(r.Config, r.Version, r.provenance(), r.getTempSourceArchivePath())
+	r.downloadCleanup = cleanupDownload(r.Config, r.getTempSourceArchivePath())
+	return nil
+}
+
+// InstallRelease installs the release tagged version, downloading it unless DownloadRelease already did
+func (r *GitLabRelease) InstallRelease(version string) error {
+	if version == "" || r.downloadedVersion != version {
+		if err := r.DownloadRelease(version); err != nil {
+			return err
 		}
-		return fileUtils.InstallArchivedBinaryWithConfig(r.Config, r.Version, fileUtilsConfig)
 	}
-	return fileUtils.InstallBinary(r.Config, r.Version)
+	r.downloadedVersion = ""
+	return r.InstallLatestRelease()
 }
 
+// checkDiskSpace compares the selected asset's size with the free space in the staging and install directories
+func (r *GitLabRelease) checkDiskSpace() error {
+	if r.Info == nil {
+		return nil
+	}
+	asset, ok := r.Info.FindAsset(r.AssetName)
+	if !ok {
+		return nil
+	}
+	config := r.stagedConfig()
+	config.StreamExtraction = r.streamExtractionEnabled()
+	return fileUtils.CheckInstallSpace(config, asset.Size)
+}
+
+// streamExtractionEnabled reports whether the selected archive is extracted while downloading.
+// Assets with a verifiable digest are downloaded first instead, so the digest is checked before installing.
+func (r *GitLabRelease) streamExtractionEnabled() bool {
+	return r.Config.StreamExtraction && !r.Config.UsesDirectInstall() && fileUtils.CanStreamExtract(r.AssetName) &&
+		!verifiableDigest(r.assetDigest())
+}
 
+// InstallFromFile installs a pre-staged archive or binary without contacting GitLab
+func (r *GitLabRelease) InstallFromFile(path, version string) error {
+	r.Version = version
+	if err := fileUtils.InstallFromFile(r.Config, path, version, r.AssetMatchingConfig.extractionConfig()); err != nil {
+		return err
+	}
+	recordProvenance(r.Config, version, localProvenance(path), path)
+	return nil
+}
 
 // NewGitlabRelease creates a new GitLab release instance with default configuration
 func NewGitlabRelease(projectId string, fileConfig fileUtils.FileConfig) *GitLabRelease {
@@ -306,33 +718,11 @@ func NewGitlabRelease(projectId string, fileConfig fileUtils.FileConfig) *GitLab
 		config.BaseURL = baseURL
 	}
 
-	// Configure asset matching
-	assetConfig := DefaultAssetMatchingConfig()
-	assetConfig.ProjectName = fileConfig.ProjectName
-	assetConfig.IsDirectBinary = fileConfig.IsDirectBinary
-
-	// Configure asset matching strategy based on FileConfig
-	switch fileConfig.AssetMatchingStrategy {
-	case "standard":
-		assetConfig.Strategy = StandardStrategy
-	case "flexible":
-		assetConfig.Strategy = FlexibleStrategy
-	case "custom":
-		assetConfig.Strategy = CustomStrategy
-		assetConfig.CustomPatterns = fileConfig.CustomAssetPatterns
-	case "cdn":
-		assetConfig.Strategy = CDNStrategy
-	case "hybrid":
-		assetConfig.Strategy = HybridStrategy
-	default:
-		assetConfig.Strategy = FlexibleStrategy
-	}
-
 	return &GitLabRelease{
 		ProjectId:           projectId,
 		Config:              fileConfig,
 		GitLabConfig:        config,
-		AssetMatchingConfig: assetConfig,
+		AssetMatchingConfig: assetConfigFromFileConfig(fileConfig),
 	}
 }
 
@@ -345,29 +735,11 @@ func NewGitlabReleaseWithToken(projectId string, token string, fileConfig fileUt
 
 // NewGitlabReleaseWithConfig creates a new GitLab release instance with full configuration
 func NewGitlabReleaseWithConfig(projectId string, fileConfig fileUtils.FileConfig, gitlabConfig GitLabConfig) *GitLabRelease {
-	// Configure asset matching
-	assetConfig := DefaultAssetMatchingConfig()
-	assetConfig.ProjectName = fileConfig.ProjectName
-	assetConfig.IsDirectBinary = fileConfig.IsDirectBinary
-
-	// Configure asset matching strategy based on FileConfig
-	switch fileConfig.AssetMatchingStrategy {
-	case "standard":
-		assetConfig.Strategy = StandardStrategy
-	case "flexible":
-		assetConfig.Strategy = FlexibleStrategy
-	case "custom":
-		assetConfig.Strategy = CustomStrategy
-		assetConfig.CustomPatterns = fileConfig.CustomAssetPatterns
-	default:
-		assetConfig.Strategy = FlexibleStrategy
-	}
-
 	return &GitLabRelease{
 		ProjectId:           projectId,
 		Config:              fileConfig,
 		GitLabConfig:        gitlabConfig,
-		AssetMatchingConfig: assetConfig,
+		AssetMatchingConfig: assetConfigFromFileConfig(fileConfig),
 	}
 }
 
@@ -380,12 +752,46 @@ func (r *GitLabRelease) GetInstalledBinaryPath() (string, error) {
 	return fileUtils.GetInstalledBinaryPath(r.Config, r.Version)
 }
 
+// ReleaseNotes returns the notes of the release found by GetLatestRelease, so updaters can show
+// what's new before installing
+func (r *GitLabRelease) ReleaseNotes() (string, error) {
+	if r.Info == nil {
+		return "", fmt.Errorf("no release information available - call GetLatestRelease() first")
+	}
+	return r.Info.ReleaseNotes(), nil
+}
+
+// Assets returns every asset of the release found by GetLatestRelease or GetRelease, including
+// assets the matcher did not select, so UIs can let users pick one manually. It returns nil
+// before a release was fetched.
+func (r *GitLabRelease) Assets() []AssetInfo {
+	if r.Info == nil {
+		return nil
+	}
+	return slices.Clone(r.Info.Assets)
+}
+
+// LatestVersion returns the version resolved by GetLatestRelease
+func (r *GitLabRelease) LatestVersion() string {
+	return r.Version
+}
+
+// InstalledVersion returns the version the local symlink currently points to
+func (r *GitLabRelease) InstalledVersion() (string, error) {
+	return fileUtils.CurrentInstalledVersion(r.Config)
+}
+
 // GetInstallationInfo returns comprehensive information about the installed binary
 func (r *GitLabRelease) GetInstallationInfo() (*fileUtils.InstallationInfo, error) {
 	if r.Version == "" {
 		return nil, fmt.Errorf("no version information available - call GetLatestRelease() first")
 	}
-	return fileUtils.GetInstallationInfo(r.Config, r.Version)
+	info, err := fileUtils.GetInstallationInfo(r.Config, r.Version)
+	if err != nil {
+		return nil, err
+	}
+	info.Warnings = append(info.Warnings, r.Warnings...)
+	return info, nil
 }
 
 // SetCustomHeaders allows setting custom headers for GitLab API requests
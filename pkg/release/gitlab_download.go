@@ -0,0 +1,185 @@
+package release
+
+import (
+	"errors"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/filesystem"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// assetToken returns the token to download link with: the GitLab token for links on the GitLab
+// instance, and none for links to other hosts
+func (r *GitLabRelease) assetToken(link string) (string, error) {
+	baseURL := r.GitLabConfig.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultGitLabAPIURL
+	}
+	if !sameHost(link, baseURL) {
+		return "", nil
+	}
+	return r.authToken()
+}
+
+// assetHTTPClient returns the retrying client for asset downloads. It uses the GitLab retry
+// settings with the CDN timeout, because the timeout covers downloading the whole asset.
+func (r *GitLabRelease) assetHTTPClient() *RetryableHTTPClient {
+	if r.assetClient == nil {
+		r.initializeHTTPClient()
+		config := r.GitLabConfig.HTTPConfig
+		config.Timeout = DefaultCDNHTTPClientConfig().Timeout
+		r.assetClient = NewRetryableHTTPClient(config)
+	}
+	return r.assetClient
+}
+
+// downloadAsset downloads a release asset to destination. Assets on the GitLab instance, such as
+// direct asset URLs of private projects, are requested with the token through the retrying client,
+// which drops the token when GitLab redirects to object storage. Other links are downloaded
+// without credentials. The checksum, if known, keys the download cache and the download is
+// verified against it.
+func (r *GitLabRelease) downloadAsset(link, destination, checksum string) error {
+	token, err := r.assetToken(link)
+	if err != nil {
+		return err
+	}
+	if token == "" {
+		return fileUtils.DownloadFileWithConfig(r.Config, link, destination, "", checksum)
+	}
+
+	var cache *fileUtils.DownloadCache
+	if r.Config.Cache.Enabled {
+		if cache, err = fileUtils.NewDownloadCache(r.Config.Cache); err != nil {
+			return err
+		}
+		if hit, err := cache.Get(link, checksum, destination); err != nil {
+			return err
+		} else if hit {
+			fmt.Printf("Using cached download for %s\n", link)
+			return nil
+		}
+	}
+
+	if err := r.downloadAuthenticated(link, destination, token); err != nil {
+		return err
+	}
+	if cache == nil {
+		if checksum == "" {
+			return nil
+		}
+		if err := fileUtils.VerifyFileSHA256(destination, checksum); err != nil {
+			fsys().Remove(destination)
+			return err
+		}
+		return nil
+	}
+	// Put verifies the download against the checksum before caching it
+	if err := cache.Put(link, checksum, destination); err != nil {
+		if errors.Is(err, fileUtils.ErrChecksumMismatch) {
+			fsys().Remove(destination)
+			return err
+		}
+		fmt.Printf("Warning: failed to cache download: %v\n", err)
+	}
+	return nil
+}
+
+// downloadAuthenticated downloads link with the token. Failed requests are retried by the client;
+// a download interrupted mid-transfer resumes with a Range request, or starts over when the server
+// does not support ranges.
+func (r *GitLabRelease) downloadAuthenticated(link, destination, token string) (err error) {
+	start := time.Now()
+	var written int64
+	defer func() { fileUtils.ObserveDownload("gitlab", start, written, err) }()
+
+	if err := fsys().MkdirAll(filepath.Dir(destination), 0755); err != nil {
+		return fmt.Errorf("failed to create download directory: %w", err)
+	}
+	out, err := fsys().Create(destination)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer out.Close()
+
+	client := r.assetHTTPClient()
+	for attempt := 0; ; attempt++ {
+		req, err := r.newAssetRequest(link, token, written)
+		if err != nil {
+			return err
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to download file: %w", err)
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusPartialContent && written > 0 &&
+			strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", written)):
+			// Continue where the interrupted attempt stopped
+		case resp.StatusCode == http.StatusOK:
+			if written > 0 {
+				if err := restartDownload(out); err != nil {
+					resp.Body.Close()
+					return err
+				}
+				written = 0
+			}
+			if err := fileUtils.CheckDiskSpace(filepath.Dir(destination), resp.ContentLength); err != nil {
+				resp.Body.Close()
+				return err
+			}
+		default:
+			resp.Body.Close()
+			return fmt.Errorf("unexpected status code: %w", newHTTPStatusError(resp))
+		}
+
+		n, copyErr := io.Copy(out, resp.Body)
+		resp.Body.Close()
+		written += n
+		if copyErr == nil {
+			break
+		}
+		if attempt >= client.config.MaxRetries {
+			return fmt.Errorf("failed to write file: %w", copyErr)
+		}
+		if err := client.waitBeforeRetry(req, attempt, fmt.Sprintf("interrupted after %d bytes: %v", written, copyErr)); err != nil {
+			return fmt.Errorf("failed to write file: %v: %w", copyErr, err)
+		}
+	}
+
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	return nil
+}
+
+// newAssetRequest builds an authenticated asset request, resuming at offset when it is positive
+func (r *GitLabRelease) newAssetRequest(link, token string, offset int64) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(r.Config.Context(), "GET", link, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, value := range r.GitLabConfig.CustomHeaders {
+		req.Header.Set(key, value)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	return req, nil
+}
+
+// restartDownload discards a partial download whose server ignored the Range request
+func restartDownload(out filesystem.File) error {
+	if err := out.Truncate(0); err != nil {
+		return fmt.Errorf("failed to restart download: %w", err)
+	}
+	if _, err := out.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to restart download: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package release
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGitLabRelease_DownloadAssetFollowsAuthRedirect(t *testing.T) {
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("Expected no credentials at the storage host, got %q", auth)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte("private binary"))
+	}))
+	defer storage.Close()
+	gitlab := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			http.NotFound(w, r)
+			return
+		}
+		http.Redirect(w, r, storage.URL+"/signed/myapp?signature=abc", http.StatusFound)
+	}))
+	defer gitlab.Close()
+
+	release := NewGitlabReleaseWithToken("123", "secret", fileUtils.FileConfig{})
+	release.GitLabConfig.BaseURL = gitlab.URL + "/api/v4"
+	destination := filepath.Join(t.TempDir(), "myapp")
+
+	if err := release.downloadAsset(gitlab.URL+"/group/project/-/releases/v1.0.0/downloads/myapp", destination, ""); err != nil {
+		t.Fatalf("downloadAsset failed: %v", err)
+	}
+	if data, _ := os.ReadFile(destination); string(data) != "private binary" {
+		t.Errorf("Expected the redirected asset, got %q", data)
+	}
+
+	// Links to other hosts are downloaded without the token
+	if err := release.downloadAsset(storage.URL+"/public/myapp", destination, ""); err != nil {
+		t.Fatalf("downloadAsset of an external link failed: %v", err)
+	}
+
+	// Without the token the private asset is not found
+	anonymous := NewGitlabRelease("123", fileUtils.FileConfig{})
+	anonymous.GitLabConfig.BaseURL = gitlab.URL + "/api/v4"
+	if err := anonymous.downloadAsset(gitlab.URL+"/group/project/-/releases/v1.0.0/downloads/myapp", destination, ""); err == nil {
+		t.Error("Expected the anonymous download to fail")
+	}
+}
+
+func TestGitLabRelease_DownloadAssetResumes(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 1000)
+	var requests atomic.Int32
+	var resumedAt atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			// Announce the full length but drop the connection halfway
+			w.Header().Set("Content-Length", "10000")
+			w.Write(content[:4000])
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		resumedAt.Store(r.Header.Get("Range"))
+		http.ServeContent(w, r, "myapp", time.Time{}, bytes.NewReader(content))
+	}))
+	defer server.Close()
+
+	release := NewGitlabReleaseWithToken("123", "secret", fileUtils.FileConfig{})
+	release.GitLabConfig.BaseURL = server.URL + "/api/v4"
+	release.GitLabConfig.HTTPConfig.InitialDelay = time.Millisecond
+	destination := filepath.Join(t.TempDir(), "myapp")
+
+	if err := release.downloadAsset(server.URL+"/group/project/-/releases/v1.0.0/downloads/myapp", destination, ""); err != nil {
+		t.Fatalf("downloadAsset failed: %v", err)
+	}
+	if data, _ := os.ReadFile(destination); !bytes.Equal(data, content) {
+		t.Errorf("Expected the complete asset, got %d bytes", len(data))
+	}
+	if got, _ := resumedAt.Load().(string); !strings.HasPrefix(got, "bytes=4000-") {
+		t.Errorf("Expected the download to resume at byte 4000, got Range %q", got)
+	}
+}
+
+func TestGitLabRelease_DownloadAssetCacheKeyedByChecksum(t *testing.T) {
+	content := []byte("myapp v2")
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write(content)
+	}))
+	defer server.Close()
+
+	tempDir := t.TempDir()
+	release := NewGitlabReleaseWithToken("123", "secret", fileUtils.FileConfig{
+		Cache: fileUtils.CacheConfig{Enabled: true, Directory: filepath.Join(tempDir, "cache")},
+	})
+	release.GitLabConfig.BaseURL = server.URL + "/api/v4"
+	link := server.URL + "/group/project/-/releases/v1.0.0/downloads/myapp"
+
+	// An entry cached without a checksum, e.g. of a replaced asset, is not reused
+	stale := filepath.Join(tempDir, "stale")
+	os.WriteFile(stale, []byte("myapp v1"), 0644)
+	cache, _ := fileUtils.NewDownloadCache(release.Config.Cache)
+	if err := cache.Put(link, "", stale); err != nil {
+		t.Fatal(err)
+	}
+
+	sum := sha256.Sum256(content)
+	checksum := hex.EncodeToString(sum[:])
+	for i := 0; i < 2; i++ {
+		destination := filepath.Join(tempDir, fmt.Sprintf("myapp-%d", i))
+		if err := release.downloadAsset(link, destination, checksum); err != nil {
+			t.Fatalf("downloadAsset failed: %v", err)
+		}
+		if data, _ := os.ReadFile(destination); !bytes.Equal(data, content) {
+			t.Errorf("Expected %q, got %q", content, data)
+		}
+	}
+	if requests.Load() != 1 {
+		t.Errorf("Expected one download followed by a cache hit, got %d requests", requests.Load())
+	}
+}
@@ -0,0 +1,117 @@
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+)
+
+// GitLabReleaseLink is an asset link as returned by the GitLab release links endpoint
+type GitLabReleaseLink struct {
+	ID             int    `json:"id"`
+	Name           string `json:"name"`
+	URL            string `json:"url"`
+	DirectAssetURL string `json:"direct_asset_url"`
+	LinkType       string `json:"link_type"` // "package", "image", "runbook" or "other"
+}
+
+// ReleaseLinks fetches the asset links of the release tagged version from the release links
+// endpoint, which reports link types even where the release response omits them. GitLab does not
+// report link sizes; see GitLabConfig.LinkMetadata for how the selected asset's size is resolved.
+func (r *GitLabRelease) ReleaseLinks(version string) ([]GitLabReleaseLink, error) {
+	apiURL, err := r.GetApiUrl()
+	if err != nil {
+		return nil, fmt.Errorf("error constructing GitLab API URL: %w", err)
+	}
+	source := &gitlabAPISource{release: r}
+	body, err := source.fetch(apiURL + "/" + url.PathEscape(version) + "/assets/links")
+	if err != nil {
+		return nil, fmt.Errorf("error fetching links of GitLab release %s: %w", version, err)
+	}
+	var links []GitLabReleaseLink
+	if err := json.Unmarshal(body, &links); err != nil {
+		return nil, fmt.Errorf("error decoding links of GitLab release %s: %v", version, err)
+	}
+	return links, nil
+}
+
+// resolveLinkTypes completes the link types missing from the release with the release links
+// endpoint, so LinkTypes filters apply. Releases from a custom Source are left as they are.
+func (r *GitLabRelease) resolveLinkTypes(info *ReleaseInfo) {
+	if r.Source != nil {
+		return
+	}
+	missing := false
+	for _, asset := range info.Assets {
+		missing = missing || asset.LinkType == ""
+	}
+	if !missing {
+		return
+	}
+	links, err := r.ReleaseLinks(info.TagName())
+	if err != nil {
+		log.Printf("Warning: GitLab link types unavailable: %v", err)
+		return
+	}
+	linkTypes := make(map[string]string, len(links))
+	for _, link := range links {
+		linkTypes[link.Name] = link.LinkType
+	}
+	for i, asset := range info.Assets {
+		if asset.LinkType == "" {
+			info.Assets[i].LinkType = linkTypes[asset.Name]
+		}
+	}
+}
+
+// resolveAssetSize records the size of the selected asset, so disk space is checked before the
+// download starts. The size is the Content-Length of a HEAD request on the link, because GitLab
+// does not store link sizes. Links without a usable answer keep the size unknown.
+func (r *GitLabRelease) resolveAssetSize(info *ReleaseInfo, asset *AssetInfo) {
+	if asset.Size > 0 {
+		return
+	}
+	size, err := r.assetSize(asset.URL)
+	if err != nil {
+		log.Printf("Warning: size of GitLab asset %s unavailable: %v", asset.Name, err)
+		return
+	}
+	asset.Size = size
+	for i := range info.Assets {
+		if info.Assets[i].Name == asset.Name {
+			info.Assets[i].Size = size
+		}
+	}
+}
+
+// assetSize returns the Content-Length of a HEAD request on link, authenticated like the download
+func (r *GitLabRelease) assetSize(link string) (int64, error) {
+	token, err := r.assetToken(link)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(r.Config.Context(), "HEAD", link, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	if token != "" {
+		for key, value := range r.GitLabConfig.CustomHeaders {
+			req.Header.Set(key, value)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := r.assetHTTPClient().Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("unexpected status code: %w", newHTTPStatusError(resp))
+	}
+	if resp.ContentLength <= 0 {
+		return 0, fmt.Errorf("no Content-Length reported")
+	}
+	return resp.ContentLength, nil
+}
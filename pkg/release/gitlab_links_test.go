@@ -0,0 +1,68 @@
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+)
+
+func TestGitLabRelease_LinkMetadata(t *testing.T) {
+	binary := fmt.Sprintf("myapp_%s_%s", runtime.GOOS, runtime.GOARCH)
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		link := func(id int, name, linkType string) map[string]any {
+			return map[string]any{"id": id, "name": name, "direct_asset_url": server.URL + "/downloads/" + name, "link_type": linkType}
+		}
+		switch r.URL.Path {
+		case "/api/v4/projects/123/releases/v1.0.0":
+			// Release responses of older GitLab versions omit the link type
+			json.NewEncoder(w).Encode(map[string]any{
+				"tag_name": "v1.0.0",
+				"assets": map[string]any{"links": []map[string]any{
+					link(1, binary+".txt", ""),
+					link(2, binary, ""),
+				}},
+			})
+		case "/api/v4/projects/123/releases/v1.0.0/assets/links":
+			json.NewEncoder(w).Encode([]map[string]any{link(1, binary+".txt", "other"), link(2, binary, "package")})
+		case "/downloads/" + binary:
+			if r.Method != http.MethodHead || r.Header.Get("Authorization") != "Bearer secret" {
+				t.Errorf("Expected an authenticated HEAD request, got %s with %q", r.Method, r.Header.Get("Authorization"))
+			}
+			w.Header().Set("Content-Length", "4096")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	release := NewGitlabReleaseWithToken("123", "secret", fileUtils.FileConfig{})
+	release.GitLabConfig.BaseURL = server.URL + "/api/v4"
+	release.GitLabConfig.LinkMetadata = true
+	release.AssetMatchingConfig.LinkTypes = []string{"package"}
+
+	if err := release.GetRelease("v1.0.0"); err != nil {
+		t.Fatalf("GetRelease failed: %v", err)
+	}
+	if release.AssetName != binary {
+		t.Errorf("Expected the package link %s, got %s", binary, release.AssetName)
+	}
+	asset, _ := release.Info.FindAsset(binary)
+	if asset.LinkType != "package" || asset.Size != 4096 {
+		t.Errorf("Expected link type package and size 4096, got %q and %d", asset.LinkType, asset.Size)
+	}
+
+	// Without LinkMetadata no further requests are made
+	plain := NewGitlabReleaseWithToken("123", "secret", fileUtils.FileConfig{})
+	plain.GitLabConfig.BaseURL = server.URL + "/api/v4"
+	if err := plain.GetRelease("v1.0.0"); err != nil {
+		t.Fatalf("GetRelease failed: %v", err)
+	}
+	if asset, _ := plain.Info.FindAsset(binary); asset.LinkType != "" || asset.Size != 0 {
+		t.Errorf("Expected no link metadata, got %q and %d", asset.LinkType, asset.Size)
+	}
+}
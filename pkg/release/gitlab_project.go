@@ -0,0 +1,129 @@
+package release
+
+import (
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// gitlabProjectIDs caches resolved project IDs by API URL and project path for the lifetime of the process
+var (
+	gitlabProjectIDsMu sync.Mutex
+	gitlabProjectIDs   = make(map[string]string)
+)
+
+// gitlabProjectResponse is the part of the GitLab project API response needed to resolve IDs
+type gitlabProjectResponse struct {
+	ID int `json:"id"`
+}
+
+// ResolveGitLabProjectID looks up the numeric ID of a project path such as "group/subgroup/project"
+// or a project URL like "https://gitlab.com/group/subgroup/project" using the GitLab API. Numeric IDs
+// are returned unchanged and resolved IDs are cached, so repeated lookups don't hit the API.
+func ResolveGitLabProjectID(projectPath string, config GitLabConfig) (string, error) {
+	projectPath = normalizeGitLabProjectPath(projectPath)
+	if projectPath == "" {
+		return "", fmt.Errorf("GitLab project path cannot be empty")
+	}
+	if id, err := strconv.Atoi(projectPath); err == nil && id > 0 {
+		return projectPath, nil
+	}
+
+	if config.BaseURL == "" {
+		config.BaseURL = DefaultGitLabAPIURL
+	}
+	if config.HTTPConfig.MaxRetries == 0 {
+		config.HTTPConfig = DefaultHTTPClientConfig()
+	}
+	baseURL := strings.TrimSuffix(config.BaseURL, "/")
+	cacheKey := baseURL + "|" + projectPath
+
+	gitlabProjectIDsMu.Lock()
+	id, ok := gitlabProjectIDs[cacheKey]
+	gitlabProjectIDsMu.Unlock()
+	if ok {
+		return id, nil
+	}
+
+	// Reuse the release's authentication and custom headers for the lookup
+	r := &GitLabRelease{GitLabConfig: config}
+	r.initializeHTTPClient()
+	headers, err := r.getAuthHeaders()
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := r.httpClient.GetWithHeaders(baseURL+"/projects/"+url.PathEscape(projectPath), headers)
+	if err != nil {
+		return "", fmt.Errorf("error making HTTP request to GitLab: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return "", fmt.Errorf("GitLab project not found (path: %s). Check project path and permissions", projectPath)
+	case http.StatusForbidden:
+		return "", fmt.Errorf("access denied to GitLab project (path: %s). Check authentication token and permissions", projectPath)
+	case http.StatusUnauthorized:
+		return "", fmt.Errorf("authentication failed for GitLab project (path: %s). Check token validity", projectPath)
+	default:
+		return "", fmt.Errorf("unexpected status code from GitLab: %w", newHTTPStatusError(resp))
+	}
+
+	body, err := ReadResponseBody(resp)
+	if err != nil {
+		return "", fmt.Errorf("error reading response body from GitLab: %w", err)
+	}
+	var project gitlabProjectResponse
+	if err := decodeResponse("GitLab", body, &project); err != nil {
+		return "", err
+	}
+	if project.ID <= 0 {
+		return "", fmt.Errorf("GitLab returned no ID for project %s", projectPath)
+	}
+
+	id = strconv.Itoa(project.ID)
+	gitlabProjectIDsMu.Lock()
+	gitlabProjectIDs[cacheKey] = id
+	gitlabProjectIDsMu.Unlock()
+	return id, nil
+}
+
+// NewGitlabReleaseFromPath creates a GitLab release for a project path such as "group/subgroup/project",
+// resolving it to the numeric project ID with ResolveGitLabProjectID
+func NewGitlabReleaseFromPath(projectPath string, fileConfig fileUtils.FileConfig) (*GitLabRelease, error) {
+	return newGitlabReleaseFromPath(projectPath, NewGitlabRelease("", fileConfig))
+}
+
+// NewGitlabReleaseFromPathWithConfig creates a GitLab release for a project path with full configuration,
+// resolving the path against the configured GitLab instance and token
+func NewGitlabReleaseFromPathWithConfig(projectPath string, fileConfig fileUtils.FileConfig, gitlabConfig GitLabConfig) (*GitLabRelease, error) {
+	return newGitlabReleaseFromPath(projectPath, NewGitlabReleaseWithConfig("", fileConfig, gitlabConfig))
+}
+
+// newGitlabReleaseFromPath resolves projectPath with the release's GitLab configuration and sets its project ID
+func newGitlabReleaseFromPath(projectPath string, release *GitLabRelease) (*GitLabRelease, error) {
+	projectId, err := ResolveGitLabProjectID(projectPath, release.GitLabConfig)
+	if err != nil {
+		return nil, err
+	}
+	release.ProjectId = projectId
+	return release, nil
+}
+
+// normalizeGitLabProjectPath strips surrounding slashes, a URL's scheme and host, and a trailing
+// ".git" or "/-/..." page suffix from a project path
+func normalizeGitLabProjectPath(projectPath string) string {
+	projectPath = strings.TrimSpace(projectPath)
+	if parsed, err := url.Parse(projectPath); err == nil && parsed.Host != "" {
+		projectPath = parsed.Path
+	}
+	projectPath, _, _ = strings.Cut(projectPath, "/-/")
+	projectPath = strings.Trim(projectPath, "/")
+	return strings.TrimSuffix(projectPath, ".git")
+}
@@ -0,0 +1,92 @@
+package release
+
+import (
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestResolveGitLabProjectID(t *testing.T) {
+	lookups := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/projects/group%2Fsubgroup%2Fproject":
+			lookups++
+			if r.Header.Get("Authorization") != "Bearer test-token" {
+				t.Errorf("Expected the token on the project lookup, got %q", r.Header.Get("Authorization"))
+			}
+			w.Write([]byte(`{"id": 4242, "path_with_namespace": "group/subgroup/project"}`))
+		case "/projects/4242/releases":
+			w.Write([]byte(`[{
+				"tag_name": "v1.0.0",
+				"released_at": "2024-01-01T00:00:00Z",
+				"assets": {"links": [{"name": "myapp-Linux_x86_64.tar.gz", "direct_asset_url": "https://example.com/linux"}]}
+			}]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	config := DefaultGitLabConfig()
+	config.BaseURL = server.URL
+	config.Token = "test-token"
+
+	inputs := []string{
+		"group/subgroup/project",
+		"/group/subgroup/project/",
+		"https://gitlab.example.com/group/subgroup/project.git",
+		"https://gitlab.example.com/group/subgroup/project/-/releases",
+	}
+	for _, input := range inputs {
+		id, err := ResolveGitLabProjectID(input, config)
+		if err != nil {
+			t.Fatalf("ResolveGitLabProjectID(%q) failed: %v", input, err)
+		}
+		if id != "4242" {
+			t.Errorf("ResolveGitLabProjectID(%q) = %s, want 4242", input, id)
+		}
+	}
+	if lookups != 1 {
+		t.Errorf("Expected resolved IDs to be cached, got %d lookups", lookups)
+	}
+
+	if id, err := ResolveGitLabProjectID("12345", config); err != nil || id != "12345" {
+		t.Errorf("Expected numeric IDs to be returned unchanged, got %s (err: %v)", id, err)
+	}
+	if _, err := ResolveGitLabProjectID("group/missing", config); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+	if _, err := ResolveGitLabProjectID(" / ", config); err == nil {
+		t.Error("Expected an error for an empty project path")
+	}
+
+	configured, err := NewGitlabReleaseFromPathWithConfig("group/subgroup/project", fileUtils.FileConfig{ProjectName: "myapp"}, config)
+	if err != nil {
+		t.Fatalf("NewGitlabReleaseFromPathWithConfig failed: %v", err)
+	}
+	if configured.ProjectId != "4242" || configured.GitLabConfig.BaseURL != server.URL {
+		t.Errorf("Expected project ID 4242 on %s, got %s on %s", server.URL, configured.ProjectId, configured.GitLabConfig.BaseURL)
+	}
+	if err := configured.GetLatestRelease(); err != nil {
+		t.Fatalf("GetLatestRelease with config failed: %v", err)
+	}
+
+	t.Setenv("GITLAB_API_URL", server.URL)
+	t.Setenv("GITLAB_TOKEN", "test-token")
+	release, err := NewGitlabReleaseFromPath("group/subgroup/project", fileUtils.FileConfig{ProjectName: "myapp"})
+	if err != nil {
+		t.Fatalf("NewGitlabReleaseFromPath failed: %v", err)
+	}
+	if release.ProjectId != "4242" {
+		t.Errorf("Expected project ID 4242, got %s", release.ProjectId)
+	}
+	if err := release.GetLatestRelease(); err != nil {
+		t.Fatalf("GetLatestRelease failed: %v", err)
+	}
+	if release.ReleaseLink != "https://example.com/linux" {
+		t.Errorf("Expected the linux asset, got %s", release.ReleaseLink)
+	}
+}
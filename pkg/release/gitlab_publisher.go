@@ -0,0 +1,141 @@
+package release
+
+import (
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// DefaultGitLabPackageName is the generic package GitLabPublisher uploads release files to
+const DefaultGitLabPackageName = "release-assets"
+
+// GitLabPublisher uploads release files to a project's generic package registry and links them
+// as release assets
+type GitLabPublisher struct {
+	ProjectId    string       // Numeric project ID or project path such as "group/subgroup/project"
+	GitLabConfig GitLabConfig // API URL, token and custom headers
+	PackageName  string       // Generic package the files are uploaded to (default: DefaultGitLabPackageName)
+}
+
+// NewGitLabPublisher creates a publisher for a project, using GITLAB_API_URL if set
+func NewGitLabPublisher(projectId, token string) *GitLabPublisher {
+	config := DefaultGitLabConfig()
+	config.Token = token
+	if baseURL := os.Getenv("GITLAB_API_URL"); baseURL != "" {
+		config.BaseURL = baseURL
+	}
+	return &GitLabPublisher{ProjectId: projectId, GitLabConfig: config}
+}
+
+// Publish creates the GitLab release if it does not exist, uploads the files as a generic package
+// and links them as release assets. Generic package versions cannot contain slashes, so neither can
+// the release tag.
+func (p *GitLabPublisher) Publish(request PublishRequest) (*ReleaseInfo, error) {
+	assets, err := request.prepare()
+	if err != nil {
+		return nil, err
+	}
+	projectId, err := ResolveGitLabProjectID(p.ProjectId, p.GitLabConfig)
+	if err != nil {
+		return nil, err
+	}
+	headers, err := (&GitLabRelease{GitLabConfig: p.GitLabConfig}).getAuthHeaders()
+	if err != nil {
+		return nil, err
+	}
+	headers["Content-Type"] = "application/json"
+
+	baseURL := strings.TrimSuffix(p.GitLabConfig.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = DefaultGitLabAPIURL
+	}
+	projectURL := baseURL + "/projects/" + projectId
+	releaseURL := projectURL + "/releases/" + url.PathEscape(request.Version)
+	client := fileUtils.NewHTTPClient(0)
+
+	var release GitlabReleaseResponse
+	err = publishRequest(client, http.MethodGet, releaseURL, headers, nil, 0, http.StatusOK, &release)
+	if isNotFound(err) {
+		body, length, encodeErr := jsonBody(struct {
+			TagName     string `json:"tag_name"`
+			Name        string `json:"name"`
+			Description string `json:"description,omitempty"`
+			Ref         string `json:"ref,omitempty"`
+		}{request.Version, request.releaseName(), request.Description, request.Ref})
+		if encodeErr != nil {
+			return nil, encodeErr
+		}
+		err = publishRequest(client, http.MethodPost, projectURL+"/releases", headers, body, length, http.StatusCreated, &release)
+		if err != nil {
+			return nil, fmt.Errorf("error creating GitLab release %s: %w", request.Version, err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("error fetching GitLab release %s: %w", request.Version, err)
+	}
+
+	// Check every name before uploading, so a conflict does not leave a partially published release
+	existing := make(map[string]int)
+	for _, link := range release.Assets.Links {
+		existing[link.Name] = link.Id
+	}
+	for _, asset := range assets {
+		if _, ok := existing[asset.name]; ok && !request.Replace {
+			return nil, fmt.Errorf("asset %s already exists in GitLab release %s", asset.name, request.Version)
+		}
+	}
+
+	packageName := p.PackageName
+	if packageName == "" {
+		packageName = DefaultGitLabPackageName
+	}
+	packageURL := projectURL + "/packages/generic/" + url.PathEscape(packageName) + "/" + url.PathEscape(request.Version)
+	for _, asset := range assets {
+		fileURL := packageURL + "/" + url.PathEscape(asset.name)
+		if err := p.upload(client, fileURL, headers, asset); err != nil {
+			return nil, fmt.Errorf("error uploading GitLab package file %s: %w", asset.name, err)
+		}
+
+		if id, ok := existing[asset.name]; ok {
+			linkURL := fmt.Sprintf("%s/assets/links/%d", releaseURL, id)
+			if err := publishRequest(client, http.MethodDelete, linkURL, headers, nil, 0, http.StatusOK, nil); err != nil {
+				return nil, fmt.Errorf("error replacing GitLab release link %s: %w", asset.name, err)
+			}
+		}
+		body, length, err := jsonBody(struct {
+			Name            string `json:"name"`
+			URL             string `json:"url"`
+			DirectAssetPath string `json:"direct_asset_path"`
+			LinkType        string `json:"link_type"`
+		}{asset.name, fileURL, "/" + asset.name, "package"})
+		if err != nil {
+			return nil, err
+		}
+		if err := publishRequest(client, http.MethodPost, releaseURL+"/assets/links", headers, body, length, http.StatusCreated, nil); err != nil {
+			return nil, fmt.Errorf("error linking GitLab release asset %s: %w", asset.name, err)
+		}
+	}
+
+	if err := publishRequest(client, http.MethodGet, releaseURL, headers, nil, 0, http.StatusOK, &release); err != nil {
+		return nil, fmt.Errorf("error fetching GitLab release %s: %w", request.Version, err)
+	}
+	return release.ToReleaseInfo(), nil
+}
+
+// upload stores one file in the generic package registry
+func (p *GitLabPublisher) upload(client *http.Client, fileURL string, headers map[string]string, asset publishAsset) error {
+	content, size, err := asset.open()
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	uploadHeaders := make(map[string]string, len(headers))
+	for key, value := range headers {
+		uploadHeaders[key] = value
+	}
+	uploadHeaders["Content-Type"] = "application/octet-stream"
+	return publishRequest(client, http.MethodPut, fileURL, uploadHeaders, content, size, http.StatusCreated, nil)
+}
@@ -18,6 +18,7 @@ func TestGitlabReleaseResponse_GetReleaseLink(t *testing.T) {
 				Name           string
 				Url            string
 				DirectAssetUrl string
+				LinkType       string
 			}
 		}
 	}
@@ -35,6 +36,7 @@ func TestGitlabReleaseResponse_GetReleaseLink(t *testing.T) {
 						Name           string
 						Url            string
 						DirectAssetUrl string
+						LinkType       string
 					}
 				}{
 					Links: []struct {
@@ -42,6 +44,7 @@ func TestGitlabReleaseResponse_GetReleaseLink(t *testing.T) {
 						Name           string
 						Url            string
 						DirectAssetUrl string
+						LinkType       string
 					}{
 						{
 							Name:           "Linux_x86_64",
@@ -61,6 +64,7 @@ func TestGitlabReleaseResponse_GetReleaseLink(t *testing.T) {
 						Name           string
 						Url            string
 						DirectAssetUrl string
+						LinkType       string
 					}
 				}{},
 			},
@@ -82,6 +86,7 @@ func TestGitlabReleaseResponse_GetReleaseLink(t *testing.T) {
 						Name           string `json:"name"`
 						Url            string `json:"url"`
 						DirectAssetUrl string `json:"direct_asset_url"`
+						LinkType       string `json:"link_type"`
 					} `json:"links"`
 				}(tt.fields.Assets),
 			}
@@ -1,10 +1,14 @@
 package release
 
 import (
+	"fmt"
 	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
@@ -302,3 +306,147 @@ func contains(s, substr string) bool {
 	}
 	return false
 }
+
+func TestGitLabRelease_GetRelease(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/projects/12345/releases/tools%2Fv1.2.0" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{
+			"tag_name": "tools/v1.2.0",
+			"assets": {
+				"links": [{
+					"name": "myapp-Linux_x86_64.tar.gz",
+					"direct_asset_url": "https://example.com/v1.2.0/linux"
+				}]
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	release := NewGitlabRelease("12345", fileUtils.FileConfig{ProjectName: "myapp"})
+	release.GitLabConfig.BaseURL = server.URL
+
+	if err := release.GetRelease("tools/v1.2.0"); err != nil {
+		t.Fatalf("GetRelease failed: %v", err)
+	}
+	if release.Version != "tools/v1.2.0" || release.ReleaseLink != "https://example.com/v1.2.0/linux" {
+		t.Errorf("Expected the v1.2.0 linux asset, got %s (%s)", release.ReleaseLink, release.Version)
+	}
+}
+
+func TestGitLabRelease_SkipsUpcomingAndHistoricalReleases(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{
+			"tag_name": "v3.0.0",
+			"released_at": "2099-01-01T00:00:00Z",
+			"upcoming_release": true,
+			"assets": {"links": [{"name": "myapp-Linux_x86_64.tar.gz", "direct_asset_url": "https://example.com/v3"}]}
+		}, {
+			"tag_name": "v2.0.0",
+			"released_at": "2024-01-01T00:00:00Z",
+			"assets": {"links": [{"name": "myapp-Linux_x86_64.tar.gz", "direct_asset_url": "https://example.com/v2"}]}
+		}, {
+			"tag_name": "v0.9.0",
+			"released_at": "2024-02-01T00:00:00Z",
+			"historical_release": true,
+			"assets": {"links": [{"name": "myapp-Linux_x86_64.tar.gz", "direct_asset_url": "https://example.com/v0"}]}
+		}]`))
+	}))
+	defer server.Close()
+
+	release := NewGitlabRelease("123", fileUtils.FileConfig{ProjectName: "myapp"})
+	release.GitLabConfig.BaseURL = server.URL
+	if err := release.GetLatestRelease(); err != nil {
+		t.Fatalf("GetLatestRelease failed: %v", err)
+	}
+	if release.Version != "v2.0.0" {
+		t.Errorf("Expected the published release v2.0.0, got %s", release.Version)
+	}
+}
+
+func TestGitLabRelease_Pagination(t *testing.T) {
+	// Releases newest first, as GitLab orders them by released_at; the two newest are upcoming
+	var all []string
+	for i := 7; i >= 1; i-- {
+		all = append(all, fmt.Sprintf(`{
+			"tag_name": "v%d.0.0",
+			"released_at": "2024-01-%02dT00:00:00Z",
+			"upcoming_release": %t,
+			"assets": {"links": [{"name": "myapp-Linux_x86_64.tar.gz", "direct_asset_url": "https://example.com/v%d"}]}
+		}`, i, i, i > 5, i))
+	}
+
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("order_by") != "released_at" || query.Get("sort") != "desc" {
+			t.Errorf("Expected releases ordered by release date, got %s", r.URL.RawQuery)
+		}
+		page, _ := strconv.Atoi(query.Get("page"))
+		perPage, _ := strconv.Atoi(query.Get("per_page"))
+		pages = append(pages, query.Get("page"))
+		start := min((page-1)*perPage, len(all))
+		end := min(start+perPage, len(all))
+		w.Write([]byte("[" + strings.Join(all[start:end], ",") + "]"))
+	}))
+	defer server.Close()
+
+	newRelease := func(perPage, maxPages int) *GitLabRelease {
+		pages = nil
+		release := NewGitlabRelease("123", fileUtils.FileConfig{ProjectName: "myapp"})
+		release.GitLabConfig.BaseURL = server.URL
+		release.GitLabConfig.PerPage = perPage
+		release.GitLabConfig.MaxPages = maxPages
+		return release
+	}
+
+	t.Run("LatestStopsAtFirstInstallableRelease", func(t *testing.T) {
+		release := newRelease(2, 0)
+		if err := release.GetLatestRelease(); err != nil {
+			t.Fatalf("GetLatestRelease failed: %v", err)
+		}
+		if release.Version != "v5.0.0" {
+			t.Errorf("Expected v5.0.0, got %s", release.Version)
+		}
+		if !reflect.DeepEqual(pages, []string{"1", "2"}) {
+			t.Errorf("Expected pages 1 and 2 to be fetched, got %v", pages)
+		}
+	})
+
+	t.Run("ListFetchesAllPages", func(t *testing.T) {
+		release := newRelease(2, 0)
+		releases, err := release.assetSource().(ReleaseLister).Releases()
+		if err != nil {
+			t.Fatalf("Releases failed: %v", err)
+		}
+		if len(releases) != 5 || releases[0].Version != "v5.0.0" || releases[4].Version != "v1.0.0" {
+			t.Errorf("Expected the 5 published releases newest first, got %v", releases)
+		}
+		if !reflect.DeepEqual(pages, []string{"1", "2", "3", "4"}) {
+			t.Errorf("Expected pages 1 to 4 to be fetched, got %v", pages)
+		}
+	})
+
+	t.Run("MaxPages", func(t *testing.T) {
+		release := newRelease(2, 2)
+		releases, err := release.assetSource().(ReleaseLister).Releases()
+		if err != nil {
+			t.Fatalf("Releases failed: %v", err)
+		}
+		if len(releases) != 2 || len(pages) != 2 {
+			t.Errorf("Expected 2 releases from 2 pages, got %d releases from pages %v", len(releases), pages)
+		}
+	})
+
+	t.Run("PerPageIsLimited", func(t *testing.T) {
+		release := newRelease(500, 0)
+		if err := release.GetLatestRelease(); err != nil {
+			t.Fatalf("GetLatestRelease failed: %v", err)
+		}
+		if release.GitLabConfig.perPage() != DefaultGitLabPerPage || len(pages) != 1 {
+			t.Errorf("Expected a single page of %d releases, got pages %v", DefaultGitLabPerPage, pages)
+		}
+	})
+}
@@ -3,10 +3,14 @@ package release
 import (
 	"context"
 	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
 	"io"
+	"log"
 	"math"
+	"math/rand"
 	"net/http"
-	"strconv"
+	"sort"
+	"sync"
 	"time"
 )
 
@@ -19,8 +23,20 @@ type HTTPClientConfig struct {
 	Timeout         time.Duration // Request timeout
 	RateLimitDelay  time.Duration // Additional delay for rate limiting
 	CircuitBreaker  bool          // Enable circuit breaker pattern
+	Jitter          JitterMode    // Randomization of backoff delays (empty: none; DefaultHTTPClientConfig: equal)
+	Logger          *log.Logger   // Receives the chosen retry delays (default: the standard logger)
 }
 
+// JitterMode controls how backoff delays are randomized, so that clients across a fleet do not
+// retry in lockstep after a shared outage
+type JitterMode string
+
+const (
+	JitterNone  JitterMode = "none"  // Deterministic exponential backoff
+	JitterFull  JitterMode = "full"  // Uniformly random delay between 0 and the backoff
+	JitterEqual JitterMode = "equal" // Half the backoff plus a random delay up to the other half
+)
+
 // DefaultHTTPClientConfig returns a sensible default configuration
 func DefaultHTTPClientConfig() HTTPClientConfig {
 	return HTTPClientConfig{
@@ -31,87 +47,149 @@ func DefaultHTTPClientConfig() HTTPClientConfig {
 		Timeout:         30 * time.Second,
 		RateLimitDelay:  1 * time.Second,
 		CircuitBreaker:  true,
+		Jitter:          JitterEqual,
 	}
 }
 
+// DefaultCDNHTTPClientConfig returns the retry configuration for CDN downloads, which allows
+// much longer requests than the API defaults because the timeout covers downloading the binary
+func DefaultCDNHTTPClientConfig() HTTPClientConfig {
+	config := DefaultHTTPClientConfig()
+	config.Timeout = 30 * time.Minute // Long timeout for large binaries
+	return config
+}
+
 // RetryableHTTPClient provides HTTP client with retry logic and rate limiting
 type RetryableHTTPClient struct {
 	client         *http.Client
 	config         HTTPClientConfig
-	failureCount   int
-	lastFailure    time.Time
-	circuitOpen    bool
+	circuitMu      sync.Mutex
+	circuits       map[string]*CircuitState // Circuit breaker state per target host
 	circuitTimeout time.Duration
+	random         func() float64 // Source of jitter in [0, 1); replaced in tests
 }
 
 // NewRetryableHTTPClient creates a new HTTP client with retry capabilities
 func NewRetryableHTTPClient(config HTTPClientConfig) *RetryableHTTPClient {
 	return &RetryableHTTPClient{
-		client: &http.Client{
-			Timeout: config.Timeout,
-		},
+		client:         fileUtils.NewHTTPClient(config.Timeout),
 		config:         config,
 		circuitTimeout: 60 * time.Second, // Circuit breaker timeout
+		random:         rand.Float64,
 	}
 }
 
+// circuitFailureThreshold is the number of consecutive failures that opens a host's circuit
+const circuitFailureThreshold = 5
+
+// CircuitState describes the circuit breaker of one host
+type CircuitState struct {
+	Host         string    // Target host, including the port if the URL has one
+	Open         bool      // True while requests to the host are rejected
+	FailureCount int       // Consecutive failed attempts
+	LastFailure  time.Time // Time of the most recent failure
+}
+
 // Do executes an HTTP request with retry logic and rate limiting
 func (c *RetryableHTTPClient) Do(req *http.Request) (*http.Response, error) {
 	// Check circuit breaker
-	if c.config.CircuitBreaker && c.isCircuitOpen() {
-		return nil, fmt.Errorf("circuit breaker is open, too many recent failures")
+	host := req.URL.Host
+	if c.config.CircuitBreaker && c.isCircuitOpen(host) {
+		return nil, fmt.Errorf("circuit breaker is open for %s, too many recent failures", host)
 	}
 
+	// Identify the client unless the caller already set a User-Agent
+	fileUtils.SetUserAgent(req)
+
 	var lastErr error
+	labels := map[string]string{"host": host}
 	
 	for attempt := 0; attempt <= c.config.MaxRetries; attempt++ {
-		// Add context with timeout for each attempt
-		ctx, cancel := context.WithTimeout(req.Context(), c.config.Timeout)
+		if err := req.Context().Err(); err != nil {
+			return nil, fmt.Errorf("request to %s not sent: %w", host, err)
+		}
+		if attempt > 0 {
+			fileUtils.GetMetrics().Counter(fileUtils.MetricHTTPRetries, 1, labels)
+		}
+
+		// Add context with timeout for each attempt. The timeout covers reading the body,
+		// so the context is only released once the caller closes it.
+		var ctx context.Context
+		var cancel context.CancelFunc
+		if c.config.Timeout > 0 {
+			ctx, cancel = context.WithTimeout(req.Context(), c.config.Timeout)
+		} else {
+			ctx, cancel = context.WithCancel(req.Context())
+		}
 		reqWithContext := req.WithContext(ctx)
 		
 		resp, err := c.client.Do(reqWithContext)
-		cancel()
 		
 		if err == nil {
 			// Check for rate limiting
 			if resp.StatusCode == http.StatusTooManyRequests {
-				c.handleRateLimit(resp, attempt)
+				waitErr := c.handleRateLimit(req, resp, attempt)
 				resp.Body.Close()
-				c.recordFailure()
+				cancel()
+				c.recordFailure(labels)
+				if waitErr != nil {
+					return nil, fmt.Errorf("rate limited, not retrying: %w: %w", newHTTPStatusError(resp), waitErr)
+				}
 				if attempt < c.config.MaxRetries {
 					continue
 				}
-				return nil, fmt.Errorf("rate limited after %d attempts", c.config.MaxRetries+1)
+				return nil, fmt.Errorf("rate limited after %d attempts: %w", c.config.MaxRetries+1, newHTTPStatusError(resp))
 			}
 
 			// Check for server errors that should be retried
 			if c.shouldRetry(resp.StatusCode) {
 				resp.Body.Close()
-				c.recordFailure()
+				cancel()
+				c.recordFailure(labels)
 				if attempt < c.config.MaxRetries {
-					c.waitBeforeRetry(attempt)
+					if err := c.waitBeforeRetry(req, attempt, fmt.Sprintf("status %d", resp.StatusCode)); err != nil {
+						return nil, fmt.Errorf("server error, not retrying: %w: %w", newHTTPStatusError(resp), err)
+					}
 					continue
 				}
-				return nil, fmt.Errorf("server error %d after %d attempts", resp.StatusCode, c.config.MaxRetries+1)
+				return nil, fmt.Errorf("server error after %d attempts: %w", c.config.MaxRetries+1, newHTTPStatusError(resp))
 			}
 
 			// Success - reset failure count and circuit breaker
-			c.resetCircuitBreaker()
+			c.ResetCircuit(host)
+			resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
 			return resp, nil
 		}
 		
+		cancel()
 		lastErr = err
-		c.recordFailure()
+		c.recordFailure(labels)
+		if ctxErr := req.Context().Err(); ctxErr != nil {
+			return nil, fmt.Errorf("request to %s stopped: %w", host, ctxErr)
+		}
 		
 		// Don't wait after the last attempt
 		if attempt < c.config.MaxRetries {
-			c.waitBeforeRetry(attempt)
+			if err := c.waitBeforeRetry(req, attempt, err.Error()); err != nil {
+				return nil, fmt.Errorf("request failed, not retrying: %v: %w", lastErr, err)
+			}
 		}
 	}
 	
 	return nil, fmt.Errorf("request failed after %d attempts: %w", c.config.MaxRetries+1, lastErr)
 }
 
+// cancelOnCloseBody releases the request context when the response body is closed
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
 // shouldRetry determines if a request should be retried based on status code
 func (c *RetryableHTTPClient) shouldRetry(statusCode int) bool {
 	switch statusCode {
@@ -126,72 +204,157 @@ func (c *RetryableHTTPClient) shouldRetry(statusCode int) bool {
 	}
 }
 
-// handleRateLimit handles rate limiting responses
-func (c *RetryableHTTPClient) handleRateLimit(resp *http.Response, attempt int) {
-	// Check for Retry-After header
-	retryAfter := resp.Header.Get("Retry-After")
-	if retryAfter != "" {
-		if seconds, err := strconv.Atoi(retryAfter); err == nil {
-			delay := time.Duration(seconds) * time.Second
-			// Cap the delay to prevent excessive waiting
-			if delay > c.config.MaxDelay {
-				delay = c.config.MaxDelay
-			}
-			time.Sleep(delay)
-			return
+// handleRateLimit waits before retrying a rate limited request
+func (c *RetryableHTTPClient) handleRateLimit(req *http.Request, resp *http.Response, attempt int) error {
+	// Check for Retry-After header. The server asked for this delay, so it is not shortened by jitter.
+	if delay := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); delay > 0 {
+		// Cap the delay to prevent excessive waiting
+		if delay > c.config.MaxDelay {
+			delay = c.config.MaxDelay
 		}
+		c.logf("Rate limited by %s, retrying in %v (Retry-After)", req.URL.Host, delay)
+		return sleepContext(req.Context(), delay)
 	}
-	
+
 	// Fallback to configured rate limit delay with exponential backoff
-	delay := c.config.RateLimitDelay * time.Duration(math.Pow(c.config.BackoffFactor, float64(attempt)))
-	if delay > c.config.MaxDelay {
-		delay = c.config.MaxDelay
-	}
-	time.Sleep(delay)
+	delay := c.backoffDelay(c.config.RateLimitDelay, attempt)
+	c.logf("Rate limited by %s, retrying in %v (attempt %d/%d)", req.URL.Host, delay, attempt+1, c.config.MaxRetries)
+	return sleepContext(req.Context(), delay)
 }
 
 // waitBeforeRetry implements exponential backoff
-func (c *RetryableHTTPClient) waitBeforeRetry(attempt int) {
-	delay := time.Duration(float64(c.config.InitialDelay) * math.Pow(c.config.BackoffFactor, float64(attempt)))
+func (c *RetryableHTTPClient) waitBeforeRetry(req *http.Request, attempt int, reason string) error {
+	delay := c.backoffDelay(c.config.InitialDelay, attempt)
+	c.logf("Request to %s failed (%s), retrying in %v (attempt %d/%d)", req.URL.Host, reason, delay, attempt+1, c.config.MaxRetries)
+	return sleepContext(req.Context(), delay)
+}
+
+// newRetryTimer starts the timer retries wait for; tests replace it to skip real backoffs
+var newRetryTimer = time.NewTimer
+
+// sleepContext waits for delay unless ctx is done first. A delay reaching past the context's
+// deadline fails at once, as the retry could not finish in time anyway.
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		return fmt.Errorf("retry in %v would pass the deadline: %w", delay, context.DeadlineExceeded)
+	}
+	timer := newRetryTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// backoffDelay returns the jittered exponential backoff for an attempt, capped at MaxDelay
+func (c *RetryableHTTPClient) backoffDelay(base time.Duration, attempt int) time.Duration {
+	delay := time.Duration(float64(base) * math.Pow(c.config.BackoffFactor, float64(attempt)))
 	if delay > c.config.MaxDelay {
 		delay = c.config.MaxDelay
 	}
-	time.Sleep(delay)
+
+	random := c.random
+	if random == nil {
+		random = rand.Float64
+	}
+	switch c.config.Jitter {
+	case JitterFull:
+		return time.Duration(random() * float64(delay))
+	case JitterEqual:
+		return delay/2 + time.Duration(random()*float64(delay/2))
+	}
+	return delay
 }
 
-// recordFailure records a failure for circuit breaker logic
-func (c *RetryableHTTPClient) recordFailure() {
-	c.failureCount++
-	c.lastFailure = time.Now()
-	
-	// Open circuit breaker after 5 consecutive failures
-	if c.config.CircuitBreaker && c.failureCount >= 5 {
-		c.circuitOpen = true
+// logf reports retry decisions to the configured logger
+func (c *RetryableHTTPClient) logf(format string, args ...interface{}) {
+	if c.config.Logger != nil {
+		c.config.Logger.Printf(format, args...)
+		return
 	}
+	log.Printf(format, args...)
 }
 
-// resetCircuitBreaker resets the circuit breaker state
-func (c *RetryableHTTPClient) resetCircuitBreaker() {
-	c.failureCount = 0
-	c.circuitOpen = false
+// recordFailure records a failure for circuit breaker logic and metrics
+func (c *RetryableHTTPClient) recordFailure(labels map[string]string) {
+	fileUtils.GetMetrics().Counter(fileUtils.MetricHTTPFailures, 1, labels)
+
+	c.circuitMu.Lock()
+	defer c.circuitMu.Unlock()
+	host := labels["host"]
+	if c.circuits == nil {
+		c.circuits = make(map[string]*CircuitState)
+	}
+	state := c.circuits[host]
+	if state == nil {
+		state = &CircuitState{Host: host}
+		c.circuits[host] = state
+	}
+	state.FailureCount++
+	state.LastFailure = time.Now()
+
+	// Open circuit breaker after 5 consecutive failures
+	if c.config.CircuitBreaker && state.FailureCount >= circuitFailureThreshold {
+		state.Open = true
+	}
 }
 
-// isCircuitOpen checks if the circuit breaker is open
-func (c *RetryableHTTPClient) isCircuitOpen() bool {
-	if !c.circuitOpen {
+// isCircuitOpen checks if the circuit breaker of a host is open
+func (c *RetryableHTTPClient) isCircuitOpen(host string) bool {
+	c.circuitMu.Lock()
+	defer c.circuitMu.Unlock()
+	state := c.circuits[host]
+	if state == nil || !state.Open {
 		return false
 	}
-	
+
 	// Check if circuit breaker timeout has passed
-	if time.Since(c.lastFailure) > c.circuitTimeout {
-		c.circuitOpen = false
-		c.failureCount = 0
+	if time.Since(state.LastFailure) > c.circuitTimeout {
+		delete(c.circuits, host)
 		return false
 	}
-	
+
 	return true
 }
 
+// CircuitState returns the circuit breaker state of a host (e.g. "api.github.com")
+func (c *RetryableHTTPClient) CircuitState(host string) CircuitState {
+	c.circuitMu.Lock()
+	defer c.circuitMu.Unlock()
+	if state := c.circuits[host]; state != nil {
+		return *state
+	}
+	return CircuitState{Host: host}
+}
+
+// CircuitStates returns the circuit breaker state of every host with recent failures
+func (c *RetryableHTTPClient) CircuitStates() []CircuitState {
+	c.circuitMu.Lock()
+	defer c.circuitMu.Unlock()
+	states := make([]CircuitState, 0, len(c.circuits))
+	for _, state := range c.circuits {
+		states = append(states, *state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Host < states[j].Host })
+	return states
+}
+
+// ResetCircuit closes the circuit breaker of a host and clears its failures
+func (c *RetryableHTTPClient) ResetCircuit(host string) {
+	c.circuitMu.Lock()
+	defer c.circuitMu.Unlock()
+	delete(c.circuits, host)
+}
+
+// ResetCircuits closes the circuit breakers of all hosts
+func (c *RetryableHTTPClient) ResetCircuits() {
+	c.circuitMu.Lock()
+	defer c.circuitMu.Unlock()
+	c.circuits = nil
+}
+
 // Get is a convenience method for GET requests
 func (c *RetryableHTTPClient) Get(url string) (*http.Response, error) {
 	req, err := http.NewRequest("GET", url, nil)
@@ -203,7 +366,12 @@ func (c *RetryableHTTPClient) Get(url string) (*http.Response, error) {
 
 // GetWithHeaders is a convenience method for GET requests with custom headers
 func (c *RetryableHTTPClient) GetWithHeaders(url string, headers map[string]string) (*http.Response, error) {
-	req, err := http.NewRequest("GET", url, nil)
+	return c.GetWithHeadersContext(context.Background(), url, headers)
+}
+
+// GetWithHeadersContext is GetWithHeaders, stopping the request and its retries when ctx is done
+func (c *RetryableHTTPClient) GetWithHeadersContext(ctx context.Context, url string, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -215,8 +383,77 @@ func (c *RetryableHTTPClient) GetWithHeaders(url string, headers map[string]stri
 	return c.Do(req)
 }
 
-// ReadResponseBody safely reads and closes the response body
+// DefaultMaxMetadataResponseSize limits API metadata responses, so a misconfigured URL that serves
+// a large file fails instead of exhausting memory
+const DefaultMaxMetadataResponseSize int64 = 32 << 20
+
+// DefaultMetadataTimeout bounds GitHub API requests, including reading the response
+const DefaultMetadataTimeout = 30 * time.Second
+
+// ErrResponseTooLarge is returned (wrapped) when a metadata response exceeds its size limit
+var ErrResponseTooLarge = fileUtils.NewError(fileUtils.ErrorCodeNetwork, "response too large")
+
+// ReadResponseBody safely reads and closes the response body, failing with ErrResponseTooLarge
+// beyond DefaultMaxMetadataResponseSize
 func ReadResponseBody(resp *http.Response) ([]byte, error) {
-	defer resp.Body.Close()
-	return io.ReadAll(resp.Body)
+	return ReadResponseBodyLimit(resp, 0)
+}
+
+// ReadResponseBodyLimit reads and closes the response body, failing with ErrResponseTooLarge beyond
+// limit bytes (0 uses DefaultMaxMetadataResponseSize)
+func ReadResponseBodyLimit(resp *http.Response, limit int64) ([]byte, error) {
+	body, err := newLimitedBody(resp, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+// limitedBody fails with ErrResponseTooLarge once more than limit bytes are read from a response
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+	limit     int64
+}
+
+// newLimitedBody limits a response body to limit bytes (0 uses DefaultMaxMetadataResponseSize). A
+// declared Content-Length above the limit fails without reading the body.
+func newLimitedBody(resp *http.Response, limit int64) (io.ReadCloser, error) {
+	if limit <= 0 {
+		limit = DefaultMaxMetadataResponseSize
+	}
+	if resp.ContentLength > limit {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: %d bytes exceed the limit of %d bytes", ErrResponseTooLarge, resp.ContentLength, limit)
+	}
+	return &limitedBody{ReadCloser: resp.Body, remaining: limit, limit: limit}, nil
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		// Probe for data beyond the limit instead of silently truncating the response
+		var probe [1]byte
+		n, err := b.ReadCloser.Read(probe[:])
+		if n > 0 {
+			return 0, fmt.Errorf("%w: more than %d bytes", ErrResponseTooLarge, b.limit)
+		}
+		return 0, err
+	}
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	return n, err
+}
+
+// readMetadata reads and closes a metadata response body
+func readMetadata(provider string, body io.ReadCloser) ([]byte, error) {
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body from %s: %w", provider, err)
+	}
+	return data, nil
 }
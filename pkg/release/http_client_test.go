@@ -1,8 +1,16 @@
 package release
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"io"
+	"log"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -64,6 +72,7 @@ func TestRetryableHTTPClient_RetryOnServerError(t *testing.T) {
 }
 
 func TestRetryableHTTPClient_RateLimitHandling(t *testing.T) {
+	realRetryTimers(t)
 	attempts := 0
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		attempts++
@@ -132,6 +141,60 @@ func TestRetryableHTTPClient_CircuitBreaker(t *testing.T) {
 	}
 }
 
+func TestRetryableHTTPClient_CircuitBreakerPerHost(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer healthy.Close()
+
+	config := DefaultHTTPClientConfig()
+	config.MaxRetries = 0
+	client := NewRetryableHTTPClient(config)
+
+	for i := 0; i < 5; i++ {
+		client.Get(failing.URL)
+	}
+	failingHost := failing.Listener.Addr().String()
+	state := client.CircuitState(failingHost)
+	if !state.Open || state.FailureCount != 5 || state.LastFailure.IsZero() {
+		t.Errorf("Expected open circuit after 5 failures, got %+v", state)
+	}
+
+	// A failing CDN must not block requests to other hosts
+	resp, err := client.Get(healthy.URL)
+	if err != nil {
+		t.Fatalf("Expected healthy host to be reachable, got %v", err)
+	}
+	resp.Body.Close()
+	if state := client.CircuitState(healthy.Listener.Addr().String()); state.Open || state.FailureCount != 0 {
+		t.Errorf("Expected closed circuit for healthy host, got %+v", state)
+	}
+	if states := client.CircuitStates(); len(states) != 1 || states[0].Host != failingHost {
+		t.Errorf("Expected only the failing host to be tracked, got %+v", states)
+	}
+
+	if _, err := client.Get(failing.URL); err == nil || !contains(err.Error(), "circuit breaker is open for "+failingHost) {
+		t.Errorf("Expected circuit breaker error, got %v", err)
+	}
+
+	client.ResetCircuit(failingHost)
+	if state := client.CircuitState(failingHost); state.Open {
+		t.Errorf("Expected circuit to be closed after reset, got %+v", state)
+	}
+	if _, err := client.Get(failing.URL); err == nil || contains(err.Error(), "circuit breaker") {
+		t.Errorf("Expected the request to reach the server after reset, got %v", err)
+	}
+
+	client.ResetCircuits()
+	if states := client.CircuitStates(); len(states) != 0 {
+		t.Errorf("Expected no tracked hosts after ResetCircuits, got %+v", states)
+	}
+}
+
 func TestRetryableHTTPClient_GetWithHeaders(t *testing.T) {
 	expectedHeaders := map[string]string{
 		"Authorization": "Bearer token123",
@@ -242,6 +305,73 @@ func TestDefaultHTTPClientConfig(t *testing.T) {
 	if !config.CircuitBreaker {
 		t.Error("Expected CircuitBreaker to be enabled")
 	}
+
+	if config.Jitter != JitterEqual {
+		t.Errorf("Expected equal jitter, got %q", config.Jitter)
+	}
+}
+
+func TestRetryableHTTPClient_BackoffJitter(t *testing.T) {
+	config := DefaultHTTPClientConfig()
+	config.InitialDelay = 100 * time.Millisecond
+	config.MaxDelay = 1 * time.Second
+
+	testCases := []struct {
+		jitter   JitterMode
+		random   float64
+		attempt  int
+		expected time.Duration
+	}{
+		{JitterNone, 0.5, 0, 100 * time.Millisecond},
+		{JitterNone, 0.5, 2, 400 * time.Millisecond},
+		{JitterNone, 0.5, 10, 1 * time.Second},
+		{"", 0.5, 1, 200 * time.Millisecond},
+		{JitterFull, 0, 2, 0},
+		{JitterFull, 0.25, 2, 100 * time.Millisecond},
+		{JitterFull, 0.5, 10, 500 * time.Millisecond},
+		{JitterEqual, 0, 2, 200 * time.Millisecond},
+		{JitterEqual, 0.5, 2, 300 * time.Millisecond},
+		{JitterEqual, 0.999, 10, 999500 * time.Microsecond},
+	}
+
+	for _, tc := range testCases {
+		config.Jitter = tc.jitter
+		client := NewRetryableHTTPClient(config)
+		client.random = func() float64 { return tc.random }
+		if got := client.backoffDelay(config.InitialDelay, tc.attempt); got != tc.expected {
+			t.Errorf("backoffDelay(jitter=%q, random=%v, attempt=%d) = %v, want %v", tc.jitter, tc.random, tc.attempt, got, tc.expected)
+		}
+	}
+}
+
+func TestRetryableHTTPClient_LogsRetryDelays(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	config := DefaultHTTPClientConfig()
+	config.InitialDelay = 10 * time.Millisecond
+	config.Jitter = JitterNone
+	config.Logger = log.New(&output, "", 0)
+	client := NewRetryableHTTPClient(config)
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Expected success after retry, got error: %v", err)
+	}
+	resp.Body.Close()
+
+	if !bytes.Contains(output.Bytes(), []byte("status 503")) || !bytes.Contains(output.Bytes(), []byte("retrying in 10ms")) {
+		t.Errorf("Expected the retry delay to be logged, got %q", output.String())
+	}
 }
 
 func TestReadResponseBody(t *testing.T) {
@@ -287,3 +417,158 @@ func BenchmarkRetryableHTTPClient(b *testing.B) {
 }
 
 
+
+func TestRetryableHTTPClient_SetsUserAgent(t *testing.T) {
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	fileUtils.SetClientInfo(fileUtils.ClientInfo{AppName: "fleet-manager", ContactURL: "ops@example.com"})
+	defer fileUtils.SetClientInfo(fileUtils.ClientInfo{})
+
+	client := NewRetryableHTTPClient(DefaultHTTPClientConfig())
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Expected success, got error: %v", err)
+	}
+	resp.Body.Close()
+
+	if userAgent != "fleet-manager (+ops@example.com) go-binary-updater/1.0" {
+		t.Errorf("Unexpected User-Agent: %q", userAgent)
+	}
+}
+
+func TestRetryableHTTPClient_BodyReadableAfterDo(t *testing.T) {
+	payload := bytes.Repeat([]byte("x"), 8<<20)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(payload)
+	}))
+	defer server.Close()
+
+	client := NewRetryableHTTPClient(DefaultHTTPClientConfig())
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Expected success, got error: %v", err)
+	}
+	body, err := ReadResponseBody(resp)
+	if err != nil {
+		t.Fatalf("Expected the full body to be readable after Do returns, got error: %v", err)
+	}
+	if len(body) != len(payload) {
+		t.Errorf("Expected %d bytes, got %d", len(payload), len(body))
+	}
+}
+
+// countingMetrics sums counters by metric name
+type countingMetrics struct {
+	mu       sync.Mutex
+	counters map[string]int64
+}
+
+func (m *countingMetrics) Counter(name string, value int64, labels map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counters[name] += value
+}
+
+func (m *countingMetrics) Duration(name string, duration time.Duration, labels map[string]string) {}
+
+func TestRetryableHTTPClient_ReportsMetrics(t *testing.T) {
+	metrics := &countingMetrics{counters: make(map[string]int64)}
+	fileUtils.SetMetrics(metrics)
+	defer fileUtils.SetMetrics(nil)
+
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	config := DefaultHTTPClientConfig()
+	config.InitialDelay = 10 * time.Millisecond
+	resp, err := NewRetryableHTTPClient(config).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Expected success after retries, got error: %v", err)
+	}
+	resp.Body.Close()
+
+	if metrics.counters[fileUtils.MetricHTTPRetries] != 2 || metrics.counters[fileUtils.MetricHTTPFailures] != 2 {
+		t.Errorf("Expected 2 retries and 2 failures, got %v", metrics.counters)
+	}
+}
+
+func TestReadResponseBodyLimit(t *testing.T) {
+	newResponse := func(body string, contentLength int64) *http.Response {
+		return &http.Response{Body: io.NopCloser(strings.NewReader(body)), ContentLength: contentLength}
+	}
+
+	if data, err := ReadResponseBodyLimit(newResponse("12345", -1), 5); err != nil || string(data) != "12345" {
+		t.Errorf("Expected a body at the limit to be read, got %q (%v)", data, err)
+	}
+	if _, err := ReadResponseBodyLimit(newResponse("123456", -1), 5); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("Expected ErrResponseTooLarge for a body beyond the limit, got %v", err)
+	}
+	if _, err := ReadResponseBodyLimit(newResponse("", 1<<40), 0); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("Expected ErrResponseTooLarge for a declared length beyond the default limit, got %v", err)
+	}
+}
+
+func TestMetadataResponseSizeLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A misconfigured URL serving a large file instead of release metadata
+		w.Write([]byte(`[{"tag_name": "v1.0.0", "body": "` + strings.Repeat("x", 4096) + `"}]`))
+	}))
+	defer server.Close()
+
+	github := NewGithubRelease("owner/repo", fileUtils.FileConfig{})
+	github.BaseURL = server.URL
+	github.MaxMetadataSize = 1024
+	if _, err := github.assetSource().(ReleaseLister).Releases(); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("Expected the GitHub release list to exceed the limit, got %v", err)
+	}
+
+	gitlab := NewGitlabRelease("123", fileUtils.FileConfig{})
+	gitlab.GitLabConfig.BaseURL = server.URL
+	gitlab.GitLabConfig.MaxMetadataSize = 1024
+	if err := gitlab.GetLatestRelease(); !errors.Is(err, ErrResponseTooLarge) {
+		t.Errorf("Expected the GitLab release list to exceed the limit, got %v", err)
+	}
+
+	github.MaxMetadataSize = 0
+	releases, err := github.assetSource().(ReleaseLister).Releases()
+	if err != nil || len(releases) != 1 {
+		t.Errorf("Expected the release list within the default limit, got %d releases (%v)", len(releases), err)
+	}
+}
+
+func TestRetryableHTTPClient_RetryPastDeadline(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := NewRetryableHTTPClient(DefaultHTTPClientConfig()).GetWithHeadersContext(ctx, server.URL, nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the deadline to be exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected no wait for a retry after the deadline, took %v", elapsed)
+	}
+	if attempts != 1 {
+		t.Errorf("Expected 1 attempt, got %d", attempts)
+	}
+}
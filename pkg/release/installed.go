@@ -0,0 +1,61 @@
+package release
+
+import (
+	"errors"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"log"
+)
+
+// skipInstalled reports whether the resolved version is already installed intact and active, in
+// which case its download and installation are skipped unless force is set
+func skipInstalled(config fileUtils.FileConfig, version string, force bool) bool {
+	if force || version == "" {
+		return false
+	}
+	if err := fileUtils.CheckInstalledVersion(config, version); err != nil {
+		if !errors.Is(err, fileUtils.ErrVersionNotInstalled) {
+			log.Printf("Installing %s again: %v", version, err)
+		}
+		return false
+	}
+	log.Printf("Version %s is already installed, skipping download", version)
+	return true
+}
+
+// repairInstallation restores the broken parts of the installation and reinstalls the recorded
+// version with reinstall when its binary cannot be restored from the local files
+func repairInstallation(config fileUtils.FileConfig, reinstall func(version string) error) (*fileUtils.RepairResult, error) {
+	result, err := fileUtils.RepairInstallation(config)
+	if err != nil || !result.NeedsReinstall {
+		return result, err
+	}
+	fmt.Printf("%s %s is damaged, reinstalling\n", config.BinaryName, result.Version)
+	if err := reinstall(result.Version); err != nil {
+		return result, fmt.Errorf("failed to reinstall %s: %w", result.Version, err)
+	}
+	result.Repaired = append(result.Repaired, fileUtils.GetVersionedBinaryPath(config, result.Version))
+	return result, nil
+}
+
+// RepairInstallation validates the installed version against its install receipt and restores only
+// what is broken. A missing or modified binary is downloaded and installed again as with Force.
+func (g *GithubRelease) RepairInstallation() (*fileUtils.RepairResult, error) {
+	return repairInstallation(g.Config, func(version string) error {
+		force := g.Force
+		g.Force = true
+		defer func() { g.Force = force }()
+		return g.InstallRelease(version)
+	})
+}
+
+// RepairInstallation validates the installed version against its install receipt and restores only
+// what is broken. A missing or modified binary is downloaded and installed again as with Force.
+func (r *GitLabRelease) RepairInstallation() (*fileUtils.RepairResult, error) {
+	return repairInstallation(r.Config, func(version string) error {
+		force := r.Force
+		r.Force = true
+		defer func() { r.Force = force }()
+		return r.InstallRelease(version)
+	})
+}
@@ -0,0 +1,145 @@
+package release
+
+import (
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGithubRelease_SkipsInstalledVersion(t *testing.T) {
+	tempDir := t.TempDir()
+	archivePath := filepath.Join(tempDir, "fixture.tar.gz")
+	writeTestTarGz(t, archivePath, map[string]string{"myapp": "#!/bin/sh\necho installed\n"})
+	archive, _ := os.ReadFile(archivePath)
+
+	downloads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downloads++
+		w.Write(archive)
+	}))
+	defer server.Close()
+
+	fileConfig := fileUtils.FileConfig{
+		BaseBinaryDirectory:    filepath.Join(tempDir, "bin"),
+		VersionedDirectoryName: "versions",
+		SourceBinaryName:       "myapp",
+		BinaryName:             "myapp",
+		ProjectName:            "myapp",
+		CreateLocalSymlink:     true,
+		StagingDirectory:       filepath.Join(tempDir, "staging"),
+	}
+	release := NewGithubRelease("owner/repo", fileConfig)
+	release.Source = &fakeAssetSource{release: &ReleaseInfo{
+		Version: "v1.2.0",
+		Assets:  []AssetInfo{{Name: "myapp-Linux_x86_64.tar.gz", URL: server.URL + "/myapp-Linux_x86_64.tar.gz"}},
+	}}
+	update := func() {
+		t.Helper()
+		if err := release.DownloadLatestRelease(); err != nil {
+			t.Fatalf("DownloadLatestRelease() error = %v", err)
+		}
+		if err := release.InstallLatestRelease(); err != nil {
+			t.Fatalf("InstallLatestRelease() error = %v", err)
+		}
+	}
+
+	update()
+	if downloads != 1 || release.AlreadyInstalled {
+		t.Fatalf("Expected the first update to download, got %d downloads (AlreadyInstalled %v)", downloads, release.AlreadyInstalled)
+	}
+
+	result, err := release.DownloadLatestReleaseWithResult()
+	if err != nil {
+		t.Fatalf("DownloadLatestReleaseWithResult() error = %v", err)
+	}
+	if downloads != 1 || !release.AlreadyInstalled || !result.AlreadyInstalled {
+		t.Errorf("Expected the installed version to be skipped, got %d downloads and result %+v", downloads, result)
+	}
+	if err := release.InstallLatestRelease(); err != nil {
+		t.Errorf("InstallLatestRelease() of the installed version error = %v", err)
+	}
+
+	release.Force = true
+	update()
+	if downloads != 2 {
+		t.Errorf("Expected Force to download again, got %d downloads", downloads)
+	}
+	release.Force = false
+
+	// A modified binary no longer matches the recorded checksum and is installed again
+	os.WriteFile(fileUtils.GetVersionedBinaryPath(fileConfig, "v1.2.0"), []byte("tampered"), 0755)
+	update()
+	if downloads != 3 || release.AlreadyInstalled {
+		t.Errorf("Expected the modified installation to be replaced, got %d downloads", downloads)
+	}
+	if data, _ := os.ReadFile(fileUtils.GetVersionedBinaryPath(fileConfig, "v1.2.0")); string(data) != "#!/bin/sh\necho installed\n" {
+		t.Errorf("Expected the binary to be restored, got %q", data)
+	}
+
+	// Locking needs the asset's checksum, so the installed version is downloaded anyway
+	if _, err := release.LockLatestRelease(); err != nil {
+		t.Errorf("LockLatestRelease() error = %v", err)
+	}
+	if downloads != 4 || release.Force {
+		t.Errorf("Expected LockLatestRelease to download without changing Force, got %d downloads", downloads)
+	}
+}
+
+func TestGithubRelease_RepairInstallation(t *testing.T) {
+	tempDir := t.TempDir()
+	archivePath := filepath.Join(tempDir, "fixture.tar.gz")
+	writeTestTarGz(t, archivePath, map[string]string{"myapp": "#!/bin/sh\necho installed\n"})
+	archive, _ := os.ReadFile(archivePath)
+
+	downloads := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		downloads++
+		w.Write(archive)
+	}))
+	defer server.Close()
+
+	fileConfig := fileUtils.FileConfig{
+		BaseBinaryDirectory:    filepath.Join(tempDir, "bin"),
+		VersionedDirectoryName: "versions",
+		SourceBinaryName:       "myapp",
+		BinaryName:             "myapp",
+		ProjectName:            "myapp",
+		CreateLocalSymlink:     true,
+		StagingDirectory:       filepath.Join(tempDir, "staging"),
+	}
+	release := NewGithubRelease("owner/repo", fileConfig)
+	release.Source = &fakeListingSource{releases: []ReleaseInfo{{
+		Version: "v1.2.0",
+		Assets:  []AssetInfo{{Name: "myapp-Linux_x86_64.tar.gz", URL: server.URL + "/myapp-Linux_x86_64.tar.gz"}},
+	}}}
+	if err := release.InstallRelease("v1.2.0"); err != nil {
+		t.Fatalf("InstallRelease() error = %v", err)
+	}
+
+	// A missing symlink is restored without downloading
+	localSymlinkPath := fileUtils.GetLocalSymlinkPath(fileConfig)
+	os.Remove(localSymlinkPath)
+	result, err := release.RepairInstallation()
+	if err != nil {
+		t.Fatalf("RepairInstallation() error = %v", err)
+	}
+	if downloads != 1 || len(result.Repaired) != 1 || result.Repaired[0] != localSymlinkPath {
+		t.Errorf("Expected only the symlink to be repaired, got %+v after %d downloads", result, downloads)
+	}
+
+	// A corrupted binary is downloaded and installed again
+	binaryPath := fileUtils.GetVersionedBinaryPath(fileConfig, "v1.2.0")
+	os.WriteFile(binaryPath, []byte("corrupted"), 0755)
+	if _, err := release.RepairInstallation(); err != nil {
+		t.Fatalf("RepairInstallation() error = %v", err)
+	}
+	if downloads != 2 || release.Force {
+		t.Errorf("Expected a forced reinstall without changing Force, got %d downloads", downloads)
+	}
+	if err := fileUtils.CheckInstalledVersion(fileConfig, "v1.2.0"); err != nil {
+		t.Errorf("CheckInstalledVersion() after repair error = %v", err)
+	}
+}
@@ -243,6 +243,7 @@ func TestHTTPClientResilience(t *testing.T) {
 
 	// Test rate limiting
 	t.Run("RateLimiting", func(t *testing.T) {
+		realRetryTimers(t)
 		attempts := 0
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			attempts++
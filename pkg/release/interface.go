@@ -11,3 +11,18 @@ type Release interface {
 	GetInstalledBinaryPath() (string, error)                    // Returns the preferred path to the installed binary
 	GetInstallationInfo() (*fileUtils.InstallationInfo, error) // Returns comprehensive installation information
 }
+
+// VersionReporter is implemented by providers that report the resolved latest version and the
+// version the local symlink currently points to, which is what update checks compare
+type VersionReporter interface {
+	LatestVersion() string             // Version resolved by the last GetLatestRelease call
+	InstalledVersion() (string, error) // Currently active installed version
+}
+
+// VersionedRelease is implemented by providers that can download and install a specific version,
+// which orchestration code uses to pin versions and roll back independently of the provider
+type VersionedRelease interface {
+	GetRelease(version string) error      // Fetches the release tagged version
+	DownloadRelease(version string) error // Downloads the release tagged version
+	InstallRelease(version string) error  // Installs the release tagged version, downloading it if needed
+}
@@ -0,0 +1,150 @@
+package release
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// writeTestTarGz creates a .tar.gz archive containing the given files
+func writeTestTarGz(t *testing.T, archivePath string, files map[string]string) {
+	t.Helper()
+	file, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatalf("Failed to create archive: %v", err)
+	}
+	defer file.Close()
+
+	gzipWriter := gzip.NewWriter(file)
+	defer gzipWriter.Close()
+	tarWriter := tar.NewWriter(gzipWriter)
+	defer tarWriter.Close()
+
+	for name, content := range files {
+		header := &tar.Header{Name: name, Mode: 0755, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			t.Fatalf("Failed to write tar header: %v", err)
+		}
+		if _, err := tarWriter.Write([]byte(content)); err != nil {
+			t.Fatalf("Failed to write tar content: %v", err)
+		}
+	}
+}
+
+func TestLocalRelease_InstallArchive(t *testing.T) {
+	tempDir := t.TempDir()
+	archivePath := filepath.Join(tempDir, "myapp.tar.gz")
+	writeTestTarGz(t, archivePath, map[string]string{"myapp": "#!/bin/sh\necho hi\n"})
+
+	config := fileUtils.FileConfig{
+		VersionedDirectoryName: "versions",
+		SourceBinaryName:       "myapp",
+		BinaryName:             "myapp",
+		BaseBinaryDirectory:    filepath.Join(tempDir, "bin"),
+		CreateLocalSymlink:     true,
+	}
+	if err := os.MkdirAll(config.BaseBinaryDirectory, 0755); err != nil {
+		t.Fatalf("Failed to create base dir: %v", err)
+	}
+
+	var r Release = NewLocalRelease(archivePath, "v1.0.0", config)
+	if err := r.DownloadLatestRelease(); err != nil {
+		t.Fatalf("DownloadLatestRelease failed: %v", err)
+	}
+	if err := r.InstallLatestRelease(); err != nil {
+		t.Fatalf("InstallLatestRelease failed: %v", err)
+	}
+
+	info, err := r.GetInstallationInfo()
+	if err != nil {
+		t.Fatalf("GetInstallationInfo failed: %v", err)
+	}
+	if info.SymlinkStatus != "created" {
+		t.Errorf("Expected symlink status 'created', got %s", info.SymlinkStatus)
+	}
+	if info.Version != "v1.0.0" {
+		t.Errorf("Expected version v1.0.0, got %s", info.Version)
+	}
+}
+
+func TestLocalRelease_BinaryPathUsesCDNArchMapping(t *testing.T) {
+	tempDir := t.TempDir()
+	archivePath := filepath.Join(tempDir, "tool.tar.gz")
+	writeTestTarGz(t, archivePath, map[string]string{runtime.GOOS + "-mapped/tool": "binary"})
+	config := fileUtils.FileConfig{
+		VersionedDirectoryName: "versions",
+		BinaryName:             "tool",
+		BaseBinaryDirectory:    filepath.Join(tempDir, "bin"),
+		CreateLocalSymlink:     true,
+	}
+
+	local := NewLocalRelease(archivePath, "1.0.0", config)
+	local.AssetMatchingConfig.CDNArchMapping = map[string]string{runtime.GOARCH: "mapped"}
+	local.AssetMatchingConfig.ExtractionConfig = &ExtractionConfig{BinaryPath: "{os}-{arch}/tool"}
+	if err := local.InstallLatestRelease(); err != nil {
+		t.Fatalf("InstallLatestRelease() error = %v", err)
+	}
+	if local.AssetMatchingConfig.ExtractionConfig.ArchMapping != nil {
+		t.Error("Expected the configured ExtractionConfig not to be modified")
+	}
+
+	// An explicit ArchMapping takes precedence over the CDN mapping
+	local.AssetMatchingConfig.ExtractionConfig.ArchMapping = map[string]string{runtime.GOARCH: "other"}
+	if err := local.InstallFromFile(archivePath, "1.0.1"); err == nil {
+		t.Error("Expected the binary to be looked up in the explicitly mapped directory")
+	}
+}
+
+func TestLocalRelease_InstallDirectBinary(t *testing.T) {
+	tempDir := t.TempDir()
+	binaryPath := filepath.Join(tempDir, "k0s-v1.30.0-amd64")
+	if err := os.WriteFile(binaryPath, []byte("binary"), 0644); err != nil {
+		t.Fatalf("Failed to write binary: %v", err)
+	}
+
+	config := fileUtils.FileConfig{
+		BinaryName:              "k0s",
+		ProjectName:             "k0s",
+		BaseBinaryDirectory:     tempDir,
+		UseVersionsSubdirectory: true,
+		CreateLocalSymlink:      true,
+	}
+
+	// The artifact has no archive extension, so it is installed as a direct binary
+	githubRelease := NewGithubRelease("k0sproject/k0s", config)
+	if err := githubRelease.InstallFromFile(binaryPath, "v1.30.0"); err != nil {
+		t.Fatalf("InstallFromFile failed: %v", err)
+	}
+	if githubRelease.Version != "v1.30.0" {
+		t.Errorf("Expected version to be set to v1.30.0, got %s", githubRelease.Version)
+	}
+
+	installed := filepath.Join(tempDir, "versions", "k0s", "v1.30.0", "k0s")
+	info, err := os.Stat(installed)
+	if err != nil {
+		t.Fatalf("Expected binary at %s: %v", installed, err)
+	}
+	if info.Mode().Perm()&0100 == 0 {
+		t.Errorf("Expected installed binary to be executable, got mode %v", info.Mode())
+	}
+}
+
+func TestLocalRelease_Validation(t *testing.T) {
+	config := fileUtils.FileConfig{BinaryName: "myapp", BaseBinaryDirectory: t.TempDir()}
+
+	if err := NewLocalRelease("/nonexistent/myapp.tar.gz", "v1.0.0", config).GetLatestRelease(); err == nil {
+		t.Error("Expected error for missing artifact")
+	}
+
+	artifact := filepath.Join(t.TempDir(), "myapp")
+	if err := os.WriteFile(artifact, []byte("binary"), 0755); err != nil {
+		t.Fatalf("Failed to write artifact: %v", err)
+	}
+	if err := NewLocalRelease(artifact, "", config).GetLatestRelease(); err == nil {
+		t.Error("Expected error for missing version")
+	}
+}
@@ -0,0 +1,288 @@
+package release
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// LockfileVersion is the format version written to lockfiles
+const LockfileVersion = 1
+
+// Lockfile pins every tool of a tool set to an exact asset, so CI and developer machines
+// install bit-identical binaries
+type Lockfile struct {
+	Version int                      `json:"version"` // Format version (LockfileVersion)
+	Tools   map[string]LockedRelease `json:"tools"`   // Locked release per tool name
+}
+
+// LockedRelease is the exact asset a tool is pinned to
+type LockedRelease struct {
+	Version   string `json:"version"`    // Release version
+	AssetName string `json:"asset_name"` // Asset file name, used for format detection when installing
+	URL       string `json:"url"`        // URL the asset is downloaded from
+	SHA256    string `json:"sha256"`     // Hex-encoded SHA-256 the download must match
+}
+
+// Lockable is implemented by providers that can resolve a release into a lockfile entry and later
+// install exactly that asset again
+type Lockable interface {
+	LockLatestRelease() (*LockedRelease, error)       // Resolves and downloads the latest release and describes the downloaded asset
+	InstallLockedRelease(locked LockedRelease) error  // Downloads the locked URL, verifies its checksum and installs it
+	LockedReleaseInstalled(locked LockedRelease) bool // Reports whether exactly the locked asset is installed and active
+}
+
+// NewLockfile returns an empty lockfile
+func NewLockfile() *Lockfile {
+	return &Lockfile{Version: LockfileVersion, Tools: make(map[string]LockedRelease)}
+}
+
+// ReadLockfile loads a lockfile
+func ReadLockfile(path string) (*Lockfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read lockfile: %v", err)
+	}
+	return parseLockfile(data, path)
+}
+
+// ReadSignedLockfile loads a lockfile after verifying its minisign signature (path + ".minisig"),
+// refusing lockfiles that were changed after signing
+func ReadSignedLockfile(path string, key *fileUtils.MinisignPublicKey) (*Lockfile, error) {
+	data, err := fileUtils.ReadVerifiedFile(path, key)
+	if err != nil {
+		return nil, err
+	}
+	return parseLockfile(data, path)
+}
+
+// parseLockfile decodes and validates the lockfile read from path
+func parseLockfile(data []byte, path string) (*Lockfile, error) {
+	lockfile := NewLockfile()
+	if err := json.Unmarshal(data, lockfile); err != nil {
+		return nil, fmt.Errorf("failed to parse lockfile %s: %v", path, err)
+	}
+	if lockfile.Version != LockfileVersion {
+		return nil, fmt.Errorf("unsupported lockfile version %d in %s", lockfile.Version, path)
+	}
+	if lockfile.Tools == nil {
+		lockfile.Tools = make(map[string]LockedRelease)
+	}
+	for name, locked := range lockfile.Tools {
+		if err := locked.validate(); err != nil {
+			return nil, fmt.Errorf("invalid lockfile entry %s: %v", name, err)
+		}
+	}
+	return lockfile, nil
+}
+
+// Write atomically replaces the lockfile at path. Tools are written in name order, so
+// regenerating an unchanged tool set produces an identical file.
+func (l *Lockfile) Write(path string) error {
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode lockfile: %v", err)
+	}
+	tempPath := path + ".tmp"
+	if err := os.WriteFile(tempPath, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write lockfile: %v", err)
+	}
+	if err := os.Rename(tempPath, path); err != nil {
+		os.Remove(tempPath)
+		return fmt.Errorf("failed to write lockfile: %v", err)
+	}
+	return nil
+}
+
+// WriteSigned writes the lockfile and its minisign signature (path + ".minisig")
+func (l *Lockfile) WriteSigned(path string, key *fileUtils.MinisignPrivateKey) error {
+	if err := l.Write(path); err != nil {
+		return err
+	}
+	return fileUtils.SignFile(path, key)
+}
+
+// Names returns the locked tool names in sorted order
+func (l *Lockfile) Names() []string {
+	names := make([]string, 0, len(l.Tools))
+	for name := range l.Tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validate checks that the entry can be installed verbatim
+func (l LockedRelease) validate() error {
+	if l.Version == "" || l.URL == "" {
+		return fmt.Errorf("version and url are required")
+	}
+	if len(l.SHA256) != 64 || strings.Trim(strings.ToLower(l.SHA256), "0123456789abcdef") != "" {
+		return fmt.Errorf("sha256 must be a hex-encoded SHA-256 digest, got %q", l.SHA256)
+	}
+	return nil
+}
+
+// fileName returns the name the locked asset is staged under
+func (l LockedRelease) fileName() string {
+	if l.AssetName != "" {
+		return l.AssetName
+	}
+	if parsed, err := url.Parse(l.URL); err == nil {
+		return path.Base(parsed.Path)
+	}
+	return ""
+}
+
+// newLockedRelease turns a completed download into a lockfile entry
+func newLockedRelease(result *DownloadResult) (*LockedRelease, error) {
+	switch {
+	case result.DeltaApplied:
+		return nil, fmt.Errorf("cannot lock %s: the binary was produced by a delta patch; disable delta updates to lock it", result.Version)
+	case result.Streamed:
+		return nil, fmt.Errorf("cannot lock %s: streamed downloads are not checksummed; disable StreamExtraction to lock it", result.Version)
+	case result.URL == "":
+		return nil, fmt.Errorf("cannot lock %s: the download URL is unknown", result.Version)
+	}
+	return &LockedRelease{
+		Version:   result.Version,
+		AssetName: result.AssetName,
+		URL:       result.URL,
+		SHA256:    result.Checksum,
+	}, nil
+}
+
+// downloadLocked downloads the locked asset to the provider's staging path for it, claimed for
+// owner, and verifies its checksum. A download that does not match is removed.
+func downloadLocked(config fileUtils.FileConfig, locked LockedRelease, token, provider, owner string) (string, error) {
+	if err := locked.validate(); err != nil {
+		return "", fmt.Errorf("invalid locked release: %v", err)
+	}
+	destination := fileUtils.GetStagingPath(config, provider, locked.Version, locked.fileName())
+	err := stageDownload(destination, owner, func() error {
+		// The download is verified against the checksum and removed if it does not match
+		err := fileUtils.DownloadFileWithConfig(config, locked.URL, destination, token, locked.SHA256)
+		if errors.Is(err, fileUtils.ErrChecksumMismatch) {
+			return fmt.Errorf("locked release %s does not match the lockfile: %w", locked.Version, err)
+		}
+		if err != nil {
+			return fmt.Errorf("error downloading locked release %s: %w", locked.Version, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return destination, nil
+}
+
+// sameHost reports whether two URLs share scheme and host, which decides whether credentials
+// for one may be sent to the other
+func sameHost(a, b string) bool {
+	parsedA, errA := url.Parse(a)
+	parsedB, errB := url.Parse(b)
+	return errA == nil && errB == nil && parsedA.Host != "" &&
+		strings.EqualFold(parsedA.Scheme, parsedB.Scheme) && strings.EqualFold(parsedA.Host, parsedB.Host)
+}
+
+// lockedReleaseInstalled reports whether the locked version is installed unmodified and active and
+// the provenance recorded when it was installed carries the locked checksum. A version of the same
+// name built from a different asset is therefore installed again.
+func lockedReleaseInstalled(config fileUtils.FileConfig, locked LockedRelease) bool {
+	if fileUtils.CheckInstalledVersion(config, locked.Version) != nil {
+		return false
+	}
+	receipt, err := fileUtils.GetInstallReceipt(config)
+	if err != nil {
+		return false
+	}
+	checksum := receipt.Provenance[locked.Version].Checksum
+	return checksum != "" && strings.EqualFold(checksum, locked.SHA256)
+}
+
+// LockedReleaseInstalled reports whether exactly the locked asset is installed and active
+func (g *GithubRelease) LockedReleaseInstalled(locked LockedRelease) bool {
+	return lockedReleaseInstalled(g.Config, locked)
+}
+
+// LockLatestRelease resolves and downloads the latest GitHub release and describes the downloaded asset
+func (g *GithubRelease) LockLatestRelease() (*LockedRelease, error) {
+	// Locking needs the asset's checksum, so it is downloaded even if the version is installed
+	force := g.Force
+	g.Force = true
+	defer func() { g.Force = force }()
+	result, err := g.DownloadLatestReleaseWithResult()
+	if err != nil {
+		return nil, err
+	}
+	return newLockedRelease(result)
+}
+
+// InstallLockedRelease downloads the locked asset, verifies its checksum and installs it. The
+// token is only sent to the GitHub API host, e.g. for assets of private repositories.
+func (g *GithubRelease) InstallLockedRelease(locked LockedRelease) error {
+	token := ""
+	if sameHost(locked.URL, NormalizeGitHubAPIURL(g.APIURL)) {
+		var err error
+		if token, err = g.authToken(); err != nil {
+			return err
+		}
+	}
+	path, err := downloadLocked(g.Config, locked, token, "github", g.stagingOwner())
+	if err != nil {
+		return err
+	}
+	defer fileUtils.ReleaseStagingPath(path, g.stagingOwner())
+	g.AssetName = locked.AssetName
+	if err := g.InstallFromFile(path, locked.Version); err != nil {
+		return err
+	}
+	recordProvenance(g.Config, locked.Version, lockedProvenance(fileUtils.ProvenanceGitHub, g.Repository, locked), "")
+	g.downloadCleanup = cleanupDownload(g.Config, path)
+	return nil
+}
+
+// LockedReleaseInstalled reports whether exactly the locked asset is installed and active
+func (r *GitLabRelease) LockedReleaseInstalled(locked LockedRelease) bool {
+	return lockedReleaseInstalled(r.Config, locked)
+}
+
+// LockLatestRelease resolves and downloads the latest GitLab release and describes the downloaded asset
+func (r *GitLabRelease) LockLatestRelease() (*LockedRelease, error) {
+	// Locking needs the asset's checksum, so it is downloaded even if the version is installed
+	force := r.Force
+	r.Force = true
+	defer func() { r.Force = force }()
+	result, err := r.DownloadLatestReleaseWithResult()
+	if err != nil {
+		return nil, err
+	}
+	return newLockedRelease(result)
+}
+
+// InstallLockedRelease downloads the locked asset, verifies its checksum and installs it. The
+// token is only sent to the GitLab instance, e.g. for assets of private projects.
+func (r *GitLabRelease) InstallLockedRelease(locked LockedRelease) error {
+	token, err := r.assetToken(locked.URL)
+	if err != nil {
+		return err
+	}
+	path, err := downloadLocked(r.Config, locked, token, "gitlab", r.stagingOwner())
+	if err != nil {
+		return err
+	}
+	defer fileUtils.ReleaseStagingPath(path, r.stagingOwner())
+	r.AssetName = locked.AssetName
+	if err := r.InstallFromFile(path, locked.Version); err != nil {
+		return err
+	}
+	recordProvenance(r.Config, locked.Version, lockedProvenance(fileUtils.ProvenanceGitLab, r.ProjectId, locked), "")
+	r.downloadCleanup = cleanupDownload(r.Config, path)
+	return nil
+}
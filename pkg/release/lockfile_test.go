@@ -0,0 +1,132 @@
+package release
+
+import (
+	"errors"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGithubRelease_LockAndInstallLocked(t *testing.T) {
+	tempDir := t.TempDir()
+	archivePath := filepath.Join(tempDir, "archive.tar.gz")
+	writeTestTarGz(t, archivePath, map[string]string{"myapp": "#!/bin/sh\necho v1.0.0\n"})
+	archive, _ := os.ReadFile(archivePath)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive)
+	}))
+	defer server.Close()
+
+	newConfig := func(name string) fileUtils.FileConfig {
+		return fileUtils.FileConfig{
+			BaseBinaryDirectory:    filepath.Join(tempDir, name, "bin"),
+			VersionedDirectoryName: "versions",
+			SourceBinaryName:       "myapp",
+			BinaryName:             "myapp",
+			ProjectName:            "myapp",
+			CreateLocalSymlink:     true,
+			StagingDirectory:       filepath.Join(tempDir, name, "staging"),
+		}
+	}
+	locker := NewGithubRelease("owner/repo", newConfig("ci"))
+	locker.Source = &fakeAssetSource{release: &ReleaseInfo{
+		Version: "v1.0.0",
+		Assets:  []AssetInfo{{Name: "myapp-Linux_x86_64.tar.gz", URL: server.URL + "/myapp-Linux_x86_64.tar.gz"}},
+	}}
+	var _ Lockable = locker
+
+	locked, err := locker.LockLatestRelease()
+	if err != nil {
+		t.Fatalf("LockLatestRelease failed: %v", err)
+	}
+	wantChecksum, _ := fileUtils.FileSHA256(archivePath)
+	if locked.Version != "v1.0.0" || locked.AssetName != "myapp-Linux_x86_64.tar.gz" || locked.SHA256 != wantChecksum {
+		t.Fatalf("Unexpected locked release: %+v", locked)
+	}
+
+	// Round-trip through the lockfile
+	lockfile := NewLockfile()
+	lockfile.Tools["myapp"] = *locked
+	lockPath := filepath.Join(tempDir, "tools.lock.json")
+	if err := lockfile.Write(lockPath); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	read, err := ReadLockfile(lockPath)
+	if err != nil {
+		t.Fatalf("ReadLockfile failed: %v", err)
+	}
+	if read.Tools["myapp"] != *locked {
+		t.Errorf("Expected %+v after reading the lockfile, got %+v", *locked, read.Tools["myapp"])
+	}
+
+	// Installing from the lockfile needs no release metadata
+	developer := NewGithubRelease("owner/repo", newConfig("dev"))
+	developer.Source = &fakeAssetSource{err: os.ErrNotExist}
+	if err := developer.InstallLockedRelease(read.Tools["myapp"]); err != nil {
+		t.Fatalf("InstallLockedRelease failed: %v", err)
+	}
+	if version, err := developer.InstalledVersion(); err != nil || version != "v1.0.0" {
+		t.Errorf("Expected v1.0.0 to be installed, got %s (%v)", version, err)
+	}
+	if !developer.LockedReleaseInstalled(read.Tools["myapp"]) {
+		t.Error("Expected the locked asset to be reported as installed")
+	}
+
+	// A changed asset is rejected
+	tampered := read.Tools["myapp"]
+	tampered.SHA256 = strings.Repeat("0", 64)
+	if developer.LockedReleaseInstalled(tampered) {
+		t.Error("Expected a version installed from a different asset not to match the lockfile")
+	}
+	other := NewGithubRelease("owner/repo", newConfig("other"))
+	if err := other.InstallLockedRelease(tampered); err == nil || !strings.Contains(err.Error(), "does not match the lockfile") {
+		t.Errorf("Expected a checksum mismatch, got %v", err)
+	}
+	if fileUtils.FileExists(fileUtils.GetVersionedBinaryPath(newConfig("other"), "v1.0.0")) {
+		t.Error("Expected nothing to be installed for a mismatching asset")
+	}
+}
+
+func TestReadLockfile_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"unsupported version": `{"version": 2, "tools": {}}`,
+		"missing checksum":    `{"version": 1, "tools": {"a": {"version": "v1", "url": "https://example.com/a"}}}`,
+		"malformed":           `{"version": 1,`,
+	}
+	for name, content := range tests {
+		path := filepath.Join(t.TempDir(), "tools.lock.json")
+		os.WriteFile(path, []byte(content), 0644)
+		if _, err := ReadLockfile(path); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestReadSignedLockfile(t *testing.T) {
+	key, err := fileUtils.GenerateMinisignKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	lockfile := NewLockfile()
+	lockfile.Tools["myapp"] = LockedRelease{Version: "v1.0.0", URL: "https://example.com/myapp.tar.gz", SHA256: strings.Repeat("a", 64)}
+	path := filepath.Join(t.TempDir(), "tools.lock.json")
+	if err := lockfile.WriteSigned(path, key); err != nil {
+		t.Fatalf("WriteSigned failed: %v", err)
+	}
+
+	read, err := ReadSignedLockfile(path, key.Public())
+	if err != nil || read.Tools["myapp"] != lockfile.Tools["myapp"] {
+		t.Fatalf("ReadSignedLockfile() = %+v, %v", read, err)
+	}
+
+	// Pointing a tool at another asset invalidates the signature
+	data, _ := os.ReadFile(path)
+	os.WriteFile(path, []byte(strings.Replace(string(data), "example.com", "evil.example", 1)), 0644)
+	if _, err := ReadSignedLockfile(path, key.Public()); !errors.Is(err, fileUtils.ErrInvalidSignature) {
+		t.Errorf("Expected a tampered lockfile to be refused, got %v", err)
+	}
+}
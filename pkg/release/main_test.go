@@ -0,0 +1,25 @@
+package release
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// TestMain skips the real backoff between retries, which the default jittered configuration
+// would otherwise wait through in every test that hits a failing or unreachable server.
+// Tests that measure the delays use realRetryTimers.
+func TestMain(m *testing.M) {
+	newRetryTimer = func(time.Duration) *time.Timer {
+		return time.NewTimer(0)
+	}
+	os.Exit(m.Run())
+}
+
+// realRetryTimers makes retries wait for their actual delay until the test ends
+func realRetryTimers(t *testing.T) {
+	t.Helper()
+	fake := newRetryTimer
+	newRetryTimer = time.NewTimer
+	t.Cleanup(func() { newRetryTimer = fake })
+}
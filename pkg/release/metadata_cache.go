@@ -0,0 +1,102 @@
+package release
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"net/http"
+	"path/filepath"
+	"time"
+)
+
+// MetadataCache persists release API responses together with their ETag and Last-Modified
+// validators so that repeated update checks can use conditional requests. A 304 Not Modified
+// response does not count against GitHub's rate limit.
+type MetadataCache struct {
+	Directory string // Directory where cached responses are stored
+}
+
+// metadataCacheEntry is the on-disk representation of a cached API response
+type metadataCacheEntry struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag"`
+	LastModified string    `json:"last_modified"`
+	Body         []byte    `json:"body"`
+	StoredAt     time.Time `json:"stored_at"`
+}
+
+// NewMetadataCache creates a metadata cache in the given directory.
+// An empty directory defaults to a "metadata" folder inside fileUtils.DefaultCacheDirectory().
+func NewMetadataCache(directory string) *MetadataCache {
+	if directory == "" {
+		directory = filepath.Join(fileUtils.DefaultCacheDirectory(), "metadata")
+	}
+	return &MetadataCache{Directory: directory}
+}
+
+// entryPath returns the file used to cache the response for the given URL
+func (c *MetadataCache) entryPath(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(c.Directory, hex.EncodeToString(sum[:])+".json")
+}
+
+// load returns the cached entry for a URL, or nil if none is available
+func (c *MetadataCache) load(url string) *metadataCacheEntry {
+	if c == nil {
+		return nil
+	}
+	data, err := fsys().ReadFile(c.entryPath(url))
+	if err != nil {
+		return nil
+	}
+	var entry metadataCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != url {
+		return nil
+	}
+	return &entry
+}
+
+// store saves a successful response body with its validators. Responses without
+// an ETag or Last-Modified header cannot be revalidated and are not stored.
+func (c *MetadataCache) store(url string, header http.Header, body []byte) error {
+	if c == nil {
+		return nil
+	}
+	entry := metadataCacheEntry{
+		URL:          url,
+		ETag:         header.Get("ETag"),
+		LastModified: header.Get("Last-Modified"),
+		Body:         body,
+		StoredAt:     time.Now().UTC(),
+	}
+	if entry.ETag == "" && entry.LastModified == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode metadata cache entry: %w", err)
+	}
+	// Responses of private repositories are only readable by the current user
+	if err := fsys().MkdirAll(c.Directory, 0700); err != nil {
+		return fmt.Errorf("failed to create metadata cache directory: %w", err)
+	}
+	return fsys().WriteFile(c.entryPath(url), data, 0600)
+}
+
+// conditionalHeaders returns If-None-Match / If-Modified-Since headers for a cached entry
+func (e *metadataCacheEntry) conditionalHeaders() map[string]string {
+	headers := make(map[string]string)
+	if e == nil {
+		return headers
+	}
+	if e.ETag != "" {
+		headers["If-None-Match"] = e.ETag
+	}
+	if e.LastModified != "" {
+		headers["If-Modified-Since"] = e.LastModified
+	}
+	return headers
+}
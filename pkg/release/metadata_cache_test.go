@@ -0,0 +1,134 @@
+package release
+
+import (
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/filesystem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"testing"
+)
+
+// newConditionalServer returns a server that answers 304 when the client presents the current ETag
+func newConditionalServer(body string, fullResponses *int) *httptest.Server {
+	const etag = `"release-etag-1"`
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		*fullResponses++
+		w.Header().Set("ETag", etag)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(body))
+	}))
+}
+
+func TestGithubRelease_ConditionalRequests(t *testing.T) {
+	fullResponses := 0
+	server := newConditionalServer(`{
+		"tag_name": "v1.0.0",
+		"assets": [{"name": "myapp-Linux_x86_64.tar.gz", "browser_download_url": "https://example.com/myapp-Linux_x86_64.tar.gz"}]
+	}`, &fullResponses)
+	defer server.Close()
+
+	cache := NewMetadataCache(t.TempDir())
+	for i := 0; i < 3; i++ {
+		release := NewGithubRelease("owner/repo", fileUtils.FileConfig{ProjectName: "myapp"})
+		release.BaseURL = server.URL
+		release.MetadataCache = cache
+
+		if err := release.GetLatestRelease(); err != nil {
+			t.Fatalf("GetLatestRelease failed on call %d: %v", i+1, err)
+		}
+		if release.Version != "v1.0.0" {
+			t.Errorf("Expected version v1.0.0, got %s", release.Version)
+		}
+		if release.NotModified != (i > 0) {
+			t.Errorf("Call %d: expected NotModified=%v, got %v", i+1, i > 0, release.NotModified)
+		}
+	}
+
+	if fullResponses != 1 {
+		t.Errorf("Expected 1 full response, got %d", fullResponses)
+	}
+
+	if runtime.GOOS != "windows" {
+		entries, _ := os.ReadDir(cache.Directory)
+		for _, entry := range entries {
+			if info, err := entry.Info(); err != nil || info.Mode().Perm() != 0600 {
+				t.Errorf("Expected cached metadata to be private, got %v (err: %v)", info.Mode(), err)
+			}
+		}
+	}
+}
+
+func TestMetadataCache_FileSystem(t *testing.T) {
+	memFS := filesystem.NewMemFS()
+	filesystem.SetDefault(memFS)
+	defer filesystem.SetDefault(nil)
+
+	cache := NewMetadataCache("/cache/metadata")
+	header := http.Header{"Etag": []string{`"etag"`}}
+	if err := cache.store("https://example.com/releases", header, []byte("body")); err != nil {
+		t.Fatalf("store failed: %v", err)
+	}
+	if _, err := memFS.Stat(cache.entryPath("https://example.com/releases")); err != nil {
+		t.Errorf("Expected the entry on the configured file system: %v", err)
+	}
+	if entry := cache.load("https://example.com/releases"); entry == nil || string(entry.Body) != "body" {
+		t.Errorf("Expected the cached body, got %+v", entry)
+	}
+}
+
+func TestGitLabRelease_ConditionalRequests(t *testing.T) {
+	fullResponses := 0
+	server := newConditionalServer(`[{
+		"tag_name": "v2.0.0",
+		"released_at": "2024-01-01T00:00:00Z",
+		"assets": {"links": [{"name": "myapp-Linux_x86_64.tar.gz", "direct_asset_url": "https://example.com/myapp-Linux_x86_64.tar.gz"}]}
+	}]`, &fullResponses)
+	defer server.Close()
+
+	cache := NewMetadataCache(t.TempDir())
+	for i := 0; i < 2; i++ {
+		release := NewGitlabRelease("123", fileUtils.FileConfig{ProjectName: "myapp"})
+		release.GitLabConfig.BaseURL = server.URL
+		release.MetadataCache = cache
+
+		if err := release.GetLatestRelease(); err != nil {
+			t.Fatalf("GetLatestRelease failed on call %d: %v", i+1, err)
+		}
+		if release.Version != "v2.0.0" {
+			t.Errorf("Expected version v2.0.0, got %s", release.Version)
+		}
+		if release.NotModified != (i > 0) {
+			t.Errorf("Call %d: expected NotModified=%v, got %v", i+1, i > 0, release.NotModified)
+		}
+	}
+
+	if fullResponses != 1 {
+		t.Errorf("Expected 1 full response, got %d", fullResponses)
+	}
+}
+
+func TestGithubRelease_NoMetadataCache(t *testing.T) {
+	fullResponses := 0
+	server := newConditionalServer(`{"tag_name": "v1.0.0", "assets": [{"name": "myapp-Linux_x86_64.tar.gz", "browser_download_url": "https://example.com/a"}]}`, &fullResponses)
+	defer server.Close()
+
+	for i := 0; i < 2; i++ {
+		release := NewGithubRelease("owner/repo", fileUtils.FileConfig{ProjectName: "myapp"})
+		release.BaseURL = server.URL
+		if err := release.GetLatestRelease(); err != nil {
+			t.Fatalf("GetLatestRelease failed: %v", err)
+		}
+		if release.NotModified {
+			t.Error("Expected NotModified to be false without a metadata cache")
+		}
+	}
+	if fullResponses != 2 {
+		t.Errorf("Expected 2 full responses without cache, got %d", fullResponses)
+	}
+}
@@ -0,0 +1,179 @@
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MirrorRankingFileName is the file the ranking is persisted to inside the download cache directory
+const MirrorRankingFileName = "mirror-ranking.json"
+
+// Mirror health thresholds
+const (
+	mirrorFailureThreshold = 3                // Consecutive failures that mark a mirror unhealthy
+	mirrorFailureCooldown  = 10 * time.Minute // Time after which an unhealthy mirror is tried again
+	mirrorLatencyWeight    = 0.3              // Weight of the newest sample in the latency average
+)
+
+// MirrorStats records how a mirror has performed
+type MirrorStats struct {
+	Successes           int           `json:"successes"`
+	Failures            int           `json:"failures"`
+	ConsecutiveFailures int           `json:"consecutive_failures"`
+	Latency             time.Duration `json:"latency"`      // Moving average time to response headers
+	LastFailure         time.Time     `json:"last_failure"` // Zero if the mirror never failed
+}
+
+// healthy reports whether the mirror should be preferred over untested mirrors
+func (s MirrorStats) healthy(now time.Time) bool {
+	return s.ConsecutiveFailures < mirrorFailureThreshold || now.Sub(s.LastFailure) > mirrorFailureCooldown
+}
+
+// MirrorRanking orders download mirrors by health and latency so that the fastest healthy mirror
+// is used first. Rankings are shared across downloads within a process and can be persisted.
+type MirrorRanking struct {
+	mu    sync.Mutex
+	path  string // Persistence file; empty keeps the ranking in memory only
+	stats map[string]*MirrorStats
+}
+
+var (
+	processMirrorRanking   = NewMirrorRanking()
+	persistedRankingsMu    sync.Mutex
+	persistedMirrorRanking = map[string]*MirrorRanking{}
+)
+
+// NewMirrorRanking creates an in-memory mirror ranking
+func NewMirrorRanking() *MirrorRanking {
+	return &MirrorRanking{stats: make(map[string]*MirrorStats)}
+}
+
+// LoadMirrorRanking returns the ranking persisted at path, which is saved after every download.
+// A missing or unreadable file starts an empty ranking. Calls with the same path share one ranking.
+func LoadMirrorRanking(path string) *MirrorRanking {
+	persistedRankingsMu.Lock()
+	defer persistedRankingsMu.Unlock()
+	if ranking, ok := persistedMirrorRanking[path]; ok {
+		return ranking
+	}
+
+	ranking := NewMirrorRanking()
+	ranking.path = path
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &ranking.stats); err != nil || ranking.stats == nil {
+			ranking.stats = make(map[string]*MirrorStats)
+		}
+	}
+	persistedMirrorRanking[path] = ranking
+	return ranking
+}
+
+// Record updates a mirror's statistics after a download attempt
+func (r *MirrorRanking) Record(mirror string, latency time.Duration, err error) {
+	r.mu.Lock()
+	stats := r.stats[mirror]
+	if stats == nil {
+		stats = &MirrorStats{}
+		r.stats[mirror] = stats
+	}
+	if err != nil {
+		stats.Failures++
+		stats.ConsecutiveFailures++
+		stats.LastFailure = time.Now()
+	} else {
+		stats.Successes++
+		stats.ConsecutiveFailures = 0
+		if stats.Latency == 0 {
+			stats.Latency = latency
+		} else {
+			stats.Latency = time.Duration(mirrorLatencyWeight*float64(latency) + (1-mirrorLatencyWeight)*float64(stats.Latency))
+		}
+	}
+	r.mu.Unlock()
+
+	if err := r.save(); err != nil {
+		fmt.Printf("Warning: failed to save mirror ranking: %v\n", err)
+	}
+}
+
+// Rank orders mirrors for the next download: healthy mirrors by latency, then untested mirrors in
+// their configured order, then unhealthy mirrors starting with the one that failed longest ago
+func (r *MirrorRanking) Rank(mirrors []string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	group := func(mirror string) int {
+		stats := r.stats[mirror]
+		switch {
+		case stats == nil || (stats.Successes == 0 && stats.ConsecutiveFailures == 0):
+			return 1
+		case !stats.healthy(now):
+			return 2
+		case stats.Successes == 0:
+			return 1 // Failed recently but not often enough to be skipped
+		}
+		return 0
+	}
+
+	ranked := append([]string(nil), mirrors...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		gi, gj := group(ranked[i]), group(ranked[j])
+		if gi != gj {
+			return gi < gj
+		}
+		switch gi {
+		case 0:
+			return r.stats[ranked[i]].Latency < r.stats[ranked[j]].Latency
+		case 2:
+			return r.stats[ranked[i]].LastFailure.Before(r.stats[ranked[j]].LastFailure)
+		}
+		return false
+	})
+	return ranked
+}
+
+// Stats returns a snapshot of the recorded mirror statistics
+func (r *MirrorRanking) Stats() map[string]MirrorStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats := make(map[string]MirrorStats, len(r.stats))
+	for mirror, s := range r.stats {
+		stats[mirror] = *s
+	}
+	return stats
+}
+
+// save writes the ranking to its persistence file, if it has one
+func (r *MirrorRanking) save() error {
+	if r.path == "" {
+		return nil
+	}
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.stats, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+	temp, err := os.CreateTemp(filepath.Dir(r.path), ".mirror-ranking-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), r.path)
+}
@@ -0,0 +1,117 @@
+package release
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMirrorRanking_Rank(t *testing.T) {
+	ranking := NewMirrorRanking()
+	ranking.Record("https://slow/", 300*time.Millisecond, nil)
+	ranking.Record("https://fast/", 50*time.Millisecond, nil)
+	for i := 0; i < mirrorFailureThreshold; i++ {
+		ranking.Record("https://broken/", 0, errors.New("connection refused"))
+	}
+
+	got := ranking.Rank([]string{"https://broken/", "https://untested/", "https://slow/", "https://fast/"})
+	want := []string{"https://fast/", "https://slow/", "https://untested/", "https://broken/"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Rank() = %v, want %v", got, want)
+	}
+
+	// An unhealthy mirror is tried again once its cooldown has passed
+	ranking.stats["https://broken/"].LastFailure = time.Now().Add(-2 * mirrorFailureCooldown)
+	ranking.stats["https://broken/"].Successes = 1
+	ranking.stats["https://broken/"].Latency = 10 * time.Millisecond
+	if first := ranking.Rank(want)[0]; first != "https://broken/" {
+		t.Errorf("Expected recovered mirror to rank first, got %s", first)
+	}
+}
+
+func TestMirrorRanking_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), MirrorRankingFileName)
+	ranking := LoadMirrorRanking(path)
+	ranking.Record("https://mirror/", 100*time.Millisecond, nil)
+
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("Expected ranking to be persisted: %v", err)
+	}
+	if LoadMirrorRanking(path) != ranking {
+		t.Error("Expected rankings with the same path to be shared within the process")
+	}
+
+	persistedRankingsMu.Lock()
+	delete(persistedMirrorRanking, path)
+	persistedRankingsMu.Unlock()
+
+	stats := LoadMirrorRanking(path).Stats()["https://mirror/"]
+	if stats.Successes != 1 || stats.Latency != 100*time.Millisecond {
+		t.Errorf("Unexpected persisted stats: %+v", stats)
+	}
+}
+
+func TestCDNDownloader_FallsBackToHealthyMirror(t *testing.T) {
+	brokenRequests := 0
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		brokenRequests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer broken.Close()
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("binary content"))
+	}))
+	defer healthy.Close()
+
+	downloader := NewCDNDownloader(broken.URL+"/", "tool-{version}-{os}-{arch}")
+	downloader.HTTPClient = NewRetryableHTTPClient(HTTPClientConfig{MaxRetries: 0, Timeout: 5 * time.Second})
+	downloader.Mirrors = []string{healthy.URL + "/"}
+	downloader.Ranking = NewMirrorRanking()
+
+	destination := filepath.Join(t.TempDir(), "tool")
+	if err := downloader.Download("v1.0.0", destination); err != nil {
+		t.Fatalf("Expected download to fall back to the healthy mirror, got: %v", err)
+	}
+	content, _ := os.ReadFile(destination)
+	if string(content) != "binary content" {
+		t.Errorf("Unexpected downloaded content: %q", content)
+	}
+
+	// The healthy mirror is preferred for the next download
+	if err := downloader.Download("v1.0.0", destination); err != nil {
+		t.Fatalf("Second download failed: %v", err)
+	}
+	if brokenRequests != 1 {
+		t.Errorf("Expected the failing mirror to be skipped after ranking, got %d requests", brokenRequests)
+	}
+
+	stats := downloader.Ranking.Stats()
+	if stats[broken.URL+"/"].Failures != 1 || stats[healthy.URL+"/"].Successes != 2 {
+		t.Errorf("Unexpected mirror stats: %+v", stats)
+	}
+}
+
+func TestCDNDownloader_AllMirrorsFail(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	downloader := NewCDNDownloader(server.URL+"/a/", "tool-{version}")
+	downloader.HTTPClient = NewRetryableHTTPClient(HTTPClientConfig{MaxRetries: 0, Timeout: 5 * time.Second})
+	downloader.Mirrors = []string{server.URL + "/b/"}
+	downloader.Ranking = NewMirrorRanking()
+
+	err := downloader.Download("v1.0.0", filepath.Join(t.TempDir(), "tool"))
+	if err == nil {
+		t.Fatal("Expected an error when every mirror fails")
+	}
+	if len(downloader.Ranking.Stats()) != 2 {
+		t.Errorf("Expected both mirrors to be recorded, got %+v", downloader.Ranking.Stats())
+	}
+}
@@ -0,0 +1,36 @@
+package release
+
+import (
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+)
+
+var (
+	// ErrNoMatchingRelease matches (via errors.Is) failures to find a release for the requested
+	// version, version constraint or selection policy
+	ErrNoMatchingRelease = fileUtils.NewError(fileUtils.ErrorCodeNoMatch, "no matching release")
+
+	// ErrNoMatchingAsset matches (via errors.Is) failures to find an asset of a release for the
+	// current platform
+	ErrNoMatchingAsset = fileUtils.NewError(fileUtils.ErrorCodeNoMatch, "no matching asset")
+)
+
+// noMatchError keeps its own message while matching one of the sentinels above, whose
+// ErrorCodeNoMatch it reports
+type noMatchError struct {
+	message  string
+	sentinel error
+}
+
+// noMatchErrorf formats a message for an error matching sentinel
+func noMatchErrorf(sentinel error, format string, args ...any) error {
+	return &noMatchError{message: fmt.Sprintf(format, args...), sentinel: sentinel}
+}
+
+func (e *noMatchError) Error() string {
+	return e.message
+}
+
+func (e *noMatchError) Unwrap() error {
+	return e.sentinel
+}
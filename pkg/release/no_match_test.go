@@ -0,0 +1,125 @@
+package release
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGithubRelease_ErrorCodes(t *testing.T) {
+	asset := fmt.Sprintf("myapp_%s_%s", runtime.GOOS, runtime.GOARCH)
+	var status int
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/download/"+asset {
+			w.Write([]byte("binary"))
+			return
+		}
+		if status != 0 {
+			w.WriteHeader(status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+	release := func(name, digest string) string {
+		return fmt.Sprintf(`{"tag_name": "v1.0.0", "assets": [{"id": 1, "name": %q, "browser_download_url": "%s/download/%s", "digest": %q}]}`,
+			name, server.URL, name, digest)
+	}
+
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		constraint string
+		want       fileUtils.ErrorCode
+		sentinel   error
+	}{
+		{"unauthorized", http.StatusUnauthorized, "", "", fileUtils.ErrorCodeAuth, nil},
+		{"not found", http.StatusNotFound, "", "", fileUtils.ErrorCodeNoMatch, nil},
+		{"server error", http.StatusBadGateway, "", "", fileUtils.ErrorCodeNetwork, nil},
+		{"no asset", 0, release("myapp_plan9_mips", ""), "", fileUtils.ErrorCodeNoMatch, ErrNoMatchingAsset},
+		{"no release", 0, "[" + release(asset, "") + "]", "^2.0.0", fileUtils.ErrorCodeNoMatch, ErrNoMatchingRelease},
+		{"digest", 0, release(asset, "sha256:"+strings.Repeat("0", 64)), "", fileUtils.ErrorCodeChecksum, fileUtils.ErrChecksumMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body = tt.status, tt.body
+			r := NewGithubRelease("owner/repo", fileUtils.FileConfig{
+				BinaryName:          "myapp",
+				IsDirectBinary:      true,
+				BaseBinaryDirectory: t.TempDir(),
+			})
+			r.BaseURL = server.URL
+			r.VersionConstraint = tt.constraint
+
+			err := r.DownloadLatestRelease()
+			if got := fileUtils.ErrorCodeOf(err); got != tt.want {
+				t.Errorf("ErrorCodeOf(%v) = %q, want %q", err, got, tt.want)
+			}
+			if tt.sentinel != nil && !errors.Is(err, tt.sentinel) {
+				t.Errorf("Expected %v to match %v", err, tt.sentinel)
+			}
+		})
+	}
+}
+
+func TestCDNDownloader_ErrorCodes(t *testing.T) {
+	var status int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status == 0 {
+			<-r.Context().Done()
+			return
+		}
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	tests := []struct {
+		name    string
+		baseURL string
+		status  int
+		timeout time.Duration
+		want    fileUtils.ErrorCode
+	}{
+		{"unauthorized", server.URL, http.StatusUnauthorized, 0, fileUtils.ErrorCodeAuth},
+		{"forbidden", server.URL, http.StatusForbidden, 0, fileUtils.ErrorCodeAuth},
+		{"not found", server.URL, http.StatusNotFound, 0, fileUtils.ErrorCodeNoMatch},
+		{"unreachable", closed.URL, 0, 0, fileUtils.ErrorCodeNetwork},
+		{"deadline", server.URL, 0, 50 * time.Millisecond, fileUtils.ErrorCodeNetwork},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status = tt.status
+			httpConfig := DefaultCDNHTTPClientConfig()
+			httpConfig.MaxRetries = 0
+			assetConfig := DefaultAssetMatchingConfig()
+			assetConfig.CDNBaseURL = tt.baseURL + "/"
+			assetConfig.CDNPattern = "tool-{version}-{os}-{arch}"
+			assetConfig.CDNHTTPConfig = &httpConfig
+			fileConfig := fileUtils.FileConfig{}
+			if tt.timeout > 0 {
+				ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+				defer cancel()
+				fileConfig = fileConfig.WithContext(ctx)
+			}
+
+			downloader := newConfiguredCDNDownloader(assetConfig, fileConfig)
+			err := downloader.Download("v1.0.0", filepath.Join(t.TempDir(), "tool"))
+			if got := fileUtils.ErrorCodeOf(err); got != tt.want {
+				t.Errorf("ErrorCodeOf(%v) = %q, want %q", err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PresetFunc builds a fresh asset matching configuration for a preset
+type PresetFunc func() AssetMatchingConfig
+
+// PresetRegistry holds named asset matching presets. Applications can register their own
+// presets at runtime or load them from a file, similar to adding a homebrew "tap".
+type PresetRegistry struct {
+	mu      sync.RWMutex
+	presets map[string]PresetFunc
+}
+
+// DefaultPresetRegistry is the registry used by GetPresetConfig, pre-populated with the built-in presets
+var DefaultPresetRegistry = NewBuiltinPresetRegistry()
+
+// NewPresetRegistry creates an empty preset registry
+func NewPresetRegistry() *PresetRegistry {
+	return &PresetRegistry{presets: make(map[string]PresetFunc)}
+}
+
+// NewBuiltinPresetRegistry creates a registry containing the built-in presets
+func NewBuiltinPresetRegistry() *PresetRegistry {
+	registry := NewPresetRegistry()
+	builtins := map[string]PresetFunc{
+		"helm":          GetHelmCDNConfig,
+		"kubectl":       GetKubectlCDNConfig,
+		"k0s":           GetK0sConfig,
+		"terraform":     GetTerraformConfig,
+		"docker":        GetDockerConfig,
+		"jq":            GetJqConfig,
+		"gh":            GetGhConfig,
+		"golangci-lint": GetGolangciLintConfig,
+		"kustomize":     GetKustomizeConfig,
+		"kind":          GetKindConfig,
+		"minikube":      GetMinikubeConfig,
+	}
+	for name, preset := range builtins {
+		registry.presets[name] = preset
+	}
+	return registry
+}
+
+// Register adds or replaces a preset. Names are case-insensitive.
+func (r *PresetRegistry) Register(name string, preset PresetFunc) error {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if key == "" {
+		return fmt.Errorf("preset name cannot be empty")
+	}
+	if preset == nil {
+		return fmt.Errorf("preset %s has no configuration function", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.presets[key] = preset
+	return nil
+}
+
+// RegisterConfig adds or replaces a preset from a fixed configuration.
+// Each Get returns an independent copy, so callers may modify the result freely.
+func (r *PresetRegistry) RegisterConfig(name string, config AssetMatchingConfig) error {
+	config = cloneAssetMatchingConfig(config)
+	return r.Register(name, func() AssetMatchingConfig {
+		return cloneAssetMatchingConfig(config)
+	})
+}
+
+// cloneAssetMatchingConfig copies config with its own slices, maps and pointed-to settings.
+// Functions such as CDNRequestSigner are shared.
+func cloneAssetMatchingConfig(config AssetMatchingConfig) AssetMatchingConfig {
+	cloneAliases := func(aliases map[string][]string) map[string][]string {
+		if aliases == nil {
+			return nil
+		}
+		cloned := make(map[string][]string, len(aliases))
+		for key, values := range aliases {
+			cloned[key] = slices.Clone(values)
+		}
+		return cloned
+	}
+
+	config.CustomPatterns = slices.Clone(config.CustomPatterns)
+	config.AssetNameTemplates = slices.Clone(config.AssetNameTemplates)
+	config.AssetLabelTemplates = slices.Clone(config.AssetLabelTemplates)
+	config.ArchitectureAliases = cloneAliases(config.ArchitectureAliases)
+	config.OSAliases = cloneAliases(config.OSAliases)
+	config.ExtraArchitectureAliases = cloneAliases(config.ExtraArchitectureAliases)
+	config.ExtraOSAliases = cloneAliases(config.ExtraOSAliases)
+	config.RemovedArchitectureAliases = cloneAliases(config.RemovedArchitectureAliases)
+	config.RemovedOSAliases = cloneAliases(config.RemovedOSAliases)
+	config.FileExtensions = slices.Clone(config.FileExtensions)
+	config.ExcludePatterns = slices.Clone(config.ExcludePatterns)
+	config.PriorityPatterns = slices.Clone(config.PriorityPatterns)
+	config.CDNMirrors = slices.Clone(config.CDNMirrors)
+	config.CDNArchMapping = maps.Clone(config.CDNArchMapping)
+	config.LinkTypes = slices.Clone(config.LinkTypes)
+	if config.CDNHTTPConfig != nil {
+		httpConfig := *config.CDNHTTPConfig
+		config.CDNHTTPConfig = &httpConfig
+	}
+	if config.CDNVersionDiscovery != nil {
+		discovery := *config.CDNVersionDiscovery
+		config.CDNVersionDiscovery = &discovery
+	}
+	if config.ExtractionConfig != nil {
+		extraction := *config.ExtractionConfig
+		extraction.ArchMapping = maps.Clone(extraction.ArchMapping)
+		extraction.Include = slices.Clone(extraction.Include)
+		extraction.Exclude = slices.Clone(extraction.Exclude)
+		config.ExtractionConfig = &extraction
+	}
+	return config
+}
+
+// Get returns the configuration for the named preset
+func (r *PresetRegistry) Get(name string) (AssetMatchingConfig, error) {
+	r.mu.RLock()
+	preset, exists := r.presets[strings.ToLower(strings.TrimSpace(name))]
+	r.mu.RUnlock()
+
+	if !exists {
+		return AssetMatchingConfig{}, fmt.Errorf("no preset configuration available for binary: %s", name)
+	}
+	return preset(), nil
+}
+
+// Names returns the sorted names of all registered presets
+func (r *PresetRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.presets))
+	for name := range r.presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadFile registers presets from a JSON or YAML (.yaml, .yml) file mapping preset names to asset
+// matching configurations, using the JSON field names in both formats.
+// Fields omitted in a preset keep the values of DefaultAssetMatchingConfig.
+func (r *PresetRegistry) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read preset file: %v", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		// Convert to JSON so both formats share the field names and defaults of AssetMatchingConfig
+		var document map[string]interface{}
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return fmt.Errorf("failed to parse preset file %s: %v", path, err)
+		}
+		if data, err = json.Marshal(document); err != nil {
+			return fmt.Errorf("failed to parse preset file %s: %v", path, err)
+		}
+	}
+
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("failed to parse preset file %s: %v", path, err)
+	}
+
+	configs := make(map[string]AssetMatchingConfig, len(raw))
+	for name, message := range raw {
+		config := DefaultAssetMatchingConfig()
+		if err := json.Unmarshal(message, &config); err != nil {
+			return fmt.Errorf("failed to parse preset %s in %s: %v", name, path, err)
+		}
+		configs[name] = config
+	}
+
+	// Register only after the whole file parsed so a bad file leaves the registry untouched
+	for name, config := range configs {
+		if err := r.RegisterConfig(name, config); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetJqConfig returns configuration for jq, which ships direct binaries (e.g. jq-linux-amd64, jq-macos-arm64)
+func GetJqConfig() AssetMatchingConfig {
+	config := DefaultAssetMatchingConfig()
+	config.Strategy = FlexibleStrategy
+	config.ProjectName = "jq"
+	config.IsDirectBinary = true
+	config.FileExtensions = []string{}
+	return config
+}
+
+// GetGhConfig returns configuration for the GitHub CLI (e.g. gh_2.40.0_linux_amd64.tar.gz)
+func GetGhConfig() AssetMatchingConfig {
+	config := DefaultAssetMatchingConfig()
+	config.Strategy = FlexibleStrategy
+	config.ProjectName = "gh"
+	config.FileExtensions = []string{".tar.gz", ".zip"}
+	config.ExcludePatterns = append(config.ExcludePatterns,
+		"\\.deb$", // Exclude Debian packages
+		"\\.rpm$", // Exclude RPM packages
+		"\\.msi$", // Exclude Windows installers
+	)
+	return config
+}
+
+// GetGolangciLintConfig returns configuration for golangci-lint (e.g. golangci-lint-1.55.2-linux-amd64.tar.gz)
+func GetGolangciLintConfig() AssetMatchingConfig {
+	config := DefaultAssetMatchingConfig()
+	config.Strategy = FlexibleStrategy
+	config.ProjectName = "golangci-lint"
+	config.FileExtensions = []string{".tar.gz", ".zip"}
+	config.ExcludePatterns = append(config.ExcludePatterns,
+		"\\.deb$",
+		"\\.rpm$",
+		"checksums",
+	)
+	return config
+}
+
+// GetKustomizeConfig returns configuration for kustomize (e.g. kustomize_v5.3.0_linux_amd64.tar.gz)
+func GetKustomizeConfig() AssetMatchingConfig {
+	config := DefaultAssetMatchingConfig()
+	config.Strategy = FlexibleStrategy
+	config.ProjectName = "kustomize"
+	config.FileExtensions = []string{".tar.gz"}
+	config.ExcludePatterns = append(config.ExcludePatterns, "checksums")
+	return config
+}
+
+// GetKindConfig returns configuration for kind, which ships direct binaries (e.g. kind-linux-amd64)
+func GetKindConfig() AssetMatchingConfig {
+	config := DefaultAssetMatchingConfig()
+	config.Strategy = FlexibleStrategy
+	config.ProjectName = "kind"
+	config.IsDirectBinary = true
+	config.FileExtensions = []string{}
+	config.ExcludePatterns = append(config.ExcludePatterns, "\\.sha256sum$")
+	return config
+}
+
+// GetMinikubeConfig returns configuration for minikube's direct binaries (e.g. minikube-linux-amd64)
+func GetMinikubeConfig() AssetMatchingConfig {
+	config := DefaultAssetMatchingConfig()
+	config.Strategy = FlexibleStrategy
+	config.ProjectName = "minikube"
+	config.IsDirectBinary = true
+	config.FileExtensions = []string{}
+	config.ExcludePatterns = append(config.ExcludePatterns,
+		"\\.tar\\.gz$", // Exclude archived variants of the same binary
+		"\\.deb$",
+		"\\.rpm$",
+		"\\.json$",
+	)
+	return config
+}
@@ -0,0 +1,169 @@
+package release
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPresetRegistry_RegisterAndGet(t *testing.T) {
+	registry := NewPresetRegistry()
+
+	custom := DefaultAssetMatchingConfig()
+	custom.ProjectName = "mytool"
+	if err := registry.RegisterConfig("MyTool", custom); err != nil {
+		t.Fatalf("RegisterConfig failed: %v", err)
+	}
+
+	config, err := registry.Get("mytool")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if config.ProjectName != "mytool" {
+		t.Errorf("Expected ProjectName mytool, got %s", config.ProjectName)
+	}
+
+	// Modifying a returned config must not affect the registered preset
+	config.ExcludePatterns[0] = "modified"
+	again, _ := registry.Get("mytool")
+	if again.ExcludePatterns[0] == "modified" {
+		t.Error("Expected registered preset to be independent of returned copies")
+	}
+
+	// Fields that cannot be serialised, such as the request signer, are kept
+	signed := DefaultAssetMatchingConfig()
+	signed.CDNRequestSigner = func(req *http.Request) error {
+		req.Header.Set("X-Signed", "yes")
+		return nil
+	}
+	signed.ExtractionConfig = &ExtractionConfig{Include: []string{"bin/*"}}
+	if err := registry.RegisterConfig("signed", signed); err != nil {
+		t.Fatalf("RegisterConfig failed: %v", err)
+	}
+	signedConfig, _ := registry.Get("signed")
+	if signedConfig.CDNRequestSigner == nil {
+		t.Fatal("Expected the registered preset to keep its request signer")
+	}
+	signedConfig.ExtractionConfig.Include[0] = "modified"
+	if again, _ := registry.Get("signed"); again.ExtractionConfig.Include[0] != "bin/*" {
+		t.Error("Expected the extraction config to be copied")
+	}
+
+	if _, err := registry.Get("unknown"); err == nil {
+		t.Error("Expected error for unknown preset")
+	}
+	if err := registry.Register("", GetJqConfig); err == nil {
+		t.Error("Expected error for empty preset name")
+	}
+}
+
+func TestPresetRegistry_LoadFile(t *testing.T) {
+	tempDir := t.TempDir()
+	presetFile := filepath.Join(tempDir, "presets.json")
+	content := `{
+		"mytool": {"project_name": "mytool", "is_direct_binary": true, "file_extensions": []},
+		"other": {"project_name": "other"}
+	}`
+	if err := os.WriteFile(presetFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write preset file: %v", err)
+	}
+
+	registry := NewPresetRegistry()
+	if err := registry.LoadFile(presetFile); err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+
+	names := registry.Names()
+	if len(names) != 2 || names[0] != "mytool" || names[1] != "other" {
+		t.Errorf("Expected [mytool other], got %v", names)
+	}
+
+	config, err := registry.Get("mytool")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if !config.IsDirectBinary {
+		t.Error("Expected IsDirectBinary from preset file")
+	}
+	if len(config.ExcludePatterns) == 0 {
+		t.Error("Expected omitted fields to keep default exclusion patterns")
+	}
+
+	yamlFile := filepath.Join(tempDir, "presets.yaml")
+	yamlContent := `
+yamltool:
+  project_name: yamltool
+  is_direct_binary: true
+  file_extensions: []
+  cdn_arch_mapping:
+    amd64: x86_64
+`
+	if err := os.WriteFile(yamlFile, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("Failed to write YAML preset file: %v", err)
+	}
+	if err := registry.LoadFile(yamlFile); err != nil {
+		t.Fatalf("LoadFile YAML failed: %v", err)
+	}
+	config, err = registry.Get("yamltool")
+	if err != nil {
+		t.Fatalf("Get YAML preset failed: %v", err)
+	}
+	if !config.IsDirectBinary || config.ProjectName != "yamltool" || config.CDNArchMapping["amd64"] != "x86_64" {
+		t.Errorf("Expected the YAML preset fields, got %+v", config)
+	}
+	if len(config.ExcludePatterns) == 0 {
+		t.Error("Expected omitted YAML fields to keep default exclusion patterns")
+	}
+
+	invalidYAML := filepath.Join(tempDir, "invalid.yml")
+	os.WriteFile(invalidYAML, []byte("broken: [unclosed"), 0644)
+	if err := registry.LoadFile(invalidYAML); err == nil {
+		t.Error("Expected error for invalid YAML preset file")
+	}
+
+	invalidFile := filepath.Join(tempDir, "invalid.json")
+	os.WriteFile(invalidFile, []byte(`{"broken": {"project_name": 42}}`), 0644)
+	if err := registry.LoadFile(invalidFile); err == nil {
+		t.Error("Expected error for invalid preset file")
+	}
+	if _, err := registry.Get("broken"); err == nil {
+		t.Error("Expected invalid preset file to leave the registry untouched")
+	}
+}
+
+func TestBuiltinPresets_AssetSelection(t *testing.T) {
+	testCases := []struct {
+		preset   string
+		assets   []string
+		expected string
+	}{
+		{"jq", []string{"jq-linux-amd64", "jq-linux-arm64", "jq-macos-amd64", "jq-windows-amd64.exe"}, "jq-linux-amd64"},
+		{"gh", []string{"gh_2.40.0_linux_amd64.deb", "gh_2.40.0_linux_amd64.tar.gz", "gh_2.40.0_macOS_amd64.zip"}, "gh_2.40.0_linux_amd64.tar.gz"},
+		{"golangci-lint", []string{"golangci-lint-1.55.2-linux-amd64.rpm", "golangci-lint-1.55.2-linux-amd64.tar.gz", "golangci-lint-1.55.2-checksums.txt"}, "golangci-lint-1.55.2-linux-amd64.tar.gz"},
+		{"kustomize", []string{"kustomize_v5.3.0_darwin_amd64.tar.gz", "kustomize_v5.3.0_linux_amd64.tar.gz", "checksums.txt"}, "kustomize_v5.3.0_linux_amd64.tar.gz"},
+		{"kind", []string{"kind-linux-amd64", "kind-linux-amd64.sha256sum", "kind-darwin-amd64"}, "kind-linux-amd64"},
+		{"minikube", []string{"minikube-linux-amd64.tar.gz", "minikube-linux-amd64", "minikube_1.32.0-0_amd64.deb"}, "minikube-linux-amd64"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.preset, func(t *testing.T) {
+			config, err := GetPresetConfig(tc.preset)
+			if err != nil {
+				t.Fatalf("Expected built-in preset %s, got error: %v", tc.preset, err)
+			}
+
+			matcher := NewAssetMatcher(config)
+			matcher.os = "linux"
+			matcher.arch = "amd64"
+
+			bestMatch, err := matcher.FindBestMatch(tc.assets)
+			if err != nil {
+				t.Fatalf("Expected to find a match, got error: %v", err)
+			}
+			if bestMatch != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, bestMatch)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package release
+
+import (
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"path/filepath"
+	"strings"
+)
+
+// recordProvenance records where the installed version came from in the install receipt. Without
+// a checksum the downloaded file at downloadPath is hashed, if there is one. Failures only produce
+// a warning because the binary itself was installed successfully.
+func recordProvenance(config fileUtils.FileConfig, version string, provenance fileUtils.Provenance, downloadPath string) {
+	if provenance.Checksum == "" && downloadPath != "" {
+		if checksum, err := fileUtils.FileSHA256(downloadPath); err == nil {
+			provenance.Checksum = checksum
+		}
+	}
+	if err := fileUtils.RecordProvenance(config, version, provenance); err != nil {
+		fmt.Printf("Warning: failed to record provenance of %s %s: %v\n", config.BinaryName, version, err)
+	}
+}
+
+// localProvenance describes a pre-staged file installed without a download
+func localProvenance(path string) fileUtils.Provenance {
+	return fileUtils.Provenance{Provider: fileUtils.ProvenanceLocal, AssetName: filepath.Base(path), DownloadURL: path}
+}
+
+// lockedProvenance describes an asset installed from a lockfile entry
+func lockedProvenance(provider, repository string, locked LockedRelease) fileUtils.Provenance {
+	return fileUtils.Provenance{
+		Provider:    provider,
+		Repository:  repository,
+		AssetName:   locked.AssetName,
+		DownloadURL: locked.URL,
+		Checksum:    locked.SHA256,
+	}
+}
+
+// assetProvenance describes the release asset or CDN file selected for installation. The checksum
+// is the digest the provider reported for the asset, if any.
+func assetProvenance(provider, repository string, info *ReleaseInfo, assetName, link, cdnFileName, cdnURL string) fileUtils.Provenance {
+	if cdnFileName != "" {
+		return fileUtils.Provenance{Provider: fileUtils.ProvenanceCDN, Repository: repository, AssetName: cdnFileName, DownloadURL: cdnURL}
+	}
+	provenance := fileUtils.Provenance{Provider: provider, Repository: repository, AssetName: assetName, DownloadURL: link}
+	if info != nil {
+		if asset, ok := info.FindAsset(assetName); ok {
+			if hexDigest, ok := strings.CutPrefix(asset.Digest, "sha256:"); ok {
+				provenance.Checksum = hexDigest
+			}
+		}
+	}
+	return provenance
+}
+
+// provenance describes the download of the release being installed
+func (g *GithubRelease) provenance() fileUtils.Provenance {
+	return assetProvenance(fileUtils.ProvenanceGitHub, g.Repository, g.Info, g.AssetName, g.ReleaseLink, g.cdnFileName, g.downloadedFrom)
+}
+
+// provenance describes the download of the release being installed
+func (r *GitLabRelease) provenance() fileUtils.Provenance {
+	return assetProvenance(fileUtils.ProvenanceGitLab, r.ProjectId, r.Info, r.AssetName, r.ReleaseLink, r.cdnFileName, r.downloadedFrom)
+}
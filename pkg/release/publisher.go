@@ -0,0 +1,174 @@
+package release
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultChecksumFileName is the name of the checksums file attached by publishers
+const DefaultChecksumFileName = "checksums.txt"
+
+// Publisher creates releases and attaches assets to them, the producer side of the providers
+type Publisher interface {
+	Publish(request PublishRequest) (*ReleaseInfo, error) // Creates the release if missing and uploads the assets
+}
+
+// PublishRequest describes a release to create or update and the files to attach to it
+type PublishRequest struct {
+	Version          string   // Tag name of the release, created if missing
+	Name             string   // Release title (defaults to Version)
+	Description      string   // Release notes, used when the release is created
+	Ref              string   // Commit, branch or tag to create a missing tag from (default: the default branch)
+	Assets           []string // Paths of the files to attach, uploaded under their base names
+	Checksums        bool     // Also attach a checksums file with the SHA-256 of each asset in sha256sum format
+	ChecksumFileName string   // Name of the checksums file (default: DefaultChecksumFileName)
+	Replace          bool     // Replace assets with the same name instead of failing
+}
+
+// publishAsset is a file to upload, read from path or, for generated files, held in content
+type publishAsset struct {
+	name    string
+	path    string
+	content []byte
+}
+
+// open returns the asset's content and size
+func (a publishAsset) open() (io.ReadCloser, int64, error) {
+	if a.path == "" {
+		return io.NopCloser(bytes.NewReader(a.content)), int64(len(a.content)), nil
+	}
+	file, err := os.Open(a.path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error opening asset %s: %w", a.path, err)
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, 0, fmt.Errorf("error reading asset %s: %w", a.path, err)
+	}
+	return file, stat.Size(), nil
+}
+
+// prepare validates the request and returns the assets to upload, including the checksums file
+func (p PublishRequest) prepare() ([]publishAsset, error) {
+	if p.Version == "" {
+		return nil, fmt.Errorf("version cannot be empty")
+	}
+
+	var assets []publishAsset
+	var checksums strings.Builder
+	names := make(map[string]bool)
+	for _, path := range p.Assets {
+		name := filepath.Base(path)
+		if names[name] {
+			return nil, fmt.Errorf("duplicate asset name %s", name)
+		}
+		names[name] = true
+
+		sum, err := fileSHA256(path)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&checksums, "%s  %s\n", sum, name)
+		assets = append(assets, publishAsset{name: name, path: path})
+	}
+
+	if p.Checksums {
+		name := p.ChecksumFileName
+		if name == "" {
+			name = DefaultChecksumFileName
+		}
+		if names[name] {
+			return nil, fmt.Errorf("checksums file %s conflicts with an asset of the same name", name)
+		}
+		assets = append(assets, publishAsset{name: name, content: []byte(checksums.String())})
+	}
+	return assets, nil
+}
+
+// releaseName returns the release title, defaulting to the version
+func (p PublishRequest) releaseName() string {
+	if p.Name != "" {
+		return p.Name
+	}
+	return p.Version
+}
+
+// fileSHA256 returns the hex-encoded SHA-256 of a file
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("error opening asset %s: %w", path, err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("error reading asset %s: %w", path, err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// publishRequest sends a publisher API request with the given headers. A non-nil payload is sent
+// as JSON, an io.Reader body as is. Responses with another status than wantStatus fail with an
+// HTTPStatusError; otherwise a non-nil result is decoded from the response.
+func publishRequest(client *http.Client, method, requestURL string, headers map[string]string, body io.Reader,
+	contentLength int64, wantStatus int, result any) error {
+	req, err := http.NewRequest(method, requestURL, body)
+	if err != nil {
+		return fmt.Errorf("error creating HTTP request: %w", err)
+	}
+	if body != nil {
+		req.ContentLength = contentLength
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	fileUtils.SetUserAgent(req)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error making HTTP request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != wantStatus {
+		return newHTTPStatusError(resp)
+	}
+	if result == nil {
+		return nil
+	}
+	data, err := ReadResponseBody(resp)
+	if err != nil {
+		return fmt.Errorf("error reading response body: %w", err)
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("error decoding response: %w", err)
+	}
+	return nil
+}
+
+// jsonBody encodes a request payload as JSON
+func jsonBody(payload any) (io.Reader, int64, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error encoding request: %w", err)
+	}
+	return bytes.NewReader(data), int64(len(data)), nil
+}
+
+// isNotFound reports whether err is an HTTPStatusError for a 404 response
+func isNotFound(err error) bool {
+	var statusErr *HTTPStatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound
+}
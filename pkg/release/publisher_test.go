@@ -0,0 +1,239 @@
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeGitHubReleases is an in-memory GitHub releases API for one repository
+type fakeGitHubReleases struct {
+	mu      sync.Mutex
+	created bool
+	assets  map[string]string // Asset name to content
+	nextID  int
+	ids     map[int]string
+}
+
+func (f *fakeGitHubReleases) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r.Header.Get("Authorization") != "Bearer test-token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	serverURL := "http://" + r.Host + "/api/v3"
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/repos/owner/repo/releases/tags/v1.0.0":
+		if !f.created {
+			http.NotFound(w, r)
+			return
+		}
+		f.writeRelease(w, serverURL)
+	case r.Method == http.MethodPost && r.URL.Path == "/repos/owner/repo/releases":
+		var payload map[string]string
+		json.NewDecoder(r.Body).Decode(&payload)
+		if payload["tag_name"] != "v1.0.0" || payload["name"] != "Release v1.0.0" {
+			http.Error(w, fmt.Sprintf("unexpected release payload %v", payload), http.StatusBadRequest)
+			return
+		}
+		f.created = true
+		w.WriteHeader(http.StatusCreated)
+		f.writeRelease(w, serverURL)
+	case r.Method == http.MethodPost && (r.URL.Path == "/uploads/1/assets" || r.URL.Path == "/repos/owner/repo/releases/1/assets"):
+		content, _ := io.ReadAll(r.Body)
+		f.nextID++
+		f.ids[f.nextID] = r.URL.Query().Get("name")
+		f.assets[r.URL.Query().Get("name")] = string(content)
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/repos/owner/repo/releases/assets/"):
+		var id int
+		fmt.Sscanf(strings.TrimPrefix(r.URL.Path, "/repos/owner/repo/releases/assets/"), "%d", &id)
+		delete(f.assets, f.ids[id])
+		delete(f.ids, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (f *fakeGitHubReleases) writeRelease(w http.ResponseWriter, serverURL string) {
+	var assets []map[string]any
+	for id, name := range f.ids {
+		assets = append(assets, map[string]any{"id": id, "name": name, "browser_download_url": serverURL + "/download/" + name})
+	}
+	json.NewEncoder(w).Encode(map[string]any{
+		"id":         1,
+		"tag_name":   "v1.0.0",
+		"upload_url": serverURL + "/uploads/1/assets{?name,label}",
+		"assets":     assets,
+	})
+}
+
+func writePublishFixture(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestGitHubPublisher_Publish(t *testing.T) {
+	fake := &fakeGitHubReleases{assets: map[string]string{}, ids: map[int]string{}}
+	// Bare hosts are treated as GitHub Enterprise Server, which serves the API under /api/v3
+	server := httptest.NewServer(http.StripPrefix("/api/v3", fake))
+	defer server.Close()
+
+	publisher := NewGitHubPublisher("owner/repo", "test-token")
+	publisher.APIURL = server.URL
+	var _ Publisher = publisher
+
+	archive := writePublishFixture(t, "myapp-Linux_x86_64.tar.gz", "archive")
+	request := PublishRequest{
+		Version:   "v1.0.0",
+		Name:      "Release v1.0.0",
+		Assets:    []string{archive},
+		Checksums: true,
+	}
+	info, err := publisher.Publish(request)
+	if err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if info.Version != "v1.0.0" || len(info.Assets) != 2 {
+		t.Errorf("Expected the release with 2 assets, got %+v", info)
+	}
+	if fake.assets["myapp-Linux_x86_64.tar.gz"] != "archive" {
+		t.Errorf("Expected the archive to be uploaded, got %q", fake.assets["myapp-Linux_x86_64.tar.gz"])
+	}
+	wantChecksums := "0eb3e36bfb24dcd9bb1d1bece1531216b59539a8fde17ee80224af0653c92aa3  myapp-Linux_x86_64.tar.gz\n"
+	if fake.assets[DefaultChecksumFileName] != wantChecksums {
+		t.Errorf("Expected checksums %q, got %q", wantChecksums, fake.assets[DefaultChecksumFileName])
+	}
+
+	// Publishing again conflicts unless existing assets are replaced
+	if _, err := publisher.Publish(request); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("Expected a conflict for existing assets, got %v", err)
+	}
+	os.WriteFile(archive, []byte("rebuilt"), 0644)
+	request.Replace = true
+	if _, err := publisher.Publish(request); err != nil {
+		t.Fatalf("Publish with Replace failed: %v", err)
+	}
+	if fake.assets["myapp-Linux_x86_64.tar.gz"] != "rebuilt" || len(fake.assets) != 2 {
+		t.Errorf("Expected the assets to be replaced, got %v", fake.assets)
+	}
+}
+
+func TestGitHubPublisher_UploadURL(t *testing.T) {
+	fake := &fakeGitHubReleases{assets: map[string]string{}, ids: map[int]string{}}
+	server := httptest.NewServer(http.StripPrefix("/api/v3", fake))
+	defer server.Close()
+
+	// Uploads go to the configured upload root instead of the upload_url GitHub returns
+	publisher := NewGitHubPublisher("owner/repo", "test-token")
+	publisher.APIURL = server.URL
+	publisher.UploadURL = server.URL + "/api/v3/"
+	archive := writePublishFixture(t, "myapp-Linux_x86_64.tar.gz", "archive")
+	if _, err := publisher.Publish(PublishRequest{Version: "v1.0.0", Name: "Release v1.0.0", Assets: []string{archive}}); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if fake.assets["myapp-Linux_x86_64.tar.gz"] != "archive" {
+		t.Errorf("Expected the archive to be uploaded through the upload root, got %v", fake.assets)
+	}
+
+	if got, want := publisher.assetUploadURL(githubPublishRelease{ID: 7}), server.URL+"/api/v3/repos/owner/repo/releases/7/assets"; got != want {
+		t.Errorf("assetUploadURL() = %s, want %s", got, want)
+	}
+	publisher.UploadURL = ""
+	if got, want := publisher.assetUploadURL(githubPublishRelease{ID: 7}), server.URL+"/api/uploads/repos/owner/repo/releases/7/assets"; got != want {
+		t.Errorf("assetUploadURL() without upload_url = %s, want %s", got, want)
+	}
+}
+
+func TestGitHubPublisher_RequiresToken(t *testing.T) {
+	publisher := NewGitHubPublisher("owner/repo", "")
+	if _, err := publisher.Publish(PublishRequest{Version: "v1.0.0"}); err == nil {
+		t.Error("Expected an error without a token")
+	}
+}
+
+func TestGitLabPublisher_Publish(t *testing.T) {
+	var mu sync.Mutex
+	created := false
+	packages := map[string]string{}
+	var links []map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/projects/42/releases/v1.0.0":
+			if !created {
+				http.NotFound(w, r)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]any{"tag_name": "v1.0.0", "assets": map[string]any{"links": links}})
+		case r.Method == http.MethodPost && r.URL.Path == "/projects/42/releases":
+			created = true
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]any{"tag_name": "v1.0.0"})
+		case r.Method == http.MethodPut && strings.HasPrefix(r.URL.Path, "/projects/42/packages/generic/release-assets/v1.0.0/"):
+			content, _ := io.ReadAll(r.Body)
+			packages[filepath.Base(r.URL.Path)] = string(content)
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodPost && r.URL.Path == "/projects/42/releases/v1.0.0/assets/links":
+			var link map[string]any
+			json.NewDecoder(r.Body).Decode(&link)
+			link["id"] = len(links) + 1
+			link["direct_asset_url"] = link["url"]
+			links = append(links, link)
+			w.WriteHeader(http.StatusCreated)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	publisher := NewGitLabPublisher("42", "test-token")
+	publisher.GitLabConfig.BaseURL = server.URL
+	var _ Publisher = publisher
+
+	archive := writePublishFixture(t, "myapp-Linux_x86_64.tar.gz", "archive")
+	info, err := publisher.Publish(PublishRequest{Version: "v1.0.0", Assets: []string{archive}, Checksums: true})
+	if err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if packages["myapp-Linux_x86_64.tar.gz"] != "archive" || packages[DefaultChecksumFileName] == "" {
+		t.Errorf("Expected the archive and checksums in the package registry, got %v", packages)
+	}
+	if len(info.Assets) != 2 {
+		t.Fatalf("Expected 2 linked assets, got %+v", info.Assets)
+	}
+	wantURL := server.URL + "/projects/42/packages/generic/release-assets/v1.0.0/myapp-Linux_x86_64.tar.gz"
+	if info.Assets[0].URL != wantURL || info.Assets[0].LinkType != "package" {
+		t.Errorf("Expected a package link to %s, got %+v", wantURL, info.Assets[0])
+	}
+}
+
+func TestPublishRequest_Prepare(t *testing.T) {
+	if _, err := (PublishRequest{}).prepare(); err == nil {
+		t.Error("Expected an error without a version")
+	}
+	a := writePublishFixture(t, "tool", "a")
+	b := writePublishFixture(t, "tool", "b")
+	if _, err := (PublishRequest{Version: "v1", Assets: []string{a, b}}).prepare(); err == nil {
+		t.Error("Expected an error for duplicate asset names")
+	}
+	if _, err := (PublishRequest{Version: "v1", Assets: []string{filepath.Join(t.TempDir(), "missing")}}).prepare(); err == nil {
+		t.Error("Expected an error for a missing asset")
+	}
+}
@@ -0,0 +1,138 @@
+package release
+
+import (
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrRateLimited matches (via errors.Is) HTTPStatusErrors caused by provider rate limiting
+var ErrRateLimited = fileUtils.NewError(fileUtils.ErrorCodeNetwork, "rate limited")
+
+// HTTPStatusError describes an unsuccessful HTTP response, including the retry hints GitHub and
+// GitLab send, so orchestrators can schedule a later retry instead of failing the whole run
+type HTTPStatusError struct {
+	StatusCode         int           // HTTP status of the final response
+	RetryAfter         time.Duration // Delay requested by the Retry-After header (0 if absent)
+	RateLimitLimit     int           // Requests allowed per window (-1 if unknown)
+	RateLimitRemaining int           // Requests left in the window (-1 if unknown)
+	RateLimitReset     time.Time     // When the rate-limit window resets (zero if unknown)
+}
+
+// newHTTPStatusError reads the status and the Retry-After and rate-limit headers of a response.
+// GitHub sends X-RateLimit-*, GitLab RateLimit-* headers.
+func newHTTPStatusError(resp *http.Response) *HTTPStatusError {
+	now := time.Now()
+	return &HTTPStatusError{
+		StatusCode:         resp.StatusCode,
+		RetryAfter:         parseRetryAfter(resp.Header.Get("Retry-After"), now),
+		RateLimitLimit:     rateLimitHeader(resp.Header, "Limit"),
+		RateLimitRemaining: rateLimitHeader(resp.Header, "Remaining"),
+		RateLimitReset:     parseRateLimitReset(rateLimitHeaderValue(resp.Header, "Reset"), now),
+	}
+}
+
+func (e *HTTPStatusError) Error() string {
+	var details []string
+	if e.RateLimitRemaining == 0 {
+		details = append(details, "rate limit exhausted")
+	}
+	if e.RetryAfter > 0 {
+		details = append(details, fmt.Sprintf("retry after %v", e.RetryAfter))
+	}
+	if !e.RateLimitReset.IsZero() {
+		details = append(details, fmt.Sprintf("rate limit resets at %s", e.RateLimitReset.UTC().Format(time.RFC3339)))
+	}
+	if len(details) == 0 {
+		return strconv.Itoa(e.StatusCode)
+	}
+	return fmt.Sprintf("%d (%s)", e.StatusCode, strings.Join(details, ", "))
+}
+
+// Is makes errors.Is(err, ErrRateLimited) match rate-limit responses
+func (e *HTTPStatusError) Is(target error) bool {
+	return target == ErrRateLimited && e.RateLimited()
+}
+
+// ErrorCode classifies the response: rejected credentials (401, or a 403 that is not rate
+// limiting) as ErrorCodeAuth, a missing release or asset (404) as ErrorCodeNoMatch and anything
+// else as ErrorCodeNetwork. Private repositories answer 404 without valid credentials, too.
+func (e *HTTPStatusError) ErrorCode() fileUtils.ErrorCode {
+	switch {
+	case e.StatusCode == http.StatusUnauthorized, e.StatusCode == http.StatusForbidden && !e.RateLimited():
+		return fileUtils.ErrorCodeAuth
+	case e.StatusCode == http.StatusNotFound:
+		return fileUtils.ErrorCodeNoMatch
+	}
+	return fileUtils.ErrorCodeNetwork
+}
+
+// RateLimited reports whether the response was caused by rate limiting: a 429, or a 403 with an
+// exhausted rate limit as GitHub sends for its primary limit
+func (e *HTTPStatusError) RateLimited() bool {
+	return e.StatusCode == http.StatusTooManyRequests ||
+		(e.StatusCode == http.StatusForbidden && (e.RateLimitRemaining == 0 || e.RetryAfter > 0))
+}
+
+// RetryAt returns the earliest time a retry may succeed: after Retry-After if present, otherwise
+// at the rate-limit reset. The zero time means the response gave no hint.
+func (e *HTTPStatusError) RetryAt(now time.Time) time.Time {
+	if e.RetryAfter > 0 {
+		return now.Add(e.RetryAfter)
+	}
+	if e.RateLimitRemaining == 0 || e.StatusCode == http.StatusTooManyRequests {
+		return e.RateLimitReset
+	}
+	return time.Time{}
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
+
+// rateLimitHeaderValue returns the X-RateLimit-{name} or RateLimit-{name} header
+func rateLimitHeaderValue(header http.Header, name string) string {
+	if value := header.Get("X-RateLimit-" + name); value != "" {
+		return value
+	}
+	return header.Get("RateLimit-" + name)
+}
+
+// rateLimitHeader returns a numeric rate-limit header, or -1 if it is missing or malformed
+func rateLimitHeader(header http.Header, name string) int {
+	value, err := strconv.Atoi(strings.TrimSpace(rateLimitHeaderValue(header, name)))
+	if err != nil {
+		return -1
+	}
+	return value
+}
+
+// parseRateLimitReset parses a reset header, which is a Unix timestamp for GitHub and GitLab;
+// small values are treated as seconds from now as in the IETF RateLimit header draft
+func parseRateLimitReset(value string, now time.Time) time.Time {
+	seconds, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || seconds < 0 {
+		return time.Time{}
+	}
+	if seconds < 1_000_000_000 {
+		return now.Add(time.Duration(seconds) * time.Second)
+	}
+	return time.Unix(seconds, 0)
+}
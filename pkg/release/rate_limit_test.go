@@ -0,0 +1,94 @@
+package release
+
+import (
+	"errors"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRetryableHTTPClient_RateLimitError(t *testing.T) {
+	reset := time.Now().Add(10 * time.Minute).Truncate(time.Second)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "0")
+		w.Header().Set("RateLimit-Limit", "2000")
+		w.Header().Set("RateLimit-Remaining", "0")
+		w.Header().Set("RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	config := DefaultHTTPClientConfig()
+	config.MaxRetries = 1
+	config.RateLimitDelay = time.Millisecond
+	_, err := NewRetryableHTTPClient(config).Get(server.URL)
+
+	var statusErr *HTTPStatusError
+	if !errors.As(err, &statusErr) || !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("Expected rate-limit HTTPStatusError, got %v", err)
+	}
+	if statusErr.StatusCode != http.StatusTooManyRequests || statusErr.RateLimitLimit != 2000 ||
+		statusErr.RateLimitRemaining != 0 || !statusErr.RateLimitReset.Equal(reset) {
+		t.Errorf("Unexpected rate-limit details: %+v", statusErr)
+	}
+	now := time.Now()
+	if got := statusErr.RetryAt(now); !got.Equal(reset) {
+		t.Errorf("RetryAt() = %v, want %v", got, reset)
+	}
+}
+
+func TestGithubRelease_RateLimitError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "60")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "1735689600")
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	release := NewGithubRelease("owner/repo", fileUtils.FileConfig{BinaryName: "myapp"})
+	release.BaseURL = server.URL
+	err := release.GetLatestRelease()
+
+	var statusErr *HTTPStatusError
+	if !errors.As(err, &statusErr) || !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("Expected rate-limit HTTPStatusError, got %v", err)
+	}
+	if statusErr.RateLimitLimit != 60 || !statusErr.RateLimitReset.Equal(time.Unix(1735689600, 0)) {
+		t.Errorf("Unexpected rate-limit details: %+v", statusErr)
+	}
+	expected := "unexpected status code from GitHub: 403 (rate limit exhausted, rate limit resets at 2025-01-01T00:00:00Z)"
+	if err.Error() != expected {
+		t.Errorf("Error() = %q, want %q", err.Error(), expected)
+	}
+}
+
+func TestHTTPStatusError_RetryHints(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	if got := parseRetryAfter("120", now); got != 2*time.Minute {
+		t.Errorf("parseRetryAfter(seconds) = %v", got)
+	}
+	if got := parseRetryAfter(now.Add(time.Minute).Format(http.TimeFormat), now); got != time.Minute {
+		t.Errorf("parseRetryAfter(date) = %v", got)
+	}
+	if got := parseRetryAfter("soon", now); got != 0 {
+		t.Errorf("parseRetryAfter(invalid) = %v", got)
+	}
+	if got := parseRateLimitReset("30", now); !got.Equal(now.Add(30 * time.Second)) {
+		t.Errorf("parseRateLimitReset(delta) = %v", got)
+	}
+
+	serverError := &HTTPStatusError{StatusCode: http.StatusBadGateway, RateLimitLimit: -1, RateLimitRemaining: -1}
+	if errors.Is(serverError, ErrRateLimited) || !serverError.RetryAt(now).IsZero() || serverError.Error() != "502" {
+		t.Errorf("Expected plain server error, got %q", serverError.Error())
+	}
+
+	retryAfter := &HTTPStatusError{StatusCode: http.StatusServiceUnavailable, RetryAfter: time.Minute, RateLimitRemaining: -1}
+	if got := retryAfter.RetryAt(now); !got.Equal(now.Add(time.Minute)) {
+		t.Errorf("RetryAt() = %v", got)
+	}
+}
@@ -0,0 +1,215 @@
+package release
+
+import (
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ResultRelease is implemented by providers that report structured outcomes of downloads and installs
+type ResultRelease interface {
+	DownloadLatestReleaseWithResult() (*DownloadResult, error) // Like DownloadLatestRelease, describing what was downloaded
+	InstallLatestReleaseWithResult() (*InstallResult, error)   // Like InstallLatestRelease, describing what was installed
+}
+
+// DownloadResult describes a completed download
+type DownloadResult struct {
+	Version          string        `json:"version"`           // Resolved release version
+	AssetName        string        `json:"asset_name"`        // Selected asset (may be empty for CDN downloads)
+	URL              string        `json:"url"`               // URL the asset was downloaded from (empty for delta updates and local files)
+	Path             string        `json:"path"`              // Downloaded file, or the patched binary of a delta update
+	Bytes            int64         `json:"bytes"`             // Size of the downloaded file
+	Checksum         string        `json:"checksum"`          // Hex-encoded SHA-256 of the downloaded file
+	Duration         time.Duration `json:"duration"`          // Time spent resolving and downloading the release
+	DeltaApplied     bool          `json:"delta_applied"`     // True if the binary was produced by a delta patch
+	Streamed         bool          `json:"streamed"`          // True if the archive is downloaded and extracted during installation instead
+	AlreadyInstalled bool          `json:"already_installed"` // True if nothing was downloaded because the version is already installed
+}
+
+// InstallResult describes a completed installation
+type InstallResult struct {
+	Version         string                      `json:"version"`            // Installed version
+	Duration        time.Duration               `json:"duration"`           // Time spent installing
+	Download        *DownloadResult             `json:"download,omitempty"` // The preceding download, if it was made with DownloadLatestReleaseWithResult
+	Installation    *fileUtils.InstallationInfo `json:"installation"`       // Paths and symlink status of the installed binary
+	DownloadCleanup string                      `json:"download_cleanup"`   // What happened to the downloaded file (see DownloadCleanupRemoved)
+}
+
+// inspect records the size and checksum of the downloaded file. A missing file is only
+// acceptable when the archive is streamed during installation.
+func (r *DownloadResult) inspect(path string, streaming bool) error {
+	info, err := fsys().Stat(path)
+	if os.IsNotExist(err) && streaming {
+		r.Streamed = true
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to inspect downloaded file: %v", err)
+	}
+
+	checksum, err := fileUtils.FileSHA256(path)
+	if err != nil {
+		return fmt.Errorf("failed to checksum downloaded file: %v", err)
+	}
+	r.Path = path
+	r.Bytes = info.Size()
+	r.Checksum = checksum
+	return nil
+}
+
+// newInstallResult describes an installation that started at start
+func newInstallResult(version string, start time.Time, download *DownloadResult, downloadCleanup string,
+	installationInfo func() (*fileUtils.InstallationInfo, error)) (*InstallResult, error) {
+	duration := time.Since(start)
+	info, err := installationInfo()
+	if err != nil {
+		return nil, fmt.Errorf("installed %s but failed to read installation info: %w", version, err)
+	}
+	return &InstallResult{
+		Version:         version,
+		Duration:        duration,
+		Download:        download,
+		Installation:    info,
+		DownloadCleanup: downloadCleanup,
+	}, nil
+}
+
+// DownloadLatestReleaseWithResult downloads the latest release and describes the download
+func (g *GithubRelease) DownloadLatestReleaseWithResult() (*DownloadResult, error) {
+	start := time.Now()
+	g.lastDownload = nil
+	g.downloadedFrom = ""
+	if err := g.DownloadLatestRelease(); err != nil {
+		return nil, err
+	}
+
+	result := &DownloadResult{
+		Version:      g.Version,
+		AssetName:    g.AssetName,
+		URL:          g.downloadedFrom,
+		DeltaApplied: g.DeltaApplied,
+	}
+	if g.AlreadyInstalled {
+		result.AlreadyInstalled = true
+		result.Duration = time.Since(start)
+		g.lastDownload = result
+		return result, nil
+	}
+	path := g.getTempSourceArchivePath()
+	if g.DeltaApplied {
+		path = g.deltaBinaryPath
+	}
+	if err := result.inspect(path, g.streamExtractionEnabled()); err != nil {
+		return nil, err
+	}
+	result.Duration = time.Since(start)
+	g.lastDownload = result
+	return result, nil
+}
+
+// InstallLatestReleaseWithResult installs the downloaded release and describes the installation
+func (g *GithubRelease) InstallLatestReleaseWithResult() (*InstallResult, error) {
+	start := time.Now()
+	download := g.lastDownload
+	g.lastDownload = nil
+	if err := g.InstallLatestRelease(); err != nil {
+		return nil, err
+	}
+	return newInstallResult(g.Version, start, download, g.downloadCleanup, g.GetInstallationInfo)
+}
+
+// InstallReleaseWithResult installs the release tagged version, downloading it if needed, and
+// describes the installation
+func (g *GithubRelease) InstallReleaseWithResult(version string) (*InstallResult, error) {
+	start := time.Now()
+	g.lastDownload = nil
+	if err := g.InstallRelease(version); err != nil {
+		return nil, err
+	}
+	return newInstallResult(g.Version, start, nil, g.downloadCleanup, g.GetInstallationInfo)
+}
+
+// DownloadLatestReleaseWithResult downloads the latest release and describes the download
+func (r *GitLabRelease) DownloadLatestReleaseWithResult() (*DownloadResult, error) {
+	start := time.Now()
+	r.lastDownload = nil
+	r.downloadedFrom = ""
+	if err := r.DownloadLatestRelease(); err != nil {
+		return nil, err
+	}
+
+	result := &DownloadResult{
+		Version:      r.Version,
+		AssetName:    r.AssetName,
+		URL:          r.downloadedFrom,
+		DeltaApplied: r.DeltaApplied,
+	}
+	if r.AlreadyInstalled {
+		result.AlreadyInstalled = true
+		result.Duration = time.Since(start)
+		r.lastDownload = result
+		return result, nil
+	}
+	path := r.getTempSourceArchivePath()
+	if r.DeltaApplied {
+		path = r.deltaBinaryPath
+	}
+	if err := result.inspect(path, r.streamExtractionEnabled()); err != nil {
+		return nil, err
+	}
+	result.Duration = time.Since(start)
+	r.lastDownload = result
+	return result, nil
+}
+
+// InstallLatestReleaseWithResult installs the downloaded release and describes the installation
+func (r *GitLabRelease) InstallLatestReleaseWithResult() (*InstallResult, error) {
+	start := time.Now()
+	download := r.lastDownload
+	r.lastDownload = nil
+	if err := r.InstallLatestRelease(); err != nil {
+		return nil, err
+	}
+	return newInstallResult(r.Version, start, download, r.downloadCleanup, r.GetInstallationInfo)
+}
+
+// InstallReleaseWithResult installs the release tagged version, downloading it if needed, and
+// describes the installation
+func (r *GitLabRelease) InstallReleaseWithResult(version string) (*InstallResult, error) {
+	start := time.Now()
+	r.lastDownload = nil
+	if err := r.InstallRelease(version); err != nil {
+		return nil, err
+	}
+	return newInstallResult(r.Version, start, nil, r.downloadCleanup, r.GetInstallationInfo)
+}
+
+// DownloadLatestReleaseWithResult validates the local artifact and describes it
+func (l *LocalRelease) DownloadLatestReleaseWithResult() (*DownloadResult, error) {
+	start := time.Now()
+	if err := l.DownloadLatestRelease(); err != nil {
+		return nil, err
+	}
+
+	result := &DownloadResult{Version: l.Version, AssetName: filepath.Base(l.ArtifactPath)}
+	if err := result.inspect(l.ArtifactPath, false); err != nil {
+		return nil, err
+	}
+	result.Duration = time.Since(start)
+	return result, nil
+}
+
+// InstallLatestReleaseWithResult installs the local artifact and describes the installation
+func (l *LocalRelease) InstallLatestReleaseWithResult() (*InstallResult, error) {
+	start := time.Now()
+	download, err := l.DownloadLatestReleaseWithResult()
+	if err != nil {
+		return nil, err
+	}
+	if err := l.InstallLatestRelease(); err != nil {
+		return nil, err
+	}
+	return newInstallResult(l.Version, start, download, DownloadCleanupNone, l.GetInstallationInfo)
+}
@@ -0,0 +1,157 @@
+package release
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGithubRelease_WithResult(t *testing.T) {
+	tempDir := t.TempDir()
+	archivePath := filepath.Join(tempDir, "fixture.tar.gz")
+	writeTestTarGz(t, archivePath, map[string]string{"myapp": "#!/bin/sh\necho result\n"})
+	archive, _ := os.ReadFile(archivePath)
+	digest := sha256.Sum256(archive)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive)
+	}))
+	defer server.Close()
+
+	fileConfig := fileUtils.FileConfig{
+		BaseBinaryDirectory:    filepath.Join(tempDir, "bin"),
+		VersionedDirectoryName: "versions",
+		SourceBinaryName:       "myapp",
+		BinaryName:             "myapp",
+		ProjectName:            "myapp",
+		CreateLocalSymlink:     true,
+		StagingDirectory:       filepath.Join(tempDir, "staging"),
+		KeepDownloads:          true,
+	}
+	assetURL := server.URL + "/myapp-Linux_x86_64.tar.gz"
+	release := NewGithubRelease("owner/repo", fileConfig)
+	release.Source = &fakeAssetSource{release: &ReleaseInfo{
+		Version: "v1.2.0",
+		Assets:  []AssetInfo{{Name: "myapp-Linux_x86_64.tar.gz", URL: assetURL}},
+	}}
+
+	download, err := release.DownloadLatestReleaseWithResult()
+	if err != nil {
+		t.Fatalf("DownloadLatestReleaseWithResult() error = %v", err)
+	}
+	if download.Version != "v1.2.0" || download.AssetName != "myapp-Linux_x86_64.tar.gz" || download.URL != assetURL {
+		t.Errorf("Unexpected download result: %+v", download)
+	}
+	if download.Bytes != int64(len(archive)) || download.Checksum != hex.EncodeToString(digest[:]) {
+		t.Errorf("Expected %d bytes with checksum %x, got %d bytes with %s", len(archive), digest, download.Bytes, download.Checksum)
+	}
+
+	install, err := release.InstallLatestReleaseWithResult()
+	if err != nil {
+		t.Fatalf("InstallLatestReleaseWithResult() error = %v", err)
+	}
+	if install.Download != download {
+		t.Error("Expected the install result to include the preceding download")
+	}
+	if install.Installation == nil || install.Installation.VersionedPath != fileUtils.GetVersionedBinaryPath(fileConfig, "v1.2.0") {
+		t.Errorf("Unexpected installation info: %+v", install.Installation)
+	}
+	if install.DownloadCleanup != DownloadCleanupDisabled || !fileUtils.FileExists(download.Path) {
+		t.Errorf("Expected the download to be kept with KeepDownloads, got %q", install.DownloadCleanup)
+	}
+	expected := fileUtils.Provenance{Provider: fileUtils.ProvenanceGitHub, Repository: "owner/repo",
+		AssetName: "myapp-Linux_x86_64.tar.gz", DownloadURL: assetURL, Checksum: download.Checksum}
+	if provenance := install.Installation.Provenance; provenance == nil || *provenance != expected {
+		t.Errorf("Expected provenance %+v, got %+v", expected, provenance)
+	}
+}
+
+func TestGithubRelease_CleanupDownloads(t *testing.T) {
+	tempDir := t.TempDir()
+	archivePath := filepath.Join(tempDir, "fixture.tar.gz")
+	writeTestTarGz(t, archivePath, map[string]string{"myapp": "#!/bin/sh\necho cleanup\n"})
+	archive, _ := os.ReadFile(archivePath)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive)
+	}))
+	defer server.Close()
+
+	newRelease := func(sourceBinaryName string) *GithubRelease {
+		fileConfig := fileUtils.FileConfig{CreateLocalSymlink: true}
+		fileConfig.BaseBinaryDirectory = filepath.Join(tempDir, "bin")
+		fileConfig.VersionedDirectoryName = "versions"
+		fileConfig.SourceBinaryName = sourceBinaryName
+		fileConfig.BinaryName = "myapp"
+		fileConfig.ProjectName = "myapp"
+		fileConfig.StagingDirectory = filepath.Join(tempDir, "staging")
+
+		release := NewGithubRelease("owner/repo", fileConfig)
+		release.Source = &fakeAssetSource{release: &ReleaseInfo{
+			Version: "v1.2.0",
+			Assets:  []AssetInfo{{Name: "myapp-Linux_x86_64.tar.gz", URL: server.URL + "/myapp-Linux_x86_64.tar.gz"}},
+		}}
+		return release
+	}
+
+	// A failed installation keeps the download for debugging
+	failing := newRelease("not-in-archive")
+	download, err := failing.DownloadLatestReleaseWithResult()
+	if err != nil {
+		t.Fatalf("DownloadLatestReleaseWithResult() error = %v", err)
+	}
+	if _, err := failing.InstallLatestReleaseWithResult(); err == nil {
+		t.Fatal("Expected installation of a missing binary to fail")
+	}
+	if !fileUtils.FileExists(download.Path) {
+		t.Error("Expected the download to be kept after a failed installation")
+	}
+
+	release := newRelease("myapp")
+	if download, err = release.DownloadLatestReleaseWithResult(); err != nil {
+		t.Fatalf("DownloadLatestReleaseWithResult() error = %v", err)
+	}
+	install, err := release.InstallLatestReleaseWithResult()
+	if err != nil {
+		t.Fatalf("InstallLatestReleaseWithResult() error = %v", err)
+	}
+	if install.DownloadCleanup != DownloadCleanupRemoved {
+		t.Errorf("Expected download cleanup status %q, got %q", DownloadCleanupRemoved, install.DownloadCleanup)
+	}
+	if fileUtils.FileExists(download.Path) {
+		t.Errorf("Expected %s to be removed after installing", download.Path)
+	}
+}
+
+func TestLocalRelease_InstallWithResult(t *testing.T) {
+	tempDir := t.TempDir()
+	archivePath := filepath.Join(tempDir, "myapp.tar.gz")
+	writeTestTarGz(t, archivePath, map[string]string{"myapp": "#!/bin/sh\n"})
+
+	fileConfig := fileUtils.FileConfig{
+		BaseBinaryDirectory:    filepath.Join(tempDir, "bin"),
+		VersionedDirectoryName: "versions",
+		SourceBinaryName:       "myapp",
+		BinaryName:             "myapp",
+		CreateLocalSymlink:     true,
+	}
+	result, err := NewLocalRelease(archivePath, "v1.0.0", fileConfig).InstallLatestReleaseWithResult()
+	if err != nil {
+		t.Fatalf("InstallLatestReleaseWithResult() error = %v", err)
+	}
+	if result.Download == nil || result.Download.AssetName != "myapp.tar.gz" || result.Download.Checksum == "" {
+		t.Errorf("Expected the local artifact to be described, got %+v", result.Download)
+	}
+	if !result.Installation.LocalSymlinkCreated {
+		t.Error("Expected the installation info to report the local symlink")
+	}
+	if provenance := result.Installation.Provenance; provenance == nil || provenance.Provider != fileUtils.ProvenanceLocal ||
+		provenance.DownloadURL != archivePath || provenance.Checksum != result.Download.Checksum {
+		t.Errorf("Expected the local artifact as provenance, got %+v", provenance)
+	}
+}
@@ -0,0 +1,174 @@
+package release
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SelectionPolicy decides which of a project's releases counts as the latest
+type SelectionPolicy string
+
+const (
+	SelectByDefault  SelectionPolicy = ""       // The provider's latest release (GitHub's latest, GitLab's most recently released)
+	SelectByDate     SelectionPolicy = "date"   // The most recently published release
+	SelectBySemver   SelectionPolicy = "semver" // The highest stable semantic version, ignoring backported patch releases published later
+	SelectByTagRegex SelectionPolicy = "regex"  // The most recently published release whose tag matches TagPattern
+)
+
+// ReleaseSelection configures how the latest release is chosen from the list of releases
+type ReleaseSelection struct {
+	Policy     SelectionPolicy `json:"policy"`      // Selection policy (default: the provider's latest release)
+	TagPattern string          `json:"tag_pattern"` // Regular expression tags must match; required by SelectByTagRegex, a filter for the other policies. A group named version, e.g. `^toolA-(?P<version>v.+)$`, is the installed version
+	TagPrefix  string          `json:"tag_prefix"`  // Prefix of the tool's tags in monorepos, e.g. "toolA/": other tags are skipped and the prefix is stripped from the installed version
+	IgnoreTags []string        `json:"ignore_tags"` // Tags never selected as the latest release, e.g. known-bad versions ("v" prefixes are ignored)
+}
+
+// usesProviderLatest reports whether the provider's own latest release can be used without listing releases
+func (s ReleaseSelection) usesProviderLatest() bool {
+	return s.Policy == SelectByDefault && s.TagPattern == "" && s.TagPrefix == "" && len(s.IgnoreTags) == 0
+}
+
+// versionGroup returns the TagPattern if it has a group named version
+func (s ReleaseSelection) versionGroup() *regexp.Regexp {
+	if s.TagPattern == "" {
+		return nil
+	}
+	pattern, err := regexp.Compile(s.TagPattern)
+	if err != nil || pattern.SubexpIndex("version") < 0 {
+		return nil
+	}
+	return pattern
+}
+
+// TagVersion returns the version of the tool released with the tag: the tag without TagPrefix, or
+// the TagPattern group named version. It returns false for tags of other tools.
+func (s ReleaseSelection) TagVersion(tag string) (string, bool) {
+	version, ok := strings.CutPrefix(tag, s.TagPrefix)
+	if !ok || version == "" {
+		return "", false
+	}
+	if pattern := s.versionGroup(); pattern != nil {
+		match := pattern.FindStringSubmatch(version)
+		if match == nil || match[pattern.SubexpIndex("version")] == "" {
+			return "", false
+		}
+		version = match[pattern.SubexpIndex("version")]
+	}
+	return version, true
+}
+
+// Tag returns the tag of a pinned version, adding TagPrefix unless the version already has it
+func (s ReleaseSelection) Tag(version string) string {
+	if strings.HasPrefix(version, s.TagPrefix) {
+		return version
+	}
+	return s.TagPrefix + version
+}
+
+// withTagVersion sets the release's Version to the version derived from its tag, keeping the tag
+// in Tag. Releases are left unchanged without TagPrefix or a version group and for tags of other
+// tools.
+func (s ReleaseSelection) withTagVersion(release *ReleaseInfo) *ReleaseInfo {
+	if s.TagPrefix == "" && s.versionGroup() == nil {
+		return release
+	}
+	if version, ok := s.TagVersion(release.TagName()); ok {
+		release.Tag = release.TagName()
+		release.Version = version
+	}
+	return release
+}
+
+// forTool returns the releases of the tool with versions derived from their tags (see
+// withTagVersion). Releases are returned unchanged without TagPrefix or a version group.
+func (s ReleaseSelection) forTool(releases []ReleaseInfo) []ReleaseInfo {
+	if s.TagPrefix == "" && s.versionGroup() == nil {
+		return releases
+	}
+	var kept []ReleaseInfo
+	for _, release := range releases {
+		if _, ok := s.TagVersion(release.TagName()); ok {
+			kept = append(kept, *s.withTagVersion(&release))
+		}
+	}
+	return kept
+}
+
+// ignores reports whether the release's version or tag is on the ignore list
+func (s ReleaseSelection) ignores(release *ReleaseInfo) bool {
+	for _, tag := range s.IgnoreTags {
+		if sameVersion(tag, release.Version) || sameVersion(tag, release.TagName()) {
+			return true
+		}
+	}
+	return false
+}
+
+// withoutIgnored returns the releases not on the ignore list
+func (s ReleaseSelection) withoutIgnored(releases []ReleaseInfo) []ReleaseInfo {
+	var kept []ReleaseInfo
+	for i := range releases {
+		if !s.ignores(&releases[i]) {
+			kept = append(kept, releases[i])
+		}
+	}
+	return kept
+}
+
+// Latest returns the latest release according to the policy, skipping ignored tags. Releases are
+// expected in the provider's order, most recent first; the default policy selects the first
+// release that is not a prerelease, like the providers' own latest release.
+func (s ReleaseSelection) Latest(releases []ReleaseInfo) (*ReleaseInfo, error) {
+	var pattern *regexp.Regexp
+	if s.TagPattern != "" {
+		var err error
+		if pattern, err = regexp.Compile(s.TagPattern); err != nil {
+			return nil, fmt.Errorf("invalid tag pattern %q: %w", s.TagPattern, err)
+		}
+	} else if s.Policy == SelectByTagRegex {
+		return nil, fmt.Errorf("selection policy %q requires a tag pattern", s.Policy)
+	}
+
+	var best *ReleaseInfo
+	var bestVersion SemVersion
+	for i := range releases {
+		release := &releases[i]
+		if (pattern != nil && !pattern.MatchString(release.TagName())) || s.ignores(release) {
+			continue
+		}
+
+		switch s.Policy {
+		case SelectByDefault:
+			if best == nil && !release.Prerelease {
+				best = release
+			}
+		case SelectByDate, SelectByTagRegex:
+			if best == nil || release.PublishedAt.After(best.PublishedAt) {
+				best = release
+			}
+		case SelectBySemver:
+			version, err := ParseSemVersion(release.Version)
+			if err != nil || version.Prerelease != "" {
+				continue
+			}
+			if best == nil || version.Compare(bestVersion) > 0 {
+				best = release
+				bestVersion = version
+			}
+		default:
+			return nil, fmt.Errorf("unknown selection policy %q", s.Policy)
+		}
+	}
+
+	if best == nil {
+		if s.TagPrefix != "" && s.TagPattern == "" {
+			return nil, noMatchErrorf(ErrNoMatchingRelease, "no selectable release tag starts with %s", s.TagPrefix)
+		}
+		if s.TagPattern != "" {
+			return nil, noMatchErrorf(ErrNoMatchingRelease, "no release tag matches pattern %s", s.TagPattern)
+		}
+		return nil, noMatchErrorf(ErrNoMatchingRelease, "no selectable release found among %d releases", len(releases))
+	}
+	return best, nil
+}
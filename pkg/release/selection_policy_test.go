@@ -0,0 +1,174 @@
+package release
+
+import (
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"testing"
+	"time"
+)
+
+// backportReleases lists releases most recently released first, with a backported patch release
+// published after the newer minor version
+func backportReleases() []ReleaseInfo {
+	day := func(d int) time.Time { return time.Date(2024, 6, d, 0, 0, 0, 0, time.UTC) }
+	asset := []AssetInfo{{Name: "myapp-Linux_x86_64.tar.gz", URL: "https://example.com/linux"}}
+	return []ReleaseInfo{
+		{Version: "v1.29.0-rc.1", PublishedAt: day(20), Assets: asset},
+		{Version: "v1.27.9", PublishedAt: day(15), Assets: asset},
+		{Version: "v1.28.1", PublishedAt: day(10), Assets: asset},
+		{Version: "tools/v2.0.0", PublishedAt: day(5), Assets: asset},
+		{Version: "v1.28.0", PublishedAt: day(1), Assets: asset},
+	}
+}
+
+func TestReleaseSelection_Latest(t *testing.T) {
+	tests := []struct {
+		name      string
+		selection ReleaseSelection
+		want      string
+	}{
+		{"date", ReleaseSelection{Policy: SelectByDate}, "v1.29.0-rc.1"},
+		{"semver", ReleaseSelection{Policy: SelectBySemver}, "tools/v2.0.0"},
+		{"semver with pattern", ReleaseSelection{Policy: SelectBySemver, TagPattern: `^v\d`}, "v1.28.1"},
+		{"regex", ReleaseSelection{Policy: SelectByTagRegex, TagPattern: `^v1\.28\.`}, "v1.28.1"},
+		{"date with pattern", ReleaseSelection{Policy: SelectByDate, TagPattern: `^tools/`}, "tools/v2.0.0"},
+		{"default", ReleaseSelection{}, "v1.29.0-rc.1"},
+		{"ignored tags", ReleaseSelection{Policy: SelectBySemver, TagPattern: `^v`, IgnoreTags: []string{"1.28.1"}}, "v1.28.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			latest, err := tt.selection.Latest(backportReleases())
+			if err != nil {
+				t.Fatalf("Latest failed: %v", err)
+			}
+			if latest.Version != tt.want {
+				t.Errorf("Latest() = %s, want %s", latest.Version, tt.want)
+			}
+		})
+	}
+
+	for _, selection := range []ReleaseSelection{
+		{Policy: SelectByTagRegex},
+		{Policy: SelectByDate, TagPattern: `(`},
+		{Policy: SelectByDate, TagPattern: `^v9`},
+		{Policy: "newest"},
+	} {
+		if _, err := selection.Latest(backportReleases()); err == nil {
+			t.Errorf("Expected an error for %+v", selection)
+		}
+	}
+}
+
+func TestGitLabRelease_SelectionPolicy(t *testing.T) {
+	release := NewGitlabRelease("123", fileUtils.FileConfig{ProjectName: "myapp"})
+	release.Source = &fakeListingSource{releases: backportReleases()[1:]}
+
+	// GitLab's most recently released backport is the provider's latest release
+	if err := release.GetLatestRelease(); err != nil {
+		t.Fatalf("GetLatestRelease failed: %v", err)
+	}
+	if release.Version != "v1.27.9" {
+		t.Errorf("Expected the provider's latest release v1.27.9, got %s", release.Version)
+	}
+
+	release.Selection = ReleaseSelection{Policy: SelectBySemver, TagPattern: `^v`}
+	if err := release.GetLatestRelease(); err != nil {
+		t.Fatalf("GetLatestRelease failed: %v", err)
+	}
+	if release.Version != "v1.28.1" {
+		t.Errorf("Expected v1.28.1 by semver, got %s", release.Version)
+	}
+
+	// Constraints restrict the candidates before the policy selects one
+	release.Selection = ReleaseSelection{Policy: SelectByDate}
+	release.VersionConstraint = "~1.28"
+	if err := release.GetLatestRelease(); err != nil {
+		t.Fatalf("GetLatestRelease failed: %v", err)
+	}
+	if release.Version != "v1.28.1" {
+		t.Errorf("Expected the most recent ~1.28 release v1.28.1, got %s", release.Version)
+	}
+}
+
+func TestGithubRelease_SelectionPolicyRequiresLister(t *testing.T) {
+	release := NewGithubRelease("owner/repo", fileUtils.FileConfig{ProjectName: "myapp"})
+	release.Source = &fakeAssetSource{release: fakeLinuxRelease()}
+	release.Selection = ReleaseSelection{Policy: SelectBySemver}
+	if err := release.GetLatestRelease(); err == nil {
+		t.Error("Expected an error when the asset source cannot list releases")
+	}
+}
+
+func TestGithubRelease_IgnoreTags(t *testing.T) {
+	releases := backportReleases()
+	releases[0].Prerelease = true
+	release := NewGithubRelease("owner/repo", fileUtils.FileConfig{ProjectName: "myapp"})
+	release.Source = &fakeListingSource{releases: releases}
+	release.Selection.IgnoreTags = []string{"v1.27.9"}
+
+	// Prereleases are skipped like GitHub's latest release, ignored tags before selection
+	if err := release.GetLatestRelease(); err != nil {
+		t.Fatalf("GetLatestRelease failed: %v", err)
+	}
+	if release.Version != "v1.28.1" {
+		t.Errorf("Expected v1.28.1, got %s", release.Version)
+	}
+
+	release.VersionConstraint = "~1.27"
+	if err := release.GetLatestRelease(); err == nil {
+		t.Error("Expected no release to match once v1.27.9 is ignored")
+	}
+}
+
+func TestReleaseSelection_MonorepoTags(t *testing.T) {
+	asset := []AssetInfo{{Name: "myapp-Linux_x86_64.tar.gz", URL: "https://example.com/linux"}}
+	releases := []ReleaseInfo{
+		{Version: "toolB/v0.9.0", Assets: asset},
+		{Version: "toolA/v2.0.0-rc.1", Prerelease: true, Assets: asset},
+		{Version: "toolA/v1.10.0", Assets: asset},
+		{Version: "toolA/v1.9.0", Assets: asset},
+		{Version: "v3.0.0", Assets: asset},
+	}
+	release := NewGithubRelease("owner/monorepo", fileUtils.FileConfig{ProjectName: "myapp"})
+	release.Source = &fakeListingSource{releases: releases}
+
+	tests := []struct {
+		name       string
+		selection  ReleaseSelection
+		constraint string
+		want       string
+		wantTag    string
+	}{
+		{"prefix", ReleaseSelection{TagPrefix: "toolA/"}, "", "v1.10.0", "toolA/v1.10.0"},
+		{"prefix by semver", ReleaseSelection{TagPrefix: "toolA/", Policy: SelectBySemver}, "", "v1.10.0", "toolA/v1.10.0"},
+		{"prefix with constraint", ReleaseSelection{TagPrefix: "toolA/"}, "~1.9", "v1.9.0", "toolA/v1.9.0"},
+		{"ignored prefixed tag", ReleaseSelection{TagPrefix: "toolA/", IgnoreTags: []string{"toolA/v1.10.0"}}, "", "v1.9.0", "toolA/v1.9.0"},
+		{"version group", ReleaseSelection{TagPattern: `^toolB/(?P<version>v.+)$`}, "", "v0.9.0", "toolB/v0.9.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release.Selection = tt.selection
+			release.VersionConstraint = tt.constraint
+			if err := release.GetLatestRelease(); err != nil {
+				t.Fatalf("GetLatestRelease failed: %v", err)
+			}
+			if release.Version != tt.want || release.Info.TagName() != tt.wantTag {
+				t.Errorf("Expected version %s, got %s (tag %s)", tt.want, release.Version, release.Info.TagName())
+			}
+		})
+	}
+
+	// Pinned versions are looked up by their prefixed tag
+	release.Selection = ReleaseSelection{TagPrefix: "toolA/"}
+	release.VersionConstraint = ""
+	if err := release.GetRelease("v1.9.0"); err != nil {
+		t.Fatalf("GetRelease failed: %v", err)
+	}
+	if release.Version != "v1.9.0" || release.Info.Tag != "toolA/v1.9.0" {
+		t.Errorf("Expected v1.9.0 from tag toolA/v1.9.0, got %s from %s", release.Version, release.Info.Tag)
+	}
+
+	release.Selection = ReleaseSelection{TagPrefix: "toolC/"}
+	if err := release.GetLatestRelease(); err == nil {
+		t.Error("Expected an error when no tag has the prefix")
+	}
+}
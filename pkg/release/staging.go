@@ -0,0 +1,62 @@
+package release
+
+import (
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/filesystem"
+)
+
+// fsys returns the file system downloads are staged on, see filesystem.SetDefault
+func fsys() filesystem.FS {
+	return filesystem.Default()
+}
+
+// stagingOwner identifies the tool in staging path claims
+func (g *GithubRelease) stagingOwner() string {
+	return "github:" + g.Repository
+}
+
+// stagingOwner identifies the tool in staging path claims
+func (r *GitLabRelease) stagingOwner() string {
+	return "gitlab:" + r.ProjectId
+}
+
+// releaseStagedDownload releases the claim on the staged download once an installation attempt
+// ends, so a failed or abandoned installation does not block later downloads to the path
+func (g *GithubRelease) releaseStagedDownload() {
+	fileUtils.ReleaseStagingPath(g.getTempSourceArchivePath(), g.stagingOwner())
+}
+
+// ReleaseDownload releases the claim on the staging path taken by DownloadLatestRelease or
+// DownloadRelease when the download will not be installed, so other tools sharing the path can
+// download to it. Installing releases the claim by itself. The staged file is left in place.
+func (g *GithubRelease) ReleaseDownload() {
+	g.releaseStagedDownload()
+}
+
+// releaseStagedDownload releases the claim on the staged download once an installation attempt
+// ends, so a failed or abandoned installation does not block later downloads to the path
+func (r *GitLabRelease) releaseStagedDownload() {
+	fileUtils.ReleaseStagingPath(r.getTempSourceArchivePath(), r.stagingOwner())
+}
+
+// ReleaseDownload releases the claim on the staging path taken by DownloadLatestRelease or
+// DownloadRelease when the download will not be installed, so other tools sharing the path can
+// download to it. Installing releases the claim by itself. The staged file is left in place.
+func (r *GitLabRelease) ReleaseDownload() {
+	r.releaseStagedDownload()
+}
+
+// stageDownload claims destination for owner and downloads into it, failing with
+// fileUtils.ErrStagingCollision while another tool's download occupies the path. The claim is kept
+// until the installation attempt ends, whatever its outcome, or ReleaseDownload is called, and
+// released when the download fails. Claims only protect against tools in the same process.
+func stageDownload(destination, owner string, download func() error) error {
+	if err := fileUtils.ClaimStagingPath(destination, owner); err != nil {
+		return err
+	}
+	if err := download(); err != nil {
+		fileUtils.ReleaseStagingPath(destination, owner)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package release
+
+import (
+	"context"
+	"errors"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRelease_StagingCollision(t *testing.T) {
+	tempDir := t.TempDir()
+	archivePath := filepath.Join(tempDir, "fixture.tar.gz")
+	writeTestTarGz(t, archivePath, map[string]string{"myapp": "#!/bin/sh\necho staged\n"})
+	archive, _ := os.ReadFile(archivePath)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive)
+	}))
+	defer server.Close()
+
+	// Both tools are configured with the same explicit download path
+	newConfig := func(name string) fileUtils.FileConfig {
+		return fileUtils.FileConfig{
+			BaseBinaryDirectory:    filepath.Join(tempDir, name),
+			VersionedDirectoryName: "versions",
+			SourceBinaryName:       "myapp",
+			BinaryName:             "myapp",
+			ProjectName:            name,
+			SourceArchivePath:      filepath.Join(tempDir, "shared.tar.gz"),
+		}
+	}
+	source := &fakeAssetSource{release: &ReleaseInfo{
+		Version: "v1.0.0",
+		Assets:  []AssetInfo{{Name: "myapp-Linux_x86_64.tar.gz", URL: server.URL + "/myapp-Linux_x86_64.tar.gz"}},
+	}}
+	first := NewGithubRelease("owner/first", newConfig("first"))
+	first.Source = source
+	second := NewGitlabRelease("123", newConfig("second"))
+	second.Source = source
+
+	if err := first.DownloadLatestRelease(); err != nil {
+		t.Fatalf("DownloadLatestRelease failed: %v", err)
+	}
+	if err := second.DownloadLatestRelease(); !errors.Is(err, fileUtils.ErrStagingCollision) {
+		t.Fatalf("Expected ErrStagingCollision while the first download is pending, got %v", err)
+	}
+	if err := first.InstallLatestRelease(); err != nil {
+		t.Fatalf("InstallLatestRelease failed: %v", err)
+	}
+
+	// The installed download no longer occupies the path
+	if err := second.DownloadLatestRelease(); err != nil {
+		t.Fatalf("Expected the download to succeed after the first installation, got %v", err)
+	}
+	if err := second.InstallLatestRelease(); err != nil {
+		t.Fatalf("InstallLatestRelease failed: %v", err)
+	}
+}
+
+func TestRelease_FailedInstallReleasesStagingPath(t *testing.T) {
+	tempDir := t.TempDir()
+	archivePath := filepath.Join(tempDir, "fixture.tar.gz")
+	writeTestTarGz(t, archivePath, map[string]string{"myapp": "#!/bin/sh\necho staged\n"})
+	archive, _ := os.ReadFile(archivePath)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive)
+	}))
+	defer server.Close()
+
+	newConfig := func(name, sourceBinaryName string) fileUtils.FileConfig {
+		return fileUtils.FileConfig{
+			BaseBinaryDirectory:    filepath.Join(tempDir, name),
+			VersionedDirectoryName: "versions",
+			SourceBinaryName:       sourceBinaryName,
+			BinaryName:             "myapp",
+			ProjectName:            name,
+			SourceArchivePath:      filepath.Join(tempDir, "shared.tar.gz"),
+		}
+	}
+	source := &fakeAssetSource{release: &ReleaseInfo{
+		Version: "v1.0.0",
+		Assets:  []AssetInfo{{Name: "myapp-Linux_x86_64.tar.gz", URL: server.URL + "/myapp-Linux_x86_64.tar.gz"}},
+	}}
+	failing := NewGithubRelease("owner/failing", newConfig("failing", "not-in-archive"))
+	failing.Source = source
+	other := NewGithubRelease("owner/other", newConfig("other", "myapp"))
+	other.Source = source
+
+	if err := failing.DownloadLatestRelease(); err != nil {
+		t.Fatalf("DownloadLatestRelease failed: %v", err)
+	}
+	if err := failing.InstallLatestRelease(); err == nil {
+		t.Fatal("Expected installation of a missing binary to fail")
+	}
+	if err := other.DownloadLatestRelease(); err != nil {
+		t.Fatalf("Expected the failed installation to release the path, got %v", err)
+	}
+
+	// An installation that never starts because its context is done releases the path as well
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := other.InstallLatestReleaseContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	if err := failing.DownloadLatestRelease(); err != nil {
+		t.Fatalf("Expected the abandoned installation to release the path, got %v", err)
+	}
+
+	// A download that is never installed releases the path with ReleaseDownload
+	failing.ReleaseDownload()
+	if err := other.DownloadLatestRelease(); err != nil {
+		t.Fatalf("Expected ReleaseDownload to release the path, got %v", err)
+	}
+}
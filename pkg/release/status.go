@@ -0,0 +1,75 @@
+package release
+
+import (
+	"bytes"
+	"encoding/json"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"io"
+)
+
+// ToolStatus is the state of one tool for scripting, e.g. `mytool update --json | jq`. Field
+// names are stable; new fields may be added. Times are RFC 3339 and durations nanoseconds.
+type ToolStatus struct {
+	Name         string                      `json:"name"`                   // Name the caller manages the tool by
+	Installed    bool                        `json:"installed"`              // Whether a version is active
+	Installation *fileUtils.InstallationInfo `json:"installation,omitempty"` // Paths and symlink status of the active version
+	Versions     []string                    `json:"versions,omitempty"`     // Installed versions, if listed by the caller
+	Check        *UpdateCheck                `json:"check,omitempty"`        // Latest release compared with the installed version, if checked
+	Result       *InstallResult              `json:"result,omitempty"`       // What was downloaded and installed, if anything
+	Error        *StatusError                `json:"error,omitempty"`        // Why checking or installing failed
+}
+
+// StatusError is an error with its code, as reported in a ToolStatus
+type StatusError struct {
+	Message string              `json:"message"`
+	Code    fileUtils.ErrorCode `json:"code"` // See fileUtils.ErrorCodeOf
+}
+
+// NewStatusError describes err, or returns nil if err is nil
+func NewStatusError(err error) *StatusError {
+	if err == nil {
+		return nil
+	}
+	return &StatusError{Message: err.Error(), Code: fileUtils.ErrorCodeOf(err)}
+}
+
+// NewToolStatus describes the active installation of the tool installed with config. Callers
+// add the Check, Result or Error of what they did.
+func NewToolStatus(name string, config fileUtils.FileConfig) ToolStatus {
+	status := ToolStatus{Name: name}
+	if info, err := fileUtils.GetActiveInstallationInfo(config); err == nil {
+		status.Installed = true
+		status.Installation = info
+	}
+	return status
+}
+
+// StatusReport lists the status of several tools, in the order they were given
+type StatusReport struct {
+	Tools []ToolStatus `json:"tools"`
+}
+
+// MarshalJSON encodes a report without tools as an empty list rather than null. HTML characters
+// are escaped (or not) by the calling encoder.
+func (r StatusReport) MarshalJSON() ([]byte, error) {
+	type report StatusReport // Without this method
+	if r.Tools == nil {
+		r.Tools = []ToolStatus{}
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(report(r)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteJSON writes v, e.g. a StatusReport, as indented JSON followed by a newline. URLs are
+// written as they are instead of escaping &, < and >.
+func WriteJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,81 @@
+package release
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestToolStatus_JSON(t *testing.T) {
+	tempDir := t.TempDir()
+	config := fileUtils.FileConfig{
+		BaseBinaryDirectory:    filepath.Join(tempDir, "bin"),
+		VersionedDirectoryName: "versions",
+		BinaryName:             "tool",
+		CreateLocalSymlink:     true,
+	}
+	missing := NewToolStatus("missing", config)
+	if missing.Installed || missing.Installation != nil {
+		t.Errorf("Expected nothing to be installed, got %+v", missing)
+	}
+
+	source := filepath.Join(tempDir, "tool-1.0.0")
+	os.WriteFile(source, []byte("version 1.0.0"), 0755)
+	if err := fileUtils.InstallFromFile(config, source, "1.0.0", nil); err != nil {
+		t.Fatalf("InstallFromFile() error = %v", err)
+	}
+	installed := NewToolStatus("tool", config)
+	installed.Check = &UpdateCheck{CurrentVersion: "1.0.0", LatestVersion: "1.1.0", UpdateAvailable: true,
+		AssetURL: "https://example.com/tool?a=1&b=2"}
+	missing.Error = NewStatusError(fmt.Errorf("%w: 1.0.0", fileUtils.ErrVersionNotInstalled))
+
+	var output bytes.Buffer
+	if err := WriteJSON(&output, StatusReport{Tools: []ToolStatus{installed, missing}}); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+	if !strings.Contains(output.String(), "a=1&b=2") {
+		t.Errorf("Expected URLs to be written unescaped, got:\n%s", output.String())
+	}
+	var document struct {
+		Tools []struct {
+			Name         string `json:"name"`
+			Installed    bool   `json:"installed"`
+			Installation struct {
+				Version          string `json:"version"`
+				SymlinkStatus    string `json:"symlink_status"`
+				LocalSymlinkPath string `json:"local_symlink_path"`
+				VersionedPath    string `json:"versioned_path"`
+			} `json:"installation"`
+			Check struct {
+				UpdateAvailable bool `json:"update_available"`
+			} `json:"check"`
+			Error *StatusError `json:"error"`
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal(output.Bytes(), &document); err != nil {
+		t.Fatalf("Invalid JSON: %v\n%s", err, output.String())
+	}
+	if len(document.Tools) != 2 {
+		t.Fatalf("Expected 2 tools, got:\n%s", output.String())
+	}
+	tool := document.Tools[0]
+	if !tool.Installed || tool.Installation.Version != "1.0.0" || tool.Installation.SymlinkStatus != "created" ||
+		tool.Installation.LocalSymlinkPath != filepath.Join(config.BaseBinaryDirectory, "tool") ||
+		tool.Installation.VersionedPath == "" || !tool.Check.UpdateAvailable || tool.Error != nil {
+		t.Errorf("Unexpected status of the installed tool:\n%s", output.String())
+	}
+	if errorStatus := document.Tools[1].Error; errorStatus == nil || errorStatus.Code != fileUtils.ErrorCodeNoMatch {
+		t.Errorf("Expected the error and its code, got:\n%s", output.String())
+	}
+
+	output.Reset()
+	WriteJSON(&output, StatusReport{})
+	if got := strings.Join(strings.Fields(output.String()), ""); got != `{"tools":[]}` {
+		t.Errorf("Expected an empty list of tools, got %s", got)
+	}
+}
@@ -0,0 +1,92 @@
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/schema"
+	"strings"
+)
+
+// strategyNames are the names of the strategies in FileConfig.AssetMatchingStrategy and JSON
+var strategyNames = map[AssetMatchingStrategy]string{
+	StandardStrategy: "standard",
+	FlexibleStrategy: "flexible",
+	CustomStrategy:   "custom",
+	CDNStrategy:      "cdn",
+	HybridStrategy:   "hybrid",
+}
+
+// ParseStrategy returns the strategy named "standard", "flexible", "custom", "cdn" or "hybrid",
+// ignoring case. An empty name selects the default, FlexibleStrategy.
+func ParseStrategy(name string) (AssetMatchingStrategy, error) {
+	if name == "" {
+		return FlexibleStrategy, nil
+	}
+	for strategy, strategyName := range strategyNames {
+		if strings.EqualFold(name, strategyName) {
+			return strategy, nil
+		}
+	}
+	return FlexibleStrategy, fmt.Errorf("unknown asset matching strategy %q (use standard, flexible, custom, cdn or hybrid)", name)
+}
+
+// String returns the name of the strategy, e.g. "flexible"
+func (s AssetMatchingStrategy) String() string {
+	if name, ok := strategyNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("unknown (%d)", int(s))
+}
+
+// MarshalJSON encodes the strategy by name
+func (s AssetMatchingStrategy) MarshalJSON() ([]byte, error) {
+	if _, ok := strategyNames[s]; !ok {
+		return nil, fmt.Errorf("unknown asset matching strategy %d", int(s))
+	}
+	return json.Marshal(s.String())
+}
+
+// UnmarshalJSON decodes a strategy name, or the number older configurations stored
+func (s *AssetMatchingStrategy) UnmarshalJSON(data []byte) error {
+	var number int
+	if err := json.Unmarshal(data, &number); err == nil {
+		*s = AssetMatchingStrategy(number)
+		return nil
+	}
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf("asset matching strategy must be a name or a number: %v", err)
+	}
+	strategy, err := ParseStrategy(name)
+	if err != nil {
+		return err
+	}
+	*s = strategy
+	return nil
+}
+
+// JSONSchema describes the accepted encodings: a name or the number of the strategy
+func (AssetMatchingStrategy) JSONSchema() schema.Schema {
+	var names []string
+	for strategy := StandardStrategy; strategy <= HybridStrategy; strategy++ {
+		names = append(names, strategy.String())
+	}
+	return schema.Schema{"oneOf": []schema.Schema{
+		{"enum": names},
+		{"type": "integer", "minimum": int(StandardStrategy), "maximum": int(HybridStrategy)},
+	}}
+}
+
+// assetConfigFromFileConfig returns the asset matching configuration of the constructors that only
+// take a FileConfig. Unknown strategy names fall back to FlexibleStrategy.
+func assetConfigFromFileConfig(fileConfig fileUtils.FileConfig) AssetMatchingConfig {
+	assetConfig := DefaultAssetMatchingConfig()
+	assetConfig.ProjectName = fileConfig.ProjectName
+	assetConfig.IsDirectBinary = fileConfig.IsDirectBinary
+	assetConfig.Strategy, _ = ParseStrategy(fileConfig.AssetMatchingStrategy)
+	if assetConfig.Strategy == CustomStrategy {
+		assetConfig.CustomPatterns = fileConfig.CustomAssetPatterns
+	}
+	return assetConfig
+}
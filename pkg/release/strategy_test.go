@@ -0,0 +1,74 @@
+package release
+
+import (
+	"encoding/json"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"testing"
+)
+
+func TestParseStrategy(t *testing.T) {
+	for strategy := StandardStrategy; strategy <= HybridStrategy; strategy++ {
+		if parsed, err := ParseStrategy(strategy.String()); err != nil || parsed != strategy {
+			t.Errorf("ParseStrategy(%q) = %v, %v, want %v", strategy.String(), parsed, err, strategy)
+		}
+	}
+	if parsed, err := ParseStrategy("CDN"); err != nil || parsed != CDNStrategy {
+		t.Errorf("ParseStrategy(\"CDN\") = %v, %v, want cdn", parsed, err)
+	}
+	if parsed, err := ParseStrategy(""); err != nil || parsed != FlexibleStrategy {
+		t.Errorf("ParseStrategy(\"\") = %v, %v, want the flexible default", parsed, err)
+	}
+	if _, err := ParseStrategy("fuzzy"); err == nil {
+		t.Error("Expected an unknown strategy to be refused")
+	}
+	if name := AssetMatchingStrategy(42).String(); name != "unknown (42)" {
+		t.Errorf("String() of an unknown strategy = %q", name)
+	}
+}
+
+func TestAssetMatchingStrategy_JSON(t *testing.T) {
+	data, err := json.Marshal(AssetMatchingConfig{Strategy: CustomStrategy})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	var decoded struct {
+		Strategy string `json:"strategy"`
+	}
+	if json.Unmarshal(data, &decoded); decoded.Strategy != "custom" {
+		t.Errorf("Expected the strategy to be encoded by name, got %s", data)
+	}
+
+	for _, input := range []string{`{"strategy": "hybrid"}`, `{"strategy": 4}`} {
+		var config AssetMatchingConfig
+		if err := json.Unmarshal([]byte(input), &config); err != nil || config.Strategy != HybridStrategy {
+			t.Errorf("Unmarshal(%s) = %v, %v, want hybrid", input, config.Strategy, err)
+		}
+	}
+	var config AssetMatchingConfig
+	if err := json.Unmarshal([]byte(`{"strategy": "fuzzy"}`), &config); err == nil {
+		t.Error("Expected an unknown strategy name to be refused")
+	}
+}
+
+func TestConstructors_AssetMatchingStrategy(t *testing.T) {
+	fileConfig := fileUtils.FileConfig{ProjectName: "tool", AssetMatchingStrategy: "Custom", CustomAssetPatterns: []string{"tool-{OS}"}}
+	configs := map[string]AssetMatchingConfig{
+		"NewGithubRelease":           NewGithubRelease("owner/tool", fileConfig).AssetMatchingConfig,
+		"NewGitlabRelease":           NewGitlabRelease("1", fileConfig).AssetMatchingConfig,
+		"NewGitlabReleaseWithConfig": NewGitlabReleaseWithConfig("1", fileConfig, DefaultGitLabConfig()).AssetMatchingConfig,
+	}
+	for name, config := range configs {
+		if config.Strategy != CustomStrategy || len(config.CustomPatterns) != 1 || config.ProjectName != "tool" {
+			t.Errorf("%s: expected the custom strategy with its patterns, got %v, %v", name, config.Strategy, config.CustomPatterns)
+		}
+	}
+
+	fileConfig.AssetMatchingStrategy = "hybrid"
+	if strategy := NewGitlabReleaseWithConfig("1", fileConfig, DefaultGitLabConfig()).AssetMatchingConfig.Strategy; strategy != HybridStrategy {
+		t.Errorf("Expected every constructor to support the hybrid strategy, got %v", strategy)
+	}
+	fileConfig.AssetMatchingStrategy = "fuzzy"
+	if strategy := NewGithubRelease("owner/tool", fileConfig).AssetMatchingConfig.Strategy; strategy != FlexibleStrategy {
+		t.Errorf("Expected unknown names to fall back to flexible, got %v", strategy)
+	}
+}
@@ -0,0 +1,173 @@
+package release
+
+import (
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"net/url"
+	"path"
+	"regexp"
+)
+
+// Validate checks the asset matching configuration for conflicting flags, missing CDN settings and
+// invalid patterns without any network call. Every problem is reported in the returned
+// fileUtils.ErrInvalidConfig.
+func (c AssetMatchingConfig) Validate() error {
+	return fileUtils.InvalidConfigError(c.problems())
+}
+
+// problems implements Validate
+func (c AssetMatchingConfig) problems() []string {
+	var problems []string
+	add := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if c.Strategy < StandardStrategy || c.Strategy > HybridStrategy {
+		add("strategy must be between %d (standard) and %d (hybrid), got %d", StandardStrategy, HybridStrategy, c.Strategy)
+	}
+	if c.Strategy == CustomStrategy && len(c.CustomPatterns) == 0 {
+		add("the custom strategy requires custom_patterns")
+	}
+	problems = append(problems, fileUtils.CheckPatterns("custom_patterns", c.CustomPatterns)...)
+	problems = append(problems, fileUtils.CheckPatterns("exclude_patterns", c.ExcludePatterns)...)
+	problems = append(problems, fileUtils.CheckPatterns("priority_patterns", c.PriorityPatterns)...)
+	for _, text := range c.AssetNameTemplates {
+		if _, err := parseAssetTemplate(text); err != nil {
+			add("asset_name_templates: invalid template %q: %v", text, err)
+		}
+	}
+	for _, text := range c.AssetLabelTemplates {
+		if _, err := parseAssetTemplate(text); err != nil {
+			add("asset_label_templates: invalid template %q: %v", text, err)
+		}
+	}
+
+	if err := ValidateCDNConfig(c); err != nil {
+		add("%v", err)
+	}
+	for _, baseURL := range append([]string{c.CDNBaseURL}, c.CDNMirrors...) {
+		if baseURL != "" && !isHTTPURL(baseURL) {
+			add("CDN URL %q must be an http or https URL", baseURL)
+		}
+	}
+	if c.CDNHTTPConfig != nil {
+		problems = append(problems, c.CDNHTTPConfig.problems("cdn_http_config.")...)
+	}
+	if discovery := c.CDNVersionDiscovery; discovery != nil {
+		if !isHTTPURL(discovery.URL) {
+			add("cdn_version_discovery.url must be an http or https URL, got %q", discovery.URL)
+		}
+		switch discovery.Format {
+		case "", DiscoveryText, DiscoveryJSON, DiscoveryHTML, DiscoveryGCS:
+		default:
+			add("cdn_version_discovery.format must be text, json, html or gcs, got %q", discovery.Format)
+		}
+		if _, err := regexp.Compile(discovery.Pattern); err != nil {
+			add("cdn_version_discovery.pattern: invalid pattern %q: %v", discovery.Pattern, err)
+		}
+	}
+
+	if extraction := c.ExtractionConfig; extraction != nil {
+		if c.IsDirectBinary {
+			add("extraction_config cannot be used with is_direct_binary, which installs the asset without extracting it")
+		}
+		if extraction.StripComponents < 0 || extraction.Concurrency < 0 || extraction.MaxFileSize < 0 || extraction.MaxTotalSize < 0 {
+			add("extraction_config strip_components, concurrency, max_file_size and max_total_size must not be negative")
+		}
+		for _, pattern := range append(append([]string{}, extraction.Include...), extraction.Exclude...) {
+			if _, err := path.Match(pattern, ""); err != nil {
+				add("extraction_config: invalid glob %q: %v", pattern, err)
+			}
+		}
+		if _, err := path.Match(extraction.BinaryPath, ""); err != nil {
+			add("extraction_config.binary_path: invalid glob %q: %v", extraction.BinaryPath, err)
+		}
+	}
+
+	switch c.UniversalBinaryPreference {
+	case "", UniversalBinaryPrefer, UniversalBinaryAvoid:
+	default:
+		add("universal_binary_preference must be prefer or avoid, got %q", c.UniversalBinaryPreference)
+	}
+	switch c.AppImagePreference {
+	case "", AppImagePrefer, AppImageAvoid:
+	default:
+		add("appimage_preference must be prefer or avoid, got %q", c.AppImagePreference)
+	}
+	switch c.ARMVersion {
+	case "", "5", "6", "7":
+	default:
+		add("arm_version must be 5, 6 or 7, got %q", c.ARMVersion)
+	}
+	return problems
+}
+
+// Validate checks the GitLab configuration without any network call. Every problem is reported in
+// the returned fileUtils.ErrInvalidConfig.
+func (c GitLabConfig) Validate() error {
+	var problems []string
+	if !isHTTPURL(c.BaseURL) {
+		problems = append(problems, fmt.Sprintf("BaseURL must be an http or https URL, got %q", c.BaseURL))
+	}
+	if c.PerPage < 0 || c.PerPage > DefaultGitLabPerPage {
+		problems = append(problems, fmt.Sprintf("per_page must be between 0 and %d, got %d", DefaultGitLabPerPage, c.PerPage))
+	}
+	if c.MaxPages < 0 || c.MaxMetadataSize < 0 {
+		problems = append(problems, "max_pages and max_metadata_size must not be negative")
+	}
+	if c.Credentials != nil {
+		if _, err := c.Credentials.Provider(); err != nil {
+			problems = append(problems, fmt.Sprintf("credentials: %v", err))
+		}
+	}
+	problems = append(problems, c.HTTPConfig.problems("HTTPConfig.")...)
+	return fileUtils.InvalidConfigError(problems)
+}
+
+// Validate checks the selection policy and tag pattern. Every problem is reported in the returned
+// fileUtils.ErrInvalidConfig.
+func (s ReleaseSelection) Validate() error {
+	var problems []string
+	switch s.Policy {
+	case SelectByDefault, SelectByDate, SelectBySemver:
+	case SelectByTagRegex:
+		if s.TagPattern == "" {
+			problems = append(problems, "the regex policy requires tag_pattern")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("policy must be date, semver or regex, got %q", s.Policy))
+	}
+	if _, err := regexp.Compile(s.TagPattern); err != nil {
+		problems = append(problems, fmt.Sprintf("tag_pattern: invalid pattern %q: %v", s.TagPattern, err))
+	}
+	return fileUtils.InvalidConfigError(problems)
+}
+
+// Validate checks the retry settings. Every problem is reported in the returned
+// fileUtils.ErrInvalidConfig.
+func (c HTTPClientConfig) Validate() error {
+	return fileUtils.InvalidConfigError(c.problems(""))
+}
+
+// problems implements Validate, prefixing the field names
+func (c HTTPClientConfig) problems(prefix string) []string {
+	var problems []string
+	if c.MaxRetries < 0 || c.InitialDelay < 0 || c.MaxDelay < 0 || c.Timeout < 0 || c.RateLimitDelay < 0 || c.BackoffFactor < 0 {
+		problems = append(problems, fmt.Sprintf("%sMaxRetries, the delays, Timeout and BackoffFactor must not be negative", prefix))
+	}
+	if c.MaxDelay > 0 && c.InitialDelay > c.MaxDelay {
+		problems = append(problems, fmt.Sprintf("%sInitialDelay (%v) must not exceed %sMaxDelay (%v)", prefix, c.InitialDelay, prefix, c.MaxDelay))
+	}
+	switch c.Jitter {
+	case "", JitterNone, JitterFull, JitterEqual:
+	default:
+		problems = append(problems, fmt.Sprintf("%sJitter must be none, full or equal, got %q", prefix, c.Jitter))
+	}
+	return problems
+}
+
+// isHTTPURL reports whether value is an absolute http or https URL
+func isHTTPURL(value string) bool {
+	parsed, err := url.Parse(value)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
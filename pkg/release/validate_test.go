@@ -0,0 +1,92 @@
+package release
+
+import (
+	"errors"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultsAndPresets_Validate(t *testing.T) {
+	if err := DefaultAssetMatchingConfig().Validate(); err != nil {
+		t.Errorf("DefaultAssetMatchingConfig().Validate() error = %v", err)
+	}
+	if err := DefaultGitLabConfig().Validate(); err != nil {
+		t.Errorf("DefaultGitLabConfig().Validate() error = %v", err)
+	}
+	if err := DefaultCDNHTTPClientConfig().Validate(); err != nil {
+		t.Errorf("DefaultCDNHTTPClientConfig().Validate() error = %v", err)
+	}
+	for _, name := range DefaultPresetRegistry.Names() {
+		config, _ := DefaultPresetRegistry.Get(name)
+		if err := config.Validate(); err != nil {
+			t.Errorf("Preset %s Validate() error = %v", name, err)
+		}
+	}
+}
+
+func TestAssetMatchingConfig_Validate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		modify   func(*AssetMatchingConfig)
+		expected string
+	}{
+		{"Strategy", func(c *AssetMatchingConfig) { c.Strategy = 9 }, "strategy must be between"},
+		{"CustomWithoutPatterns", func(c *AssetMatchingConfig) { c.Strategy = CustomStrategy }, "requires custom_patterns"},
+		{"InvalidRegex", func(c *AssetMatchingConfig) { c.ExcludePatterns = append(c.ExcludePatterns, "*.sig") }, "exclude_patterns"},
+		{"DirectBinaryExtraction", func(c *AssetMatchingConfig) {
+			c.IsDirectBinary = true
+			c.ExtractionConfig = &ExtractionConfig{BinaryPath: "bin/tool"}
+		}, "cannot be used with is_direct_binary"},
+		{"ExtractionGlob", func(c *AssetMatchingConfig) { c.ExtractionConfig = &ExtractionConfig{Include: []string{"bin/["}} }, "invalid glob"},
+		{"BinaryPathGlob", func(c *AssetMatchingConfig) { c.ExtractionConfig = &ExtractionConfig{BinaryPath: "[/tool"} }, "binary_path"},
+		{"CDNWithoutURL", func(c *AssetMatchingConfig) { c.Strategy = CDNStrategy }, "requires CDNBaseURL"},
+		{"CDNMirror", func(c *AssetMatchingConfig) { c.CDNMirrors = []string{"mirror.example.com"} }, "must be an http or https URL"},
+		{"CDNHTTPConfig", func(c *AssetMatchingConfig) { c.CDNHTTPConfig = &HTTPClientConfig{Jitter: "random"} }, "cdn_http_config.Jitter"},
+		{"Preference", func(c *AssetMatchingConfig) { c.AppImagePreference = "always" }, "appimage_preference"},
+		{"ARMVersion", func(c *AssetMatchingConfig) { c.ARMVersion = "8" }, "arm_version"},
+		{"NameTemplate", func(c *AssetMatchingConfig) { c.AssetNameTemplates = []string{"{{.Project}_{{.OS}}"} }, "asset_name_templates"},
+		{"LabelTemplate", func(c *AssetMatchingConfig) { c.AssetLabelTemplates = []string{"{{.Platform}}"} }, "asset_label_templates"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := DefaultAssetMatchingConfig()
+			tc.modify(&config)
+			err := config.Validate()
+			if !errors.Is(err, fileUtils.ErrInvalidConfig) || !strings.Contains(err.Error(), tc.expected) {
+				t.Errorf("Validate() error = %v, want ErrInvalidConfig mentioning %q", err, tc.expected)
+			}
+		})
+	}
+}
+
+func TestGitLabConfig_Validate(t *testing.T) {
+	config := DefaultGitLabConfig()
+	config.BaseURL = "gitlab.example.com"
+	config.PerPage = 500
+	config.Credentials = &CredentialConfig{Source: "vault"}
+	config.HTTPConfig.InitialDelay = time.Minute
+	err := config.Validate()
+	if !errors.Is(err, fileUtils.ErrInvalidConfig) {
+		t.Fatalf("Validate() error = %v, want ErrInvalidConfig", err)
+	}
+	for _, expected := range []string{"BaseURL", "per_page", "credentials", "HTTPConfig.InitialDelay"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected the error to mention %s, got %v", expected, err)
+		}
+	}
+}
+
+func TestReleaseSelection_Validate(t *testing.T) {
+	if err := (ReleaseSelection{TagPrefix: "toolA/", Policy: SelectBySemver}).Validate(); err != nil {
+		t.Errorf("Validate() = %v, want nil", err)
+	}
+	err := ReleaseSelection{Policy: "newest", TagPattern: "("}.Validate()
+	if !errors.Is(err, fileUtils.ErrInvalidConfig) || !strings.Contains(err.Error(), "policy") || !strings.Contains(err.Error(), "tag_pattern") {
+		t.Errorf("Validate() error = %v, want policy and tag_pattern problems", err)
+	}
+	if err := (ReleaseSelection{Policy: SelectByTagRegex}).Validate(); err == nil {
+		t.Error("Expected the regex policy to require tag_pattern")
+	}
+}
@@ -0,0 +1,384 @@
+package release
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SemVersion is a parsed semantic version. Tag prefixes such as "v" or "kustomize/v" are ignored.
+type SemVersion struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string // e.g. "rc.1" (empty for stable releases)
+	Original   string // Version string as it was parsed
+}
+
+// ParseSemVersion parses versions like "1.2.3", "v1.2", "1.2.3-rc.1+build" or "kustomize/v5.3.0".
+// Missing minor and patch components default to 0.
+func ParseSemVersion(version string) (SemVersion, error) {
+	v := SemVersion{Original: version}
+
+	s := strings.TrimSpace(version)
+	if idx := strings.LastIndex(s, "/"); idx >= 0 {
+		s = s[idx+1:]
+	}
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "v"), "V")
+	s, _, _ = strings.Cut(s, "+") // Build metadata does not affect precedence
+	s, v.Prerelease, _ = strings.Cut(s, "-")
+
+	parts := strings.Split(s, ".")
+	if len(parts) == 0 || len(parts) > 3 || parts[0] == "" {
+		return SemVersion{}, fmt.Errorf("invalid version: %s", version)
+	}
+
+	numbers := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return SemVersion{}, fmt.Errorf("invalid version: %s", version)
+		}
+		*numbers[i] = n
+	}
+	return v, nil
+}
+
+// Compare returns -1, 0 or 1 depending on whether v is lower than, equal to or greater than other
+func (v SemVersion) Compare(other SemVersion) int {
+	for _, pair := range [][2]int{{v.Major, other.Major}, {v.Minor, other.Minor}, {v.Patch, other.Patch}} {
+		if pair[0] != pair[1] {
+			if pair[0] < pair[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return comparePrerelease(v.Prerelease, other.Prerelease)
+}
+
+// IsNewerVersion reports whether latest is newer than installed. Versions that are not semantic
+// versions are considered newer whenever they differ.
+func IsNewerVersion(latest, installed string) bool {
+	if installed == "" {
+		return latest != ""
+	}
+	latestVersion, err1 := ParseSemVersion(latest)
+	installedVersion, err2 := ParseSemVersion(installed)
+	if err1 != nil || err2 != nil {
+		return latest != installed
+	}
+	return latestVersion.Compare(installedVersion) > 0
+}
+
+// comparePrerelease compares prerelease identifiers following semver precedence rules
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1 // A stable release is greater than any of its prereleases
+	case b == "":
+		return -1
+	}
+
+	aParts, bParts := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		aNum, aErr := strconv.Atoi(aParts[i])
+		bNum, bErr := strconv.Atoi(bParts[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if aNum != bNum {
+				if aNum < bNum {
+					return -1
+				}
+				return 1
+			}
+		case aErr == nil:
+			return -1 // Numeric identifiers have lower precedence
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(aParts[i], bParts[i]); c != 0 {
+				return c
+			}
+		}
+	}
+
+	switch {
+	case len(aParts) < len(bParts):
+		return -1
+	case len(aParts) > len(bParts):
+		return 1
+	}
+	return 0
+}
+
+// versionComparator is a single "<op> <version>" condition
+type versionComparator struct {
+	op      string
+	version SemVersion
+}
+
+func (c versionComparator) matches(v SemVersion) bool {
+	cmp := v.Compare(c.version)
+	switch c.op {
+	case "=":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	return false
+}
+
+// VersionConstraint restricts which releases may be installed, e.g. "^1.28", "~3.12",
+// ">=1.2, <2", "1.4.x" or "^1 || ^2". Prereleases only match when the constraint names one.
+type VersionConstraint struct {
+	raw    string
+	groups [][]versionComparator // Alternatives (||), each a set of comparators that must all match
+}
+
+// ParseVersionConstraint parses a version constraint expression
+func ParseVersionConstraint(constraint string) (*VersionConstraint, error) {
+	c := &VersionConstraint{raw: constraint}
+
+	for _, alternative := range strings.Split(constraint, "||") {
+		terms := strings.Fields(strings.ReplaceAll(alternative, ",", " "))
+		if len(terms) == 0 {
+			return nil, fmt.Errorf("invalid version constraint %q: empty expression", constraint)
+		}
+
+		var group []versionComparator
+		for i := 0; i < len(terms); i++ {
+			term := terms[i]
+			// Allow a space between operator and version (">= 1.2")
+			if isConstraintOperator(term) && i+1 < len(terms) {
+				i++
+				term += terms[i]
+			}
+
+			comparators, err := parseConstraintTerm(term)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version constraint %q: %v", constraint, err)
+			}
+			group = append(group, comparators...)
+		}
+		c.groups = append(c.groups, group)
+	}
+
+	return c, nil
+}
+
+// isConstraintOperator reports whether a term is only an operator
+func isConstraintOperator(term string) bool {
+	switch term {
+	case "=", "!=", ">", ">=", "<", "<=", "^", "~":
+		return true
+	}
+	return false
+}
+
+// parseConstraintTerm expands a single term (operator, caret, tilde or wildcard) into comparators
+func parseConstraintTerm(term string) ([]versionComparator, error) {
+	if term == "*" || term == "x" || term == "X" {
+		return nil, nil
+	}
+
+	op := ""
+	for _, candidate := range []string{">=", "<=", "!=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(term, candidate) {
+			op = candidate
+			term = strings.TrimPrefix(term, candidate)
+			break
+		}
+	}
+
+	// Count the explicit components and treat x/* wildcards as missing ones
+	core, _, _ := strings.Cut(strings.TrimPrefix(term, "v"), "-")
+	core, _, _ = strings.Cut(core, "+")
+	specified := 0
+	for _, part := range strings.Split(core, ".") {
+		if part == "x" || part == "X" || part == "*" {
+			break
+		}
+		specified++
+	}
+	parts := strings.Split(core, ".")
+	if specified < len(parts) {
+		term = strings.Join(parts[:specified], ".")
+		if specified == 0 {
+			return nil, nil // Full wildcard
+		}
+	}
+
+	version, err := ParseSemVersion(term)
+	if err != nil {
+		return nil, err
+	}
+
+	// Upper bound of a partial version: "1.2" covers [1.2.0, 1.3.0)
+	next := func(level int) SemVersion {
+		switch level {
+		case 1:
+			return SemVersion{Major: version.Major + 1}
+		case 2:
+			return SemVersion{Major: version.Major, Minor: version.Minor + 1}
+		}
+		return SemVersion{Major: version.Major, Minor: version.Minor, Patch: version.Patch + 1}
+	}
+	base := SemVersion{Major: version.Major, Minor: version.Minor, Patch: version.Patch, Prerelease: version.Prerelease}
+
+	switch op {
+	case "^":
+		// Allow changes that do not modify the left-most non-zero component
+		upper := next(1)
+		if version.Major == 0 && specified > 1 {
+			upper = next(2)
+			if version.Minor == 0 && specified > 2 {
+				upper = next(3)
+			}
+		}
+		return []versionComparator{{">=", base}, {"<", upper}}, nil
+	case "~":
+		// Allow patch-level changes if a minor version is specified, minor-level changes otherwise
+		upper := next(2)
+		if specified == 1 {
+			upper = next(1)
+		}
+		return []versionComparator{{">=", base}, {"<", upper}}, nil
+	case "", "=":
+		if specified < 3 {
+			return []versionComparator{{">=", base}, {"<", next(specified)}}, nil
+		}
+		return []versionComparator{{"=", base}}, nil
+	case ">":
+		if specified < 3 {
+			return []versionComparator{{">=", next(specified)}}, nil
+		}
+	case "<=":
+		if specified < 3 {
+			return []versionComparator{{"<", next(specified)}}, nil
+		}
+	}
+	return []versionComparator{{op, base}}, nil
+}
+
+// String returns the original constraint expression
+func (c *VersionConstraint) String() string {
+	return c.raw
+}
+
+// Check reports whether a version satisfies the constraint
+func (c *VersionConstraint) Check(version string) bool {
+	v, err := ParseSemVersion(version)
+	if err != nil {
+		return false
+	}
+
+	for _, group := range c.groups {
+		if v.Prerelease != "" && !allowsPrerelease(group, v) {
+			continue
+		}
+		matched := true
+		for _, comparator := range group {
+			if !comparator.matches(v) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsPrerelease reports whether a comparator group explicitly names a prerelease of the
+// same major.minor.patch, which is the only way prereleases can satisfy a constraint
+func allowsPrerelease(group []versionComparator, v SemVersion) bool {
+	for _, comparator := range group {
+		cv := comparator.version
+		if cv.Prerelease != "" && cv.Major == v.Major && cv.Minor == v.Minor && cv.Patch == v.Patch {
+			return true
+		}
+	}
+	return false
+}
+
+// Latest returns the highest release satisfying the constraint
+func (c *VersionConstraint) Latest(releases []ReleaseInfo) (*ReleaseInfo, error) {
+	var best *ReleaseInfo
+	var bestVersion SemVersion
+	for i := range releases {
+		if !c.Check(releases[i].Version) {
+			continue
+		}
+		version, _ := ParseSemVersion(releases[i].Version)
+		if best == nil || version.Compare(bestVersion) > 0 {
+			best = &releases[i]
+			bestVersion = version
+		}
+	}
+
+	if best == nil {
+		return nil, noMatchErrorf(ErrNoMatchingRelease, "no release matches version constraint %s", c.raw)
+	}
+	return best, nil
+}
+
+// latestReleaseMatching returns the latest release from the source according to the selection,
+// restricted to the constraint when one is set. Constraints and selection policies other than the
+// provider's latest release require a source implementing ReleaseLister.
+func latestReleaseMatching(source AssetSource, constraint string, selection ReleaseSelection) (*ReleaseInfo, error) {
+	if constraint == "" && selection.usesProviderLatest() {
+		return source.LatestRelease()
+	}
+
+	var parsed *VersionConstraint
+	if constraint != "" {
+		var err error
+		if parsed, err = ParseVersionConstraint(constraint); err != nil {
+			return nil, err
+		}
+	}
+
+	lister, ok := source.(ReleaseLister)
+	if !ok {
+		if parsed != nil {
+			return nil, fmt.Errorf("asset source cannot list releases, required for version constraint %s", constraint)
+		}
+		return nil, fmt.Errorf("asset source cannot list releases, required for selection policy %q", selection.Policy)
+	}
+
+	releases, err := lister.Releases()
+	if err != nil {
+		return nil, err
+	}
+	releases = selection.forTool(releases)
+	if parsed == nil {
+		return selection.Latest(releases)
+	}
+	releases = selection.withoutIgnored(releases)
+	if selection.Policy == SelectByDefault && selection.TagPattern == "" {
+		return parsed.Latest(releases)
+	}
+
+	var matching []ReleaseInfo
+	for _, release := range releases {
+		if parsed.Check(release.Version) {
+			matching = append(matching, release)
+		}
+	}
+	if len(matching) == 0 {
+		return nil, noMatchErrorf(ErrNoMatchingRelease, "no release matches version constraint %s", constraint)
+	}
+	return selection.Latest(matching)
+}
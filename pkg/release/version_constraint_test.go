@@ -0,0 +1,150 @@
+package release
+
+import (
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseSemVersion(t *testing.T) {
+	testCases := []struct {
+		input      string
+		expected   SemVersion
+		expectFail bool
+	}{
+		{input: "1.2.3", expected: SemVersion{Major: 1, Minor: 2, Patch: 3}},
+		{input: "v1.28", expected: SemVersion{Major: 1, Minor: 28}},
+		{input: "v2.0.0-rc.1+build.5", expected: SemVersion{Major: 2, Prerelease: "rc.1"}},
+		{input: "kustomize/v5.3.0", expected: SemVersion{Major: 5, Minor: 3}},
+		{input: "latest", expectFail: true},
+		{input: "1.2.3.4", expectFail: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			v, err := ParseSemVersion(tc.input)
+			if tc.expectFail {
+				if err == nil {
+					t.Errorf("Expected error parsing %s", tc.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSemVersion failed: %v", err)
+			}
+			v.Original = ""
+			if v != tc.expected {
+				t.Errorf("Expected %+v, got %+v", tc.expected, v)
+			}
+		})
+	}
+}
+
+func TestSemVersion_Compare(t *testing.T) {
+	ordered := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-beta", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.2.0", "2.0.0"}
+	for i := 0; i < len(ordered)-1; i++ {
+		a, _ := ParseSemVersion(ordered[i])
+		b, _ := ParseSemVersion(ordered[i+1])
+		if a.Compare(b) >= 0 || b.Compare(a) <= 0 {
+			t.Errorf("Expected %s < %s", ordered[i], ordered[i+1])
+		}
+	}
+}
+
+func TestVersionConstraint_Check(t *testing.T) {
+	testCases := []struct {
+		constraint string
+		version    string
+		expected   bool
+	}{
+		{"^1.28", "v1.28.4", true},
+		{"^1.28", "v1.30.0", true},
+		{"^1.28", "v1.27.9", false},
+		{"^1.28", "v2.0.0", false},
+		{"^0.4.2", "0.4.9", true},
+		{"^0.4.2", "0.5.0", false},
+		{"~3.12", "3.12.7", true},
+		{"~3.12", "3.13.0", false},
+		{"~3", "3.99.0", true},
+		{">=1.2, <2", "1.9.9", true},
+		{">= 1.2 < 2", "2.0.0", false},
+		{"1.4.x", "1.4.11", true},
+		{"1.4.x", "1.5.0", false},
+		{"1.4", "1.4.2", true},
+		{"*", "9.9.9", true},
+		{"^1 || ^3", "3.1.0", true},
+		{"^1 || ^3", "2.1.0", false},
+		{"=1.2.3", "v1.2.3", true},
+		{"!=1.2.3", "1.2.3", false},
+		{">1.2", "1.2.9", false},
+		{"<=1.2", "1.2.9", true},
+		{"^1.28", "v1.29.0-rc.1", false},
+		{">=1.29.0-rc.1", "v1.29.0-rc.2", true},
+		{"^1.28", "nightly", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.constraint+"_"+tc.version, func(t *testing.T) {
+			constraint, err := ParseVersionConstraint(tc.constraint)
+			if err != nil {
+				t.Fatalf("ParseVersionConstraint failed: %v", err)
+			}
+			if got := constraint.Check(tc.version); got != tc.expected {
+				t.Errorf("Check(%s) with %s: expected %v, got %v", tc.version, tc.constraint, tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseVersionConstraint_Invalid(t *testing.T) {
+	for _, constraint := range []string{"", "^abc", ">=1.2 ||", "1.2.3.4"} {
+		if _, err := ParseVersionConstraint(constraint); err == nil {
+			t.Errorf("Expected error for constraint %q", constraint)
+		}
+	}
+}
+
+func TestGithubRelease_VersionConstraint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/owner/repo/releases" {
+			t.Errorf("Expected release list request, got %s", r.URL.Path)
+		}
+		w.Write([]byte(`[
+			{"tag_name": "v2.0.0", "assets": [{"name": "myapp-Linux_x86_64.tar.gz", "browser_download_url": "https://example.com/v2"}]},
+			{"tag_name": "v1.29.0-rc.1", "assets": [{"name": "myapp-Linux_x86_64.tar.gz", "browser_download_url": "https://example.com/rc"}]},
+			{"tag_name": "v1.28.5", "assets": [{"name": "myapp-Linux_x86_64.tar.gz", "browser_download_url": "https://example.com/v1.28.5"}]},
+			{"tag_name": "v1.28.4", "assets": [{"name": "myapp-Linux_x86_64.tar.gz", "browser_download_url": "https://example.com/v1.28.4"}]}
+		]`))
+	}))
+	defer server.Close()
+
+	release := NewGithubRelease("owner/repo", fileUtils.FileConfig{ProjectName: "myapp"})
+	release.BaseURL = server.URL
+	release.VersionConstraint = "^1.28"
+
+	if err := release.GetLatestRelease(); err != nil {
+		t.Fatalf("GetLatestRelease failed: %v", err)
+	}
+	if release.Version != "v1.28.5" {
+		t.Errorf("Expected v1.28.5, got %s", release.Version)
+	}
+	if release.ReleaseLink != "https://example.com/v1.28.5" {
+		t.Errorf("Expected v1.28.5 asset link, got %s", release.ReleaseLink)
+	}
+
+	release.VersionConstraint = "^3"
+	if err := release.GetLatestRelease(); err == nil {
+		t.Error("Expected error when no release matches the constraint")
+	}
+}
+
+func TestVersionConstraint_RequiresReleaseLister(t *testing.T) {
+	release := NewGitlabRelease("123", fileUtils.FileConfig{ProjectName: "myapp"})
+	release.Source = &fakeAssetSource{release: fakeLinuxRelease()}
+	release.VersionConstraint = "^3"
+
+	if err := release.GetLatestRelease(); err == nil {
+		t.Error("Expected error when the asset source cannot list releases")
+	}
+}
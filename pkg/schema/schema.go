@@ -0,0 +1,137 @@
+// Package schema generates JSON Schemas describing how configuration structs are encoded with
+// encoding/json, so editors and CI can check configuration files before anything is downloaded.
+package schema
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// Draft is the JSON Schema dialect of the generated schemas
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema document or subschema
+type Schema map[string]any
+
+// Describer is implemented by types whose JSON encoding is not derived from their Go type, e.g.
+// enums encoded by name
+type Describer interface {
+	JSONSchema() Schema
+}
+
+var (
+	describerType     = reflect.TypeOf((*Describer)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// Generate returns the schema of the type of v, e.g. release.AssetMatchingConfig{}. Objects list
+// the field names written by encoding/json and reject other properties, so typos are reported;
+// encoding/json itself also accepts field names in a different case.
+func Generate(title string, v any) Schema {
+	schema := generate(reflect.TypeOf(v), map[reflect.Type]bool{})
+	schema["$schema"] = Draft
+	if title != "" {
+		schema["title"] = title
+	}
+	return schema
+}
+
+// MarshalIndent encodes the schema as indented JSON
+func (s Schema) MarshalIndent() ([]byte, error) {
+	return json.MarshalIndent(s, "", "  ")
+}
+
+// generate returns the schema of t. visiting holds the structs being generated, so recursive
+// types end in an unconstrained object.
+func generate(t reflect.Type, visiting map[reflect.Type]bool) Schema {
+	if t == nil {
+		return Schema{}
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Implements(describerType) {
+		return reflect.Zero(t).Interface().(Describer).JSONSchema()
+	}
+	if reflect.PointerTo(t).Implements(describerType) {
+		return reflect.New(t).Interface().(Describer).JSONSchema()
+	}
+	if t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return Schema{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		// time.Duration and os.FileMode are encoded as plain numbers
+		return Schema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return Schema{"type": "string", "contentEncoding": "base64"}
+		}
+		return Schema{"type": "array", "items": generate(t.Elem(), visiting)}
+	case reflect.Map:
+		return Schema{"type": "object", "additionalProperties": generate(t.Elem(), visiting)}
+	case reflect.Struct:
+		if visiting[t] {
+			return Schema{"type": "object"}
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+		properties := Schema{}
+		addFields(t, properties, visiting)
+		return Schema{"type": "object", "properties": properties, "additionalProperties": false}
+	}
+	return Schema{}
+}
+
+// addFields adds the properties of the struct's fields. Fields of embedded structs are added
+// after the struct's own fields, which take precedence as in encoding/json.
+func addFields(t reflect.Type, properties Schema, visiting map[reflect.Type]bool) {
+	var embedded []reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			embedded = append(embedded, fieldType)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		switch fieldType.Kind() {
+		case reflect.Func, reflect.Chan, reflect.UnsafePointer, reflect.Complex64, reflect.Complex128:
+			continue // encoding/json cannot encode these
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = generate(field.Type, visiting)
+	}
+
+	for _, embeddedType := range embedded {
+		fields := Schema{}
+		addFields(embeddedType, fields, visiting)
+		for name, property := range fields {
+			if _, ok := properties[name]; !ok {
+				properties[name] = property
+			}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testBase struct {
+	Name string `json:"name"`
+}
+
+type testNode struct {
+	Value    int `json:"value"`
+	Children []*testNode
+}
+
+type testMode int
+
+func (testMode) JSONSchema() Schema {
+	return Schema{"enum": []string{"fast", "safe"}}
+}
+
+type testConfig struct {
+	testBase
+	Name      string            `json:"name_override"`
+	Enabled   bool              `json:"enabled"`
+	Timeout   time.Duration     `json:"timeout"`
+	Ratio     float64           `json:"ratio,omitempty"`
+	Patterns  []string          `json:"patterns"`
+	Headers   map[string]string `json:"headers"`
+	Published time.Time         `json:"published"`
+	Tree      *testNode         `json:"tree"`
+	Mode      testMode          `json:"mode"`
+	Callback  func()            `json:"-"`
+	internal  string
+}
+
+func TestGenerate(t *testing.T) {
+	schema := Generate("Test", testConfig{})
+	if schema["$schema"] != Draft || schema["title"] != "Test" || schema["additionalProperties"] != false {
+		t.Errorf("Unexpected document keywords: %v", schema)
+	}
+
+	properties := schema["properties"].(Schema)
+	expected := map[string]Schema{
+		"name":          {"type": "string"},
+		"name_override": {"type": "string"},
+		"enabled":       {"type": "boolean"},
+		"timeout":       {"type": "integer"},
+		"ratio":         {"type": "number"},
+		"patterns":      {"type": "array", "items": Schema{"type": "string"}},
+		"headers":       {"type": "object", "additionalProperties": Schema{"type": "string"}},
+		"published":     {"type": "string"},
+		"mode":          {"enum": []string{"fast", "safe"}},
+	}
+	for name, want := range expected {
+		if got := properties[name]; !reflect.DeepEqual(got, want) {
+			t.Errorf("Property %s = %v, want %v", name, got, want)
+		}
+	}
+	for _, name := range []string{"Name", "Callback", "internal"} {
+		if _, ok := properties[name]; ok {
+			t.Errorf("Expected no property %s", name)
+		}
+	}
+
+	// Recursive types end in an unconstrained object
+	tree := properties["tree"].(Schema)["properties"].(Schema)
+	children := tree["Children"].(Schema)["items"].(Schema)
+	if !reflect.DeepEqual(children, Schema{"type": "object"}) {
+		t.Errorf("Expected the recursion to stop, got %v", children)
+	}
+
+	data, err := schema.MarshalIndent()
+	if err != nil || !json.Valid(data) {
+		t.Errorf("MarshalIndent() = %s, %v", data, err)
+	}
+}
@@ -0,0 +1,214 @@
+// Package testutil helps applications test their update flows without real GitHub or GitLab
+// APIs: FakeRelease stands in for a release provider, and Server is an httptest server speaking
+// the GitHub and GitLab release APIs with configurable releases, errors and rate limits.
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/release"
+	"slices"
+	"sync"
+)
+
+// FakeRelease is a release provider that downloads and installs nothing. It reports the Latest
+// version, "installs" it by making it the Installed version and records every call, so update
+// orchestration (e.g. updater.Scheduler) can be tested. It is safe for concurrent use; set the
+// fields before use.
+type FakeRelease struct {
+	Latest     string   // Version GetLatestRelease resolves
+	Installed  string   // Active version, "" if nothing is installed; replaced by installs
+	Versions   []string // Versions GetRelease knows besides Latest (nil accepts every version)
+	BinaryPath string   // Path reported for the installed binary
+
+	LatestErr   error // Returned by GetLatestRelease and GetRelease
+	DownloadErr error // Returned by DownloadLatestRelease and DownloadRelease
+	InstallErr  error // Returned by InstallLatestRelease and InstallRelease
+
+	mu         sync.Mutex
+	calls      []string
+	resolved   string // Version resolved by the last GetLatestRelease or GetRelease
+	downloaded string // Version downloaded by the last download
+}
+
+var (
+	_ release.Release          = (*FakeRelease)(nil)
+	_ release.VersionReporter  = (*FakeRelease)(nil)
+	_ release.VersionedRelease = (*FakeRelease)(nil)
+	_ release.ContextRelease   = (*FakeRelease)(nil)
+)
+
+// Calls returns the names of the methods called so far, in order, e.g. "InstallRelease v1.2.0"
+func (f *FakeRelease) Calls() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.calls)
+}
+
+// GetLatestRelease resolves Latest
+func (f *FakeRelease) GetLatestRelease() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, "GetLatestRelease")
+	return f.resolve(f.Latest)
+}
+
+// DownloadLatestRelease resolves and "downloads" Latest
+func (f *FakeRelease) DownloadLatestRelease() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, "DownloadLatestRelease")
+	return f.download(f.Latest)
+}
+
+// InstallLatestRelease makes the downloaded version the installed one
+func (f *FakeRelease) InstallLatestRelease() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, "InstallLatestRelease")
+	return f.install()
+}
+
+// GetRelease resolves version, failing for versions not in Versions
+func (f *FakeRelease) GetRelease(version string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, "GetRelease "+version)
+	return f.resolve(version)
+}
+
+// DownloadRelease resolves and "downloads" version
+func (f *FakeRelease) DownloadRelease(version string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, "DownloadRelease "+version)
+	return f.download(version)
+}
+
+// InstallRelease "downloads" version unless it was just downloaded and installs it
+func (f *FakeRelease) InstallRelease(version string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, "InstallRelease "+version)
+	if f.downloaded != version {
+		if err := f.download(version); err != nil {
+			return err
+		}
+	}
+	return f.install()
+}
+
+// GetLatestReleaseContext is GetLatestRelease, failing with the context's error once it is done
+func (f *FakeRelease) GetLatestReleaseContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return f.GetLatestRelease()
+}
+
+// DownloadLatestReleaseContext is DownloadLatestRelease, failing with the context's error once it is done
+func (f *FakeRelease) DownloadLatestReleaseContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return f.DownloadLatestRelease()
+}
+
+// InstallLatestReleaseContext is InstallLatestRelease, failing with the context's error once it is done
+func (f *FakeRelease) InstallLatestReleaseContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return f.InstallLatestRelease()
+}
+
+// InstallReleaseContext is InstallRelease, failing with the context's error once it is done
+func (f *FakeRelease) InstallReleaseContext(ctx context.Context, version string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return f.InstallRelease(version)
+}
+
+// GetInstalledBinaryPath returns BinaryPath once a version is installed
+func (f *FakeRelease) GetInstalledBinaryPath() (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Installed == "" {
+		return "", fmt.Errorf("no version installed")
+	}
+	return f.BinaryPath, nil
+}
+
+// GetInstallationInfo describes the installed version
+func (f *FakeRelease) GetInstallationInfo() (*fileUtils.InstallationInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Installed == "" {
+		return nil, fmt.Errorf("no version installed")
+	}
+	return &fileUtils.InstallationInfo{
+		BinaryPath:    f.BinaryPath,
+		Version:       f.Installed,
+		SymlinkStatus: "not_attempted",
+		VersionedPath: f.BinaryPath,
+	}, nil
+}
+
+// LatestVersion returns the version resolved by the last GetLatestRelease or GetRelease call
+func (f *FakeRelease) LatestVersion() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.resolved
+}
+
+// InstalledVersion returns Installed
+func (f *FakeRelease) InstalledVersion() (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Installed == "" {
+		return "", fmt.Errorf("no version installed")
+	}
+	return f.Installed, nil
+}
+
+// resolve checks that version exists and records it as resolved
+func (f *FakeRelease) resolve(version string) error {
+	if f.LatestErr != nil {
+		return f.LatestErr
+	}
+	if version == "" {
+		return fmt.Errorf("no release found")
+	}
+	if version != f.Latest && f.Versions != nil && !slices.Contains(f.Versions, version) {
+		return fmt.Errorf("release %s not found", version)
+	}
+	f.resolved = version
+	return nil
+}
+
+// download resolves version and records it as downloaded
+func (f *FakeRelease) download(version string) error {
+	f.downloaded = ""
+	if err := f.resolve(version); err != nil {
+		return err
+	}
+	if f.DownloadErr != nil {
+		return f.DownloadErr
+	}
+	f.downloaded = version
+	return nil
+}
+
+// install makes the downloaded version the installed one
+func (f *FakeRelease) install() error {
+	if f.InstallErr != nil {
+		return f.InstallErr
+	}
+	if f.downloaded == "" {
+		return fmt.Errorf("nothing downloaded to install")
+	}
+	f.Installed, f.downloaded = f.downloaded, ""
+	return nil
+}
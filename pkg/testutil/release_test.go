@@ -0,0 +1,57 @@
+package testutil
+
+import (
+	"context"
+	"errors"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/updater"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestFakeRelease_Scheduler(t *testing.T) {
+	fake := &FakeRelease{Latest: "v1.2.0", Installed: "v1.1.0", BinaryPath: "/opt/bin/myapp"}
+	scheduler := updater.NewScheduler(time.Hour, updater.Target{Name: "myapp", Release: fake, AutoInstall: true})
+
+	events := scheduler.CheckNow(context.Background())
+	if len(events) != 1 || events[0].Type != updater.EventInstalled || events[0].CurrentVersion != "v1.1.0" {
+		t.Fatalf("Expected v1.2.0 to be installed over v1.1.0, got %+v", events)
+	}
+	if version, _ := fake.InstalledVersion(); version != "v1.2.0" {
+		t.Errorf("Expected v1.2.0 to be installed, got %s", version)
+	}
+	expected := []string{"GetLatestRelease", "DownloadLatestRelease", "InstallLatestRelease"}
+	if calls := fake.Calls(); !slices.Equal(calls, expected) {
+		t.Errorf("Expected calls %v, got %v", expected, calls)
+	}
+
+	if events := scheduler.CheckNow(context.Background()); events[0].Type != updater.EventUpToDate {
+		t.Errorf("Expected the target to be up to date, got %+v", events[0])
+	}
+
+	fake.Latest = "v1.3.0"
+	fake.DownloadErr = errors.New("network down")
+	events = scheduler.CheckNow(context.Background())
+	if events[0].Type != updater.EventError || !errors.Is(events[0].Err, fake.DownloadErr) {
+		t.Errorf("Expected the download error, got %+v", events[0])
+	}
+	if version, _ := fake.InstalledVersion(); version != "v1.2.0" {
+		t.Errorf("Expected v1.2.0 to stay installed, got %s", version)
+	}
+}
+
+func TestFakeRelease_Versions(t *testing.T) {
+	fake := &FakeRelease{Latest: "v2.0.0", Versions: []string{"v1.0.0"}}
+	if err := fake.InstallRelease("v1.0.0"); err != nil {
+		t.Fatalf("InstallRelease() error = %v", err)
+	}
+	if version, _ := fake.InstalledVersion(); version != "v1.0.0" {
+		t.Errorf("Expected v1.0.0 to be installed, got %s", version)
+	}
+	if err := fake.InstallRelease("v0.9.0"); err == nil {
+		t.Error("Expected an error for an unknown version")
+	}
+	if _, err := (&FakeRelease{}).GetInstallationInfo(); err == nil {
+		t.Error("Expected an error without an installed version")
+	}
+}
@@ -0,0 +1,370 @@
+package testutil
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Release is a release served by Server
+type Release struct {
+	Tag         string
+	Name        string
+	Notes       string
+	Prerelease  bool      // GitHub only: skipped by the latest release endpoint
+	Draft       bool      // GitHub only: never listed
+	PublishedAt time.Time // Zero orders releases by when they were added
+	Assets      []Asset
+}
+
+// Asset is a release asset (GitHub) or release link (GitLab) served by Server
+type Asset struct {
+	Name     string
+	Label    string // GitHub only
+	LinkType string // GitLab only, e.g. "package"
+	Content  []byte
+}
+
+// Server is an httptest server answering the GitHub REST API below GitHubAPIURL and the GitLab
+// REST API below GitLabAPIURL with the releases added to it, and serving their assets. Failures
+// and rate limits can be injected. GitHub repositories are keyed by "owner/name" and GitLab
+// projects by their ID.
+//
+//	server := testutil.NewServer()
+//	defer server.Close()
+//	server.AddRelease("owner/tool", testutil.Release{Tag: "v1.0.0", Assets: []testutil.Asset{...}})
+//	provider := release.NewGithubRelease("owner/tool", fileConfig)
+//	provider.APIURL = server.GitHubAPIURL()
+type Server struct {
+	*httptest.Server
+
+	mu            sync.Mutex
+	releases      map[string][]*Release
+	assetIDs      map[*Asset]int // GitHub asset IDs, numbered from 1 as assets are added
+	failures      []int
+	rateLimited   bool
+	requests      []string
+	nextPublished time.Time // Publish time given to the next release added without one
+}
+
+// githubAPIPrefix and gitlabAPIPrefix are the paths of the API roots, as on self-hosted instances
+const (
+	githubAPIPrefix = "/api/v3"
+	gitlabAPIPrefix = "/api/v4"
+)
+
+// NewServer starts a server without releases. Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		releases:      make(map[string][]*Release),
+		assetIDs:      make(map[*Asset]int),
+		nextPublished: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	mux := http.NewServeMux()
+	github := "GET " + githubAPIPrefix + "/repos/{owner}/{repo}"
+	mux.HandleFunc(github+"/releases/latest", s.api(s.githubLatest))
+	mux.HandleFunc(github+"/releases/tags/{tag...}", s.api(s.githubRelease))
+	mux.HandleFunc(github+"/releases", s.api(s.githubReleases))
+	mux.HandleFunc(github+"/releases/assets/{id}", s.download)
+	mux.HandleFunc(github+"/tags", s.api(s.githubTags))
+	mux.HandleFunc("GET /{owner}/{repo}/releases/download/{tag}/{name}", s.download)
+
+	gitlab := "GET " + gitlabAPIPrefix + "/projects/{project}/releases"
+	mux.HandleFunc(gitlab, s.api(s.gitlabReleases))
+	mux.HandleFunc(gitlab+"/{tag}", s.api(s.gitlabRelease))
+	mux.HandleFunc(gitlab+"/{tag}/assets/links", s.api(s.gitlabLinks))
+	mux.HandleFunc("GET /gitlab/{project}/-/releases/{tag}/downloads/{name}", s.download)
+
+	s.Server = httptest.NewServer(s.record(mux))
+	return s
+}
+
+// GitHubAPIURL returns the API root to set as GithubRelease.APIURL
+func (s *Server) GitHubAPIURL() string {
+	return s.URL + githubAPIPrefix
+}
+
+// GitLabAPIURL returns the API root to set as GitLabConfig.BaseURL
+func (s *Server) GitLabAPIURL() string {
+	return s.URL + gitlabAPIPrefix
+}
+
+// AddRelease adds a release to a GitHub repository ("owner/name") or GitLab project ID. Releases
+// without PublishedAt are published an hour after the previously added one, so the last one
+// added is the latest.
+func (s *Server) AddRelease(repository string, release Release) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if release.PublishedAt.IsZero() {
+		s.nextPublished = s.nextPublished.Add(time.Hour)
+		release.PublishedAt = s.nextPublished
+	}
+	release.Assets = slices.Clone(release.Assets)
+	for i := range release.Assets {
+		s.assetIDs[&release.Assets[i]] = len(s.assetIDs) + 1
+	}
+	s.releases[repository] = append(s.releases[repository], &release)
+}
+
+// FailNext answers the next count requests with statusCode, e.g. 502 to test retries
+func (s *Server) FailNext(statusCode, count int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i < count; i++ {
+		s.failures = append(s.failures, statusCode)
+	}
+}
+
+// SetRateLimited makes API requests fail as when the rate limit is exhausted: GitHub requests
+// with 403 and X-RateLimit-* headers, GitLab requests with 429 and RateLimit-* headers. Both
+// match release.ErrRateLimited. Asset downloads are not limited. GitLab clients retry 429, so
+// lower their HTTPConfig.MaxRetries or RateLimitDelay to keep tests fast.
+func (s *Server) SetRateLimited(limited bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rateLimited = limited
+}
+
+// Requests returns the requests served so far, e.g. "GET /api/v3/repos/owner/tool/releases/latest"
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.requests)
+}
+
+// record logs each request and answers it with the next injected failure, if any
+func (s *Server) record(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.RequestURI())
+		failure := 0
+		if len(s.failures) > 0 {
+			failure, s.failures = s.failures[0], s.failures[1:]
+		}
+		s.mu.Unlock()
+		if failure != 0 {
+			http.Error(w, http.StatusText(failure), failure)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// api wraps an API handler with rate limiting and JSON encoding. Handlers return nil for 404.
+func (s *Server) api(handler func(r *http.Request) any) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		limited := s.rateLimited
+		s.mu.Unlock()
+		if limited {
+			reset := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+			if strings.HasPrefix(r.URL.Path, githubAPIPrefix) {
+				w.Header().Set("X-RateLimit-Limit", "60")
+				w.Header().Set("X-RateLimit-Remaining", "0")
+				w.Header().Set("X-RateLimit-Reset", reset)
+				http.Error(w, `{"message": "API rate limit exceeded"}`, http.StatusForbidden)
+			} else {
+				w.Header().Set("RateLimit-Limit", "60")
+				w.Header().Set("RateLimit-Remaining", "0")
+				w.Header().Set("RateLimit-Reset", reset)
+				http.Error(w, `{"message": "Retry later"}`, http.StatusTooManyRequests)
+			}
+			return
+		}
+
+		s.mu.Lock()
+		response := handler(r)
+		s.mu.Unlock()
+		if response == nil {
+			http.Error(w, `{"message": "Not Found"}`, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+// published returns the repository's releases, newest first, leaving out GitHub drafts
+func (s *Server) published(repository string) []*Release {
+	var releases []*Release
+	for _, release := range s.releases[repository] {
+		if !release.Draft {
+			releases = append(releases, release)
+		}
+	}
+	slices.SortStableFunc(releases, func(a, b *Release) int { return b.PublishedAt.Compare(a.PublishedAt) })
+	return releases
+}
+
+// find returns the repository's release tagged tag
+func (s *Server) find(repository, tag string) *Release {
+	for _, release := range s.releases[repository] {
+		if release.Tag == tag {
+			return release
+		}
+	}
+	return nil
+}
+
+// githubRepository returns the repository of a GitHub API request
+func githubRepository(r *http.Request) string {
+	return r.PathValue("owner") + "/" + r.PathValue("repo")
+}
+
+func (s *Server) githubLatest(r *http.Request) any {
+	repository := githubRepository(r)
+	for _, release := range s.published(repository) {
+		if !release.Prerelease {
+			return s.githubJSON(repository, release)
+		}
+	}
+	return nil
+}
+
+func (s *Server) githubRelease(r *http.Request) any {
+	repository := githubRepository(r)
+	if release := s.find(repository, r.PathValue("tag")); release != nil && !release.Draft {
+		return s.githubJSON(repository, release)
+	}
+	return nil
+}
+
+func (s *Server) githubReleases(r *http.Request) any {
+	repository := githubRepository(r)
+	list := []any{}
+	for _, release := range paginate(s.published(repository), r, 30) {
+		list = append(list, s.githubJSON(repository, release))
+	}
+	return list
+}
+
+func (s *Server) githubTags(r *http.Request) any {
+	list := []any{}
+	for _, release := range paginate(s.published(githubRepository(r)), r, 30) {
+		list = append(list, map[string]any{"name": release.Tag})
+	}
+	return list
+}
+
+// githubJSON encodes a release like the GitHub releases API
+func (s *Server) githubJSON(repository string, release *Release) map[string]any {
+	assets := []any{}
+	for i, asset := range release.Assets {
+		id := s.assetIDs[&release.Assets[i]]
+		digest := sha256.Sum256(asset.Content)
+		assets = append(assets, map[string]any{
+			"id":                   id,
+			"name":                 asset.Name,
+			"label":                asset.Label,
+			"content_type":         "application/octet-stream",
+			"size":                 len(asset.Content),
+			"digest":               "sha256:" + hex.EncodeToString(digest[:]),
+			"url":                  fmt.Sprintf("%s/repos/%s/releases/assets/%d", s.GitHubAPIURL(), repository, id),
+			"browser_download_url": fmt.Sprintf("%s/%s/releases/download/%s/%s", s.URL, repository, url.PathEscape(release.Tag), url.PathEscape(asset.Name)),
+		})
+	}
+	return map[string]any{
+		"tag_name":     release.Tag,
+		"name":         release.Name,
+		"body":         release.Notes,
+		"draft":        release.Draft,
+		"prerelease":   release.Prerelease,
+		"created_at":   release.PublishedAt,
+		"published_at": release.PublishedAt,
+		"assets":       assets,
+	}
+}
+
+func (s *Server) gitlabReleases(r *http.Request) any {
+	project := r.PathValue("project")
+	list := []any{}
+	for _, release := range paginate(s.published(project), r, 20) {
+		list = append(list, s.gitlabJSON(project, release))
+	}
+	return list
+}
+
+func (s *Server) gitlabRelease(r *http.Request) any {
+	project := r.PathValue("project")
+	if release := s.find(project, r.PathValue("tag")); release != nil {
+		return s.gitlabJSON(project, release)
+	}
+	return nil
+}
+
+func (s *Server) gitlabLinks(r *http.Request) any {
+	project := r.PathValue("project")
+	if release := s.find(project, r.PathValue("tag")); release != nil {
+		return s.gitlabJSON(project, release)["assets"].(map[string]any)["links"]
+	}
+	return nil
+}
+
+// gitlabJSON encodes a release like the GitLab releases API
+func (s *Server) gitlabJSON(project string, release *Release) map[string]any {
+	links := []any{}
+	for i, asset := range release.Assets {
+		link := fmt.Sprintf("%s/gitlab/%s/-/releases/%s/downloads/%s", s.URL, project, url.PathEscape(release.Tag), url.PathEscape(asset.Name))
+		links = append(links, map[string]any{
+			"id":               i + 1,
+			"name":             asset.Name,
+			"url":              link,
+			"direct_asset_url": link,
+			"link_type":        asset.LinkType,
+		})
+	}
+	return map[string]any{
+		"tag_name":    release.Tag,
+		"name":        release.Name,
+		"description": release.Notes,
+		"created_at":  release.PublishedAt,
+		"released_at": release.PublishedAt,
+		"assets":      map[string]any{"links": links},
+	}
+}
+
+// download serves an asset by its browser URL, its GitHub API URL or its GitLab link
+func (s *Server) download(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for repository, releases := range s.releases {
+		for _, release := range releases {
+			for i, asset := range release.Assets {
+				byID := r.PathValue("id") == strconv.Itoa(s.assetIDs[&release.Assets[i]]) &&
+					githubRepository(r) == repository
+				byName := r.PathValue("name") == asset.Name && r.PathValue("tag") == release.Tag &&
+					(githubRepository(r) == repository || r.PathValue("project") == repository)
+				if byID || byName {
+					w.Header().Set("Content-Type", "application/octet-stream")
+					w.Header().Set("Content-Length", strconv.Itoa(len(asset.Content)))
+					w.Write(asset.Content)
+					return
+				}
+			}
+		}
+	}
+	http.NotFound(w, r)
+}
+
+// paginate returns the page of releases requested by the page and per_page parameters
+func paginate(releases []*Release, r *http.Request, defaultPerPage int) []*Release {
+	perPage, err := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if err != nil || perPage <= 0 {
+		perPage = defaultPerPage
+	}
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+	start := min((page-1)*perPage, len(releases))
+	return releases[start:min(start+perPage, len(releases))]
+}
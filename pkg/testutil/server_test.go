@@ -0,0 +1,140 @@
+package testutil
+
+import (
+	"errors"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/release"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestServer serves two releases of a tool for this platform, with an older stable release
+// and a newer prerelease, under both a GitHub repository and a GitLab project
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+	server := NewServer()
+	t.Cleanup(server.Close)
+	asset := fmt.Sprintf("myapp_%s_%s.tar.gz", runtime.GOOS, runtime.GOARCH)
+	for _, version := range []string{"v1.0.0", "v1.1.0", "v2.0.0-rc.1"} {
+		archive, err := TarGz(map[string]string{"myapp": "#!/bin/sh\necho " + version + "\n"})
+		if err != nil {
+			t.Fatalf("TarGz() error = %v", err)
+		}
+		r := Release{Tag: version, Prerelease: strings.Contains(version, "-"), Assets: []Asset{{Name: asset, Content: archive}}}
+		server.AddRelease("owner/myapp", r)
+		server.AddRelease("42", r)
+	}
+	return server
+}
+
+func testFileConfig(t *testing.T) fileUtils.FileConfig {
+	tempDir := t.TempDir()
+	return fileUtils.FileConfig{
+		BaseBinaryDirectory:    filepath.Join(tempDir, "bin"),
+		VersionedDirectoryName: "versions",
+		SourceBinaryName:       "myapp",
+		BinaryName:             "myapp",
+		ProjectName:            "myapp",
+		CreateLocalSymlink:     true,
+		StagingDirectory:       filepath.Join(tempDir, "staging"),
+	}
+}
+
+func TestServer_GitHub(t *testing.T) {
+	server := newTestServer(t)
+	fileConfig := testFileConfig(t)
+	provider := release.NewGithubRelease("owner/myapp", fileConfig)
+	provider.APIURL = server.GitHubAPIURL()
+
+	if err := provider.GetLatestRelease(); err != nil {
+		t.Fatalf("GetLatestRelease() error = %v", err)
+	}
+	if provider.LatestVersion() != "v1.1.0" {
+		t.Errorf("Expected the latest stable release v1.1.0, got %s", provider.LatestVersion())
+	}
+
+	if err := provider.DownloadLatestRelease(); err != nil {
+		t.Fatalf("DownloadLatestRelease() error = %v", err)
+	}
+	if err := provider.InstallLatestRelease(); err != nil {
+		t.Fatalf("InstallLatestRelease() error = %v", err)
+	}
+	content, err := os.ReadFile(fileUtils.GetVersionedBinaryPath(fileConfig, "v1.1.0"))
+	if err != nil || !strings.Contains(string(content), "echo v1.1.0") {
+		t.Errorf("Expected v1.1.0 to be installed, got %q (%v)", content, err)
+	}
+
+	if err := provider.GetRelease("v2.0.0-rc.1"); err != nil {
+		t.Errorf("GetRelease() of the prerelease error = %v", err)
+	}
+	if err := provider.GetRelease("v9.9.9"); err == nil {
+		t.Error("Expected an error for an unknown release")
+	}
+}
+
+func TestServer_GitLab(t *testing.T) {
+	server := newTestServer(t)
+	fileConfig := testFileConfig(t)
+	gitlabConfig := release.DefaultGitLabConfig()
+	gitlabConfig.BaseURL = server.GitLabAPIURL()
+	gitlabConfig.PerPage = 2
+	provider := release.NewGitlabReleaseWithConfig("42", fileConfig, gitlabConfig)
+
+	provider.Selection.IgnoreTags = []string{"v2.0.0-rc.1"}
+
+	if err := provider.GetLatestRelease(); err != nil {
+		t.Fatalf("GetLatestRelease() error = %v", err)
+	}
+	if provider.LatestVersion() != "v1.1.0" {
+		t.Errorf("Expected v1.1.0 once the release candidate is ignored, got %s", provider.LatestVersion())
+	}
+	if requests := strings.Join(server.Requests(), "\n"); !strings.Contains(requests, "page=2") {
+		t.Errorf("Expected the releases to be listed over two pages, got requests:\n%s", requests)
+	}
+
+	if err := provider.InstallRelease("v1.0.0"); err != nil {
+		t.Fatalf("InstallRelease() error = %v", err)
+	}
+	if version, err := provider.InstalledVersion(); err != nil || version != "v1.0.0" {
+		t.Errorf("Expected v1.0.0 to be installed, got %q (%v)", version, err)
+	}
+}
+
+func TestServer_Failures(t *testing.T) {
+	server := newTestServer(t)
+	provider := release.NewGithubRelease("owner/myapp", testFileConfig(t))
+	provider.APIURL = server.GitHubAPIURL()
+
+	server.FailNext(404, 1)
+	var statusErr *release.HTTPStatusError
+	if err := provider.GetLatestRelease(); !errors.As(err, &statusErr) || statusErr.StatusCode != 404 {
+		t.Errorf("Expected the injected 404, got %v", err)
+	}
+	if err := provider.GetLatestRelease(); err != nil {
+		t.Errorf("Expected the failure to be used up, got %v", err)
+	}
+
+	server.SetRateLimited(true)
+	if err := provider.GetLatestRelease(); !errors.Is(err, release.ErrRateLimited) {
+		t.Errorf("Expected a GitHub rate limit error, got %v", err)
+	}
+
+	gitlabConfig := release.DefaultGitLabConfig()
+	gitlabConfig.BaseURL = server.GitLabAPIURL()
+	gitlabConfig.HTTPConfig.MaxRetries = 0
+	gitlabConfig.HTTPConfig.RateLimitDelay = time.Millisecond
+	gitlab := release.NewGitlabReleaseWithConfig("42", testFileConfig(t), gitlabConfig)
+	if err := gitlab.GetLatestRelease(); !errors.Is(err, release.ErrRateLimited) {
+		t.Errorf("Expected a GitLab rate limit error, got %v", err)
+	}
+
+	requests := server.Requests()
+	if len(requests) == 0 || !strings.HasPrefix(requests[0], "GET /api/v3/repos/owner/myapp/releases/latest") {
+		t.Errorf("Expected the requests to be recorded, got %v", requests)
+	}
+}
@@ -0,0 +1,93 @@
+package updater
+
+import (
+	"context"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/release"
+	"time"
+)
+
+// Lock resolves and downloads the latest release of every target and returns a lockfile pinning
+// the downloaded assets by URL and SHA-256. Every target must implement release.Lockable.
+func (s *Scheduler) Lock(ctx context.Context) (*release.Lockfile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lockfile := release.NewLockfile()
+	for _, target := range s.Targets {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		lockable, ok := target.Release.(release.Lockable)
+		if !ok {
+			return nil, fmt.Errorf("release provider %T of %s cannot be locked", target.Release, target.Name)
+		}
+		if _, exists := lockfile.Tools[target.Name]; exists {
+			return nil, fmt.Errorf("duplicate target name %s", target.Name)
+		}
+		locked, err := lockable.LockLatestRelease()
+		if err != nil {
+			return nil, fmt.Errorf("failed to lock %s: %w", target.Name, err)
+		}
+		lockfile.Tools[target.Name] = *locked
+	}
+	return lockfile, nil
+}
+
+// InstallLocked installs every target exactly as pinned in the lockfile and returns the events
+// that were emitted. Targets that already have exactly the locked asset installed are left alone,
+// and targets missing from the lockfile fail.
+func (s *Scheduler) InstallLocked(ctx context.Context, lockfile *release.Lockfile) []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var events []Event
+	for _, target := range s.Targets {
+		if ctx.Err() != nil {
+			break
+		}
+		event := installLockedTarget(target, lockfile)
+		events = append(events, event)
+		if s.OnEvent != nil {
+			s.OnEvent(event)
+		}
+	}
+	return events
+}
+
+// installLockedTarget installs the locked release of a single target
+func installLockedTarget(target Target, lockfile *release.Lockfile) Event {
+	event := Event{Target: target.Name, Time: time.Now()}
+	fail := func(err error) Event {
+		event.Type = EventError
+		event.Err = err
+		return event
+	}
+
+	locked, ok := lockfile.Tools[target.Name]
+	if !ok {
+		return fail(fmt.Errorf("%s is not in the lockfile", target.Name))
+	}
+	event.LatestVersion = locked.Version
+	lockable, ok := target.Release.(release.Lockable)
+	if !ok {
+		return fail(fmt.Errorf("release provider %T cannot install locked releases", target.Release))
+	}
+
+	if reporter, ok := target.Release.(release.VersionReporter); ok {
+		if current, err := reporter.InstalledVersion(); err == nil {
+			event.CurrentVersion = current
+		}
+	}
+	// A matching version alone is not enough: it may have been built from a different asset
+	if lockable.LockedReleaseInstalled(locked) {
+		event.Type = EventUpToDate
+		return event
+	}
+
+	if err := lockable.InstallLockedRelease(locked); err != nil {
+		return fail(fmt.Errorf("failed to install locked release %s: %w", locked.Version, err))
+	}
+	event.Type = EventInstalled
+	return event
+}
@@ -0,0 +1,81 @@
+package updater
+
+import (
+	"context"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/release"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeLockableRelease locks its latest version and records locked installs
+type fakeLockableRelease struct {
+	fakeRelease
+	installedLocks    []release.LockedRelease
+	installedChecksum string
+}
+
+func (f *fakeLockableRelease) LockLatestRelease() (*release.LockedRelease, error) {
+	return &release.LockedRelease{
+		Version: f.latest,
+		URL:     "https://example.com/" + f.latest,
+		SHA256:  strings.Repeat("a", 64),
+	}, nil
+}
+
+func (f *fakeLockableRelease) InstallLockedRelease(locked release.LockedRelease) error {
+	f.installedLocks = append(f.installedLocks, locked)
+	f.installed = locked.Version
+	f.installedChecksum = locked.SHA256
+	return nil
+}
+
+func (f *fakeLockableRelease) LockedReleaseInstalled(locked release.LockedRelease) bool {
+	return f.installed == locked.Version && f.installedChecksum == locked.SHA256
+}
+
+func TestScheduler_LockAndInstallLocked(t *testing.T) {
+	tool := &fakeLockableRelease{fakeRelease: fakeRelease{latest: "1.2.0", installed: "1.1.0"}}
+	current := &fakeLockableRelease{fakeRelease: fakeRelease{latest: "2.0.0", installed: "2.0.0"}, installedChecksum: strings.Repeat("a", 64)}
+	scheduler := NewScheduler(time.Hour, Target{Name: "tool", Release: tool}, Target{Name: "current", Release: current})
+
+	lockfile, err := scheduler.Lock(context.Background())
+	if err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+	if len(lockfile.Tools) != 2 || lockfile.Tools["tool"].Version != "1.2.0" {
+		t.Fatalf("Unexpected lockfile: %+v", lockfile)
+	}
+
+	// A newer release published after locking is not installed
+	tool.latest = "1.3.0"
+	events := scheduler.InstallLocked(context.Background(), lockfile)
+	if len(events) != 2 || events[0].Type != EventInstalled || events[1].Type != EventUpToDate {
+		t.Fatalf("Unexpected events: %+v", events)
+	}
+	if tool.installed != "1.2.0" || len(tool.installedLocks) != 1 || len(current.installedLocks) != 0 {
+		t.Errorf("Expected only the locked version of tool to be installed, got %s and %v", tool.installed, tool.installedLocks)
+	}
+
+	// The same version built from a different asset is installed again
+	rebuilt := lockfile.Tools["current"]
+	rebuilt.SHA256 = strings.Repeat("b", 64)
+	lockfile.Tools["current"] = rebuilt
+	if events := scheduler.InstallLocked(context.Background(), lockfile); events[0].Type != EventUpToDate || events[1].Type != EventInstalled {
+		t.Errorf("Expected only the rebuilt version to be installed again, got %+v", events)
+	}
+	if len(current.installedLocks) != 1 || current.installedChecksum != rebuilt.SHA256 {
+		t.Errorf("Expected the rebuilt asset to be installed, got %v", current.installedLocks)
+	}
+
+	delete(lockfile.Tools, "tool")
+	if events := scheduler.InstallLocked(context.Background(), lockfile); events[0].Type != EventError {
+		t.Errorf("Expected an error for a target missing from the lockfile, got %+v", events[0])
+	}
+
+	// Providers that cannot be locked are rejected
+	scheduler.Targets = append(scheduler.Targets, Target{Name: "plain", Release: &fakeRelease{latest: "1.0.0"}})
+	if _, err := scheduler.Lock(context.Background()); err == nil {
+		t.Error("Expected an error for a provider that cannot be locked")
+	}
+}
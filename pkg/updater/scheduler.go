@@ -0,0 +1,205 @@
+package updater
+
+import (
+	"context"
+	"fmt"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/release"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EventType identifies what a scheduled update check found or did
+type EventType string
+
+const (
+	EventUpdateAvailable EventType = "update_available" // A newer version was found and not installed
+	EventUpToDate        EventType = "up_to_date"       // The installed version is the latest
+	EventInstalled       EventType = "installed"        // A newer version was downloaded and installed
+	EventError           EventType = "error"            // The check, download or install failed
+)
+
+// Target is one release the scheduler keeps up to date
+type Target struct {
+	Name        string          // Identifies the target in events
+	Release     release.Release // Provider to check; must also implement release.VersionReporter
+	AutoInstall bool            // Install new versions of this target even if the scheduler does not
+}
+
+// Event reports the outcome of checking one target
+type Event struct {
+	Type           EventType
+	Target         string
+	CurrentVersion string // Installed version before the check
+	LatestVersion  string // Latest version reported by the provider
+	Err            error  // Set for EventError
+	Time           time.Time
+}
+
+// Scheduler periodically checks a set of releases for updates, optionally installs them and
+// reports every outcome through OnEvent
+type Scheduler struct {
+	Targets     []Target
+	Interval    time.Duration // Time between checks when no cron schedule is set
+	AutoInstall bool          // Install new versions of every target
+	OnEvent     func(Event)   // Called for each event; may be nil
+	Timeout     time.Duration // Deadline of checking and installing each target (default: none)
+
+	cron *CronSchedule
+	mu   sync.Mutex // Serializes checks so a slow pass never overlaps the next one
+}
+
+// NewScheduler creates a scheduler that checks the targets every interval
+func NewScheduler(interval time.Duration, targets ...Target) *Scheduler {
+	return &Scheduler{Targets: targets, Interval: interval}
+}
+
+// NewCronScheduler creates a scheduler that checks the targets according to a cron expression.
+// "@every <duration>" (e.g. "@every 6h") is accepted as an interval.
+func NewCronScheduler(expression string, targets ...Target) (*Scheduler, error) {
+	if rest, ok := strings.CutPrefix(strings.TrimSpace(expression), "@every "); ok {
+		interval, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("invalid interval in %q: %v", expression, err)
+		}
+		return NewScheduler(interval, targets...), nil
+	}
+
+	schedule, err := ParseCron(expression)
+	if err != nil {
+		return nil, err
+	}
+	return &Scheduler{Targets: targets, cron: schedule}, nil
+}
+
+// Next returns the time of the first check after t
+func (s *Scheduler) Next(t time.Time) time.Time {
+	if s.cron != nil {
+		return s.cron.Next(t)
+	}
+	return t.Add(s.Interval)
+}
+
+// Run checks all targets immediately and then on every scheduled tick until ctx is cancelled
+func (s *Scheduler) Run(ctx context.Context) error {
+	if s.cron == nil && s.Interval <= 0 {
+		return fmt.Errorf("scheduler has neither an interval nor a cron schedule")
+	}
+
+	for {
+		s.CheckNow(ctx)
+
+		next := s.Next(time.Now())
+		if next.IsZero() {
+			return fmt.Errorf("cron schedule %q never fires again", s.cron)
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// CheckNow checks every target once, installing updates where enabled, and returns the events
+// that were emitted
+func (s *Scheduler) CheckNow(ctx context.Context) []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var events []Event
+	for _, target := range s.Targets {
+		if ctx.Err() != nil {
+			break
+		}
+		event := s.checkTarget(ctx, target)
+		events = append(events, event)
+		if s.OnEvent != nil {
+			s.OnEvent(event)
+		}
+	}
+	return events
+}
+
+// checkTarget checks a single target and installs the latest version if enabled. Providers
+// implementing release.ContextRelease are stopped when ctx is done or Timeout has passed.
+func (s *Scheduler) checkTarget(ctx context.Context, target Target) Event {
+	if s.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.Timeout)
+		defer cancel()
+	}
+
+	event := Event{Target: target.Name, Time: time.Now()}
+	fail := func(err error) Event {
+		event.Type = EventError
+		event.Err = err
+		return event
+	}
+
+	reporter, ok := target.Release.(release.VersionReporter)
+	if !ok {
+		return fail(fmt.Errorf("release provider %T does not report versions", target.Release))
+	}
+
+	current, err := reporter.InstalledVersion()
+	if err != nil {
+		// Nothing installed yet; any release is an update
+		current = ""
+	}
+	event.CurrentVersion = current
+
+	provider := boundedRelease{Release: target.Release, ctx: ctx}
+	if err := provider.GetLatestRelease(); err != nil {
+		return fail(fmt.Errorf("failed to get latest release: %w", err))
+	}
+	event.LatestVersion = reporter.LatestVersion()
+
+	if !release.IsNewerVersion(event.LatestVersion, current) {
+		event.Type = EventUpToDate
+		return event
+	}
+	if !s.AutoInstall && !target.AutoInstall {
+		event.Type = EventUpdateAvailable
+		return event
+	}
+
+	if err := provider.DownloadLatestRelease(); err != nil {
+		return fail(fmt.Errorf("failed to download release %s: %w", event.LatestVersion, err))
+	}
+	if err := provider.InstallLatestRelease(); err != nil {
+		return fail(fmt.Errorf("failed to install release %s: %w", event.LatestVersion, err))
+	}
+	event.Type = EventInstalled
+	return event
+}
+
+// boundedRelease calls the context variants of a release.ContextRelease, and the plain methods of
+// other providers
+type boundedRelease struct {
+	release.Release
+	ctx context.Context
+}
+
+func (b boundedRelease) GetLatestRelease() error {
+	if c, ok := b.Release.(release.ContextRelease); ok {
+		return c.GetLatestReleaseContext(b.ctx)
+	}
+	return b.Release.GetLatestRelease()
+}
+
+func (b boundedRelease) DownloadLatestRelease() error {
+	if c, ok := b.Release.(release.ContextRelease); ok {
+		return c.DownloadLatestReleaseContext(b.ctx)
+	}
+	return b.Release.DownloadLatestRelease()
+}
+
+func (b boundedRelease) InstallLatestRelease() error {
+	if c, ok := b.Release.(release.ContextRelease); ok {
+		return c.InstallLatestReleaseContext(b.ctx)
+	}
+	return b.Release.InstallLatestRelease()
+}
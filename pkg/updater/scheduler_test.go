@@ -0,0 +1,172 @@
+package updater
+
+import (
+	"context"
+	"errors"
+	"gitlab.com/locke-codes/go-binary-updater/pkg/fileUtils"
+	"testing"
+	"time"
+)
+
+// fakeRelease is a release provider with a fixed latest and installed version
+type fakeRelease struct {
+	latest    string
+	installed string
+	latestErr error
+	installs  int
+}
+
+func (f *fakeRelease) GetLatestRelease() error      { return f.latestErr }
+func (f *fakeRelease) DownloadLatestRelease() error { return nil }
+func (f *fakeRelease) InstallLatestRelease() error {
+	f.installs++
+	f.installed = f.latest
+	return nil
+}
+func (f *fakeRelease) GetInstalledBinaryPath() (string, error) { return "", nil }
+func (f *fakeRelease) GetInstallationInfo() (*fileUtils.InstallationInfo, error) {
+	return nil, nil
+}
+func (f *fakeRelease) LatestVersion() string { return f.latest }
+func (f *fakeRelease) InstalledVersion() (string, error) {
+	if f.installed == "" {
+		return "", errors.New("not installed")
+	}
+	return f.installed, nil
+}
+
+func TestScheduler_CheckNow(t *testing.T) {
+	upToDate := &fakeRelease{latest: "1.0.0", installed: "1.0.0"}
+	outdated := &fakeRelease{latest: "1.2.0", installed: "1.1.0"}
+	autoInstalled := &fakeRelease{latest: "2.0.0", installed: "1.9.9"}
+	notInstalled := &fakeRelease{latest: "0.1.0"}
+	failing := &fakeRelease{latestErr: errors.New("rate limited")}
+
+	scheduler := NewScheduler(time.Hour,
+		Target{Name: "current", Release: upToDate},
+		Target{Name: "outdated", Release: outdated},
+		Target{Name: "auto", Release: autoInstalled, AutoInstall: true},
+		Target{Name: "new", Release: notInstalled},
+		Target{Name: "failing", Release: failing},
+	)
+	var received []Event
+	scheduler.OnEvent = func(event Event) { received = append(received, event) }
+
+	events := scheduler.CheckNow(context.Background())
+	if len(events) != 5 || len(received) != 5 {
+		t.Fatalf("Expected 5 events returned and emitted, got %d and %d", len(events), len(received))
+	}
+
+	expected := []EventType{EventUpToDate, EventUpdateAvailable, EventInstalled, EventUpdateAvailable, EventError}
+	for i, eventType := range expected {
+		if events[i].Type != eventType {
+			t.Errorf("Event %d (%s) type = %s, want %s", i, events[i].Target, events[i].Type, eventType)
+		}
+	}
+	if events[1].CurrentVersion != "1.1.0" || events[1].LatestVersion != "1.2.0" {
+		t.Errorf("Unexpected versions in event: %+v", events[1])
+	}
+	if outdated.installs != 0 || autoInstalled.installs != 1 {
+		t.Errorf("Expected only the auto-install target to be installed, got %d and %d", outdated.installs, autoInstalled.installs)
+	}
+	if events[4].Err == nil {
+		t.Error("Expected error event to carry the error")
+	}
+
+	// Scheduler-wide auto-install
+	scheduler.AutoInstall = true
+	scheduler.CheckNow(context.Background())
+	if outdated.installs != 1 || outdated.installed != "1.2.0" {
+		t.Errorf("Expected outdated target to be installed, installs=%d installed=%s", outdated.installs, outdated.installed)
+	}
+}
+
+func TestScheduler_Run(t *testing.T) {
+	target := &fakeRelease{latest: "1.0.0", installed: "1.0.0"}
+	scheduler := NewScheduler(10*time.Millisecond, Target{Name: "tool", Release: target})
+
+	checks := make(chan Event, 10)
+	scheduler.OnEvent = func(event Event) {
+		select {
+		case checks <- event:
+		default:
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- scheduler.Run(ctx) }()
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-checks:
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for check %d", i+1)
+		}
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() error = %v, want context.Canceled", err)
+	}
+
+	if err := NewScheduler(0).Run(context.Background()); err == nil {
+		t.Error("Expected error for scheduler without interval")
+	}
+}
+
+func TestNewCronScheduler(t *testing.T) {
+	scheduler, err := NewCronScheduler("@every 6h")
+	if err != nil {
+		t.Fatalf("NewCronScheduler() error = %v", err)
+	}
+	start := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	if got := scheduler.Next(start); !got.Equal(start.Add(6 * time.Hour)) {
+		t.Errorf("Next() = %v, want %v", got, start.Add(6*time.Hour))
+	}
+
+	scheduler, err = NewCronScheduler("0 3 * * *")
+	if err != nil {
+		t.Fatalf("NewCronScheduler() error = %v", err)
+	}
+	if got := scheduler.Next(start); !got.Equal(time.Date(2025, 1, 16, 3, 0, 0, 0, time.UTC)) {
+		t.Errorf("Next() = %v", got)
+	}
+
+	if _, err := NewCronScheduler("@every soon"); err == nil {
+		t.Error("Expected error for invalid interval")
+	}
+	if _, err := NewCronScheduler("not a cron"); err == nil {
+		t.Error("Expected error for invalid expression")
+	}
+}
+
+// slowRelease is a fakeRelease whose installation blocks until its context is done
+type slowRelease struct {
+	fakeRelease
+}
+
+func (s *slowRelease) GetLatestReleaseContext(ctx context.Context) error { return s.GetLatestRelease() }
+func (s *slowRelease) DownloadLatestReleaseContext(ctx context.Context) error {
+	return s.DownloadLatestRelease()
+}
+func (s *slowRelease) InstallLatestReleaseContext(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+func (s *slowRelease) InstallReleaseContext(ctx context.Context, version string) error {
+	return s.InstallLatestReleaseContext(ctx)
+}
+
+func TestScheduler_Timeout(t *testing.T) {
+	slow := &slowRelease{fakeRelease{latest: "1.1.0", installed: "1.0.0"}}
+	scheduler := NewScheduler(time.Hour, Target{Name: "slow", Release: slow, AutoInstall: true})
+	scheduler.Timeout = 50 * time.Millisecond
+
+	events := scheduler.CheckNow(context.Background())
+	if len(events) != 1 || events[0].Type != EventError {
+		t.Fatalf("Expected an error event, got %+v", events)
+	}
+	if !errors.Is(events[0].Err, context.DeadlineExceeded) {
+		t.Errorf("Expected the installation to stop at the timeout, got %v", events[0].Err)
+	}
+}